@@ -0,0 +1,943 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: neurouter/v1/admin.proto
+
+package v1
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LimiterType int32
+
+const (
+	LimiterType_LIMITER_TYPE_UNSPECIFIED LimiterType = 0
+	LimiterType_LIMITER_TYPE_CONCURRENCY LimiterType = 1
+	// Refills continuously, as used by RPM and TPM.
+	LimiterType_LIMITER_TYPE_TOKEN_BUCKET LimiterType = 2
+	// Resets at fixed boundaries, as used by RPD and TPD.
+	LimiterType_LIMITER_TYPE_CALENDAR_WINDOW LimiterType = 3
+	LimiterType_LIMITER_TYPE_SLIDING_WINDOW  LimiterType = 4
+)
+
+// Enum value maps for LimiterType.
+var (
+	LimiterType_name = map[int32]string{
+		0: "LIMITER_TYPE_UNSPECIFIED",
+		1: "LIMITER_TYPE_CONCURRENCY",
+		2: "LIMITER_TYPE_TOKEN_BUCKET",
+		3: "LIMITER_TYPE_CALENDAR_WINDOW",
+		4: "LIMITER_TYPE_SLIDING_WINDOW",
+	}
+	LimiterType_value = map[string]int32{
+		"LIMITER_TYPE_UNSPECIFIED":     0,
+		"LIMITER_TYPE_CONCURRENCY":     1,
+		"LIMITER_TYPE_TOKEN_BUCKET":    2,
+		"LIMITER_TYPE_CALENDAR_WINDOW": 3,
+		"LIMITER_TYPE_SLIDING_WINDOW":  4,
+	}
+)
+
+func (x LimiterType) Enum() *LimiterType {
+	p := new(LimiterType)
+	*p = x
+	return p
+}
+
+func (x LimiterType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LimiterType) Descriptor() protoreflect.EnumDescriptor {
+	return file_neurouter_v1_admin_proto_enumTypes[0].Descriptor()
+}
+
+func (LimiterType) Type() protoreflect.EnumType {
+	return &file_neurouter_v1_admin_proto_enumTypes[0]
+}
+
+func (x LimiterType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LimiterType.Descriptor instead.
+func (LimiterType) EnumDescriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+type LimiterUnit int32
+
+const (
+	LimiterUnit_LIMITER_UNIT_UNSPECIFIED LimiterUnit = 0
+	LimiterUnit_LIMITER_UNIT_REQUEST     LimiterUnit = 1
+	LimiterUnit_LIMITER_UNIT_TOKEN       LimiterUnit = 2
+)
+
+// Enum value maps for LimiterUnit.
+var (
+	LimiterUnit_name = map[int32]string{
+		0: "LIMITER_UNIT_UNSPECIFIED",
+		1: "LIMITER_UNIT_REQUEST",
+		2: "LIMITER_UNIT_TOKEN",
+	}
+	LimiterUnit_value = map[string]int32{
+		"LIMITER_UNIT_UNSPECIFIED": 0,
+		"LIMITER_UNIT_REQUEST":     1,
+		"LIMITER_UNIT_TOKEN":       2,
+	}
+)
+
+func (x LimiterUnit) Enum() *LimiterUnit {
+	p := new(LimiterUnit)
+	*p = x
+	return p
+}
+
+func (x LimiterUnit) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LimiterUnit) Descriptor() protoreflect.EnumDescriptor {
+	return file_neurouter_v1_admin_proto_enumTypes[1].Descriptor()
+}
+
+func (LimiterUnit) Type() protoreflect.EnumType {
+	return &file_neurouter_v1_admin_proto_enumTypes[1]
+}
+
+func (x LimiterUnit) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LimiterUnit.Descriptor instead.
+func (LimiterUnit) EnumDescriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+type LimiterState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The position of the limiter in its group, used to reset or adjust it.
+	Index uint32      `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Type  LimiterType `protobuf:"varint,2,opt,name=type,proto3,enum=neurouter.v1.LimiterType" json:"type,omitempty"`
+	Unit  LimiterUnit `protobuf:"varint,3,opt,name=unit,proto3,enum=neurouter.v1.LimiterUnit" json:"unit,omitempty"`
+	// The maximum units admitted by the limiter.
+	Limit int64 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// The units that can be admitted right now.
+	Remaining int64 `protobuf:"varint,5,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// The requests currently holding a concurrency slot.
+	InFlight int64 `protobuf:"varint,6,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	// The requests blocked waiting for the limiter.
+	Waiters int64 `protobuf:"varint,7,opt,name=waiters,proto3" json:"waiters,omitempty"`
+	// The refill period or window length, if any.
+	Window *durationpb.Duration `protobuf:"bytes,8,opt,name=window,proto3" json:"window,omitempty"`
+	// When the limiter next regains quota, if it is not full.
+	NextReset *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=next_reset,json=nextReset,proto3" json:"next_reset,omitempty"`
+	// The delay a request of a single unit would currently wait.
+	ProbeDelay *durationpb.Duration `protobuf:"bytes,10,opt,name=probe_delay,json=probeDelay,proto3" json:"probe_delay,omitempty"`
+	// Whether the remaining quota can be reset or adjusted.
+	Adjustable    bool `protobuf:"varint,11,opt,name=adjustable,proto3" json:"adjustable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimiterState) Reset() {
+	*x = LimiterState{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimiterState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimiterState) ProtoMessage() {}
+
+func (x *LimiterState) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimiterState.ProtoReflect.Descriptor instead.
+func (*LimiterState) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LimiterState) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *LimiterState) GetType() LimiterType {
+	if x != nil {
+		return x.Type
+	}
+	return LimiterType_LIMITER_TYPE_UNSPECIFIED
+}
+
+func (x *LimiterState) GetUnit() LimiterUnit {
+	if x != nil {
+		return x.Unit
+	}
+	return LimiterUnit_LIMITER_UNIT_UNSPECIFIED
+}
+
+func (x *LimiterState) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *LimiterState) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *LimiterState) GetInFlight() int64 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *LimiterState) GetWaiters() int64 {
+	if x != nil {
+		return x.Waiters
+	}
+	return 0
+}
+
+func (x *LimiterState) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *LimiterState) GetNextReset() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextReset
+	}
+	return nil
+}
+
+func (x *LimiterState) GetProbeDelay() *durationpb.Duration {
+	if x != nil {
+		return x.ProbeDelay
+	}
+	return nil
+}
+
+func (x *LimiterState) GetAdjustable() bool {
+	if x != nil {
+		return x.Adjustable
+	}
+	return false
+}
+
+type LimiterGroup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The upstream the limiters belong to.
+	Upstream string `protobuf:"bytes,1,opt,name=upstream,proto3" json:"upstream,omitempty"`
+	// The model the limiters belong to, empty for upstream-level limiters.
+	Model    string          `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Limiters []*LimiterState `protobuf:"bytes,3,rep,name=limiters,proto3" json:"limiters,omitempty"`
+	// The delay a single-token request would currently wait across the group.
+	ProbeDelay *durationpb.Duration `protobuf:"bytes,4,opt,name=probe_delay,json=probeDelay,proto3" json:"probe_delay,omitempty"`
+	// The credential the limiters belong to, empty unless the group holds the
+	// limiters of a credential pooled by the upstream.
+	Credential string `protobuf:"bytes,5,opt,name=credential,proto3" json:"credential,omitempty"`
+	// Whether the credential was disabled after the upstream rejected it.
+	Disabled      bool `protobuf:"varint,6,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimiterGroup) Reset() {
+	*x = LimiterGroup{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimiterGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimiterGroup) ProtoMessage() {}
+
+func (x *LimiterGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimiterGroup.ProtoReflect.Descriptor instead.
+func (*LimiterGroup) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LimiterGroup) GetUpstream() string {
+	if x != nil {
+		return x.Upstream
+	}
+	return ""
+}
+
+func (x *LimiterGroup) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *LimiterGroup) GetLimiters() []*LimiterState {
+	if x != nil {
+		return x.Limiters
+	}
+	return nil
+}
+
+func (x *LimiterGroup) GetProbeDelay() *durationpb.Duration {
+	if x != nil {
+		return x.ProbeDelay
+	}
+	return nil
+}
+
+func (x *LimiterGroup) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+func (x *LimiterGroup) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type ListLimitersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLimitersRequest) Reset() {
+	*x = ListLimitersRequest{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLimitersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLimitersRequest) ProtoMessage() {}
+
+func (x *ListLimitersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLimitersRequest.ProtoReflect.Descriptor instead.
+func (*ListLimitersRequest) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{2}
+}
+
+type ListLimitersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Groups        []*LimiterGroup        `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLimitersResponse) Reset() {
+	*x = ListLimitersResponse{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLimitersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLimitersResponse) ProtoMessage() {}
+
+func (x *ListLimitersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLimitersResponse.ProtoReflect.Descriptor instead.
+func (*ListLimitersResponse) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListLimitersResponse) GetGroups() []*LimiterGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+type ResetLimiterRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Upstream string                 `protobuf:"bytes,1,opt,name=upstream,proto3" json:"upstream,omitempty"`
+	// Empty to address the upstream-level limiters.
+	Model string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// The limiter to reset. Every adjustable limiter of the group is reset if unset.
+	Index *uint32 `protobuf:"varint,3,opt,name=index,proto3,oneof" json:"index,omitempty"`
+	// Addresses the limiters of a credential, with an empty model.
+	Credential    string `protobuf:"bytes,4,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetLimiterRequest) Reset() {
+	*x = ResetLimiterRequest{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetLimiterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetLimiterRequest) ProtoMessage() {}
+
+func (x *ResetLimiterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetLimiterRequest.ProtoReflect.Descriptor instead.
+func (*ResetLimiterRequest) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ResetLimiterRequest) GetUpstream() string {
+	if x != nil {
+		return x.Upstream
+	}
+	return ""
+}
+
+func (x *ResetLimiterRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ResetLimiterRequest) GetIndex() uint32 {
+	if x != nil && x.Index != nil {
+		return *x.Index
+	}
+	return 0
+}
+
+func (x *ResetLimiterRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+type ResetLimiterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *LimiterGroup          `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResetLimiterResponse) Reset() {
+	*x = ResetLimiterResponse{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResetLimiterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResetLimiterResponse) ProtoMessage() {}
+
+func (x *ResetLimiterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResetLimiterResponse.ProtoReflect.Descriptor instead.
+func (*ResetLimiterResponse) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResetLimiterResponse) GetGroup() *LimiterGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type AdjustLimiterRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Upstream string                 `protobuf:"bytes,1,opt,name=upstream,proto3" json:"upstream,omitempty"`
+	// Empty to address the upstream-level limiters.
+	Model string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Index uint32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	// The units the limiter should admit from now on.
+	Remaining int64 `protobuf:"varint,4,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// Addresses the limiters of a credential, with an empty model.
+	Credential    string `protobuf:"bytes,5,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustLimiterRequest) Reset() {
+	*x = AdjustLimiterRequest{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustLimiterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustLimiterRequest) ProtoMessage() {}
+
+func (x *AdjustLimiterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustLimiterRequest.ProtoReflect.Descriptor instead.
+func (*AdjustLimiterRequest) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AdjustLimiterRequest) GetUpstream() string {
+	if x != nil {
+		return x.Upstream
+	}
+	return ""
+}
+
+func (x *AdjustLimiterRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AdjustLimiterRequest) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *AdjustLimiterRequest) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *AdjustLimiterRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+type AdjustLimiterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *LimiterGroup          `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustLimiterResponse) Reset() {
+	*x = AdjustLimiterResponse{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustLimiterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustLimiterResponse) ProtoMessage() {}
+
+func (x *AdjustLimiterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustLimiterResponse.ProtoReflect.Descriptor instead.
+func (*AdjustLimiterResponse) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AdjustLimiterResponse) GetGroup() *LimiterGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+type EnableCredentialRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Upstream      string                 `protobuf:"bytes,1,opt,name=upstream,proto3" json:"upstream,omitempty"`
+	Credential    string                 `protobuf:"bytes,2,opt,name=credential,proto3" json:"credential,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableCredentialRequest) Reset() {
+	*x = EnableCredentialRequest{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableCredentialRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableCredentialRequest) ProtoMessage() {}
+
+func (x *EnableCredentialRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableCredentialRequest.ProtoReflect.Descriptor instead.
+func (*EnableCredentialRequest) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *EnableCredentialRequest) GetUpstream() string {
+	if x != nil {
+		return x.Upstream
+	}
+	return ""
+}
+
+func (x *EnableCredentialRequest) GetCredential() string {
+	if x != nil {
+		return x.Credential
+	}
+	return ""
+}
+
+type EnableCredentialResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Group         *LimiterGroup          `protobuf:"bytes,1,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableCredentialResponse) Reset() {
+	*x = EnableCredentialResponse{}
+	mi := &file_neurouter_v1_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableCredentialResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableCredentialResponse) ProtoMessage() {}
+
+func (x *EnableCredentialResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableCredentialResponse.ProtoReflect.Descriptor instead.
+func (*EnableCredentialResponse) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EnableCredentialResponse) GetGroup() *LimiterGroup {
+	if x != nil {
+		return x.Group
+	}
+	return nil
+}
+
+var File_neurouter_v1_admin_proto protoreflect.FileDescriptor
+
+const file_neurouter_v1_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x18neurouter/v1/admin.proto\x12\fneurouter.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb7\x03\n" +
+	"\fLimiterState\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12-\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x19.neurouter.v1.LimiterTypeR\x04type\x12-\n" +
+	"\x04unit\x18\x03 \x01(\x0e2\x19.neurouter.v1.LimiterUnitR\x04unit\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x03R\x05limit\x12\x1c\n" +
+	"\tremaining\x18\x05 \x01(\x03R\tremaining\x12\x1b\n" +
+	"\tin_flight\x18\x06 \x01(\x03R\binFlight\x12\x18\n" +
+	"\awaiters\x18\a \x01(\x03R\awaiters\x121\n" +
+	"\x06window\x18\b \x01(\v2\x19.google.protobuf.DurationR\x06window\x129\n" +
+	"\n" +
+	"next_reset\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tnextReset\x12:\n" +
+	"\vprobe_delay\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"probeDelay\x12\x1e\n" +
+	"\n" +
+	"adjustable\x18\v \x01(\bR\n" +
+	"adjustable\"\xf0\x01\n" +
+	"\fLimiterGroup\x12\x1a\n" +
+	"\bupstream\x18\x01 \x01(\tR\bupstream\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x126\n" +
+	"\blimiters\x18\x03 \x03(\v2\x1a.neurouter.v1.LimiterStateR\blimiters\x12:\n" +
+	"\vprobe_delay\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"probeDelay\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x05 \x01(\tR\n" +
+	"credential\x12\x1a\n" +
+	"\bdisabled\x18\x06 \x01(\bR\bdisabled\"\x15\n" +
+	"\x13ListLimitersRequest\"J\n" +
+	"\x14ListLimitersResponse\x122\n" +
+	"\x06groups\x18\x01 \x03(\v2\x1a.neurouter.v1.LimiterGroupR\x06groups\"\x8c\x01\n" +
+	"\x13ResetLimiterRequest\x12\x1a\n" +
+	"\bupstream\x18\x01 \x01(\tR\bupstream\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x19\n" +
+	"\x05index\x18\x03 \x01(\rH\x00R\x05index\x88\x01\x01\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x04 \x01(\tR\n" +
+	"credentialB\b\n" +
+	"\x06_index\"H\n" +
+	"\x14ResetLimiterResponse\x120\n" +
+	"\x05group\x18\x01 \x01(\v2\x1a.neurouter.v1.LimiterGroupR\x05group\"\x9c\x01\n" +
+	"\x14AdjustLimiterRequest\x12\x1a\n" +
+	"\bupstream\x18\x01 \x01(\tR\bupstream\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x14\n" +
+	"\x05index\x18\x03 \x01(\rR\x05index\x12\x1c\n" +
+	"\tremaining\x18\x04 \x01(\x03R\tremaining\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x05 \x01(\tR\n" +
+	"credential\"I\n" +
+	"\x15AdjustLimiterResponse\x120\n" +
+	"\x05group\x18\x01 \x01(\v2\x1a.neurouter.v1.LimiterGroupR\x05group\"U\n" +
+	"\x17EnableCredentialRequest\x12\x1a\n" +
+	"\bupstream\x18\x01 \x01(\tR\bupstream\x12\x1e\n" +
+	"\n" +
+	"credential\x18\x02 \x01(\tR\n" +
+	"credential\"L\n" +
+	"\x18EnableCredentialResponse\x120\n" +
+	"\x05group\x18\x01 \x01(\v2\x1a.neurouter.v1.LimiterGroupR\x05group*\xab\x01\n" +
+	"\vLimiterType\x12\x1c\n" +
+	"\x18LIMITER_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18LIMITER_TYPE_CONCURRENCY\x10\x01\x12\x1d\n" +
+	"\x19LIMITER_TYPE_TOKEN_BUCKET\x10\x02\x12 \n" +
+	"\x1cLIMITER_TYPE_CALENDAR_WINDOW\x10\x03\x12\x1f\n" +
+	"\x1bLIMITER_TYPE_SLIDING_WINDOW\x10\x04*]\n" +
+	"\vLimiterUnit\x12\x1c\n" +
+	"\x18LIMITER_UNIT_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14LIMITER_UNIT_REQUEST\x10\x01\x12\x16\n" +
+	"\x12LIMITER_UNIT_TOKEN\x10\x022\x83\x04\n" +
+	"\x05Admin\x12q\n" +
+	"\fListLimiters\x12!.neurouter.v1.ListLimitersRequest\x1a\".neurouter.v1.ListLimitersResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/admin/limiters\x12z\n" +
+	"\fResetLimiter\x12!.neurouter.v1.ResetLimiterRequest\x1a\".neurouter.v1.ResetLimiterResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/limiters/reset\x12~\n" +
+	"\rAdjustLimiter\x12\".neurouter.v1.AdjustLimiterRequest\x1a#.neurouter.v1.AdjustLimiterResponse\"$\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/admin/limiters/adjust\x12\x8a\x01\n" +
+	"\x10EnableCredential\x12%.neurouter.v1.EnableCredentialRequest\x1a&.neurouter.v1.EnableCredentialResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/v1/admin/credentials/enableB3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
+
+var (
+	file_neurouter_v1_admin_proto_rawDescOnce sync.Once
+	file_neurouter_v1_admin_proto_rawDescData []byte
+)
+
+func file_neurouter_v1_admin_proto_rawDescGZIP() []byte {
+	file_neurouter_v1_admin_proto_rawDescOnce.Do(func() {
+		file_neurouter_v1_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_neurouter_v1_admin_proto_rawDesc), len(file_neurouter_v1_admin_proto_rawDesc)))
+	})
+	return file_neurouter_v1_admin_proto_rawDescData
+}
+
+var file_neurouter_v1_admin_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_neurouter_v1_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_neurouter_v1_admin_proto_goTypes = []any{
+	(LimiterType)(0),                 // 0: neurouter.v1.LimiterType
+	(LimiterUnit)(0),                 // 1: neurouter.v1.LimiterUnit
+	(*LimiterState)(nil),             // 2: neurouter.v1.LimiterState
+	(*LimiterGroup)(nil),             // 3: neurouter.v1.LimiterGroup
+	(*ListLimitersRequest)(nil),      // 4: neurouter.v1.ListLimitersRequest
+	(*ListLimitersResponse)(nil),     // 5: neurouter.v1.ListLimitersResponse
+	(*ResetLimiterRequest)(nil),      // 6: neurouter.v1.ResetLimiterRequest
+	(*ResetLimiterResponse)(nil),     // 7: neurouter.v1.ResetLimiterResponse
+	(*AdjustLimiterRequest)(nil),     // 8: neurouter.v1.AdjustLimiterRequest
+	(*AdjustLimiterResponse)(nil),    // 9: neurouter.v1.AdjustLimiterResponse
+	(*EnableCredentialRequest)(nil),  // 10: neurouter.v1.EnableCredentialRequest
+	(*EnableCredentialResponse)(nil), // 11: neurouter.v1.EnableCredentialResponse
+	(*durationpb.Duration)(nil),      // 12: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
+}
+var file_neurouter_v1_admin_proto_depIdxs = []int32{
+	0,  // 0: neurouter.v1.LimiterState.type:type_name -> neurouter.v1.LimiterType
+	1,  // 1: neurouter.v1.LimiterState.unit:type_name -> neurouter.v1.LimiterUnit
+	12, // 2: neurouter.v1.LimiterState.window:type_name -> google.protobuf.Duration
+	13, // 3: neurouter.v1.LimiterState.next_reset:type_name -> google.protobuf.Timestamp
+	12, // 4: neurouter.v1.LimiterState.probe_delay:type_name -> google.protobuf.Duration
+	2,  // 5: neurouter.v1.LimiterGroup.limiters:type_name -> neurouter.v1.LimiterState
+	12, // 6: neurouter.v1.LimiterGroup.probe_delay:type_name -> google.protobuf.Duration
+	3,  // 7: neurouter.v1.ListLimitersResponse.groups:type_name -> neurouter.v1.LimiterGroup
+	3,  // 8: neurouter.v1.ResetLimiterResponse.group:type_name -> neurouter.v1.LimiterGroup
+	3,  // 9: neurouter.v1.AdjustLimiterResponse.group:type_name -> neurouter.v1.LimiterGroup
+	3,  // 10: neurouter.v1.EnableCredentialResponse.group:type_name -> neurouter.v1.LimiterGroup
+	4,  // 11: neurouter.v1.Admin.ListLimiters:input_type -> neurouter.v1.ListLimitersRequest
+	6,  // 12: neurouter.v1.Admin.ResetLimiter:input_type -> neurouter.v1.ResetLimiterRequest
+	8,  // 13: neurouter.v1.Admin.AdjustLimiter:input_type -> neurouter.v1.AdjustLimiterRequest
+	10, // 14: neurouter.v1.Admin.EnableCredential:input_type -> neurouter.v1.EnableCredentialRequest
+	5,  // 15: neurouter.v1.Admin.ListLimiters:output_type -> neurouter.v1.ListLimitersResponse
+	7,  // 16: neurouter.v1.Admin.ResetLimiter:output_type -> neurouter.v1.ResetLimiterResponse
+	9,  // 17: neurouter.v1.Admin.AdjustLimiter:output_type -> neurouter.v1.AdjustLimiterResponse
+	11, // 18: neurouter.v1.Admin.EnableCredential:output_type -> neurouter.v1.EnableCredentialResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_neurouter_v1_admin_proto_init() }
+func file_neurouter_v1_admin_proto_init() {
+	if File_neurouter_v1_admin_proto != nil {
+		return
+	}
+	file_neurouter_v1_admin_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_neurouter_v1_admin_proto_rawDesc), len(file_neurouter_v1_admin_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_neurouter_v1_admin_proto_goTypes,
+		DependencyIndexes: file_neurouter_v1_admin_proto_depIdxs,
+		EnumInfos:         file_neurouter_v1_admin_proto_enumTypes,
+		MessageInfos:      file_neurouter_v1_admin_proto_msgTypes,
+	}.Build()
+	File_neurouter_v1_admin_proto = out.File
+	file_neurouter_v1_admin_proto_goTypes = nil
+	file_neurouter_v1_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,257 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: neurouter/v1/admin.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Admin_ListLimiters_FullMethodName     = "/neurouter.v1.Admin/ListLimiters"
+	Admin_ResetLimiter_FullMethodName     = "/neurouter.v1.Admin/ResetLimiter"
+	Admin_AdjustLimiter_FullMethodName    = "/neurouter.v1.Admin/AdjustLimiter"
+	Admin_EnableCredential_FullMethodName = "/neurouter.v1.Admin/EnableCredential"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admin exposes the runtime state of upstream, credential and model limiters.
+type AdminClient interface {
+	ListLimiters(ctx context.Context, in *ListLimitersRequest, opts ...grpc.CallOption) (*ListLimitersResponse, error)
+	ResetLimiter(ctx context.Context, in *ResetLimiterRequest, opts ...grpc.CallOption) (*ResetLimiterResponse, error)
+	AdjustLimiter(ctx context.Context, in *AdjustLimiterRequest, opts ...grpc.CallOption) (*AdjustLimiterResponse, error)
+	// Puts a credential disabled after the upstream rejected it back into
+	// rotation, once its key was fixed upstream.
+	EnableCredential(ctx context.Context, in *EnableCredentialRequest, opts ...grpc.CallOption) (*EnableCredentialResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListLimiters(ctx context.Context, in *ListLimitersRequest, opts ...grpc.CallOption) (*ListLimitersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLimitersResponse)
+	err := c.cc.Invoke(ctx, Admin_ListLimiters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ResetLimiter(ctx context.Context, in *ResetLimiterRequest, opts ...grpc.CallOption) (*ResetLimiterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetLimiterResponse)
+	err := c.cc.Invoke(ctx, Admin_ResetLimiter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AdjustLimiter(ctx context.Context, in *AdjustLimiterRequest, opts ...grpc.CallOption) (*AdjustLimiterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdjustLimiterResponse)
+	err := c.cc.Invoke(ctx, Admin_AdjustLimiter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) EnableCredential(ctx context.Context, in *EnableCredentialRequest, opts ...grpc.CallOption) (*EnableCredentialResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnableCredentialResponse)
+	err := c.cc.Invoke(ctx, Admin_EnableCredential_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility.
+//
+// Admin exposes the runtime state of upstream, credential and model limiters.
+type AdminServer interface {
+	ListLimiters(context.Context, *ListLimitersRequest) (*ListLimitersResponse, error)
+	ResetLimiter(context.Context, *ResetLimiterRequest) (*ResetLimiterResponse, error)
+	AdjustLimiter(context.Context, *AdjustLimiterRequest) (*AdjustLimiterResponse, error)
+	// Puts a credential disabled after the upstream rejected it back into
+	// rotation, once its key was fixed upstream.
+	EnableCredential(context.Context, *EnableCredentialRequest) (*EnableCredentialResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServer struct{}
+
+func (UnimplementedAdminServer) ListLimiters(context.Context, *ListLimitersRequest) (*ListLimitersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListLimiters not implemented")
+}
+func (UnimplementedAdminServer) ResetLimiter(context.Context, *ResetLimiterRequest) (*ResetLimiterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResetLimiter not implemented")
+}
+func (UnimplementedAdminServer) AdjustLimiter(context.Context, *AdjustLimiterRequest) (*AdjustLimiterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdjustLimiter not implemented")
+}
+func (UnimplementedAdminServer) EnableCredential(context.Context, *EnableCredentialRequest) (*EnableCredentialResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnableCredential not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+func (UnimplementedAdminServer) testEmbeddedByValue()               {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	// If the following call panics, it indicates UnimplementedAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_ListLimiters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLimitersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListLimiters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListLimiters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListLimiters(ctx, req.(*ListLimitersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ResetLimiter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetLimiterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ResetLimiter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ResetLimiter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ResetLimiter(ctx, req.(*ResetLimiterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AdjustLimiter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustLimiterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AdjustLimiter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AdjustLimiter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AdjustLimiter(ctx, req.(*AdjustLimiterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_EnableCredential_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableCredentialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).EnableCredential(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_EnableCredential_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).EnableCredential(ctx, req.(*EnableCredentialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "neurouter.v1.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListLimiters",
+			Handler:    _Admin_ListLimiters_Handler,
+		},
+		{
+			MethodName: "ResetLimiter",
+			Handler:    _Admin_ResetLimiter_Handler,
+		},
+		{
+			MethodName: "AdjustLimiter",
+			Handler:    _Admin_AdjustLimiter_Handler,
+		},
+		{
+			MethodName: "EnableCredential",
+			Handler:    _Admin_EnableCredential_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "neurouter/v1/admin.proto",
+}
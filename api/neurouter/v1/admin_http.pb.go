@@ -0,0 +1,196 @@
+// Code generated by protoc-gen-go-http. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-http v2.9.2
+// - protoc             (unknown)
+// source: neurouter/v1/admin.proto
+
+package v1
+
+import (
+	context "context"
+	http "github.com/go-kratos/kratos/v3/transport/http"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the kratos package it is being compiled against.
+var _ = new(context.Context)
+
+const _ = http.SupportPackageIsVersion3
+
+const OperationAdminListLimiters = "/neurouter.v1.Admin/ListLimiters"
+const OperationAdminResetLimiter = "/neurouter.v1.Admin/ResetLimiter"
+const OperationAdminAdjustLimiter = "/neurouter.v1.Admin/AdjustLimiter"
+const OperationAdminEnableCredential = "/neurouter.v1.Admin/EnableCredential"
+
+type AdminHTTPServer interface {
+	ListLimiters(context.Context, *ListLimitersRequest) (*ListLimitersResponse, error)
+	ResetLimiter(context.Context, *ResetLimiterRequest) (*ResetLimiterResponse, error)
+	AdjustLimiter(context.Context, *AdjustLimiterRequest) (*AdjustLimiterResponse, error)
+	EnableCredential(context.Context, *EnableCredentialRequest) (*EnableCredentialResponse, error)
+}
+
+func RegisterAdminHTTPServer(s *http.Server, srv AdminHTTPServer) {
+	r := s.Route("/")
+	r.Handle("GET", "/v1/admin/limiters", _Admin_ListLimiters0_HTTP_Handler(srv))
+	r.Handle("POST", "/v1/admin/limiters/reset", _Admin_ResetLimiter0_HTTP_Handler(srv))
+	r.Handle("POST", "/v1/admin/limiters/adjust", _Admin_AdjustLimiter0_HTTP_Handler(srv))
+	r.Handle("POST", "/v1/admin/credentials/enable", _Admin_EnableCredential0_HTTP_Handler(srv))
+}
+
+func _Admin_ListLimiters0_HTTP_Handler(srv AdminHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ListLimitersRequest
+		if err := ctx.BindQuery(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAdminListLimiters)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ListLimiters(ctx, req.(*ListLimitersRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ListLimitersResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _Admin_ResetLimiter0_HTTP_Handler(srv AdminHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ResetLimiterRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAdminResetLimiter)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.ResetLimiter(ctx, req.(*ResetLimiterRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*ResetLimiterResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _Admin_AdjustLimiter0_HTTP_Handler(srv AdminHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in AdjustLimiterRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAdminAdjustLimiter)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.AdjustLimiter(ctx, req.(*AdjustLimiterRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*AdjustLimiterResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+func _Admin_EnableCredential0_HTTP_Handler(srv AdminHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in EnableCredentialRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationAdminEnableCredential)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.EnableCredential(ctx, req.(*EnableCredentialRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*EnableCredentialResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+type AdminHTTPClient interface {
+	ListLimiters(ctx context.Context, req *ListLimitersRequest, opts ...http.CallOption) (rsp *ListLimitersResponse, err error)
+	ResetLimiter(ctx context.Context, req *ResetLimiterRequest, opts ...http.CallOption) (rsp *ResetLimiterResponse, err error)
+	AdjustLimiter(ctx context.Context, req *AdjustLimiterRequest, opts ...http.CallOption) (rsp *AdjustLimiterResponse, err error)
+	EnableCredential(ctx context.Context, req *EnableCredentialRequest, opts ...http.CallOption) (rsp *EnableCredentialResponse, err error)
+}
+
+type AdminHTTPClientImpl struct {
+	cc *http.Client
+}
+
+func NewAdminHTTPClient(client *http.Client) AdminHTTPClient {
+	return &AdminHTTPClientImpl{client}
+}
+
+func (c *AdminHTTPClientImpl) ListLimiters(ctx context.Context, in *ListLimitersRequest, opts ...http.CallOption) (*ListLimitersResponse, error) {
+	var out ListLimitersResponse
+	pattern := "/v1/admin/limiters"
+	path := http.BuildPath(pattern, in, http.WithQueryParams())
+	opts = append([]http.CallOption{
+		http.Accept("application/protojson"),
+		http.Operation(OperationAdminListLimiters),
+		http.PathTemplate(pattern),
+	}, opts...)
+	err := c.cc.Invoke(ctx, "GET", path, nil, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *AdminHTTPClientImpl) ResetLimiter(ctx context.Context, in *ResetLimiterRequest, opts ...http.CallOption) (*ResetLimiterResponse, error) {
+	var out ResetLimiterResponse
+	pattern := "/v1/admin/limiters/reset"
+	path := http.BuildPath(pattern, in)
+	opts = append([]http.CallOption{
+		http.Accept("application/protojson"),
+		http.ContentType("application/protojson"),
+		http.Operation(OperationAdminResetLimiter),
+		http.PathTemplate(pattern),
+	}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *AdminHTTPClientImpl) AdjustLimiter(ctx context.Context, in *AdjustLimiterRequest, opts ...http.CallOption) (*AdjustLimiterResponse, error) {
+	var out AdjustLimiterResponse
+	pattern := "/v1/admin/limiters/adjust"
+	path := http.BuildPath(pattern, in)
+	opts = append([]http.CallOption{
+		http.Accept("application/protojson"),
+		http.ContentType("application/protojson"),
+		http.Operation(OperationAdminAdjustLimiter),
+		http.PathTemplate(pattern),
+	}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *AdminHTTPClientImpl) EnableCredential(ctx context.Context, in *EnableCredentialRequest, opts ...http.CallOption) (*EnableCredentialResponse, error) {
+	var out EnableCredentialResponse
+	pattern := "/v1/admin/credentials/enable"
+	path := http.BuildPath(pattern, in)
+	opts = append([]http.CallOption{
+		http.Accept("application/protojson"),
+		http.ContentType("application/protojson"),
+		http.Operation(OperationAdminEnableCredential),
+		http.PathTemplate(pattern),
+	}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -252,6 +252,8 @@ type ChatRequest struct {
 	Messages []*Message `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`
 	// The tools available for the model to use
 	Tools []*Tool `protobuf:"bytes,6,rep,name=tools,proto3" json:"tools,omitempty"`
+	// How the model uses the tools, leaving the upstream default if unset
+	ToolChoice *ToolChoice `protobuf:"bytes,7,opt,name=tool_choice,json=toolChoice,proto3" json:"tool_choice,omitempty"`
 	// Additional metadata for the request
 	Metadata      map[string]string `protobuf:"bytes,15,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
@@ -330,6 +332,13 @@ func (x *ChatRequest) GetTools() []*Tool {
 	return nil
 }
 
+func (x *ChatRequest) GetToolChoice() *ToolChoice {
+	if x != nil {
+		return x.ToolChoice
+	}
+	return nil
+}
+
 func (x *ChatRequest) GetMetadata() map[string]string {
 	if x != nil {
 		return x.Metadata
@@ -418,6 +427,69 @@ func (x *ChatResponse) GetStatistics() *Statistics {
 	return nil
 }
 
+type CountTokensResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The model whose tokenizer counted the request
+	Model string `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	// The number of input tokens the request would consume
+	InputTokens uint32 `protobuf:"varint,2,opt,name=input_tokens,json=inputTokens,proto3" json:"input_tokens,omitempty"`
+	// Whether the count is a local estimate rather than reported by the upstream
+	Estimated     bool `protobuf:"varint,3,opt,name=estimated,proto3" json:"estimated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountTokensResponse) Reset() {
+	*x = CountTokensResponse{}
+	mi := &file_neurouter_v1_chat_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountTokensResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountTokensResponse) ProtoMessage() {}
+
+func (x *CountTokensResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_chat_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountTokensResponse.ProtoReflect.Descriptor instead.
+func (*CountTokensResponse) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CountTokensResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CountTokensResponse) GetInputTokens() uint32 {
+	if x != nil {
+		return x.InputTokens
+	}
+	return 0
+}
+
+func (x *CountTokensResponse) GetEstimated() bool {
+	if x != nil {
+		return x.Estimated
+	}
+	return false
+}
+
 type ChatEvent struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The id of the request this event belongs to.
@@ -441,7 +513,7 @@ type ChatEvent struct {
 
 func (x *ChatEvent) Reset() {
 	*x = ChatEvent{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[3]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -453,7 +525,7 @@ func (x *ChatEvent) String() string {
 func (*ChatEvent) ProtoMessage() {}
 
 func (x *ChatEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[3]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +538,7 @@ func (x *ChatEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatEvent.ProtoReflect.Descriptor instead.
 func (*ChatEvent) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{3}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ChatEvent) GetId() string {
@@ -607,7 +679,7 @@ type MessageStart struct {
 
 func (x *MessageStart) Reset() {
 	*x = MessageStart{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[4]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -619,7 +691,7 @@ func (x *MessageStart) String() string {
 func (*MessageStart) ProtoMessage() {}
 
 func (x *MessageStart) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[4]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -632,7 +704,7 @@ func (x *MessageStart) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageStart.ProtoReflect.Descriptor instead.
 func (*MessageStart) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{4}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *MessageStart) GetId() string {
@@ -659,7 +731,7 @@ type MessageStop struct {
 
 func (x *MessageStop) Reset() {
 	*x = MessageStop{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[5]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -671,7 +743,7 @@ func (x *MessageStop) String() string {
 func (*MessageStop) ProtoMessage() {}
 
 func (x *MessageStop) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[5]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -684,7 +756,7 @@ func (x *MessageStop) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageStop.ProtoReflect.Descriptor instead.
 func (*MessageStop) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{5}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *MessageStop) GetStatus() ChatStatus {
@@ -702,7 +774,7 @@ type TextStart struct {
 
 func (x *TextStart) Reset() {
 	*x = TextStart{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[6]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -714,7 +786,7 @@ func (x *TextStart) String() string {
 func (*TextStart) ProtoMessage() {}
 
 func (x *TextStart) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[6]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -727,7 +799,7 @@ func (x *TextStart) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TextStart.ProtoReflect.Descriptor instead.
 func (*TextStart) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{6}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{7}
 }
 
 type ToolUseStart struct {
@@ -740,7 +812,7 @@ type ToolUseStart struct {
 
 func (x *ToolUseStart) Reset() {
 	*x = ToolUseStart{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[7]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -752,7 +824,7 @@ func (x *ToolUseStart) String() string {
 func (*ToolUseStart) ProtoMessage() {}
 
 func (x *ToolUseStart) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[7]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -765,7 +837,7 @@ func (x *ToolUseStart) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ToolUseStart.ProtoReflect.Descriptor instead.
 func (*ToolUseStart) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{7}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ToolUseStart) GetId() string {
@@ -791,6 +863,8 @@ type ContentStart struct {
 	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
 	// The semantic phase of the content
 	Phase ContentPhase `protobuf:"varint,3,opt,name=phase,proto3,enum=neurouter.v1.ContentPhase" json:"phase,omitempty"`
+	// The provider family that produces the content, as in Content.provenance.
+	Provenance Provenance `protobuf:"varint,4,opt,name=provenance,proto3,enum=neurouter.v1.Provenance" json:"provenance,omitempty"`
 	// Additional metadata for the content
 	Metadata map[string]string `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	// Types that are valid to be assigned to Content:
@@ -804,7 +878,7 @@ type ContentStart struct {
 
 func (x *ContentStart) Reset() {
 	*x = ContentStart{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[8]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -816,7 +890,7 @@ func (x *ContentStart) String() string {
 func (*ContentStart) ProtoMessage() {}
 
 func (x *ContentStart) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[8]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -829,7 +903,7 @@ func (x *ContentStart) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContentStart.ProtoReflect.Descriptor instead.
 func (*ContentStart) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{8}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ContentStart) GetId() string {
@@ -853,6 +927,13 @@ func (x *ContentStart) GetPhase() ContentPhase {
 	return ContentPhase_CONTENT_PHASE_NORMAL
 }
 
+func (x *ContentStart) GetProvenance() Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return Provenance_PROVENANCE_UNSPECIFIED
+}
+
 func (x *ContentStart) GetMetadata() map[string]string {
 	if x != nil {
 		return x.Metadata
@@ -917,7 +998,7 @@ type ContentDelta struct {
 
 func (x *ContentDelta) Reset() {
 	*x = ContentDelta{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[9]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -929,7 +1010,7 @@ func (x *ContentDelta) String() string {
 func (*ContentDelta) ProtoMessage() {}
 
 func (x *ContentDelta) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[9]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -942,7 +1023,7 @@ func (x *ContentDelta) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContentDelta.ProtoReflect.Descriptor instead.
 func (*ContentDelta) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{9}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ContentDelta) GetIndex() uint32 {
@@ -1022,7 +1103,7 @@ type ContentStop struct {
 
 func (x *ContentStop) Reset() {
 	*x = ContentStop{}
-	mi := &file_neurouter_v1_chat_proto_msgTypes[10]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1034,7 +1115,7 @@ func (x *ContentStop) String() string {
 func (*ContentStop) ProtoMessage() {}
 
 func (x *ContentStop) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_chat_proto_msgTypes[10]
+	mi := &file_neurouter_v1_chat_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1047,7 +1128,7 @@ func (x *ContentStop) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ContentStop.ProtoReflect.Descriptor instead.
 func (*ContentStop) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{10}
+	return file_neurouter_v1_chat_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ContentStop) GetIndex() uint32 {
@@ -1066,14 +1147,16 @@ const file_neurouter_v1_chat_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
 	"\x04role\x18\x02 \x01(\x0e2\x12.neurouter.v1.RoleR\x04role\x121\n" +
 	"\bcontents\x18\x03 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\x12\x12\n" +
-	"\x04name\x18\x04 \x01(\tR\x04name\"\xe4\x02\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\"\x9f\x03\n" +
 	"\vChatRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\asession\x18\x02 \x01(\tR\asession\x12\x14\n" +
 	"\x05model\x18\x03 \x01(\tR\x05model\x126\n" +
 	"\x06config\x18\x04 \x01(\v2\x1e.neurouter.v1.GenerationConfigR\x06config\x121\n" +
 	"\bmessages\x18\x05 \x03(\v2\x15.neurouter.v1.MessageR\bmessages\x12(\n" +
-	"\x05tools\x18\x06 \x03(\v2\x12.neurouter.v1.ToolR\x05tools\x12C\n" +
+	"\x05tools\x18\x06 \x03(\v2\x12.neurouter.v1.ToolR\x05tools\x129\n" +
+	"\vtool_choice\x18\a \x01(\v2\x18.neurouter.v1.ToolChoiceR\n" +
+	"toolChoice\x12C\n" +
 	"\bmetadata\x18\x0f \x03(\v2'.neurouter.v1.ChatRequest.MetadataEntryR\bmetadata\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
@@ -1085,7 +1168,11 @@ const file_neurouter_v1_chat_proto_rawDesc = "" +
 	"\amessage\x18\x04 \x01(\v2\x15.neurouter.v1.MessageR\amessage\x128\n" +
 	"\n" +
 	"statistics\x18\x05 \x01(\v2\x18.neurouter.v1.StatisticsR\n" +
-	"statistics\"\xdc\x03\n" +
+	"statistics\"l\n" +
+	"\x13CountTokensResponse\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12!\n" +
+	"\finput_tokens\x18\x02 \x01(\rR\vinputTokens\x12\x1c\n" +
+	"\testimated\x18\x03 \x01(\bR\testimated\"\xdc\x03\n" +
 	"\tChatEvent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
 	"\x05usage\x18\x02 \x01(\v2\x13.neurouter.v1.UsageR\x05usage\x12A\n" +
@@ -1104,11 +1191,14 @@ const file_neurouter_v1_chat_proto_rawDesc = "" +
 	"\tTextStart\"2\n" +
 	"\fToolUseStart\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"\xdc\x02\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x96\x03\n" +
 	"\fContentStart\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05index\x18\x02 \x01(\rR\x05index\x120\n" +
-	"\x05phase\x18\x03 \x01(\x0e2\x1a.neurouter.v1.ContentPhaseR\x05phase\x12D\n" +
+	"\x05phase\x18\x03 \x01(\x0e2\x1a.neurouter.v1.ContentPhaseR\x05phase\x128\n" +
+	"\n" +
+	"provenance\x18\x04 \x01(\x0e2\x18.neurouter.v1.ProvenanceR\n" +
+	"provenance\x12D\n" +
 	"\bmetadata\x18\t \x03(\v2(.neurouter.v1.ContentStart.MetadataEntryR\bmetadata\x12-\n" +
 	"\x04text\x18\n" +
 	" \x01(\v2\x17.neurouter.v1.TextStartH\x00R\x04text\x127\n" +
@@ -1141,11 +1231,12 @@ const file_neurouter_v1_chat_proto_rawDesc = "" +
 	"\x13CHAT_STATUS_REFUSED\x10\x04\x12\x19\n" +
 	"\x15CHAT_STATUS_CANCELLED\x10\x05\x12 \n" +
 	"\x1cCHAT_STATUS_PENDING_TOOL_USE\x10\x06\x12#\n" +
-	"\x1fCHAT_STATUS_REACHED_TOKEN_LIMIT\x10\a2\xa0\x01\n" +
+	"\x1fCHAT_STATUS_REACHED_TOKEN_LIMIT\x10\a2\x8f\x02\n" +
 	"\x04Chat\x12R\n" +
 	"\x04Chat\x12\x19.neurouter.v1.ChatRequest\x1a\x1a.neurouter.v1.ChatResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/v1/chat\x12D\n" +
 	"\n" +
-	"ChatStream\x12\x19.neurouter.v1.ChatRequest\x1a\x17.neurouter.v1.ChatEvent\"\x000\x01B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
+	"ChatStream\x12\x19.neurouter.v1.ChatRequest\x1a\x17.neurouter.v1.ChatEvent\"\x000\x01\x12m\n" +
+	"\vCountTokens\x12\x19.neurouter.v1.ChatRequest\x1a!.neurouter.v1.CountTokensResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/chat/count_tokensB3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
 
 var (
 	file_neurouter_v1_chat_proto_rawDescOnce sync.Once
@@ -1160,61 +1251,68 @@ func file_neurouter_v1_chat_proto_rawDescGZIP() []byte {
 }
 
 var file_neurouter_v1_chat_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_neurouter_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_neurouter_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_neurouter_v1_chat_proto_goTypes = []any{
-	(Role)(0),                // 0: neurouter.v1.Role
-	(ChatStatus)(0),          // 1: neurouter.v1.ChatStatus
-	(*Message)(nil),          // 2: neurouter.v1.Message
-	(*ChatRequest)(nil),      // 3: neurouter.v1.ChatRequest
-	(*ChatResponse)(nil),     // 4: neurouter.v1.ChatResponse
-	(*ChatEvent)(nil),        // 5: neurouter.v1.ChatEvent
-	(*MessageStart)(nil),     // 6: neurouter.v1.MessageStart
-	(*MessageStop)(nil),      // 7: neurouter.v1.MessageStop
-	(*TextStart)(nil),        // 8: neurouter.v1.TextStart
-	(*ToolUseStart)(nil),     // 9: neurouter.v1.ToolUseStart
-	(*ContentStart)(nil),     // 10: neurouter.v1.ContentStart
-	(*ContentDelta)(nil),     // 11: neurouter.v1.ContentDelta
-	(*ContentStop)(nil),      // 12: neurouter.v1.ContentStop
-	nil,                      // 13: neurouter.v1.ChatRequest.MetadataEntry
-	nil,                      // 14: neurouter.v1.ContentStart.MetadataEntry
-	(*Content)(nil),          // 15: neurouter.v1.Content
-	(*GenerationConfig)(nil), // 16: neurouter.v1.GenerationConfig
-	(*Tool)(nil),             // 17: neurouter.v1.Tool
-	(*Statistics)(nil),       // 18: neurouter.v1.Statistics
-	(*Usage)(nil),            // 19: neurouter.v1.Usage
-	(ContentPhase)(0),        // 20: neurouter.v1.ContentPhase
+	(Role)(0),                   // 0: neurouter.v1.Role
+	(ChatStatus)(0),             // 1: neurouter.v1.ChatStatus
+	(*Message)(nil),             // 2: neurouter.v1.Message
+	(*ChatRequest)(nil),         // 3: neurouter.v1.ChatRequest
+	(*ChatResponse)(nil),        // 4: neurouter.v1.ChatResponse
+	(*CountTokensResponse)(nil), // 5: neurouter.v1.CountTokensResponse
+	(*ChatEvent)(nil),           // 6: neurouter.v1.ChatEvent
+	(*MessageStart)(nil),        // 7: neurouter.v1.MessageStart
+	(*MessageStop)(nil),         // 8: neurouter.v1.MessageStop
+	(*TextStart)(nil),           // 9: neurouter.v1.TextStart
+	(*ToolUseStart)(nil),        // 10: neurouter.v1.ToolUseStart
+	(*ContentStart)(nil),        // 11: neurouter.v1.ContentStart
+	(*ContentDelta)(nil),        // 12: neurouter.v1.ContentDelta
+	(*ContentStop)(nil),         // 13: neurouter.v1.ContentStop
+	nil,                         // 14: neurouter.v1.ChatRequest.MetadataEntry
+	nil,                         // 15: neurouter.v1.ContentStart.MetadataEntry
+	(*Content)(nil),             // 16: neurouter.v1.Content
+	(*GenerationConfig)(nil),    // 17: neurouter.v1.GenerationConfig
+	(*Tool)(nil),                // 18: neurouter.v1.Tool
+	(*ToolChoice)(nil),          // 19: neurouter.v1.ToolChoice
+	(*Statistics)(nil),          // 20: neurouter.v1.Statistics
+	(*Usage)(nil),               // 21: neurouter.v1.Usage
+	(ContentPhase)(0),           // 22: neurouter.v1.ContentPhase
+	(Provenance)(0),             // 23: neurouter.v1.Provenance
 }
 var file_neurouter_v1_chat_proto_depIdxs = []int32{
 	0,  // 0: neurouter.v1.Message.role:type_name -> neurouter.v1.Role
-	15, // 1: neurouter.v1.Message.contents:type_name -> neurouter.v1.Content
-	16, // 2: neurouter.v1.ChatRequest.config:type_name -> neurouter.v1.GenerationConfig
+	16, // 1: neurouter.v1.Message.contents:type_name -> neurouter.v1.Content
+	17, // 2: neurouter.v1.ChatRequest.config:type_name -> neurouter.v1.GenerationConfig
 	2,  // 3: neurouter.v1.ChatRequest.messages:type_name -> neurouter.v1.Message
-	17, // 4: neurouter.v1.ChatRequest.tools:type_name -> neurouter.v1.Tool
-	13, // 5: neurouter.v1.ChatRequest.metadata:type_name -> neurouter.v1.ChatRequest.MetadataEntry
-	1,  // 6: neurouter.v1.ChatResponse.status:type_name -> neurouter.v1.ChatStatus
-	2,  // 7: neurouter.v1.ChatResponse.message:type_name -> neurouter.v1.Message
-	18, // 8: neurouter.v1.ChatResponse.statistics:type_name -> neurouter.v1.Statistics
-	19, // 9: neurouter.v1.ChatEvent.usage:type_name -> neurouter.v1.Usage
-	6,  // 10: neurouter.v1.ChatEvent.message_start:type_name -> neurouter.v1.MessageStart
-	7,  // 11: neurouter.v1.ChatEvent.message_stop:type_name -> neurouter.v1.MessageStop
-	10, // 12: neurouter.v1.ChatEvent.content_start:type_name -> neurouter.v1.ContentStart
-	11, // 13: neurouter.v1.ChatEvent.content_delta:type_name -> neurouter.v1.ContentDelta
-	12, // 14: neurouter.v1.ChatEvent.content_stop:type_name -> neurouter.v1.ContentStop
-	15, // 15: neurouter.v1.ChatEvent.content_snapshot:type_name -> neurouter.v1.Content
-	1,  // 16: neurouter.v1.MessageStop.status:type_name -> neurouter.v1.ChatStatus
-	20, // 17: neurouter.v1.ContentStart.phase:type_name -> neurouter.v1.ContentPhase
-	14, // 18: neurouter.v1.ContentStart.metadata:type_name -> neurouter.v1.ContentStart.MetadataEntry
-	8,  // 19: neurouter.v1.ContentStart.text:type_name -> neurouter.v1.TextStart
-	9,  // 20: neurouter.v1.ContentStart.tool_use:type_name -> neurouter.v1.ToolUseStart
-	3,  // 21: neurouter.v1.Chat.Chat:input_type -> neurouter.v1.ChatRequest
-	3,  // 22: neurouter.v1.Chat.ChatStream:input_type -> neurouter.v1.ChatRequest
-	4,  // 23: neurouter.v1.Chat.Chat:output_type -> neurouter.v1.ChatResponse
-	5,  // 24: neurouter.v1.Chat.ChatStream:output_type -> neurouter.v1.ChatEvent
-	23, // [23:25] is the sub-list for method output_type
-	21, // [21:23] is the sub-list for method input_type
-	21, // [21:21] is the sub-list for extension type_name
-	21, // [21:21] is the sub-list for extension extendee
-	0,  // [0:21] is the sub-list for field type_name
+	18, // 4: neurouter.v1.ChatRequest.tools:type_name -> neurouter.v1.Tool
+	19, // 5: neurouter.v1.ChatRequest.tool_choice:type_name -> neurouter.v1.ToolChoice
+	14, // 6: neurouter.v1.ChatRequest.metadata:type_name -> neurouter.v1.ChatRequest.MetadataEntry
+	1,  // 7: neurouter.v1.ChatResponse.status:type_name -> neurouter.v1.ChatStatus
+	2,  // 8: neurouter.v1.ChatResponse.message:type_name -> neurouter.v1.Message
+	20, // 9: neurouter.v1.ChatResponse.statistics:type_name -> neurouter.v1.Statistics
+	21, // 10: neurouter.v1.ChatEvent.usage:type_name -> neurouter.v1.Usage
+	7,  // 11: neurouter.v1.ChatEvent.message_start:type_name -> neurouter.v1.MessageStart
+	8,  // 12: neurouter.v1.ChatEvent.message_stop:type_name -> neurouter.v1.MessageStop
+	11, // 13: neurouter.v1.ChatEvent.content_start:type_name -> neurouter.v1.ContentStart
+	12, // 14: neurouter.v1.ChatEvent.content_delta:type_name -> neurouter.v1.ContentDelta
+	13, // 15: neurouter.v1.ChatEvent.content_stop:type_name -> neurouter.v1.ContentStop
+	16, // 16: neurouter.v1.ChatEvent.content_snapshot:type_name -> neurouter.v1.Content
+	1,  // 17: neurouter.v1.MessageStop.status:type_name -> neurouter.v1.ChatStatus
+	22, // 18: neurouter.v1.ContentStart.phase:type_name -> neurouter.v1.ContentPhase
+	23, // 19: neurouter.v1.ContentStart.provenance:type_name -> neurouter.v1.Provenance
+	15, // 20: neurouter.v1.ContentStart.metadata:type_name -> neurouter.v1.ContentStart.MetadataEntry
+	9,  // 21: neurouter.v1.ContentStart.text:type_name -> neurouter.v1.TextStart
+	10, // 22: neurouter.v1.ContentStart.tool_use:type_name -> neurouter.v1.ToolUseStart
+	3,  // 23: neurouter.v1.Chat.Chat:input_type -> neurouter.v1.ChatRequest
+	3,  // 24: neurouter.v1.Chat.ChatStream:input_type -> neurouter.v1.ChatRequest
+	3,  // 25: neurouter.v1.Chat.CountTokens:input_type -> neurouter.v1.ChatRequest
+	4,  // 26: neurouter.v1.Chat.Chat:output_type -> neurouter.v1.ChatResponse
+	6,  // 27: neurouter.v1.Chat.ChatStream:output_type -> neurouter.v1.ChatEvent
+	5,  // 28: neurouter.v1.Chat.CountTokens:output_type -> neurouter.v1.CountTokensResponse
+	26, // [26:29] is the sub-list for method output_type
+	23, // [23:26] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_neurouter_v1_chat_proto_init() }
@@ -1224,7 +1322,7 @@ func file_neurouter_v1_chat_proto_init() {
 	}
 	file_neurouter_v1_common_proto_init()
 	file_neurouter_v1_content_proto_init()
-	file_neurouter_v1_chat_proto_msgTypes[3].OneofWrappers = []any{
+	file_neurouter_v1_chat_proto_msgTypes[4].OneofWrappers = []any{
 		(*ChatEvent_MessageStart)(nil),
 		(*ChatEvent_MessageStop)(nil),
 		(*ChatEvent_ContentStart)(nil),
@@ -1232,11 +1330,11 @@ func file_neurouter_v1_chat_proto_init() {
 		(*ChatEvent_ContentStop)(nil),
 		(*ChatEvent_ContentSnapshot)(nil),
 	}
-	file_neurouter_v1_chat_proto_msgTypes[8].OneofWrappers = []any{
+	file_neurouter_v1_chat_proto_msgTypes[9].OneofWrappers = []any{
 		(*ContentStart_Text)(nil),
 		(*ContentStart_ToolUse)(nil),
 	}
-	file_neurouter_v1_chat_proto_msgTypes[9].OneofWrappers = []any{
+	file_neurouter_v1_chat_proto_msgTypes[10].OneofWrappers = []any{
 		(*ContentDelta_Text)(nil),
 		(*ContentDelta_Signature)(nil),
 		(*ContentDelta_ToolInputText)(nil),
@@ -1247,7 +1345,7 @@ func file_neurouter_v1_chat_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_neurouter_v1_chat_proto_rawDesc), len(file_neurouter_v1_chat_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   13,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -33,8 +33,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Chat_Chat_FullMethodName       = "/neurouter.v1.Chat/Chat"
-	Chat_ChatStream_FullMethodName = "/neurouter.v1.Chat/ChatStream"
+	Chat_Chat_FullMethodName        = "/neurouter.v1.Chat/Chat"
+	Chat_ChatStream_FullMethodName  = "/neurouter.v1.Chat/ChatStream"
+	Chat_CountTokens_FullMethodName = "/neurouter.v1.Chat/CountTokens"
 )
 
 // ChatClient is the client API for Chat service.
@@ -47,6 +48,11 @@ type ChatClient interface {
 	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
 	// buf:lint:ignore RPC_RESPONSE_STANDARD_NAME
 	ChatStream(ctx context.Context, in *ChatRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatEvent], error)
+	// Counts the input tokens of a chat request without generating. Upstreams
+	// with native counting are asked, others are estimated locally.
+	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
+	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
+	CountTokens(ctx context.Context, in *ChatRequest, opts ...grpc.CallOption) (*CountTokensResponse, error)
 }
 
 type chatClient struct {
@@ -86,6 +92,16 @@ func (c *chatClient) ChatStream(ctx context.Context, in *ChatRequest, opts ...gr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Chat_ChatStreamClient = grpc.ServerStreamingClient[ChatEvent]
 
+func (c *chatClient) CountTokens(ctx context.Context, in *ChatRequest, opts ...grpc.CallOption) (*CountTokensResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountTokensResponse)
+	err := c.cc.Invoke(ctx, Chat_CountTokens_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatServer is the server API for Chat service.
 // All implementations must embed UnimplementedChatServer
 // for forward compatibility.
@@ -96,6 +112,11 @@ type ChatServer interface {
 	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
 	// buf:lint:ignore RPC_RESPONSE_STANDARD_NAME
 	ChatStream(*ChatRequest, grpc.ServerStreamingServer[ChatEvent]) error
+	// Counts the input tokens of a chat request without generating. Upstreams
+	// with native counting are asked, others are estimated locally.
+	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
+	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
+	CountTokens(context.Context, *ChatRequest) (*CountTokensResponse, error)
 	mustEmbedUnimplementedChatServer()
 }
 
@@ -112,6 +133,9 @@ func (UnimplementedChatServer) Chat(context.Context, *ChatRequest) (*ChatRespons
 func (UnimplementedChatServer) ChatStream(*ChatRequest, grpc.ServerStreamingServer[ChatEvent]) error {
 	return status.Error(codes.Unimplemented, "method ChatStream not implemented")
 }
+func (UnimplementedChatServer) CountTokens(context.Context, *ChatRequest) (*CountTokensResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CountTokens not implemented")
+}
 func (UnimplementedChatServer) mustEmbedUnimplementedChatServer() {}
 func (UnimplementedChatServer) testEmbeddedByValue()              {}
 
@@ -162,6 +186,24 @@ func _Chat_ChatStream_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Chat_ChatStreamServer = grpc.ServerStreamingServer[ChatEvent]
 
+func _Chat_CountTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatServer).CountTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Chat_CountTokens_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatServer).CountTokens(ctx, req.(*ChatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Chat_ServiceDesc is the grpc.ServiceDesc for Chat service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -173,6 +215,10 @@ var Chat_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Chat",
 			Handler:    _Chat_Chat_Handler,
 		},
+		{
+			MethodName: "CountTokens",
+			Handler:    _Chat_CountTokens_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
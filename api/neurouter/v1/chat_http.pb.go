@@ -18,15 +18,22 @@ var _ = new(context.Context)
 const _ = http.SupportPackageIsVersion3
 
 const OperationChatChat = "/neurouter.v1.Chat/Chat"
+const OperationChatCountTokens = "/neurouter.v1.Chat/CountTokens"
 
 type ChatHTTPServer interface {
 	// Chat buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	Chat(context.Context, *ChatRequest) (*ChatResponse, error)
+	// CountTokens Counts the input tokens of a chat request without generating. Upstreams
+	// with native counting are asked, others are estimated locally.
+	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
+	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
+	CountTokens(context.Context, *ChatRequest) (*CountTokensResponse, error)
 }
 
 func RegisterChatHTTPServer(s *http.Server, srv ChatHTTPServer) {
 	r := s.Route("/")
 	r.Handle("POST", "/v1/chat", _Chat_Chat0_HTTP_Handler(srv))
+	r.Handle("POST", "/v1/chat/count_tokens", _Chat_CountTokens0_HTTP_Handler(srv))
 }
 
 func _Chat_Chat0_HTTP_Handler(srv ChatHTTPServer) func(ctx http.Context) error {
@@ -48,9 +55,33 @@ func _Chat_Chat0_HTTP_Handler(srv ChatHTTPServer) func(ctx http.Context) error {
 	}
 }
 
+func _Chat_CountTokens0_HTTP_Handler(srv ChatHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in ChatRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationChatCountTokens)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.CountTokens(ctx, req.(*ChatRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*CountTokensResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
 type ChatHTTPClient interface {
 	// Chat buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	Chat(ctx context.Context, req *ChatRequest, opts ...http.CallOption) (rsp *ChatResponse, err error)
+	// CountTokens Counts the input tokens of a chat request without generating. Upstreams
+	// with native counting are asked, others are estimated locally.
+	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
+	// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
+	CountTokens(ctx context.Context, req *ChatRequest, opts ...http.CallOption) (rsp *CountTokensResponse, err error)
 }
 
 type ChatHTTPClientImpl struct {
@@ -78,3 +109,24 @@ func (c *ChatHTTPClientImpl) Chat(ctx context.Context, in *ChatRequest, opts ...
 	}
 	return &out, nil
 }
+
+// CountTokens Counts the input tokens of a chat request without generating. Upstreams
+// with native counting are asked, others are estimated locally.
+// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
+// buf:lint:ignore RPC_REQUEST_STANDARD_NAME
+func (c *ChatHTTPClientImpl) CountTokens(ctx context.Context, in *ChatRequest, opts ...http.CallOption) (*CountTokensResponse, error) {
+	var out CountTokensResponse
+	pattern := "/v1/chat/count_tokens"
+	path := http.BuildPath(pattern, in)
+	opts = append([]http.CallOption{
+		http.Accept("application/protojson"),
+		http.ContentType("application/protojson"),
+		http.Operation(OperationChatCountTokens),
+		http.PathTemplate(pattern),
+	}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -160,11 +160,12 @@ func (Modality) EnumDescriptor() ([]byte, []int) {
 type Capability int32
 
 const (
-	Capability_CAPABILITY_UNSPECIFIED Capability = 0
-	Capability_CAPABILITY_CHAT        Capability = 1
-	Capability_CAPABILITY_COMPLETION  Capability = 2
-	Capability_CAPABILITY_EMBEDDING   Capability = 3
-	Capability_CAPABILITY_TOOL_USE    Capability = 4
+	Capability_CAPABILITY_UNSPECIFIED       Capability = 0
+	Capability_CAPABILITY_CHAT              Capability = 1
+	Capability_CAPABILITY_COMPLETION        Capability = 2
+	Capability_CAPABILITY_EMBEDDING         Capability = 3
+	Capability_CAPABILITY_TOOL_USE          Capability = 4
+	Capability_CAPABILITY_STRUCTURED_OUTPUT Capability = 5
 )
 
 // Enum value maps for Capability.
@@ -175,13 +176,15 @@ var (
 		2: "CAPABILITY_COMPLETION",
 		3: "CAPABILITY_EMBEDDING",
 		4: "CAPABILITY_TOOL_USE",
+		5: "CAPABILITY_STRUCTURED_OUTPUT",
 	}
 	Capability_value = map[string]int32{
-		"CAPABILITY_UNSPECIFIED": 0,
-		"CAPABILITY_CHAT":        1,
-		"CAPABILITY_COMPLETION":  2,
-		"CAPABILITY_EMBEDDING":   3,
-		"CAPABILITY_TOOL_USE":    4,
+		"CAPABILITY_UNSPECIFIED":       0,
+		"CAPABILITY_CHAT":              1,
+		"CAPABILITY_COMPLETION":        2,
+		"CAPABILITY_EMBEDDING":         3,
+		"CAPABILITY_TOOL_USE":          4,
+		"CAPABILITY_STRUCTURED_OUTPUT": 5,
 	}
 )
 
@@ -212,6 +215,66 @@ func (Capability) EnumDescriptor() ([]byte, []int) {
 	return file_neurouter_v1_common_proto_rawDescGZIP(), []int{2}
 }
 
+type ToolChoiceMode int32
+
+const (
+	// The upstream default, which is normally the same as auto.
+	ToolChoiceMode_TOOL_CHOICE_MODE_UNSPECIFIED ToolChoiceMode = 0
+	// The model decides whether to call tools.
+	ToolChoiceMode_TOOL_CHOICE_MODE_AUTO ToolChoiceMode = 1
+	// The model must not call any tool.
+	ToolChoiceMode_TOOL_CHOICE_MODE_NONE ToolChoiceMode = 2
+	// The model must call at least one tool.
+	ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED ToolChoiceMode = 3
+	// The model must call the named tool.
+	ToolChoiceMode_TOOL_CHOICE_MODE_TOOL ToolChoiceMode = 4
+)
+
+// Enum value maps for ToolChoiceMode.
+var (
+	ToolChoiceMode_name = map[int32]string{
+		0: "TOOL_CHOICE_MODE_UNSPECIFIED",
+		1: "TOOL_CHOICE_MODE_AUTO",
+		2: "TOOL_CHOICE_MODE_NONE",
+		3: "TOOL_CHOICE_MODE_REQUIRED",
+		4: "TOOL_CHOICE_MODE_TOOL",
+	}
+	ToolChoiceMode_value = map[string]int32{
+		"TOOL_CHOICE_MODE_UNSPECIFIED": 0,
+		"TOOL_CHOICE_MODE_AUTO":        1,
+		"TOOL_CHOICE_MODE_NONE":        2,
+		"TOOL_CHOICE_MODE_REQUIRED":    3,
+		"TOOL_CHOICE_MODE_TOOL":        4,
+	}
+)
+
+func (x ToolChoiceMode) Enum() *ToolChoiceMode {
+	p := new(ToolChoiceMode)
+	*p = x
+	return p
+}
+
+func (x ToolChoiceMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ToolChoiceMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_neurouter_v1_common_proto_enumTypes[3].Descriptor()
+}
+
+func (ToolChoiceMode) Type() protoreflect.EnumType {
+	return &file_neurouter_v1_common_proto_enumTypes[3]
+}
+
+func (x ToolChoiceMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ToolChoiceMode.Descriptor instead.
+func (ToolChoiceMode) EnumDescriptor() ([]byte, []int) {
+	return file_neurouter_v1_common_proto_rawDescGZIP(), []int{3}
+}
+
 type ReasoningConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The effort level for reasoning.
@@ -480,8 +543,11 @@ type Usage struct {
 	CachedInputTokens uint32 `protobuf:"varint,3,opt,name=cached_input_tokens,json=cachedInputTokens,proto3" json:"cached_input_tokens,omitempty"`
 	// Number of reasoning tokens consumed (subset of output_tokens for reasoning models).
 	ReasoningTokens uint32 `protobuf:"varint,4,opt,name=reasoning_tokens,json=reasoningTokens,proto3" json:"reasoning_tokens,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Number of input tokens written to prompt cache (subset of input_tokens,
+	// disjoint from cached_input_tokens).
+	CacheWriteInputTokens uint32 `protobuf:"varint,5,opt,name=cache_write_input_tokens,json=cacheWriteInputTokens,proto3" json:"cache_write_input_tokens,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *Usage) Reset() {
@@ -542,6 +608,13 @@ func (x *Usage) GetReasoningTokens() uint32 {
 	return 0
 }
 
+func (x *Usage) GetCacheWriteInputTokens() uint32 {
+	if x != nil {
+		return x.CacheWriteInputTokens
+	}
+	return 0
+}
+
 // Statistics contains model invocation statistics.
 type Statistics struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -653,6 +726,80 @@ type Tool_Function_ struct {
 
 func (*Tool_Function_) isTool_Tool() {}
 
+// ToolChoice controls how the model uses the tools of a request.
+type ToolChoice struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Mode  ToolChoiceMode         `protobuf:"varint,1,opt,name=mode,proto3,enum=neurouter.v1.ToolChoiceMode" json:"mode,omitempty"`
+	// The name of the function the model must call in TOOL_CHOICE_MODE_TOOL.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Whether the model may call several tools in one turn. Unset leaves the
+	// upstream default, which normally allows it.
+	ParallelToolCalls *bool `protobuf:"varint,3,opt,name=parallel_tool_calls,json=parallelToolCalls,proto3,oneof" json:"parallel_tool_calls,omitempty"`
+	// The names of the tools the model may call in the auto and required modes.
+	// Empty allows every tool of the request.
+	AllowedNames  []string `protobuf:"bytes,4,rep,name=allowed_names,json=allowedNames,proto3" json:"allowed_names,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolChoice) Reset() {
+	*x = ToolChoice{}
+	mi := &file_neurouter_v1_common_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolChoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolChoice) ProtoMessage() {}
+
+func (x *ToolChoice) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_common_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolChoice.ProtoReflect.Descriptor instead.
+func (*ToolChoice) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_common_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ToolChoice) GetMode() ToolChoiceMode {
+	if x != nil {
+		return x.Mode
+	}
+	return ToolChoiceMode_TOOL_CHOICE_MODE_UNSPECIFIED
+}
+
+func (x *ToolChoice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolChoice) GetParallelToolCalls() bool {
+	if x != nil && x.ParallelToolCalls != nil {
+		return *x.ParallelToolCalls
+	}
+	return false
+}
+
+func (x *ToolChoice) GetAllowedNames() []string {
+	if x != nil {
+		return x.AllowedNames
+	}
+	return nil
+}
+
 type Tool_Function struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -664,7 +811,7 @@ type Tool_Function struct {
 
 func (x *Tool_Function) Reset() {
 	*x = Tool_Function{}
-	mi := &file_neurouter_v1_common_proto_msgTypes[5]
+	mi := &file_neurouter_v1_common_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -676,7 +823,7 @@ func (x *Tool_Function) String() string {
 func (*Tool_Function) ProtoMessage() {}
 
 func (x *Tool_Function) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_common_proto_msgTypes[5]
+	mi := &file_neurouter_v1_common_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -744,12 +891,13 @@ const file_neurouter_v1_common_proto_rawDesc = "" +
 	"\x06_top_kB\x14\n" +
 	"\x12_frequency_penaltyB\x13\n" +
 	"\x11_presence_penaltyB\x13\n" +
-	"\x11_reasoning_config\"\xaa\x01\n" +
+	"\x11_reasoning_config\"\xe3\x01\n" +
 	"\x05Usage\x12!\n" +
 	"\finput_tokens\x18\x01 \x01(\rR\vinputTokens\x12#\n" +
 	"\routput_tokens\x18\x02 \x01(\rR\foutputTokens\x12.\n" +
 	"\x13cached_input_tokens\x18\x03 \x01(\rR\x11cachedInputTokens\x12)\n" +
-	"\x10reasoning_tokens\x18\x04 \x01(\rR\x0freasoningTokens\"7\n" +
+	"\x10reasoning_tokens\x18\x04 \x01(\rR\x0freasoningTokens\x127\n" +
+	"\x18cache_write_input_tokens\x18\x05 \x01(\rR\x15cacheWriteInputTokens\"7\n" +
 	"\n" +
 	"Statistics\x12)\n" +
 	"\x05usage\x18\x01 \x01(\v2\x13.neurouter.v1.UsageR\x05usage\"\xc7\x01\n" +
@@ -759,7 +907,14 @@ const file_neurouter_v1_common_proto_rawDesc = "" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12:\n" +
 	"\finput_schema\x18\x03 \x01(\v2\x17.google.protobuf.StructR\vinputSchemaB\x06\n" +
-	"\x04tool*\xf9\x01\n" +
+	"\x04tool\"\xc4\x01\n" +
+	"\n" +
+	"ToolChoice\x120\n" +
+	"\x04mode\x18\x01 \x01(\x0e2\x1c.neurouter.v1.ToolChoiceModeR\x04mode\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x123\n" +
+	"\x13parallel_tool_calls\x18\x03 \x01(\bH\x00R\x11parallelToolCalls\x88\x01\x01\x12#\n" +
+	"\rallowed_names\x18\x04 \x03(\tR\fallowedNamesB\x16\n" +
+	"\x14_parallel_tool_calls*\xf9\x01\n" +
 	"\x0fReasoningEffort\x12 \n" +
 	"\x1cREASONING_EFFORT_UNSPECIFIED\x10\x00\x12\x19\n" +
 	"\x15REASONING_EFFORT_NONE\x10\x01\x12\x1c\n" +
@@ -774,14 +929,21 @@ const file_neurouter_v1_common_proto_rawDesc = "" +
 	"\rMODALITY_TEXT\x10\x01\x12\x12\n" +
 	"\x0eMODALITY_IMAGE\x10\x02\x12\x12\n" +
 	"\x0eMODALITY_AUDIO\x10\x03\x12\x12\n" +
-	"\x0eMODALITY_VIDEO\x10\x04*\x8b\x01\n" +
+	"\x0eMODALITY_VIDEO\x10\x04*\xad\x01\n" +
 	"\n" +
 	"Capability\x12\x1a\n" +
 	"\x16CAPABILITY_UNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fCAPABILITY_CHAT\x10\x01\x12\x19\n" +
 	"\x15CAPABILITY_COMPLETION\x10\x02\x12\x18\n" +
 	"\x14CAPABILITY_EMBEDDING\x10\x03\x12\x17\n" +
-	"\x13CAPABILITY_TOOL_USE\x10\x04B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
+	"\x13CAPABILITY_TOOL_USE\x10\x04\x12 \n" +
+	"\x1cCAPABILITY_STRUCTURED_OUTPUT\x10\x05*\xa2\x01\n" +
+	"\x0eToolChoiceMode\x12 \n" +
+	"\x1cTOOL_CHOICE_MODE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15TOOL_CHOICE_MODE_AUTO\x10\x01\x12\x19\n" +
+	"\x15TOOL_CHOICE_MODE_NONE\x10\x02\x12\x1d\n" +
+	"\x19TOOL_CHOICE_MODE_REQUIRED\x10\x03\x12\x19\n" +
+	"\x15TOOL_CHOICE_MODE_TOOL\x10\x04B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
 
 var (
 	file_neurouter_v1_common_proto_rawDescOnce sync.Once
@@ -795,32 +957,35 @@ func file_neurouter_v1_common_proto_rawDescGZIP() []byte {
 	return file_neurouter_v1_common_proto_rawDescData
 }
 
-var file_neurouter_v1_common_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_neurouter_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_neurouter_v1_common_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_neurouter_v1_common_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_neurouter_v1_common_proto_goTypes = []any{
 	(ReasoningEffort)(0),     // 0: neurouter.v1.ReasoningEffort
 	(Modality)(0),            // 1: neurouter.v1.Modality
 	(Capability)(0),          // 2: neurouter.v1.Capability
-	(*ReasoningConfig)(nil),  // 3: neurouter.v1.ReasoningConfig
-	(*GenerationConfig)(nil), // 4: neurouter.v1.GenerationConfig
-	(*Usage)(nil),            // 5: neurouter.v1.Usage
-	(*Statistics)(nil),       // 6: neurouter.v1.Statistics
-	(*Tool)(nil),             // 7: neurouter.v1.Tool
-	(*Tool_Function)(nil),    // 8: neurouter.v1.Tool.Function
-	(*structpb.Struct)(nil),  // 9: google.protobuf.Struct
+	(ToolChoiceMode)(0),      // 3: neurouter.v1.ToolChoiceMode
+	(*ReasoningConfig)(nil),  // 4: neurouter.v1.ReasoningConfig
+	(*GenerationConfig)(nil), // 5: neurouter.v1.GenerationConfig
+	(*Usage)(nil),            // 6: neurouter.v1.Usage
+	(*Statistics)(nil),       // 7: neurouter.v1.Statistics
+	(*Tool)(nil),             // 8: neurouter.v1.Tool
+	(*ToolChoice)(nil),       // 9: neurouter.v1.ToolChoice
+	(*Tool_Function)(nil),    // 10: neurouter.v1.Tool.Function
+	(*structpb.Struct)(nil),  // 11: google.protobuf.Struct
 }
 var file_neurouter_v1_common_proto_depIdxs = []int32{
-	0, // 0: neurouter.v1.ReasoningConfig.effort:type_name -> neurouter.v1.ReasoningEffort
-	3, // 1: neurouter.v1.GenerationConfig.reasoning_config:type_name -> neurouter.v1.ReasoningConfig
-	9, // 2: neurouter.v1.GenerationConfig.schema:type_name -> google.protobuf.Struct
-	5, // 3: neurouter.v1.Statistics.usage:type_name -> neurouter.v1.Usage
-	8, // 4: neurouter.v1.Tool.function:type_name -> neurouter.v1.Tool.Function
-	9, // 5: neurouter.v1.Tool.Function.input_schema:type_name -> google.protobuf.Struct
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: neurouter.v1.ReasoningConfig.effort:type_name -> neurouter.v1.ReasoningEffort
+	4,  // 1: neurouter.v1.GenerationConfig.reasoning_config:type_name -> neurouter.v1.ReasoningConfig
+	11, // 2: neurouter.v1.GenerationConfig.schema:type_name -> google.protobuf.Struct
+	6,  // 3: neurouter.v1.Statistics.usage:type_name -> neurouter.v1.Usage
+	10, // 4: neurouter.v1.Tool.function:type_name -> neurouter.v1.Tool.Function
+	3,  // 5: neurouter.v1.ToolChoice.mode:type_name -> neurouter.v1.ToolChoiceMode
+	11, // 6: neurouter.v1.Tool.Function.input_schema:type_name -> google.protobuf.Struct
+	7,  // [7:7] is the sub-list for method output_type
+	7,  // [7:7] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_neurouter_v1_common_proto_init() }
@@ -837,13 +1002,14 @@ func file_neurouter_v1_common_proto_init() {
 	file_neurouter_v1_common_proto_msgTypes[4].OneofWrappers = []any{
 		(*Tool_Function_)(nil),
 	}
+	file_neurouter_v1_common_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_neurouter_v1_common_proto_rawDesc), len(file_neurouter_v1_common_proto_rawDesc)),
-			NumEnums:      3,
-			NumMessages:   6,
+			NumEnums:      4,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -21,6 +21,7 @@
 package v1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -44,7 +45,9 @@ type CompleteRequest struct {
 	// The generation configuration
 	Config *GenerationConfig `protobuf:"bytes,3,opt,name=config,proto3" json:"config,omitempty"`
 	// The multi-modality content
-	Contents      []*Content `protobuf:"bytes,4,rep,name=contents,proto3" json:"contents,omitempty"`
+	Contents []*Content `protobuf:"bytes,4,rep,name=contents,proto3" json:"contents,omitempty"`
+	// The content following the completion, for fill-in-the-middle
+	Suffix        []*Content `protobuf:"bytes,5,rep,name=suffix,proto3" json:"suffix,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -107,6 +110,15 @@ func (x *CompleteRequest) GetContents() []*Content {
 	return nil
 }
 
+func (x *CompleteRequest) GetSuffix() []*Content {
+	if x != nil {
+		return x.Suffix
+	}
+	return nil
+}
+
+// In streams, each response carries the contents generated since the previous
+// one, and the last one carries the status and statistics.
 type CompleteResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The unique identifier of the request
@@ -116,7 +128,9 @@ type CompleteResponse struct {
 	// The multi-modality content
 	Contents []*Content `protobuf:"bytes,3,rep,name=contents,proto3" json:"contents,omitempty"`
 	// The statistics for the generation
-	Statistics    *Statistics `protobuf:"bytes,4,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	Statistics *Statistics `protobuf:"bytes,4,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	// The final status of the generation
+	Status        ChatStatus `protobuf:"varint,5,opt,name=status,proto3,enum=neurouter.v1.ChatStatus" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -179,26 +193,35 @@ func (x *CompleteResponse) GetStatistics() *Statistics {
 	return nil
 }
 
+func (x *CompleteResponse) GetStatus() ChatStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ChatStatus_CHAT_STATUS_UNSPECIFIED
+}
+
 var File_neurouter_v1_completion_proto protoreflect.FileDescriptor
 
 const file_neurouter_v1_completion_proto_rawDesc = "" +
 	"\n" +
-	"\x1dneurouter/v1/completion.proto\x12\fneurouter.v1\x1a\x19neurouter/v1/common.proto\x1a\x1aneurouter/v1/content.proto\"\xa2\x01\n" +
+	"\x1dneurouter/v1/completion.proto\x12\fneurouter.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x17neurouter/v1/chat.proto\x1a\x19neurouter/v1/common.proto\x1a\x1aneurouter/v1/content.proto\"\xd1\x01\n" +
 	"\x0fCompleteRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05model\x18\x02 \x01(\tR\x05model\x126\n" +
 	"\x06config\x18\x03 \x01(\v2\x1e.neurouter.v1.GenerationConfigR\x06config\x121\n" +
-	"\bcontents\x18\x04 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\"\xa5\x01\n" +
+	"\bcontents\x18\x04 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\x12-\n" +
+	"\x06suffix\x18\x05 \x03(\v2\x15.neurouter.v1.ContentR\x06suffix\"\xd7\x01\n" +
 	"\x10CompleteResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05model\x18\x02 \x01(\tR\x05model\x121\n" +
 	"\bcontents\x18\x03 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\x128\n" +
 	"\n" +
 	"statistics\x18\x04 \x01(\v2\x18.neurouter.v1.StatisticsR\n" +
-	"statistics2\xae\x01\n" +
+	"statistics\x120\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x18.neurouter.v1.ChatStatusR\x06status2\xc5\x01\n" +
 	"\n" +
-	"Completion\x12K\n" +
-	"\bComplete\x12\x1d.neurouter.v1.CompleteRequest\x1a\x1e.neurouter.v1.CompleteResponse\"\x00\x12S\n" +
+	"Completion\x12b\n" +
+	"\bComplete\x12\x1d.neurouter.v1.CompleteRequest\x1a\x1e.neurouter.v1.CompleteResponse\"\x17\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/complete\x12S\n" +
 	"\x0eCompleteStream\x12\x1d.neurouter.v1.CompleteRequest\x1a\x1e.neurouter.v1.CompleteResponse\"\x000\x01B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
 
 var (
@@ -220,21 +243,24 @@ var file_neurouter_v1_completion_proto_goTypes = []any{
 	(*GenerationConfig)(nil), // 2: neurouter.v1.GenerationConfig
 	(*Content)(nil),          // 3: neurouter.v1.Content
 	(*Statistics)(nil),       // 4: neurouter.v1.Statistics
+	(ChatStatus)(0),          // 5: neurouter.v1.ChatStatus
 }
 var file_neurouter_v1_completion_proto_depIdxs = []int32{
 	2, // 0: neurouter.v1.CompleteRequest.config:type_name -> neurouter.v1.GenerationConfig
 	3, // 1: neurouter.v1.CompleteRequest.contents:type_name -> neurouter.v1.Content
-	3, // 2: neurouter.v1.CompleteResponse.contents:type_name -> neurouter.v1.Content
-	4, // 3: neurouter.v1.CompleteResponse.statistics:type_name -> neurouter.v1.Statistics
-	0, // 4: neurouter.v1.Completion.Complete:input_type -> neurouter.v1.CompleteRequest
-	0, // 5: neurouter.v1.Completion.CompleteStream:input_type -> neurouter.v1.CompleteRequest
-	1, // 6: neurouter.v1.Completion.Complete:output_type -> neurouter.v1.CompleteResponse
-	1, // 7: neurouter.v1.Completion.CompleteStream:output_type -> neurouter.v1.CompleteResponse
-	6, // [6:8] is the sub-list for method output_type
-	4, // [4:6] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	3, // 2: neurouter.v1.CompleteRequest.suffix:type_name -> neurouter.v1.Content
+	3, // 3: neurouter.v1.CompleteResponse.contents:type_name -> neurouter.v1.Content
+	4, // 4: neurouter.v1.CompleteResponse.statistics:type_name -> neurouter.v1.Statistics
+	5, // 5: neurouter.v1.CompleteResponse.status:type_name -> neurouter.v1.ChatStatus
+	0, // 6: neurouter.v1.Completion.Complete:input_type -> neurouter.v1.CompleteRequest
+	0, // 7: neurouter.v1.Completion.CompleteStream:input_type -> neurouter.v1.CompleteRequest
+	1, // 8: neurouter.v1.Completion.Complete:output_type -> neurouter.v1.CompleteResponse
+	1, // 9: neurouter.v1.Completion.CompleteStream:output_type -> neurouter.v1.CompleteResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_neurouter_v1_completion_proto_init() }
@@ -242,6 +268,7 @@ func file_neurouter_v1_completion_proto_init() {
 	if File_neurouter_v1_completion_proto != nil {
 		return
 	}
+	file_neurouter_v1_chat_proto_init()
 	file_neurouter_v1_common_proto_init()
 	file_neurouter_v1_content_proto_init()
 	type x struct{}
@@ -0,0 +1,77 @@
+// Code generated by protoc-gen-go-http. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-http v2.9.2
+// - protoc             (unknown)
+// source: neurouter/v1/completion.proto
+
+package v1
+
+import (
+	context "context"
+	http "github.com/go-kratos/kratos/v3/transport/http"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the kratos package it is being compiled against.
+var _ = new(context.Context)
+
+const _ = http.SupportPackageIsVersion3
+
+const OperationCompletionComplete = "/neurouter.v1.Completion/Complete"
+
+type CompletionHTTPServer interface {
+	Complete(context.Context, *CompleteRequest) (*CompleteResponse, error)
+}
+
+func RegisterCompletionHTTPServer(s *http.Server, srv CompletionHTTPServer) {
+	r := s.Route("/")
+	r.Handle("POST", "/v1/complete", _Completion_Complete0_HTTP_Handler(srv))
+}
+
+func _Completion_Complete0_HTTP_Handler(srv CompletionHTTPServer) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		var in CompleteRequest
+		if err := ctx.Bind(&in); err != nil {
+			return err
+		}
+		http.SetOperation(ctx, OperationCompletionComplete)
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.Complete(ctx, req.(*CompleteRequest))
+		})
+		out, err := h(ctx, &in)
+		if err != nil {
+			return err
+		}
+		reply := out.(*CompleteResponse)
+		return ctx.Result(200, reply)
+	}
+}
+
+type CompletionHTTPClient interface {
+	Complete(ctx context.Context, req *CompleteRequest, opts ...http.CallOption) (rsp *CompleteResponse, err error)
+}
+
+type CompletionHTTPClientImpl struct {
+	cc *http.Client
+}
+
+func NewCompletionHTTPClient(client *http.Client) CompletionHTTPClient {
+	return &CompletionHTTPClientImpl{client}
+}
+
+func (c *CompletionHTTPClientImpl) Complete(ctx context.Context, in *CompleteRequest, opts ...http.CallOption) (*CompleteResponse, error) {
+	var out CompleteResponse
+	pattern := "/v1/complete"
+	path := http.BuildPath(pattern, in)
+	opts = append([]http.CallOption{
+		http.Accept("application/protojson"),
+		http.ContentType("application/protojson"),
+		http.Operation(OperationCompletionComplete),
+		http.PathTemplate(pattern),
+	}, opts...)
+	err := c.cc.Invoke(ctx, "POST", path, in, &out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
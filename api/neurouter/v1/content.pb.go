@@ -89,6 +89,65 @@ func (ContentPhase) EnumDescriptor() ([]byte, []int) {
 	return file_neurouter_v1_content_proto_rawDescGZIP(), []int{0}
 }
 
+// The provider family that produced a signature or an opaque content. Only the
+// producing family can verify them, so upstreams of other families drop or
+// degrade them when the history is replayed.
+type Provenance int32
+
+const (
+	// Unknown provenance. Signatures and opaque contents are replayed as is.
+	Provenance_PROVENANCE_UNSPECIFIED Provenance = 0
+	// Anthropic Messages API.
+	Provenance_PROVENANCE_ANTHROPIC Provenance = 1
+	// Google Gemini API.
+	Provenance_PROVENANCE_GOOGLE Provenance = 2
+	// OpenAI Responses API.
+	Provenance_PROVENANCE_OPENAI Provenance = 3
+)
+
+// Enum value maps for Provenance.
+var (
+	Provenance_name = map[int32]string{
+		0: "PROVENANCE_UNSPECIFIED",
+		1: "PROVENANCE_ANTHROPIC",
+		2: "PROVENANCE_GOOGLE",
+		3: "PROVENANCE_OPENAI",
+	}
+	Provenance_value = map[string]int32{
+		"PROVENANCE_UNSPECIFIED": 0,
+		"PROVENANCE_ANTHROPIC":   1,
+		"PROVENANCE_GOOGLE":      2,
+		"PROVENANCE_OPENAI":      3,
+	}
+)
+
+func (x Provenance) Enum() *Provenance {
+	p := new(Provenance)
+	*p = x
+	return p
+}
+
+func (x Provenance) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Provenance) Descriptor() protoreflect.EnumDescriptor {
+	return file_neurouter_v1_content_proto_enumTypes[1].Descriptor()
+}
+
+func (Provenance) Type() protoreflect.EnumType {
+	return &file_neurouter_v1_content_proto_enumTypes[1]
+}
+
+func (x Provenance) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Provenance.Descriptor instead.
+func (Provenance) EnumDescriptor() ([]byte, []int) {
+	return file_neurouter_v1_content_proto_rawDescGZIP(), []int{1}
+}
+
 // Represent a text content
 type Text struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -372,6 +431,9 @@ type Content struct {
 	Phase ContentPhase `protobuf:"varint,3,opt,name=phase,proto3,enum=neurouter.v1.ContentPhase" json:"phase,omitempty"`
 	// Provider-supplied verification signature for this content.
 	Signature string `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// The provider family that produced the content, recorded by upstreams for
+	// contents that may carry a signature or an opaque payload.
+	Provenance Provenance `protobuf:"varint,5,opt,name=provenance,proto3,enum=neurouter.v1.Provenance" json:"provenance,omitempty"`
 	// Additional metadata for the content
 	Metadata map[string]string `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	// Types that are valid to be assigned to Content:
@@ -444,6 +506,13 @@ func (x *Content) GetSignature() string {
 	return ""
 }
 
+func (x *Content) GetProvenance() Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return Provenance_PROVENANCE_UNSPECIFIED
+}
+
 func (x *Content) GetMetadata() map[string]string {
 	if x != nil {
 		return x.Metadata
@@ -736,12 +805,15 @@ const file_neurouter_v1_content_proto_rawDesc = "" +
 	" \x01(\tH\x00R\x04text\x12+\n" +
 	"\x05image\x18\v \x01(\v2\x13.neurouter.v1.ImageH\x00R\x05imageB\b\n" +
 	"\x06outputB\b\n" +
-	"\x06_index\"\xf9\x03\n" +
+	"\x06_index\"\xb3\x04\n" +
 	"\aContent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\x05index\x18\x02 \x01(\rH\x01R\x05index\x88\x01\x01\x120\n" +
 	"\x05phase\x18\x03 \x01(\x0e2\x1a.neurouter.v1.ContentPhaseR\x05phase\x12\x1c\n" +
-	"\tsignature\x18\x04 \x01(\tR\tsignature\x12?\n" +
+	"\tsignature\x18\x04 \x01(\tR\tsignature\x128\n" +
+	"\n" +
+	"provenance\x18\x05 \x01(\x0e2\x18.neurouter.v1.ProvenanceR\n" +
+	"provenance\x12?\n" +
 	"\bmetadata\x18\t \x03(\v2#.neurouter.v1.Content.MetadataEntryR\bmetadata\x12(\n" +
 	"\x04text\x18\n" +
 	" \x01(\v2\x12.neurouter.v1.TextH\x00R\x04text\x12+\n" +
@@ -758,7 +830,13 @@ const file_neurouter_v1_content_proto_rawDesc = "" +
 	"\fContentPhase\x12\x18\n" +
 	"\x14CONTENT_PHASE_NORMAL\x10\x00\x12\x19\n" +
 	"\x15CONTENT_PHASE_OUTCOME\x10\x01\x12\x1b\n" +
-	"\x17CONTENT_PHASE_REASONING\x10\x02B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
+	"\x17CONTENT_PHASE_REASONING\x10\x02*p\n" +
+	"\n" +
+	"Provenance\x12\x1a\n" +
+	"\x16PROVENANCE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14PROVENANCE_ANTHROPIC\x10\x01\x12\x15\n" +
+	"\x11PROVENANCE_GOOGLE\x10\x02\x12\x15\n" +
+	"\x11PROVENANCE_OPENAI\x10\x03B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
 
 var (
 	file_neurouter_v1_content_proto_rawDescOnce sync.Once
@@ -772,34 +850,36 @@ func file_neurouter_v1_content_proto_rawDescGZIP() []byte {
 	return file_neurouter_v1_content_proto_rawDescData
 }
 
-var file_neurouter_v1_content_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_neurouter_v1_content_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
 var file_neurouter_v1_content_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_neurouter_v1_content_proto_goTypes = []any{
 	(ContentPhase)(0),         // 0: neurouter.v1.ContentPhase
-	(*Text)(nil),              // 1: neurouter.v1.Text
-	(*Image)(nil),             // 2: neurouter.v1.Image
-	(*ToolUse)(nil),           // 3: neurouter.v1.ToolUse
-	(*ToolResult)(nil),        // 4: neurouter.v1.ToolResult
-	(*Content)(nil),           // 5: neurouter.v1.Content
-	(*ToolUse_Input)(nil),     // 6: neurouter.v1.ToolUse.Input
-	(*ToolResult_Output)(nil), // 7: neurouter.v1.ToolResult.Output
-	nil,                       // 8: neurouter.v1.Content.MetadataEntry
+	(Provenance)(0),           // 1: neurouter.v1.Provenance
+	(*Text)(nil),              // 2: neurouter.v1.Text
+	(*Image)(nil),             // 3: neurouter.v1.Image
+	(*ToolUse)(nil),           // 4: neurouter.v1.ToolUse
+	(*ToolResult)(nil),        // 5: neurouter.v1.ToolResult
+	(*Content)(nil),           // 6: neurouter.v1.Content
+	(*ToolUse_Input)(nil),     // 7: neurouter.v1.ToolUse.Input
+	(*ToolResult_Output)(nil), // 8: neurouter.v1.ToolResult.Output
+	nil,                       // 9: neurouter.v1.Content.MetadataEntry
 }
 var file_neurouter_v1_content_proto_depIdxs = []int32{
-	6, // 0: neurouter.v1.ToolUse.inputs:type_name -> neurouter.v1.ToolUse.Input
-	7, // 1: neurouter.v1.ToolResult.outputs:type_name -> neurouter.v1.ToolResult.Output
-	0, // 2: neurouter.v1.Content.phase:type_name -> neurouter.v1.ContentPhase
-	8, // 3: neurouter.v1.Content.metadata:type_name -> neurouter.v1.Content.MetadataEntry
-	1, // 4: neurouter.v1.Content.text:type_name -> neurouter.v1.Text
-	2, // 5: neurouter.v1.Content.image:type_name -> neurouter.v1.Image
-	3, // 6: neurouter.v1.Content.tool_use:type_name -> neurouter.v1.ToolUse
-	4, // 7: neurouter.v1.Content.tool_result:type_name -> neurouter.v1.ToolResult
-	2, // 8: neurouter.v1.ToolResult.Output.image:type_name -> neurouter.v1.Image
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	7,  // 0: neurouter.v1.ToolUse.inputs:type_name -> neurouter.v1.ToolUse.Input
+	8,  // 1: neurouter.v1.ToolResult.outputs:type_name -> neurouter.v1.ToolResult.Output
+	0,  // 2: neurouter.v1.Content.phase:type_name -> neurouter.v1.ContentPhase
+	1,  // 3: neurouter.v1.Content.provenance:type_name -> neurouter.v1.Provenance
+	9,  // 4: neurouter.v1.Content.metadata:type_name -> neurouter.v1.Content.MetadataEntry
+	2,  // 5: neurouter.v1.Content.text:type_name -> neurouter.v1.Text
+	3,  // 6: neurouter.v1.Content.image:type_name -> neurouter.v1.Image
+	4,  // 7: neurouter.v1.Content.tool_use:type_name -> neurouter.v1.ToolUse
+	5,  // 8: neurouter.v1.Content.tool_result:type_name -> neurouter.v1.ToolResult
+	3,  // 9: neurouter.v1.ToolResult.Output.image:type_name -> neurouter.v1.Image
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_neurouter_v1_content_proto_init() }
@@ -831,7 +911,7 @@ func file_neurouter_v1_content_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_neurouter_v1_content_proto_rawDesc), len(file_neurouter_v1_content_proto_rawDesc)),
-			NumEnums:      1,
+			NumEnums:      2,
 			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
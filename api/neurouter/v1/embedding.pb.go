@@ -36,11 +36,72 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type EmbedTaskType int32
+
+const (
+	EmbedTaskType_EMBED_TASK_TYPE_UNSPECIFIED EmbedTaskType = 0
+	// The input is a search query.
+	EmbedTaskType_EMBED_TASK_TYPE_QUERY EmbedTaskType = 1
+	// The input is a document to be searched.
+	EmbedTaskType_EMBED_TASK_TYPE_DOCUMENT EmbedTaskType = 2
+)
+
+// Enum value maps for EmbedTaskType.
+var (
+	EmbedTaskType_name = map[int32]string{
+		0: "EMBED_TASK_TYPE_UNSPECIFIED",
+		1: "EMBED_TASK_TYPE_QUERY",
+		2: "EMBED_TASK_TYPE_DOCUMENT",
+	}
+	EmbedTaskType_value = map[string]int32{
+		"EMBED_TASK_TYPE_UNSPECIFIED": 0,
+		"EMBED_TASK_TYPE_QUERY":       1,
+		"EMBED_TASK_TYPE_DOCUMENT":    2,
+	}
+)
+
+func (x EmbedTaskType) Enum() *EmbedTaskType {
+	p := new(EmbedTaskType)
+	*p = x
+	return p
+}
+
+func (x EmbedTaskType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EmbedTaskType) Descriptor() protoreflect.EnumDescriptor {
+	return file_neurouter_v1_embedding_proto_enumTypes[0].Descriptor()
+}
+
+func (EmbedTaskType) Type() protoreflect.EnumType {
+	return &file_neurouter_v1_embedding_proto_enumTypes[0]
+}
+
+func (x EmbedTaskType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EmbedTaskType.Descriptor instead.
+func (EmbedTaskType) EnumDescriptor() ([]byte, []int) {
+	return file_neurouter_v1_embedding_proto_rawDescGZIP(), []int{0}
+}
+
 type EmbedRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
-	Contents      []*Content             `protobuf:"bytes,3,rep,name=contents,proto3" json:"contents,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Model string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// The contents of a single input. Equivalent to one entry in inputs.
+	Contents []*Content `protobuf:"bytes,3,rep,name=contents,proto3" json:"contents,omitempty"`
+	// The number of dimensions of the output embedding. Longer embeddings are
+	// truncated if the model does not shorten them itself.
+	Dimensions *uint32 `protobuf:"varint,4,opt,name=dimensions,proto3,oneof" json:"dimensions,omitempty"`
+	// The inputs to embed, each producing one embedding.
+	Inputs []*EmbedInput `protobuf:"bytes,5,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// What the embeddings are used for, for models that optimize for it.
+	TaskType EmbedTaskType `protobuf:"varint,6,opt,name=task_type,json=taskType,proto3,enum=neurouter.v1.EmbedTaskType" json:"task_type,omitempty"`
+	// Whether to scale the embeddings to unit length.
+	Normalize     bool `protobuf:"varint,7,opt,name=normalize,proto3" json:"normalize,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -96,18 +157,95 @@ func (x *EmbedRequest) GetContents() []*Content {
 	return nil
 }
 
-type EmbedResponse struct {
+func (x *EmbedRequest) GetDimensions() uint32 {
+	if x != nil && x.Dimensions != nil {
+		return *x.Dimensions
+	}
+	return 0
+}
+
+func (x *EmbedRequest) GetInputs() []*EmbedInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *EmbedRequest) GetTaskType() EmbedTaskType {
+	if x != nil {
+		return x.TaskType
+	}
+	return EmbedTaskType_EMBED_TASK_TYPE_UNSPECIFIED
+}
+
+func (x *EmbedRequest) GetNormalize() bool {
+	if x != nil {
+		return x.Normalize
+	}
+	return false
+}
+
+type EmbedInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Model         string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
-	Embedding     []float32              `protobuf:"fixed32,3,rep,packed,name=embedding,proto3" json:"embedding,omitempty"`
+	Contents      []*Content             `protobuf:"bytes,1,rep,name=contents,proto3" json:"contents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbedInput) Reset() {
+	*x = EmbedInput{}
+	mi := &file_neurouter_v1_embedding_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbedInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbedInput) ProtoMessage() {}
+
+func (x *EmbedInput) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_embedding_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbedInput.ProtoReflect.Descriptor instead.
+func (*EmbedInput) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_embedding_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EmbedInput) GetContents() []*Content {
+	if x != nil {
+		return x.Contents
+	}
+	return nil
+}
+
+type EmbedResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Model string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// The embedding of a single input. Only set when the request has one input.
+	Embedding []float32 `protobuf:"fixed32,3,rep,packed,name=embedding,proto3" json:"embedding,omitempty"`
+	// Token usage reported by the upstream, if any.
+	Usage *Usage `protobuf:"bytes,4,opt,name=usage,proto3" json:"usage,omitempty"`
+	// The embeddings of the inputs, in input order.
+	Embeddings    []*EmbeddingVector `protobuf:"bytes,5,rep,name=embeddings,proto3" json:"embeddings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *EmbedResponse) Reset() {
 	*x = EmbedResponse{}
-	mi := &file_neurouter_v1_embedding_proto_msgTypes[1]
+	mi := &file_neurouter_v1_embedding_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -119,7 +257,7 @@ func (x *EmbedResponse) String() string {
 func (*EmbedResponse) ProtoMessage() {}
 
 func (x *EmbedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_neurouter_v1_embedding_proto_msgTypes[1]
+	mi := &file_neurouter_v1_embedding_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -132,7 +270,7 @@ func (x *EmbedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmbedResponse.ProtoReflect.Descriptor instead.
 func (*EmbedResponse) Descriptor() ([]byte, []int) {
-	return file_neurouter_v1_embedding_proto_rawDescGZIP(), []int{1}
+	return file_neurouter_v1_embedding_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *EmbedResponse) GetId() string {
@@ -156,19 +294,107 @@ func (x *EmbedResponse) GetEmbedding() []float32 {
 	return nil
 }
 
+func (x *EmbedResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *EmbedResponse) GetEmbeddings() []*EmbeddingVector {
+	if x != nil {
+		return x.Embeddings
+	}
+	return nil
+}
+
+type EmbeddingVector struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The index of the input in the request.
+	Index         uint32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Values        []float32 `protobuf:"fixed32,2,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmbeddingVector) Reset() {
+	*x = EmbeddingVector{}
+	mi := &file_neurouter_v1_embedding_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmbeddingVector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmbeddingVector) ProtoMessage() {}
+
+func (x *EmbeddingVector) ProtoReflect() protoreflect.Message {
+	mi := &file_neurouter_v1_embedding_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmbeddingVector.ProtoReflect.Descriptor instead.
+func (*EmbeddingVector) Descriptor() ([]byte, []int) {
+	return file_neurouter_v1_embedding_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EmbeddingVector) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *EmbeddingVector) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
 var File_neurouter_v1_embedding_proto protoreflect.FileDescriptor
 
 const file_neurouter_v1_embedding_proto_rawDesc = "" +
 	"\n" +
-	"\x1cneurouter/v1/embedding.proto\x12\fneurouter.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1aneurouter/v1/content.proto\"g\n" +
+	"\x1cneurouter/v1/embedding.proto\x12\fneurouter.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x19neurouter/v1/common.proto\x1a\x1aneurouter/v1/content.proto\"\xa5\x02\n" +
 	"\fEmbedRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05model\x18\x02 \x01(\tR\x05model\x121\n" +
-	"\bcontents\x18\x03 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\"S\n" +
+	"\bcontents\x18\x03 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\x12#\n" +
+	"\n" +
+	"dimensions\x18\x04 \x01(\rH\x00R\n" +
+	"dimensions\x88\x01\x01\x120\n" +
+	"\x06inputs\x18\x05 \x03(\v2\x18.neurouter.v1.EmbedInputR\x06inputs\x128\n" +
+	"\ttask_type\x18\x06 \x01(\x0e2\x1b.neurouter.v1.EmbedTaskTypeR\btaskType\x12\x1c\n" +
+	"\tnormalize\x18\a \x01(\bR\tnormalizeB\r\n" +
+	"\v_dimensions\"?\n" +
+	"\n" +
+	"EmbedInput\x121\n" +
+	"\bcontents\x18\x01 \x03(\v2\x15.neurouter.v1.ContentR\bcontents\"\xbd\x01\n" +
 	"\rEmbedResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05model\x18\x02 \x01(\tR\x05model\x12\x1c\n" +
-	"\tembedding\x18\x03 \x03(\x02R\tembedding2c\n" +
+	"\tembedding\x18\x03 \x03(\x02R\tembedding\x12)\n" +
+	"\x05usage\x18\x04 \x01(\v2\x13.neurouter.v1.UsageR\x05usage\x12=\n" +
+	"\n" +
+	"embeddings\x18\x05 \x03(\v2\x1d.neurouter.v1.EmbeddingVectorR\n" +
+	"embeddings\"?\n" +
+	"\x0fEmbeddingVector\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\rR\x05index\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\x02R\x06values*i\n" +
+	"\rEmbedTaskType\x12\x1f\n" +
+	"\x1bEMBED_TASK_TYPE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15EMBED_TASK_TYPE_QUERY\x10\x01\x12\x1c\n" +
+	"\x18EMBED_TASK_TYPE_DOCUMENT\x10\x022c\n" +
 	"\tEmbedding\x12V\n" +
 	"\x05Embed\x12\x1a.neurouter.v1.EmbedRequest\x1a\x1b.neurouter.v1.EmbedResponse\"\x14\x82\xd3\xe4\x93\x02\x0e:\x01*\"\t/v1/embedB3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
 
@@ -184,21 +410,31 @@ func file_neurouter_v1_embedding_proto_rawDescGZIP() []byte {
 	return file_neurouter_v1_embedding_proto_rawDescData
 }
 
-var file_neurouter_v1_embedding_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_neurouter_v1_embedding_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_neurouter_v1_embedding_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_neurouter_v1_embedding_proto_goTypes = []any{
-	(*EmbedRequest)(nil),  // 0: neurouter.v1.EmbedRequest
-	(*EmbedResponse)(nil), // 1: neurouter.v1.EmbedResponse
-	(*Content)(nil),       // 2: neurouter.v1.Content
+	(EmbedTaskType)(0),      // 0: neurouter.v1.EmbedTaskType
+	(*EmbedRequest)(nil),    // 1: neurouter.v1.EmbedRequest
+	(*EmbedInput)(nil),      // 2: neurouter.v1.EmbedInput
+	(*EmbedResponse)(nil),   // 3: neurouter.v1.EmbedResponse
+	(*EmbeddingVector)(nil), // 4: neurouter.v1.EmbeddingVector
+	(*Content)(nil),         // 5: neurouter.v1.Content
+	(*Usage)(nil),           // 6: neurouter.v1.Usage
 }
 var file_neurouter_v1_embedding_proto_depIdxs = []int32{
-	2, // 0: neurouter.v1.EmbedRequest.contents:type_name -> neurouter.v1.Content
-	0, // 1: neurouter.v1.Embedding.Embed:input_type -> neurouter.v1.EmbedRequest
-	1, // 2: neurouter.v1.Embedding.Embed:output_type -> neurouter.v1.EmbedResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 0: neurouter.v1.EmbedRequest.contents:type_name -> neurouter.v1.Content
+	2, // 1: neurouter.v1.EmbedRequest.inputs:type_name -> neurouter.v1.EmbedInput
+	0, // 2: neurouter.v1.EmbedRequest.task_type:type_name -> neurouter.v1.EmbedTaskType
+	5, // 3: neurouter.v1.EmbedInput.contents:type_name -> neurouter.v1.Content
+	6, // 4: neurouter.v1.EmbedResponse.usage:type_name -> neurouter.v1.Usage
+	4, // 5: neurouter.v1.EmbedResponse.embeddings:type_name -> neurouter.v1.EmbeddingVector
+	1, // 6: neurouter.v1.Embedding.Embed:input_type -> neurouter.v1.EmbedRequest
+	3, // 7: neurouter.v1.Embedding.Embed:output_type -> neurouter.v1.EmbedResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_neurouter_v1_embedding_proto_init() }
@@ -206,19 +442,22 @@ func file_neurouter_v1_embedding_proto_init() {
 	if File_neurouter_v1_embedding_proto != nil {
 		return
 	}
+	file_neurouter_v1_common_proto_init()
 	file_neurouter_v1_content_proto_init()
+	file_neurouter_v1_embedding_proto_msgTypes[0].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_neurouter_v1_embedding_proto_rawDesc), len(file_neurouter_v1_embedding_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_neurouter_v1_embedding_proto_goTypes,
 		DependencyIndexes: file_neurouter_v1_embedding_proto_depIdxs,
+		EnumInfos:         file_neurouter_v1_embedding_proto_enumTypes,
 		MessageInfos:      file_neurouter_v1_embedding_proto_msgTypes,
 	}.Build()
 	File_neurouter_v1_embedding_proto = out.File
@@ -38,22 +38,46 @@ const (
 type ErrorReason int32
 
 const (
-	ErrorReason_ERROR_REASON_UNSPECIFIED           ErrorReason = 0
-	ErrorReason_ERROR_REASON_NO_UPSTREAM           ErrorReason = 1
-	ErrorReason_ERROR_REASON_TOKEN_QUOTA_EXHAUSTED ErrorReason = 2
+	ErrorReason_ERROR_REASON_UNSPECIFIED               ErrorReason = 0
+	ErrorReason_ERROR_REASON_NO_UPSTREAM               ErrorReason = 1
+	ErrorReason_ERROR_REASON_TOKEN_QUOTA_EXHAUSTED     ErrorReason = 2
+	ErrorReason_ERROR_REASON_CLIENT_QUOTA_EXCEEDED     ErrorReason = 3
+	ErrorReason_ERROR_REASON_LIMITER_NOT_FOUND         ErrorReason = 4
+	ErrorReason_ERROR_REASON_LIMITER_NOT_ADJUSTABLE    ErrorReason = 5
+	ErrorReason_ERROR_REASON_FORBIDDEN                 ErrorReason = 6
+	ErrorReason_ERROR_REASON_RESPONSE_NOT_FOUND        ErrorReason = 7
+	ErrorReason_ERROR_REASON_STRUCTURED_OUTPUT_INVALID ErrorReason = 8
+	ErrorReason_ERROR_REASON_UPSTREAM_UNAUTHORIZED     ErrorReason = 9
+	ErrorReason_ERROR_REASON_UPSTREAM_TIMEOUT          ErrorReason = 10
 )
 
 // Enum value maps for ErrorReason.
 var (
 	ErrorReason_name = map[int32]string{
-		0: "ERROR_REASON_UNSPECIFIED",
-		1: "ERROR_REASON_NO_UPSTREAM",
-		2: "ERROR_REASON_TOKEN_QUOTA_EXHAUSTED",
+		0:  "ERROR_REASON_UNSPECIFIED",
+		1:  "ERROR_REASON_NO_UPSTREAM",
+		2:  "ERROR_REASON_TOKEN_QUOTA_EXHAUSTED",
+		3:  "ERROR_REASON_CLIENT_QUOTA_EXCEEDED",
+		4:  "ERROR_REASON_LIMITER_NOT_FOUND",
+		5:  "ERROR_REASON_LIMITER_NOT_ADJUSTABLE",
+		6:  "ERROR_REASON_FORBIDDEN",
+		7:  "ERROR_REASON_RESPONSE_NOT_FOUND",
+		8:  "ERROR_REASON_STRUCTURED_OUTPUT_INVALID",
+		9:  "ERROR_REASON_UPSTREAM_UNAUTHORIZED",
+		10: "ERROR_REASON_UPSTREAM_TIMEOUT",
 	}
 	ErrorReason_value = map[string]int32{
-		"ERROR_REASON_UNSPECIFIED":           0,
-		"ERROR_REASON_NO_UPSTREAM":           1,
-		"ERROR_REASON_TOKEN_QUOTA_EXHAUSTED": 2,
+		"ERROR_REASON_UNSPECIFIED":               0,
+		"ERROR_REASON_NO_UPSTREAM":               1,
+		"ERROR_REASON_TOKEN_QUOTA_EXHAUSTED":     2,
+		"ERROR_REASON_CLIENT_QUOTA_EXCEEDED":     3,
+		"ERROR_REASON_LIMITER_NOT_FOUND":         4,
+		"ERROR_REASON_LIMITER_NOT_ADJUSTABLE":    5,
+		"ERROR_REASON_FORBIDDEN":                 6,
+		"ERROR_REASON_RESPONSE_NOT_FOUND":        7,
+		"ERROR_REASON_STRUCTURED_OUTPUT_INVALID": 8,
+		"ERROR_REASON_UPSTREAM_UNAUTHORIZED":     9,
+		"ERROR_REASON_UPSTREAM_TIMEOUT":          10,
 	}
 )
 
@@ -88,11 +112,20 @@ var File_neurouter_v1_error_reason_proto protoreflect.FileDescriptor
 
 const file_neurouter_v1_error_reason_proto_rawDesc = "" +
 	"\n" +
-	"\x1fneurouter/v1/error_reason.proto\x12\fneurouter.v1*q\n" +
+	"\x1fneurouter/v1/error_reason.proto\x12\fneurouter.v1*\x9e\x03\n" +
 	"\vErrorReason\x12\x1c\n" +
 	"\x18ERROR_REASON_UNSPECIFIED\x10\x00\x12\x1c\n" +
 	"\x18ERROR_REASON_NO_UPSTREAM\x10\x01\x12&\n" +
-	"\"ERROR_REASON_TOKEN_QUOTA_EXHAUSTED\x10\x02B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
+	"\"ERROR_REASON_TOKEN_QUOTA_EXHAUSTED\x10\x02\x12&\n" +
+	"\"ERROR_REASON_CLIENT_QUOTA_EXCEEDED\x10\x03\x12\"\n" +
+	"\x1eERROR_REASON_LIMITER_NOT_FOUND\x10\x04\x12'\n" +
+	"#ERROR_REASON_LIMITER_NOT_ADJUSTABLE\x10\x05\x12\x1a\n" +
+	"\x16ERROR_REASON_FORBIDDEN\x10\x06\x12#\n" +
+	"\x1fERROR_REASON_RESPONSE_NOT_FOUND\x10\a\x12*\n" +
+	"&ERROR_REASON_STRUCTURED_OUTPUT_INVALID\x10\b\x12&\n" +
+	"\"ERROR_REASON_UPSTREAM_UNAUTHORIZED\x10\t\x12!\n" +
+	"\x1dERROR_REASON_UPSTREAM_TIMEOUT\x10\n" +
+	"B3Z1github.com/neuraxes/neurouter/api/neurouter/v1;v1b\x06proto3"
 
 var (
 	file_neurouter_v1_error_reason_proto_rawDescOnce sync.Once
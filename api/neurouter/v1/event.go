@@ -72,6 +72,12 @@ func NewIdentifiedContentStartToolUseEvent(itemID string, index uint32, callID,
 	}
 }
 
+// WithProvenance records the provider family that produces the content.
+func (x *ChatEvent_ContentStart) WithProvenance(provenance Provenance) *ChatEvent_ContentStart {
+	x.ContentStart.Provenance = provenance
+	return x
+}
+
 // NewContentDeltaTextEvent carries a text or reasoning fragment.
 func NewContentDeltaTextEvent(index uint32, text string) *ChatEvent_ContentDelta {
 	return &ChatEvent_ContentDelta{
@@ -5,6 +5,14 @@ func (x *Content) IsReasoning() bool {
 	return x.GetPhase() == ContentPhase_CONTENT_PHASE_REASONING
 }
 
+// VerifiableBy reports whether the signature and the opaque content can be
+// replayed to an upstream of the given provider family. Contents of unknown
+// provenance are assumed verifiable.
+func (x *Content) VerifiableBy(provenance Provenance) bool {
+	p := x.GetProvenance()
+	return p == Provenance_PROVENANCE_UNSPECIFIED || p == provenance
+}
+
 func (x *Content) Meta(key string) string {
 	if x == nil {
 		return ""
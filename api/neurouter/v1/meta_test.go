@@ -6,6 +6,26 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 )
 
+func TestContent_VerifiableBy(t *testing.T) {
+	Convey("Content VerifiableBy", t, func() {
+		Convey("should accept contents of the same provenance", func() {
+			content := &Content{Provenance: Provenance_PROVENANCE_ANTHROPIC}
+			So(content.VerifiableBy(Provenance_PROVENANCE_ANTHROPIC), ShouldBeTrue)
+		})
+
+		Convey("should reject contents of another provenance", func() {
+			content := &Content{Provenance: Provenance_PROVENANCE_OPENAI}
+			So(content.VerifiableBy(Provenance_PROVENANCE_GOOGLE), ShouldBeFalse)
+		})
+
+		Convey("should accept contents of unknown provenance", func() {
+			So((&Content{}).VerifiableBy(Provenance_PROVENANCE_GOOGLE), ShouldBeTrue)
+			var content *Content
+			So(content.VerifiableBy(Provenance_PROVENANCE_OPENAI), ShouldBeTrue)
+		})
+	})
+}
+
 func TestContent_Meta(t *testing.T) {
 	Convey("Content Meta", t, func() {
 		Convey("should return metadata value when key exists", func() {
@@ -0,0 +1,41 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import "slices"
+
+// AllowedToolNames returns the names of the tools the model may call under the
+// choice, or nil if it may call any. The allowed names only restrict the auto
+// and required modes: no tool is called in the none mode, and the named tool
+// in the tool mode.
+func (x *ToolChoice) AllowedToolNames() []string {
+	switch x.GetMode() {
+	case ToolChoiceMode_TOOL_CHOICE_MODE_NONE, ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		return nil
+	}
+	return x.GetAllowedNames()
+}
+
+// FilterTools returns the tools the model may call under the choice, for
+// upstreams that cannot restrict the tools of a request otherwise.
+func (x *ToolChoice) FilterTools(tools []*Tool) []*Tool {
+	names := x.AllowedToolNames()
+	if len(names) == 0 {
+		return tools
+	}
+	return slices.DeleteFunc(slices.Clone(tools), func(tool *Tool) bool {
+		return !slices.Contains(names, tool.GetFunction().GetName())
+	})
+}
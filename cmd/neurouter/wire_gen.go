@@ -10,14 +10,19 @@ import (
 	"github.com/go-kratos/kratos/v3"
 	"github.com/go-kratos/kratos/v3/config"
 	"github.com/neuraxes/neurouter/internal/biz/chat"
+	"github.com/neuraxes/neurouter/internal/biz/completion"
 	"github.com/neuraxes/neurouter/internal/biz/embedding"
 	"github.com/neuraxes/neurouter/internal/biz/model"
+	"github.com/neuraxes/neurouter/internal/biz/response"
 	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/data/responsestore"
 	"github.com/neuraxes/neurouter/internal/data/telemetry"
 	"github.com/neuraxes/neurouter/internal/data/upstream/anthropic"
 	"github.com/neuraxes/neurouter/internal/data/upstream/google"
 	"github.com/neuraxes/neurouter/internal/data/upstream/neurouter"
+	"github.com/neuraxes/neurouter/internal/data/upstream/ollama"
 	"github.com/neuraxes/neurouter/internal/data/upstream/openai"
+	"github.com/neuraxes/neurouter/internal/data/upstream/shared"
 	"github.com/neuraxes/neurouter/internal/server"
 	"github.com/neuraxes/neurouter/internal/service"
 	"log/slog"
@@ -35,21 +40,34 @@ func wireApp(configConfig config.Config, confServer *conf.Server, data *conf.Dat
 	if err != nil {
 		return nil, nil, err
 	}
+	transportFactory := shared.NewTransportFactory()
 	upstreamFactory := anthropic.NewAnthropicChatRepoFactory(loggerProvider)
 	repositoryUpstreamFactory := google.NewGoogleFactory(loggerProvider)
 	upstreamFactory2 := neurouter.NewNeurouterFactory()
-	upstreamFactory3 := openai.NewOpenAIFactory(loggerProvider)
+	upstreamFactory3 := ollama.NewOllamaFactory(loggerProvider)
+	upstreamFactory4 := openai.NewOpenAIFactory(loggerProvider)
 	meterProvider, cleanup2, err := telemetry.NewMeterProvider()
 	if err != nil {
 		cleanup()
 		return nil, nil, err
 	}
-	useCaseImpl := model.NewModelUseCase(configConfig, upstreamFactory, repositoryUpstreamFactory, upstreamFactory2, upstreamFactory3, meterProvider, logger)
+	useCaseImpl, cleanup3 := model.NewModelUseCase(configConfig, transportFactory, upstreamFactory, repositoryUpstreamFactory, upstreamFactory2, upstreamFactory3, upstreamFactory4, meterProvider, logger)
 	useCase := chat.NewChatUseCase(useCaseImpl, logger)
 	embeddingUseCase := embedding.NewUseCase(useCaseImpl, logger)
-	routerService := service.NewRouterService(useCase, useCaseImpl, embeddingUseCase, logger)
-	tracerProvider, cleanup3, err := telemetry.NewTracerProvider()
+	completionUseCase := completion.NewUseCase(useCaseImpl, logger)
+	responseStoreRepo, cleanup4, err := responsestore.NewResponseStore(data, logger)
 	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	responseUseCase := response.NewUseCase(responseStoreRepo, logger)
+	routerService := service.NewRouterService(useCase, useCaseImpl, embeddingUseCase, completionUseCase, useCaseImpl, responseUseCase, logger)
+	tracerProvider, cleanup5, err := telemetry.NewTracerProvider()
+	if err != nil {
+		cleanup4()
+		cleanup3()
 		cleanup2()
 		cleanup()
 		return nil, nil, err
@@ -59,6 +77,8 @@ func wireApp(configConfig config.Config, confServer *conf.Server, data *conf.Dat
 	httpServer := server.NewHTTPServer(confServer, routerService, grpcWebFilter, loggerProvider, tracerProvider, logger)
 	app := newApp(logger, grpcServer, httpServer)
 	return app, func() {
+		cleanup5()
+		cleanup4()
 		cleanup3()
 		cleanup2()
 		cleanup()
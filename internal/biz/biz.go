@@ -18,15 +18,21 @@ import (
 	"github.com/google/wire"
 
 	"github.com/neuraxes/neurouter/internal/biz/chat"
+	"github.com/neuraxes/neurouter/internal/biz/completion"
 	"github.com/neuraxes/neurouter/internal/biz/embedding"
 	"github.com/neuraxes/neurouter/internal/biz/model"
+	"github.com/neuraxes/neurouter/internal/biz/response"
 )
 
 var ProviderSet = wire.NewSet(
 	chat.NewChatUseCase,
 	model.NewModelUseCase,
 	embedding.NewUseCase,
+	completion.NewUseCase,
+	response.NewUseCase,
 	wire.Bind(new(model.UseCase), new(*model.UseCaseImpl)),
+	wire.Bind(new(model.AdminUseCase), new(*model.UseCaseImpl)),
 	wire.Bind(new(chat.Elector), new(*model.UseCaseImpl)),
 	wire.Bind(new(embedding.Elector), new(*model.UseCaseImpl)),
+	wire.Bind(new(completion.Elector), new(*model.UseCaseImpl)),
 )
@@ -83,10 +83,11 @@ func (r *ChatEventReducer) startContent(start *v1.ContentStart) {
 
 	index := start.GetIndex()
 	content := &v1.Content{
-		Id:       start.GetId(),
-		Index:    new(uint32(index)),
-		Phase:    start.GetPhase(),
-		Metadata: start.GetMetadata(),
+		Id:         start.GetId(),
+		Index:      new(uint32(index)),
+		Phase:      start.GetPhase(),
+		Provenance: start.GetProvenance(),
+		Metadata:   start.GetMetadata(),
 	}
 
 	switch c := start.Content.(type) {
@@ -170,4 +171,7 @@ func (r *ChatEventReducer) reduceUsage(usage *v1.Usage) {
 	if usage.ReasoningTokens != 0 {
 		r.resp.Statistics.Usage.ReasoningTokens = usage.ReasoningTokens
 	}
+	if usage.CacheWriteInputTokens != 0 {
+		r.resp.Statistics.Usage.CacheWriteInputTokens = usage.CacheWriteInputTokens
+	}
 }
@@ -79,6 +79,15 @@ func TestChatEventReducer(t *testing.T) {
 			So(resp.Message.Contents[0].Metadata["provider_item_id"], ShouldEqual, "item-1")
 		})
 
+		Convey("Content start provenance is preserved", func() {
+			r := newTestChatEventReducer()
+			reduceTestChatEvent(r, v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING).
+				WithProvenance(v1.Provenance_PROVENANCE_ANTHROPIC))
+
+			resp := r.Resp()
+			So(resp.Message.Contents[0].Provenance, ShouldEqual, v1.Provenance_PROVENANCE_ANTHROPIC)
+		})
+
 		Convey("Reasoning block with text and signature deltas", func() {
 			r := newTestChatEventReducer()
 			reduceTestChatEvent(r, v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING))
@@ -26,6 +26,7 @@ import (
 type UseCase interface {
 	Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error)
 	ChatStream(ctx context.Context, req *entity.ChatRequest, stream repository.ChatStreamServer) error
+	CountTokens(ctx context.Context, req *entity.ChatRequest) (*entity.CountTokensResponse, error)
 }
 
 type chatUseCase struct {
@@ -40,6 +41,33 @@ func NewChatUseCase(elector Elector, logger *slog.Logger) UseCase {
 	}
 }
 
+// chatRepo returns the repo chatting with the model, emulating tool calls
+// for models configured to, and structured output if the request
+// asks for it and the model does not follow schemas. Structured output may
+// be emulated with a tool, so tool calls are emulated beneath it. Timeouts
+// apply to each request to the upstream.
+func (uc *chatUseCase) chatRepo(model Model, req *entity.ChatRequest) repository.ChatRepo {
+	repo := model.ChatRepo()
+	if t := model.Timeouts(); t != (Timeouts{}) {
+		repo = &timeoutRepo{chat: repo, timeouts: t}
+	}
+	if model.EmulatedToolUse() {
+		repo = &toolUseRepo{chat: repo, log: uc.log}
+	}
+
+	so := model.StructuredOutput()
+	if so.Emulation == StructuredOutputNative || structuredOutputSchema(req) == nil {
+		return repo
+	}
+	return &structuredOutputRepo{
+		chat:        repo,
+		emulation:   so.Emulation,
+		maxRetries:  so.MaxRetries,
+		log:         uc.log,
+		recordUsage: model.RecordUsage,
+	}
+}
+
 func (uc *chatUseCase) Chat(ctx context.Context, req *entity.ChatRequest) (resp *entity.ChatResponse, err error) {
 	model, err := uc.elector.ElectForChat(ctx, req)
 	if err != nil {
@@ -47,7 +75,7 @@ func (uc *chatUseCase) Chat(ctx context.Context, req *entity.ChatRequest) (resp
 	}
 	defer model.Close()
 
-	resp, err = model.ChatRepo().Chat(ctx, req)
+	resp, err = uc.chatRepo(model, req).Chat(ctx, req)
 	if err != nil {
 		return
 	}
@@ -65,7 +93,7 @@ func (uc *chatUseCase) ChatStream(ctx context.Context, req *entity.ChatRequest,
 	defer model.Close()
 
 	reducer := NewChatEventReducer(uc.log)
-	for event, err := range model.ChatRepo().ChatStream(ctx, req) {
+	for event, err := range uc.chatRepo(model, req).ChatStream(ctx, req) {
 		if err != nil {
 			return err
 		}
@@ -86,3 +114,11 @@ func (uc *chatUseCase) ChatStream(ctx context.Context, req *entity.ChatRequest,
 	uc.printChat(req, finalResp)
 	return nil
 }
+
+func (uc *chatUseCase) CountTokens(ctx context.Context, req *entity.ChatRequest) (*entity.CountTokensResponse, error) {
+	counter, err := uc.elector.ElectForTokenCount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return counter.CountTokens(ctx, req)
+}
@@ -23,10 +23,23 @@ import (
 
 type Model interface {
 	ChatRepo() repository.ChatRepo
+	// EmulatedToolUse reports whether the model has tool calls emulated in
+	// its text rather than calling tools natively.
+	EmulatedToolUse() bool
+	// StructuredOutput returns how structured output is served for the model.
+	StructuredOutput() StructuredOutput
+	// Timeouts returns the timeouts of requests to the model.
+	Timeouts() Timeouts
 	RecordUsage(ctx context.Context, stats *v1.Statistics)
 	Close()
 }
 
+// TokenCounter counts the input tokens of chat requests with an elected model.
+type TokenCounter interface {
+	CountTokens(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error)
+}
+
 type Elector interface {
 	ElectForChat(ctx context.Context, req *v1.ChatRequest) (Model, error)
+	ElectForTokenCount(ctx context.Context, req *v1.ChatRequest) (TokenCounter, error)
 }
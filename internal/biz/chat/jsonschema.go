@@ -0,0 +1,347 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// validateSchema reports the first violation of the JSON schema by a value
+// decoded with encoding/json. It covers the keywords structured output
+// schemas are made of; unknown keywords are ignored.
+func validateSchema(schema map[string]any, value any) error {
+	v := &schemaValidator{root: schema}
+	return v.validate("$", schema, value, 0)
+}
+
+// maxSchemaDepth bounds $ref expansion, so that recursive schemas cannot
+// recurse forever on values that never terminate them.
+const maxSchemaDepth = 64
+
+type schemaValidator struct {
+	root map[string]any
+}
+
+func (v *schemaValidator) validate(path string, schema any, value any, depth int) error {
+	if depth > maxSchemaDepth {
+		return fmt.Errorf("%s: schema nests too deeply", path)
+	}
+
+	switch s := schema.(type) {
+	case bool:
+		if !s {
+			return fmt.Errorf("%s: no value is allowed", path)
+		}
+		return nil
+	case map[string]any:
+		return v.validateObject(path, s, value, depth)
+	default:
+		return nil
+	}
+}
+
+func (v *schemaValidator) validateObject(path string, schema map[string]any, value any, depth int) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if err = v.validate(path, target, value, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if t, ok := schema["type"]; ok {
+		if err := validateType(path, t, value); err != nil {
+			return err
+		}
+	}
+	if allowed, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(allowed, func(a any) bool { return reflect.DeepEqual(a, value) }) {
+			return fmt.Errorf("%s: value is not one of the allowed values", path)
+		}
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		return fmt.Errorf("%s: value must be %s", path, encodeValue(c))
+	}
+
+	for _, sub := range asSlice(schema["allOf"]) {
+		if err := v.validate(path, sub, value, depth+1); err != nil {
+			return err
+		}
+	}
+	if subs := asSlice(schema["anyOf"]); len(subs) > 0 {
+		if v.countMatches(path, subs, value, depth) == 0 {
+			return fmt.Errorf("%s: value matches none of anyOf", path)
+		}
+	}
+	if subs := asSlice(schema["oneOf"]); len(subs) > 0 {
+		if n := v.countMatches(path, subs, value, depth); n != 1 {
+			return fmt.Errorf("%s: value matches %d of oneOf instead of exactly one", path, n)
+		}
+	}
+	if not, ok := schema["not"]; ok && v.validate(path, not, value, depth+1) == nil {
+		return fmt.Errorf("%s: value matches the schema of not", path)
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		return v.validateProperties(path, schema, val, depth)
+	case []any:
+		return v.validateItems(path, schema, val, depth)
+	case string:
+		return validateString(path, schema, val)
+	case float64:
+		return validateNumber(path, schema, val)
+	}
+	return nil
+}
+
+func (v *schemaValidator) countMatches(path string, schemas []any, value any, depth int) (n int) {
+	for _, sub := range schemas {
+		if v.validate(path, sub, value, depth+1) == nil {
+			n++
+		}
+	}
+	return
+}
+
+func (v *schemaValidator) validateProperties(path string, schema map[string]any, value map[string]any, depth int) error {
+	for _, name := range asSlice(schema["required"]) {
+		if name, ok := name.(string); ok {
+			if _, ok = value[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	slices.Sort(names) // Report violations deterministically
+
+	for _, name := range names {
+		propertyPath := path + "." + name
+		if sub, ok := properties[name]; ok {
+			if err := v.validate(propertyPath, sub, value[name], depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		if additional, ok := schema["additionalProperties"]; ok {
+			if additional == false {
+				return fmt.Errorf("%s: property %q is not allowed", path, name)
+			}
+			if err := v.validate(propertyPath, additional, value[name], depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if n, ok := asInt(schema["minProperties"]); ok && len(value) < n {
+		return fmt.Errorf("%s: expected at least %d properties", path, n)
+	}
+	if n, ok := asInt(schema["maxProperties"]); ok && len(value) > n {
+		return fmt.Errorf("%s: expected at most %d properties", path, n)
+	}
+	return nil
+}
+
+func (v *schemaValidator) validateItems(path string, schema map[string]any, value []any, depth int) error {
+	prefix := asSlice(schema["prefixItems"])
+	for i, item := range value {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		var sub any
+		if i < len(prefix) {
+			sub = prefix[i]
+		} else if items, ok := schema["items"]; ok {
+			sub = items
+		} else {
+			continue
+		}
+		if err := v.validate(itemPath, sub, item, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if n, ok := asInt(schema["minItems"]); ok && len(value) < n {
+		return fmt.Errorf("%s: expected at least %d items", path, n)
+	}
+	if n, ok := asInt(schema["maxItems"]); ok && len(value) > n {
+		return fmt.Errorf("%s: expected at most %d items", path, n)
+	}
+	if schema["uniqueItems"] == true {
+		for i := range value {
+			for j := range i {
+				if reflect.DeepEqual(value[i], value[j]) {
+					return fmt.Errorf("%s: items %d and %d are equal", path, j, i)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func validateString(path string, schema map[string]any, value string) error {
+	length := utf8.RuneCountInString(value)
+	if n, ok := asInt(schema["minLength"]); ok && length < n {
+		return fmt.Errorf("%s: expected at least %d characters", path, n)
+	}
+	if n, ok := asInt(schema["maxLength"]); ok && length > n {
+		return fmt.Errorf("%s: expected at most %d characters", path, n)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		// Patterns Go cannot compile are left to the model
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(value) {
+			return fmt.Errorf("%s: value does not match pattern %q", path, pattern)
+		}
+	}
+	return nil
+}
+
+func validateNumber(path string, schema map[string]any, value float64) error {
+	if m, ok := schema["minimum"].(float64); ok && value < m {
+		return fmt.Errorf("%s: expected a value of at least %v", path, m)
+	}
+	if m, ok := schema["maximum"].(float64); ok && value > m {
+		return fmt.Errorf("%s: expected a value of at most %v", path, m)
+	}
+	if m, ok := schema["exclusiveMinimum"].(float64); ok && value <= m {
+		return fmt.Errorf("%s: expected a value greater than %v", path, m)
+	}
+	if m, ok := schema["exclusiveMaximum"].(float64); ok && value >= m {
+		return fmt.Errorf("%s: expected a value less than %v", path, m)
+	}
+	if m, ok := schema["multipleOf"].(float64); ok && m > 0 {
+		if q := value / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			return fmt.Errorf("%s: expected a multiple of %v", path, m)
+		}
+	}
+	return nil
+}
+
+func validateType(path string, t any, value any) error {
+	var types []string
+	switch t := t.(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		return nil
+	}
+
+	if slices.ContainsFunc(types, func(t string) bool { return hasType(t, value) }) {
+		return nil
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), typeOf(value))
+}
+
+func hasType(t string, value any) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// resolve looks up a local reference such as "#/$defs/item" in the root schema.
+func (v *schemaValidator) resolve(ref string) (any, error) {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+
+	var node any = v.root
+	for token := range strings.SplitSeq(strings.TrimPrefix(pointer, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+		if node, ok = object[token]; !ok {
+			return nil, fmt.Errorf("unresolvable reference %q", ref)
+		}
+	}
+	return node, nil
+}
+
+func asSlice(value any) []any {
+	s, _ := value.([]any)
+	return s
+}
+
+func asInt(value any) (int, bool) {
+	f, ok := value.(float64)
+	return int(f), ok
+}
+
+func encodeValue(value any) string {
+	b, _ := json.Marshal(value)
+	return string(b)
+}
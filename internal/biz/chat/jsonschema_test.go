@@ -0,0 +1,101 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func validateTestSchema(schema, value string) error {
+	var s map[string]any
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		panic(err)
+	}
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		panic(err)
+	}
+	return validateSchema(s, v)
+}
+
+func TestValidateSchema(t *testing.T) {
+	Convey("Test validateSchema", t, func() {
+		Convey("should check types", func() {
+			So(validateTestSchema(`{"type":"string"}`, `"a"`), ShouldBeNil)
+			So(validateTestSchema(`{"type":"integer"}`, `3`), ShouldBeNil)
+			So(validateTestSchema(`{"type":["string","null"]}`, `null`), ShouldBeNil)
+
+			err := validateTestSchema(`{"type":"integer"}`, `3.5`)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "$: expected integer, got number")
+		})
+
+		Convey("should check properties", func() {
+			schema := `{
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}},
+				"required": ["name"],
+				"additionalProperties": false
+			}`
+			So(validateTestSchema(schema, `{"name":"a","age":3}`), ShouldBeNil)
+
+			err := validateTestSchema(schema, `{"age":3}`)
+			So(err.Error(), ShouldEqual, `$: missing required property "name"`)
+
+			err = validateTestSchema(schema, `{"name":"a","age":-1}`)
+			So(err.Error(), ShouldEqual, "$.age: expected a value of at least 0")
+
+			err = validateTestSchema(schema, `{"name":"a","extra":true}`)
+			So(err.Error(), ShouldEqual, `$: property "extra" is not allowed`)
+		})
+
+		Convey("should check items", func() {
+			schema := `{"type":"array","items":{"enum":["a","b"]},"maxItems":2}`
+			So(validateTestSchema(schema, `["a","b"]`), ShouldBeNil)
+
+			err := validateTestSchema(schema, `["a","c"]`)
+			So(err.Error(), ShouldEqual, "$[1]: value is not one of the allowed values")
+
+			err = validateTestSchema(schema, `["a","b","a"]`)
+			So(err.Error(), ShouldEqual, "$: expected at most 2 items")
+		})
+
+		Convey("should check strings", func() {
+			schema := `{"type":"string","minLength":2,"pattern":"^[a-z]+$"}`
+			So(validateTestSchema(schema, `"ab"`), ShouldBeNil)
+			So(validateTestSchema(schema, `"a"`), ShouldNotBeNil)
+			So(validateTestSchema(schema, `"AB"`), ShouldNotBeNil)
+		})
+
+		Convey("should check combinators", func() {
+			schema := `{"anyOf":[{"type":"string"},{"type":"number"}]}`
+			So(validateTestSchema(schema, `1`), ShouldBeNil)
+			So(validateTestSchema(schema, `true`).Error(), ShouldEqual, "$: value matches none of anyOf")
+
+			schema = `{"oneOf":[{"type":"number"},{"type":"integer"}]}`
+			So(validateTestSchema(schema, `1.5`), ShouldBeNil)
+			So(validateTestSchema(schema, `1`), ShouldNotBeNil)
+
+			schema = `{"allOf":[{"required":["a"]},{"required":["b"]}]}`
+			So(validateTestSchema(schema, `{"a":1,"b":2}`), ShouldBeNil)
+			So(validateTestSchema(schema, `{"a":1}`), ShouldNotBeNil)
+		})
+
+		Convey("should resolve references", func() {
+			schema := `{
+				"$defs": {"node": {"type": "object", "properties": {"next": {"anyOf": [{"$ref": "#/$defs/node"}, {"type": "null"}]}}, "required": ["next"]}},
+				"$ref": "#/$defs/node"
+			}`
+			So(validateTestSchema(schema, `{"next":{"next":null}}`), ShouldBeNil)
+			So(validateTestSchema(schema, `{"next":{}}`), ShouldNotBeNil)
+
+			err := validateTestSchema(`{"$ref":"#/$defs/missing"}`, `1`)
+			So(err.Error(), ShouldEqual, `$: unresolvable reference "#/$defs/missing"`)
+		})
+
+		Convey("should accept anything with an empty schema", func() {
+			So(validateTestSchema(`{}`, `{"a":[1,"b",null]}`), ShouldBeNil)
+		})
+	})
+}
@@ -113,6 +113,7 @@ const chatPrettyPrintTmpl = `
    • Input Tokens: {{.Response.Statistics.Usage.InputTokens}}
    • Output Tokens: {{.Response.Statistics.Usage.OutputTokens}}
    • Cached Input Tokens: {{.Response.Statistics.Usage.CachedInputTokens}}
+   • Cache Write Input Tokens: {{.Response.Statistics.Usage.CacheWriteInputTokens}}
    • Reasoning Tokens: {{.Response.Statistics.Usage.ReasoningTokens}}
 {{- end}}
   </statistics>
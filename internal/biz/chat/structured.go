@@ -0,0 +1,357 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// StructuredOutputEmulation is how structured output is served for a model.
+type StructuredOutputEmulation int
+
+const (
+	// StructuredOutputNative passes the requested format to the upstream.
+	StructuredOutputNative StructuredOutputEmulation = iota
+	// StructuredOutputTool forces a call of a tool whose input is the schema.
+	StructuredOutputTool
+	// StructuredOutputInstruction describes the schema in a system instruction.
+	StructuredOutputInstruction
+)
+
+// StructuredOutput configures the structured output emulation of a model.
+type StructuredOutput struct {
+	Emulation StructuredOutputEmulation
+	// MaxRetries is how many times a reply not matching the schema is retried.
+	MaxRetries int
+}
+
+const (
+	structuredOutputToolName        = "respond"
+	structuredOutputToolDescription = "Respond to the user. The input of this tool is the complete response."
+	structuredOutputInstruction     = "Reply with a single JSON value matching the following JSON schema, " +
+		"without any other text or markdown code fences.\n\n"
+	structuredOutputToolFeedback = "The input does not match the schema: %v. " +
+		"Call the tool again with the corrected input."
+	structuredOutputTextFeedback = "The reply does not match the schema: %v. " +
+		"Reply again with the corrected JSON only."
+)
+
+// structuredOutputSchema returns the schema the request asks replies to
+// match, or nil if it asks for no structured output. JSON object mode asks
+// for any object.
+func structuredOutputSchema(req *entity.ChatRequest) *structpb.Struct {
+	switch g := req.GetConfig().GetGrammar().(type) {
+	case *v1.GenerationConfig_Schema:
+		return g.Schema
+	case *v1.GenerationConfig_PresetGrammar:
+		if g.PresetGrammar == "json_object" {
+			return &structpb.Struct{Fields: map[string]*structpb.Value{
+				"type": structpb.NewStringValue("object"),
+			}}
+		}
+	}
+	return nil
+}
+
+// structuredOutputRepo emulates structured output over a chat model that
+// does not follow schemas natively. Replies are validated against the
+// schema, retried with the violation fed back, and returned as text, the
+// way a native model answers.
+type structuredOutputRepo struct {
+	chat       repository.ChatRepo
+	emulation  StructuredOutputEmulation
+	maxRetries int
+	log        *slog.Logger
+
+	// recordUsage charges the attempts of a request failing after retries,
+	// as no response carries their usage back to the caller.
+	recordUsage func(context.Context, *v1.Statistics)
+}
+
+func (r *structuredOutputRepo) convertRequest(req *entity.ChatRequest, schema *structpb.Struct) *entity.ChatRequest {
+	emulated := proto.Clone(req).(*entity.ChatRequest)
+	emulated.Config.Grammar = nil
+
+	switch r.emulation {
+	case StructuredOutputTool:
+		emulated.Tools = append(emulated.Tools, &v1.Tool{
+			Tool: &v1.Tool_Function_{
+				Function: &v1.Tool_Function{
+					Name:        structuredOutputToolName,
+					Description: structuredOutputToolDescription,
+					InputSchema: schema,
+				},
+			},
+		})
+
+		// Tools of the caller stay callable, so alongside them a tool call is
+		// required instead of the reply tool being forced.
+		choice := emulated.GetToolChoice()
+		switch {
+		case len(req.Tools) == 0 || choice.GetMode() == v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+			emulated.ToolChoice = &v1.ToolChoice{
+				Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL,
+				Name: structuredOutputToolName,
+			}
+		case choice == nil:
+			emulated.ToolChoice = &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED}
+		case choice.Mode != v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+			choice.Mode = v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED
+			if len(choice.AllowedNames) > 0 {
+				choice.AllowedNames = append(choice.AllowedNames, structuredOutputToolName)
+			}
+		}
+	case StructuredOutputInstruction:
+		b, _ := schema.MarshalJSON()
+		emulated.Messages = insertInstruction(emulated.Messages, structuredOutputInstruction+string(b))
+	}
+	return emulated
+}
+
+// insertInstruction adds a system message after the leading system messages,
+// for upstreams that only accept system prompts before the conversation.
+func insertInstruction(messages []*v1.Message, instruction string) []*v1.Message {
+	i := 0
+	for i < len(messages) && messages[i].Role == v1.Role_ROLE_SYSTEM {
+		i++
+	}
+	return slices.Insert(messages, i, &v1.Message{
+		Role:     v1.Role_ROLE_SYSTEM,
+		Contents: []*v1.Content{{Content: v1.NewTextContent(instruction)}},
+	})
+}
+
+// extractOutput finds the structured output in a reply, returning the tool
+// use carrying it in the tool emulation. It reports false for replies that
+// carry no structured output, such as refusals or calls of caller tools.
+func (r *structuredOutputRepo) extractOutput(resp *entity.ChatResponse) (output string, toolUse *v1.ToolUse, ok bool) {
+	switch resp.Status {
+	case v1.ChatStatus_CHAT_STATUS_COMPLETED, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE:
+	default:
+		return "", nil, false
+	}
+
+	var text strings.Builder
+	var callsOtherTools bool
+	for _, c := range resp.GetMessage().GetContents() {
+		if c.IsReasoning() {
+			continue
+		}
+		switch content := c.Content.(type) {
+		case *v1.Content_ToolUse:
+			if r.emulation == StructuredOutputTool && content.ToolUse.Name == structuredOutputToolName {
+				return content.ToolUse.GetTextualInput(), content.ToolUse, true
+			}
+			callsOtherTools = true
+		case *v1.Content_Text:
+			text.WriteString(content.Text.GetText())
+		}
+	}
+	if callsOtherTools {
+		return "", nil, false
+	}
+	return trimCodeFence(text.String()), nil, true
+}
+
+// trimCodeFence strips the markdown code fence models tend to wrap JSON in.
+func trimCodeFence(text string) string {
+	text = strings.TrimSpace(text)
+	if body, ok := strings.CutPrefix(text, "```"); ok {
+		if _, rest, found := strings.Cut(body, "\n"); found {
+			if rest, ok = strings.CutSuffix(strings.TrimSpace(rest), "```"); ok {
+				return strings.TrimSpace(rest)
+			}
+		}
+	}
+	return text
+}
+
+func validateOutput(schema *structpb.Struct, output string) error {
+	var value any
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return validateSchema(schema.AsMap(), value)
+}
+
+// feedback returns the messages telling the model why its reply is rejected.
+func feedback(reply *v1.Message, toolUse *v1.ToolUse, err error) []*v1.Message {
+	if toolUse != nil {
+		return []*v1.Message{reply, {
+			Role: v1.Role_ROLE_USER,
+			Contents: []*v1.Content{{
+				Content: &v1.Content_ToolResult{
+					ToolResult: &v1.ToolResult{
+						Id: toolUse.Id,
+						Outputs: []*v1.ToolResult_Output{{
+							Output: &v1.ToolResult_Output_Text{Text: fmt.Sprintf(structuredOutputToolFeedback, err)},
+						}},
+					},
+				},
+			}},
+		}}
+	}
+	return []*v1.Message{reply, {
+		Role:     v1.Role_ROLE_USER,
+		Contents: []*v1.Content{{Content: v1.NewTextContent(fmt.Sprintf(structuredOutputTextFeedback, err))}},
+	}}
+}
+
+func (r *structuredOutputRepo) Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	schema := structuredOutputSchema(req)
+	emulated := r.convertRequest(req, schema)
+
+	// Every attempt is charged, so usage sums up over retries
+	usage := &v1.Usage{}
+	for attempt := 0; ; attempt++ {
+		resp, err := r.chat.Chat(ctx, emulated)
+		if err != nil {
+			if attempt > 0 {
+				r.recordUsage(ctx, &v1.Statistics{Usage: usage})
+			}
+			return nil, err
+		}
+		addUsage(usage, resp.GetStatistics().GetUsage())
+		resp.Statistics = &v1.Statistics{Usage: proto.Clone(usage).(*v1.Usage)}
+
+		output, toolUse, ok := r.extractOutput(resp)
+		if !ok {
+			return resp, nil
+		}
+
+		err = validateOutput(schema, output)
+		if err == nil {
+			replaceOutput(resp, output)
+			return resp, nil
+		}
+		if attempt >= r.maxRetries {
+			r.recordUsage(ctx, resp.Statistics)
+			return nil, entity.ErrStructuredOutputInvalid.WithCause(err)
+		}
+
+		r.log.WarnContext(
+			ctx,
+			"structured output does not match the schema, retrying",
+			"model", resp.Model,
+			"attempt", attempt+1,
+			"error", err,
+		)
+		emulated.Messages = append(emulated.Messages, feedback(resp.Message, toolUse, err)...)
+	}
+}
+
+// replaceOutput turns the reply into the answer of a native model: the
+// reasoning followed by the output as text.
+func replaceOutput(resp *entity.ChatResponse, output string) {
+	var contents []*v1.Content
+	for _, c := range resp.GetMessage().GetContents() {
+		if c.IsReasoning() {
+			contents = append(contents, c)
+		}
+	}
+	contents = append(contents, &v1.Content{Content: v1.NewTextContent(output)})
+
+	for i, c := range contents {
+		c.Index = new(uint32(i))
+	}
+	if resp.Message == nil {
+		resp.Message = &v1.Message{Role: v1.Role_ROLE_MODEL}
+	}
+	resp.Message.Contents = contents
+	resp.Status = v1.ChatStatus_CHAT_STATUS_COMPLETED
+}
+
+func addUsage(sum, usage *v1.Usage) {
+	sum.InputTokens += usage.GetInputTokens()
+	sum.OutputTokens += usage.GetOutputTokens()
+	sum.CachedInputTokens += usage.GetCachedInputTokens()
+	sum.ReasoningTokens += usage.GetReasoningTokens()
+	sum.CacheWriteInputTokens += usage.GetCacheWriteInputTokens()
+}
+
+// ChatStream validates the whole reply before sending any of it, so the
+// response is streamed only once complete.
+func (r *structuredOutputRepo) ChatStream(ctx context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		resp, err := r.Chat(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, event := range responseEvents(req.Id, resp) {
+			if !yield(event, nil) {
+				return
+			}
+		}
+	}
+}
+
+// responseEvents replays a complete response as the events streaming it.
+func responseEvents(id string, resp *entity.ChatResponse) []*entity.ChatEvent {
+	events := []*entity.ChatEvent{
+		v1.NewChatEvent(id, v1.NewMessageStartEvent(resp.GetMessage().GetId(), resp.Model)),
+	}
+
+	for i, c := range resp.GetMessage().GetContents() {
+		index := uint32(i)
+		switch content := c.Content.(type) {
+		case *v1.Content_Text:
+			start := v1.NewIdentifiedContentStartTextEvent(c.Id, index, c.Phase)
+			start.ContentStart.Provenance = c.Provenance
+			start.ContentStart.Metadata = c.Metadata
+			events = append(events, v1.NewChatEvent(id, start))
+			if text := content.Text.GetText(); text != "" {
+				events = append(events, v1.NewChatEvent(id, v1.NewContentDeltaTextEvent(index, text)))
+			}
+		case *v1.Content_ToolUse:
+			start := v1.NewIdentifiedContentStartToolUseEvent(c.Id, index, content.ToolUse.Id, content.ToolUse.Name)
+			start.ContentStart.Phase = c.Phase
+			start.ContentStart.Provenance = c.Provenance
+			start.ContentStart.Metadata = c.Metadata
+			events = append(events, v1.NewChatEvent(id, start))
+			if input := content.ToolUse.GetTextualInput(); input != "" {
+				events = append(events, v1.NewChatEvent(id, v1.NewContentDeltaToolInputTextEvent(index, input)))
+			}
+		default:
+			snapshot := proto.Clone(c).(*v1.Content)
+			snapshot.Index = new(index)
+			events = append(events, v1.NewChatEvent(id, v1.NewContentSnapshotEvent(snapshot)))
+			continue
+		}
+		if c.Signature != "" {
+			events = append(events, v1.NewChatEvent(id, v1.NewContentDeltaSignatureEvent(index, c.Signature)))
+		}
+		events = append(events, v1.NewChatEvent(id, v1.NewContentStopEvent(index)))
+	}
+
+	stop := v1.NewChatEvent(id, v1.NewMessageStopEvent(resp.Status))
+	stop.Usage = resp.GetStatistics().GetUsage()
+	return append(events, stop)
+}
+
+var _ repository.ChatRepo = (*structuredOutputRepo)(nil)
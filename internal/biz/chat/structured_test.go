@@ -0,0 +1,299 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"slices"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// mockChatRepo answers chats with the scripted replies in turn.
+type mockChatRepo struct {
+	replies []*v1.Message
+	reqs    []*entity.ChatRequest
+}
+
+func (r *mockChatRepo) Chat(_ context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	r.reqs = append(r.reqs, req)
+	reply := r.replies[0]
+	r.replies = r.replies[1:]
+
+	status := v1.ChatStatus_CHAT_STATUS_COMPLETED
+	for _, c := range reply.Contents {
+		if c.GetToolUse() != nil {
+			status = v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
+		}
+	}
+	return &entity.ChatResponse{
+		Id:         req.Id,
+		Model:      "model",
+		Status:     status,
+		Message:    reply,
+		Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 10, OutputTokens: 5}},
+	}, nil
+}
+
+// ChatStream streams the next reply with text split into small chunks, to
+// exercise parsing across chunk boundaries.
+func (r *mockChatRepo) ChatStream(ctx context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		resp, _ := r.Chat(ctx, req)
+		for _, event := range responseEvents(req.Id, resp) {
+			delta := event.GetContentDelta()
+			text, ok := delta.GetDelta().(*v1.ContentDelta_Text)
+			if !ok {
+				if !yield(event, nil) {
+					return
+				}
+				continue
+			}
+			for chunk := range slices.Chunk([]byte(text.Text), 3) {
+				if !yield(v1.NewChatEvent(req.Id, v1.NewContentDeltaTextEvent(delta.Index, string(chunk))), nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func newTextReply(text string) *v1.Message {
+	return &v1.Message{
+		Role: v1.Role_ROLE_MODEL,
+		Contents: []*v1.Content{
+			{Phase: v1.ContentPhase_CONTENT_PHASE_REASONING, Content: v1.NewTextContent("thinking")},
+			{Content: v1.NewTextContent(text)},
+		},
+	}
+}
+
+func newToolUseReply(name, input string) *v1.Message {
+	return &v1.Message{
+		Role: v1.Role_ROLE_MODEL,
+		Contents: []*v1.Content{{
+			Content: &v1.Content_ToolUse{ToolUse: &v1.ToolUse{
+				Id:     "call_1",
+				Name:   name,
+				Inputs: []*v1.ToolUse_Input{{Input: &v1.ToolUse_Input_Text{Text: input}}},
+			}},
+		}},
+	}
+}
+
+func newStructuredRequest() *entity.ChatRequest {
+	schema, _ := structpb.NewStruct(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"answer": map[string]any{"type": "integer"}},
+		"required":   []any{"answer"},
+	})
+	return &entity.ChatRequest{
+		Id:     "req",
+		Config: &v1.GenerationConfig{Grammar: &v1.GenerationConfig_Schema{Schema: schema}},
+		Messages: []*v1.Message{
+			{Role: v1.Role_ROLE_SYSTEM, Contents: []*v1.Content{{Content: v1.NewTextContent("Be brief.")}}},
+			{Role: v1.Role_ROLE_USER, Contents: []*v1.Content{{Content: v1.NewTextContent("1+1?")}}},
+		},
+	}
+}
+
+func TestStructuredOutputRepo(t *testing.T) {
+	Convey("Test structuredOutputRepo", t, func() {
+		ctx := context.Background()
+		chatRepo := &mockChatRepo{}
+		var recorded []*v1.Statistics
+		repo := &structuredOutputRepo{
+			chat: chatRepo,
+			log:  slog.Default(),
+			recordUsage: func(_ context.Context, stats *v1.Statistics) {
+				recorded = append(recorded, stats)
+			},
+		}
+		req := newStructuredRequest()
+
+		Convey("with tool emulation", func() {
+			repo.emulation = StructuredOutputTool
+
+			Convey("should force the tool and return its input as text", func() {
+				chatRepo.replies = []*v1.Message{newToolUseReply(structuredOutputToolName, `{"answer":2}`)}
+
+				resp, err := repo.Chat(ctx, req)
+				So(err, ShouldBeNil)
+				So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+				So(resp.Message.Contents, ShouldHaveLength, 1)
+				So(resp.Message.Contents[0].GetText().GetText(), ShouldEqual, `{"answer":2}`)
+
+				sent := chatRepo.reqs[0]
+				So(sent.Config.Grammar, ShouldBeNil)
+				So(sent.Tools, ShouldHaveLength, 1)
+				So(sent.Tools[0].GetFunction().Name, ShouldEqual, structuredOutputToolName)
+				So(sent.ToolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL)
+				So(sent.ToolChoice.Name, ShouldEqual, structuredOutputToolName)
+
+				// The request of the caller is left untouched
+				So(req.Config.Grammar, ShouldNotBeNil)
+				So(req.Tools, ShouldBeEmpty)
+			})
+
+			Convey("should feed validation errors back as tool results", func() {
+				repo.maxRetries = 1
+				chatRepo.replies = []*v1.Message{
+					newToolUseReply(structuredOutputToolName, `{"answer":"two"}`),
+					newToolUseReply(structuredOutputToolName, `{"answer":2}`),
+				}
+
+				resp, err := repo.Chat(ctx, req)
+				So(err, ShouldBeNil)
+				So(resp.Message.Contents[0].GetText().GetText(), ShouldEqual, `{"answer":2}`)
+				So(resp.Statistics.Usage.InputTokens, ShouldEqual, 20)
+				So(resp.Statistics.Usage.OutputTokens, ShouldEqual, 10)
+				So(recorded, ShouldBeEmpty)
+
+				retry := chatRepo.reqs[1]
+				So(retry.Messages, ShouldHaveLength, 4)
+				result := retry.Messages[3].Contents[0].GetToolResult()
+				So(result.Id, ShouldEqual, "call_1")
+				So(result.GetTextualOutput(), ShouldContainSubstring, "$.answer: expected integer, got string")
+			})
+
+			Convey("should let the model call tools of the caller", func() {
+				req.Tools = []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "calc"}}}}
+				chatRepo.replies = []*v1.Message{newToolUseReply("calc", `{"expr":"1+1"}`)}
+
+				resp, err := repo.Chat(ctx, req)
+				So(err, ShouldBeNil)
+				So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
+				So(resp.Message.Contents[0].GetToolUse().Name, ShouldEqual, "calc")
+				So(chatRepo.reqs[0].ToolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED)
+			})
+
+			Convey("should keep the reply tool among the allowed tools", func() {
+				req.Tools = []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "calc"}}}}
+				req.ToolChoice = &v1.ToolChoice{AllowedNames: []string{"calc"}}
+				chatRepo.replies = []*v1.Message{newToolUseReply(structuredOutputToolName, `{"answer":2}`)}
+
+				_, err := repo.Chat(ctx, req)
+				So(err, ShouldBeNil)
+				So(chatRepo.reqs[0].ToolChoice.AllowedNames, ShouldResemble, []string{"calc", structuredOutputToolName})
+				So(req.ToolChoice.AllowedNames, ShouldResemble, []string{"calc"})
+			})
+		})
+
+		Convey("with instruction emulation", func() {
+			repo.emulation = StructuredOutputInstruction
+
+			Convey("should instruct the schema and strip code fences", func() {
+				chatRepo.replies = []*v1.Message{newTextReply("```json\n{\"answer\": 2}\n```")}
+
+				resp, err := repo.Chat(ctx, req)
+				So(err, ShouldBeNil)
+				So(resp.Message.Contents, ShouldHaveLength, 2)
+				So(resp.Message.Contents[0].IsReasoning(), ShouldBeTrue)
+				So(resp.Message.Contents[1].GetText().GetText(), ShouldEqual, `{"answer": 2}`)
+
+				sent := chatRepo.reqs[0]
+				So(sent.Messages, ShouldHaveLength, 3)
+				So(sent.Messages[1].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+				So(sent.Messages[1].Contents[0].GetText().GetText(), ShouldContainSubstring, `"required":["answer"]`)
+				So(sent.Messages[2].Role, ShouldEqual, v1.Role_ROLE_USER)
+			})
+
+			Convey("should fail once retries are exhausted", func() {
+				repo.maxRetries = 1
+				chatRepo.replies = []*v1.Message{newTextReply("two"), newTextReply(`{}`)}
+
+				_, err := repo.Chat(ctx, req)
+				So(errors.Is(err, entity.ErrStructuredOutputInvalid), ShouldBeTrue)
+				So(chatRepo.reqs, ShouldHaveLength, 2)
+				So(chatRepo.reqs[1].Messages[4].Contents[0].GetText().GetText(), ShouldContainSubstring, "invalid JSON")
+
+				// Both attempts are charged though no response is returned
+				So(recorded, ShouldHaveLength, 1)
+				So(recorded[0].Usage.InputTokens, ShouldEqual, 20)
+				So(recorded[0].Usage.OutputTokens, ShouldEqual, 10)
+			})
+
+			Convey("should accept any object in JSON object mode", func() {
+				req.Config.Grammar = &v1.GenerationConfig_PresetGrammar{PresetGrammar: "json_object"}
+				chatRepo.replies = []*v1.Message{newTextReply(`[1]`), newTextReply(`{"a":1}`)}
+				repo.maxRetries = 1
+
+				resp, err := repo.Chat(ctx, req)
+				So(err, ShouldBeNil)
+				So(resp.Message.Contents[1].GetText().GetText(), ShouldEqual, `{"a":1}`)
+			})
+		})
+
+		Convey("ChatStream should replay the validated response", func() {
+			repo.emulation = StructuredOutputInstruction
+			chatRepo.replies = []*v1.Message{newTextReply(`{"answer":2}`)}
+
+			reducer := NewChatEventReducer(slog.Default())
+			for event, err := range repo.ChatStream(ctx, req) {
+				So(err, ShouldBeNil)
+				reducer.Reduce(event)
+			}
+			resp := reducer.Resp()
+			So(resp.Id, ShouldEqual, "req")
+			So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+			So(resp.Message.Contents, ShouldHaveLength, 2)
+			So(resp.Message.Contents[0].GetText().GetText(), ShouldEqual, "thinking")
+			So(resp.Message.Contents[1].GetText().GetText(), ShouldEqual, `{"answer":2}`)
+			So(resp.Statistics.Usage.InputTokens, ShouldEqual, 10)
+		})
+	})
+}
+
+type mockModel struct {
+	chatRepo         repository.ChatRepo
+	emulatedToolUse  bool
+	structuredOutput StructuredOutput
+	timeouts         Timeouts
+}
+
+func (m *mockModel) ChatRepo() repository.ChatRepo               { return m.chatRepo }
+func (m *mockModel) EmulatedToolUse() bool                       { return m.emulatedToolUse }
+func (m *mockModel) StructuredOutput() StructuredOutput          { return m.structuredOutput }
+func (m *mockModel) Timeouts() Timeouts                          { return m.timeouts }
+func (m *mockModel) RecordUsage(context.Context, *v1.Statistics) {}
+func (m *mockModel) Close()                                      {}
+
+func TestChatRepo(t *testing.T) {
+	Convey("Test chatUseCase.chatRepo", t, func() {
+		uc := &chatUseCase{log: slog.Default()}
+		chatRepo := &mockChatRepo{}
+		model := &mockModel{chatRepo: chatRepo}
+
+		Convey("should emulate structured output when configured", func() {
+			model.structuredOutput = StructuredOutput{Emulation: StructuredOutputTool, MaxRetries: 2}
+			repo, ok := uc.chatRepo(model, newStructuredRequest()).(*structuredOutputRepo)
+			So(ok, ShouldBeTrue)
+			So(repo.maxRetries, ShouldEqual, 2)
+		})
+
+		Convey("should use the model directly without structured output", func() {
+			model.structuredOutput = StructuredOutput{Emulation: StructuredOutputTool}
+			So(uc.chatRepo(model, &entity.ChatRequest{}), ShouldEqual, chatRepo)
+		})
+
+		Convey("should use the model directly when it follows schemas", func() {
+			So(uc.chatRepo(model, newStructuredRequest()), ShouldEqual, chatRepo)
+		})
+
+		Convey("should emulate tool calls beneath structured output", func() {
+			model.emulatedToolUse = true
+			model.structuredOutput = StructuredOutput{Emulation: StructuredOutputTool}
+			repo := uc.chatRepo(model, newStructuredRequest()).(*structuredOutputRepo)
+			toolUse, ok := repo.chat.(*toolUseRepo)
+			So(ok, ShouldBeTrue)
+			So(toolUse.chat, ShouldEqual, chatRepo)
+		})
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// Timeouts bound the chat requests to a model. Zero durations are not enforced.
+type Timeouts struct {
+	// FirstEvent is the time allowed until the first event of a stream.
+	FirstEvent time.Duration
+	// Idle is the time allowed between two events of a stream.
+	Idle time.Duration
+	// Total is the time allowed for the whole request.
+	Total time.Duration
+}
+
+// timeoutRepo aborts the requests of a chat repo that exceed its timeouts,
+// failing them with entity.ErrUpstreamTimeout. Time spent by the consumer of
+// a stream is not counted as idle.
+type timeoutRepo struct {
+	chat     repository.ChatRepo
+	timeouts Timeouts
+}
+
+// timeoutError returns the error of an exceeded timeout.
+func timeoutError(what string, d time.Duration) error {
+	return entity.ErrUpstreamTimeout.WithCause(fmt.Errorf("%s exceeded %s", what, d))
+}
+
+// timeoutCause returns the timeout that cancelled ctx, if any.
+func timeoutCause(ctx context.Context) error {
+	if cause := context.Cause(ctx); errors.Is(cause, entity.ErrUpstreamTimeout) {
+		return cause
+	}
+	return nil
+}
+
+// Chat bounds a request that is not streamed by the total time only, as its
+// response arrives once the whole generation is done.
+func (r *timeoutRepo) Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	if d := r.timeouts.Total; d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, d, timeoutError("total time", d))
+		defer cancel()
+	}
+
+	resp, err := r.chat.Chat(ctx, req)
+	if err != nil {
+		if cause := timeoutCause(ctx); cause != nil {
+			return nil, cause
+		}
+	}
+	return resp, err
+}
+
+func (r *timeoutRepo) ChatStream(ctx context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		if d := r.timeouts.Total; d > 0 {
+			total := time.AfterFunc(d, func() { cancel(timeoutError("total time", d)) })
+			defer total.Stop()
+		}
+
+		// The timer waiting for the next event, armed only while the
+		// upstream is read
+		var wait *time.Timer
+		arm := func(what string, d time.Duration) {
+			if d > 0 {
+				wait = time.AfterFunc(d, func() { cancel(timeoutError(what, d)) })
+			}
+		}
+		disarm := func() {
+			if wait != nil {
+				wait.Stop()
+				wait = nil
+			}
+		}
+		defer disarm()
+
+		arm("time to first event", r.timeouts.FirstEvent)
+		for event, err := range r.chat.ChatStream(ctx, req) {
+			disarm()
+			if err != nil {
+				if cause := timeoutCause(ctx); cause != nil {
+					err = cause
+				}
+				yield(nil, err)
+				return
+			}
+			if !yield(event, nil) {
+				return
+			}
+			arm("idle time", r.timeouts.Idle)
+		}
+
+		// Upstreams may end a cancelled stream without an error
+		if cause := timeoutCause(ctx); cause != nil {
+			yield(nil, cause)
+		}
+	}
+}
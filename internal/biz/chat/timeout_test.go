@@ -0,0 +1,216 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v3/errors"
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// stallingChatRepo streams an event after each delay, then stalls until the
+// request is cancelled.
+type stallingChatRepo struct {
+	delays []time.Duration
+}
+
+func (r *stallingChatRepo) Chat(ctx context.Context, _ *entity.ChatRequest) (*entity.ChatResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (r *stallingChatRepo) ChatStream(ctx context.Context, _ *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		for _, d := range r.delays {
+			select {
+			case <-time.After(d):
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			}
+			if !yield(newTestChatEvent(v1.NewContentDeltaTextEvent(0, "x")), nil) {
+				return
+			}
+		}
+		<-ctx.Done()
+		yield(nil, ctx.Err())
+	}
+}
+
+// slowChatRepo replies after a delay.
+type slowChatRepo struct {
+	*mockChatRepo
+	delay time.Duration
+}
+
+func (r *slowChatRepo) Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	select {
+	case <-time.After(r.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return r.mockChatRepo.Chat(ctx, req)
+}
+
+// collect drains the stream, returning the number of events and the error.
+func collect(seq iter.Seq2[*entity.ChatEvent, error]) (events int, err error) {
+	for _, err := range seq {
+		if err != nil {
+			return events, err
+		}
+		events++
+	}
+	return events, nil
+}
+
+func TestTimeoutRepo(t *testing.T) {
+	Convey("Test timeoutRepo", t, func() {
+		ctx := context.Background()
+		req := &entity.ChatRequest{}
+
+		Convey("should time out waiting for the first event", func() {
+			repo := &timeoutRepo{
+				chat:     &stallingChatRepo{delays: []time.Duration{time.Second}},
+				timeouts: Timeouts{FirstEvent: 20 * time.Millisecond},
+			}
+			events, err := collect(repo.ChatStream(ctx, req))
+			So(events, ShouldEqual, 0)
+			So(errors.Is(err, entity.ErrUpstreamTimeout), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "time to first event")
+		})
+
+		Convey("should time out between events", func() {
+			repo := &timeoutRepo{
+				chat:     &stallingChatRepo{delays: []time.Duration{0, 0}},
+				timeouts: Timeouts{FirstEvent: time.Second, Idle: 20 * time.Millisecond},
+			}
+			events, err := collect(repo.ChatStream(ctx, req))
+			So(events, ShouldEqual, 2)
+			So(errors.Is(err, entity.ErrUpstreamTimeout), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "idle time")
+		})
+
+		Convey("should not count the time of the consumer as idle", func() {
+			repo := &timeoutRepo{
+				chat:     &stallingChatRepo{delays: []time.Duration{0, 0}},
+				timeouts: Timeouts{Idle: 20 * time.Millisecond, Total: 200 * time.Millisecond},
+			}
+			var events int
+			var err error
+			for _, err = range repo.ChatStream(ctx, req) {
+				if err != nil {
+					break
+				}
+				events++
+				time.Sleep(40 * time.Millisecond)
+			}
+			So(events, ShouldEqual, 2)
+			So(err.Error(), ShouldContainSubstring, "idle time")
+		})
+
+		Convey("should time out the whole stream", func() {
+			repo := &timeoutRepo{
+				chat: &stallingChatRepo{delays: []time.Duration{
+					10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond,
+				}},
+				timeouts: Timeouts{Idle: time.Second, Total: 25 * time.Millisecond},
+			}
+			events, err := collect(repo.ChatStream(ctx, req))
+			So(events, ShouldBeLessThan, 4)
+			So(errors.Is(err, entity.ErrUpstreamTimeout), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "total time")
+		})
+
+		Convey("should time out a request that is not streamed", func() {
+			repo := &timeoutRepo{
+				chat:     &stallingChatRepo{},
+				timeouts: Timeouts{Total: 20 * time.Millisecond},
+			}
+			_, err := repo.Chat(ctx, req)
+			So(errors.Is(err, entity.ErrUpstreamTimeout), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "total time")
+		})
+
+		Convey("should not bound a request that is not streamed by the first event", func() {
+			repo := &timeoutRepo{
+				chat: &slowChatRepo{
+					mockChatRepo: &mockChatRepo{replies: []*v1.Message{newTextReply("hi")}},
+					delay:        40 * time.Millisecond,
+				},
+				timeouts: Timeouts{FirstEvent: 10 * time.Millisecond, Total: time.Second},
+			}
+			_, err := repo.Chat(ctx, req)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should not bound a stream of emulated structured output by the first event", func() {
+			repo := &structuredOutputRepo{
+				chat: &timeoutRepo{
+					chat: &slowChatRepo{
+						mockChatRepo: &mockChatRepo{replies: []*v1.Message{newToolUseReply(structuredOutputToolName, `{"answer":2}`)}},
+						delay:        40 * time.Millisecond,
+					},
+					timeouts: Timeouts{FirstEvent: 10 * time.Millisecond, Total: time.Second},
+				},
+				emulation:   StructuredOutputTool,
+				log:         slog.Default(),
+				recordUsage: func(context.Context, *v1.Statistics) {},
+			}
+			events, err := collect(repo.ChatStream(ctx, newStructuredRequest()))
+			So(err, ShouldBeNil)
+			So(events, ShouldBeGreaterThan, 0)
+		})
+
+		Convey("should mark the timeout as retryable", func() {
+			repo := &timeoutRepo{
+				chat:     &stallingChatRepo{},
+				timeouts: Timeouts{Total: 20 * time.Millisecond},
+			}
+			_, err := repo.Chat(ctx, req)
+			So(kerrors.FromError(err).GetMetadata(), ShouldContainKey, "retryable")
+		})
+
+		Convey("should pass other errors through", func() {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			repo := &timeoutRepo{
+				chat:     &stallingChatRepo{},
+				timeouts: Timeouts{FirstEvent: time.Second},
+			}
+			_, err := collect(repo.ChatStream(cancelled, req))
+			So(errors.Is(err, context.Canceled), ShouldBeTrue)
+		})
+
+		Convey("should be applied by the chat use case when configured", func() {
+			uc := &chatUseCase{}
+			model := &mockModel{chatRepo: &mockChatRepo{}}
+			So(uc.chatRepo(model, req), ShouldEqual, model.chatRepo)
+
+			model.timeouts = Timeouts{Idle: time.Second}
+			repo, ok := uc.chatRepo(model, req).(*timeoutRepo)
+			So(ok, ShouldBeTrue)
+			So(repo.chat, ShouldEqual, model.chatRepo)
+		})
+	})
+}
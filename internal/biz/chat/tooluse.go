@@ -0,0 +1,398 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+// Tool calls are emulated in the Hermes format.
+const (
+	toolCallTag     = util.ToolCallTag
+	toolResponseTag = util.ToolResponseTag
+
+	toolUseInstruction = "You may call tools to help with the request. To call a tool, reply with a JSON object " +
+		"holding its name and arguments within <tool_call></tool_call> tags, for example:\n" +
+		"<tool_call>\n{\"name\": \"tool_name\", \"arguments\": {\"argument\": \"value\"}}\n</tool_call>\n" +
+		"After calling tools, stop and wait for their results, which are given within " +
+		"<tool_response></tool_response> tags.\n\nThe tools available are:\n<tools>\n"
+)
+
+// toolUseRepo emulates tool calling over a chat model without native
+// support. Tools and earlier calls and results are rendered into the prompt,
+// and calls are parsed from the text of the reply, as it streams.
+type toolUseRepo struct {
+	chat repository.ChatRepo
+	log  *slog.Logger
+}
+
+// usesTools reports whether the request has tools, or calls and results of
+// them in its history.
+func usesTools(req *entity.ChatRequest) bool {
+	if len(req.Tools) > 0 {
+		return true
+	}
+	for _, m := range req.Messages {
+		for _, c := range m.Contents {
+			switch c.Content.(type) {
+			case *v1.Content_ToolUse, *v1.Content_ToolResult:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type emulatedTool struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Parameters  *structpb.Struct `json:"parameters,omitempty"`
+}
+
+func toolUseInstructionOf(tools []*v1.Tool, choice *v1.ToolChoice) string {
+	var sb strings.Builder
+	sb.WriteString(toolUseInstruction)
+	for _, tool := range choice.FilterTools(tools) {
+		f := tool.GetFunction()
+		if f == nil {
+			continue
+		}
+		b, _ := json.Marshal(emulatedTool{Name: f.Name, Description: f.Description, Parameters: f.InputSchema})
+		sb.Write(b)
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("</tools>")
+
+	switch choice.GetMode() {
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+		sb.WriteString("\n\nDo not call any tool now.")
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED:
+		sb.WriteString("\n\nYou must call at least one tool now.")
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		fmt.Fprintf(&sb, "\n\nYou must call the %s tool now.", choice.Name)
+	}
+	if choice != nil && choice.ParallelToolCalls != nil && !*choice.ParallelToolCalls {
+		sb.WriteString(" Call at most one tool at a time.")
+	}
+	return sb.String()
+}
+
+// renderToolUse writes a tool call the way the model is told to call tools.
+func renderToolUse(toolUse *v1.ToolUse) string {
+	arguments := json.RawMessage(toolUse.GetTextualInput())
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+	if !json.Valid(arguments) {
+		arguments, _ = json.Marshal(string(arguments))
+	}
+	b, _ := json.Marshal(util.HermesToolCall{Name: toolUse.Name, Arguments: arguments})
+	return "<" + toolCallTag + ">\n" + string(b) + "\n</" + toolCallTag + ">"
+}
+
+// renderToolResult writes the textual output of a tool result; images stay
+// contents of their own.
+func renderToolResult(toolResult *v1.ToolResult) []*v1.Content {
+	contents := []*v1.Content{{
+		Content: v1.NewTextContent("<" + toolResponseTag + ">\n" + toolResult.GetTextualOutput() + "\n</" + toolResponseTag + ">"),
+	}}
+	for _, output := range toolResult.Outputs {
+		if image, ok := output.Output.(*v1.ToolResult_Output_Image); ok {
+			contents = append(contents, &v1.Content{Content: &v1.Content_Image{Image: image.Image}})
+		}
+	}
+	return contents
+}
+
+func convertToolUseRequest(req *entity.ChatRequest) *entity.ChatRequest {
+	emulated := proto.Clone(req).(*entity.ChatRequest)
+	emulated.Tools = nil
+	emulated.ToolChoice = nil
+
+	for _, m := range emulated.Messages {
+		contents := make([]*v1.Content, 0, len(m.Contents))
+		for _, c := range m.Contents {
+			switch content := c.Content.(type) {
+			case *v1.Content_ToolUse:
+				contents = append(contents, &v1.Content{Content: v1.NewTextContent(renderToolUse(content.ToolUse))})
+			case *v1.Content_ToolResult:
+				contents = append(contents, renderToolResult(content.ToolResult)...)
+			default:
+				contents = append(contents, c)
+			}
+		}
+		m.Contents = contents
+	}
+
+	if len(req.Tools) > 0 {
+		emulated.Messages = insertInstruction(emulated.Messages, toolUseInstructionOf(req.Tools, req.ToolChoice))
+	}
+	return emulated
+}
+
+// toolCallBlock is the state of a text content of the reply, which may turn
+// into several contents of text and tool uses.
+type toolCallBlock struct {
+	start  *v1.ContentStart
+	parser *util.TagParser
+	// The index of the text content being sent, if any
+	text *uint32
+	// Whitespace not yet sent, which is dropped around tool calls
+	pending string
+	call    strings.Builder
+	// A signature received while no text content was open, sent when the
+	// block is flushed
+	signature string
+}
+
+// toolCallExtractor rewrites the events of a reply, turning the tool calls
+// written in its text into tool use contents. Contents are renumbered, since
+// a text content may be split.
+type toolCallExtractor struct {
+	id      string
+	blocks  map[uint32]*toolCallBlock
+	indices map[uint32]uint32
+	next    uint32
+	calls   int
+	// Set once the model writes tool results itself, after which the rest of
+	// the reply is made up and dropped
+	done  bool
+	usage *v1.Usage
+}
+
+func newToolCallExtractor(id string) *toolCallExtractor {
+	return &toolCallExtractor{
+		id:      id,
+		blocks:  map[uint32]*toolCallBlock{},
+		indices: map[uint32]uint32{},
+	}
+}
+
+func (x *toolCallExtractor) process(event *entity.ChatEvent) (events []*entity.ChatEvent) {
+	emit := func(payload v1.ChatEventPayload) {
+		events = append(events, v1.NewChatEvent(x.id, payload))
+	}
+
+	switch e := event.Event.(type) {
+	case *v1.ChatEvent_ContentStart:
+		start := e.ContentStart
+		if _, ok := start.Content.(*v1.ContentStart_Text); ok && start.Phase != v1.ContentPhase_CONTENT_PHASE_REASONING {
+			x.blocks[start.Index] = &toolCallBlock{start: start, parser: util.NewTagParser(toolCallTag, toolResponseTag)}
+			break
+		}
+		start = proto.Clone(start).(*v1.ContentStart)
+		start.Index = x.allocate(e.ContentStart.Index)
+		emit(&v1.ChatEvent_ContentStart{ContentStart: start})
+	case *v1.ChatEvent_ContentDelta:
+		if block := x.blocks[e.ContentDelta.Index]; block != nil {
+			switch d := e.ContentDelta.Delta.(type) {
+			case *v1.ContentDelta_Text:
+				for _, te := range block.parser.Feed(d.Text) {
+					x.processTag(block, te, emit)
+				}
+			case *v1.ContentDelta_Signature:
+				if block.text != nil {
+					emit(v1.NewContentDeltaSignatureEvent(*block.text, d.Signature))
+				} else {
+					block.signature = d.Signature
+				}
+			}
+			break
+		}
+		delta := proto.Clone(e.ContentDelta).(*v1.ContentDelta)
+		delta.Index = x.indices[e.ContentDelta.Index]
+		emit(&v1.ChatEvent_ContentDelta{ContentDelta: delta})
+	case *v1.ChatEvent_ContentStop:
+		if block := x.blocks[e.ContentStop.Index]; block != nil {
+			x.flush(block, emit)
+			delete(x.blocks, e.ContentStop.Index)
+			break
+		}
+		emit(v1.NewContentStopEvent(x.indices[e.ContentStop.Index]))
+	case *v1.ChatEvent_ContentSnapshot:
+		snapshot := proto.Clone(e.ContentSnapshot).(*v1.Content)
+		snapshot.Index = new(x.allocate(e.ContentSnapshot.GetIndex()))
+		emit(v1.NewContentSnapshotEvent(snapshot))
+	case *v1.ChatEvent_MessageStop:
+		// Blocks left open are flushed in the order of the reply
+		for _, index := range slices.Sorted(maps.Keys(x.blocks)) {
+			x.flush(x.blocks[index], emit)
+			delete(x.blocks, index)
+		}
+		status := e.MessageStop.Status
+		if x.calls > 0 && status == v1.ChatStatus_CHAT_STATUS_COMPLETED {
+			status = v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
+		}
+		emit(v1.NewMessageStopEvent(status))
+	default:
+		events = append(events, proto.Clone(event).(*entity.ChatEvent))
+		events[len(events)-1].Id = x.id
+	}
+
+	// Usage rides on the next event sent, as the events carrying it may be
+	// held back
+	if event.Usage != nil {
+		x.usage = event.Usage
+	}
+	if x.usage != nil && len(events) > 0 {
+		events[len(events)-1].Usage = x.usage
+		x.usage = nil
+	}
+	return
+}
+
+func (x *toolCallExtractor) allocate(index uint32) uint32 {
+	x.indices[index] = x.next
+	x.next++
+	return x.indices[index]
+}
+
+func (x *toolCallExtractor) processTag(block *toolCallBlock, te util.TagEvent, emit func(v1.ChatEventPayload)) {
+	if x.done {
+		return
+	}
+
+	switch {
+	case te.Kind == util.TagEventText && te.Tag == "":
+		x.sendText(block, te.Text, emit)
+	case te.Kind == util.TagEventText && te.Tag == toolCallTag:
+		block.call.WriteString(te.Text)
+	case te.Kind == util.TagEventOpen:
+		x.stopText(block, emit)
+		block.pending = ""
+		block.call.Reset()
+		if te.Tag == toolResponseTag {
+			x.done = true
+		}
+	case te.Kind == util.TagEventClose && te.Tag == toolCallTag:
+		toolUse, ok := util.ParseHermesToolCall(block.call.String())
+		if !ok {
+			// Not a call after all, so the model meant the text as is
+			x.sendText(block, "<"+toolCallTag+">"+block.call.String()+"</"+toolCallTag+">", emit)
+			return
+		}
+		index := x.next
+		x.next++
+		x.calls++
+		emit(v1.NewContentStartToolUseEvent(index, toolUse.Id, toolUse.Name))
+		emit(v1.NewContentDeltaToolInputTextEvent(index, toolUse.GetTextualInput()))
+		emit(v1.NewContentStopEvent(index))
+	}
+}
+
+func (x *toolCallExtractor) sendText(block *toolCallBlock, text string, emit func(v1.ChatEventPayload)) {
+	if block.text == nil {
+		block.pending += text
+		if strings.TrimSpace(block.pending) == "" {
+			return
+		}
+		text, block.pending = block.pending, ""
+		x.startText(block, emit)
+	}
+	emit(v1.NewContentDeltaTextEvent(*block.text, text))
+}
+
+func (x *toolCallExtractor) startText(block *toolCallBlock, emit func(v1.ChatEventPayload)) {
+	index := x.next
+	x.next++
+	block.text = &index
+	start := v1.NewIdentifiedContentStartTextEvent(block.start.Id, index, block.start.Phase)
+	start.ContentStart.Metadata = block.start.Metadata
+	start.ContentStart.Provenance = block.start.Provenance
+	emit(start)
+}
+
+func (x *toolCallExtractor) stopText(block *toolCallBlock, emit func(v1.ChatEventPayload)) {
+	if block.text != nil {
+		emit(v1.NewContentStopEvent(*block.text))
+		block.text = nil
+	}
+}
+
+func (x *toolCallExtractor) flush(block *toolCallBlock, emit func(v1.ChatEventPayload)) {
+	for _, te := range block.parser.Flush() {
+		x.processTag(block, te, emit)
+	}
+	// A signature held back, such as one trailing a tool call, is sent on a
+	// text content of its own
+	if block.signature != "" {
+		if block.text == nil {
+			x.startText(block, emit)
+		}
+		emit(v1.NewContentDeltaSignatureEvent(*block.text, block.signature))
+		block.signature = ""
+	}
+	x.stopText(block, emit)
+}
+
+func (r *toolUseRepo) Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	if !usesTools(req) {
+		return r.chat.Chat(ctx, req)
+	}
+
+	resp, err := r.chat.Chat(ctx, convertToolUseRequest(req))
+	if err != nil {
+		return nil, err
+	}
+
+	// The reply is replayed through the extractor of streams, so that both
+	// find calls alike
+	x := newToolCallExtractor(req.Id)
+	reducer := NewChatEventReducer(r.log)
+	for _, event := range responseEvents(req.Id, resp) {
+		for _, e := range x.process(event) {
+			reducer.Reduce(e)
+		}
+	}
+	return reducer.Resp(), nil
+}
+
+func (r *toolUseRepo) ChatStream(ctx context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	if !usesTools(req) {
+		return r.chat.ChatStream(ctx, req)
+	}
+
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		x := newToolCallExtractor(req.Id)
+		for event, err := range r.chat.ChatStream(ctx, convertToolUseRequest(req)) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, e := range x.process(event) {
+				if !yield(e, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+var _ repository.ChatRepo = (*toolUseRepo)(nil)
@@ -0,0 +1,211 @@
+package chat
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func newToolUseRequest() *entity.ChatRequest {
+	schema, _ := structpb.NewStruct(map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+	})
+	return &entity.ChatRequest{
+		Id: "req",
+		Messages: []*v1.Message{
+			{Role: v1.Role_ROLE_SYSTEM, Contents: []*v1.Content{{Content: v1.NewTextContent("Be brief.")}}},
+			{Role: v1.Role_ROLE_USER, Contents: []*v1.Content{{Content: v1.NewTextContent("Weather in Paris?")}}},
+		},
+		Tools: []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{
+			Name:        "get_weather",
+			Description: "Get the weather of a city",
+			InputSchema: schema,
+		}}}},
+	}
+}
+
+const toolCallReply = "Let me check.\n<tool_call>\n{\"name\": \"get_weather\", \"arguments\": {\"city\": \"Paris\"}}\n</tool_call>\n" +
+	"<tool_call>\n{\"name\": \"get_weather\", \"arguments\": \"{\\\"city\\\": \\\"Lyon\\\"}\"}\n</tool_call>\n" +
+	"<tool_response>\nSunny\n</tool_response>"
+
+func TestToolUseRepo(t *testing.T) {
+	Convey("Test toolUseRepo", t, func() {
+		ctx := context.Background()
+		chatRepo := &mockChatRepo{}
+		repo := &toolUseRepo{chat: chatRepo, log: slog.Default()}
+		req := newToolUseRequest()
+
+		Convey("should render tools into the prompt", func() {
+			chatRepo.replies = []*v1.Message{newTextReply("Sunny.")}
+			req.ToolChoice = &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED}
+
+			_, err := repo.Chat(ctx, req)
+			So(err, ShouldBeNil)
+
+			sent := chatRepo.reqs[0]
+			So(sent.Tools, ShouldBeEmpty)
+			So(sent.ToolChoice, ShouldBeNil)
+			So(sent.Messages, ShouldHaveLength, 3)
+			instruction := sent.Messages[1].Contents[0].GetText().GetText()
+			So(instruction, ShouldContainSubstring, `{"name":"get_weather","description":"Get the weather of a city","parameters":{`)
+			So(instruction, ShouldEndWith, "You must call at least one tool now.")
+		})
+
+		Convey("should render earlier calls and results as text", func() {
+			chatRepo.replies = []*v1.Message{newTextReply("Sunny.")}
+			req.Messages = append(req.Messages,
+				&v1.Message{Role: v1.Role_ROLE_MODEL, Contents: []*v1.Content{{
+					Content: &v1.Content_ToolUse{ToolUse: &v1.ToolUse{
+						Id:     "call_1",
+						Name:   "get_weather",
+						Inputs: []*v1.ToolUse_Input{{Input: &v1.ToolUse_Input_Text{Text: `{"city":"Paris"}`}}},
+					}},
+				}}},
+				&v1.Message{Role: v1.Role_ROLE_USER, Contents: []*v1.Content{{
+					Content: &v1.Content_ToolResult{ToolResult: &v1.ToolResult{
+						Id:      "call_1",
+						Outputs: []*v1.ToolResult_Output{{Output: &v1.ToolResult_Output_Text{Text: "Sunny"}}},
+					}},
+				}}},
+			)
+
+			_, err := repo.Chat(ctx, req)
+			So(err, ShouldBeNil)
+
+			sent := chatRepo.reqs[0]
+			So(sent.Messages[3].Contents[0].GetText().GetText(), ShouldEqual,
+				"<tool_call>\n{\"name\":\"get_weather\",\"arguments\":{\"city\":\"Paris\"}}\n</tool_call>")
+			So(sent.Messages[4].Contents[0].GetText().GetText(), ShouldEqual, "<tool_response>\nSunny\n</tool_response>")
+
+			// The request of the caller is left untouched
+			So(req.Messages[2].Contents[0].GetToolUse(), ShouldNotBeNil)
+		})
+
+		Convey("should pass requests without tools through", func() {
+			chatRepo.replies = []*v1.Message{newTextReply("<tool_call>{}</tool_call>")}
+			req.Tools = nil
+
+			resp, err := repo.Chat(ctx, req)
+			So(err, ShouldBeNil)
+			So(chatRepo.reqs[0], ShouldEqual, req)
+			So(resp.Message.Contents[1].GetText().GetText(), ShouldEqual, "<tool_call>{}</tool_call>")
+		})
+
+		verifyToolCalls := func(resp *entity.ChatResponse) {
+			So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
+			So(resp.Statistics.Usage.InputTokens, ShouldEqual, 10)
+
+			contents := resp.Message.Contents
+			So(contents, ShouldHaveLength, 4)
+			So(contents[0].IsReasoning(), ShouldBeTrue)
+			So(contents[1].GetText().GetText(), ShouldEqual, "Let me check.\n")
+			So(contents[2].GetToolUse().Name, ShouldEqual, "get_weather")
+			So(contents[2].GetToolUse().Id, ShouldStartWith, "call_")
+			So(contents[2].GetToolUse().GetTextualInput(), ShouldEqual, `{"city": "Paris"}`)
+			So(contents[3].GetToolUse().GetTextualInput(), ShouldEqual, `{"city": "Lyon"}`)
+			So(contents[3].GetToolUse().Id, ShouldNotEqual, contents[2].GetToolUse().Id)
+			for i, c := range contents {
+				So(c.GetIndex(), ShouldEqual, i)
+			}
+		}
+
+		Convey("should parse tool calls from the reply", func() {
+			chatRepo.replies = []*v1.Message{newTextReply(toolCallReply)}
+
+			resp, err := repo.Chat(ctx, req)
+			So(err, ShouldBeNil)
+			So(resp.Id, ShouldEqual, "req")
+			verifyToolCalls(resp)
+		})
+
+		Convey("should parse tool calls from streamed replies", func() {
+			chatRepo.replies = []*v1.Message{newTextReply(toolCallReply)}
+
+			reducer := NewChatEventReducer(slog.Default())
+			var toolStarts int
+			for event, err := range repo.ChatStream(ctx, req) {
+				So(err, ShouldBeNil)
+				if event.GetContentStart().GetToolUse() != nil {
+					toolStarts++
+				}
+				reducer.Reduce(event)
+			}
+			So(toolStarts, ShouldEqual, 2)
+			verifyToolCalls(reducer.Resp())
+		})
+
+		Convey("should keep malformed calls as text", func() {
+			chatRepo.replies = []*v1.Message{newTextReply("<tool_call>not json</tool_call>")}
+
+			resp, err := repo.Chat(ctx, req)
+			So(err, ShouldBeNil)
+			So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+			So(resp.Message.Contents[1].GetText().GetText(), ShouldEqual, "<tool_call>not json</tool_call>")
+		})
+	})
+}
+
+func TestToolCallExtractor(t *testing.T) {
+	Convey("Test toolCallExtractor", t, func() {
+		Convey("should flush the blocks left open in the order of the reply", func() {
+			run := func() (indices []uint32, texts []string) {
+				x := newToolCallExtractor("req")
+				x.process(v1.NewChatEvent("req", v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_NORMAL)))
+				x.process(v1.NewChatEvent("req", v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL)))
+				x.process(v1.NewChatEvent("req", v1.NewContentDeltaTextEvent(0, "first <tool_c")))
+				x.process(v1.NewChatEvent("req", v1.NewContentDeltaTextEvent(1, "second <tool_c")))
+				for _, event := range x.process(v1.NewChatEvent("req", v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_COMPLETED))) {
+					if delta := event.GetContentDelta(); delta != nil {
+						indices = append(indices, delta.Index)
+						texts = append(texts, delta.GetText())
+					}
+				}
+				return
+			}
+			for range 20 {
+				indices, texts := run()
+				So(indices, ShouldResemble, []uint32{0, 1})
+				So(texts, ShouldResemble, []string{"<tool_c", "<tool_c"})
+			}
+		})
+
+		Convey("should keep a signature trailing a tool call", func() {
+			x := newToolCallExtractor("req")
+			reducer := NewChatEventReducer(slog.Default())
+			for _, event := range []*entity.ChatEvent{
+				v1.NewChatEvent("req", v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+				v1.NewChatEvent("req", v1.NewContentDeltaTextEvent(0, "<tool_call>{\"name\": \"get_weather\", \"arguments\": {}}</tool_call>")),
+				v1.NewChatEvent("req", v1.NewContentDeltaSignatureEvent(0, "sig")),
+				v1.NewChatEvent("req", v1.NewContentStopEvent(0)),
+				v1.NewChatEvent("req", v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_COMPLETED)),
+			} {
+				for _, out := range x.process(event) {
+					reducer.Reduce(out)
+				}
+			}
+
+			contents := reducer.Resp().Message.Contents
+			So(contents, ShouldHaveLength, 2)
+			So(contents[0].GetToolUse().GetName(), ShouldEqual, "get_weather")
+			So(contents[1].GetText().GetText(), ShouldBeEmpty)
+			So(contents[1].Signature, ShouldEqual, "sig")
+		})
+
+		Convey("should keep the provenance of text contents", func() {
+			x := newToolCallExtractor("req")
+			start := v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_NORMAL)
+			start.ContentStart.Provenance = v1.Provenance_PROVENANCE_ANTHROPIC
+			x.process(v1.NewChatEvent("req", start))
+
+			events := x.process(v1.NewChatEvent("req", v1.NewContentDeltaTextEvent(0, "Hi")))
+			So(events[0].GetContentStart().GetProvenance(), ShouldEqual, v1.Provenance_PROVENANCE_ANTHROPIC)
+		})
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+type UseCase interface {
+	Complete(ctx context.Context, req *entity.CompleteRequest) (*entity.CompleteResponse, error)
+	CompleteStream(ctx context.Context, req *entity.CompleteRequest, server repository.CompletionStreamServer) error
+}
+
+type useCase struct {
+	elector Elector
+	log     *slog.Logger
+}
+
+// NewUseCase creates a new completion use case instance.
+func NewUseCase(elector Elector, logger *slog.Logger) UseCase {
+	return &useCase{
+		elector: elector,
+		log:     logger,
+	}
+}
+
+// completionRepo returns the repo completing for the model, emulating
+// completions over chat if the model does not serve them natively.
+func (uc *useCase) completionRepo(model Model) repository.CompletionRepo {
+	if repo := model.CompletionRepo(); repo != nil {
+		return repo
+	}
+	return &chatCompletionRepo{chat: model.ChatRepo(), log: uc.log}
+}
+
+// Complete generates the continuation of the given prompt.
+func (uc *useCase) Complete(ctx context.Context, req *entity.CompleteRequest) (resp *entity.CompleteResponse, err error) {
+	model, err := uc.elector.ElectForCompletion(ctx, req)
+	if err != nil {
+		return
+	}
+	defer model.Close()
+
+	resp, err = uc.completionRepo(model).Complete(ctx, req)
+	if err != nil {
+		return
+	}
+
+	model.RecordUsage(ctx, resp.Statistics)
+	return
+}
+
+// CompleteStream generates the continuation of the given prompt, sending
+// the contents as they are generated.
+func (uc *useCase) CompleteStream(ctx context.Context, req *entity.CompleteRequest, server repository.CompletionStreamServer) error {
+	model, err := uc.elector.ElectForCompletion(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer model.Close()
+
+	var stats *v1.Statistics
+	for chunk, err := range uc.completionRepo(model).CompleteStream(ctx, req) {
+		if err != nil {
+			return err
+		}
+
+		if errors.Is(ctx.Err(), context.Canceled) {
+			break
+		}
+
+		if chunk.Statistics != nil {
+			stats = chunk.Statistics
+		}
+		err = server.Send(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	model.RecordUsage(ctx, stats)
+	return nil
+}
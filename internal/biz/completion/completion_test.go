@@ -0,0 +1,167 @@
+package completion
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// mockChatRepo answers every chat with a reasoning block and an answer.
+type mockChatRepo struct {
+	req *entity.ChatRequest
+}
+
+func (r *mockChatRepo) Chat(_ context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	r.req = req
+	return &entity.ChatResponse{
+		Model: "chat-model",
+		Message: &v1.Message{
+			Contents: []*v1.Content{
+				{Phase: v1.ContentPhase_CONTENT_PHASE_REASONING, Content: v1.NewTextContent("thinking")},
+				{Content: v1.NewTextContent(" world")},
+			},
+		},
+		Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 10, OutputTokens: 3}},
+		Status:     v1.ChatStatus_CHAT_STATUS_COMPLETED,
+	}, nil
+}
+
+func (r *mockChatRepo) ChatStream(_ context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	r.req = req
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		for _, payload := range []v1.ChatEventPayload{
+			v1.NewMessageStartEvent("msg", "chat-model"),
+			v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING),
+			v1.NewContentDeltaTextEvent(0, "thinking"),
+			v1.NewContentStopEvent(0),
+			v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL),
+			v1.NewContentDeltaTextEvent(1, " wor"),
+			v1.NewContentDeltaTextEvent(1, "ld"),
+			v1.NewContentStopEvent(1),
+			v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_COMPLETED),
+		} {
+			if !yield(v1.NewChatEvent(req.Id, payload), nil) {
+				return
+			}
+		}
+	}
+}
+
+type mockModel struct {
+	completionRepo repository.CompletionRepo
+	chatRepo       *mockChatRepo
+	recorded       *v1.Statistics
+}
+
+func (m *mockModel) CompletionRepo() repository.CompletionRepo { return m.completionRepo }
+func (m *mockModel) ChatRepo() repository.ChatRepo             { return m.chatRepo }
+func (m *mockModel) Close()                                    {}
+
+func (m *mockModel) RecordUsage(_ context.Context, stats *v1.Statistics) {
+	m.recorded = stats
+}
+
+type mockElector struct {
+	model *mockModel
+}
+
+func (e *mockElector) ElectForCompletion(_ context.Context, _ *v1.CompleteRequest) (Model, error) {
+	return e.model, nil
+}
+
+type mockStreamServer struct {
+	chunks []*entity.CompleteResponse
+}
+
+func (s *mockStreamServer) Send(chunk *entity.CompleteResponse) error {
+	s.chunks = append(s.chunks, chunk)
+	return nil
+}
+
+func TestConvertCompleteRequestToChat(t *testing.T) {
+	Convey("Test convertCompleteRequestToChat", t, func() {
+		req := &entity.CompleteRequest{
+			Id:       "cmpl",
+			Model:    "m",
+			Contents: []*v1.Content{{Content: v1.NewTextContent("Hello")}},
+		}
+
+		Convey("should instruct to continue the prompt", func() {
+			chatReq := convertCompleteRequestToChat(req)
+			So(chatReq.Id, ShouldEqual, "cmpl")
+			So(chatReq.Messages, ShouldHaveLength, 2)
+			So(chatReq.Messages[0].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+			So(chatReq.Messages[0].Contents[0].GetText().GetText(), ShouldEqual, continueInstruction)
+			So(chatReq.Messages[1].Role, ShouldEqual, v1.Role_ROLE_USER)
+			So(chatReq.Messages[1].Contents, ShouldHaveLength, 1)
+			So(chatReq.Messages[1].Contents[0].GetText().GetText(), ShouldEqual, "Hello")
+		})
+
+		Convey("should instruct to fill the gap before the suffix", func() {
+			req.Suffix = []*v1.Content{{Content: v1.NewTextContent("!")}}
+			chatReq := convertCompleteRequestToChat(req)
+			So(chatReq.Messages[0].Contents[0].GetText().GetText(), ShouldEqual, fillInstruction)
+
+			var texts []string
+			for _, c := range chatReq.Messages[1].Contents {
+				texts = append(texts, c.GetText().GetText())
+			}
+			So(texts, ShouldResemble, []string{"<prefix>", "Hello", "</prefix>\n<suffix>", "!", "</suffix>"})
+		})
+	})
+}
+
+func TestComplete(t *testing.T) {
+	Convey("Test Complete", t, func() {
+		chatRepo := &mockChatRepo{}
+		model := &mockModel{chatRepo: chatRepo}
+		uc := NewUseCase(&mockElector{model: model}, slog.Default())
+		req := &entity.CompleteRequest{
+			Id:       "cmpl",
+			Model:    "m",
+			Contents: []*v1.Content{{Content: v1.NewTextContent("Hello")}},
+		}
+
+		Convey("should emulate the completion over chat", func() {
+			resp, err := uc.Complete(context.Background(), req)
+			So(err, ShouldBeNil)
+			So(chatRepo.req, ShouldNotBeNil)
+			So(resp.Id, ShouldEqual, "cmpl")
+			So(resp.Model, ShouldEqual, "chat-model")
+			So(resp.Contents, ShouldHaveLength, 1)
+			So(resp.Contents[0].GetText().GetText(), ShouldEqual, " world")
+			So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+			So(model.recorded.GetUsage().GetOutputTokens(), ShouldEqual, 3)
+		})
+
+		Convey("should stream only the answer of the chat", func() {
+			server := &mockStreamServer{}
+			err := uc.CompleteStream(context.Background(), req, server)
+			So(err, ShouldBeNil)
+			So(server.chunks, ShouldHaveLength, 3)
+			So(server.chunks[0].Contents[0].GetText().GetText(), ShouldEqual, " wor")
+			So(server.chunks[1].Contents[0].GetText().GetText(), ShouldEqual, "ld")
+			So(server.chunks[2].Contents, ShouldBeEmpty)
+			So(server.chunks[2].Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+			for _, chunk := range server.chunks {
+				So(chunk.Id, ShouldEqual, "cmpl")
+				So(chunk.Model, ShouldEqual, "chat-model")
+			}
+		})
+
+		Convey("should prefer the native completion repo", func() {
+			native := &chatCompletionRepo{chat: &mockChatRepo{}, log: slog.Default()}
+			model.completionRepo = native
+			_, err := uc.Complete(context.Background(), req)
+			So(err, ShouldBeNil)
+			So(chatRepo.req, ShouldBeNil)
+		})
+	})
+}
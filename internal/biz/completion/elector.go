@@ -0,0 +1,35 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"context"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+type Model interface {
+	// CompletionRepo returns the repo serving completions natively, or nil if
+	// completions are emulated over ChatRepo.
+	CompletionRepo() repository.CompletionRepo
+	ChatRepo() repository.ChatRepo
+	RecordUsage(ctx context.Context, stats *v1.Statistics)
+	Close()
+}
+
+type Elector interface {
+	ElectForCompletion(ctx context.Context, req *v1.CompleteRequest) (Model, error)
+}
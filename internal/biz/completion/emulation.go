@@ -0,0 +1,136 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/chat"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+const (
+	continueInstruction = "Continue the text given by the user. " +
+		"Reply with the continuation only, without repeating the given text or adding any commentary."
+	fillInstruction = "Fill in the missing text between the <prefix> and the <suffix> given by the user. " +
+		"Reply with the missing text only, without repeating the prefix or the suffix or adding any commentary."
+)
+
+// chatCompletionRepo emulates completions over a chat model, instructing it
+// to continue the prompt, or to fill the gap before the suffix.
+type chatCompletionRepo struct {
+	chat repository.ChatRepo
+	log  *slog.Logger
+}
+
+func convertCompleteRequestToChat(req *entity.CompleteRequest) *entity.ChatRequest {
+	instruction := continueInstruction
+	contents := req.Contents
+	if len(req.Suffix) > 0 {
+		instruction = fillInstruction
+		contents = make([]*v1.Content, 0, len(req.Contents)+len(req.Suffix)+2)
+		contents = append(contents, &v1.Content{Content: v1.NewTextContent("<prefix>")})
+		contents = append(contents, req.Contents...)
+		contents = append(contents, &v1.Content{Content: v1.NewTextContent("</prefix>\n<suffix>")})
+		contents = append(contents, req.Suffix...)
+		contents = append(contents, &v1.Content{Content: v1.NewTextContent("</suffix>")})
+	}
+
+	return &entity.ChatRequest{
+		Id:     req.Id,
+		Model:  req.Model,
+		Config: req.Config,
+		Messages: []*v1.Message{
+			{
+				Role:     v1.Role_ROLE_SYSTEM,
+				Contents: []*v1.Content{{Content: v1.NewTextContent(instruction)}},
+			},
+			{
+				Role:     v1.Role_ROLE_USER,
+				Contents: contents,
+			},
+		},
+	}
+}
+
+func (r *chatCompletionRepo) Complete(ctx context.Context, req *entity.CompleteRequest) (*entity.CompleteResponse, error) {
+	chatResp, err := r.chat.Chat(ctx, convertCompleteRequestToChat(req))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &entity.CompleteResponse{
+		Id:         req.Id,
+		Model:      chatResp.Model,
+		Statistics: chatResp.Statistics,
+		Status:     chatResp.Status,
+	}
+	// Only the answer is the completion, reasoning and tool uses are dropped
+	for _, c := range chatResp.GetMessage().GetContents() {
+		if _, ok := c.Content.(*v1.Content_Text); ok && c.Phase != v1.ContentPhase_CONTENT_PHASE_REASONING {
+			resp.Contents = append(resp.Contents, c)
+		}
+	}
+	return resp, nil
+}
+
+func (r *chatCompletionRepo) CompleteStream(ctx context.Context, req *entity.CompleteRequest) iter.Seq2[*entity.CompleteResponse, error] {
+	return func(yield func(*entity.CompleteResponse, error) bool) {
+		reducer := chat.NewChatEventReducer(r.log)
+		answers := make(map[uint32]bool)
+
+		for event, err := range r.chat.ChatStream(ctx, convertCompleteRequestToChat(req)) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			reducer.Reduce(event)
+
+			switch e := event.Event.(type) {
+			case *v1.ChatEvent_ContentStart:
+				if _, ok := e.ContentStart.Content.(*v1.ContentStart_Text); ok {
+					answers[e.ContentStart.Index] = e.ContentStart.Phase != v1.ContentPhase_CONTENT_PHASE_REASONING
+				}
+			case *v1.ChatEvent_ContentDelta:
+				text, ok := e.ContentDelta.Delta.(*v1.ContentDelta_Text)
+				if !ok || !answers[e.ContentDelta.Index] {
+					continue
+				}
+				chunk := &entity.CompleteResponse{
+					Id:       req.Id,
+					Model:    reducer.Resp().Model,
+					Contents: []*v1.Content{{Content: v1.NewTextContent(text.Text)}},
+				}
+				if !yield(chunk, nil) {
+					return
+				}
+			}
+		}
+
+		resp := reducer.Resp()
+		yield(&entity.CompleteResponse{
+			Id:         req.Id,
+			Model:      resp.Model,
+			Statistics: resp.Statistics,
+			Status:     resp.Status,
+		}, nil)
+	}
+}
+
+var _ repository.CompletionRepo = (*chatCompletionRepo)(nil)
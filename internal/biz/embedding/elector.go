@@ -23,6 +23,11 @@ import (
 
 type Model interface {
 	EmbeddingRepo() repository.EmbeddingRepo
+	// MaxBatchSize returns the maximum inputs per call, or 0 if unlimited.
+	MaxBatchSize() int
+	// ReserveChunk waits until the limiters of the model admit a chunk of a
+	// split batch. The returned function charges the tokens the chunk used.
+	ReserveChunk(ctx context.Context, inputs []*v1.EmbedInput) (complete func(actualTokens int64), err error)
 	RecordUsage(ctx context.Context, actualTokens int64)
 	Close()
 }
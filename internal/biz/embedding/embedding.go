@@ -16,8 +16,15 @@ package embedding
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
 
+	"github.com/go-kratos/kratos/v3/errors"
+	"golang.org/x/sync/errgroup"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/entity"
 )
 
@@ -38,20 +45,118 @@ func NewUseCase(elector Elector, logger *slog.Logger) UseCase {
 	}
 }
 
-// Embed creates embeddings for the given contents using the specified model.
-func (uc *useCase) Embed(ctx context.Context, req *entity.EmbedRequest) (resp *entity.EmbedResponse, err error) {
+// maxConcurrentChunks bounds the chunks of a batch embedded at a time.
+const maxConcurrentChunks = 4
+
+// Embed creates embeddings for the given inputs using the specified model.
+// Batches exceeding the limit of the elected upstream are split into chunks
+// embedded concurrently by that upstream, so that all vectors come from the
+// same model. Each chunk is charged to the limiters of the model as it is
+// embedded, while the client quotas are charged for the request once.
+// Dimensions and normalization are applied to the results for upstreams that
+// do not support them.
+func (uc *useCase) Embed(ctx context.Context, req *entity.EmbedRequest) (*entity.EmbedResponse, error) {
+	// Contents are a shorthand for a single input
+	if len(req.Inputs) == 0 && len(req.Contents) > 0 {
+		req.Inputs = []*v1.EmbedInput{{Contents: req.Contents}}
+		req.Contents = nil
+	}
+
+	resp, err := uc.embed(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Id = req.Id
+	for _, e := range resp.Embeddings {
+		e.Values = truncateEmbedding(e.Values, req.GetDimensions())
+		if req.Normalize {
+			normalizeEmbedding(e.Values)
+		}
+	}
+	if len(resp.Embeddings) == 1 {
+		resp.Embedding = resp.Embeddings[0].Values
+	}
+	return resp, nil
+}
+
+func (uc *useCase) embed(ctx context.Context, req *entity.EmbedRequest) (resp *entity.EmbedResponse, err error) {
 	model, err := uc.elector.ElectForEmbedding(ctx, req)
 	if err != nil {
 		return
 	}
 	defer model.Close()
 
-	resp, err = model.EmbeddingRepo().Embed(ctx, req)
+	if size := model.MaxBatchSize(); size > 0 && len(req.Inputs) > size {
+		resp, err = uc.embedChunks(ctx, model, req, size)
+	} else {
+		resp, err = model.EmbeddingRepo().Embed(ctx, req)
+	}
 	if err != nil {
 		return
 	}
 
-	// TODO: record the actual token usage
-	model.RecordUsage(ctx, 0)
+	model.RecordUsage(ctx, int64(resp.GetUsage().GetInputTokens()))
 	return
 }
+
+// embedChunks embeds the inputs in chunks of at most size inputs concurrently
+// with model and merges the results in input order.
+func (uc *useCase) embedChunks(ctx context.Context, model Model, req *entity.EmbedRequest, size int) (*entity.EmbedResponse, error) {
+	chunks := slices.Collect(slices.Chunk(req.Inputs, size))
+	uc.log.InfoContext(ctx, "splitting embedding batch", "inputs", len(req.Inputs), "chunks", len(chunks))
+
+	resps := make([]*entity.EmbedResponse, len(chunks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentChunks)
+	for i, chunk := range chunks {
+		g.Go(func() error {
+			complete, err := model.ReserveChunk(gctx, chunk)
+			if err != nil {
+				return err
+			}
+			resp, err := model.EmbeddingRepo().Embed(gctx, &entity.EmbedRequest{
+				Id:         req.Id,
+				Model:      req.Model,
+				Dimensions: req.Dimensions,
+				Inputs:     chunk,
+				TaskType:   req.TaskType,
+				Normalize:  req.Normalize,
+			})
+			if err != nil {
+				return err
+			}
+			if len(resp.Embeddings) != len(chunk) {
+				return errors.New(
+					http.StatusBadGateway,
+					"",
+					fmt.Sprintf("upstream returned %d embeddings for %d inputs", len(resp.Embeddings), len(chunk)),
+				)
+			}
+			complete(int64(resp.GetUsage().GetInputTokens()))
+			resps[i] = resp
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	resp := &entity.EmbedResponse{Model: resps[0].Model}
+	for i, r := range resps {
+		offset := uint32(i * size)
+		for _, e := range r.Embeddings {
+			resp.Embeddings = append(resp.Embeddings, &v1.EmbeddingVector{
+				Index:  e.Index + offset,
+				Values: e.Values,
+			})
+		}
+		if r.Usage != nil {
+			if resp.Usage == nil {
+				resp.Usage = &v1.Usage{}
+			}
+			resp.Usage.InputTokens += r.Usage.InputTokens
+		}
+	}
+	return resp, nil
+}
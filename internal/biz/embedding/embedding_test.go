@@ -0,0 +1,248 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v3/errors"
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// mockEmbeddingRepo embeds each input as the length of its text.
+type mockEmbeddingRepo struct {
+	mu        sync.Mutex
+	calls     []*entity.EmbedRequest
+	err       error
+	short     bool
+	active    int
+	maxActive int
+}
+
+func (r *mockEmbeddingRepo) Embed(_ context.Context, req *entity.EmbedRequest) (*entity.EmbedResponse, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, req)
+	r.active++
+	r.maxActive = max(r.maxActive, r.active)
+	r.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+	r.mu.Lock()
+	r.active--
+	r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	inputs := req.Inputs
+	if r.short {
+		inputs = inputs[1:]
+	}
+	resp := &entity.EmbedResponse{Model: req.Model, Usage: &v1.Usage{InputTokens: uint32(len(req.Inputs))}}
+	for i, input := range inputs {
+		resp.Embeddings = append(resp.Embeddings, &v1.EmbeddingVector{
+			Index:  uint32(i),
+			Values: []float32{float32(len(input.Contents[0].GetText().GetText()))},
+		})
+	}
+	return resp, nil
+}
+
+type mockModel struct {
+	repo      *mockEmbeddingRepo
+	batchSize int
+
+	mu       sync.Mutex
+	recorded int64
+	closed   int
+	reserved int
+	charged  []int64
+}
+
+func (m *mockModel) EmbeddingRepo() repository.EmbeddingRepo { return m.repo }
+func (m *mockModel) MaxBatchSize() int                       { return m.batchSize }
+
+func (m *mockModel) ReserveChunk(_ context.Context, _ []*v1.EmbedInput) (func(int64), error) {
+	m.mu.Lock()
+	m.reserved++
+	m.mu.Unlock()
+	return func(actualTokens int64) {
+		m.mu.Lock()
+		m.charged = append(m.charged, actualTokens)
+		m.mu.Unlock()
+	}, nil
+}
+
+func (m *mockModel) Close() {
+	m.mu.Lock()
+	m.closed++
+	m.mu.Unlock()
+}
+
+func (m *mockModel) RecordUsage(_ context.Context, actualTokens int64) {
+	m.mu.Lock()
+	m.recorded += actualTokens
+	m.mu.Unlock()
+}
+
+// mockElector elects the same model and records the requested models.
+type mockElector struct {
+	model *mockModel
+
+	mu        sync.Mutex
+	requested []string
+}
+
+func (e *mockElector) ElectForEmbedding(_ context.Context, req *v1.EmbedRequest) (Model, error) {
+	e.mu.Lock()
+	e.requested = append(e.requested, req.Model)
+	e.mu.Unlock()
+
+	req.Model = "upstream-" + req.Model
+	return e.model, nil
+}
+
+// textInputs returns inputs whose text lengths are 1 to n.
+func textInputs(n int) []*v1.EmbedInput {
+	inputs := make([]*v1.EmbedInput, n)
+	text := ""
+	for i := range inputs {
+		text += "x"
+		inputs[i] = &v1.EmbedInput{Contents: []*v1.Content{{Content: v1.NewTextContent(text)}}}
+	}
+	return inputs
+}
+
+func TestEmbed(t *testing.T) {
+	Convey("Test Embed", t, func() {
+		repo := &mockEmbeddingRepo{}
+		elector := &mockElector{model: &mockModel{repo: repo}}
+		uc := NewUseCase(elector, slog.Default())
+
+		Convey("should treat contents as a single input", func() {
+			resp, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Id:       "embed",
+				Model:    "ada",
+				Contents: []*v1.Content{{Content: v1.NewTextContent("hello")}},
+			})
+			So(err, ShouldBeNil)
+			So(repo.calls, ShouldHaveLength, 1)
+			So(repo.calls[0].Inputs, ShouldHaveLength, 1)
+			So(repo.calls[0].Contents, ShouldBeEmpty)
+			So(resp.Id, ShouldEqual, "embed")
+			So(resp.Embeddings, ShouldHaveLength, 1)
+			So(resp.Embedding, ShouldResemble, []float32{5})
+		})
+
+		Convey("should embed a batch within the limit in one call", func() {
+			elector.model.batchSize = 4
+			resp, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Model:  "ada",
+				Inputs: textInputs(4),
+			})
+			So(err, ShouldBeNil)
+			So(repo.calls, ShouldHaveLength, 1)
+			So(resp.Embeddings, ShouldHaveLength, 4)
+			So(resp.Embedding, ShouldBeEmpty)
+		})
+
+		Convey("should split a batch exceeding the limit into chunks", func() {
+			elector.model.batchSize = 2
+			resp, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Id:         "batch",
+				Model:      "ada",
+				Inputs:     textInputs(5),
+				Dimensions: new(uint32(8)),
+			})
+			So(err, ShouldBeNil)
+			So(repo.calls, ShouldHaveLength, 3)
+			for _, call := range repo.calls {
+				So(len(call.Inputs), ShouldBeLessThanOrEqualTo, 2)
+				So(call.Model, ShouldEqual, "upstream-ada")
+				So(call.GetDimensions(), ShouldEqual, 8)
+			}
+
+			// The batch is elected and recorded once, and every chunk is
+			// embedded by the elected model under its limiters
+			So(elector.requested, ShouldResemble, []string{"ada"})
+			So(elector.model.closed, ShouldEqual, 1)
+			So(elector.model.reserved, ShouldEqual, 3)
+			So(elector.model.charged, ShouldHaveLength, 3)
+
+			So(resp.Id, ShouldEqual, "batch")
+			So(resp.Usage.InputTokens, ShouldEqual, 5)
+			So(elector.model.recorded, ShouldEqual, 5)
+			So(resp.Embeddings, ShouldHaveLength, 5)
+			for i, e := range resp.Embeddings {
+				So(e.Index, ShouldEqual, i)
+				So(e.Values[0], ShouldEqual, i+1)
+			}
+		})
+
+		Convey("should bound the chunks embedded concurrently", func() {
+			elector.model.batchSize = 1
+			resp, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Model:  "ada",
+				Inputs: textInputs(20),
+			})
+			So(err, ShouldBeNil)
+			So(resp.Embeddings, ShouldHaveLength, 20)
+			So(repo.calls, ShouldHaveLength, 20)
+			So(repo.maxActive, ShouldBeLessThanOrEqualTo, maxConcurrentChunks)
+		})
+
+		Convey("should not reserve chunks for a batch within the limit", func() {
+			_, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Model:  "ada",
+				Inputs: textInputs(3),
+			})
+			So(err, ShouldBeNil)
+			So(elector.model.reserved, ShouldEqual, 0)
+			So(elector.model.recorded, ShouldEqual, 3)
+		})
+
+		Convey("should fail if a chunk misses embeddings", func() {
+			elector.model.batchSize = 2
+			repo.short = true
+			_, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Model:  "ada",
+				Inputs: textInputs(3),
+			})
+			So(kerrors.Code(err), ShouldEqual, http.StatusBadGateway)
+			So(elector.model.closed, ShouldEqual, 1)
+			So(elector.model.recorded, ShouldEqual, 0)
+		})
+
+		Convey("should normalize embeddings on request", func() {
+			resp, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Model:     "ada",
+				Inputs:    textInputs(2),
+				Normalize: true,
+			})
+			So(err, ShouldBeNil)
+			So(resp.Embeddings[0].Values, ShouldResemble, []float32{1})
+			So(resp.Embeddings[1].Values, ShouldResemble, []float32{1})
+		})
+
+		Convey("should fail if any chunk fails", func() {
+			elector.model.batchSize = 2
+			repo.err = errors.New("upstream error")
+			_, err := uc.Embed(context.Background(), &entity.EmbedRequest{
+				Model:  "ada",
+				Inputs: textInputs(3),
+			})
+			So(err, ShouldNotBeNil)
+			So(elector.model.closed, ShouldEqual, 1)
+			So(elector.model.recorded, ShouldEqual, 0)
+		})
+	})
+}
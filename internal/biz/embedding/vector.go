@@ -0,0 +1,41 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedding
+
+import "math"
+
+// truncateEmbedding keeps the leading dimensions of an embedding, for
+// upstreams that return embeddings longer than requested.
+func truncateEmbedding(values []float32, dimensions uint32) []float32 {
+	if dimensions == 0 || len(values) <= int(dimensions) {
+		return values
+	}
+	return values[:dimensions]
+}
+
+// normalizeEmbedding scales an embedding to unit L2 norm in place.
+func normalizeEmbedding(values []float32) {
+	var sum float64
+	for _, v := range values {
+		sum += float64(v) * float64(v)
+	}
+	if sum == 0 {
+		return
+	}
+	norm := math.Sqrt(sum)
+	for i, v := range values {
+		values[i] = float32(float64(v) / norm)
+	}
+}
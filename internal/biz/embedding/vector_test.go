@@ -0,0 +1,56 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedding
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTruncateEmbedding(t *testing.T) {
+	Convey("Test truncateEmbedding", t, func() {
+		values := []float32{1, 2, 3, 4}
+
+		Convey("should keep the leading dimensions", func() {
+			So(truncateEmbedding(values, 2), ShouldResemble, []float32{1, 2})
+		})
+
+		Convey("should keep shorter embeddings", func() {
+			So(truncateEmbedding(values, 8), ShouldResemble, values)
+		})
+
+		Convey("should keep the embedding if dimensions are unset", func() {
+			So(truncateEmbedding(values, 0), ShouldResemble, values)
+		})
+	})
+}
+
+func TestNormalizeEmbedding(t *testing.T) {
+	Convey("Test normalizeEmbedding", t, func() {
+		Convey("should scale to unit length", func() {
+			values := []float32{3, 4}
+			normalizeEmbedding(values)
+			So(values[0], ShouldAlmostEqual, 0.6, 1e-6)
+			So(values[1], ShouldAlmostEqual, 0.8, 1e-6)
+		})
+
+		Convey("should keep zero vectors", func() {
+			values := []float32{0, 0}
+			normalizeEmbedding(values)
+			So(values, ShouldResemble, []float32{0, 0})
+		})
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entity
+
+import "context"
+
+type clientKey struct{}
+
+// NewClientContext returns a context carrying the identity of the calling
+// client, which per-client quotas are charged to.
+func NewClientContext(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the identity of the calling client, if any.
+func ClientFromContext(ctx context.Context) (client string, ok bool) {
+	client, ok = ctx.Value(clientKey{}).(string)
+	return client, ok && client != ""
+}
@@ -25,6 +25,9 @@ type ChatResponse = v1.ChatResponse
 // ChatEvent represents a streaming chat event, aliased from the API proto definition.
 type ChatEvent = v1.ChatEvent
 
+// CountTokensResponse represents a token count of a chat request, aliased from the API proto definition.
+type CountTokensResponse = v1.CountTokensResponse
+
 // ModelSpec represents a model specification, aliased from the API proto definition.
 type ModelSpec = v1.ModelSpec
 
@@ -33,3 +36,15 @@ type EmbedRequest = v1.EmbedRequest
 
 // EmbedResponse represents an embedding response, aliased from the API proto definition.
 type EmbedResponse = v1.EmbedResponse
+
+// CompleteRequest represents a text completion request, aliased from the API proto definition.
+type CompleteRequest = v1.CompleteRequest
+
+// CompleteResponse represents a text completion response or stream chunk, aliased from the API proto definition.
+type CompleteResponse = v1.CompleteResponse
+
+// LimiterGroup represents the limiters of an upstream, credential or model, aliased from the API proto definition.
+type LimiterGroup = v1.LimiterGroup
+
+// LimiterState represents a limiter snapshot, aliased from the API proto definition.
+type LimiterState = v1.LimiterState
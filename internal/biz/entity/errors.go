@@ -15,6 +15,8 @@
 package entity
 
 import (
+	"net/http"
+
 	"github.com/go-kratos/kratos/v3/errors"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
@@ -29,4 +31,40 @@ var (
 		v1.ErrorReason_ERROR_REASON_TOKEN_QUOTA_EXHAUSTED.String(),
 		"token quota exhausted",
 	)
+	ErrClientQuotaExceeded = errors.TooManyRequests(
+		v1.ErrorReason_ERROR_REASON_CLIENT_QUOTA_EXCEEDED.String(),
+		"client quota exceeded",
+	)
+	ErrLimiterNotFound = errors.NotFound(
+		v1.ErrorReason_ERROR_REASON_LIMITER_NOT_FOUND.String(),
+		"limiter not found",
+	)
+	ErrLimiterNotAdjustable = errors.BadRequest(
+		v1.ErrorReason_ERROR_REASON_LIMITER_NOT_ADJUSTABLE.String(),
+		"limiter is not adjustable",
+	)
+	ErrForbidden = errors.Forbidden(
+		v1.ErrorReason_ERROR_REASON_FORBIDDEN.String(),
+		"forbidden",
+	)
+	ErrResponseNotFound = errors.NotFound(
+		v1.ErrorReason_ERROR_REASON_RESPONSE_NOT_FOUND.String(),
+		"response not found",
+	)
+	ErrStructuredOutputInvalid = errors.New(
+		http.StatusBadGateway,
+		v1.ErrorReason_ERROR_REASON_STRUCTURED_OUTPUT_INVALID.String(),
+		"model reply does not match the requested schema",
+	)
+	ErrUpstreamUnauthorized = errors.New(
+		http.StatusBadGateway,
+		v1.ErrorReason_ERROR_REASON_UPSTREAM_UNAUTHORIZED.String(),
+		"upstream rejected the credential",
+	)
+	// ErrUpstreamTimeout reports an upstream that stalled, so the request may
+	// be retried with another one, as its metadata tells clients.
+	ErrUpstreamTimeout = errors.GatewayTimeout(
+		v1.ErrorReason_ERROR_REASON_UPSTREAM_TIMEOUT.String(),
+		"upstream timed out",
+	).WithMetadata(map[string]string{"retryable": "true"})
 )
@@ -0,0 +1,55 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitWindow describes the most constrained limiter of one unit.
+type RateLimitWindow struct {
+	Limit     int64
+	Remaining int64
+	// Reset is when the limiter regains quota, zero if it is not depleted.
+	Reset time.Time
+}
+
+// RateLimit describes the limits a request was admitted or rejected under,
+// for clients that back off on rate-limit response headers.
+type RateLimit struct {
+	Requests *RateLimitWindow
+	Tokens   *RateLimitWindow
+	// RetryAfter is set when the request was rejected and may be retried later.
+	RetryAfter time.Duration
+}
+
+// RateLimitReporter receives the rate limit of a request once it is known,
+// before any response is written.
+type RateLimitReporter func(rl *RateLimit)
+
+type rateLimitReporterKey struct{}
+
+// NewRateLimitContext returns a context carrying the reporter that the rate
+// limit of the request is reported to.
+func NewRateLimitContext(ctx context.Context, reporter RateLimitReporter) context.Context {
+	return context.WithValue(ctx, rateLimitReporterKey{}, reporter)
+}
+
+// RateLimitReporterFromContext returns the rate limit reporter, if any.
+func RateLimitReporterFromContext(ctx context.Context) (reporter RateLimitReporter, ok bool) {
+	reporter, ok = ctx.Value(rateLimitReporterKey{}).(RateLimitReporter)
+	return reporter, ok && reporter != nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package entity
+
+import (
+	"time"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+// StoredResponse is a generated response kept for stateful API clients.
+type StoredResponse struct {
+	Id string
+	// Client is the identity of the client that created the response, empty
+	// for anonymous clients. Only the same client may access the response.
+	Client string
+	// Messages is the conversation the response answers, including the
+	// conversations it continued. System instructions of the request are not
+	// part of it, as they do not carry over to continuations.
+	Messages []*v1.Message
+	// Output is the generated message.
+	Output *v1.Message
+	// Response is the API rendering of the response, returned on retrieval.
+	Response []byte
+	// InputItems is the API rendering of the request input, returned on retrieval.
+	InputItems []byte
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+}
+
+// Conversation returns the messages a continuation of the response builds on.
+func (r *StoredResponse) Conversation() []*v1.Message {
+	messages := make([]*v1.Message, 0, len(r.Messages)+1)
+	messages = append(messages, r.Messages...)
+	if r.Output != nil {
+		messages = append(messages, r.Output)
+	}
+	return messages
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// AdminUseCase exposes the live limiter state of upstreams, credentials and
+// models.
+//
+// Limiters are addressed by upstream name, model ID (empty for the
+// upstream-level group), credential name (empty unless addressing the group
+// of a pooled credential) and their index within the group, request limiters
+// first.
+type AdminUseCase interface {
+	ListLimiters(ctx context.Context) ([]*entity.LimiterGroup, error)
+	// ResetLimiter restores the full limit of a limiter, or of every
+	// adjustable limiter in the group if index is nil.
+	ResetLimiter(ctx context.Context, upstream, model, credential string, index *uint32) (*entity.LimiterGroup, error)
+	// AdjustLimiter sets the units a limiter admits from now on, clamped to
+	// its limit.
+	AdjustLimiter(ctx context.Context, upstream, model, credential string, index uint32, remaining int64) (*entity.LimiterGroup, error)
+	// EnableCredential puts a credential disabled after the upstream
+	// rejected it back into rotation.
+	EnableCredential(ctx context.Context, upstream, credential string) (*entity.LimiterGroup, error)
+}
+
+// scopedLimiterGroup is a limiter group with the scope it belongs to.
+type scopedLimiterGroup struct {
+	upstream   string
+	model      string
+	credential *credential
+	limiters   *limiterGroup
+}
+
+// limiters returns all limiters of the group in index order.
+func (g *limiterGroup) limiters() []any {
+	ls := make([]any, 0, len(g.requestLimiters)+len(g.tokenLimiters))
+	for _, l := range g.requestLimiters {
+		ls = append(ls, l)
+	}
+	for _, l := range g.tokenLimiters {
+		ls = append(ls, l)
+	}
+	return ls
+}
+
+// limiterGroups returns the upstream-level groups, each followed by the
+// groups of its credentials and of its models, in configuration order.
+func (uc *UseCaseImpl) limiterGroups() []*scopedLimiterGroup {
+	var groups []*scopedLimiterGroup
+	seen := make(map[*limiterGroup]bool)
+	models, _ := uc.snapshot()
+	for _, m := range models {
+		if !seen[m.upstreamLimiters] {
+			seen[m.upstreamLimiters] = true
+			groups = append(groups, &scopedLimiterGroup{
+				upstream: m.upstreamConfig.GetName(),
+				limiters: m.upstreamLimiters,
+			})
+		}
+		if l := m.credentialLimiters(); l != nil && !seen[l] {
+			seen[l] = true
+			groups = append(groups, &scopedLimiterGroup{
+				upstream:   m.upstreamConfig.GetName(),
+				credential: m.credential,
+				limiters:   l,
+			})
+		}
+		if !seen[m.modelLimiters] {
+			seen[m.modelLimiters] = true
+			groups = append(groups, &scopedLimiterGroup{
+				upstream: m.upstreamConfig.GetName(),
+				model:    m.config.GetId(),
+				limiters: m.modelLimiters,
+			})
+		}
+	}
+	return groups
+}
+
+// credentialName returns the name of the credential of the group, if any.
+func (g *scopedLimiterGroup) credentialName() string {
+	if g.credential == nil {
+		return ""
+	}
+	return g.credential.name
+}
+
+// findLimiterGroup returns the group of the given scope.
+func (uc *UseCaseImpl) findLimiterGroup(upstream, model, credential string) (*scopedLimiterGroup, error) {
+	for _, g := range uc.limiterGroups() {
+		if g.upstream == upstream && g.model == model && g.credentialName() == credential {
+			return g, nil
+		}
+	}
+	return nil, entity.ErrLimiterNotFound
+}
+
+// snapshot converts the state of the group for the API.
+func (g *scopedLimiterGroup) snapshot() *entity.LimiterGroup {
+	group := &entity.LimiterGroup{
+		Upstream:   g.upstream,
+		Model:      g.model,
+		Credential: g.credentialName(),
+		Disabled:   g.credential.isDisabled(),
+		ProbeDelay: durationpb.New(g.limiters.probeDelay(1)),
+	}
+	for i, l := range g.limiters.limiters() {
+		state := &entity.LimiterState{Index: uint32(i)}
+
+		if il, ok := l.(repository.InspectableLimiter); ok {
+			s := il.Inspect()
+			state.Type = s.Type
+			state.Unit = s.Unit
+			state.Limit = s.Limit
+			state.Remaining = s.Remaining
+			state.InFlight = s.InFlight
+			state.Waiters = s.Waiters
+			if s.Window > 0 {
+				state.Window = durationpb.New(s.Window)
+			}
+			if !s.NextReset.IsZero() {
+				state.NextReset = timestamppb.New(s.NextReset)
+			}
+		}
+
+		var delay time.Duration
+		switch l := l.(type) {
+		case repository.RequestLimiter:
+			delay = l.Probe()
+		case repository.TokenLimiter:
+			delay = l.Probe(1)
+		}
+		state.ProbeDelay = durationpb.New(delay)
+
+		_, state.Adjustable = l.(repository.AdjustableLimiter)
+		group.Limiters = append(group.Limiters, state)
+	}
+	return group
+}
+
+// adjustable returns the limiter at index if it can be adjusted.
+func (g *scopedLimiterGroup) adjustable(index uint32) (repository.AdjustableLimiter, int64, error) {
+	ls := g.limiters.limiters()
+	if int(index) >= len(ls) {
+		return nil, 0, entity.ErrLimiterNotFound
+	}
+	al, ok := ls[index].(repository.AdjustableLimiter)
+	if !ok {
+		return nil, 0, entity.ErrLimiterNotAdjustable
+	}
+	var limit int64
+	if il, ok := ls[index].(repository.InspectableLimiter); ok {
+		limit = il.Inspect().Limit
+	}
+	return al, limit, nil
+}
+
+func (uc *UseCaseImpl) ListLimiters(_ context.Context) ([]*entity.LimiterGroup, error) {
+	groups := uc.limiterGroups()
+	result := make([]*entity.LimiterGroup, len(groups))
+	for i, g := range groups {
+		result[i] = g.snapshot()
+	}
+	return result, nil
+}
+
+func (uc *UseCaseImpl) ResetLimiter(ctx context.Context, upstream, model, credential string, index *uint32) (*entity.LimiterGroup, error) {
+	g, err := uc.findLimiterGroup(upstream, model, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	if index != nil {
+		al, limit, err := g.adjustable(*index)
+		if err != nil {
+			return nil, err
+		}
+		al.SetRemaining(limit)
+		uc.log.InfoContext(ctx, "limiter reset", "upstream", upstream, "model", model, "credential", credential, "index", *index)
+	} else {
+		for _, l := range g.limiters.limiters() {
+			al, ok := l.(repository.AdjustableLimiter)
+			il, inspectable := l.(repository.InspectableLimiter)
+			if ok && inspectable {
+				al.SetRemaining(il.Inspect().Limit)
+			}
+		}
+		uc.log.InfoContext(ctx, "limiter group reset", "upstream", upstream, "model", model, "credential", credential)
+	}
+
+	return g.snapshot(), nil
+}
+
+func (uc *UseCaseImpl) AdjustLimiter(ctx context.Context, upstream, model, credential string, index uint32, remaining int64) (*entity.LimiterGroup, error) {
+	g, err := uc.findLimiterGroup(upstream, model, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	al, limit, err := g.adjustable(index)
+	if err != nil {
+		return nil, err
+	}
+	al.SetRemaining(min(max(remaining, 0), limit))
+
+	uc.log.InfoContext(ctx, "limiter adjusted", "upstream", upstream, "model", model, "credential", credential, "index", index, "remaining", remaining)
+	return g.snapshot(), nil
+}
+
+func (uc *UseCaseImpl) EnableCredential(ctx context.Context, upstream, credential string) (*entity.LimiterGroup, error) {
+	g, err := uc.findLimiterGroup(upstream, "", credential)
+	if err != nil {
+		return nil, err
+	}
+	if g.credential == nil {
+		return nil, entity.ErrLimiterNotFound
+	}
+
+	if g.credential.enable() {
+		uc.log.InfoContext(ctx, "credential enabled", "upstream", upstream, "credential", credential)
+	}
+	return g.snapshot(), nil
+}
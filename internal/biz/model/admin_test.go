@@ -0,0 +1,165 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func makeAdminUseCase() *UseCaseImpl {
+	upstreamLimiters := newLimiterGroup(2, 0, 0, 0, 0)
+	m1 := makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+	m1.upstreamLimiters = upstreamLimiters
+	m1.modelLimiters = newLimiterGroup(0, 60, 0, 1000, 0)
+	m2 := makeModel("mini", "gpt-4o-mini", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+	m2.upstreamLimiters = upstreamLimiters
+	return &UseCaseImpl{
+		models: []*model{m1, m2},
+		log:    slog.Default(),
+	}
+}
+
+func TestListLimiters(t *testing.T) {
+	Convey("Test ListLimiters", t, func() {
+		uc := makeAdminUseCase()
+
+		groups, err := uc.ListLimiters(context.Background())
+		So(err, ShouldBeNil)
+		So(groups, ShouldHaveLength, 3)
+
+		Convey("should list the shared upstream group once, first", func() {
+			So(groups[0].Upstream, ShouldEqual, "openai")
+			So(groups[0].Model, ShouldBeEmpty)
+			So(groups[0].Limiters, ShouldHaveLength, 1)
+			So(groups[0].Limiters[0].Type, ShouldEqual, v1.LimiterType_LIMITER_TYPE_CONCURRENCY)
+			So(groups[0].Limiters[0].Adjustable, ShouldBeFalse)
+			So(groups[1].Model, ShouldEqual, "gpt")
+			So(groups[2].Model, ShouldEqual, "mini")
+			So(groups[2].Limiters, ShouldBeEmpty)
+		})
+
+		Convey("should index request limiters before token limiters", func() {
+			ls := groups[1].Limiters
+			So(ls, ShouldHaveLength, 2)
+			So(ls[0].Index, ShouldEqual, 0)
+			So(ls[0].Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_REQUEST)
+			So(ls[0].Limit, ShouldEqual, 60)
+			So(ls[1].Index, ShouldEqual, 1)
+			So(ls[1].Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_TOKEN)
+			So(ls[1].Adjustable, ShouldBeTrue)
+			So(groups[1].ProbeDelay.AsDuration(), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestAdjustAndResetLimiter(t *testing.T) {
+	Convey("Test AdjustLimiter and ResetLimiter", t, func() {
+		uc := makeAdminUseCase()
+		ctx := context.Background()
+
+		Convey("should drain a limiter", func() {
+			group, err := uc.AdjustLimiter(ctx, "openai", "gpt", "", 0, 0)
+			So(err, ShouldBeNil)
+			So(group.Limiters[0].Remaining, ShouldEqual, 0)
+			So(group.Limiters[0].ProbeDelay.AsDuration(), ShouldBeGreaterThan, 0)
+			So(group.ProbeDelay.AsDuration(), ShouldBeGreaterThan, 0)
+
+			Convey("and reset it", func() {
+				group, err := uc.ResetLimiter(ctx, "openai", "gpt", "", new(uint32(0)))
+				So(err, ShouldBeNil)
+				So(group.Limiters[0].Remaining, ShouldEqual, 60)
+			})
+
+			Convey("and reset the whole group", func() {
+				_, err := uc.AdjustLimiter(ctx, "openai", "gpt", "", 1, 10)
+				So(err, ShouldBeNil)
+				group, err := uc.ResetLimiter(ctx, "openai", "gpt", "", nil)
+				So(err, ShouldBeNil)
+				So(group.Limiters[0].Remaining, ShouldEqual, 60)
+				So(group.Limiters[1].Remaining, ShouldEqual, 1000)
+			})
+		})
+
+		Convey("should clamp the remaining units to the limit", func() {
+			group, err := uc.AdjustLimiter(ctx, "openai", "gpt", "", 1, 5000)
+			So(err, ShouldBeNil)
+			So(group.Limiters[1].Remaining, ShouldEqual, 1000)
+		})
+
+		Convey("should reject unknown scopes and indexes", func() {
+			_, err := uc.AdjustLimiter(ctx, "openai", "unknown", "", 0, 0)
+			So(errors.Is(err, entity.ErrLimiterNotFound), ShouldBeTrue)
+			_, err = uc.AdjustLimiter(ctx, "openai", "gpt", "", 2, 0)
+			So(errors.Is(err, entity.ErrLimiterNotFound), ShouldBeTrue)
+			_, err = uc.ResetLimiter(ctx, "anthropic", "", "", nil)
+			So(errors.Is(err, entity.ErrLimiterNotFound), ShouldBeTrue)
+		})
+
+		Convey("should reject limiters that cannot be adjusted", func() {
+			_, err := uc.AdjustLimiter(ctx, "openai", "", "", 0, 0)
+			So(errors.Is(err, entity.ErrLimiterNotAdjustable), ShouldBeTrue)
+
+			group, err := uc.ResetLimiter(ctx, "openai", "", "", nil)
+			So(err, ShouldBeNil)
+			So(group.Limiters[0].Remaining, ShouldEqual, 2)
+		})
+	})
+}
+
+func TestCredentialLimiters(t *testing.T) {
+	Convey("Test credential limiters", t, func() {
+		ctx := context.Background()
+		uc := makeAdminUseCase()
+		a := uc.models[0]
+		a.credential = &credential{name: "a", limiters: newLimiterGroup(0, 10, 0, 0, 0)}
+		b := makeModel("gpt", "gpt-4", a.config.Capabilities)
+		b.config = a.config
+		b.upstreamLimiters = a.upstreamLimiters
+		b.modelLimiters = a.modelLimiters
+		b.credential = &credential{name: "b", limiters: newLimiterGroup(0, 20, 0, 0, 0)}
+		b.credential.disabled.Store(true)
+		uc.models = []*model{a, b}
+
+		groups, err := uc.ListLimiters(ctx)
+		So(err, ShouldBeNil)
+		So(groups, ShouldHaveLength, 4)
+
+		Convey("should list each credential once, with model groups shared", func() {
+			So(groups[1].Credential, ShouldEqual, "a")
+			So(groups[1].Model, ShouldBeEmpty)
+			So(groups[1].Disabled, ShouldBeFalse)
+			So(groups[2].Model, ShouldEqual, "gpt")
+			So(groups[3].Credential, ShouldEqual, "b")
+			So(groups[3].Disabled, ShouldBeTrue)
+		})
+
+		Convey("should address the limiters of a credential", func() {
+			group, err := uc.AdjustLimiter(ctx, "openai", "", "b", 0, 5)
+			So(err, ShouldBeNil)
+			So(group.Credential, ShouldEqual, "b")
+			So(group.Limiters[0].Remaining, ShouldEqual, 5)
+
+			_, err = uc.ResetLimiter(ctx, "openai", "", "c", nil)
+			So(errors.Is(err, entity.ErrLimiterNotFound), ShouldBeTrue)
+		})
+
+		Convey("should enable a disabled credential", func() {
+			group, err := uc.EnableCredential(ctx, "openai", "b")
+			So(err, ShouldBeNil)
+			So(group.Credential, ShouldEqual, "b")
+			So(group.Disabled, ShouldBeFalse)
+			So(probeModelDelay(b, 0), ShouldBeLessThan, repository.InfDuration)
+
+			_, err = uc.EnableCredential(ctx, "openai", "")
+			So(errors.Is(err, entity.ErrLimiterNotFound), ShouldBeTrue)
+		})
+	})
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"context"
+	"encoding/json"
+	"log/slog"
 	"slices"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
@@ -13,13 +15,53 @@ import (
 
 type chatModel struct {
 	*model
-	reservations    *reservationSet
-	estimatedTokens int64
+	reservations *reservationSet
+	// clientReservations hold the quota of the calling client, which is
+	// charged the unweighted tokens of the request, so that the same usage
+	// costs a client the same whichever upstream serves it.
+	clientReservations *reservationSet
+	estimatedTokens    int64
+}
+
+func (m *chatModel) ChatRepo() repository.ChatRepo { return m.observedChatRepo() }
+
+// EmulatedToolUse reports whether the model is configured to have its tool
+// calls emulated.
+func (m *chatModel) EmulatedToolUse() bool {
+	return m.config.GetToolUse().GetEmulation() == conf.ToolUse_EMULATION_HERMES
+}
+
+// StructuredOutput returns the configured emulation of models that do not
+// follow schemas natively.
+func (m *chatModel) StructuredOutput() chat.StructuredOutput {
+	if slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_STRUCTURED_OUTPUT) {
+		return chat.StructuredOutput{}
+	}
+
+	cfg := m.config.GetStructuredOutput()
+	so := chat.StructuredOutput{MaxRetries: int(cfg.GetMaxRetries())}
+	switch cfg.GetEmulation() {
+	case conf.StructuredOutput_EMULATION_TOOL:
+		so.Emulation = chat.StructuredOutputTool
+	case conf.StructuredOutput_EMULATION_INSTRUCTION:
+		so.Emulation = chat.StructuredOutputInstruction
+	}
+	return so
+}
+
+// Timeouts returns the configured timeouts of chat requests to the model.
+func (m *chatModel) Timeouts() chat.Timeouts {
+	cfg := m.config.GetTimeouts()
+	return chat.Timeouts{
+		FirstEvent: cfg.GetFirstEvent().AsDuration(),
+		Idle:       cfg.GetIdle().AsDuration(),
+		Total:      cfg.GetTotal().AsDuration(),
+	}
 }
 
-func (m *chatModel) ChatRepo() repository.ChatRepo { return m.chatRepo }
 func (m *chatModel) RecordUsage(ctx context.Context, stats *v1.Statistics) {
 	actualTokens := m.estimatedTokens // Default to estimated tokens
+	clientTokens := m.estimatedTokens
 
 	if stats != nil && stats.Usage != nil {
 		inputTokens := int64(stats.Usage.InputTokens)
@@ -42,10 +84,11 @@ func (m *chatModel) RecordUsage(ctx context.Context, stats *v1.Statistics) {
 			reasoningTokens,
 		)
 
-		tokenUsage := int64(stats.Usage.InputTokens + stats.Usage.OutputTokens)
-		// If upstream provides usage info, use actual tokens
-		if tokenUsage > 0 {
-			actualTokens = tokenUsage
+		// If upstream provides usage info, use actual tokens, weighted as the
+		// upstream meters them
+		if inputTokens+outputTokens > 0 {
+			actualTokens = weightedTokens(stats.Usage, m.upstreamConfig.GetScheduling().GetTokenWeights())
+			clientTokens = inputTokens + outputTokens
 		}
 	}
 
@@ -53,10 +96,16 @@ func (m *chatModel) RecordUsage(ctx context.Context, stats *v1.Statistics) {
 
 	// Complete reservations with actual or estimated token usage
 	m.reservations.complete(actualTokens)
+	if m.clientReservations != nil {
+		m.clientReservations.complete(clientTokens)
+	}
 }
 
 func (m *chatModel) Close() {
 	m.reservations.cancel()
+	if m.clientReservations != nil {
+		m.clientReservations.cancel()
+	}
 }
 
 // estimateTokens provides a rough token estimate for a chat request.
@@ -100,38 +149,83 @@ func estimateTokens(req *v1.ChatRequest) int64 {
 	return textTokens + imageTokens
 }
 
-func (uc *UseCaseImpl) ElectForChat(ctx context.Context, req *v1.ChatRequest) (chat.Model, error) {
-	estimatedTokens := estimateTokens(req) // Estimate input tokens roughly: ~4 chars per token
-	estimatedTokens += 512                 // Add some buffer for output tokens
+// estimateCountTokens provides the local estimate of the input tokens of a
+// chat request for token counting. Besides the messages estimateTokens
+// counts, it counts the tool definitions, whose schemas may make up much of
+// the input, and the tool choice. Reservations leave them out, as they are
+// only a rough bound reconciled with the usage reported once the request
+// completes.
+func estimateCountTokens(req *v1.ChatRequest) int64 {
+	totalChars := 0
+	for _, tool := range req.Tools {
+		fn := tool.GetFunction()
+		totalChars += len(fn.GetName()) + len(fn.GetDescription())
+		if fn.GetInputSchema() != nil {
+			if schema, err := json.Marshal(fn.GetInputSchema().AsMap()); err == nil {
+				totalChars += len(schema)
+			}
+		}
+	}
+	totalChars += len(req.ToolChoice.GetName())
+	for _, name := range req.ToolChoice.GetAllowedNames() {
+		totalChars += len(name)
+	}
 
-	// Collect all available candidates
-	var allCandidates []*model
-	var matchingCandidates []*model
+	tokens := estimateTokens(req)
+	if totalChars > 0 {
+		tokens += int64(totalChars/4) + 1
+	}
+	return tokens
+}
 
-	for _, m := range uc.models {
+// chatCandidates returns all chat models, and those matching the requested
+// model by ID or alias.
+func (uc *UseCaseImpl) chatCandidates(requested string) (all, matching []*model) {
+	models, aliases := uc.snapshot()
+	for _, m := range models {
 		if m.chatRepo == nil || !slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_CHAT) {
 			continue
 		}
-		allCandidates = append(allCandidates, m)
-		if m.config.Id == req.Model {
-			matchingCandidates = append(matchingCandidates, m)
+		all = append(all, m)
+		if m.config.Id == requested {
+			matching = append(matching, m)
 		}
 	}
 
-	if a := uc.aliases[req.Model]; a != nil {
+	if a := aliases[requested]; a != nil {
 		for _, m := range a.models {
 			if m.chatRepo == nil || !slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_CHAT) {
 				continue
 			}
-			if !slices.Contains(matchingCandidates, m) {
-				matchingCandidates = append(matchingCandidates, m)
+			if !slices.Contains(matching, m) {
+				matching = append(matching, m)
 			}
 		}
 	}
+	return
+}
+
+func (uc *UseCaseImpl) ElectForChat(ctx context.Context, req *v1.ChatRequest) (_ chat.Model, err error) {
+	estimatedTokens := estimateTokens(req) // Estimate input tokens roughly: ~4 chars per token
+	estimatedTokens += 512                 // Add some buffer for output tokens
+
+	// Client quotas are charged before election, so that a client over its
+	// limits never holds upstream capacity.
+	clientReservations, err := uc.clientQuotas.reserve(ctx, estimatedTokens)
+	if err != nil {
+		uc.reportClientQuotaExceeded(ctx, estimatedTokens)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			clientReservations.cancel()
+		}
+	}()
+
+	allCandidates, matchingCandidates := uc.chatCandidates(req.Model)
 
 	var selected *model
 	var rs *reservationSet
-	var err error
 
 	// If there are matching models, randomly select from them
 	if len(matchingCandidates) > 0 {
@@ -162,6 +256,8 @@ func (uc *UseCaseImpl) ElectForChat(ctx context.Context, req *v1.ChatRequest) (c
 		return nil, entity.ErrNoUpstream
 	}
 
+	uc.reportRateLimit(ctx, selected)
+
 	// Update request model to upstream ID
 	if selected.config.UpstreamId != "" {
 		req.Model = selected.config.UpstreamId
@@ -170,8 +266,114 @@ func (uc *UseCaseImpl) ElectForChat(ctx context.Context, req *v1.ChatRequest) (c
 	}
 
 	return &chatModel{
-		model:           selected,
-		reservations:    rs,
-		estimatedTokens: estimatedTokens,
+		model:              selected,
+		reservations:       rs,
+		clientReservations: clientReservations,
+		estimatedTokens:    estimatedTokens,
 	}, nil
 }
+
+// tokenCounter counts tokens with the native counting of its model's upstream,
+// falling back to the local estimate. reservations is nil when no counting
+// upstream was elected, in which case the tokens are only estimated.
+type tokenCounter struct {
+	*model
+	reservations *reservationSet
+	log          *slog.Logger
+}
+
+func (c *tokenCounter) CountTokens(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error) {
+	resp := &v1.CountTokensResponse{Model: c.config.Id}
+
+	if c.reservations != nil {
+		repo := c.chatRepo.(repository.TokenCountingRepo)
+		tokens, err := repo.CountTokens(ctx, req)
+		c.reservations.complete(0)
+		c.credential.observe(ctx, err)
+		if err == nil {
+			resp.InputTokens = uint32(tokens)
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		c.log.WarnContext(
+			ctx,
+			"native token counting failed, falling back to estimate",
+			"upstream", c.upstreamConfig.Name,
+			"model", c.config.Id,
+			"error", err,
+		)
+	}
+
+	resp.InputTokens = uint32(estimateCountTokens(req))
+	resp.Estimated = true
+	return resp, nil
+}
+
+// ElectForTokenCount selects a model to count the tokens of the request with.
+// Models whose upstream counts natively are elected through the limiters like
+// any other request; when none of them is available, the tokens are estimated
+// locally instead.
+func (uc *UseCaseImpl) ElectForTokenCount(ctx context.Context, req *v1.ChatRequest) (chat.TokenCounter, error) {
+	all, matching := uc.chatCandidates(req.Model)
+	candidates := matching
+	if len(candidates) == 0 {
+		candidates = all
+	}
+	if len(candidates) == 0 {
+		return nil, entity.ErrNoUpstream
+	}
+
+	clientReservations, err := uc.clientQuotas.reserve(ctx, 0)
+	if err != nil {
+		uc.reportClientQuotaExceeded(ctx, 0)
+		return nil, err
+	}
+
+	counting := slices.DeleteFunc(slices.Clone(candidates), func(m *model) bool {
+		_, ok := m.chatRepo.(repository.TokenCountingRepo)
+		return !ok
+	})
+	var selected *model
+	var rs *reservationSet
+	if len(counting) > 0 {
+		selected, rs, err = electFromCandidates(ctx, counting, 0)
+		if err != nil {
+			if ctx.Err() != nil {
+				clientReservations.cancel()
+				return nil, ctx.Err()
+			}
+			uc.log.DebugContext(ctx, "no model available for token counting, estimating", "error", err)
+		}
+	}
+
+	if rs != nil {
+		rs.merge(clientReservations)
+		uc.log.DebugContext(
+			ctx,
+			"selected model for token counting",
+			"upstream", selected.upstreamConfig.Name,
+			"model", selected.config.Id,
+		)
+	} else {
+		// Estimating calls no upstream, so only the client quota is charged
+		clientReservations.complete(0)
+
+		// Models of rejected credentials are left out unless nothing else is left
+		if usable := slices.DeleteFunc(slices.Clone(candidates), func(m *model) bool {
+			return m.credential.isDisabled()
+		}); len(usable) > 0 {
+			candidates = usable
+		}
+		selected = candidates[0]
+	}
+
+	if selected.config.UpstreamId != "" {
+		req.Model = selected.config.UpstreamId
+	} else {
+		req.Model = selected.config.Id
+	}
+
+	return &tokenCounter{model: selected, reservations: rs, log: uc.log}, nil
+}
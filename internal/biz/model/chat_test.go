@@ -8,6 +8,8 @@ import (
 	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/chat"
@@ -150,6 +152,32 @@ func TestEstimateTokens(t *testing.T) {
 	})
 }
 
+func TestEstimateCountTokens(t *testing.T) {
+	Convey("Test estimateCountTokens", t, func() {
+		Convey("without tools should match estimateTokens", func() {
+			req := &v1.ChatRequest{
+				Messages: []*v1.Message{{Contents: []*v1.Content{{Content: v1.NewTextContent("Hello, world!")}}}},
+			}
+			So(estimateCountTokens(req), ShouldEqual, estimateTokens(req))
+		})
+
+		Convey("should count the tool definitions and the tool choice", func() {
+			schema, _ := structpb.NewStruct(map[string]any{"type": "object"})
+			req := &v1.ChatRequest{
+				Tools: []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{
+					Name:        "get_weather",
+					Description: "Get the weather",
+					InputSchema: schema,
+				}}}},
+				ToolChoice: &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL, Name: "get_weather"},
+			}
+			// 11 + 15 + 17 ({"type":"object"}) + 11 characters
+			So(estimateCountTokens(req), ShouldEqual, 54/4+1)
+			So(estimateTokens(req), ShouldEqual, 0)
+		})
+	})
+}
+
 func TestChatModel_ChatRepo(t *testing.T) {
 	Convey("Test chatModel ChatRepo", t, func() {
 		Convey("should return the chat repo", func() {
@@ -170,6 +198,67 @@ func TestChatModel_ChatRepo(t *testing.T) {
 	})
 }
 
+func TestChatModel_EmulatedToolUse(t *testing.T) {
+	Convey("Test chatModel EmulatedToolUse", t, func() {
+		m := &chatModel{model: makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})}
+		So(m.EmulatedToolUse(), ShouldBeFalse)
+
+		m.config.ToolUse = &conf.ToolUse{Emulation: conf.ToolUse_EMULATION_HERMES}
+		So(m.EmulatedToolUse(), ShouldBeTrue)
+	})
+}
+
+func TestChatModel_StructuredOutput(t *testing.T) {
+	Convey("Test chatModel StructuredOutput", t, func() {
+		cfg := &conf.Model{
+			StructuredOutput: &conf.StructuredOutput{
+				Emulation:  conf.StructuredOutput_EMULATION_INSTRUCTION,
+				MaxRetries: 2,
+			},
+		}
+		m := &chatModel{model: &model{config: cfg}}
+
+		Convey("should return the configured emulation", func() {
+			So(m.StructuredOutput(), ShouldResemble, chat.StructuredOutput{
+				Emulation:  chat.StructuredOutputInstruction,
+				MaxRetries: 2,
+			})
+		})
+
+		Convey("should not emulate for models following schemas natively", func() {
+			cfg.Capabilities = []conf.Capability{conf.Capability_CAPABILITY_STRUCTURED_OUTPUT}
+			So(m.StructuredOutput().Emulation, ShouldEqual, chat.StructuredOutputNative)
+		})
+
+		Convey("should not emulate unless configured", func() {
+			cfg.StructuredOutput = nil
+			So(m.StructuredOutput().Emulation, ShouldEqual, chat.StructuredOutputNative)
+		})
+	})
+}
+
+func TestChatModel_Timeouts(t *testing.T) {
+	Convey("Test chatModel Timeouts", t, func() {
+		Convey("should return the configured timeouts", func() {
+			m := &chatModel{model: &model{config: &conf.Model{
+				Timeouts: &conf.Timeouts{
+					FirstEvent: durationpb.New(30 * time.Second),
+					Idle:       durationpb.New(10 * time.Second),
+				},
+			}}}
+			So(m.Timeouts(), ShouldResemble, chat.Timeouts{
+				FirstEvent: 30 * time.Second,
+				Idle:       10 * time.Second,
+			})
+		})
+
+		Convey("should return no timeouts unless configured", func() {
+			m := &chatModel{model: &model{config: &conf.Model{}}}
+			So(m.Timeouts(), ShouldResemble, chat.Timeouts{})
+		})
+	})
+}
+
 func TestChatModel_RecordUsage(t *testing.T) {
 	Convey("Test chatModel RecordUsage", t, func() {
 		Convey("with nil stats should complete with estimated tokens", func() {
@@ -272,6 +361,37 @@ func TestChatModel_RecordUsage(t *testing.T) {
 			So(tpmLimiter.Probe(9700), ShouldEqual, time.Duration(0))
 		})
 
+		Convey("should weight token usage by the upstream token weights", func() {
+			tpdLimiter := local.NewDailyTokenLimiter(1000)
+			r, _ := tpdLimiter.Reserve(500)
+
+			m := &chatModel{
+				model: &model{
+					config: &conf.Model{Id: "test"},
+					upstreamConfig: &conf.UpstreamConfig{
+						Name: "test",
+						Scheduling: &conf.UpstreamScheduling{
+							TokenWeights: &conf.TokenWeights{CachedInput: new(0.0)},
+						},
+					},
+				},
+				reservations: &reservationSet{
+					tokenReservations: []repository.TokenReservation{r},
+				},
+			}
+
+			m.RecordUsage(context.Background(), &v1.Statistics{
+				Usage: &v1.Usage{
+					InputTokens:       800,
+					OutputTokens:      100,
+					CachedInputTokens: 700,
+				},
+			})
+
+			So(tpdLimiter.Probe(800), ShouldEqual, time.Duration(0))
+			So(tpdLimiter.Probe(801), ShouldBeGreaterThan, 0)
+		})
+
 		Convey("should record OTel token and request metrics when usage exists", func() {
 			metrics, reader := newTestMetrics()
 
@@ -556,3 +676,127 @@ func TestElectForChat(t *testing.T) {
 		})
 	})
 }
+
+func TestElectForTokenCount(t *testing.T) {
+	Convey("Test ElectForTokenCount", t, func() {
+		ctx := context.Background()
+		textReq := func(model string) *v1.ChatRequest {
+			return &v1.ChatRequest{
+				Model: model,
+				Messages: []*v1.Message{
+					{Role: v1.Role_ROLE_USER, Contents: []*v1.Content{{Content: v1.NewTextContent("0123456789")}}},
+				},
+			}
+		}
+
+		Convey("with no models should return error", func() {
+			uc := &UseCaseImpl{log: slog.Default()}
+			_, err := uc.ElectForTokenCount(ctx, textReq("gpt"))
+			So(errors.Is(err, entity.ErrNoUpstream), ShouldBeTrue)
+		})
+
+		Convey("should estimate when the upstream cannot count natively", func() {
+			m := makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			req := textReq("gpt")
+			counter, err := uc.ElectForTokenCount(ctx, req)
+			So(err, ShouldBeNil)
+			So(req.Model, ShouldEqual, "gpt-4")
+
+			resp, err := counter.CountTokens(ctx, req)
+			So(err, ShouldBeNil)
+			So(resp.Model, ShouldEqual, "gpt")
+			So(resp.InputTokens, ShouldEqual, 3)
+			So(resp.Estimated, ShouldBeTrue)
+		})
+
+		Convey("should prefer a matching model that counts natively", func() {
+			m1 := makeModel("claude", "claude-a", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m2 := makeModel("claude", "claude-b", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m2.chatRepo = &mockTokenCountingRepo{tokens: 42}
+			uc := &UseCaseImpl{models: []*model{m1, m2}, log: slog.Default()}
+
+			req := textReq("claude")
+			counter, err := uc.ElectForTokenCount(ctx, req)
+			So(err, ShouldBeNil)
+			So(req.Model, ShouldEqual, "claude-b")
+
+			resp, err := counter.CountTokens(ctx, req)
+			So(err, ShouldBeNil)
+			So(resp.InputTokens, ShouldEqual, 42)
+			So(resp.Estimated, ShouldBeFalse)
+		})
+
+		Convey("should fall back to the estimate when native counting fails", func() {
+			m := makeModel("claude", "claude-a", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m.chatRepo = &mockTokenCountingRepo{err: errors.New("upstream error")}
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			req := textReq("claude")
+			counter, err := uc.ElectForTokenCount(ctx, req)
+			So(err, ShouldBeNil)
+
+			resp, err := counter.CountTokens(ctx, req)
+			So(err, ShouldBeNil)
+			So(resp.InputTokens, ShouldEqual, 3)
+			So(resp.Estimated, ShouldBeTrue)
+		})
+
+		Convey("should not consume limiter quota when estimating", func() {
+			m := makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m.upstreamLimiters = &limiterGroup{
+				requestLimiters: []repository.RequestLimiter{local.NewConcurrencyLimiter(1)},
+			}
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			for range 3 {
+				_, err := uc.ElectForTokenCount(ctx, textReq("gpt"))
+				So(err, ShouldBeNil)
+			}
+			So(m.upstreamLimiters.probeDelay(1), ShouldEqual, 0)
+		})
+
+		Convey("should hold the limiters of a counting model until counted", func() {
+			m := makeModel("claude", "claude-a", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m.chatRepo = &mockTokenCountingRepo{tokens: 42}
+			m.upstreamLimiters = &limiterGroup{
+				requestLimiters: []repository.RequestLimiter{local.NewConcurrencyLimiter(1)},
+			}
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			counter, err := uc.ElectForTokenCount(ctx, textReq("claude"))
+			So(err, ShouldBeNil)
+			So(m.upstreamLimiters.probeDelay(0), ShouldBeGreaterThan, 0)
+
+			waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+			_, err = uc.ElectForTokenCount(waitCtx, textReq("claude"))
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+
+			resp, err := counter.CountTokens(ctx, textReq("claude"))
+			So(err, ShouldBeNil)
+			So(resp.InputTokens, ShouldEqual, 42)
+			So(m.upstreamLimiters.probeDelay(0), ShouldEqual, 0)
+		})
+
+		Convey("should balance among counting models", func() {
+			m1 := makeModel("claude", "claude-a", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m1.chatRepo = &mockTokenCountingRepo{tokens: 1}
+			m2 := makeModel("claude", "claude-b", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m2.chatRepo = &mockTokenCountingRepo{tokens: 2}
+			uc := &UseCaseImpl{models: []*model{m1, m2}, log: slog.Default()}
+
+			selected := map[string]bool{}
+			for range 100 {
+				req := textReq("claude")
+				counter, err := uc.ElectForTokenCount(ctx, req)
+				So(err, ShouldBeNil)
+				_, err = counter.CountTokens(ctx, req)
+				So(err, ShouldBeNil)
+				selected[req.Model] = true
+			}
+			So(selected, ShouldHaveLength, 2)
+		})
+	})
+}
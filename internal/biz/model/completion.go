@@ -0,0 +1,151 @@
+package model
+
+import (
+	"context"
+	"slices"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/completion"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+type completionModel struct {
+	*chatModel
+}
+
+// CompletionRepo returns the completion repo of models that complete natively.
+func (m *completionModel) CompletionRepo() repository.CompletionRepo {
+	if !m.completesNatively() {
+		return nil
+	}
+	return m.observedCompletionRepo()
+}
+
+// completesNatively reports whether the upstream serves completions for the model.
+func (m *model) completesNatively() bool {
+	return m.completionRepo != nil && slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_COMPLETION)
+}
+
+// canComplete reports whether the model serves completions, either natively or
+// emulated over chat.
+func (m *model) canComplete() bool {
+	if m.completesNatively() {
+		return true
+	}
+	return m.chatRepo != nil && (slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_COMPLETION) ||
+		slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_CHAT))
+}
+
+// estimateCompletionTokens provides a rough token estimate for a completion request.
+// Uses ~4 characters per token heuristic.
+func estimateCompletionTokens(req *v1.CompleteRequest) int64 {
+	totalChars := 0
+	for _, c := range req.Contents {
+		totalChars += len(c.GetText().GetText())
+	}
+	for _, c := range req.Suffix {
+		totalChars += len(c.GetText().GetText())
+	}
+	if totalChars == 0 {
+		return 0
+	}
+	return int64(totalChars/4) + 1
+}
+
+func (uc *UseCaseImpl) ElectForCompletion(ctx context.Context, req *v1.CompleteRequest) (_ completion.Model, err error) {
+	estimatedTokens := estimateCompletionTokens(req) // Estimate input tokens roughly: ~4 chars per token
+	estimatedTokens += 512                           // Add some buffer for output tokens
+
+	// Client quotas are charged before election, so that a client over its
+	// limits never holds upstream capacity.
+	clientReservations, err := uc.clientQuotas.reserve(ctx, estimatedTokens)
+	if err != nil {
+		uc.reportClientQuotaExceeded(ctx, estimatedTokens)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			clientReservations.cancel()
+		}
+	}()
+
+	// Collect all available candidates
+	var allCandidates []*model
+	var matchingCandidates []*model
+
+	models, aliases := uc.snapshot()
+	for _, m := range models {
+		if !m.canComplete() {
+			continue
+		}
+		allCandidates = append(allCandidates, m)
+		if m.config.Id == req.Model {
+			matchingCandidates = append(matchingCandidates, m)
+		}
+	}
+
+	if a := aliases[req.Model]; a != nil {
+		for _, m := range a.models {
+			if !m.canComplete() {
+				continue
+			}
+			if !slices.Contains(matchingCandidates, m) {
+				matchingCandidates = append(matchingCandidates, m)
+			}
+		}
+	}
+
+	var selected *model
+	var rs *reservationSet
+
+	// If there are matching models, randomly select from them
+	if len(matchingCandidates) > 0 {
+		selected, rs, err = electFromCandidates(ctx, matchingCandidates, estimatedTokens)
+		if err != nil {
+			return nil, err
+		}
+		uc.log.InfoContext(
+			ctx,
+			"selected model",
+			"upstream", selected.upstreamConfig.Name,
+			"model", selected.config.Id,
+			"native", selected.completesNatively(),
+		)
+	} else if len(allCandidates) > 0 {
+		// No matching models, randomly select from all candidates
+		selected, rs, err = electFromCandidates(ctx, allCandidates, estimatedTokens)
+		if err != nil {
+			return nil, err
+		}
+		uc.log.InfoContext(
+			ctx,
+			"selected fallback model",
+			"upstream", selected.upstreamConfig.Name,
+			"model", selected.config.Id,
+			"native", selected.completesNatively(),
+			"requested_model", req.Model,
+		)
+	} else {
+		return nil, entity.ErrNoUpstream
+	}
+
+	uc.reportRateLimit(ctx, selected)
+
+	// Update request model to upstream ID
+	if selected.config.UpstreamId != "" {
+		req.Model = selected.config.UpstreamId
+	} else {
+		req.Model = selected.config.Id
+	}
+
+	return &completionModel{
+		chatModel: &chatModel{
+			model:              selected,
+			reservations:       rs,
+			clientReservations: clientReservations,
+			estimatedTokens:    estimatedTokens,
+		},
+	}, nil
+}
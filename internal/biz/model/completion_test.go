@@ -0,0 +1,95 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestEstimateCompletionTokens(t *testing.T) {
+	Convey("Test estimateCompletionTokens", t, func() {
+		Convey("with no contents should return 0", func() {
+			So(estimateCompletionTokens(&v1.CompleteRequest{}), ShouldEqual, 0)
+		})
+
+		Convey("should sum the prompt and the suffix", func() {
+			req := &v1.CompleteRequest{
+				Contents: []*v1.Content{{Content: v1.NewTextContent("Hello")}},
+				Suffix:   []*v1.Content{{Content: v1.NewTextContent("World!!!")}},
+			}
+			So(estimateCompletionTokens(req), ShouldEqual, (5+8)/4+1)
+		})
+	})
+}
+
+func TestCanComplete(t *testing.T) {
+	Convey("Test canComplete", t, func() {
+		Convey("should complete natively with completion capability and repo", func() {
+			m := makeModel("instruct", "", []conf.Capability{conf.Capability_CAPABILITY_COMPLETION})
+			m.completionRepo = &mockCompletionRepo{}
+			So(m.completesNatively(), ShouldBeTrue)
+			So(m.canComplete(), ShouldBeTrue)
+		})
+
+		Convey("should emulate completions over chat models", func() {
+			m := makeModel("gpt", "", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			m.completionRepo = &mockCompletionRepo{}
+			So(m.completesNatively(), ShouldBeFalse)
+			So(m.canComplete(), ShouldBeTrue)
+		})
+
+		Convey("should not complete without chat or completion capability", func() {
+			m := makeModel("ada", "", []conf.Capability{conf.Capability_CAPABILITY_EMBEDDING})
+			So(m.canComplete(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestElectForCompletion(t *testing.T) {
+	Convey("Test ElectForCompletion", t, func() {
+		Convey("with no models should return error", func() {
+			uc := &UseCaseImpl{log: slog.Default()}
+			_, err := uc.ElectForCompletion(context.Background(), &v1.CompleteRequest{Model: "test"})
+			So(errors.Is(err, entity.ErrNoUpstream), ShouldBeTrue)
+		})
+
+		Convey("should return the native repo and rewrite the model", func() {
+			m := makeModel("instruct", "gpt-3.5-turbo-instruct", []conf.Capability{conf.Capability_CAPABILITY_COMPLETION})
+			m.completionRepo = &mockCompletionRepo{}
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			req := &v1.CompleteRequest{Model: "instruct"}
+			result, err := uc.ElectForCompletion(context.Background(), req)
+			So(err, ShouldBeNil)
+			defer result.Close()
+			So(result.CompletionRepo(), ShouldNotBeNil)
+			So(req.Model, ShouldEqual, "gpt-3.5-turbo-instruct")
+		})
+
+		Convey("should elect chat models for emulation", func() {
+			m := makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			result, err := uc.ElectForCompletion(context.Background(), &v1.CompleteRequest{Model: "gpt"})
+			So(err, ShouldBeNil)
+			defer result.Close()
+			So(result.CompletionRepo(), ShouldBeNil)
+			So(result.ChatRepo(), ShouldNotBeNil)
+		})
+
+		Convey("should skip models that cannot complete", func() {
+			m := makeModel("ada", "", []conf.Capability{conf.Capability_CAPABILITY_EMBEDDING})
+			uc := &UseCaseImpl{models: []*model{m}, log: slog.Default()}
+
+			_, err := uc.ElectForCompletion(context.Background(), &v1.CompleteRequest{Model: "ada"})
+			So(errors.Is(err, entity.ErrNoUpstream), ShouldBeTrue)
+		})
+	})
+}
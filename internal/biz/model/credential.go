@@ -0,0 +1,150 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// credential is an API key pooled by an upstream. Its models are elected as
+// candidates of their own, and are skipped once the upstream rejects the key
+// until an operator enables it again.
+type credential struct {
+	upstream string
+	name     string
+	limiters *limiterGroup
+	disabled atomic.Bool
+	metrics  *metrics
+	log      *slog.Logger
+}
+
+// isDisabled reports whether the credential was rejected by the upstream.
+func (c *credential) isDisabled() bool {
+	return c != nil && c.disabled.Load()
+}
+
+// observe disables the credential if err reports that the upstream rejected it.
+func (c *credential) observe(ctx context.Context, err error) {
+	if c == nil || !errors.Is(err, entity.ErrUpstreamUnauthorized) {
+		return
+	}
+	if !c.disabled.CompareAndSwap(false, true) {
+		return
+	}
+	c.log.ErrorContext(
+		ctx,
+		"credential disabled after the upstream rejected it",
+		"upstream", c.upstream,
+		"credential", c.name,
+		"error", err,
+	)
+	c.metrics.recordCredentialDisabled(ctx, c.upstream, c.name)
+}
+
+// enable puts the credential back into rotation, reporting whether it was
+// disabled.
+func (c *credential) enable() bool {
+	return c.disabled.CompareAndSwap(true, false)
+}
+
+// observedChatRepo returns the chat repo of the model, observed for its
+// credential if it has one.
+func (m *model) observedChatRepo() repository.ChatRepo {
+	if m.credential == nil || m.chatRepo == nil {
+		return m.chatRepo
+	}
+	return &credentialChatRepo{ChatRepo: m.chatRepo, credential: m.credential}
+}
+
+// observedEmbeddingRepo returns the embedding repo of the model, observed for
+// its credential if it has one.
+func (m *model) observedEmbeddingRepo() repository.EmbeddingRepo {
+	if m.credential == nil || m.embeddingRepo == nil {
+		return m.embeddingRepo
+	}
+	return &credentialEmbeddingRepo{EmbeddingRepo: m.embeddingRepo, credential: m.credential}
+}
+
+// observedCompletionRepo returns the completion repo of the model, observed
+// for its credential if it has one.
+func (m *model) observedCompletionRepo() repository.CompletionRepo {
+	if m.credential == nil || m.completionRepo == nil {
+		return m.completionRepo
+	}
+	return &credentialCompletionRepo{CompletionRepo: m.completionRepo, credential: m.credential}
+}
+
+// credentialChatRepo observes the errors of a chat repo for its credential.
+type credentialChatRepo struct {
+	repository.ChatRepo
+	credential *credential
+}
+
+func (r *credentialChatRepo) Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	resp, err := r.ChatRepo.Chat(ctx, req)
+	r.credential.observe(ctx, err)
+	return resp, err
+}
+
+func (r *credentialChatRepo) ChatStream(ctx context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	return observeSeq(ctx, r.credential, r.ChatRepo.ChatStream(ctx, req))
+}
+
+// credentialEmbeddingRepo observes the errors of an embedding repo for its credential.
+type credentialEmbeddingRepo struct {
+	repository.EmbeddingRepo
+	credential *credential
+}
+
+func (r *credentialEmbeddingRepo) Embed(ctx context.Context, req *entity.EmbedRequest) (*entity.EmbedResponse, error) {
+	resp, err := r.EmbeddingRepo.Embed(ctx, req)
+	r.credential.observe(ctx, err)
+	return resp, err
+}
+
+// credentialCompletionRepo observes the errors of a completion repo for its credential.
+type credentialCompletionRepo struct {
+	repository.CompletionRepo
+	credential *credential
+}
+
+func (r *credentialCompletionRepo) Complete(ctx context.Context, req *entity.CompleteRequest) (*entity.CompleteResponse, error) {
+	resp, err := r.CompletionRepo.Complete(ctx, req)
+	r.credential.observe(ctx, err)
+	return resp, err
+}
+
+func (r *credentialCompletionRepo) CompleteStream(ctx context.Context, req *entity.CompleteRequest) iter.Seq2[*entity.CompleteResponse, error] {
+	return observeSeq(ctx, r.credential, r.CompletionRepo.CompleteStream(ctx, req))
+}
+
+// observeSeq passes the values of seq through, observing its errors.
+func observeSeq[T any](ctx context.Context, c *credential, seq iter.Seq2[T, error]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for v, err := range seq {
+			c.observe(ctx, err)
+			if !yield(v, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+// rejectingChatRepo fails every request with err.
+type rejectingChatRepo struct {
+	err error
+}
+
+func (r *rejectingChatRepo) Chat(context.Context, *entity.ChatRequest) (*entity.ChatResponse, error) {
+	return nil, r.err
+}
+
+func (r *rejectingChatRepo) ChatStream(context.Context, *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		yield(nil, r.err)
+	}
+}
+
+func makeCredentialModel(id, name string, repo repository.ChatRepo) *model {
+	m := makeModel(id, "", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+	m.chatRepo = repo
+	m.credential = &credential{
+		upstream: "openai",
+		name:     name,
+		limiters: &limiterGroup{},
+		log:      slog.Default(),
+	}
+	return m
+}
+
+func TestCredential(t *testing.T) {
+	Convey("Test credential", t, func() {
+		ctx := context.Background()
+		rejected := entity.ErrUpstreamUnauthorized.WithCause(errors.New("401 Unauthorized"))
+
+		Convey("should be disabled by a rejection from chat", func() {
+			m := makeCredentialModel("gpt", "a", &rejectingChatRepo{err: rejected})
+			cm := &chatModel{model: m}
+
+			_, err := cm.ChatRepo().Chat(ctx, &entity.ChatRequest{})
+			So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+			So(m.credential.isDisabled(), ShouldBeTrue)
+			So(probeModelDelay(m, 0), ShouldEqual, repository.InfDuration)
+		})
+
+		Convey("should be disabled by a rejection from a stream", func() {
+			m := makeCredentialModel("gpt", "a", &rejectingChatRepo{err: rejected})
+			cm := &chatModel{model: m}
+
+			for _, err := range cm.ChatRepo().ChatStream(ctx, &entity.ChatRequest{}) {
+				So(err, ShouldEqual, rejected)
+			}
+			So(m.credential.isDisabled(), ShouldBeTrue)
+		})
+
+		Convey("should stay enabled on other errors", func() {
+			m := makeCredentialModel("gpt", "a", &rejectingChatRepo{err: entity.ErrNoUpstream})
+			cm := &chatModel{model: m}
+
+			_, err := cm.ChatRepo().Chat(ctx, &entity.ChatRequest{})
+			So(err, ShouldNotBeNil)
+			So(m.credential.isDisabled(), ShouldBeFalse)
+		})
+
+		Convey("should leave models without credentials unwrapped", func() {
+			m := makeModel("gpt", "", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+			So((&chatModel{model: m}).ChatRepo(), ShouldEqual, m.chatRepo)
+			So(probeModelDelay(m, 0), ShouldEqual, 0)
+		})
+
+		Convey("should be skipped by election once disabled", func() {
+			a := makeCredentialModel("gpt", "a", &mockChatRepo{})
+			b := makeCredentialModel("gpt", "b", &mockChatRepo{})
+			a.credential.disabled.Store(true)
+			uc := &UseCaseImpl{models: []*model{a, b}, log: slog.Default()}
+
+			for range 10 {
+				selected, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+				So(err, ShouldBeNil)
+				So(selected.(*chatModel).credential, ShouldEqual, b.credential)
+				selected.Close()
+			}
+
+			b.credential.disabled.Store(true)
+			_, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+			So(errors.Is(err, entity.ErrNoUpstream), ShouldBeTrue)
+		})
+
+		Convey("should be enforced in addition to the upstream limiters", func() {
+			m := makeCredentialModel("gpt", "a", &mockChatRepo{})
+			m.credential.limiters = newLimiterGroup(1, 0, 0, 0, 0)
+
+			rs, err := tryReserveAll(m, 0)
+			So(err, ShouldBeNil)
+			So(probeModelDelay(m, 0), ShouldBeGreaterThan, 0)
+			rs.cancel()
+			So(probeModelDelay(m, 0), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+// discoveryTimeout bounds a single listing of the models of an upstream.
+const discoveryTimeout = 30 * time.Second
+
+var errNoCredential = errors.New("no credential left to list models with")
+
+// discovery registers the models listed by an upstream, besides those
+// configured for it.
+type discovery struct {
+	config           *conf.UpstreamConfig
+	repos            []*upstreamRepo
+	upstreamLimiters *limiterGroup
+	metrics          *metrics
+	log              *slog.Logger
+	models           []*model // guarded by UseCaseImpl.mu
+}
+
+// discover lists the models of the upstream and replaces those previously
+// discovered. They are kept if the listing fails. Reports whether the models
+// were replaced.
+func (uc *UseCaseImpl) discover(ctx context.Context, d *discovery) bool {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	specs, err := d.list(ctx)
+	if err != nil {
+		d.log.ErrorContext(ctx, "failed to discover upstream models", "upstream", d.config.GetName(), "error", err)
+		return false
+	}
+
+	models := d.build(specs)
+
+	uc.mu.Lock()
+	d.models = models
+	uc.mu.Unlock()
+
+	d.log.DebugContext(ctx, "discovered upstream models", "upstream", d.config.GetName(), "models", len(specs))
+	return true
+}
+
+// discoverPeriodically refreshes the models of the upstream until the use
+// case is stopped.
+func (uc *UseCaseImpl) discoverPeriodically(d *discovery, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-uc.stop:
+			return
+		case <-ticker.C:
+			if uc.discover(context.Background(), d) {
+				uc.rebuild()
+			}
+		}
+	}
+}
+
+// list lists the models of the upstream with the first credential it has not
+// rejected.
+func (d *discovery) list(ctx context.Context) ([]*entity.ModelSpec, error) {
+	err := errNoCredential
+	for _, r := range d.repos {
+		if r.credential.isDisabled() {
+			continue
+		}
+		lister := r.repo.(repository.ModelListingRepo)
+
+		var specs []*entity.ModelSpec
+		specs, err = lister.ListModels(ctx)
+		r.credential.observe(ctx, err)
+		if errors.Is(err, entity.ErrUpstreamUnauthorized) {
+			continue
+		}
+		return specs, err
+	}
+	return nil, err
+}
+
+// build creates the models of the listed specs that pass the filters and are
+// not configured for the upstream. Models discovered before are reused, so
+// that their limiters and token counters carry over.
+func (d *discovery) build(specs []*entity.ModelSpec) []*model {
+	previous := make(map[string][]*model)
+	for _, m := range d.models {
+		previous[m.config.Id] = append(previous[m.config.Id], m)
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range d.config.GetModels() {
+		seen[m.GetId()] = true
+	}
+
+	var models []*model
+	for _, spec := range specs {
+		if seen[spec.GetId()] || !d.matches(spec.GetId()) {
+			continue
+		}
+		seen[spec.GetId()] = true
+
+		if ms, ok := previous[spec.GetId()]; ok {
+			models = append(models, ms...)
+			continue
+		}
+
+		config := convertModelSpecToConfig(spec)
+		for _, c := range d.config.GetDiscovery().GetCapabilities() {
+			if !slices.Contains(config.Capabilities, c) {
+				config.Capabilities = append(config.Capabilities, c)
+			}
+		}
+
+		// Shared across all credentials in this upstream
+		modelLimiters := &limiterGroup{}
+		for _, r := range d.repos {
+			models = append(models, newModel(config, d.config, r, d.upstreamLimiters, modelLimiters, d.metrics))
+		}
+	}
+	return models
+}
+
+// matches reports whether the model passes the include and exclude patterns.
+func (d *discovery) matches(id string) bool {
+	c := d.config.GetDiscovery()
+	if len(c.GetInclude()) > 0 && !matchesAny(c.GetInclude(), id) {
+		return false
+	}
+	return !matchesAny(c.GetExclude(), id)
+}
+
+func matchesAny(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether s matches the pattern, where '*' matches any
+// sequence of characters, slashes included, as model IDs are often
+// namespaced, and '?' matches any single character.
+func matchGlob(pattern, s string) bool {
+	p, i := []rune(pattern), []rune(s)
+	var pi, si int
+	star, mark := -1, 0
+	for si < len(i) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == i[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			// Let the last star match one more character
+			pi = star + 1
+			mark++
+			si = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+func convertModelSpecToConfig(spec *entity.ModelSpec) *conf.Model {
+	modalities := make([]conf.Modality, 0, len(spec.Modalities))
+	for _, modality := range spec.Modalities {
+		modalities = append(modalities, conf.Modality(modality))
+	}
+	capabilities := make([]conf.Capability, 0, len(spec.Capabilities))
+	for _, capability := range spec.Capabilities {
+		capabilities = append(capabilities, conf.Capability(capability))
+	}
+	return &conf.Model{
+		Id:            spec.Id,
+		Name:          spec.Name,
+		Owner:         spec.Owner,
+		Provider:      spec.Provider,
+		Modalities:    modalities,
+		Capabilities:  capabilities,
+		ContextLength: spec.ContextLength,
+	}
+}
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestDiscovery(t *testing.T) {
+	Convey("Test model discovery", t, func() {
+		repo := &mockModelListingRepo{
+			specs: []*entity.ModelSpec{
+				{
+					Id:            "gpt-4o",
+					Name:          "GPT-4o",
+					Modalities:    []v1.Modality{v1.Modality_MODALITY_TEXT, v1.Modality_MODALITY_IMAGE},
+					Capabilities:  []v1.Capability{v1.Capability_CAPABILITY_CHAT, v1.Capability_CAPABILITY_TOOL_USE},
+					ContextLength: 128000,
+				},
+				{Id: "gpt-4o-mini"},
+				{Id: "text-embedding-3-small"},
+				{Id: "ft:gpt-4o:org"},
+				{Id: "gpt-4o"},
+			},
+		}
+		factory := func(*conf.OpenAIConfig, http.RoundTripper, *slog.Logger) (repository.Repo, error) {
+			return repo, nil
+		}
+		chatFactory := func(*conf.AnthropicConfig, http.RoundTripper, *slog.Logger) (repository.Repo, error) {
+			return &mockChatRepo{}, nil
+		}
+		newUseCase := func(c *conf.Upstream) *UseCaseImpl {
+			uc, cleanup := NewModelUseCase(
+				&mockKratosConfig{upstream: c},
+				func(*conf.HTTPTransport) (http.RoundTripper, error) { return nil, nil },
+				chatFactory, nil, nil, nil, factory,
+				noop.NewMeterProvider(), slog.Default(),
+			)
+			Reset(cleanup)
+			return uc
+		}
+
+		c := &conf.Upstream{
+			Configs: []*conf.UpstreamConfig{
+				{
+					Name: "openai",
+					Models: []*conf.Model{
+						{Id: "gpt-4o-mini", Name: "Configured", Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT}},
+					},
+					Discovery: &conf.Discovery{
+						Enabled:      true,
+						Include:      []string{"gpt-*"},
+						Exclude:      []string{"*-embedding-*"},
+						Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT},
+					},
+					Config: &conf.UpstreamConfig_OpenAi{OpenAi: &conf.OpenAIConfig{}},
+				},
+			},
+		}
+
+		Convey("should register the listed models that pass the filters", func() {
+			uc := newUseCase(c)
+			So(repo.calls, ShouldEqual, 1)
+			So(uc.models, ShouldHaveLength, 2)
+			So(uc.models[0].config.Name, ShouldEqual, "Configured")
+
+			discovered := uc.models[1]
+			So(discovered.config.Id, ShouldEqual, "gpt-4o")
+			So(discovered.config.Name, ShouldEqual, "GPT-4o")
+			So(discovered.config.ContextLength, ShouldEqual, 128000)
+			So(discovered.config.Modalities, ShouldResemble, []conf.Modality{conf.Modality_MODALITY_TEXT, conf.Modality_MODALITY_IMAGE})
+			So(discovered.config.Capabilities, ShouldResemble, []conf.Capability{conf.Capability_CAPABILITY_CHAT, conf.Capability_CAPABILITY_TOOL_USE})
+			So(discovered.chatRepo, ShouldEqual, repo)
+			So(discovered.upstreamLimiters, ShouldPointTo, uc.models[0].upstreamLimiters)
+
+			all, matching := uc.chatCandidates("gpt-4o")
+			So(all, ShouldHaveLength, 2)
+			So(matching, ShouldResemble, []*model{discovered})
+		})
+
+		Convey("should add the configured capabilities to the reported ones", func() {
+			c.Configs[0].Discovery.Capabilities = []conf.Capability{conf.Capability_CAPABILITY_CHAT, conf.Capability_CAPABILITY_STRUCTURED_OUTPUT}
+
+			uc := newUseCase(c)
+			So(uc.models[1].config.Capabilities, ShouldResemble, []conf.Capability{
+				conf.Capability_CAPABILITY_CHAT, conf.Capability_CAPABILITY_TOOL_USE, conf.Capability_CAPABILITY_STRUCTURED_OUTPUT,
+			})
+		})
+
+		Convey("should apply the configured capabilities to models reporting none", func() {
+			c.Configs[0].Discovery.Include = nil
+			c.Configs[0].Discovery.Exclude = []string{"gpt-4o", "text-*"}
+
+			uc := newUseCase(c)
+			So(uc.models, ShouldHaveLength, 2)
+			So(uc.models[1].config.Id, ShouldEqual, "ft:gpt-4o:org")
+			So(uc.models[1].config.Capabilities, ShouldResemble, []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+		})
+
+		Convey("should resolve aliases to discovered models", func() {
+			c.Aliases = []*conf.AliasConfig{
+				{Id: "default", Actual: &conf.AliasConfig_ActualConfig{Upstream: "openai", Model: "gpt-4o"}},
+			}
+
+			uc := newUseCase(c)
+			So(uc.aliases, ShouldContainKey, "default")
+			So(uc.aliases["default"].models, ShouldResemble, []*model{uc.models[1]})
+		})
+
+		Convey("should reuse the models discovered before on refresh", func() {
+			uc := newUseCase(c)
+			before := uc.models[1]
+
+			repo.specs = append(repo.specs, &entity.ModelSpec{Id: "gpt-5"})
+			So(uc.discover(context.Background(), uc.discoveries[0]), ShouldBeTrue)
+			uc.rebuild()
+
+			So(uc.models, ShouldHaveLength, 3)
+			So(uc.models[1], ShouldPointTo, before)
+			So(uc.models[2].config.Id, ShouldEqual, "gpt-5")
+		})
+
+		Convey("should keep the models discovered before when listing fails", func() {
+			uc := newUseCase(c)
+
+			repo.err = errors.New("listing error")
+			So(uc.discover(context.Background(), uc.discoveries[0]), ShouldBeFalse)
+			uc.rebuild()
+			So(uc.models, ShouldHaveLength, 2)
+		})
+
+		Convey("should list with the next credential when one is rejected", func() {
+			rejected := &mockModelListingRepo{err: entity.ErrUpstreamUnauthorized}
+			repos := []repository.Repo{rejected, repo}
+			factory = func(*conf.OpenAIConfig, http.RoundTripper, *slog.Logger) (repository.Repo, error) {
+				r := repos[0]
+				repos = repos[1:]
+				return r, nil
+			}
+			c.Configs[0].Credentials = []*conf.Credential{{ApiKey: "sk-a"}, {ApiKey: "sk-b"}}
+
+			uc := newUseCase(c)
+			So(rejected.calls, ShouldEqual, 1)
+			So(uc.models, ShouldHaveLength, 4)
+			So(uc.models[0].credential.isDisabled(), ShouldBeTrue)
+			So(uc.models[2].modelLimiters, ShouldPointTo, uc.models[3].modelLimiters)
+		})
+
+		Convey("should skip upstreams that cannot list models", func() {
+			c.Configs = append(c.Configs, &conf.UpstreamConfig{
+				Name:      "anthropic",
+				Discovery: &conf.Discovery{Enabled: true},
+				Config:    &conf.UpstreamConfig_Anthropic{Anthropic: &conf.AnthropicConfig{}},
+			})
+
+			uc := newUseCase(c)
+			So(uc.discoveries, ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestDiscoveryMatches(t *testing.T) {
+	Convey("Test discovery matches", t, func() {
+		d := &discovery{config: &conf.UpstreamConfig{Discovery: &conf.Discovery{}}}
+
+		Convey("should match all models without patterns", func() {
+			So(d.matches("anything"), ShouldBeTrue)
+		})
+
+		Convey("should match included models that are not excluded", func() {
+			d.config.Discovery.Include = []string{"claude-*", "gemini-*"}
+			d.config.Discovery.Exclude = []string{"*-preview"}
+			So(d.matches("claude-sonnet"), ShouldBeTrue)
+			So(d.matches("gemini-pro-preview"), ShouldBeFalse)
+			So(d.matches("gpt-4o"), ShouldBeFalse)
+		})
+
+		Convey("should match across slashes", func() {
+			d.config.Discovery.Include = []string{"*"}
+			So(d.matches("meta-llama/Llama-3.1-8B"), ShouldBeTrue)
+			d.config.Discovery.Include = []string{"meta-llama*"}
+			So(d.matches("meta-llama/Llama-3.1-8B"), ShouldBeTrue)
+			d.config.Discovery.Exclude = []string{"*/*-8?"}
+			So(d.matches("meta-llama/Llama-3.1-8B"), ShouldBeFalse)
+			So(d.matches("meta-llama/Llama-3.1-70B"), ShouldBeTrue)
+		})
+	})
+}
@@ -27,6 +27,12 @@ type scoredModel struct {
 //
 // estimatedTokens is the estimated token cost for token limiters (0 to skip token probing).
 func electFromCandidates(ctx context.Context, candidates []*model, estimatedTokens int64) (*model, *reservationSet, error) {
+	return electFromCandidatesBy(ctx, candidates, func(*model) int64 { return estimatedTokens })
+}
+
+// electFromCandidatesBy is like electFromCandidates with a token cost
+// estimated per candidate.
+func electFromCandidatesBy(ctx context.Context, candidates []*model, estimate func(m *model) int64) (*model, *reservationSet, error) {
 	if len(candidates) == 0 {
 		return nil, nil, entity.ErrNoUpstream
 	}
@@ -35,7 +41,7 @@ func electFromCandidates(ctx context.Context, candidates []*model, estimatedToke
 	var available, waitable []scoredModel
 
 	for _, m := range candidates {
-		d := probeModelDelay(m, estimatedTokens)
+		d := probeModelDelay(m, estimate(m))
 		switch {
 		case d == 0:
 			available = append(available, scoredModel{model: m, delay: d})
@@ -57,7 +63,7 @@ func electFromCandidates(ctx context.Context, candidates []*model, estimatedToke
 	// Phase 2: Try reserve from available first, then waitable
 	ordered := append(available, waitable...)
 	for _, s := range ordered {
-		rs, err := tryReserveAll(s.model, estimatedTokens)
+		rs, err := tryReserveAll(s.model, estimate(s.model))
 		if err != nil {
 			continue // This candidate failed, try next
 		}
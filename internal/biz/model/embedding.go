@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"slices"
+	"sync"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/embedding"
@@ -15,9 +16,62 @@ type embeddingModel struct {
 	*model
 	reservations    *reservationSet
 	estimatedTokens int64
+
+	mu sync.Mutex
+	// elected holds the limiters reserved at election for the first chunk of
+	// a split batch, whose reservations then only hold the client quotas.
+	elected *reservationSet
+	// chunks holds the limiters reserved for the chunks of a split batch.
+	chunks []*reservationSet
+}
+
+func (m *embeddingModel) EmbeddingRepo() repository.EmbeddingRepo { return m.observedEmbeddingRepo() }
+
+// MaxBatchSize returns the configured batch size of the upstream, falling back
+// to the limit of the provider. 0 means unlimited.
+func (m *embeddingModel) MaxBatchSize() int { return embeddingBatchSize(m.model) }
+
+func embeddingBatchSize(m *model) int {
+	if size := m.upstreamConfig.GetScheduling().GetEmbeddingBatchSize(); size > 0 {
+		return int(size)
+	}
+	if r, ok := m.embeddingRepo.(repository.BatchEmbeddingRepo); ok {
+		return r.MaxEmbeddingBatchSize()
+	}
+	return 0
 }
 
-func (m *embeddingModel) EmbeddingRepo() repository.EmbeddingRepo { return m.embeddingRepo }
+// ReserveChunk waits until the limiters of the model admit a chunk of a split
+// batch. The first chunk takes the reservations held since election.
+func (m *embeddingModel) ReserveChunk(ctx context.Context, inputs []*v1.EmbedInput) (func(actualTokens int64), error) {
+	estimatedTokens := estimateInputTokens(inputs)
+
+	m.mu.Lock()
+	rs := m.elected
+	m.elected = nil
+	m.mu.Unlock()
+
+	if rs == nil {
+		var err error
+		if rs, err = tryReserveAll(m.model, estimatedTokens); err != nil {
+			return nil, err
+		}
+		if err = rs.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+	m.chunks = append(m.chunks, rs)
+	m.mu.Unlock()
+
+	return func(actualTokens int64) {
+		if actualTokens == 0 {
+			actualTokens = estimatedTokens
+		}
+		rs.complete(actualTokens)
+	}, nil
+}
 
 func (m *embeddingModel) RecordUsage(ctx context.Context, actualTokens int64) {
 	// If upstream doesn't provide usage info, fall back to estimated tokens
@@ -39,6 +93,15 @@ func (m *embeddingModel) RecordUsage(ctx context.Context, actualTokens int64) {
 
 func (m *embeddingModel) Close() {
 	m.reservations.cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.elected != nil {
+		m.elected.cancel()
+	}
+	for _, rs := range m.chunks {
+		rs.cancel()
+	}
 }
 
 // estimateEmbeddingTokens provides a rough token estimate for an embedding request.
@@ -48,20 +111,56 @@ func estimateEmbeddingTokens(req *v1.EmbedRequest) int64 {
 	for _, c := range req.Contents {
 		totalChars += len(c.GetText().GetText())
 	}
+	for _, input := range req.Inputs {
+		for _, c := range input.Contents {
+			totalChars += len(c.GetText().GetText())
+		}
+	}
 	if totalChars == 0 {
 		return 0
 	}
 	return int64(totalChars/4) + 1
 }
 
-func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedRequest) (embedding.Model, error) {
+// estimateInputTokens estimates the tokens of the given inputs alone.
+func estimateInputTokens(inputs []*v1.EmbedInput) int64 {
+	return estimateEmbeddingTokens(&v1.EmbedRequest{Inputs: inputs})
+}
+
+// estimateFirstCallTokens returns a function estimating the tokens of the
+// first call of req to a model, which is a chunk for split batches.
+func estimateFirstCallTokens(req *v1.EmbedRequest) func(m *model) int64 {
+	estimatedTokens := estimateEmbeddingTokens(req)
+	return func(m *model) int64 {
+		if size := embeddingBatchSize(m); size > 0 && len(req.Inputs) > size {
+			return estimateInputTokens(req.Inputs[:size])
+		}
+		return estimatedTokens
+	}
+}
+
+func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedRequest) (_ embedding.Model, err error) {
 	estimatedTokens := estimateEmbeddingTokens(req) // Estimate input tokens roughly: ~4 chars per token
 
+	// Client quotas are charged before election, so that a client over its
+	// limits never holds upstream capacity.
+	clientReservations, err := uc.clientQuotas.reserve(ctx, estimatedTokens)
+	if err != nil {
+		uc.reportClientQuotaExceeded(ctx, estimatedTokens)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			clientReservations.cancel()
+		}
+	}()
+
 	// Collect all available candidates
 	var allCandidates []*model
 	var matchingCandidates []*model
 
-	for _, m := range uc.models {
+	models, aliases := uc.snapshot()
+	for _, m := range models {
 		if m.embeddingRepo == nil || !slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_EMBEDDING) {
 			continue
 		}
@@ -71,7 +170,7 @@ func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedReque
 		}
 	}
 
-	if a := uc.aliases[req.Model]; a != nil {
+	if a := aliases[req.Model]; a != nil {
 		for _, m := range a.models {
 			if m.embeddingRepo == nil || !slices.Contains(m.config.Capabilities, conf.Capability_CAPABILITY_EMBEDDING) {
 				continue
@@ -84,11 +183,13 @@ func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedReque
 
 	var selected *model
 	var rs *reservationSet
-	var err error
+
+	// Split batches are charged to the limiters of the model per chunk
+	firstCallTokens := estimateFirstCallTokens(req)
 
 	// If there are matching models, randomly select from them
 	if len(matchingCandidates) > 0 {
-		selected, rs, err = electFromCandidates(ctx, matchingCandidates, estimatedTokens)
+		selected, rs, err = electFromCandidatesBy(ctx, matchingCandidates, firstCallTokens)
 		if err != nil {
 			return nil, err
 		}
@@ -100,7 +201,7 @@ func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedReque
 		)
 	} else if len(allCandidates) > 0 {
 		// No matching models, randomly select from all candidates
-		selected, rs, err = electFromCandidates(ctx, allCandidates, estimatedTokens)
+		selected, rs, err = electFromCandidatesBy(ctx, allCandidates, firstCallTokens)
 		if err != nil {
 			return nil, err
 		}
@@ -115,6 +216,18 @@ func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedReque
 		return nil, entity.ErrNoUpstream
 	}
 
+	em := &embeddingModel{
+		model:           selected,
+		reservations:    rs,
+		estimatedTokens: estimatedTokens,
+	}
+	if size := embeddingBatchSize(selected); size > 0 && len(req.Inputs) > size {
+		em.elected, em.reservations = rs, clientReservations
+	} else {
+		rs.merge(clientReservations)
+	}
+	uc.reportRateLimit(ctx, selected)
+
 	// Update request model to upstream ID
 	if selected.config.UpstreamId != "" {
 		req.Model = selected.config.UpstreamId
@@ -122,9 +235,5 @@ func (uc *UseCaseImpl) ElectForEmbedding(ctx context.Context, req *v1.EmbedReque
 		req.Model = selected.config.Id
 	}
 
-	return &embeddingModel{
-		model:           selected,
-		reservations:    rs,
-		estimatedTokens: estimatedTokens,
-	}, nil
+	return em, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -50,6 +51,16 @@ func TestEstimateEmbeddingTokens(t *testing.T) {
 			So(estimateEmbeddingTokens(req), ShouldEqual, (5+8)/4+1)
 		})
 
+		Convey("with multiple inputs should sum all text", func() {
+			req := &v1.EmbedRequest{
+				Inputs: []*v1.EmbedInput{
+					{Contents: []*v1.Content{{Content: v1.NewTextContent("Hello")}}},
+					{Contents: []*v1.Content{{Content: v1.NewTextContent("World!!!")}}},
+				},
+			}
+			So(estimateEmbeddingTokens(req), ShouldEqual, (5+8)/4+1)
+		})
+
 		Convey("with non-text content should ignore it", func() {
 			req := &v1.EmbedRequest{
 				Contents: []*v1.Content{
@@ -81,6 +92,42 @@ func TestEmbeddingModel_EmbeddingRepo(t *testing.T) {
 	})
 }
 
+func TestEmbeddingModel_MaxBatchSize(t *testing.T) {
+	Convey("Test embeddingModel MaxBatchSize", t, func() {
+		Convey("should be unlimited by default", func() {
+			m := &embeddingModel{
+				model: &model{
+					embeddingRepo:  &mockEmbeddingRepo{},
+					upstreamConfig: &conf.UpstreamConfig{},
+				},
+			}
+			So(m.MaxBatchSize(), ShouldEqual, 0)
+		})
+
+		Convey("should fall back to the limit of the repo", func() {
+			m := &embeddingModel{
+				model: &model{
+					embeddingRepo:  &mockBatchEmbeddingRepo{},
+					upstreamConfig: &conf.UpstreamConfig{},
+				},
+			}
+			So(m.MaxBatchSize(), ShouldEqual, 16)
+		})
+
+		Convey("should prefer the configured batch size", func() {
+			m := &embeddingModel{
+				model: &model{
+					embeddingRepo: &mockBatchEmbeddingRepo{},
+					upstreamConfig: &conf.UpstreamConfig{
+						Scheduling: &conf.UpstreamScheduling{EmbeddingBatchSize: 4},
+					},
+				},
+			}
+			So(m.MaxBatchSize(), ShouldEqual, 4)
+		})
+	})
+}
+
 func TestEmbeddingModel_RecordUsage(t *testing.T) {
 	Convey("Test embeddingModel RecordUsage", t, func() {
 		Convey("should complete reservations with actual tokens", func() {
@@ -124,6 +171,26 @@ func TestEmbeddingModel_RecordUsage(t *testing.T) {
 			So(tpmLimiter.Probe(9700), ShouldEqual, 0)
 		})
 
+		Convey("should charge reported usage below the estimate", func() {
+			tpmLimiter := local.NewTPMLimiter(10000)
+			r, _ := tpmLimiter.Reserve(500)
+
+			m := &embeddingModel{
+				model: &model{
+					config:         &conf.Model{Id: "test"},
+					upstreamConfig: &conf.UpstreamConfig{Name: "test"},
+				},
+				reservations: &reservationSet{
+					tokenReservations: []repository.TokenReservation{r},
+				},
+				estimatedTokens: 500,
+			}
+
+			m.RecordUsage(context.Background(), 200)
+
+			So(tpmLimiter.Probe(9800), ShouldEqual, 0)
+		})
+
 		Convey("with zero tokens should still complete reservations", func() {
 			concurrency := local.NewConcurrencyLimiter(1)
 			r, _ := concurrency.Reserve()
@@ -402,5 +469,50 @@ func TestElectForEmbedding(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(model, ShouldNotBeNil)
 		})
+
+		Convey("should reserve a split batch per chunk", func() {
+			tpm := local.NewTPMLimiter(30)
+			m := &model{
+				config: &conf.Model{
+					Id:           "ada",
+					Capabilities: []conf.Capability{conf.Capability_CAPABILITY_EMBEDDING},
+				},
+				upstreamConfig: &conf.UpstreamConfig{
+					Name:       "openai",
+					Scheduling: &conf.UpstreamScheduling{EmbeddingBatchSize: 2},
+				},
+				embeddingRepo: &mockEmbeddingRepo{},
+				upstreamLimiters: &limiterGroup{
+					tokenLimiters: []repository.TokenLimiter{tpm},
+				},
+				modelLimiters: &limiterGroup{},
+			}
+			uc := &UseCaseImpl{
+				models: []*model{m},
+				log:    slog.Default(),
+			}
+
+			// 4 inputs of 40 characters, 21 tokens per chunk of 2 and 41 in
+			// total, which exceeds the burst of the limiter
+			input := &v1.EmbedInput{Contents: []*v1.Content{{Content: v1.NewTextContent(strings.Repeat("x", 40))}}}
+			inputs := []*v1.EmbedInput{input, input, input, input}
+			result, err := uc.ElectForEmbedding(context.Background(), &v1.EmbedRequest{Model: "ada", Inputs: inputs})
+			So(err, ShouldBeNil)
+			defer result.Close()
+			So(tpm.Probe(10), ShouldBeGreaterThan, 0)
+
+			// The first chunk takes the reservation of the election
+			complete, err := result.ReserveChunk(context.Background(), inputs[:2])
+			So(err, ShouldBeNil)
+			complete(5)
+			So(tpm.Probe(25), ShouldEqual, 0)
+
+			complete, err = result.ReserveChunk(context.Background(), inputs[2:])
+			So(err, ShouldBeNil)
+			So(tpm.Probe(4), ShouldEqual, 0)
+			So(tpm.Probe(10), ShouldBeGreaterThan, 0)
+			complete(5)
+			So(tpm.Probe(20), ShouldEqual, 0)
+		})
 	})
 }
@@ -2,9 +2,13 @@ package model
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"time"
 
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
 	"github.com/neuraxes/neurouter/internal/data/limiter/local"
 )
 
@@ -36,6 +40,80 @@ func newLimiterGroup(concurrency, rpm, rpd, tpm, tpd uint64) *limiterGroup {
 	return g
 }
 
+// schedulingConfig is implemented by both conf.UpstreamScheduling and conf.ModelScheduling.
+type schedulingConfig interface {
+	GetConcurrencyLimit() uint64
+	GetRpmLimit() uint64
+	GetRpdLimit() uint64
+	GetTpmLimit() uint64
+	GetTpdLimit() uint64
+	GetWindows() []*conf.RateWindow
+}
+
+// newLimiterGroupFromScheduling creates a limiterGroup from the shorthand
+// limits and the windows of a scheduling configuration.
+func newLimiterGroupFromScheduling(s schedulingConfig) *limiterGroup {
+	g := newLimiterGroup(
+		s.GetConcurrencyLimit(),
+		s.GetRpmLimit(),
+		s.GetRpdLimit(),
+		s.GetTpmLimit(),
+		s.GetTpdLimit(),
+	)
+	for _, w := range s.GetWindows() {
+		g.addWindow(w)
+	}
+	return g
+}
+
+// validateWindows reports the first rate window of the scheduling that has no
+// length, as it would otherwise be ignored and not limit anything.
+func validateWindows(s schedulingConfig) error {
+	for i, w := range s.GetWindows() {
+		switch {
+		case w.GetMonths() > 0 && w.GetType() != conf.RateWindow_TYPE_CALENDAR:
+			return fmt.Errorf("window %d: months only apply to calendar windows", i)
+		case w.GetMonths() > 0 && w.GetDuration() != nil:
+			return fmt.Errorf("window %d: duration and months are exclusive", i)
+		case w.GetMonths() == 0 && w.GetDuration().AsDuration() <= 0:
+			return fmt.Errorf("window %d: duration must be positive", i)
+		}
+	}
+	return nil
+}
+
+// addWindow adds the request and token limiters of a rate window to the group.
+func (g *limiterGroup) addWindow(w *conf.RateWindow) {
+	duration := w.GetDuration().AsDuration()
+	requestLimit, tokenLimit := int64(w.GetRequestLimit()), int64(w.GetTokenLimit())
+
+	var (
+		rl repository.RequestLimiter
+		tl repository.TokenLimiter
+	)
+	switch w.GetType() {
+	case conf.RateWindow_TYPE_CALENDAR:
+		// The zero anchor is the Unix epoch, as for an unset Timestamp.
+		anchor := w.GetAnchor().AsTime()
+		if months := int(w.GetMonths()); months > 0 {
+			rl = local.NewCalendarMonthRequestLimiter(requestLimit, months, anchor)
+			tl = local.NewCalendarMonthTokenLimiter(tokenLimit, months, anchor)
+			break
+		}
+		rl = local.NewCalendarRequestLimiter(requestLimit, duration, anchor)
+		tl = local.NewCalendarTokenLimiter(tokenLimit, duration, anchor)
+	default:
+		rl = local.NewSlidingWindowRequestLimiter(requestLimit, duration)
+		tl = local.NewSlidingWindowTokenLimiter(tokenLimit, duration)
+	}
+	if rl != nil {
+		g.requestLimiters = append(g.requestLimiters, rl)
+	}
+	if tl != nil {
+		g.tokenLimiters = append(g.tokenLimiters, tl)
+	}
+}
+
 // probeDelay returns the maximum wait time across all limiters in the group.
 func (g *limiterGroup) probeDelay(estimatedTokens int64) time.Duration {
 	if g == nil {
@@ -125,11 +203,61 @@ func (rs *reservationSet) complete(actualTokens int64) {
 	rs.tokenReservations = nil
 }
 
-// probeModelDelay computes the maximum delay across upstream and model limiter groups.
+// merge moves the reservations of other into rs.
+func (rs *reservationSet) merge(other *reservationSet) {
+	rs.requestReservations = append(rs.requestReservations, other.requestReservations...)
+	rs.tokenReservations = append(rs.tokenReservations, other.tokenReservations...)
+	other.requestReservations = nil
+	other.tokenReservations = nil
+}
+
+// weightedTokens returns the tokens of usage to charge to token limiters,
+// weighted by kind as configured by w. Cache writes weigh as uncached input.
+func weightedTokens(usage *v1.Usage, w *conf.TokenWeights) int64 {
+	input := float64(usage.GetInputTokens())
+	output := float64(usage.GetOutputTokens())
+	if w == nil {
+		return int64(input + output)
+	}
+
+	cached := min(float64(usage.GetCachedInputTokens()), input)
+	reasoning := min(float64(usage.GetReasoningTokens()), output)
+
+	cachedWeight, outputWeight := 1.0, 1.0
+	if w.CachedInput != nil {
+		cachedWeight = w.GetCachedInput()
+	}
+	if w.Output != nil {
+		outputWeight = w.GetOutput()
+	}
+	reasoningWeight := outputWeight
+	if w.Reasoning != nil {
+		reasoningWeight = w.GetReasoning()
+	}
+
+	weighted := input - cached + cached*cachedWeight +
+		(output-reasoning)*outputWeight + reasoning*reasoningWeight
+	return int64(math.Round(max(weighted, 0)))
+}
+
+// probeModelDelay computes the maximum delay across upstream, credential and
+// model limiter groups. Models of a disabled credential are never available.
 func probeModelDelay(m *model, estimatedTokens int64) time.Duration {
+	if m.credential.isDisabled() {
+		return repository.InfDuration
+	}
 	upstreamMaxDelay := m.upstreamLimiters.probeDelay(estimatedTokens)
+	credentialMaxDelay := m.credentialLimiters().probeDelay(estimatedTokens)
 	modelMaxDelay := m.modelLimiters.probeDelay(estimatedTokens)
-	return max(upstreamMaxDelay, modelMaxDelay)
+	return max(upstreamMaxDelay, credentialMaxDelay, modelMaxDelay)
+}
+
+// credentialLimiters returns the limiter group of the model's credential, if any.
+func (m *model) credentialLimiters() *limiterGroup {
+	if m.credential == nil {
+		return nil
+	}
+	return m.credential.limiters
 }
 
 // tryReserveAll attempts to reserve all limiters for a model (non-blocking).
@@ -137,7 +265,7 @@ func probeModelDelay(m *model, estimatedTokens int64) time.Duration {
 func tryReserveAll(m *model, estimatedTokens int64) (*reservationSet, error) {
 	rs := &reservationSet{}
 
-	for _, g := range []*limiterGroup{m.upstreamLimiters, m.modelLimiters} {
+	for _, g := range []*limiterGroup{m.upstreamLimiters, m.credentialLimiters(), m.modelLimiters} {
 		if g == nil {
 			continue
 		}
@@ -7,8 +7,12 @@ import (
 	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
 	"github.com/neuraxes/neurouter/internal/data/limiter/local"
 )
 
@@ -71,6 +75,76 @@ func TestNewLimiterGroup(t *testing.T) {
 	})
 }
 
+func TestNewLimiterGroupFromScheduling(t *testing.T) {
+	Convey("Test newLimiterGroupFromScheduling", t, func() {
+		Convey("nil scheduling should create empty group", func() {
+			g := newLimiterGroupFromScheduling((*conf.ModelScheduling)(nil))
+			So(g.requestLimiters, ShouldBeEmpty)
+			So(g.tokenLimiters, ShouldBeEmpty)
+		})
+
+		Convey("should combine shorthands and windows", func() {
+			g := newLimiterGroupFromScheduling(&conf.UpstreamScheduling{
+				RpmLimit: 60,
+				TpdLimit: 100000,
+				Windows: []*conf.RateWindow{
+					{
+						Duration:     durationpb.New(5 * time.Hour),
+						RequestLimit: 100,
+						TokenLimit:   50000,
+					},
+					{
+						Type:       conf.RateWindow_TYPE_CALENDAR,
+						Duration:   durationpb.New(7 * 24 * time.Hour),
+						Anchor:     timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+						TokenLimit: 1000000,
+					},
+				},
+			})
+			So(len(g.requestLimiters), ShouldEqual, 2)
+			So(g.requestLimiters[1], ShouldHaveSameTypeAs, &local.SlidingWindowRequestLimiter{})
+			So(len(g.tokenLimiters), ShouldEqual, 3)
+			So(g.tokenLimiters[1], ShouldHaveSameTypeAs, &local.SlidingWindowTokenLimiter{})
+			So(g.tokenLimiters[2], ShouldHaveSameTypeAs, &local.CalendarTokenLimiter{})
+		})
+
+		Convey("should create calendar month windows", func() {
+			g := newLimiterGroupFromScheduling(&conf.ModelScheduling{
+				Windows: []*conf.RateWindow{{Type: conf.RateWindow_TYPE_CALENDAR, Months: 1, TokenLimit: 1000}},
+			})
+			So(g.requestLimiters, ShouldBeEmpty)
+			So(g.tokenLimiters, ShouldHaveLength, 1)
+			So(g.tokenLimiters[0], ShouldHaveSameTypeAs, &local.CalendarTokenLimiter{})
+		})
+	})
+}
+
+func TestValidateWindows(t *testing.T) {
+	Convey("Test validateWindows", t, func() {
+		validate := func(w *conf.RateWindow) error {
+			return validateWindows(&conf.ModelScheduling{Windows: []*conf.RateWindow{w}})
+		}
+
+		So(validate(&conf.RateWindow{Duration: durationpb.New(time.Hour), RequestLimit: 10}), ShouldBeNil)
+		So(validate(&conf.RateWindow{Type: conf.RateWindow_TYPE_CALENDAR, Months: 1, TokenLimit: 10}), ShouldBeNil)
+
+		Convey("should reject windows without duration", func() {
+			So(validate(&conf.RateWindow{RequestLimit: 10}), ShouldNotBeNil)
+			So(validate(&conf.RateWindow{Duration: durationpb.New(0), RequestLimit: 10}), ShouldNotBeNil)
+		})
+
+		Convey("should reject months outside calendar windows", func() {
+			So(validate(&conf.RateWindow{Months: 1, RequestLimit: 10}), ShouldNotBeNil)
+			So(validate(&conf.RateWindow{
+				Type:         conf.RateWindow_TYPE_CALENDAR,
+				Months:       1,
+				Duration:     durationpb.New(time.Hour),
+				RequestLimit: 10,
+			}), ShouldNotBeNil)
+		})
+	})
+}
+
 func TestLimiterGroup_ProbeDelay(t *testing.T) {
 	Convey("Test limiterGroup probeDelay", t, func() {
 		Convey("nil group should return 0", func() {
@@ -330,6 +404,47 @@ func TestReservationSet_Complete(t *testing.T) {
 	})
 }
 
+func TestWeightedTokens(t *testing.T) {
+	Convey("Test weightedTokens", t, func() {
+		usage := &v1.Usage{
+			InputTokens:       1000,
+			OutputTokens:      300,
+			CachedInputTokens: 800,
+			ReasoningTokens:   100,
+		}
+
+		Convey("without weights should charge input and output tokens", func() {
+			So(weightedTokens(usage, nil), ShouldEqual, 1300)
+			So(weightedTokens(usage, &conf.TokenWeights{}), ShouldEqual, 1300)
+		})
+
+		Convey("should discount cached input tokens", func() {
+			So(weightedTokens(usage, &conf.TokenWeights{CachedInput: new(0.0)}), ShouldEqual, 500)
+			So(weightedTokens(usage, &conf.TokenWeights{CachedInput: new(0.1)}), ShouldEqual, 580)
+		})
+
+		Convey("reasoning tokens should default to the output weight", func() {
+			So(weightedTokens(usage, &conf.TokenWeights{Output: new(2.0)}), ShouldEqual, 1600)
+			So(weightedTokens(usage, &conf.TokenWeights{Output: new(2.0), Reasoning: new(1.0)}), ShouldEqual, 1500)
+		})
+
+		Convey("should charge cache writes as uncached input", func() {
+			// Usage of an Anthropic request reading 800 and writing 150 cached tokens
+			usage := &v1.Usage{
+				InputTokens:           1000,
+				OutputTokens:          300,
+				CachedInputTokens:     800,
+				CacheWriteInputTokens: 150,
+			}
+			So(weightedTokens(usage, &conf.TokenWeights{CachedInput: new(0.0)}), ShouldEqual, 500)
+		})
+
+		Convey("should clamp inconsistent subsets", func() {
+			So(weightedTokens(&v1.Usage{InputTokens: 10, CachedInputTokens: 50}, &conf.TokenWeights{CachedInput: new(0.0)}), ShouldEqual, 0)
+		})
+	})
+}
+
 func TestProbeModelDelay(t *testing.T) {
 	Convey("Test probeModelDelay", t, func() {
 		Convey("with no limiters should return 0", func() {
@@ -9,11 +9,12 @@ import (
 
 // metrics holds OTel counter instruments for tracking model usage.
 type metrics struct {
-	inputTokens       metric.Int64Counter
-	outputTokens      metric.Int64Counter
-	cachedInputTokens metric.Int64Counter
-	reasoningTokens   metric.Int64Counter
-	requests          metric.Int64Counter
+	inputTokens         metric.Int64Counter
+	outputTokens        metric.Int64Counter
+	cachedInputTokens   metric.Int64Counter
+	reasoningTokens     metric.Int64Counter
+	requests            metric.Int64Counter
+	credentialsDisabled metric.Int64Counter
 }
 
 // newMetrics creates a new metrics instance from the given MeterProvider.
@@ -55,12 +56,20 @@ func newMetrics(meterProvider metric.MeterProvider) (*metrics, error) {
 		return nil, err
 	}
 
+	credentialsDisabled, err := meter.Int64Counter("neurouter_credentials_disabled_total",
+		metric.WithDescription("Total number of credentials disabled after the upstream rejected them"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &metrics{
-		inputTokens:       inputTokens,
-		outputTokens:      outputTokens,
-		cachedInputTokens: cachedInputTokens,
-		reasoningTokens:   reasoningTokens,
-		requests:          requests,
+		inputTokens:         inputTokens,
+		outputTokens:        outputTokens,
+		cachedInputTokens:   cachedInputTokens,
+		reasoningTokens:     reasoningTokens,
+		requests:            requests,
+		credentialsDisabled: credentialsDisabled,
 	}, nil
 }
 
@@ -95,3 +104,13 @@ func (m *metrics) recordRequest(ctx context.Context, upstream, model string) {
 		attribute.String("model", model),
 	))
 }
+
+func (m *metrics) recordCredentialDisabled(ctx context.Context, upstream, credential string) {
+	if m == nil {
+		return
+	}
+	m.credentialsDisabled.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("upstream", upstream),
+		attribute.String("credential", credential),
+	))
+}
@@ -16,18 +16,25 @@ import (
 
 type mockKratosConfig struct {
 	upstream *conf.Upstream
+	quota    *conf.Quota
 }
 
 func (m *mockKratosConfig) Load() error      { return nil }
 func (m *mockKratosConfig) Scan(v any) error { return nil }
 func (m *mockKratosConfig) Value(key string) config.Value {
-	return &mockConfigValue{upstream: m.upstream}
+	switch {
+	case key == "upstream" && m.upstream != nil:
+		return &mockConfigValue{msg: m.upstream}
+	case key == "quota" && m.quota != nil:
+		return &mockConfigValue{msg: m.quota}
+	}
+	return &mockConfigValue{}
 }
 func (m *mockKratosConfig) Watch(key string, o config.Observer) error { return nil }
 func (m *mockKratosConfig) Close() error                              { return nil }
 
 type mockConfigValue struct {
-	upstream *conf.Upstream
+	msg proto.Message
 }
 
 func (v *mockConfigValue) Bool() (bool, error)                   { return false, nil }
@@ -37,13 +44,18 @@ func (v *mockConfigValue) String() (string, error)               { return "", ni
 func (v *mockConfigValue) Duration() (time.Duration, error)      { return 0, nil }
 func (v *mockConfigValue) Slice() ([]config.Value, error)        { return nil, nil }
 func (v *mockConfigValue) Map() (map[string]config.Value, error) { return nil, nil }
-func (v *mockConfigValue) Load() any                             { return v.upstream }
-func (v *mockConfigValue) Store(any)                             {}
+func (v *mockConfigValue) Load() any {
+	if v.msg == nil {
+		return nil
+	}
+	return v.msg
+}
+func (v *mockConfigValue) Store(any) {}
 func (v *mockConfigValue) Scan(dst any) error {
-	if v.upstream == nil {
-		return errors.New("no upstream config")
+	if v.msg == nil {
+		return errors.New("no config")
 	}
-	proto.Merge(dst.(proto.Message), v.upstream)
+	proto.Merge(dst.(proto.Message), v.msg)
 	return nil
 }
 
@@ -60,6 +72,19 @@ func (m *mockChatRepo) ChatStream(context.Context, *entity.ChatRequest) iter.Seq
 
 var _ repository.ChatRepo = (*mockChatRepo)(nil)
 
+// mockTokenCountingRepo implements repository.TokenCountingRepo for testing.
+type mockTokenCountingRepo struct {
+	mockChatRepo
+	tokens int64
+	err    error
+}
+
+func (m *mockTokenCountingRepo) CountTokens(context.Context, *entity.ChatRequest) (int64, error) {
+	return m.tokens, m.err
+}
+
+var _ repository.TokenCountingRepo = (*mockTokenCountingRepo)(nil)
+
 // mockEmbeddingRepo implements repository.EmbeddingRepo for testing.
 type mockEmbeddingRepo struct{}
 
@@ -69,8 +94,45 @@ func (m *mockEmbeddingRepo) Embed(context.Context, *entity.EmbedRequest) (*entit
 
 var _ repository.EmbeddingRepo = (*mockEmbeddingRepo)(nil)
 
+// mockBatchEmbeddingRepo implements repository.BatchEmbeddingRepo for testing.
+type mockBatchEmbeddingRepo struct {
+	mockEmbeddingRepo
+}
+
+func (m *mockBatchEmbeddingRepo) MaxEmbeddingBatchSize() int { return 16 }
+
+var _ repository.BatchEmbeddingRepo = (*mockBatchEmbeddingRepo)(nil)
+
+// mockCompletionRepo implements repository.CompletionRepo for testing.
+type mockCompletionRepo struct{}
+
+func (m *mockCompletionRepo) Complete(context.Context, *entity.CompleteRequest) (*entity.CompleteResponse, error) {
+	return nil, nil
+}
+
+func (m *mockCompletionRepo) CompleteStream(context.Context, *entity.CompleteRequest) iter.Seq2[*entity.CompleteResponse, error] {
+	return nil
+}
+
+var _ repository.CompletionRepo = (*mockCompletionRepo)(nil)
+
 // mockChatEmbeddingRepo implements both ChatRepo and EmbeddingRepo for testing.
 type mockChatEmbeddingRepo struct {
 	mockChatRepo
 	mockEmbeddingRepo
 }
+
+// mockModelListingRepo implements repository.ModelListingRepo for testing.
+type mockModelListingRepo struct {
+	mockChatRepo
+	specs []*entity.ModelSpec
+	err   error
+	calls int
+}
+
+func (m *mockModelListingRepo) ListModels(context.Context) ([]*entity.ModelSpec, error) {
+	m.calls++
+	return m.specs, m.err
+}
+
+var _ repository.ModelListingRepo = (*mockModelListingRepo)(nil)
@@ -16,11 +16,17 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
 	"sync/atomic"
 
 	"github.com/go-kratos/kratos/v3/config"
 	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/protobuf/proto"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/entity"
@@ -37,12 +43,14 @@ type model struct {
 	upstreamConfig    *conf.UpstreamConfig
 	chatRepo          repository.ChatRepo
 	embeddingRepo     repository.EmbeddingRepo
+	completionRepo    repository.CompletionRepo
 	inputTokens       atomic.Int64
 	outputTokens      atomic.Int64
 	cachedInputTokens atomic.Int64
 	reasoningTokens   atomic.Int64
 	upstreamLimiters  *limiterGroup // shared across models in same upstream
-	modelLimiters     *limiterGroup // specific to this model
+	modelLimiters     *limiterGroup // specific to this model, shared across credentials
+	credential        *credential   // nil unless the upstream pools credentials
 	metrics           *metrics
 }
 
@@ -52,135 +60,326 @@ type alias struct {
 }
 
 type UseCaseImpl struct {
-	models  []*model
-	aliases map[string]*alias
-	metrics *metrics
-	log     *slog.Logger
+	mu           sync.RWMutex // guards models and aliases, replaced as models are discovered
+	models       []*model
+	aliases      map[string]*alias
+	static       []*model // configured models
+	aliasConfigs []*conf.AliasConfig
+	discoveries  []*discovery
+	clientQuotas *clientQuotas
+	metrics      *metrics
+	log          *slog.Logger
+	stop         chan struct{}
+}
+
+// upstreamRepo is the repo of an upstream, authenticating with a credential
+// pooled by the upstream if it has one.
+type upstreamRepo struct {
+	repo       repository.Repo
+	credential *credential
 }
 
 func NewModelUseCase(
 	c config.Config,
+	newTransport repository.TransportFactory,
 	anthropicFactory repository.UpstreamFactory[conf.AnthropicConfig],
 	googleFactory repository.UpstreamFactory[conf.GoogleConfig],
 	neurouterFactory repository.UpstreamFactory[conf.NeurouterConfig],
+	ollamaFactory repository.UpstreamFactory[conf.OllamaConfig],
 	openAIFactory repository.UpstreamFactory[conf.OpenAIConfig],
 	meterProvider metric.MeterProvider,
 	logger *slog.Logger,
-) *UseCaseImpl {
+) (*UseCaseImpl, func()) {
 	metrics, err := newMetrics(meterProvider)
 	if err != nil {
 		logger.Error("failed to create metrics", "error", err)
 	}
 
-	var models []*model
-	aliases := make(map[string]*alias)
+	uc := &UseCaseImpl{
+		metrics: metrics,
+		log:     logger,
+		stop:    make(chan struct{}),
+	}
 
 	upstream, err := config.Get[conf.Upstream](c, "upstream")
 	if err == nil {
-		for _, upstreamConfig := range upstream.Configs {
-			var (
-				repo repository.Repo
-				err  error
-			)
-
+		newRepo := func(upstreamConfig *conf.UpstreamConfig, transport http.RoundTripper) (repo repository.Repo, err error) {
 			switch upstreamConfig.GetConfig().(type) {
 			case *conf.UpstreamConfig_Neurouter:
-				repo, err = neurouterFactory(upstreamConfig.GetNeurouter(), logger)
+				repo, err = neurouterFactory(upstreamConfig.GetNeurouter(), transport, logger)
 			case *conf.UpstreamConfig_OpenAi:
-				repo, err = openAIFactory(upstreamConfig.GetOpenAi(), logger)
+				repo, err = openAIFactory(upstreamConfig.GetOpenAi(), transport, logger)
 			case *conf.UpstreamConfig_Google:
-				repo, err = googleFactory(upstreamConfig.GetGoogle(), logger)
+				repo, err = googleFactory(upstreamConfig.GetGoogle(), transport, logger)
 			case *conf.UpstreamConfig_Anthropic:
-				repo, err = anthropicFactory(upstreamConfig.GetAnthropic(), logger)
+				repo, err = anthropicFactory(upstreamConfig.GetAnthropic(), transport, logger)
+			case *conf.UpstreamConfig_Ollama:
+				repo, err = ollamaFactory(upstreamConfig.GetOllama(), transport, logger)
+			}
+			return
+		}
+
+		for _, upstreamConfig := range upstream.Configs {
+			if err := validateUpstreamWindows(upstreamConfig); err != nil {
+				logger.Error("invalid upstream scheduling", "error", err, "upstream", upstreamConfig.Name)
+				continue
 			}
 
-			if err != nil {
-				logger.Error("failed to create upstream repository", "error", err, "upstream", upstreamConfig.Name)
+			repos := uc.newUpstreamRepos(upstreamConfig, newTransport, newRepo)
+			if len(repos) == 0 {
 				continue
 			}
 
 			// Create upstream limiter group once (shared across all models in this upstream)
-			us := upstreamConfig.GetScheduling()
-			upstreamLimiters := newLimiterGroup(
-				us.GetConcurrencyLimit(),
-				us.GetRpmLimit(),
-				us.GetRpdLimit(),
-				us.GetTpmLimit(),
-				us.GetTpdLimit(),
-			)
-
-			for _, modelConfig := range upstreamConfig.GetModels() {
-				chatRepo, _ := repo.(repository.ChatRepo)
-				embeddingRepo, _ := repo.(repository.EmbeddingRepo)
-
-				// Create model limiter group (specific to this model)
-				ms := modelConfig.GetScheduling()
-				modelLimiters := newLimiterGroup(
-					ms.GetConcurrencyLimit(),
-					ms.GetRpmLimit(),
-					ms.GetRpdLimit(),
-					ms.GetTpmLimit(),
-					ms.GetTpdLimit(),
-				)
-
-				models = append(models, &model{
-					config:           modelConfig,
-					upstreamConfig:   upstreamConfig,
-					chatRepo:         chatRepo,
-					embeddingRepo:    embeddingRepo,
+			upstreamLimiters := newLimiterGroupFromScheduling(upstreamConfig.GetScheduling())
+
+			// Create model limiter groups once (shared across all credentials in this upstream)
+			modelLimiters := make([]*limiterGroup, len(upstreamConfig.GetModels()))
+			for i, modelConfig := range upstreamConfig.GetModels() {
+				modelLimiters[i] = newLimiterGroupFromScheduling(modelConfig.GetScheduling())
+			}
+
+			for _, r := range repos {
+				for i, modelConfig := range upstreamConfig.GetModels() {
+					uc.static = append(uc.static, newModel(modelConfig, upstreamConfig, r, upstreamLimiters, modelLimiters[i], metrics))
+				}
+			}
+
+			if upstreamConfig.GetDiscovery().GetEnabled() {
+				if _, ok := repos[0].repo.(repository.ModelListingRepo); !ok {
+					logger.Error("upstream does not support model discovery", "upstream", upstreamConfig.Name)
+					continue
+				}
+				uc.discoveries = append(uc.discoveries, &discovery{
+					config:           upstreamConfig,
+					repos:            repos,
 					upstreamLimiters: upstreamLimiters,
-					modelLimiters:    modelLimiters,
 					metrics:          metrics,
+					log:              logger,
 				})
 			}
 		}
 
-		for _, ac := range upstream.GetAliases() {
-			actual := ac.GetActual()
-			if actual == nil {
-				logger.Error("alias is missing actual config", "alias", ac.GetId())
+		uc.aliasConfigs = upstream.GetAliases()
+	}
+
+	// Models are discovered before aliases are resolved at startup, so that
+	// aliases may target them
+	var wg sync.WaitGroup
+	for _, d := range uc.discoveries {
+		wg.Go(func() { uc.discover(context.Background(), d) })
+	}
+	wg.Wait()
+
+	for _, ac := range uc.rebuild() {
+		if ac.GetActual() == nil {
+			logger.Error("alias is missing actual config", "alias", ac.GetId())
+			continue
+		}
+		logger.Error(
+			"alias target model not found",
+			"alias", ac.GetId(),
+			"upstream", ac.GetActual().GetUpstream(),
+			"model", ac.GetActual().GetModel(),
+		)
+	}
+
+	for _, d := range uc.discoveries {
+		if interval := d.config.GetDiscovery().GetInterval().AsDuration(); interval > 0 {
+			go uc.discoverPeriodically(d, interval)
+		}
+	}
+
+	if quota, err := config.Get[conf.Quota](c, "quota"); err == nil {
+		uc.clientQuotas = newClientQuotas(&quota)
+	}
+
+	return uc, func() { close(uc.stop) }
+}
+
+// validateUpstreamWindows validates the rate windows of an upstream, of its
+// credentials and of its models.
+func validateUpstreamWindows(upstreamConfig *conf.UpstreamConfig) error {
+	if err := validateWindows(upstreamConfig.GetScheduling()); err != nil {
+		return err
+	}
+	for i, credentialConfig := range upstreamConfig.GetCredentials() {
+		if err := validateWindows(credentialConfig.GetScheduling()); err != nil {
+			return fmt.Errorf("credential %d: %w", i, err)
+		}
+	}
+	for _, modelConfig := range upstreamConfig.GetModels() {
+		if err := validateWindows(modelConfig.GetScheduling()); err != nil {
+			return fmt.Errorf("model %s: %w", modelConfig.GetId(), err)
+		}
+	}
+	return nil
+}
+
+// newUpstreamRepos creates the repos of an upstream, one per pooled
+// credential, or a single one authenticating as the provider config does.
+// The repos share the transport of the upstream, built once. Credentials on an
+// upstream without API keys are ignored.
+func (uc *UseCaseImpl) newUpstreamRepos(
+	upstreamConfig *conf.UpstreamConfig,
+	newTransport repository.TransportFactory,
+	newRepo func(*conf.UpstreamConfig, http.RoundTripper) (repository.Repo, error),
+) []*upstreamRepo {
+	transport, err := newTransport(upstreamConfig.GetTransport())
+	if err != nil {
+		uc.log.Error("failed to create upstream transport", "error", err, "upstream", upstreamConfig.Name)
+		return nil
+	}
+
+	credentials := upstreamConfig.GetCredentials()
+	if _, ok := withAPIKey(upstreamConfig, ""); len(credentials) > 0 && !ok {
+		uc.log.Warn("upstream does not support credentials, ignoring them", "upstream", upstreamConfig.Name)
+		credentials = nil
+	}
+
+	if len(credentials) == 0 {
+		repo, err := newRepo(upstreamConfig, transport)
+		if err != nil {
+			uc.log.Error("failed to create upstream repository", "error", err, "upstream", upstreamConfig.Name)
+			return nil
+		}
+		return []*upstreamRepo{{repo: repo}}
+	}
+
+	var repos []*upstreamRepo
+	for i, credentialConfig := range credentials {
+		name := credentialConfig.GetName()
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+
+		keyedConfig, _ := withAPIKey(upstreamConfig, credentialConfig.GetApiKey())
+		repo, err := newRepo(keyedConfig, transport)
+		if err != nil {
+			uc.log.Error("failed to create upstream repository", "error", err, "upstream", upstreamConfig.Name, "credential", name)
+			continue
+		}
+		repos = append(repos, &upstreamRepo{
+			repo: repo,
+			credential: &credential{
+				upstream: upstreamConfig.Name,
+				name:     name,
+				limiters: newLimiterGroupFromScheduling(credentialConfig.GetScheduling()),
+				metrics:  uc.metrics,
+				log:      uc.log,
+			},
+		})
+	}
+	return repos
+}
+
+// newModel creates a model served by the repo of an upstream.
+func newModel(
+	config *conf.Model,
+	upstreamConfig *conf.UpstreamConfig,
+	r *upstreamRepo,
+	upstreamLimiters, modelLimiters *limiterGroup,
+	metrics *metrics,
+) *model {
+	chatRepo, _ := r.repo.(repository.ChatRepo)
+	embeddingRepo, _ := r.repo.(repository.EmbeddingRepo)
+	completionRepo, _ := r.repo.(repository.CompletionRepo)
+
+	return &model{
+		config:           config,
+		upstreamConfig:   upstreamConfig,
+		chatRepo:         chatRepo,
+		embeddingRepo:    embeddingRepo,
+		completionRepo:   completionRepo,
+		upstreamLimiters: upstreamLimiters,
+		modelLimiters:    modelLimiters,
+		credential:       r.credential,
+		metrics:          metrics,
+	}
+}
+
+// snapshot returns the registered models and aliases, which are replaced
+// rather than modified as models are discovered.
+func (uc *UseCaseImpl) snapshot() ([]*model, map[string]*alias) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.models, uc.aliases
+}
+
+// rebuild registers the configured and discovered models, and resolves the
+// aliases against them. Returns the configs of the aliases left unresolved.
+func (uc *UseCaseImpl) rebuild() (unresolved []*conf.AliasConfig) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	models := slices.Clone(uc.static)
+	for _, d := range uc.discoveries {
+		models = append(models, d.models...)
+	}
+
+	aliases := make(map[string]*alias)
+	for _, ac := range uc.aliasConfigs {
+		actual := ac.GetActual()
+		if actual == nil {
+			unresolved = append(unresolved, ac)
+			continue
+		}
+		var resolved []*model
+		for _, m := range models {
+			if m.config.Id != actual.GetModel() {
 				continue
 			}
-			var resolved []*model
-			for _, m := range models {
-				if m.config.Id != actual.GetModel() {
-					continue
-				}
-				if upstream := actual.GetUpstream(); upstream != "" && m.upstreamConfig.Name != upstream {
-					continue
-				}
-				resolved = append(resolved, m)
-			}
-			if len(resolved) == 0 {
-				logger.Error(
-					"alias target model not found",
-					"alias", ac.GetId(),
-					"upstream", actual.GetUpstream(),
-					"model", actual.GetModel(),
-				)
+			if upstream := actual.GetUpstream(); upstream != "" && m.upstreamConfig.Name != upstream {
 				continue
 			}
-			aliases[ac.GetId()] = &alias{config: ac, models: resolved}
+			resolved = append(resolved, m)
+		}
+		if len(resolved) == 0 {
+			unresolved = append(unresolved, ac)
+			continue
 		}
+		aliases[ac.GetId()] = &alias{config: ac, models: resolved}
 	}
 
-	return &UseCaseImpl{
-		models:  models,
-		aliases: aliases,
-		metrics: metrics,
-		log:     logger,
+	uc.models = models
+	uc.aliases = aliases
+	return
+}
+
+// withAPIKey returns a copy of the upstream config authenticating with key,
+// or false if the provider of the upstream does not take an API key.
+func withAPIKey(upstreamConfig *conf.UpstreamConfig, key string) (*conf.UpstreamConfig, bool) {
+	keyed := proto.CloneOf(upstreamConfig)
+	switch keyed.GetConfig().(type) {
+	case *conf.UpstreamConfig_OpenAi:
+		keyed.GetOpenAi().ApiKey = key
+	case *conf.UpstreamConfig_Google:
+		keyed.GetGoogle().ApiKey = key
+	case *conf.UpstreamConfig_Anthropic:
+		keyed.GetAnthropic().ApiKey = key
+	default:
+		return nil, false
 	}
+	return keyed, true
 }
 
 func (uc *UseCaseImpl) ListAvailableModels(ctx context.Context) ([]*entity.ModelSpec, error) {
 	var specs []*entity.ModelSpec
 
-	for _, m := range uc.models {
+	models, aliases := uc.snapshot()
+
+	// Models pooled across credentials are listed once
+	seen := make(map[*conf.Model]bool)
+	for _, m := range models {
+		if seen[m.config] {
+			continue
+		}
+		seen[m.config] = true
 		specs = append(specs, convertModelConfigToSpec(m.config))
 	}
 
 	// Add virtual models from aliases
-	for _, a := range uc.aliases {
+	for _, a := range aliases {
 		actual := a.models[0]
 		spec := convertModelConfigToSpec(actual.config)
 		spec.Id = a.config.Id
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/http"
 	"testing"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -17,21 +18,27 @@ import (
 func TestNewModelUseCase(t *testing.T) {
 	Convey("Test NewModelUseCase", t, func() {
 		// Factories that return mock repos
-		openAIFactory := func(config *conf.OpenAIConfig, logger *slog.Logger) (repository.Repo, error) {
+		newTransport := func(*conf.HTTPTransport) (http.RoundTripper, error) {
+			return nil, nil
+		}
+		openAIFactory := func(config *conf.OpenAIConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
 			return &mockChatEmbeddingRepo{}, nil
 		}
-		anthropicFactory := func(config *conf.AnthropicConfig, logger *slog.Logger) (repository.Repo, error) {
+		anthropicFactory := func(config *conf.AnthropicConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
 			return &mockChatRepo{}, nil
 		}
-		googleFactory := func(config *conf.GoogleConfig, logger *slog.Logger) (repository.Repo, error) {
+		googleFactory := func(config *conf.GoogleConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
 			return &mockChatRepo{}, nil
 		}
-		neurouterFactory := func(config *conf.NeurouterConfig, logger *slog.Logger) (repository.Repo, error) {
+		neurouterFactory := func(config *conf.NeurouterConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
 			return &mockChatRepo{}, nil
 		}
+		ollamaFactory := func(config *conf.OllamaConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+			return &mockChatEmbeddingRepo{}, nil
+		}
 
 		Convey("with nil config should return empty use case", func() {
-			uc := NewModelUseCase(&mockKratosConfig{}, anthropicFactory, googleFactory, neurouterFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
 			So(uc, ShouldNotBeNil)
 			So(uc.models, ShouldBeEmpty)
 			So(uc.aliases, ShouldBeEmpty)
@@ -41,7 +48,7 @@ func TestNewModelUseCase(t *testing.T) {
 			c := &conf.Upstream{
 				Configs: []*conf.UpstreamConfig{},
 			}
-			uc := NewModelUseCase(&mockKratosConfig{upstream: c}, anthropicFactory, googleFactory, neurouterFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
 			So(uc, ShouldNotBeNil)
 			So(uc.models, ShouldBeEmpty)
 			So(uc.aliases, ShouldBeEmpty)
@@ -69,7 +76,7 @@ func TestNewModelUseCase(t *testing.T) {
 				},
 			}
 
-			uc := NewModelUseCase(&mockKratosConfig{upstream: c}, anthropicFactory, googleFactory, neurouterFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
 			So(len(uc.models), ShouldEqual, 2)
 			So(uc.models[0].config.Id, ShouldEqual, "gpt-4")
 			So(uc.models[1].config.Id, ShouldEqual, "text-embedding-ada")
@@ -99,7 +106,7 @@ func TestNewModelUseCase(t *testing.T) {
 				},
 			}
 
-			uc := NewModelUseCase(&mockKratosConfig{upstream: c}, anthropicFactory, googleFactory, neurouterFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
 			So(len(uc.models), ShouldEqual, 1)
 			So(uc.models[0].config.Id, ShouldEqual, "claude-3")
 			So(uc.models[0].chatRepo, ShouldNotBeNil)
@@ -132,7 +139,7 @@ func TestNewModelUseCase(t *testing.T) {
 				},
 			}
 
-			uc := NewModelUseCase(&mockKratosConfig{upstream: c}, anthropicFactory, googleFactory, neurouterFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
 			So(len(uc.models), ShouldEqual, 1)
 			// Upstream limiters should have concurrency + rpm
 			So(len(uc.models[0].upstreamLimiters.requestLimiters), ShouldEqual, 2)
@@ -159,14 +166,184 @@ func TestNewModelUseCase(t *testing.T) {
 				},
 			}
 
-			uc := NewModelUseCase(&mockKratosConfig{upstream: c}, anthropicFactory, googleFactory, neurouterFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
 			So(len(uc.models), ShouldEqual, 2)
 			// Both models should share the same upstream limiter group pointer
 			So(uc.models[0].upstreamLimiters, ShouldPointTo, uc.models[1].upstreamLimiters)
 		})
 
+		Convey("with credentials should create models per credential", func() {
+			var keys []string
+			keyedFactory := func(config *conf.OpenAIConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+				keys = append(keys, config.ApiKey)
+				return &mockChatRepo{}, nil
+			}
+
+			c := &conf.Upstream{
+				Configs: []*conf.UpstreamConfig{
+					{
+						Name: "openai",
+						Models: []*conf.Model{
+							{
+								Id:           "gpt-4",
+								Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT},
+								Scheduling:   &conf.ModelScheduling{RpmLimit: 10},
+							},
+						},
+						Credentials: []*conf.Credential{
+							{Name: "primary", ApiKey: "sk-a", Scheduling: &conf.UpstreamScheduling{RpmLimit: 100}},
+							{ApiKey: "sk-b"},
+						},
+						Config: &conf.UpstreamConfig_OpenAi{
+							OpenAi: &conf.OpenAIConfig{ApiKey: "sk-default"},
+						},
+					},
+				},
+			}
+
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, keyedFactory, noop.NewMeterProvider(), slog.Default())
+			So(uc.models, ShouldHaveLength, 2)
+			So(keys, ShouldResemble, []string{"sk-a", "sk-b"})
+			So(c.Configs[0].GetOpenAi().ApiKey, ShouldEqual, "sk-default")
+
+			So(uc.models[0].credential.name, ShouldEqual, "primary")
+			So(uc.models[0].credential.limiters.requestLimiters, ShouldHaveLength, 1)
+			So(uc.models[1].credential.name, ShouldEqual, "1")
+			So(uc.models[1].credential.limiters.requestLimiters, ShouldBeEmpty)
+			So(uc.models[0].upstreamLimiters, ShouldPointTo, uc.models[1].upstreamLimiters)
+			So(uc.models[0].modelLimiters, ShouldPointTo, uc.models[1].modelLimiters)
+
+			specs, err := uc.ListAvailableModels(context.Background())
+			So(err, ShouldBeNil)
+			So(specs, ShouldHaveLength, 1)
+		})
+
+		Convey("should share one transport across the credentials of an upstream", func() {
+			var configs []*conf.HTTPTransport
+			shared := &http.Transport{}
+			newTransport := func(config *conf.HTTPTransport) (http.RoundTripper, error) {
+				configs = append(configs, config)
+				return shared, nil
+			}
+			var got []http.RoundTripper
+			transportFactory := func(config *conf.OpenAIConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+				got = append(got, transport)
+				return &mockChatRepo{}, nil
+			}
+
+			transport := &conf.HTTPTransport{ProxyUrl: "http://proxy:3128"}
+			c := &conf.Upstream{
+				Configs: []*conf.UpstreamConfig{
+					{
+						Name:        "openai",
+						Transport:   transport,
+						Credentials: []*conf.Credential{{ApiKey: "sk-a"}, {ApiKey: "sk-b"}},
+						Config: &conf.UpstreamConfig_OpenAi{
+							OpenAi: &conf.OpenAIConfig{},
+						},
+					},
+				},
+			}
+
+			NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, transportFactory, noop.NewMeterProvider(), slog.Default())
+			So(configs, ShouldHaveLength, 1)
+			So(configs[0].GetProxyUrl(), ShouldEqual, "http://proxy:3128")
+			So(got, ShouldHaveLength, 2)
+			So(got[0], ShouldEqual, shared)
+			So(got[1], ShouldEqual, shared)
+		})
+
+		Convey("with transport error should skip that upstream", func() {
+			failTransport := func(*conf.HTTPTransport) (http.RoundTripper, error) {
+				return nil, errors.New("transport error")
+			}
+
+			c := &conf.Upstream{
+				Configs: []*conf.UpstreamConfig{
+					{
+						Name: "openai",
+						Models: []*conf.Model{
+							{Id: "gpt-4", Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT}},
+						},
+						Config: &conf.UpstreamConfig_OpenAi{
+							OpenAi: &conf.OpenAIConfig{},
+						},
+					},
+				},
+			}
+
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, failTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			So(uc.models, ShouldBeEmpty)
+		})
+
+		Convey("with credentials on an upstream without API keys should ignore them", func() {
+			c := &conf.Upstream{
+				Configs: []*conf.UpstreamConfig{
+					{
+						Name: "ollama",
+						Models: []*conf.Model{
+							{Id: "llama", Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT}},
+						},
+						Credentials: []*conf.Credential{{ApiKey: "key"}},
+						Config: &conf.UpstreamConfig_Ollama{
+							Ollama: &conf.OllamaConfig{},
+						},
+					},
+				},
+			}
+
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			So(uc.models, ShouldHaveLength, 1)
+			So(uc.models[0].credential, ShouldBeNil)
+		})
+
+		Convey("with a credential window without length should skip that upstream", func() {
+			c := &conf.Upstream{
+				Configs: []*conf.UpstreamConfig{
+					{
+						Name: "openai",
+						Models: []*conf.Model{
+							{Id: "gpt-4", Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT}},
+						},
+						Credentials: []*conf.Credential{
+							{ApiKey: "sk-a", Scheduling: &conf.UpstreamScheduling{Windows: []*conf.RateWindow{{TokenLimit: 10}}}},
+						},
+						Config: &conf.UpstreamConfig_OpenAi{
+							OpenAi: &conf.OpenAIConfig{},
+						},
+					},
+				},
+			}
+
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			So(uc.models, ShouldBeEmpty)
+		})
+
+		Convey("with a window without length should skip that upstream", func() {
+			c := &conf.Upstream{
+				Configs: []*conf.UpstreamConfig{
+					{
+						Name: "openai",
+						Models: []*conf.Model{
+							{
+								Id:           "gpt-4",
+								Capabilities: []conf.Capability{conf.Capability_CAPABILITY_CHAT},
+								Scheduling:   &conf.ModelScheduling{Windows: []*conf.RateWindow{{TokenLimit: 10}}},
+							},
+						},
+						Config: &conf.UpstreamConfig_OpenAi{
+							OpenAi: &conf.OpenAIConfig{},
+						},
+					},
+				},
+			}
+
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, openAIFactory, noop.NewMeterProvider(), slog.Default())
+			So(uc.models, ShouldBeEmpty)
+		})
+
 		Convey("with factory error should skip that upstream", func() {
-			failFactory := func(config *conf.OpenAIConfig, logger *slog.Logger) (repository.Repo, error) {
+			failFactory := func(config *conf.OpenAIConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
 				return nil, errors.New("factory error")
 			}
 
@@ -184,7 +361,7 @@ func TestNewModelUseCase(t *testing.T) {
 				},
 			}
 
-			uc := NewModelUseCase(&mockKratosConfig{upstream: c}, anthropicFactory, googleFactory, neurouterFactory, failFactory, noop.NewMeterProvider(), slog.Default())
+			uc, _ := NewModelUseCase(&mockKratosConfig{upstream: c}, newTransport, anthropicFactory, googleFactory, neurouterFactory, ollamaFactory, failFactory, noop.NewMeterProvider(), slog.Default())
 			So(uc.models, ShouldBeEmpty)
 		})
 	})
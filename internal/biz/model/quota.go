@@ -0,0 +1,290 @@
+package model
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+const (
+	defaultMaxClients       = 10000
+	defaultQuotaIdleTimeout = time.Hour
+)
+
+// clientQuota holds the limiter state of a single client.
+type clientQuota struct {
+	client   string
+	limiters *limiterGroup
+	lastUsed time.Time
+	inFlight atomic.Int64
+}
+
+// clientQuotas enforces per-client limits before election.
+//
+// Limiter state is created lazily on a client's first request and kept in LRU
+// order. Entries idle for longer than idleTimeout are evicted, and the least
+// recently used idle entries are evicted once more than maxClients are held.
+// Entries with requests in flight are never evicted, so that their
+// concurrency slots are not lost.
+//
+// The usage an evicted entry holds in calendar and sliding windows, such as
+// the tokens of the day, is carried over in a second LRU of at most
+// maxClients entries, and restored when the client returns before the
+// windows reset, so that a client does not regain its daily quota by idling
+// or being pushed out. Clients pushed out of that LRU as well do regain it.
+type clientQuotas struct {
+	config      *conf.Quota
+	maxClients  int
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mu         sync.Mutex
+	clients    map[string]*list.Element
+	lru        *list.List // front is most recently used
+	evicted    map[string]*list.Element
+	evictedLRU *list.List // usage of evicted clients, front is most recently evicted
+}
+
+// windowUsage is the usage an evicted client held in its windows.
+type windowUsage struct {
+	client  string
+	windows []carriedWindow
+}
+
+// carriedWindow is the remaining quota of the limiter at index in the
+// limiter group of a client, valid until reset.
+type carriedWindow struct {
+	index     int
+	remaining int64
+	reset     time.Time
+}
+
+// newClientQuotas returns nil when no quota is configured.
+func newClientQuotas(c *conf.Quota) *clientQuotas {
+	if c == nil || (c.GetDefaults() == nil && len(c.GetClients()) == 0) {
+		return nil
+	}
+	q := &clientQuotas{
+		config:      c,
+		maxClients:  defaultMaxClients,
+		idleTimeout: defaultQuotaIdleTimeout,
+		now:         time.Now,
+		clients:     make(map[string]*list.Element),
+		lru:         list.New(),
+		evicted:     make(map[string]*list.Element),
+		evictedLRU:  list.New(),
+	}
+	if c.GetMaxClients() > 0 {
+		q.maxClients = int(c.GetMaxClients())
+	}
+	if c.GetIdleTimeout() != nil && c.GetIdleTimeout().AsDuration() > 0 {
+		q.idleTimeout = c.GetIdleTimeout().AsDuration()
+	}
+	return q
+}
+
+// limitsFor returns the limits of a client, falling back to the defaults.
+func (q *clientQuotas) limitsFor(client string) *conf.Quota_Limits {
+	if limits, ok := q.config.GetClients()[client]; ok {
+		return limits
+	}
+	return q.config.GetDefaults()
+}
+
+// acquire returns the quota of a client, creating it if needed, and marks it
+// as in use.
+func (q *clientQuotas) acquire(client string) *clientQuota {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	limit := q.maxClients
+	if _, ok := q.clients[client]; !ok {
+		limit-- // make room for the new entry
+	}
+	q.evictLocked(now, limit)
+
+	var cq *clientQuota
+	if e, ok := q.clients[client]; ok {
+		cq = e.Value.(*clientQuota)
+		q.lru.MoveToFront(e)
+	} else {
+		limits := q.limitsFor(client)
+		cq = &clientQuota{
+			client: client,
+			limiters: newLimiterGroup(
+				limits.GetConcurrencyLimit(),
+				limits.GetRpmLimit(),
+				0,
+				limits.GetTpmLimit(),
+				limits.GetTpdLimit(),
+			),
+		}
+		q.restoreLocked(cq, now)
+		q.clients[client] = q.lru.PushFront(cq)
+	}
+	cq.lastUsed = now
+	cq.inFlight.Add(1)
+	return cq
+}
+
+// evictLocked drops idle entries, oldest first, and trims the cache down to
+// limit entries.
+func (q *clientQuotas) evictLocked(now time.Time, limit int) {
+	for e := q.lru.Back(); e != nil; {
+		prev := e.Prev()
+		cq := e.Value.(*clientQuota)
+		if now.Sub(cq.lastUsed) <= q.idleTimeout && q.lru.Len() <= limit {
+			break
+		}
+		if cq.inFlight.Load() == 0 {
+			q.lru.Remove(e)
+			delete(q.clients, cq.client)
+			q.carryLocked(cq, now)
+		}
+		e = prev
+	}
+}
+
+// carryLocked keeps the usage an evicted client holds in calendar and sliding
+// windows, which dropping its limiter state would grant anew. Token buckets
+// are not carried, as they refill within a minute.
+func (q *clientQuotas) carryLocked(cq *clientQuota, now time.Time) {
+	u := &windowUsage{client: cq.client}
+	for i, l := range cq.limiters.limiters() {
+		il, ok := l.(repository.InspectableLimiter)
+		if !ok {
+			continue
+		}
+		s := il.Inspect()
+		switch s.Type {
+		case v1.LimiterType_LIMITER_TYPE_CALENDAR_WINDOW, v1.LimiterType_LIMITER_TYPE_SLIDING_WINDOW:
+			if s.Remaining < s.Limit && s.NextReset.After(now) {
+				u.windows = append(u.windows, carriedWindow{index: i, remaining: s.Remaining, reset: s.NextReset})
+			}
+		}
+	}
+	if len(u.windows) == 0 {
+		return
+	}
+
+	q.evicted[cq.client] = q.evictedLRU.PushFront(u)
+	for q.evictedLRU.Len() > q.maxClients {
+		e := q.evictedLRU.Back()
+		q.evictedLRU.Remove(e)
+		delete(q.evicted, e.Value.(*windowUsage).client)
+	}
+}
+
+// restoreLocked applies the usage carried over the eviction of a client to
+// its new limiter state, for the windows that have not reset since.
+func (q *clientQuotas) restoreLocked(cq *clientQuota, now time.Time) {
+	e, ok := q.evicted[cq.client]
+	if !ok {
+		return
+	}
+	q.evictedLRU.Remove(e)
+	delete(q.evicted, cq.client)
+
+	limiters := cq.limiters.limiters()
+	for _, w := range e.Value.(*windowUsage).windows {
+		if !w.reset.After(now) || w.index >= len(limiters) {
+			continue
+		}
+		if al, ok := limiters[w.index].(repository.AdjustableLimiter); ok {
+			al.SetRemaining(w.remaining)
+		}
+	}
+}
+
+// limitersOf returns the limiter group of the calling client, or nil if it has
+// no identity or no limiter state.
+func (q *clientQuotas) limitersOf(ctx context.Context) *limiterGroup {
+	if q == nil {
+		return nil
+	}
+	client, ok := entity.ClientFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if e, ok := q.clients[client]; ok {
+		return e.Value.(*clientQuota).limiters
+	}
+	return nil
+}
+
+// reserve charges a request of the calling client against its quota.
+// Client quotas never wait: a request that would have to is rejected with
+// entity.ErrClientQuotaExceeded. Requests without a client identity are not
+// limited.
+func (q *clientQuotas) reserve(ctx context.Context, estimatedTokens int64) (*reservationSet, error) {
+	rs := &reservationSet{}
+	if q == nil {
+		return rs, nil
+	}
+	client, ok := entity.ClientFromContext(ctx)
+	if !ok {
+		return rs, nil
+	}
+
+	cq := q.acquire(client)
+	rs.requestReservations = append(rs.requestReservations, &clientQuotaReservation{quota: cq})
+
+	for _, rl := range cq.limiters.requestLimiters {
+		r, err := rl.Reserve()
+		if err != nil {
+			rs.cancel()
+			return nil, entity.ErrClientQuotaExceeded
+		}
+		rs.requestReservations = append(rs.requestReservations, r)
+	}
+	if estimatedTokens > 0 {
+		for _, tl := range cq.limiters.tokenLimiters {
+			r, err := tl.Reserve(estimatedTokens)
+			if err != nil {
+				rs.cancel()
+				return nil, entity.ErrClientQuotaExceeded
+			}
+			rs.tokenReservations = append(rs.tokenReservations, r)
+		}
+	}
+
+	if rs.maxDelay() > 0 {
+		rs.cancel()
+		return nil, entity.ErrClientQuotaExceeded
+	}
+	return rs, nil
+}
+
+// clientQuotaReservation pins a client's limiter state in the cache while a
+// request is in flight.
+type clientQuotaReservation struct {
+	quota    *clientQuota
+	released atomic.Bool
+}
+
+func (r *clientQuotaReservation) Delay() time.Duration       { return 0 }
+func (r *clientQuotaReservation) Wait(context.Context) error { return nil }
+
+func (r *clientQuotaReservation) Cancel() {
+	if r.released.CompareAndSwap(false, true) {
+		r.quota.inFlight.Add(-1)
+	}
+}
+
+func (r *clientQuotaReservation) Complete() {
+	r.Cancel()
+}
+
+var _ repository.Reservation = (*clientQuotaReservation)(nil)
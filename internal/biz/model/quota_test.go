@@ -0,0 +1,253 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/data/limiter/local"
+)
+
+func TestNewClientQuotas(t *testing.T) {
+	Convey("Test newClientQuotas", t, func() {
+		Convey("without config should return nil", func() {
+			So(newClientQuotas(nil), ShouldBeNil)
+			So(newClientQuotas(&conf.Quota{}), ShouldBeNil)
+		})
+
+		Convey("should apply defaults", func() {
+			q := newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{RpmLimit: 10}})
+			So(q, ShouldNotBeNil)
+			So(q.maxClients, ShouldEqual, defaultMaxClients)
+			So(q.idleTimeout, ShouldEqual, defaultQuotaIdleTimeout)
+		})
+
+		Convey("should honour configured bounds", func() {
+			q := newClientQuotas(&conf.Quota{
+				Clients:     map[string]*conf.Quota_Limits{"alice": {RpmLimit: 10}},
+				MaxClients:  5,
+				IdleTimeout: durationpb.New(time.Minute),
+			})
+			So(q.maxClients, ShouldEqual, 5)
+			So(q.idleTimeout, ShouldEqual, time.Minute)
+		})
+	})
+}
+
+func TestClientQuotas_Reserve(t *testing.T) {
+	Convey("Test clientQuotas.reserve", t, func() {
+		ctx := entity.NewClientContext(context.Background(), "alice")
+
+		Convey("nil quotas should not limit", func() {
+			var q *clientQuotas
+			rs, err := q.reserve(ctx, 100)
+			So(err, ShouldBeNil)
+			So(rs, ShouldNotBeNil)
+			So(rs.requestReservations, ShouldBeEmpty)
+		})
+
+		Convey("requests without identity should not be limited", func() {
+			q := newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{ConcurrencyLimit: 1}})
+			for range 3 {
+				_, err := q.reserve(context.Background(), 0)
+				So(err, ShouldBeNil)
+			}
+			So(q.lru.Len(), ShouldEqual, 0)
+		})
+
+		Convey("should reject once the concurrency limit is reached", func() {
+			q := newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{ConcurrencyLimit: 1}})
+			rs, err := q.reserve(ctx, 0)
+			So(err, ShouldBeNil)
+
+			_, err = q.reserve(ctx, 0)
+			So(errors.Is(err, entity.ErrClientQuotaExceeded), ShouldBeTrue)
+
+			Convey("and accept again after completion", func() {
+				rs.complete(0)
+				rs, err := q.reserve(ctx, 0)
+				So(err, ShouldBeNil)
+				rs.cancel()
+			})
+		})
+
+		Convey("should reject once the token limit is exhausted", func() {
+			q := newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{TpdLimit: 100}})
+			rs, err := q.reserve(ctx, 80)
+			So(err, ShouldBeNil)
+			rs.complete(80)
+
+			_, err = q.reserve(ctx, 80)
+			So(errors.Is(err, entity.ErrClientQuotaExceeded), ShouldBeTrue)
+		})
+
+		Convey("should apply per-client overrides", func() {
+			q := newClientQuotas(&conf.Quota{
+				Defaults: &conf.Quota_Limits{ConcurrencyLimit: 1},
+				Clients:  map[string]*conf.Quota_Limits{"alice": {ConcurrencyLimit: 2}},
+			})
+			for range 2 {
+				_, err := q.reserve(ctx, 0)
+				So(err, ShouldBeNil)
+			}
+			_, err := q.reserve(ctx, 0)
+			So(err, ShouldNotBeNil)
+
+			bob := entity.NewClientContext(context.Background(), "bob")
+			_, err = q.reserve(bob, 0)
+			So(err, ShouldBeNil)
+			_, err = q.reserve(bob, 0)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestClientQuotas_Eviction(t *testing.T) {
+	Convey("Test clientQuotas eviction", t, func() {
+		now := time.Now()
+		q := newClientQuotas(&conf.Quota{
+			Defaults:    &conf.Quota_Limits{RpmLimit: 10},
+			MaxClients:  2,
+			IdleTimeout: durationpb.New(time.Minute),
+		})
+		q.now = func() time.Time { return now }
+
+		reserve := func(client string) *reservationSet {
+			rs, err := q.reserve(entity.NewClientContext(context.Background(), client), 0)
+			So(err, ShouldBeNil)
+			return rs
+		}
+
+		Convey("should evict idle clients", func() {
+			reserve("alice").complete(0)
+			now = now.Add(2 * time.Minute)
+			reserve("bob").complete(0)
+			So(q.clients, ShouldContainKey, "bob")
+			So(q.clients, ShouldNotContainKey, "alice")
+		})
+
+		Convey("should evict the least recently used client when full", func() {
+			reserve("alice").complete(0)
+			reserve("bob").complete(0)
+			reserve("alice").complete(0)
+			reserve("carol").complete(0)
+			So(q.lru.Len(), ShouldEqual, 2)
+			So(q.clients, ShouldContainKey, "alice")
+			So(q.clients, ShouldNotContainKey, "bob")
+		})
+
+		Convey("should keep clients with requests in flight", func() {
+			rs := reserve("alice")
+			now = now.Add(2 * time.Minute)
+			reserve("bob").complete(0)
+			reserve("carol").complete(0)
+			So(q.clients, ShouldContainKey, "alice")
+
+			rs.complete(0)
+			reserve("dave").complete(0)
+			So(q.clients, ShouldNotContainKey, "alice")
+		})
+
+		Convey("should keep charging the daily usage of idle clients", func() {
+			q.config.Defaults.TpdLimit = 100
+			alice := entity.NewClientContext(context.Background(), "alice")
+			rs, err := q.reserve(alice, 80)
+			So(err, ShouldBeNil)
+			rs.complete(80)
+
+			now = now.Add(2 * time.Minute)
+			reserve("bob").complete(0)
+			reserve("carol").complete(0)
+			So(q.lru.Len(), ShouldEqual, 2)
+			So(q.clients, ShouldNotContainKey, "alice")
+			So(q.evicted, ShouldContainKey, "alice")
+
+			_, err = q.reserve(alice, 80)
+			So(errors.Is(err, entity.ErrClientQuotaExceeded), ShouldBeTrue)
+			So(q.evicted, ShouldNotContainKey, "alice")
+
+			rs, err = q.reserve(alice, 20)
+			So(err, ShouldBeNil)
+			rs.complete(20)
+		})
+
+		Convey("should bound the daily usage carried for evicted clients", func() {
+			q.config.Defaults.TpdLimit = 100
+			for _, client := range []string{"alice", "bob", "carol", "dave", "erin"} {
+				rs, err := q.reserve(entity.NewClientContext(context.Background(), client), 80)
+				So(err, ShouldBeNil)
+				rs.complete(80)
+			}
+			So(q.lru.Len(), ShouldEqual, 2)
+			So(q.evictedLRU.Len(), ShouldEqual, 2)
+			So(q.evicted, ShouldNotContainKey, "alice")
+
+			// The oldest evicted client regains its quota
+			_, err := q.reserve(entity.NewClientContext(context.Background(), "alice"), 80)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestElectForChat_ClientQuota(t *testing.T) {
+	Convey("Test ElectForChat with client quotas", t, func() {
+		m := makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+		uc := &UseCaseImpl{
+			models:       []*model{m},
+			clientQuotas: newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{ConcurrencyLimit: 1}}),
+			log:          slog.Default(),
+		}
+		ctx := entity.NewClientContext(context.Background(), "alice")
+
+		result, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+		So(err, ShouldBeNil)
+
+		_, err = uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+		So(errors.Is(err, entity.ErrClientQuotaExceeded), ShouldBeTrue)
+
+		result.Close()
+		result, err = uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+		So(err, ShouldBeNil)
+		result.Close()
+
+		Convey("should return the client quota when election fails", func() {
+			uc.models = nil
+			_, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+			So(errors.Is(err, entity.ErrNoUpstream), ShouldBeTrue)
+
+			uc.models = []*model{m}
+			result, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+			So(err, ShouldBeNil)
+			result.Close()
+		})
+
+		Convey("should charge the client the unweighted tokens", func() {
+			tpm := local.NewTPMLimiter(10000)
+			m.upstreamLimiters = &limiterGroup{tokenLimiters: []repository.TokenLimiter{tpm}}
+			m.upstreamConfig.Scheduling = &conf.UpstreamScheduling{
+				TokenWeights: &conf.TokenWeights{Output: new(4.0)},
+			}
+			uc.clientQuotas = newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{TpmLimit: 10000}})
+
+			result, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+			So(err, ShouldBeNil)
+			result.RecordUsage(ctx, &v1.Statistics{Usage: &v1.Usage{InputTokens: 100, OutputTokens: 100}})
+			result.Close()
+
+			So(tpm.Probe(9500), ShouldEqual, 0)
+			So(tpm.Probe(9501), ShouldBeGreaterThan, 0)
+			client := uc.clientQuotas.limitersOf(ctx).tokenLimiters[0]
+			So(client.Probe(9800), ShouldEqual, 0)
+			So(client.Probe(9801), ShouldBeGreaterThan, 0)
+		})
+	})
+}
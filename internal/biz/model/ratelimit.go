@@ -0,0 +1,68 @@
+package model
+
+import (
+	"context"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// rateLimitOf summarizes the most constrained request and token limiters of
+// the groups. Concurrency limiters are not rate limits and are left out.
+func rateLimitOf(groups ...*limiterGroup) *entity.RateLimit {
+	rl := &entity.RateLimit{}
+	for _, g := range groups {
+		if g == nil {
+			continue
+		}
+		for _, l := range g.limiters() {
+			il, ok := l.(repository.InspectableLimiter)
+			if !ok {
+				continue
+			}
+			s := il.Inspect()
+			if s.Type == v1.LimiterType_LIMITER_TYPE_CONCURRENCY {
+				continue
+			}
+
+			w := &rl.Requests
+			if s.Unit == v1.LimiterUnit_LIMITER_UNIT_TOKEN {
+				w = &rl.Tokens
+			}
+			if *w == nil || s.Remaining < (*w).Remaining {
+				*w = &entity.RateLimitWindow{
+					Limit:     s.Limit,
+					Remaining: s.Remaining,
+					Reset:     s.NextReset,
+				}
+			}
+		}
+	}
+	return rl
+}
+
+// reportRateLimit reports the limits an elected model was admitted under,
+// together with the client's own quota.
+func (uc *UseCaseImpl) reportRateLimit(ctx context.Context, selected *model) {
+	report, ok := entity.RateLimitReporterFromContext(ctx)
+	if !ok {
+		return
+	}
+	report(rateLimitOf(selected.upstreamLimiters, selected.credentialLimiters(), selected.modelLimiters, uc.clientQuotas.limitersOf(ctx)))
+}
+
+// reportClientQuotaExceeded reports the client's quota and when a rejected
+// request may be retried.
+func (uc *UseCaseImpl) reportClientQuotaExceeded(ctx context.Context, estimatedTokens int64) {
+	report, ok := entity.RateLimitReporterFromContext(ctx)
+	if !ok {
+		return
+	}
+	g := uc.clientQuotas.limitersOf(ctx)
+	rl := rateLimitOf(g)
+	if d := g.probeDelay(estimatedTokens); d < repository.InfDuration {
+		rl.RetryAfter = d
+	}
+	report(rl)
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestRateLimitOf(t *testing.T) {
+	Convey("Test rateLimitOf", t, func() {
+		Convey("should be empty without rate limiters", func() {
+			rl := rateLimitOf(nil, newLimiterGroup(5, 0, 0, 0, 0))
+			So(rl.Requests, ShouldBeNil)
+			So(rl.Tokens, ShouldBeNil)
+		})
+
+		Convey("should pick the most constrained limiter of each unit", func() {
+			upstream := newLimiterGroup(0, 600, 0, 100000, 0)
+			model := newLimiterGroup(0, 60, 0, 0, 1000)
+			model.tokenLimiters[0].(repository.AdjustableLimiter).SetRemaining(10)
+
+			rl := rateLimitOf(upstream, model)
+			So(rl.Requests.Limit, ShouldEqual, 60)
+			So(rl.Requests.Remaining, ShouldEqual, 60)
+			So(rl.Tokens.Limit, ShouldEqual, 1000)
+			So(rl.Tokens.Remaining, ShouldEqual, 10)
+			So(rl.Tokens.Reset.IsZero(), ShouldBeFalse)
+		})
+	})
+}
+
+func TestElectForChat_ReportRateLimit(t *testing.T) {
+	Convey("Test ElectForChat rate limit reporting", t, func() {
+		m := makeModel("gpt", "gpt-4", []conf.Capability{conf.Capability_CAPABILITY_CHAT})
+		m.modelLimiters = newLimiterGroup(0, 60, 0, 0, 0)
+		uc := &UseCaseImpl{
+			models:       []*model{m},
+			clientQuotas: newClientQuotas(&conf.Quota{Defaults: &conf.Quota_Limits{RpmLimit: 1}}),
+			log:          slog.Default(),
+		}
+
+		var reported *entity.RateLimit
+		ctx := entity.NewClientContext(context.Background(), "alice")
+		ctx = entity.NewRateLimitContext(ctx, func(rl *entity.RateLimit) { reported = rl })
+
+		result, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+		So(err, ShouldBeNil)
+		result.RecordUsage(ctx, nil)
+		So(reported, ShouldNotBeNil)
+		So(reported.Requests.Limit, ShouldEqual, 1)
+		So(reported.Requests.Remaining, ShouldEqual, 0)
+		So(reported.RetryAfter, ShouldEqual, 0)
+
+		Convey("should report when to retry a rejected request", func() {
+			reported = nil
+			_, err := uc.ElectForChat(ctx, &v1.ChatRequest{Model: "gpt"})
+			So(errors.Is(err, entity.ErrClientQuotaExceeded), ShouldBeTrue)
+			So(reported, ShouldNotBeNil)
+			So(reported.RetryAfter, ShouldBeGreaterThan, 0)
+		})
+	})
+}
@@ -16,6 +16,7 @@ package repository
 
 import (
 	"log/slog"
+	"net/http"
 
 	"github.com/neuraxes/neurouter/internal/conf"
 )
@@ -23,8 +24,14 @@ import (
 // UpstreamConfig is a type constraint for LLM provider configurations.
 // It allows for configuration of different upstream LLM providers like OpenAI, Google, Anthropic, etc.
 type UpstreamConfig interface {
-	conf.NeurouterConfig | conf.OpenAIConfig | conf.GoogleConfig | conf.AnthropicConfig
+	conf.NeurouterConfig | conf.OpenAIConfig | conf.GoogleConfig | conf.AnthropicConfig | conf.OllamaConfig
 }
 
+// TransportFactory creates the round tripper of HTTP upstreams from their
+// transport config. It returns nil, for the default one, if config is nil.
+type TransportFactory func(config *conf.HTTPTransport) (http.RoundTripper, error)
+
 // UpstreamFactory is a generic factory function type for creating Repo instances.
-type UpstreamFactory[T UpstreamConfig] func(config *T, logger *slog.Logger) (Repo, error)
+// The transport carries the requests of HTTP upstreams, shared by the repos of
+// every credential of an upstream, and is nil unless the upstream configures one.
+type UpstreamFactory[T UpstreamConfig] func(config *T, transport http.RoundTripper, logger *slog.Logger) (Repo, error)
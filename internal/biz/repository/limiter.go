@@ -18,6 +18,8 @@ import (
 	"context"
 	"math"
 	"time"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 )
 
 var InfDuration = time.Duration(math.MaxInt64)
@@ -85,3 +87,28 @@ type TokenLimiter interface {
 	// - error if reservation fails.
 	Reserve(tokens int64) (TokenReservation, error)
 }
+
+// LimiterState is a point-in-time snapshot of a limiter.
+type LimiterState struct {
+	Type      v1.LimiterType
+	Unit      v1.LimiterUnit
+	Limit     int64         // Maximum units admitted
+	Remaining int64         // Units that can be admitted right now
+	InFlight  int64         // Requests holding a concurrency slot
+	Waiters   int64         // Requests blocked in Wait
+	Window    time.Duration // Refill period or window length, zero if none
+	NextReset time.Time     // When quota is next regained, zero if full
+}
+
+// InspectableLimiter is implemented by limiters that expose their state.
+type InspectableLimiter interface {
+	Inspect() LimiterState
+}
+
+// AdjustableLimiter is implemented by limiters whose remaining quota can be
+// changed at runtime.
+type AdjustableLimiter interface {
+	// SetRemaining makes the limiter admit the given units from now on.
+	// Resetting a limiter is setting its remaining quota to its limit.
+	SetRemaining(remaining int64)
+}
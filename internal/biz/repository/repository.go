@@ -26,6 +26,11 @@ type ChatStreamServer interface {
 	Send(*entity.ChatEvent) error
 }
 
+// CompletionStreamServer defines the server-side interface for sending completion chunks.
+type CompletionStreamServer interface {
+	Send(*entity.CompleteResponse) error
+}
+
 type Repo any
 
 // ChatRepo defines the interface for chat operations.
@@ -44,3 +49,52 @@ type EmbeddingRepo interface {
 	// Embed performs a synchronous embedding operation.
 	Embed(context.Context, *entity.EmbedRequest) (*entity.EmbedResponse, error)
 }
+
+// CompletionRepo defines the interface for text completion operations.
+type CompletionRepo interface {
+	Repo
+	// Complete performs a synchronous text completion.
+	Complete(context.Context, *entity.CompleteRequest) (*entity.CompleteResponse, error)
+	// CompleteStream initiates a streaming text completion.
+	CompleteStream(context.Context, *entity.CompleteRequest) iter.Seq2[*entity.CompleteResponse, error]
+}
+
+// BatchEmbeddingRepo is implemented by embedding repositories whose upstream
+// caps the number of inputs embedded in one call.
+type BatchEmbeddingRepo interface {
+	EmbeddingRepo
+	// MaxEmbeddingBatchSize returns the maximum number of inputs per call.
+	MaxEmbeddingBatchSize() int
+}
+
+// TokenCountingRepo is implemented by chat repositories whose upstream can
+// count the input tokens of a request natively.
+type TokenCountingRepo interface {
+	ChatRepo
+	// CountTokens returns the number of input tokens the request would consume.
+	CountTokens(context.Context, *entity.ChatRequest) (int64, error)
+}
+
+// ModelListingRepo is implemented by repositories whose upstream lists the
+// models it serves.
+type ModelListingRepo interface {
+	Repo
+	// ListModels returns the models of the upstream, with the metadata the
+	// upstream exposes. Capabilities are empty unless the upstream reports them.
+	ListModels(context.Context) ([]*entity.ModelSpec, error)
+}
+
+// ResponseStoreRepo persists generated responses for stateful API clients.
+// Implementations expire responses after their configured TTL.
+type ResponseStoreRepo interface {
+	Repo
+	// Put stores the response, replacing any of the same client with the same
+	// id, and sets its expiry.
+	Put(context.Context, *entity.StoredResponse) error
+	// Get returns the response with the id stored by the client, or
+	// entity.ErrResponseNotFound if it is missing or expired.
+	Get(ctx context.Context, client, id string) (*entity.StoredResponse, error)
+	// Delete removes the response with the id stored by the client, or
+	// returns entity.ErrResponseNotFound.
+	Delete(ctx context.Context, client, id string) error
+}
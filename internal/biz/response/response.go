@@ -0,0 +1,79 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package response
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+type UseCase interface {
+	// Enabled reports whether a response store is configured.
+	Enabled() bool
+	// Save stores the response on behalf of the calling client. It does
+	// nothing if no response store is configured.
+	Save(ctx context.Context, resp *entity.StoredResponse) error
+	// Get returns a response previously stored by the calling client.
+	Get(ctx context.Context, id string) (*entity.StoredResponse, error)
+	// Delete removes a response previously stored by the calling client.
+	Delete(ctx context.Context, id string) error
+}
+
+type useCase struct {
+	repo repository.ResponseStoreRepo
+	log  *slog.Logger
+}
+
+// NewUseCase creates a new response use case instance. The repo may be nil,
+// in which case responses are not stored.
+func NewUseCase(repo repository.ResponseStoreRepo, logger *slog.Logger) UseCase {
+	return &useCase{
+		repo: repo,
+		log:  logger,
+	}
+}
+
+func (uc *useCase) Enabled() bool {
+	return uc.repo != nil
+}
+
+func (uc *useCase) Save(ctx context.Context, resp *entity.StoredResponse) error {
+	if uc.repo == nil {
+		return nil
+	}
+	resp.Client, _ = entity.ClientFromContext(ctx)
+	return uc.repo.Put(ctx, resp)
+}
+
+func (uc *useCase) Get(ctx context.Context, id string) (*entity.StoredResponse, error) {
+	if uc.repo == nil {
+		return nil, entity.ErrResponseNotFound
+	}
+	// Responses are looked up among those of the calling client only, so
+	// that the ids of other clients can neither be probed nor collide.
+	client, _ := entity.ClientFromContext(ctx)
+	return uc.repo.Get(ctx, client, id)
+}
+
+func (uc *useCase) Delete(ctx context.Context, id string) error {
+	if uc.repo == nil {
+		return entity.ErrResponseNotFound
+	}
+	client, _ := entity.ClientFromContext(ctx)
+	return uc.repo.Delete(ctx, client, id)
+}
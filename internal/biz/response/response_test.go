@@ -0,0 +1,96 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package response
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+type mockStore struct {
+	responses map[string]*entity.StoredResponse
+}
+
+func (s *mockStore) Put(_ context.Context, resp *entity.StoredResponse) error {
+	s.responses[resp.Client+"/"+resp.Id] = resp
+	return nil
+}
+
+func (s *mockStore) Get(_ context.Context, client, id string) (*entity.StoredResponse, error) {
+	resp, ok := s.responses[client+"/"+id]
+	if !ok {
+		return nil, entity.ErrResponseNotFound
+	}
+	return resp, nil
+}
+
+func (s *mockStore) Delete(_ context.Context, client, id string) error {
+	if _, ok := s.responses[client+"/"+id]; !ok {
+		return entity.ErrResponseNotFound
+	}
+	delete(s.responses, client+"/"+id)
+	return nil
+}
+
+func TestUseCase(t *testing.T) {
+	Convey("Test response UseCase", t, func() {
+		store := &mockStore{responses: map[string]*entity.StoredResponse{}}
+		uc := NewUseCase(store, slog.Default())
+		alice := entity.NewClientContext(context.Background(), "alice")
+		bob := entity.NewClientContext(context.Background(), "bob")
+
+		Convey("should scope responses to the client that saved them", func() {
+			So(uc.Save(alice, &entity.StoredResponse{Id: "resp_1"}), ShouldBeNil)
+			So(uc.Enabled(), ShouldBeTrue)
+			So(store.responses["alice/resp_1"].Client, ShouldEqual, "alice")
+
+			resp, err := uc.Get(alice, "resp_1")
+			So(err, ShouldBeNil)
+			So(resp.Id, ShouldEqual, "resp_1")
+
+			_, err = uc.Get(bob, "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+			_, err = uc.Get(context.Background(), "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+
+			So(errors.Is(uc.Delete(bob, "resp_1"), entity.ErrResponseNotFound), ShouldBeTrue)
+			So(store.responses, ShouldContainKey, "alice/resp_1")
+			So(uc.Delete(alice, "resp_1"), ShouldBeNil)
+			So(store.responses, ShouldBeEmpty)
+		})
+
+		Convey("should share responses among anonymous clients", func() {
+			ctx := context.Background()
+			So(uc.Save(ctx, &entity.StoredResponse{Id: "resp_1"}), ShouldBeNil)
+			_, err := uc.Get(ctx, "resp_1")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should not store without a repo", func() {
+			uc := NewUseCase(nil, slog.Default())
+			So(uc.Enabled(), ShouldBeFalse)
+			So(uc.Save(alice, &entity.StoredResponse{Id: "resp_1"}), ShouldBeNil)
+			_, err := uc.Get(alice, "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+			So(errors.Is(uc.Delete(alice, "resp_1"), entity.ErrResponseNotFound), ShouldBeTrue)
+		})
+	})
+}
@@ -36,11 +36,67 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ClientIdentity_Source int32
+
+const (
+	// Defaults to the JWT subject.
+	ClientIdentity_SOURCE_UNSPECIFIED ClientIdentity_Source = 0
+	ClientIdentity_SOURCE_JWT_SUBJECT ClientIdentity_Source = 1
+	// A custom JWT claim named by `claim`.
+	ClientIdentity_SOURCE_JWT_CLAIM ClientIdentity_Source = 2
+	// The API key presented in the Authorization, x-api-key or x-goog-api-key header.
+	ClientIdentity_SOURCE_API_KEY ClientIdentity_Source = 3
+)
+
+// Enum value maps for ClientIdentity_Source.
+var (
+	ClientIdentity_Source_name = map[int32]string{
+		0: "SOURCE_UNSPECIFIED",
+		1: "SOURCE_JWT_SUBJECT",
+		2: "SOURCE_JWT_CLAIM",
+		3: "SOURCE_API_KEY",
+	}
+	ClientIdentity_Source_value = map[string]int32{
+		"SOURCE_UNSPECIFIED": 0,
+		"SOURCE_JWT_SUBJECT": 1,
+		"SOURCE_JWT_CLAIM":   2,
+		"SOURCE_API_KEY":     3,
+	}
+)
+
+func (x ClientIdentity_Source) Enum() *ClientIdentity_Source {
+	p := new(ClientIdentity_Source)
+	*p = x
+	return p
+}
+
+func (x ClientIdentity_Source) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ClientIdentity_Source) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_conf_proto_enumTypes[0].Descriptor()
+}
+
+func (ClientIdentity_Source) Type() protoreflect.EnumType {
+	return &file_conf_conf_proto_enumTypes[0]
+}
+
+func (x ClientIdentity_Source) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ClientIdentity_Source.Descriptor instead.
+func (ClientIdentity_Source) EnumDescriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3, 0}
+}
+
 type Bootstrap struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Server        *Server                `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
 	Data          *Data                  `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
 	Upstream      *Upstream              `protobuf:"bytes,3,opt,name=upstream,proto3" json:"upstream,omitempty"`
+	Quota         *Quota                 `protobuf:"bytes,4,opt,name=quota,proto3" json:"quota,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -96,11 +152,22 @@ func (x *Bootstrap) GetUpstream() *Upstream {
 	return nil
 }
 
+func (x *Bootstrap) GetQuota() *Quota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
 type Server struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
-	Grpc          *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
-	JwtKey        string                 `protobuf:"bytes,3,opt,name=jwt_key,json=jwtKey,proto3" json:"jwt_key,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Http   *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
+	Grpc   *Server_GRPC           `protobuf:"bytes,2,opt,name=grpc,proto3" json:"grpc,omitempty"`
+	JwtKey string                 `protobuf:"bytes,3,opt,name=jwt_key,json=jwtKey,proto3" json:"jwt_key,omitempty"`
+	// Identifies the client that per-client quotas are charged to.
+	ClientIdentity *ClientIdentity `protobuf:"bytes,4,opt,name=client_identity,json=clientIdentity,proto3" json:"client_identity,omitempty"`
+	// Exposes the admin API, which inspects and adjusts live limiter state.
+	Admin         *Admin `protobuf:"bytes,5,opt,name=admin,proto3" json:"admin,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -156,16 +223,140 @@ func (x *Server) GetJwtKey() string {
 	return ""
 }
 
+func (x *Server) GetClientIdentity() *ClientIdentity {
+	if x != nil {
+		return x.ClientIdentity
+	}
+	return nil
+}
+
+func (x *Server) GetAdmin() *Admin {
+	if x != nil {
+		return x.Admin
+	}
+	return nil
+}
+
+type Admin struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Registers the admin API on both the HTTP and gRPC servers. It is refused
+	// unless jwt_key and subjects are set.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// JWT subjects allowed to call the admin API. Required.
+	Subjects      []string `protobuf:"bytes,2,rep,name=subjects,proto3" json:"subjects,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Admin) Reset() {
+	*x = Admin{}
+	mi := &file_conf_conf_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Admin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Admin) ProtoMessage() {}
+
+func (x *Admin) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Admin.ProtoReflect.Descriptor instead.
+func (*Admin) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Admin) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Admin) GetSubjects() []string {
+	if x != nil {
+		return x.Subjects
+	}
+	return nil
+}
+
+type ClientIdentity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        ClientIdentity_Source  `protobuf:"varint,1,opt,name=source,proto3,enum=neurouter.config.v1.ClientIdentity_Source" json:"source,omitempty"`
+	Claim         string                 `protobuf:"bytes,2,opt,name=claim,proto3" json:"claim,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientIdentity) Reset() {
+	*x = ClientIdentity{}
+	mi := &file_conf_conf_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientIdentity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientIdentity) ProtoMessage() {}
+
+func (x *ClientIdentity) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientIdentity.ProtoReflect.Descriptor instead.
+func (*ClientIdentity) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ClientIdentity) GetSource() ClientIdentity_Source {
+	if x != nil {
+		return x.Source
+	}
+	return ClientIdentity_SOURCE_UNSPECIFIED
+}
+
+func (x *ClientIdentity) GetClaim() string {
+	if x != nil {
+		return x.Claim
+	}
+	return ""
+}
+
 type Data struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	EnableEventLog bool                   `protobuf:"varint,1,opt,name=enable_event_log,json=enableEventLog,proto3" json:"enable_event_log,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Keeps generated responses for stateful clients of the OpenAI Responses
+	// API. Responses are not stored when absent.
+	ResponseStore *ResponseStore `protobuf:"bytes,2,opt,name=response_store,json=responseStore,proto3" json:"response_store,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Data) Reset() {
 	*x = Data{}
-	mi := &file_conf_conf_proto_msgTypes[2]
+	mi := &file_conf_conf_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -177,7 +368,7 @@ func (x *Data) String() string {
 func (*Data) ProtoMessage() {}
 
 func (x *Data) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[2]
+	mi := &file_conf_conf_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -190,7 +381,7 @@ func (x *Data) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Data.ProtoReflect.Descriptor instead.
 func (*Data) Descriptor() ([]byte, []int) {
-	return file_conf_conf_proto_rawDescGZIP(), []int{2}
+	return file_conf_conf_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *Data) GetEnableEventLog() bool {
@@ -200,6 +391,184 @@ func (x *Data) GetEnableEventLog() bool {
 	return false
 }
 
+func (x *Data) GetResponseStore() *ResponseStore {
+	if x != nil {
+		return x.ResponseStore
+	}
+	return nil
+}
+
+// ResponseStore keeps the conversation and output of each stored response, so
+// that later requests can continue it by previous_response_id and clients can
+// retrieve it.
+type ResponseStore struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Backend:
+	//
+	//	*ResponseStore_Memory_
+	//	*ResponseStore_File_
+	Backend isResponseStore_Backend `protobuf_oneof:"backend"`
+	// Responses expire this long after they are stored. Defaults to 30 days,
+	// also when not positive.
+	Ttl           *durationpb.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseStore) Reset() {
+	*x = ResponseStore{}
+	mi := &file_conf_conf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseStore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseStore) ProtoMessage() {}
+
+func (x *ResponseStore) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseStore.ProtoReflect.Descriptor instead.
+func (*ResponseStore) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResponseStore) GetBackend() isResponseStore_Backend {
+	if x != nil {
+		return x.Backend
+	}
+	return nil
+}
+
+func (x *ResponseStore) GetMemory() *ResponseStore_Memory {
+	if x != nil {
+		if x, ok := x.Backend.(*ResponseStore_Memory_); ok {
+			return x.Memory
+		}
+	}
+	return nil
+}
+
+func (x *ResponseStore) GetFile() *ResponseStore_File {
+	if x != nil {
+		if x, ok := x.Backend.(*ResponseStore_File_); ok {
+			return x.File
+		}
+	}
+	return nil
+}
+
+func (x *ResponseStore) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+type isResponseStore_Backend interface {
+	isResponseStore_Backend()
+}
+
+type ResponseStore_Memory_ struct {
+	Memory *ResponseStore_Memory `protobuf:"bytes,1,opt,name=memory,proto3,oneof"`
+}
+
+type ResponseStore_File_ struct {
+	File *ResponseStore_File `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+func (*ResponseStore_Memory_) isResponseStore_Backend() {}
+
+func (*ResponseStore_File_) isResponseStore_Backend() {}
+
+// Quota limits what each authenticated client may consume, independently of
+// upstream and model scheduling. Requests without a client identity are not
+// subject to client quotas.
+type Quota struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limits applied to every client without an override.
+	Defaults *Quota_Limits `protobuf:"bytes,1,opt,name=defaults,proto3" json:"defaults,omitempty"`
+	// Per-client overrides keyed by client identity.
+	Clients map[string]*Quota_Limits `protobuf:"bytes,2,rep,name=clients,proto3" json:"clients,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Maximum number of clients whose limiter state is kept. Defaults to 10000.
+	// The daily usage of as many evicted clients is carried until their day resets.
+	MaxClients uint32 `protobuf:"varint,3,opt,name=max_clients,json=maxClients,proto3" json:"max_clients,omitempty"`
+	// Limiter state of a client idle for longer than this is evicted. Defaults to 1h.
+	IdleTimeout   *durationpb.Duration `protobuf:"bytes,4,opt,name=idle_timeout,json=idleTimeout,proto3" json:"idle_timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	mi := &file_conf_conf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Quota) GetDefaults() *Quota_Limits {
+	if x != nil {
+		return x.Defaults
+	}
+	return nil
+}
+
+func (x *Quota) GetClients() map[string]*Quota_Limits {
+	if x != nil {
+		return x.Clients
+	}
+	return nil
+}
+
+func (x *Quota) GetMaxClients() uint32 {
+	if x != nil {
+		return x.MaxClients
+	}
+	return 0
+}
+
+func (x *Quota) GetIdleTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.IdleTimeout
+	}
+	return nil
+}
+
 type Server_HTTP struct {
 	state   protoimpl.MessageState `protogen:"open.v1"`
 	Network string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
@@ -215,7 +584,7 @@ type Server_HTTP struct {
 
 func (x *Server_HTTP) Reset() {
 	*x = Server_HTTP{}
-	mi := &file_conf_conf_proto_msgTypes[3]
+	mi := &file_conf_conf_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -227,7 +596,7 @@ func (x *Server_HTTP) String() string {
 func (*Server_HTTP) ProtoMessage() {}
 
 func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[3]
+	mi := &file_conf_conf_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -289,7 +658,7 @@ type Server_GRPC struct {
 
 func (x *Server_GRPC) Reset() {
 	*x = Server_GRPC{}
-	mi := &file_conf_conf_proto_msgTypes[4]
+	mi := &file_conf_conf_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -301,7 +670,7 @@ func (x *Server_GRPC) String() string {
 func (*Server_GRPC) ProtoMessage() {}
 
 func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[4]
+	mi := &file_conf_conf_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -349,7 +718,7 @@ type Server_HTTP_CORS struct {
 
 func (x *Server_HTTP_CORS) Reset() {
 	*x = Server_HTTP_CORS{}
-	mi := &file_conf_conf_proto_msgTypes[5]
+	mi := &file_conf_conf_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -361,7 +730,7 @@ func (x *Server_HTTP_CORS) String() string {
 func (*Server_HTTP_CORS) ProtoMessage() {}
 
 func (x *Server_HTTP_CORS) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[5]
+	mi := &file_conf_conf_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -398,19 +767,182 @@ func (x *Server_HTTP_CORS) GetAllowedHeaders() []string {
 	return nil
 }
 
+// Keeps responses in process memory; they are lost on restart.
+type ResponseStore_Memory struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of responses kept, evicting the oldest. Defaults to 10000.
+	MaxEntries    uint32 `protobuf:"varint,1,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseStore_Memory) Reset() {
+	*x = ResponseStore_Memory{}
+	mi := &file_conf_conf_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseStore_Memory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseStore_Memory) ProtoMessage() {}
+
+func (x *ResponseStore_Memory) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseStore_Memory.ProtoReflect.Descriptor instead.
+func (*ResponseStore_Memory) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5, 0}
+}
+
+func (x *ResponseStore_Memory) GetMaxEntries() uint32 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+// Keeps responses as files in a local directory.
+type ResponseStore_File struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The directory holding the responses, created if missing.
+	Dir           string `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseStore_File) Reset() {
+	*x = ResponseStore_File{}
+	mi := &file_conf_conf_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseStore_File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseStore_File) ProtoMessage() {}
+
+func (x *ResponseStore_File) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseStore_File.ProtoReflect.Descriptor instead.
+func (*ResponseStore_File) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{5, 1}
+}
+
+func (x *ResponseStore_File) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+type Quota_Limits struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RpmLimit         uint64                 `protobuf:"varint,1,opt,name=rpm_limit,json=rpmLimit,proto3" json:"rpm_limit,omitempty"`
+	TpmLimit         uint64                 `protobuf:"varint,2,opt,name=tpm_limit,json=tpmLimit,proto3" json:"tpm_limit,omitempty"`
+	TpdLimit         uint64                 `protobuf:"varint,3,opt,name=tpd_limit,json=tpdLimit,proto3" json:"tpd_limit,omitempty"`
+	ConcurrencyLimit uint64                 `protobuf:"varint,4,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Quota_Limits) Reset() {
+	*x = Quota_Limits{}
+	mi := &file_conf_conf_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota_Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota_Limits) ProtoMessage() {}
+
+func (x *Quota_Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota_Limits.ProtoReflect.Descriptor instead.
+func (*Quota_Limits) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{6, 0}
+}
+
+func (x *Quota_Limits) GetRpmLimit() uint64 {
+	if x != nil {
+		return x.RpmLimit
+	}
+	return 0
+}
+
+func (x *Quota_Limits) GetTpmLimit() uint64 {
+	if x != nil {
+		return x.TpmLimit
+	}
+	return 0
+}
+
+func (x *Quota_Limits) GetTpdLimit() uint64 {
+	if x != nil {
+		return x.TpdLimit
+	}
+	return 0
+}
+
+func (x *Quota_Limits) GetConcurrencyLimit() uint64 {
+	if x != nil {
+		return x.ConcurrencyLimit
+	}
+	return 0
+}
+
 var File_conf_conf_proto protoreflect.FileDescriptor
 
 const file_conf_conf_proto_rawDesc = "" +
 	"\n" +
-	"\x0fconf/conf.proto\x12\x13neurouter.config.v1\x1a\x13conf/upstream.proto\x1a\x1egoogle/protobuf/duration.proto\"\xaa\x01\n" +
+	"\x0fconf/conf.proto\x12\x13neurouter.config.v1\x1a\x13conf/upstream.proto\x1a\x1egoogle/protobuf/duration.proto\"\xdc\x01\n" +
 	"\tBootstrap\x123\n" +
 	"\x06server\x18\x01 \x01(\v2\x1b.neurouter.config.v1.ServerR\x06server\x12-\n" +
 	"\x04data\x18\x02 \x01(\v2\x19.neurouter.config.v1.DataR\x04data\x129\n" +
-	"\bupstream\x18\x03 \x01(\v2\x1d.neurouter.config.v1.UpstreamR\bupstream\"\xd0\x04\n" +
+	"\bupstream\x18\x03 \x01(\v2\x1d.neurouter.config.v1.UpstreamR\bupstream\x120\n" +
+	"\x05quota\x18\x04 \x01(\v2\x1a.neurouter.config.v1.QuotaR\x05quota\"\xd0\x05\n" +
 	"\x06Server\x124\n" +
 	"\x04http\x18\x01 \x01(\v2 .neurouter.config.v1.Server.HTTPR\x04http\x124\n" +
 	"\x04grpc\x18\x02 \x01(\v2 .neurouter.config.v1.Server.GRPCR\x04grpc\x12\x17\n" +
-	"\ajwt_key\x18\x03 \x01(\tR\x06jwtKey\x1a\xd5\x02\n" +
+	"\ajwt_key\x18\x03 \x01(\tR\x06jwtKey\x12L\n" +
+	"\x0fclient_identity\x18\x04 \x01(\v2#.neurouter.config.v1.ClientIdentityR\x0eclientIdentity\x120\n" +
+	"\x05admin\x18\x05 \x01(\v2\x1a.neurouter.config.v1.AdminR\x05admin\x1a\xd5\x02\n" +
 	"\x04HTTP\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
@@ -425,9 +957,45 @@ const file_conf_conf_proto_rawDesc = "" +
 	"\x04GRPC\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"0\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"=\n" +
+	"\x05Admin\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1a\n" +
+	"\bsubjects\x18\x02 \x03(\tR\bsubjects\"\xce\x01\n" +
+	"\x0eClientIdentity\x12B\n" +
+	"\x06source\x18\x01 \x01(\x0e2*.neurouter.config.v1.ClientIdentity.SourceR\x06source\x12\x14\n" +
+	"\x05claim\x18\x02 \x01(\tR\x05claim\"b\n" +
+	"\x06Source\x12\x16\n" +
+	"\x12SOURCE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12SOURCE_JWT_SUBJECT\x10\x01\x12\x14\n" +
+	"\x10SOURCE_JWT_CLAIM\x10\x02\x12\x12\n" +
+	"\x0eSOURCE_API_KEY\x10\x03\"{\n" +
 	"\x04Data\x12(\n" +
-	"\x10enable_event_log\x18\x01 \x01(\bR\x0eenableEventLogB2Z0github.com/neuraxes/neurouter/internal/conf;confb\x06proto3"
+	"\x10enable_event_log\x18\x01 \x01(\bR\x0eenableEventLog\x12I\n" +
+	"\x0eresponse_store\x18\x02 \x01(\v2\".neurouter.config.v1.ResponseStoreR\rresponseStore\"\x90\x02\n" +
+	"\rResponseStore\x12C\n" +
+	"\x06memory\x18\x01 \x01(\v2).neurouter.config.v1.ResponseStore.MemoryH\x00R\x06memory\x12=\n" +
+	"\x04file\x18\x02 \x01(\v2'.neurouter.config.v1.ResponseStore.FileH\x00R\x04file\x12+\n" +
+	"\x03ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x1a)\n" +
+	"\x06Memory\x12\x1f\n" +
+	"\vmax_entries\x18\x01 \x01(\rR\n" +
+	"maxEntries\x1a\x18\n" +
+	"\x04File\x12\x10\n" +
+	"\x03dir\x18\x01 \x01(\tR\x03dirB\t\n" +
+	"\abackend\"\xd6\x03\n" +
+	"\x05Quota\x12=\n" +
+	"\bdefaults\x18\x01 \x01(\v2!.neurouter.config.v1.Quota.LimitsR\bdefaults\x12A\n" +
+	"\aclients\x18\x02 \x03(\v2'.neurouter.config.v1.Quota.ClientsEntryR\aclients\x12\x1f\n" +
+	"\vmax_clients\x18\x03 \x01(\rR\n" +
+	"maxClients\x12<\n" +
+	"\fidle_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\vidleTimeout\x1a\x8c\x01\n" +
+	"\x06Limits\x12\x1b\n" +
+	"\trpm_limit\x18\x01 \x01(\x04R\brpmLimit\x12\x1b\n" +
+	"\ttpm_limit\x18\x02 \x01(\x04R\btpmLimit\x12\x1b\n" +
+	"\ttpd_limit\x18\x03 \x01(\x04R\btpdLimit\x12+\n" +
+	"\x11concurrency_limit\x18\x04 \x01(\x04R\x10concurrencyLimit\x1a]\n" +
+	"\fClientsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x127\n" +
+	"\x05value\x18\x02 \x01(\v2!.neurouter.config.v1.Quota.LimitsR\x05value:\x028\x01B2Z0github.com/neuraxes/neurouter/internal/conf;confb\x06proto3"
 
 var (
 	file_conf_conf_proto_rawDescOnce sync.Once
@@ -441,31 +1009,53 @@ func file_conf_conf_proto_rawDescGZIP() []byte {
 	return file_conf_conf_proto_rawDescData
 }
 
-var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_conf_conf_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_conf_conf_proto_goTypes = []any{
-	(*Bootstrap)(nil),           // 0: neurouter.config.v1.Bootstrap
-	(*Server)(nil),              // 1: neurouter.config.v1.Server
-	(*Data)(nil),                // 2: neurouter.config.v1.Data
-	(*Server_HTTP)(nil),         // 3: neurouter.config.v1.Server.HTTP
-	(*Server_GRPC)(nil),         // 4: neurouter.config.v1.Server.GRPC
-	(*Server_HTTP_CORS)(nil),    // 5: neurouter.config.v1.Server.HTTP.CORS
-	(*Upstream)(nil),            // 6: neurouter.config.v1.Upstream
-	(*durationpb.Duration)(nil), // 7: google.protobuf.Duration
+	(ClientIdentity_Source)(0),   // 0: neurouter.config.v1.ClientIdentity.Source
+	(*Bootstrap)(nil),            // 1: neurouter.config.v1.Bootstrap
+	(*Server)(nil),               // 2: neurouter.config.v1.Server
+	(*Admin)(nil),                // 3: neurouter.config.v1.Admin
+	(*ClientIdentity)(nil),       // 4: neurouter.config.v1.ClientIdentity
+	(*Data)(nil),                 // 5: neurouter.config.v1.Data
+	(*ResponseStore)(nil),        // 6: neurouter.config.v1.ResponseStore
+	(*Quota)(nil),                // 7: neurouter.config.v1.Quota
+	(*Server_HTTP)(nil),          // 8: neurouter.config.v1.Server.HTTP
+	(*Server_GRPC)(nil),          // 9: neurouter.config.v1.Server.GRPC
+	(*Server_HTTP_CORS)(nil),     // 10: neurouter.config.v1.Server.HTTP.CORS
+	(*ResponseStore_Memory)(nil), // 11: neurouter.config.v1.ResponseStore.Memory
+	(*ResponseStore_File)(nil),   // 12: neurouter.config.v1.ResponseStore.File
+	(*Quota_Limits)(nil),         // 13: neurouter.config.v1.Quota.Limits
+	nil,                          // 14: neurouter.config.v1.Quota.ClientsEntry
+	(*Upstream)(nil),             // 15: neurouter.config.v1.Upstream
+	(*durationpb.Duration)(nil),  // 16: google.protobuf.Duration
 }
 var file_conf_conf_proto_depIdxs = []int32{
-	1, // 0: neurouter.config.v1.Bootstrap.server:type_name -> neurouter.config.v1.Server
-	2, // 1: neurouter.config.v1.Bootstrap.data:type_name -> neurouter.config.v1.Data
-	6, // 2: neurouter.config.v1.Bootstrap.upstream:type_name -> neurouter.config.v1.Upstream
-	3, // 3: neurouter.config.v1.Server.http:type_name -> neurouter.config.v1.Server.HTTP
-	4, // 4: neurouter.config.v1.Server.grpc:type_name -> neurouter.config.v1.Server.GRPC
-	7, // 5: neurouter.config.v1.Server.HTTP.timeout:type_name -> google.protobuf.Duration
-	5, // 6: neurouter.config.v1.Server.HTTP.cors:type_name -> neurouter.config.v1.Server.HTTP.CORS
-	7, // 7: neurouter.config.v1.Server.GRPC.timeout:type_name -> google.protobuf.Duration
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	2,  // 0: neurouter.config.v1.Bootstrap.server:type_name -> neurouter.config.v1.Server
+	5,  // 1: neurouter.config.v1.Bootstrap.data:type_name -> neurouter.config.v1.Data
+	15, // 2: neurouter.config.v1.Bootstrap.upstream:type_name -> neurouter.config.v1.Upstream
+	7,  // 3: neurouter.config.v1.Bootstrap.quota:type_name -> neurouter.config.v1.Quota
+	8,  // 4: neurouter.config.v1.Server.http:type_name -> neurouter.config.v1.Server.HTTP
+	9,  // 5: neurouter.config.v1.Server.grpc:type_name -> neurouter.config.v1.Server.GRPC
+	4,  // 6: neurouter.config.v1.Server.client_identity:type_name -> neurouter.config.v1.ClientIdentity
+	3,  // 7: neurouter.config.v1.Server.admin:type_name -> neurouter.config.v1.Admin
+	0,  // 8: neurouter.config.v1.ClientIdentity.source:type_name -> neurouter.config.v1.ClientIdentity.Source
+	6,  // 9: neurouter.config.v1.Data.response_store:type_name -> neurouter.config.v1.ResponseStore
+	11, // 10: neurouter.config.v1.ResponseStore.memory:type_name -> neurouter.config.v1.ResponseStore.Memory
+	12, // 11: neurouter.config.v1.ResponseStore.file:type_name -> neurouter.config.v1.ResponseStore.File
+	16, // 12: neurouter.config.v1.ResponseStore.ttl:type_name -> google.protobuf.Duration
+	13, // 13: neurouter.config.v1.Quota.defaults:type_name -> neurouter.config.v1.Quota.Limits
+	14, // 14: neurouter.config.v1.Quota.clients:type_name -> neurouter.config.v1.Quota.ClientsEntry
+	16, // 15: neurouter.config.v1.Quota.idle_timeout:type_name -> google.protobuf.Duration
+	16, // 16: neurouter.config.v1.Server.HTTP.timeout:type_name -> google.protobuf.Duration
+	10, // 17: neurouter.config.v1.Server.HTTP.cors:type_name -> neurouter.config.v1.Server.HTTP.CORS
+	16, // 18: neurouter.config.v1.Server.GRPC.timeout:type_name -> google.protobuf.Duration
+	13, // 19: neurouter.config.v1.Quota.ClientsEntry.value:type_name -> neurouter.config.v1.Quota.Limits
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_conf_conf_proto_init() }
@@ -474,19 +1064,24 @@ func file_conf_conf_proto_init() {
 		return
 	}
 	file_conf_upstream_proto_init()
-	file_conf_conf_proto_msgTypes[3].OneofWrappers = []any{}
+	file_conf_conf_proto_msgTypes[5].OneofWrappers = []any{
+		(*ResponseStore_Memory_)(nil),
+		(*ResponseStore_File_)(nil),
+	}
+	file_conf_conf_proto_msgTypes[7].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conf_conf_proto_rawDesc), len(file_conf_conf_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   6,
+			NumEnums:      1,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_conf_conf_proto_goTypes,
 		DependencyIndexes: file_conf_conf_proto_depIdxs,
+		EnumInfos:         file_conf_conf_proto_enumTypes,
 		MessageInfos:      file_conf_conf_proto_msgTypes,
 	}.Build()
 	File_conf_conf_proto = out.File
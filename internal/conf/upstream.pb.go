@@ -23,6 +23,8 @@ package conf
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -100,6 +102,8 @@ const (
 	Capability_CAPABILITY_COMPLETION  Capability = 2
 	Capability_CAPABILITY_EMBEDDING   Capability = 3
 	Capability_CAPABILITY_TOOL_USE    Capability = 4
+	// Natively follows a requested JSON schema or JSON object format.
+	Capability_CAPABILITY_STRUCTURED_OUTPUT Capability = 5
 )
 
 // Enum value maps for Capability.
@@ -110,13 +114,15 @@ var (
 		2: "CAPABILITY_COMPLETION",
 		3: "CAPABILITY_EMBEDDING",
 		4: "CAPABILITY_TOOL_USE",
+		5: "CAPABILITY_STRUCTURED_OUTPUT",
 	}
 	Capability_value = map[string]int32{
-		"CAPABILITY_UNSPECIFIED": 0,
-		"CAPABILITY_CHAT":        1,
-		"CAPABILITY_COMPLETION":  2,
-		"CAPABILITY_EMBEDDING":   3,
-		"CAPABILITY_TOOL_USE":    4,
+		"CAPABILITY_UNSPECIFIED":       0,
+		"CAPABILITY_CHAT":              1,
+		"CAPABILITY_COMPLETION":        2,
+		"CAPABILITY_EMBEDDING":         3,
+		"CAPABILITY_TOOL_USE":          4,
+		"CAPABILITY_STRUCTURED_OUTPUT": 5,
 	}
 )
 
@@ -147,6 +153,159 @@ func (Capability) EnumDescriptor() ([]byte, []int) {
 	return file_conf_upstream_proto_rawDescGZIP(), []int{1}
 }
 
+type RateWindow_Type int32
+
+const (
+	// Defaults to a sliding window.
+	RateWindow_TYPE_UNSPECIFIED RateWindow_Type = 0
+	// Counts usage over the trailing duration.
+	RateWindow_TYPE_SLIDING RateWindow_Type = 1
+	// Counts usage within fixed windows that reset at anchor + n * duration.
+	RateWindow_TYPE_CALENDAR RateWindow_Type = 2
+)
+
+// Enum value maps for RateWindow_Type.
+var (
+	RateWindow_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_SLIDING",
+		2: "TYPE_CALENDAR",
+	}
+	RateWindow_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_SLIDING":     1,
+		"TYPE_CALENDAR":    2,
+	}
+)
+
+func (x RateWindow_Type) Enum() *RateWindow_Type {
+	p := new(RateWindow_Type)
+	*p = x
+	return p
+}
+
+func (x RateWindow_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RateWindow_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_upstream_proto_enumTypes[2].Descriptor()
+}
+
+func (RateWindow_Type) Type() protoreflect.EnumType {
+	return &file_conf_upstream_proto_enumTypes[2]
+}
+
+func (x RateWindow_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RateWindow_Type.Descriptor instead.
+func (RateWindow_Type) EnumDescriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{3, 0}
+}
+
+type StructuredOutput_Emulation int32
+
+const (
+	// Passes the requested format to the upstream as is.
+	StructuredOutput_EMULATION_UNSPECIFIED StructuredOutput_Emulation = 0
+	// Forces a call of a tool whose input is the schema.
+	StructuredOutput_EMULATION_TOOL StructuredOutput_Emulation = 1
+	// Describes the schema in a system instruction.
+	StructuredOutput_EMULATION_INSTRUCTION StructuredOutput_Emulation = 2
+)
+
+// Enum value maps for StructuredOutput_Emulation.
+var (
+	StructuredOutput_Emulation_name = map[int32]string{
+		0: "EMULATION_UNSPECIFIED",
+		1: "EMULATION_TOOL",
+		2: "EMULATION_INSTRUCTION",
+	}
+	StructuredOutput_Emulation_value = map[string]int32{
+		"EMULATION_UNSPECIFIED": 0,
+		"EMULATION_TOOL":        1,
+		"EMULATION_INSTRUCTION": 2,
+	}
+)
+
+func (x StructuredOutput_Emulation) Enum() *StructuredOutput_Emulation {
+	p := new(StructuredOutput_Emulation)
+	*p = x
+	return p
+}
+
+func (x StructuredOutput_Emulation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StructuredOutput_Emulation) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_upstream_proto_enumTypes[3].Descriptor()
+}
+
+func (StructuredOutput_Emulation) Type() protoreflect.EnumType {
+	return &file_conf_upstream_proto_enumTypes[3]
+}
+
+func (x StructuredOutput_Emulation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StructuredOutput_Emulation.Descriptor instead.
+func (StructuredOutput_Emulation) EnumDescriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{11, 0}
+}
+
+type ToolUse_Emulation int32
+
+const (
+	// Passes the tools to the upstream as is.
+	ToolUse_EMULATION_UNSPECIFIED ToolUse_Emulation = 0
+	// Describes the tools in a system instruction and parses the calls the
+	// model writes in the Hermes format.
+	ToolUse_EMULATION_HERMES ToolUse_Emulation = 1
+)
+
+// Enum value maps for ToolUse_Emulation.
+var (
+	ToolUse_Emulation_name = map[int32]string{
+		0: "EMULATION_UNSPECIFIED",
+		1: "EMULATION_HERMES",
+	}
+	ToolUse_Emulation_value = map[string]int32{
+		"EMULATION_UNSPECIFIED": 0,
+		"EMULATION_HERMES":      1,
+	}
+)
+
+func (x ToolUse_Emulation) Enum() *ToolUse_Emulation {
+	p := new(ToolUse_Emulation)
+	*p = x
+	return p
+}
+
+func (x ToolUse_Emulation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ToolUse_Emulation) Descriptor() protoreflect.EnumDescriptor {
+	return file_conf_upstream_proto_enumTypes[4].Descriptor()
+}
+
+func (ToolUse_Emulation) Type() protoreflect.EnumType {
+	return &file_conf_upstream_proto_enumTypes[4]
+}
+
+func (x ToolUse_Emulation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ToolUse_Emulation.Descriptor instead.
+func (ToolUse_Emulation) EnumDescriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{12, 0}
+}
+
 type Upstream struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Configs       []*UpstreamConfig      `protobuf:"bytes,1,rep,name=configs,proto3" json:"configs,omitempty"`
@@ -200,14 +359,23 @@ func (x *Upstream) GetAliases() []*AliasConfig {
 }
 
 type UpstreamScheduling struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	TpmLimit         uint64                 `protobuf:"varint,1,opt,name=tpm_limit,json=tpmLimit,proto3" json:"tpm_limit,omitempty"`
-	TpdLimit         uint64                 `protobuf:"varint,2,opt,name=tpd_limit,json=tpdLimit,proto3" json:"tpd_limit,omitempty"`
-	RpmLimit         uint64                 `protobuf:"varint,3,opt,name=rpm_limit,json=rpmLimit,proto3" json:"rpm_limit,omitempty"`
-	RpdLimit         uint64                 `protobuf:"varint,4,opt,name=rpd_limit,json=rpdLimit,proto3" json:"rpd_limit,omitempty"`
-	ConcurrencyLimit uint64                 `protobuf:"varint,5,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Shorthands for common windows: TPM and RPM are token buckets refilling
+	// over a minute, TPD and RPD are calendar days starting at UTC midnight.
+	TpmLimit         uint64 `protobuf:"varint,1,opt,name=tpm_limit,json=tpmLimit,proto3" json:"tpm_limit,omitempty"`
+	TpdLimit         uint64 `protobuf:"varint,2,opt,name=tpd_limit,json=tpdLimit,proto3" json:"tpd_limit,omitempty"`
+	RpmLimit         uint64 `protobuf:"varint,3,opt,name=rpm_limit,json=rpmLimit,proto3" json:"rpm_limit,omitempty"`
+	RpdLimit         uint64 `protobuf:"varint,4,opt,name=rpd_limit,json=rpdLimit,proto3" json:"rpd_limit,omitempty"`
+	ConcurrencyLimit uint64 `protobuf:"varint,5,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
+	// Additional windows of arbitrary duration.
+	Windows []*RateWindow `protobuf:"bytes,6,rep,name=windows,proto3" json:"windows,omitempty"`
+	// Weights usage reported by this upstream when charging token limiters.
+	TokenWeights *TokenWeights `protobuf:"bytes,7,opt,name=token_weights,json=tokenWeights,proto3" json:"token_weights,omitempty"`
+	// Maximum inputs embedded in one upstream call. Larger batches are split
+	// into concurrent calls. Defaults to the limit of the provider.
+	EmbeddingBatchSize uint32 `protobuf:"varint,8,opt,name=embedding_batch_size,json=embeddingBatchSize,proto3" json:"embedding_batch_size,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *UpstreamScheduling) Reset() {
@@ -275,6 +443,182 @@ func (x *UpstreamScheduling) GetConcurrencyLimit() uint64 {
 	return 0
 }
 
+func (x *UpstreamScheduling) GetWindows() []*RateWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+func (x *UpstreamScheduling) GetTokenWeights() *TokenWeights {
+	if x != nil {
+		return x.TokenWeights
+	}
+	return nil
+}
+
+func (x *UpstreamScheduling) GetEmbeddingBatchSize() uint32 {
+	if x != nil {
+		return x.EmbeddingBatchSize
+	}
+	return 0
+}
+
+// TokenWeights scales the kinds of tokens charged to token limiters, to match
+// how a provider meters usage. Uncached input tokens, including those written
+// to prompt cache, always weigh 1.
+type TokenWeights struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Weight of input tokens read from prompt cache. Defaults to 1.
+	CachedInput *float64 `protobuf:"fixed64,1,opt,name=cached_input,json=cachedInput,proto3,oneof" json:"cached_input,omitempty"`
+	// Weight of output tokens other than reasoning tokens. Defaults to 1.
+	Output *float64 `protobuf:"fixed64,2,opt,name=output,proto3,oneof" json:"output,omitempty"`
+	// Weight of reasoning tokens. Defaults to the output weight.
+	Reasoning     *float64 `protobuf:"fixed64,3,opt,name=reasoning,proto3,oneof" json:"reasoning,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenWeights) Reset() {
+	*x = TokenWeights{}
+	mi := &file_conf_upstream_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenWeights) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenWeights) ProtoMessage() {}
+
+func (x *TokenWeights) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenWeights.ProtoReflect.Descriptor instead.
+func (*TokenWeights) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TokenWeights) GetCachedInput() float64 {
+	if x != nil && x.CachedInput != nil {
+		return *x.CachedInput
+	}
+	return 0
+}
+
+func (x *TokenWeights) GetOutput() float64 {
+	if x != nil && x.Output != nil {
+		return *x.Output
+	}
+	return 0
+}
+
+func (x *TokenWeights) GetReasoning() float64 {
+	if x != nil && x.Reasoning != nil {
+		return *x.Reasoning
+	}
+	return 0
+}
+
+// RateWindow limits the requests and tokens admitted within a window.
+type RateWindow struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Type     RateWindow_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=neurouter.config.v1.RateWindow_Type" json:"type,omitempty"`
+	Duration *durationpb.Duration   `protobuf:"bytes,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	// The reset anchor of calendar windows. Defaults to the Unix epoch, so that
+	// whole-day windows reset at UTC midnight.
+	Anchor       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=anchor,proto3" json:"anchor,omitempty"`
+	RequestLimit uint64                 `protobuf:"varint,4,opt,name=request_limit,json=requestLimit,proto3" json:"request_limit,omitempty"`
+	TokenLimit   uint64                 `protobuf:"varint,5,opt,name=token_limit,json=tokenLimit,proto3" json:"token_limit,omitempty"`
+	// The length of calendar windows in calendar months, instead of duration,
+	// e.g. 1 for monthly credit. Windows start on the day and time of anchor.
+	Months        uint32 `protobuf:"varint,6,opt,name=months,proto3" json:"months,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateWindow) Reset() {
+	*x = RateWindow{}
+	mi := &file_conf_upstream_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateWindow) ProtoMessage() {}
+
+func (x *RateWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateWindow.ProtoReflect.Descriptor instead.
+func (*RateWindow) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RateWindow) GetType() RateWindow_Type {
+	if x != nil {
+		return x.Type
+	}
+	return RateWindow_TYPE_UNSPECIFIED
+}
+
+func (x *RateWindow) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *RateWindow) GetAnchor() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Anchor
+	}
+	return nil
+}
+
+func (x *RateWindow) GetRequestLimit() uint64 {
+	if x != nil {
+		return x.RequestLimit
+	}
+	return 0
+}
+
+func (x *RateWindow) GetTokenLimit() uint64 {
+	if x != nil {
+		return x.TokenLimit
+	}
+	return 0
+}
+
+func (x *RateWindow) GetMonths() uint32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
 type UpstreamConfig struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The unique name of the upstream.
@@ -283,12 +627,23 @@ type UpstreamConfig struct {
 	Models []*Model `protobuf:"bytes,2,rep,name=models,proto3" json:"models,omitempty"`
 	// Scheduling configs for this upstream.
 	Scheduling *UpstreamScheduling `protobuf:"bytes,3,opt,name=scheduling,proto3" json:"scheduling,omitempty"`
+	// API keys pooled by this upstream, each overriding the key of the provider
+	// config. Every credential is elected as a separate candidate serving the
+	// models of the upstream. Supported by OpenAI, Google and Anthropic.
+	Credentials []*Credential `protobuf:"bytes,4,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	// Outbound HTTP settings of the upstream, shared by its credentials. Applies
+	// to the OpenAI, Anthropic, Google and Ollama providers, and is rejected for
+	// Neurouter.
+	Transport *HTTPTransport `protobuf:"bytes,5,opt,name=transport,proto3" json:"transport,omitempty"`
+	// Registers the models the upstream lists alongside the configured ones.
+	Discovery *Discovery `protobuf:"bytes,6,opt,name=discovery,proto3" json:"discovery,omitempty"`
 	// Types that are valid to be assigned to Config:
 	//
 	//	*UpstreamConfig_Neurouter
 	//	*UpstreamConfig_OpenAi
 	//	*UpstreamConfig_Google
 	//	*UpstreamConfig_Anthropic
+	//	*UpstreamConfig_Ollama
 	Config        isUpstreamConfig_Config `protobuf_oneof:"config"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -296,7 +651,7 @@ type UpstreamConfig struct {
 
 func (x *UpstreamConfig) Reset() {
 	*x = UpstreamConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[2]
+	mi := &file_conf_upstream_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -308,7 +663,7 @@ func (x *UpstreamConfig) String() string {
 func (*UpstreamConfig) ProtoMessage() {}
 
 func (x *UpstreamConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[2]
+	mi := &file_conf_upstream_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -321,7 +676,7 @@ func (x *UpstreamConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpstreamConfig.ProtoReflect.Descriptor instead.
 func (*UpstreamConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{2}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UpstreamConfig) GetName() string {
@@ -345,6 +700,27 @@ func (x *UpstreamConfig) GetScheduling() *UpstreamScheduling {
 	return nil
 }
 
+func (x *UpstreamConfig) GetCredentials() []*Credential {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *UpstreamConfig) GetTransport() *HTTPTransport {
+	if x != nil {
+		return x.Transport
+	}
+	return nil
+}
+
+func (x *UpstreamConfig) GetDiscovery() *Discovery {
+	if x != nil {
+		return x.Discovery
+	}
+	return nil
+}
+
 func (x *UpstreamConfig) GetConfig() isUpstreamConfig_Config {
 	if x != nil {
 		return x.Config
@@ -388,6 +764,15 @@ func (x *UpstreamConfig) GetAnthropic() *AnthropicConfig {
 	return nil
 }
 
+func (x *UpstreamConfig) GetOllama() *OllamaConfig {
+	if x != nil {
+		if x, ok := x.Config.(*UpstreamConfig_Ollama); ok {
+			return x.Ollama
+		}
+	}
+	return nil
+}
+
 type isUpstreamConfig_Config interface {
 	isUpstreamConfig_Config()
 }
@@ -408,6 +793,10 @@ type UpstreamConfig_Anthropic struct {
 	Anthropic *AnthropicConfig `protobuf:"bytes,103,opt,name=anthropic,proto3,oneof"`
 }
 
+type UpstreamConfig_Ollama struct {
+	Ollama *OllamaConfig `protobuf:"bytes,104,opt,name=ollama,proto3,oneof"`
+}
+
 func (*UpstreamConfig_Neurouter) isUpstreamConfig_Config() {}
 
 func (*UpstreamConfig_OpenAi) isUpstreamConfig_Config() {}
@@ -416,20 +805,325 @@ func (*UpstreamConfig_Google) isUpstreamConfig_Config() {}
 
 func (*UpstreamConfig_Anthropic) isUpstreamConfig_Config() {}
 
+func (*UpstreamConfig_Ollama) isUpstreamConfig_Config() {}
+
+// Credential is an API key with limits of its own. A credential the upstream
+// rejects with 401, or with a 403 saying the key is invalid, is disabled until
+// enabled again through the admin API or a restart.
+type Credential struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identifies the credential in logs, metrics and the admin API. Defaults to
+	// its position in the list.
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ApiKey string `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// Limits of the credential, enforced in addition to those of the upstream.
+	// Token weights and the embedding batch size are taken from the upstream.
+	Scheduling    *UpstreamScheduling `protobuf:"bytes,3,opt,name=scheduling,proto3" json:"scheduling,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Credential) Reset() {
+	*x = Credential{}
+	mi := &file_conf_upstream_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Credential) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credential) ProtoMessage() {}
+
+func (x *Credential) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credential.ProtoReflect.Descriptor instead.
+func (*Credential) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Credential) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Credential) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *Credential) GetScheduling() *UpstreamScheduling {
+	if x != nil {
+		return x.Scheduling
+	}
+	return nil
+}
+
+// Discovery lists the models of an upstream from its API, at startup and
+// periodically. Configured models take precedence over listed ones with the
+// same ID.
+type Discovery struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Enabled bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// How often the models are listed again. Listed at startup only if unset.
+	Interval *durationpb.Duration `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Glob patterns of the model IDs to register, where '*' matches any
+	// characters, slashes included, and '?' any single character. Every listed
+	// model is registered if empty.
+	Include []string `protobuf:"bytes,3,rep,name=include,proto3" json:"include,omitempty"`
+	// Glob patterns of the model IDs to leave out.
+	Exclude []string `protobuf:"bytes,4,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	// Capabilities added to those the upstream reports for its models.
+	Capabilities  []Capability `protobuf:"varint,5,rep,packed,name=capabilities,proto3,enum=neurouter.config.v1.Capability" json:"capabilities,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Discovery) Reset() {
+	*x = Discovery{}
+	mi := &file_conf_upstream_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Discovery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discovery) ProtoMessage() {}
+
+func (x *Discovery) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discovery.ProtoReflect.Descriptor instead.
+func (*Discovery) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Discovery) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Discovery) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *Discovery) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *Discovery) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *Discovery) GetCapabilities() []Capability {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+// HTTPTransport configures the connections to an upstream. Unset fields keep
+// the defaults of Go's http.DefaultTransport.
+type HTTPTransport struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Proxy for outbound requests, e.g. "http://proxy:3128" or
+	// "socks5://proxy:1080". Defaults to the HTTP_PROXY, HTTPS_PROXY and
+	// NO_PROXY environment variables.
+	ProxyUrl string `protobuf:"bytes,1,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	// PEM bundle of CAs trusted in addition to the system roots.
+	CaFile string `protobuf:"bytes,2,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	// PEM client certificate and key presented for mutual TLS, set together.
+	CertFile string `protobuf:"bytes,3,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	KeyFile  string `protobuf:"bytes,4,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// Skips verification of the server certificate. For testing only.
+	InsecureSkipVerify bool `protobuf:"varint,5,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+	// Time allowed to establish a TCP connection.
+	ConnectTimeout *durationpb.Duration `protobuf:"bytes,6,opt,name=connect_timeout,json=connectTimeout,proto3" json:"connect_timeout,omitempty"`
+	// Time allowed for the TLS handshake.
+	TlsHandshakeTimeout *durationpb.Duration `protobuf:"bytes,7,opt,name=tls_handshake_timeout,json=tlsHandshakeTimeout,proto3" json:"tls_handshake_timeout,omitempty"`
+	// Time allowed between sending a request and receiving the response
+	// headers. Streamed bodies are not limited.
+	ResponseHeaderTimeout *durationpb.Duration `protobuf:"bytes,8,opt,name=response_header_timeout,json=responseHeaderTimeout,proto3" json:"response_header_timeout,omitempty"`
+	// Time an idle connection is kept in the pool.
+	IdleConnTimeout *durationpb.Duration `protobuf:"bytes,9,opt,name=idle_conn_timeout,json=idleConnTimeout,proto3" json:"idle_conn_timeout,omitempty"`
+	// Idle connections kept in the pool, in total and per host.
+	MaxIdleConns        uint32 `protobuf:"varint,10,opt,name=max_idle_conns,json=maxIdleConns,proto3" json:"max_idle_conns,omitempty"`
+	MaxIdleConnsPerHost uint32 `protobuf:"varint,11,opt,name=max_idle_conns_per_host,json=maxIdleConnsPerHost,proto3" json:"max_idle_conns_per_host,omitempty"`
+	// Connections per host, including those in use. 0 means unlimited.
+	MaxConnsPerHost uint32 `protobuf:"varint,12,opt,name=max_conns_per_host,json=maxConnsPerHost,proto3" json:"max_conns_per_host,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *HTTPTransport) Reset() {
+	*x = HTTPTransport{}
+	mi := &file_conf_upstream_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HTTPTransport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HTTPTransport) ProtoMessage() {}
+
+func (x *HTTPTransport) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HTTPTransport.ProtoReflect.Descriptor instead.
+func (*HTTPTransport) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HTTPTransport) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+func (x *HTTPTransport) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *HTTPTransport) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *HTTPTransport) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *HTTPTransport) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
+func (x *HTTPTransport) GetConnectTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ConnectTimeout
+	}
+	return nil
+}
+
+func (x *HTTPTransport) GetTlsHandshakeTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.TlsHandshakeTimeout
+	}
+	return nil
+}
+
+func (x *HTTPTransport) GetResponseHeaderTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.ResponseHeaderTimeout
+	}
+	return nil
+}
+
+func (x *HTTPTransport) GetIdleConnTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.IdleConnTimeout
+	}
+	return nil
+}
+
+func (x *HTTPTransport) GetMaxIdleConns() uint32 {
+	if x != nil {
+		return x.MaxIdleConns
+	}
+	return 0
+}
+
+func (x *HTTPTransport) GetMaxIdleConnsPerHost() uint32 {
+	if x != nil {
+		return x.MaxIdleConnsPerHost
+	}
+	return 0
+}
+
+func (x *HTTPTransport) GetMaxConnsPerHost() uint32 {
+	if x != nil {
+		return x.MaxConnsPerHost
+	}
+	return 0
+}
+
 type ModelScheduling struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	TpmLimit         uint64                 `protobuf:"varint,1,opt,name=tpm_limit,json=tpmLimit,proto3" json:"tpm_limit,omitempty"`
-	TpdLimit         uint64                 `protobuf:"varint,2,opt,name=tpd_limit,json=tpdLimit,proto3" json:"tpd_limit,omitempty"`
-	RpmLimit         uint64                 `protobuf:"varint,3,opt,name=rpm_limit,json=rpmLimit,proto3" json:"rpm_limit,omitempty"`
-	RpdLimit         uint64                 `protobuf:"varint,4,opt,name=rpd_limit,json=rpdLimit,proto3" json:"rpd_limit,omitempty"`
-	ConcurrencyLimit uint64                 `protobuf:"varint,5,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Shorthands for common windows: TPM and RPM are token buckets refilling
+	// over a minute, TPD and RPD are calendar days starting at UTC midnight.
+	TpmLimit         uint64 `protobuf:"varint,1,opt,name=tpm_limit,json=tpmLimit,proto3" json:"tpm_limit,omitempty"`
+	TpdLimit         uint64 `protobuf:"varint,2,opt,name=tpd_limit,json=tpdLimit,proto3" json:"tpd_limit,omitempty"`
+	RpmLimit         uint64 `protobuf:"varint,3,opt,name=rpm_limit,json=rpmLimit,proto3" json:"rpm_limit,omitempty"`
+	RpdLimit         uint64 `protobuf:"varint,4,opt,name=rpd_limit,json=rpdLimit,proto3" json:"rpd_limit,omitempty"`
+	ConcurrencyLimit uint64 `protobuf:"varint,5,opt,name=concurrency_limit,json=concurrencyLimit,proto3" json:"concurrency_limit,omitempty"`
+	// Additional windows of arbitrary duration.
+	Windows       []*RateWindow `protobuf:"bytes,6,rep,name=windows,proto3" json:"windows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ModelScheduling) Reset() {
 	*x = ModelScheduling{}
-	mi := &file_conf_upstream_proto_msgTypes[3]
+	mi := &file_conf_upstream_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -441,7 +1135,7 @@ func (x *ModelScheduling) String() string {
 func (*ModelScheduling) ProtoMessage() {}
 
 func (x *ModelScheduling) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[3]
+	mi := &file_conf_upstream_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -454,7 +1148,7 @@ func (x *ModelScheduling) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ModelScheduling.ProtoReflect.Descriptor instead.
 func (*ModelScheduling) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{3}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ModelScheduling) GetTpmLimit() uint64 {
@@ -492,6 +1186,13 @@ func (x *ModelScheduling) GetConcurrencyLimit() uint64 {
 	return 0
 }
 
+func (x *ModelScheduling) GetWindows() []*RateWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
 type Model struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// The unique identifier of the model.
@@ -512,13 +1213,20 @@ type Model struct {
 	Scheduling *ModelScheduling `protobuf:"bytes,8,opt,name=scheduling,proto3" json:"scheduling,omitempty"`
 	// The context length (max tokens) supported by the model.
 	ContextLength uint32 `protobuf:"varint,9,opt,name=context_length,json=contextLength,proto3" json:"context_length,omitempty"`
+	// How structured output is served without CAPABILITY_STRUCTURED_OUTPUT.
+	StructuredOutput *StructuredOutput `protobuf:"bytes,10,opt,name=structured_output,json=structuredOutput,proto3" json:"structured_output,omitempty"`
+	// Timeouts of chat requests to the model. Unset means no timeout besides
+	// that of the server.
+	Timeouts *Timeouts `protobuf:"bytes,11,opt,name=timeouts,proto3" json:"timeouts,omitempty"`
+	// How tool calls are served for models without native function calling.
+	ToolUse       *ToolUse `protobuf:"bytes,12,opt,name=tool_use,json=toolUse,proto3" json:"tool_use,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Model) Reset() {
 	*x = Model{}
-	mi := &file_conf_upstream_proto_msgTypes[4]
+	mi := &file_conf_upstream_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -530,7 +1238,7 @@ func (x *Model) String() string {
 func (*Model) ProtoMessage() {}
 
 func (x *Model) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[4]
+	mi := &file_conf_upstream_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -543,7 +1251,7 @@ func (x *Model) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Model.ProtoReflect.Descriptor instead.
 func (*Model) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{4}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Model) GetId() string {
@@ -609,6 +1317,195 @@ func (x *Model) GetContextLength() uint32 {
 	return 0
 }
 
+func (x *Model) GetStructuredOutput() *StructuredOutput {
+	if x != nil {
+		return x.StructuredOutput
+	}
+	return nil
+}
+
+func (x *Model) GetTimeouts() *Timeouts {
+	if x != nil {
+		return x.Timeouts
+	}
+	return nil
+}
+
+func (x *Model) GetToolUse() *ToolUse {
+	if x != nil {
+		return x.ToolUse
+	}
+	return nil
+}
+
+// Timeouts abort chat requests to an upstream that stalls, with an upstream
+// timeout error.
+type Timeouts struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Time allowed until the first event of a stream. Requests that are not
+	// streamed are not bounded by it.
+	FirstEvent *durationpb.Duration `protobuf:"bytes,1,opt,name=first_event,json=firstEvent,proto3" json:"first_event,omitempty"`
+	// Time allowed between two events of a stream. Requests that are not
+	// streamed are not bounded by it.
+	Idle *durationpb.Duration `protobuf:"bytes,2,opt,name=idle,proto3" json:"idle,omitempty"`
+	// Time allowed for the whole request, streamed or not.
+	Total         *durationpb.Duration `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Timeouts) Reset() {
+	*x = Timeouts{}
+	mi := &file_conf_upstream_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Timeouts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timeouts) ProtoMessage() {}
+
+func (x *Timeouts) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timeouts.ProtoReflect.Descriptor instead.
+func (*Timeouts) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Timeouts) GetFirstEvent() *durationpb.Duration {
+	if x != nil {
+		return x.FirstEvent
+	}
+	return nil
+}
+
+func (x *Timeouts) GetIdle() *durationpb.Duration {
+	if x != nil {
+		return x.Idle
+	}
+	return nil
+}
+
+func (x *Timeouts) GetTotal() *durationpb.Duration {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+// StructuredOutput emulates structured output for models that do not follow
+// a requested JSON schema natively.
+type StructuredOutput struct {
+	state     protoimpl.MessageState     `protogen:"open.v1"`
+	Emulation StructuredOutput_Emulation `protobuf:"varint,1,opt,name=emulation,proto3,enum=neurouter.config.v1.StructuredOutput_Emulation" json:"emulation,omitempty"`
+	// How many times a reply not matching the schema is retried, with the
+	// validation error fed back to the model.
+	MaxRetries    uint32 `protobuf:"varint,2,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StructuredOutput) Reset() {
+	*x = StructuredOutput{}
+	mi := &file_conf_upstream_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StructuredOutput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StructuredOutput) ProtoMessage() {}
+
+func (x *StructuredOutput) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StructuredOutput.ProtoReflect.Descriptor instead.
+func (*StructuredOutput) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *StructuredOutput) GetEmulation() StructuredOutput_Emulation {
+	if x != nil {
+		return x.Emulation
+	}
+	return StructuredOutput_EMULATION_UNSPECIFIED
+}
+
+func (x *StructuredOutput) GetMaxRetries() uint32 {
+	if x != nil {
+		return x.MaxRetries
+	}
+	return 0
+}
+
+// ToolUse emulates tool calling for models without native function calling.
+type ToolUse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emulation     ToolUse_Emulation      `protobuf:"varint,1,opt,name=emulation,proto3,enum=neurouter.config.v1.ToolUse_Emulation" json:"emulation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolUse) Reset() {
+	*x = ToolUse{}
+	mi := &file_conf_upstream_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolUse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolUse) ProtoMessage() {}
+
+func (x *ToolUse) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolUse.ProtoReflect.Descriptor instead.
+func (*ToolUse) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ToolUse) GetEmulation() ToolUse_Emulation {
+	if x != nil {
+		return x.Emulation
+	}
+	return ToolUse_EMULATION_UNSPECIFIED
+}
+
 type NeurouterConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Endpoint      string                 `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
@@ -618,7 +1515,7 @@ type NeurouterConfig struct {
 
 func (x *NeurouterConfig) Reset() {
 	*x = NeurouterConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[5]
+	mi := &file_conf_upstream_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -630,7 +1527,7 @@ func (x *NeurouterConfig) String() string {
 func (*NeurouterConfig) ProtoMessage() {}
 
 func (x *NeurouterConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[5]
+	mi := &file_conf_upstream_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,7 +1540,7 @@ func (x *NeurouterConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NeurouterConfig.ProtoReflect.Descriptor instead.
 func (*NeurouterConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{5}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *NeurouterConfig) GetEndpoint() string {
@@ -667,13 +1564,17 @@ type OpenAIConfig struct {
 	PreferStringContentForAssistant bool `protobuf:"varint,8,opt,name=prefer_string_content_for_assistant,json=preferStringContentForAssistant,proto3" json:"prefer_string_content_for_assistant,omitempty"`
 	PreferStringContentForTool      bool `protobuf:"varint,9,opt,name=prefer_string_content_for_tool,json=preferStringContentForTool,proto3" json:"prefer_string_content_for_tool,omitempty"`
 	PreferSinglePartContent         bool `protobuf:"varint,10,opt,name=prefer_single_part_content,json=preferSinglePartContent,proto3" json:"prefer_single_part_content,omitempty"`
-	unknownFields                   protoimpl.UnknownFields
-	sizeCache                       protoimpl.SizeCache
+	// Extracts reasoning and tool calls written inline in the text content, as
+	// engines like vLLM, SGLang and llama.cpp return them when run without
+	// reasoning or tool parsers. Applies to the Chat Completions API.
+	InlineOutputParser *InlineOutputParser `protobuf:"bytes,11,opt,name=inline_output_parser,json=inlineOutputParser,proto3" json:"inline_output_parser,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *OpenAIConfig) Reset() {
 	*x = OpenAIConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[6]
+	mi := &file_conf_upstream_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -685,7 +1586,7 @@ func (x *OpenAIConfig) String() string {
 func (*OpenAIConfig) ProtoMessage() {}
 
 func (x *OpenAIConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[6]
+	mi := &file_conf_upstream_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -698,7 +1599,7 @@ func (x *OpenAIConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OpenAIConfig.ProtoReflect.Descriptor instead.
 func (*OpenAIConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{6}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *OpenAIConfig) GetApiKey() string {
@@ -771,6 +1672,78 @@ func (x *OpenAIConfig) GetPreferSinglePartContent() bool {
 	return false
 }
 
+func (x *OpenAIConfig) GetInlineOutputParser() *InlineOutputParser {
+	if x != nil {
+		return x.InlineOutputParser
+	}
+	return nil
+}
+
+type InlineOutputParser struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Turns text within <think></think> tags into reasoning.
+	ThinkTags bool `protobuf:"varint,1,opt,name=think_tags,json=thinkTags,proto3" json:"think_tags,omitempty"`
+	// Starts the output within <think>, for chat templates that open the tag
+	// at the end of the prompt, so that the output only closes it.
+	ThinkOpened bool `protobuf:"varint,2,opt,name=think_opened,json=thinkOpened,proto3" json:"think_opened,omitempty"`
+	// Turns JSON within <tool_call></tool_call> tags into tool uses, as
+	// written by Hermes and Qwen style models.
+	ToolCallTags  bool `protobuf:"varint,3,opt,name=tool_call_tags,json=toolCallTags,proto3" json:"tool_call_tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InlineOutputParser) Reset() {
+	*x = InlineOutputParser{}
+	mi := &file_conf_upstream_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InlineOutputParser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InlineOutputParser) ProtoMessage() {}
+
+func (x *InlineOutputParser) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InlineOutputParser.ProtoReflect.Descriptor instead.
+func (*InlineOutputParser) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InlineOutputParser) GetThinkTags() bool {
+	if x != nil {
+		return x.ThinkTags
+	}
+	return false
+}
+
+func (x *InlineOutputParser) GetThinkOpened() bool {
+	if x != nil {
+		return x.ThinkOpened
+	}
+	return false
+}
+
+func (x *InlineOutputParser) GetToolCallTags() bool {
+	if x != nil {
+		return x.ToolCallTags
+	}
+	return false
+}
+
 type GoogleConfig struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	ApiKey string                 `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
@@ -782,7 +1755,7 @@ type GoogleConfig struct {
 
 func (x *GoogleConfig) Reset() {
 	*x = GoogleConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[7]
+	mi := &file_conf_upstream_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -794,7 +1767,7 @@ func (x *GoogleConfig) String() string {
 func (*GoogleConfig) ProtoMessage() {}
 
 func (x *GoogleConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[7]
+	mi := &file_conf_upstream_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -807,7 +1780,7 @@ func (x *GoogleConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GoogleConfig.ProtoReflect.Descriptor instead.
 func (*GoogleConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{7}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GoogleConfig) GetApiKey() string {
@@ -838,7 +1811,7 @@ type AnthropicConfig struct {
 
 func (x *AnthropicConfig) Reset() {
 	*x = AnthropicConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[8]
+	mi := &file_conf_upstream_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -850,7 +1823,7 @@ func (x *AnthropicConfig) String() string {
 func (*AnthropicConfig) ProtoMessage() {}
 
 func (x *AnthropicConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[8]
+	mi := &file_conf_upstream_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -863,7 +1836,7 @@ func (x *AnthropicConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AnthropicConfig.ProtoReflect.Descriptor instead.
 func (*AnthropicConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{8}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *AnthropicConfig) GetApiKey() string {
@@ -901,6 +1874,79 @@ func (x *AnthropicConfig) GetSystemAsUser() bool {
 	return false
 }
 
+type OllamaConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to http://localhost:11434.
+	BaseUrl string            `protobuf:"bytes,1,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	Headers map[string]string `protobuf:"bytes,2,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// How long models stay loaded after a request, e.g. "10m" or "-1" to keep
+	// them loaded. Defaults to the setting of the Ollama server.
+	KeepAlive string `protobuf:"bytes,3,opt,name=keep_alive,json=keepAlive,proto3" json:"keep_alive,omitempty"`
+	// The context window size, passed as the num_ctx option. Defaults to the
+	// setting of the Ollama server.
+	NumCtx        uint32 `protobuf:"varint,4,opt,name=num_ctx,json=numCtx,proto3" json:"num_ctx,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OllamaConfig) Reset() {
+	*x = OllamaConfig{}
+	mi := &file_conf_upstream_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OllamaConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OllamaConfig) ProtoMessage() {}
+
+func (x *OllamaConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_upstream_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OllamaConfig.ProtoReflect.Descriptor instead.
+func (*OllamaConfig) Descriptor() ([]byte, []int) {
+	return file_conf_upstream_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *OllamaConfig) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+func (x *OllamaConfig) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *OllamaConfig) GetKeepAlive() string {
+	if x != nil {
+		return x.KeepAlive
+	}
+	return ""
+}
+
+func (x *OllamaConfig) GetNumCtx() uint32 {
+	if x != nil {
+		return x.NumCtx
+	}
+	return 0
+}
+
 type AliasConfig struct {
 	state         protoimpl.MessageState    `protogen:"open.v1"`
 	Id            string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -912,7 +1958,7 @@ type AliasConfig struct {
 
 func (x *AliasConfig) Reset() {
 	*x = AliasConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[9]
+	mi := &file_conf_upstream_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -924,7 +1970,7 @@ func (x *AliasConfig) String() string {
 func (*AliasConfig) ProtoMessage() {}
 
 func (x *AliasConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[9]
+	mi := &file_conf_upstream_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -937,7 +1983,7 @@ func (x *AliasConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AliasConfig.ProtoReflect.Descriptor instead.
 func (*AliasConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{9}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *AliasConfig) GetId() string {
@@ -971,7 +2017,7 @@ type AliasConfig_ActualConfig struct {
 
 func (x *AliasConfig_ActualConfig) Reset() {
 	*x = AliasConfig_ActualConfig{}
-	mi := &file_conf_upstream_proto_msgTypes[12]
+	mi := &file_conf_upstream_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -983,7 +2029,7 @@ func (x *AliasConfig_ActualConfig) String() string {
 func (*AliasConfig_ActualConfig) ProtoMessage() {}
 
 func (x *AliasConfig_ActualConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_upstream_proto_msgTypes[12]
+	mi := &file_conf_upstream_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -996,7 +2042,7 @@ func (x *AliasConfig_ActualConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AliasConfig_ActualConfig.ProtoReflect.Descriptor instead.
 func (*AliasConfig_ActualConfig) Descriptor() ([]byte, []int) {
-	return file_conf_upstream_proto_rawDescGZIP(), []int{9, 0}
+	return file_conf_upstream_proto_rawDescGZIP(), []int{19, 0}
 }
 
 func (x *AliasConfig_ActualConfig) GetUpstream() string {
@@ -1017,33 +2063,89 @@ var File_conf_upstream_proto protoreflect.FileDescriptor
 
 const file_conf_upstream_proto_rawDesc = "" +
 	"\n" +
-	"\x13conf/upstream.proto\x12\x13neurouter.config.v1\"\x85\x01\n" +
+	"\x13conf/upstream.proto\x12\x13neurouter.config.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x85\x01\n" +
 	"\bUpstream\x12=\n" +
 	"\aconfigs\x18\x01 \x03(\v2#.neurouter.config.v1.UpstreamConfigR\aconfigs\x12:\n" +
-	"\aaliases\x18\x02 \x03(\v2 .neurouter.config.v1.AliasConfigR\aaliases\"\xb5\x01\n" +
+	"\aaliases\x18\x02 \x03(\v2 .neurouter.config.v1.AliasConfigR\aaliases\"\xea\x02\n" +
 	"\x12UpstreamScheduling\x12\x1b\n" +
 	"\ttpm_limit\x18\x01 \x01(\x04R\btpmLimit\x12\x1b\n" +
 	"\ttpd_limit\x18\x02 \x01(\x04R\btpdLimit\x12\x1b\n" +
 	"\trpm_limit\x18\x03 \x01(\x04R\brpmLimit\x12\x1b\n" +
 	"\trpd_limit\x18\x04 \x01(\x04R\brpdLimit\x12+\n" +
-	"\x11concurrency_limit\x18\x05 \x01(\x04R\x10concurrencyLimit\"\xb2\x03\n" +
+	"\x11concurrency_limit\x18\x05 \x01(\x04R\x10concurrencyLimit\x129\n" +
+	"\awindows\x18\x06 \x03(\v2\x1f.neurouter.config.v1.RateWindowR\awindows\x12F\n" +
+	"\rtoken_weights\x18\a \x01(\v2!.neurouter.config.v1.TokenWeightsR\ftokenWeights\x120\n" +
+	"\x14embedding_batch_size\x18\b \x01(\rR\x12embeddingBatchSize\"\xa0\x01\n" +
+	"\fTokenWeights\x12&\n" +
+	"\fcached_input\x18\x01 \x01(\x01H\x00R\vcachedInput\x88\x01\x01\x12\x1b\n" +
+	"\x06output\x18\x02 \x01(\x01H\x01R\x06output\x88\x01\x01\x12!\n" +
+	"\treasoning\x18\x03 \x01(\x01H\x02R\treasoning\x88\x01\x01B\x0f\n" +
+	"\r_cached_inputB\t\n" +
+	"\a_outputB\f\n" +
+	"\n" +
+	"_reasoning\"\xd2\x02\n" +
+	"\n" +
+	"RateWindow\x128\n" +
+	"\x04type\x18\x01 \x01(\x0e2$.neurouter.config.v1.RateWindow.TypeR\x04type\x125\n" +
+	"\bduration\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\bduration\x122\n" +
+	"\x06anchor\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06anchor\x12#\n" +
+	"\rrequest_limit\x18\x04 \x01(\x04R\frequestLimit\x12\x1f\n" +
+	"\vtoken_limit\x18\x05 \x01(\x04R\n" +
+	"tokenLimit\x12\x16\n" +
+	"\x06months\x18\x06 \x01(\rR\x06months\"A\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_SLIDING\x10\x01\x12\x11\n" +
+	"\rTYPE_CALENDAR\x10\x02\"\xb2\x05\n" +
 	"\x0eUpstreamConfig\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x122\n" +
 	"\x06models\x18\x02 \x03(\v2\x1a.neurouter.config.v1.ModelR\x06models\x12G\n" +
 	"\n" +
 	"scheduling\x18\x03 \x01(\v2'.neurouter.config.v1.UpstreamSchedulingR\n" +
-	"scheduling\x12D\n" +
+	"scheduling\x12A\n" +
+	"\vcredentials\x18\x04 \x03(\v2\x1f.neurouter.config.v1.CredentialR\vcredentials\x12@\n" +
+	"\ttransport\x18\x05 \x01(\v2\".neurouter.config.v1.HTTPTransportR\ttransport\x12<\n" +
+	"\tdiscovery\x18\x06 \x01(\v2\x1e.neurouter.config.v1.DiscoveryR\tdiscovery\x12D\n" +
 	"\tneurouter\x18d \x01(\v2$.neurouter.config.v1.NeurouterConfigH\x00R\tneurouter\x12<\n" +
 	"\aopen_ai\x18e \x01(\v2!.neurouter.config.v1.OpenAIConfigH\x00R\x06openAi\x12;\n" +
 	"\x06google\x18f \x01(\v2!.neurouter.config.v1.GoogleConfigH\x00R\x06google\x12D\n" +
-	"\tanthropic\x18g \x01(\v2$.neurouter.config.v1.AnthropicConfigH\x00R\tanthropicB\b\n" +
-	"\x06config\"\xb2\x01\n" +
+	"\tanthropic\x18g \x01(\v2$.neurouter.config.v1.AnthropicConfigH\x00R\tanthropic\x12;\n" +
+	"\x06ollama\x18h \x01(\v2!.neurouter.config.v1.OllamaConfigH\x00R\x06ollamaB\b\n" +
+	"\x06config\"\x82\x01\n" +
+	"\n" +
+	"Credential\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\x12G\n" +
+	"\n" +
+	"scheduling\x18\x03 \x01(\v2'.neurouter.config.v1.UpstreamSchedulingR\n" +
+	"scheduling\"\xd5\x01\n" +
+	"\tDiscovery\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x18\n" +
+	"\ainclude\x18\x03 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x04 \x03(\tR\aexclude\x12C\n" +
+	"\fcapabilities\x18\x05 \x03(\x0e2\x1f.neurouter.config.v1.CapabilityR\fcapabilities\"\xe5\x04\n" +
+	"\rHTTPTransport\x12\x1b\n" +
+	"\tproxy_url\x18\x01 \x01(\tR\bproxyUrl\x12\x17\n" +
+	"\aca_file\x18\x02 \x01(\tR\x06caFile\x12\x1b\n" +
+	"\tcert_file\x18\x03 \x01(\tR\bcertFile\x12\x19\n" +
+	"\bkey_file\x18\x04 \x01(\tR\akeyFile\x120\n" +
+	"\x14insecure_skip_verify\x18\x05 \x01(\bR\x12insecureSkipVerify\x12B\n" +
+	"\x0fconnect_timeout\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x0econnectTimeout\x12M\n" +
+	"\x15tls_handshake_timeout\x18\a \x01(\v2\x19.google.protobuf.DurationR\x13tlsHandshakeTimeout\x12Q\n" +
+	"\x17response_header_timeout\x18\b \x01(\v2\x19.google.protobuf.DurationR\x15responseHeaderTimeout\x12E\n" +
+	"\x11idle_conn_timeout\x18\t \x01(\v2\x19.google.protobuf.DurationR\x0fidleConnTimeout\x12$\n" +
+	"\x0emax_idle_conns\x18\n" +
+	" \x01(\rR\fmaxIdleConns\x124\n" +
+	"\x17max_idle_conns_per_host\x18\v \x01(\rR\x13maxIdleConnsPerHost\x12+\n" +
+	"\x12max_conns_per_host\x18\f \x01(\rR\x0fmaxConnsPerHost\"\xed\x01\n" +
 	"\x0fModelScheduling\x12\x1b\n" +
 	"\ttpm_limit\x18\x01 \x01(\x04R\btpmLimit\x12\x1b\n" +
 	"\ttpd_limit\x18\x02 \x01(\x04R\btpdLimit\x12\x1b\n" +
 	"\trpm_limit\x18\x03 \x01(\x04R\brpmLimit\x12\x1b\n" +
 	"\trpd_limit\x18\x04 \x01(\x04R\brpdLimit\x12+\n" +
-	"\x11concurrency_limit\x18\x05 \x01(\x04R\x10concurrencyLimit\"\xef\x02\n" +
+	"\x11concurrency_limit\x18\x05 \x01(\x04R\x10concurrencyLimit\x129\n" +
+	"\awindows\x18\x06 \x03(\v2\x1f.neurouter.config.v1.RateWindowR\awindows\"\xb7\x04\n" +
 	"\x05Model\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vupstream_id\x18\x02 \x01(\tR\n" +
@@ -1058,9 +2160,31 @@ const file_conf_upstream_proto_rawDesc = "" +
 	"\n" +
 	"scheduling\x18\b \x01(\v2$.neurouter.config.v1.ModelSchedulingR\n" +
 	"scheduling\x12%\n" +
-	"\x0econtext_length\x18\t \x01(\rR\rcontextLength\"-\n" +
+	"\x0econtext_length\x18\t \x01(\rR\rcontextLength\x12R\n" +
+	"\x11structured_output\x18\n" +
+	" \x01(\v2%.neurouter.config.v1.StructuredOutputR\x10structuredOutput\x129\n" +
+	"\btimeouts\x18\v \x01(\v2\x1d.neurouter.config.v1.TimeoutsR\btimeouts\x127\n" +
+	"\btool_use\x18\f \x01(\v2\x1c.neurouter.config.v1.ToolUseR\atoolUse\"\xa6\x01\n" +
+	"\bTimeouts\x12:\n" +
+	"\vfirst_event\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"firstEvent\x12-\n" +
+	"\x04idle\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x04idle\x12/\n" +
+	"\x05total\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x05total\"\xd9\x01\n" +
+	"\x10StructuredOutput\x12M\n" +
+	"\temulation\x18\x01 \x01(\x0e2/.neurouter.config.v1.StructuredOutput.EmulationR\temulation\x12\x1f\n" +
+	"\vmax_retries\x18\x02 \x01(\rR\n" +
+	"maxRetries\"U\n" +
+	"\tEmulation\x12\x19\n" +
+	"\x15EMULATION_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eEMULATION_TOOL\x10\x01\x12\x19\n" +
+	"\x15EMULATION_INSTRUCTION\x10\x02\"\x8d\x01\n" +
+	"\aToolUse\x12D\n" +
+	"\temulation\x18\x01 \x01(\x0e2&.neurouter.config.v1.ToolUse.EmulationR\temulation\"<\n" +
+	"\tEmulation\x12\x19\n" +
+	"\x15EMULATION_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10EMULATION_HERMES\x10\x01\"-\n" +
 	"\x0fNeurouterConfig\x12\x1a\n" +
-	"\bendpoint\x18\x01 \x01(\tR\bendpoint\"\x8e\x05\n" +
+	"\bendpoint\x18\x01 \x01(\tR\bendpoint\"\xe9\x05\n" +
 	"\fOpenAIConfig\x12\x17\n" +
 	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12\x19\n" +
 	"\bbase_url\x18\x02 \x01(\tR\abaseUrl\x12H\n" +
@@ -1072,10 +2196,16 @@ const file_conf_upstream_proto_rawDesc = "" +
 	"#prefer_string_content_for_assistant\x18\b \x01(\bR\x1fpreferStringContentForAssistant\x12B\n" +
 	"\x1eprefer_string_content_for_tool\x18\t \x01(\bR\x1apreferStringContentForTool\x12;\n" +
 	"\x1aprefer_single_part_content\x18\n" +
-	" \x01(\bR\x17preferSinglePartContent\x1a:\n" +
+	" \x01(\bR\x17preferSinglePartContent\x12Y\n" +
+	"\x14inline_output_parser\x18\v \x01(\v2'.neurouter.config.v1.InlineOutputParserR\x12inlineOutputParser\x1a:\n" +
 	"\fHeadersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"M\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"|\n" +
+	"\x12InlineOutputParser\x12\x1d\n" +
+	"\n" +
+	"think_tags\x18\x01 \x01(\bR\tthinkTags\x12!\n" +
+	"\fthink_opened\x18\x02 \x01(\bR\vthinkOpened\x12$\n" +
+	"\x0etool_call_tags\x18\x03 \x01(\bR\ftoolCallTags\"M\n" +
 	"\fGoogleConfig\x12\x17\n" +
 	"\aapi_key\x18\x01 \x01(\tR\x06apiKey\x12$\n" +
 	"\x0esystem_as_user\x18\x02 \x01(\bR\fsystemAsUser\"\x93\x02\n" +
@@ -1088,6 +2218,15 @@ const file_conf_upstream_proto_rawDesc = "" +
 	"\x0esystem_as_user\x18\x05 \x01(\bR\fsystemAsUser\x1a:\n" +
 	"\fHeadersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe7\x01\n" +
+	"\fOllamaConfig\x12\x19\n" +
+	"\bbase_url\x18\x01 \x01(\tR\abaseUrl\x12H\n" +
+	"\aheaders\x18\x02 \x03(\v2..neurouter.config.v1.OllamaConfig.HeadersEntryR\aheaders\x12\x1d\n" +
+	"\n" +
+	"keep_alive\x18\x03 \x01(\tR\tkeepAlive\x12\x17\n" +
+	"\anum_ctx\x18\x04 \x01(\rR\x06numCtx\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xba\x01\n" +
 	"\vAliasConfig\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
@@ -1101,14 +2240,15 @@ const file_conf_upstream_proto_rawDesc = "" +
 	"\rMODALITY_TEXT\x10\x01\x12\x12\n" +
 	"\x0eMODALITY_IMAGE\x10\x02\x12\x12\n" +
 	"\x0eMODALITY_AUDIO\x10\x03\x12\x12\n" +
-	"\x0eMODALITY_VIDEO\x10\x04*\x8b\x01\n" +
+	"\x0eMODALITY_VIDEO\x10\x04*\xad\x01\n" +
 	"\n" +
 	"Capability\x12\x1a\n" +
 	"\x16CAPABILITY_UNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fCAPABILITY_CHAT\x10\x01\x12\x19\n" +
 	"\x15CAPABILITY_COMPLETION\x10\x02\x12\x18\n" +
 	"\x14CAPABILITY_EMBEDDING\x10\x03\x12\x17\n" +
-	"\x13CAPABILITY_TOOL_USE\x10\x04B2Z0github.com/neuraxes/neurouter/internal/conf;confb\x06proto3"
+	"\x13CAPABILITY_TOOL_USE\x10\x04\x12 \n" +
+	"\x1cCAPABILITY_STRUCTURED_OUTPUT\x10\x05B2Z0github.com/neuraxes/neurouter/internal/conf;confb\x06proto3"
 
 var (
 	file_conf_upstream_proto_rawDescOnce sync.Once
@@ -1122,45 +2262,88 @@ func file_conf_upstream_proto_rawDescGZIP() []byte {
 	return file_conf_upstream_proto_rawDescData
 }
 
-var file_conf_upstream_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_conf_upstream_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_conf_upstream_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_conf_upstream_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_conf_upstream_proto_goTypes = []any{
 	(Modality)(0),                    // 0: neurouter.config.v1.Modality
 	(Capability)(0),                  // 1: neurouter.config.v1.Capability
-	(*Upstream)(nil),                 // 2: neurouter.config.v1.Upstream
-	(*UpstreamScheduling)(nil),       // 3: neurouter.config.v1.UpstreamScheduling
-	(*UpstreamConfig)(nil),           // 4: neurouter.config.v1.UpstreamConfig
-	(*ModelScheduling)(nil),          // 5: neurouter.config.v1.ModelScheduling
-	(*Model)(nil),                    // 6: neurouter.config.v1.Model
-	(*NeurouterConfig)(nil),          // 7: neurouter.config.v1.NeurouterConfig
-	(*OpenAIConfig)(nil),             // 8: neurouter.config.v1.OpenAIConfig
-	(*GoogleConfig)(nil),             // 9: neurouter.config.v1.GoogleConfig
-	(*AnthropicConfig)(nil),          // 10: neurouter.config.v1.AnthropicConfig
-	(*AliasConfig)(nil),              // 11: neurouter.config.v1.AliasConfig
-	nil,                              // 12: neurouter.config.v1.OpenAIConfig.HeadersEntry
-	nil,                              // 13: neurouter.config.v1.AnthropicConfig.HeadersEntry
-	(*AliasConfig_ActualConfig)(nil), // 14: neurouter.config.v1.AliasConfig.ActualConfig
+	(RateWindow_Type)(0),             // 2: neurouter.config.v1.RateWindow.Type
+	(StructuredOutput_Emulation)(0),  // 3: neurouter.config.v1.StructuredOutput.Emulation
+	(ToolUse_Emulation)(0),           // 4: neurouter.config.v1.ToolUse.Emulation
+	(*Upstream)(nil),                 // 5: neurouter.config.v1.Upstream
+	(*UpstreamScheduling)(nil),       // 6: neurouter.config.v1.UpstreamScheduling
+	(*TokenWeights)(nil),             // 7: neurouter.config.v1.TokenWeights
+	(*RateWindow)(nil),               // 8: neurouter.config.v1.RateWindow
+	(*UpstreamConfig)(nil),           // 9: neurouter.config.v1.UpstreamConfig
+	(*Credential)(nil),               // 10: neurouter.config.v1.Credential
+	(*Discovery)(nil),                // 11: neurouter.config.v1.Discovery
+	(*HTTPTransport)(nil),            // 12: neurouter.config.v1.HTTPTransport
+	(*ModelScheduling)(nil),          // 13: neurouter.config.v1.ModelScheduling
+	(*Model)(nil),                    // 14: neurouter.config.v1.Model
+	(*Timeouts)(nil),                 // 15: neurouter.config.v1.Timeouts
+	(*StructuredOutput)(nil),         // 16: neurouter.config.v1.StructuredOutput
+	(*ToolUse)(nil),                  // 17: neurouter.config.v1.ToolUse
+	(*NeurouterConfig)(nil),          // 18: neurouter.config.v1.NeurouterConfig
+	(*OpenAIConfig)(nil),             // 19: neurouter.config.v1.OpenAIConfig
+	(*InlineOutputParser)(nil),       // 20: neurouter.config.v1.InlineOutputParser
+	(*GoogleConfig)(nil),             // 21: neurouter.config.v1.GoogleConfig
+	(*AnthropicConfig)(nil),          // 22: neurouter.config.v1.AnthropicConfig
+	(*OllamaConfig)(nil),             // 23: neurouter.config.v1.OllamaConfig
+	(*AliasConfig)(nil),              // 24: neurouter.config.v1.AliasConfig
+	nil,                              // 25: neurouter.config.v1.OpenAIConfig.HeadersEntry
+	nil,                              // 26: neurouter.config.v1.AnthropicConfig.HeadersEntry
+	nil,                              // 27: neurouter.config.v1.OllamaConfig.HeadersEntry
+	(*AliasConfig_ActualConfig)(nil), // 28: neurouter.config.v1.AliasConfig.ActualConfig
+	(*durationpb.Duration)(nil),      // 29: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),    // 30: google.protobuf.Timestamp
 }
 var file_conf_upstream_proto_depIdxs = []int32{
-	4,  // 0: neurouter.config.v1.Upstream.configs:type_name -> neurouter.config.v1.UpstreamConfig
-	11, // 1: neurouter.config.v1.Upstream.aliases:type_name -> neurouter.config.v1.AliasConfig
-	6,  // 2: neurouter.config.v1.UpstreamConfig.models:type_name -> neurouter.config.v1.Model
-	3,  // 3: neurouter.config.v1.UpstreamConfig.scheduling:type_name -> neurouter.config.v1.UpstreamScheduling
-	7,  // 4: neurouter.config.v1.UpstreamConfig.neurouter:type_name -> neurouter.config.v1.NeurouterConfig
-	8,  // 5: neurouter.config.v1.UpstreamConfig.open_ai:type_name -> neurouter.config.v1.OpenAIConfig
-	9,  // 6: neurouter.config.v1.UpstreamConfig.google:type_name -> neurouter.config.v1.GoogleConfig
-	10, // 7: neurouter.config.v1.UpstreamConfig.anthropic:type_name -> neurouter.config.v1.AnthropicConfig
-	0,  // 8: neurouter.config.v1.Model.modalities:type_name -> neurouter.config.v1.Modality
-	1,  // 9: neurouter.config.v1.Model.capabilities:type_name -> neurouter.config.v1.Capability
-	5,  // 10: neurouter.config.v1.Model.scheduling:type_name -> neurouter.config.v1.ModelScheduling
-	12, // 11: neurouter.config.v1.OpenAIConfig.headers:type_name -> neurouter.config.v1.OpenAIConfig.HeadersEntry
-	13, // 12: neurouter.config.v1.AnthropicConfig.headers:type_name -> neurouter.config.v1.AnthropicConfig.HeadersEntry
-	14, // 13: neurouter.config.v1.AliasConfig.actual:type_name -> neurouter.config.v1.AliasConfig.ActualConfig
-	14, // [14:14] is the sub-list for method output_type
-	14, // [14:14] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	9,  // 0: neurouter.config.v1.Upstream.configs:type_name -> neurouter.config.v1.UpstreamConfig
+	24, // 1: neurouter.config.v1.Upstream.aliases:type_name -> neurouter.config.v1.AliasConfig
+	8,  // 2: neurouter.config.v1.UpstreamScheduling.windows:type_name -> neurouter.config.v1.RateWindow
+	7,  // 3: neurouter.config.v1.UpstreamScheduling.token_weights:type_name -> neurouter.config.v1.TokenWeights
+	2,  // 4: neurouter.config.v1.RateWindow.type:type_name -> neurouter.config.v1.RateWindow.Type
+	29, // 5: neurouter.config.v1.RateWindow.duration:type_name -> google.protobuf.Duration
+	30, // 6: neurouter.config.v1.RateWindow.anchor:type_name -> google.protobuf.Timestamp
+	14, // 7: neurouter.config.v1.UpstreamConfig.models:type_name -> neurouter.config.v1.Model
+	6,  // 8: neurouter.config.v1.UpstreamConfig.scheduling:type_name -> neurouter.config.v1.UpstreamScheduling
+	10, // 9: neurouter.config.v1.UpstreamConfig.credentials:type_name -> neurouter.config.v1.Credential
+	12, // 10: neurouter.config.v1.UpstreamConfig.transport:type_name -> neurouter.config.v1.HTTPTransport
+	11, // 11: neurouter.config.v1.UpstreamConfig.discovery:type_name -> neurouter.config.v1.Discovery
+	18, // 12: neurouter.config.v1.UpstreamConfig.neurouter:type_name -> neurouter.config.v1.NeurouterConfig
+	19, // 13: neurouter.config.v1.UpstreamConfig.open_ai:type_name -> neurouter.config.v1.OpenAIConfig
+	21, // 14: neurouter.config.v1.UpstreamConfig.google:type_name -> neurouter.config.v1.GoogleConfig
+	22, // 15: neurouter.config.v1.UpstreamConfig.anthropic:type_name -> neurouter.config.v1.AnthropicConfig
+	23, // 16: neurouter.config.v1.UpstreamConfig.ollama:type_name -> neurouter.config.v1.OllamaConfig
+	6,  // 17: neurouter.config.v1.Credential.scheduling:type_name -> neurouter.config.v1.UpstreamScheduling
+	29, // 18: neurouter.config.v1.Discovery.interval:type_name -> google.protobuf.Duration
+	1,  // 19: neurouter.config.v1.Discovery.capabilities:type_name -> neurouter.config.v1.Capability
+	29, // 20: neurouter.config.v1.HTTPTransport.connect_timeout:type_name -> google.protobuf.Duration
+	29, // 21: neurouter.config.v1.HTTPTransport.tls_handshake_timeout:type_name -> google.protobuf.Duration
+	29, // 22: neurouter.config.v1.HTTPTransport.response_header_timeout:type_name -> google.protobuf.Duration
+	29, // 23: neurouter.config.v1.HTTPTransport.idle_conn_timeout:type_name -> google.protobuf.Duration
+	8,  // 24: neurouter.config.v1.ModelScheduling.windows:type_name -> neurouter.config.v1.RateWindow
+	0,  // 25: neurouter.config.v1.Model.modalities:type_name -> neurouter.config.v1.Modality
+	1,  // 26: neurouter.config.v1.Model.capabilities:type_name -> neurouter.config.v1.Capability
+	13, // 27: neurouter.config.v1.Model.scheduling:type_name -> neurouter.config.v1.ModelScheduling
+	16, // 28: neurouter.config.v1.Model.structured_output:type_name -> neurouter.config.v1.StructuredOutput
+	15, // 29: neurouter.config.v1.Model.timeouts:type_name -> neurouter.config.v1.Timeouts
+	17, // 30: neurouter.config.v1.Model.tool_use:type_name -> neurouter.config.v1.ToolUse
+	29, // 31: neurouter.config.v1.Timeouts.first_event:type_name -> google.protobuf.Duration
+	29, // 32: neurouter.config.v1.Timeouts.idle:type_name -> google.protobuf.Duration
+	29, // 33: neurouter.config.v1.Timeouts.total:type_name -> google.protobuf.Duration
+	3,  // 34: neurouter.config.v1.StructuredOutput.emulation:type_name -> neurouter.config.v1.StructuredOutput.Emulation
+	4,  // 35: neurouter.config.v1.ToolUse.emulation:type_name -> neurouter.config.v1.ToolUse.Emulation
+	25, // 36: neurouter.config.v1.OpenAIConfig.headers:type_name -> neurouter.config.v1.OpenAIConfig.HeadersEntry
+	20, // 37: neurouter.config.v1.OpenAIConfig.inline_output_parser:type_name -> neurouter.config.v1.InlineOutputParser
+	26, // 38: neurouter.config.v1.AnthropicConfig.headers:type_name -> neurouter.config.v1.AnthropicConfig.HeadersEntry
+	27, // 39: neurouter.config.v1.OllamaConfig.headers:type_name -> neurouter.config.v1.OllamaConfig.HeadersEntry
+	28, // 40: neurouter.config.v1.AliasConfig.actual:type_name -> neurouter.config.v1.AliasConfig.ActualConfig
+	41, // [41:41] is the sub-list for method output_type
+	41, // [41:41] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_conf_upstream_proto_init() }
@@ -1168,19 +2351,21 @@ func file_conf_upstream_proto_init() {
 	if File_conf_upstream_proto != nil {
 		return
 	}
-	file_conf_upstream_proto_msgTypes[2].OneofWrappers = []any{
+	file_conf_upstream_proto_msgTypes[2].OneofWrappers = []any{}
+	file_conf_upstream_proto_msgTypes[4].OneofWrappers = []any{
 		(*UpstreamConfig_Neurouter)(nil),
 		(*UpstreamConfig_OpenAi)(nil),
 		(*UpstreamConfig_Google)(nil),
 		(*UpstreamConfig_Anthropic)(nil),
+		(*UpstreamConfig_Ollama)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conf_upstream_proto_rawDesc), len(file_conf_upstream_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   13,
+			NumEnums:      5,
+			NumMessages:   24,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
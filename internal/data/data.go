@@ -18,13 +18,14 @@ import (
 	"log/slog"
 
 	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/data/responsestore"
 	"github.com/neuraxes/neurouter/internal/data/telemetry"
 	"github.com/neuraxes/neurouter/internal/data/upstream"
 
 	"github.com/google/wire"
 )
 
-var ProviderSet = wire.NewSet(NewData, upstream.ProviderSet, telemetry.ProviderSet)
+var ProviderSet = wire.NewSet(NewData, upstream.ProviderSet, telemetry.ProviderSet, responsestore.ProviderSet)
 
 type Data struct {
 }
@@ -0,0 +1,475 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// nextCalendarReset returns the first boundary after now of the windows of the
+// given period that start at anchor. A zero anchor aligns windows to the Unix epoch.
+func nextCalendarReset(now, anchor time.Time, period time.Duration) time.Time {
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0)
+	}
+	elapsed := now.Sub(anchor)
+	n := elapsed / period
+	if elapsed < 0 && elapsed%period != 0 {
+		n-- // round towards the past for times before the anchor
+	}
+	return anchor.Add((n + 1) * period)
+}
+
+// averageMonth is the nominal length of a calendar month.
+const averageMonth = 730 * time.Hour
+
+// nextCalendarMonthReset returns the first boundary after now of the windows
+// of the given number of calendar months that start at anchor, so that a
+// window anchored on the 1st resets on the 1st of every month. A zero anchor
+// aligns windows to the Unix epoch. Anchors past the 28th roll over into the
+// next month when a month is shorter.
+func nextCalendarMonthReset(now, anchor time.Time, months int) time.Time {
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0).UTC()
+	}
+	elapsed := (now.Year()-anchor.Year())*12 + int(now.Month()) - int(anchor.Month())
+	n := elapsed / months
+	if elapsed < 0 && elapsed%months != 0 {
+		n-- // round towards the past for times before the anchor
+	}
+	// Start a window early, as the boundary within the current month may not
+	// have passed yet
+	for n--; ; n++ {
+		if t := anchor.AddDate(0, n*months, 0); t.After(now) {
+			return t
+		}
+	}
+}
+
+// calendarQuotaState holds shared state for calendar window limiters.
+type calendarQuotaState struct {
+	mu sync.Mutex
+
+	limit     int64            // Maximum units per window
+	used      int64            // Units used in current window
+	window    time.Duration    // Nominal length of a window
+	resetTime time.Time        // Next reset time
+	nextReset func() time.Time // Computes the reset time of the current window
+	waiters   atomic.Int64     // Reservations blocked in Wait
+}
+
+func newCalendarQuotaState(limit int64, window time.Duration, nextReset func() time.Time) *calendarQuotaState {
+	return &calendarQuotaState{
+		limit:     limit,
+		window:    window,
+		resetTime: nextReset(),
+		nextReset: nextReset,
+	}
+}
+
+// flush resets the quota if we've passed the end of the current window.
+// Must be called with lock held.
+func (s *calendarQuotaState) flush() {
+	if time.Now().After(s.resetTime) {
+		s.used = 0
+		s.resetTime = s.nextReset()
+	}
+}
+
+// inspect returns a snapshot of the quota.
+func (s *calendarQuotaState) inspect(unit v1.LimiterUnit) repository.LimiterState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flush()
+
+	return repository.LimiterState{
+		Type:      v1.LimiterType_LIMITER_TYPE_CALENDAR_WINDOW,
+		Unit:      unit,
+		Limit:     s.limit,
+		Remaining: max(0, s.limit-s.used),
+		Waiters:   s.waiters.Load(),
+		Window:    s.window,
+		NextReset: s.resetTime,
+	}
+}
+
+// setRemaining overwrites the usage of the current window.
+func (s *calendarQuotaState) setRemaining(remaining int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flush()
+	s.used = s.limit - remaining
+}
+
+// CalendarRequestLimiter limits the requests admitted within fixed windows.
+type CalendarRequestLimiter struct {
+	state *calendarQuotaState
+}
+
+// NewCalendarRequestLimiter creates a request limiter whose windows reset at
+// anchor + n * period. If limit or period is 0 or negative, returns nil (unlimited).
+func NewCalendarRequestLimiter(limit int64, period time.Duration, anchor time.Time) repository.RequestLimiter {
+	if limit <= 0 || period <= 0 {
+		return nil
+	}
+
+	return &CalendarRequestLimiter{
+		state: newCalendarQuotaState(limit, period, func() time.Time {
+			return nextCalendarReset(time.Now(), anchor, period)
+		}),
+	}
+}
+
+// NewCalendarMonthRequestLimiter creates a request limiter whose windows span
+// the given number of calendar months, starting at anchor. If limit or months
+// is 0 or negative, returns nil (unlimited).
+func NewCalendarMonthRequestLimiter(limit int64, months int, anchor time.Time) repository.RequestLimiter {
+	if limit <= 0 || months <= 0 {
+		return nil
+	}
+
+	return &CalendarRequestLimiter{
+		state: newCalendarQuotaState(limit, time.Duration(months)*averageMonth, func() time.Time {
+			return nextCalendarMonthReset(time.Now(), anchor, months)
+		}),
+	}
+}
+
+// Probe detects the waiting duration to reserve 1 request without blocking.
+func (d *CalendarRequestLimiter) Probe() time.Duration {
+	d.state.mu.Lock()
+	defer d.state.mu.Unlock()
+
+	d.state.flush()
+
+	if d.state.used < d.state.limit {
+		return 0
+	}
+
+	// Return wait time until next reset
+	return time.Until(d.state.resetTime)
+}
+
+// Inspect returns a snapshot of the limiter state.
+func (d *CalendarRequestLimiter) Inspect() repository.LimiterState {
+	return d.state.inspect(v1.LimiterUnit_LIMITER_UNIT_REQUEST)
+}
+
+// SetRemaining makes the limiter admit the given requests in the current window.
+func (d *CalendarRequestLimiter) SetRemaining(remaining int64) {
+	d.state.setRemaining(remaining)
+}
+
+// Reserve tries to acquire quota for 1 request without blocking.
+func (d *CalendarRequestLimiter) Reserve() (repository.Reservation, error) {
+	d.state.mu.Lock()
+	defer d.state.mu.Unlock()
+
+	d.state.flush()
+
+	available := d.state.used < d.state.limit
+	if available {
+		d.state.used++
+	}
+
+	return &calendarRequestReservation{
+		state:    d.state,
+		reserved: 1,
+		acquired: available,
+	}, nil
+}
+
+// calendarRequestReservation implements repository.Reservation for calendar request limits.
+type calendarRequestReservation struct {
+	state    *calendarQuotaState
+	reserved int64
+	acquired bool
+	released bool
+}
+
+// Delay returns the time to wait before the reservation can be used.
+func (r *calendarRequestReservation) Delay() time.Duration {
+	if r.acquired {
+		return 0
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	r.state.flush()
+
+	if r.state.used < r.state.limit {
+		return 0
+	}
+
+	return time.Until(r.state.resetTime)
+}
+
+// Wait blocks until the resource is ready or the context is done.
+func (r *calendarRequestReservation) Wait(ctx context.Context) error {
+	if r.acquired || r.released {
+		return nil
+	}
+
+	r.state.waiters.Add(1)
+	defer r.state.waiters.Add(-1)
+
+	// Wait until quota becomes available
+	for {
+		r.state.mu.Lock()
+		r.state.flush()
+
+		if r.state.used < r.state.limit {
+			r.state.used++
+			r.acquired = true
+			r.state.mu.Unlock()
+			return nil
+		}
+
+		// Wait until next reset
+		dur := time.Until(r.state.resetTime)
+		r.state.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+			// Retry after reset
+			continue
+		}
+	}
+}
+
+// Cancel returns the reserved quota without consuming it.
+func (r *calendarRequestReservation) Cancel() {
+	if r.released {
+		return
+	}
+
+	if r.acquired {
+		r.state.mu.Lock()
+		r.state.used = max(0, r.state.used-r.reserved)
+		r.state.mu.Unlock()
+	}
+
+	r.released = true
+}
+
+// Complete the reservation after actual usage.
+func (r *calendarRequestReservation) Complete() {
+	r.released = true
+}
+
+// CalendarTokenLimiter limits the tokens admitted within fixed windows.
+type CalendarTokenLimiter struct {
+	state *calendarQuotaState
+}
+
+// NewCalendarTokenLimiter creates a token limiter whose windows reset at
+// anchor + n * period. If limit or period is 0 or negative, returns nil (unlimited).
+func NewCalendarTokenLimiter(limit int64, period time.Duration, anchor time.Time) repository.TokenLimiter {
+	if limit <= 0 || period <= 0 {
+		return nil
+	}
+
+	return &CalendarTokenLimiter{
+		state: newCalendarQuotaState(limit, period, func() time.Time {
+			return nextCalendarReset(time.Now(), anchor, period)
+		}),
+	}
+}
+
+// NewCalendarMonthTokenLimiter creates a token limiter whose windows span the
+// given number of calendar months, starting at anchor. If limit or months is 0
+// or negative, returns nil (unlimited).
+func NewCalendarMonthTokenLimiter(limit int64, months int, anchor time.Time) repository.TokenLimiter {
+	if limit <= 0 || months <= 0 {
+		return nil
+	}
+
+	return &CalendarTokenLimiter{
+		state: newCalendarQuotaState(limit, time.Duration(months)*averageMonth, func() time.Time {
+			return nextCalendarMonthReset(time.Now(), anchor, months)
+		}),
+	}
+}
+
+// Probe detects the waiting duration to reserve tokens without blocking.
+func (d *CalendarTokenLimiter) Probe(tokens int64) time.Duration {
+	// Check if request exceeds limit
+	if tokens > d.state.limit {
+		return repository.InfDuration
+	}
+
+	d.state.mu.Lock()
+	defer d.state.mu.Unlock()
+
+	d.state.flush()
+
+	if d.state.used+tokens <= d.state.limit {
+		return 0
+	}
+
+	// Return wait time until next reset
+	return time.Until(d.state.resetTime)
+}
+
+// Inspect returns a snapshot of the limiter state.
+func (d *CalendarTokenLimiter) Inspect() repository.LimiterState {
+	return d.state.inspect(v1.LimiterUnit_LIMITER_UNIT_TOKEN)
+}
+
+// SetRemaining makes the limiter admit the given tokens in the current window.
+func (d *CalendarTokenLimiter) SetRemaining(remaining int64) {
+	d.state.setRemaining(remaining)
+}
+
+// Reserve tries to acquire quota for given tokens without blocking.
+func (d *CalendarTokenLimiter) Reserve(tokens int64) (repository.TokenReservation, error) {
+	// Check if request exceeds limit
+	if tokens > d.state.limit {
+		return nil, entity.ErrTokenQuotaExhausted
+	}
+
+	d.state.mu.Lock()
+	defer d.state.mu.Unlock()
+
+	d.state.flush()
+
+	available := d.state.used+tokens <= d.state.limit
+	if available {
+		d.state.used += tokens
+	}
+
+	return &calendarTokenReservation{
+		state:    d.state,
+		reserved: tokens,
+		acquired: available,
+	}, nil
+}
+
+// calendarTokenReservation implements repository.TokenReservation for calendar token limits.
+type calendarTokenReservation struct {
+	state    *calendarQuotaState
+	reserved int64
+	acquired bool
+	released bool
+}
+
+// Delay returns the time to wait before the reservation can be used.
+func (r *calendarTokenReservation) Delay() time.Duration {
+	if r.acquired {
+		return 0
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	r.state.flush()
+
+	if r.state.used+r.reserved <= r.state.limit {
+		return 0
+	}
+
+	return time.Until(r.state.resetTime)
+}
+
+// Wait blocks until the resource is ready or the context is done.
+func (r *calendarTokenReservation) Wait(ctx context.Context) error {
+	if r.acquired || r.released {
+		return nil
+	}
+
+	r.state.waiters.Add(1)
+	defer r.state.waiters.Add(-1)
+
+	// Wait until quota becomes available
+	for {
+		r.state.mu.Lock()
+		r.state.flush()
+
+		if r.state.used+r.reserved <= r.state.limit {
+			r.state.used += r.reserved
+			r.acquired = true
+			r.state.mu.Unlock()
+			return nil
+		}
+
+		// Wait until next reset
+		dur := time.Until(r.state.resetTime)
+		r.state.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dur):
+			// Retry after reset
+			continue
+		}
+	}
+}
+
+// Cancel returns the reserved quota without consuming it.
+func (r *calendarTokenReservation) Cancel() {
+	if r.released {
+		return
+	}
+
+	if r.acquired {
+		r.state.mu.Lock()
+		r.state.used = max(0, r.state.used-r.reserved)
+		r.state.mu.Unlock()
+	}
+
+	r.released = true
+}
+
+// Complete the reservation after actual usage.
+func (r *calendarTokenReservation) Complete() {
+	r.released = true
+}
+
+// CompleteWithActual completes the reservation after actual usage with token adjustment.
+func (r *calendarTokenReservation) CompleteWithActual(actualTokens int64) {
+	if r.released {
+		return
+	}
+
+	if r.acquired {
+		r.state.mu.Lock()
+		r.state.used = max(0, r.state.used-r.reserved+actualTokens)
+		r.state.mu.Unlock()
+	}
+
+	r.released = true
+}
+
+var _ repository.RequestLimiter = (*CalendarRequestLimiter)(nil)
+var _ repository.Reservation = (*calendarRequestReservation)(nil)
+var _ repository.TokenLimiter = (*CalendarTokenLimiter)(nil)
+var _ repository.TokenReservation = (*calendarTokenReservation)(nil)
+var _ repository.InspectableLimiter = (*CalendarRequestLimiter)(nil)
+var _ repository.AdjustableLimiter = (*CalendarRequestLimiter)(nil)
+var _ repository.InspectableLimiter = (*CalendarTokenLimiter)(nil)
+var _ repository.AdjustableLimiter = (*CalendarTokenLimiter)(nil)
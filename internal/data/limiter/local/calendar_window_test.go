@@ -0,0 +1,152 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+func TestNextCalendarReset(t *testing.T) {
+	Convey("nextCalendarReset", t, func() {
+		anchor := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+		Convey("returns the end of the current window", func() {
+			now := anchor.Add(7 * time.Hour)
+			So(nextCalendarReset(now, anchor, 5*time.Hour), ShouldEqual, anchor.Add(10*time.Hour))
+		})
+
+		Convey("moves to the next window on a boundary", func() {
+			So(nextCalendarReset(anchor, anchor, time.Hour), ShouldEqual, anchor.Add(time.Hour))
+		})
+
+		Convey("handles times before the anchor", func() {
+			now := anchor.Add(-90 * time.Minute)
+			So(nextCalendarReset(now, anchor, time.Hour), ShouldEqual, anchor.Add(-time.Hour))
+		})
+
+		Convey("epoch anchored days reset at UTC midnight", func() {
+			now := time.Date(2024, 5, 6, 13, 0, 0, 0, time.UTC)
+			So(nextCalendarReset(now, time.Unix(0, 0), 24*time.Hour), ShouldEqual, time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC))
+		})
+	})
+
+	Convey("nextCalendarMonthReset", t, func() {
+		anchor := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+		Convey("resets on the anchor day of the next month", func() {
+			now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+			So(nextCalendarMonthReset(now, anchor, 1), ShouldEqual, time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("resets within the month before the anchor day", func() {
+			now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+			So(nextCalendarMonthReset(now, anchor, 1), ShouldEqual, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("spans several months", func() {
+			now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+			So(nextCalendarMonthReset(now, anchor, 3), ShouldEqual, time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC))
+			So(nextCalendarMonthReset(anchor, anchor, 3), ShouldEqual, time.Date(2024, 4, 15, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("handles times before the anchor", func() {
+			now := time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC)
+			So(nextCalendarMonthReset(now, anchor, 1), ShouldEqual, time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC))
+		})
+
+		Convey("epoch anchored months reset on the 1st", func() {
+			now := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+			So(nextCalendarMonthReset(now, time.Time{}, 1), ShouldEqual, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+		})
+	})
+}
+
+func TestNewCalendarLimiters(t *testing.T) {
+	Convey("Calendar limiter constructors", t, func() {
+		Convey("zero limit or period returns nil", func() {
+			So(NewCalendarRequestLimiter(0, time.Hour, time.Time{}), ShouldBeNil)
+			So(NewCalendarRequestLimiter(10, 0, time.Time{}), ShouldBeNil)
+			So(NewCalendarTokenLimiter(0, time.Hour, time.Time{}), ShouldBeNil)
+			So(NewCalendarTokenLimiter(10, -time.Hour, time.Time{}), ShouldBeNil)
+		})
+
+		Convey("positive limit returns limiter", func() {
+			So(NewCalendarRequestLimiter(10, time.Hour, time.Time{}), ShouldImplement, (*repository.RequestLimiter)(nil))
+			So(NewCalendarTokenLimiter(10, time.Hour, time.Time{}), ShouldImplement, (*repository.TokenLimiter)(nil))
+		})
+	})
+}
+
+func TestCalendarTokenLimiter(t *testing.T) {
+	Convey("CalendarTokenLimiter", t, func() {
+		anchor := time.Now().Add(-30 * time.Millisecond)
+		l := NewCalendarTokenLimiter(100, 50*time.Millisecond, anchor).(*CalendarTokenLimiter)
+
+		r, err := l.Reserve(80)
+		So(err, ShouldBeNil)
+		So(r.Delay(), ShouldEqual, 0)
+		r.CompleteWithActual(80)
+
+		Convey("waits until the window resets", func() {
+			d := l.Probe(50)
+			So(d, ShouldBeGreaterThan, 0)
+			So(d, ShouldBeLessThanOrEqualTo, 20*time.Millisecond)
+
+			r, err := l.Reserve(50)
+			So(err, ShouldBeNil)
+			So(r.Wait(context.Background()), ShouldBeNil)
+			So(l.state.used, ShouldEqual, int64(50))
+		})
+	})
+}
+
+func TestCalendarLimiter_InspectAndSetRemaining(t *testing.T) {
+	Convey("Calendar limiters inspection", t, func() {
+		l := NewCalendarRequestLimiter(10, time.Hour, time.Time{}).(*CalendarRequestLimiter)
+		r, err := l.Reserve()
+		So(err, ShouldBeNil)
+		r.Complete()
+
+		s := l.Inspect()
+		So(s.Type, ShouldEqual, v1.LimiterType_LIMITER_TYPE_CALENDAR_WINDOW)
+		So(s.Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_REQUEST)
+		So(s.Limit, ShouldEqual, 10)
+		So(s.Remaining, ShouldEqual, 9)
+		So(s.Window, ShouldEqual, time.Hour)
+		So(s.NextReset, ShouldEqual, l.state.resetTime)
+
+		Convey("SetRemaining overwrites the usage", func() {
+			l.SetRemaining(0)
+			So(l.Probe(), ShouldBeGreaterThan, 0)
+			l.SetRemaining(10)
+			So(l.Inspect().Remaining, ShouldEqual, 10)
+		})
+
+		Convey("token limiter reports tokens", func() {
+			tl := NewCalendarTokenLimiter(100, time.Hour, time.Time{}).(*CalendarTokenLimiter)
+			tl.SetRemaining(40)
+			s := tl.Inspect()
+			So(s.Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_TOKEN)
+			So(s.Remaining, ShouldEqual, 40)
+		})
+	})
+}
@@ -21,6 +21,7 @@ import (
 
 	"golang.org/x/sync/semaphore"
 
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
 )
 
@@ -39,6 +40,9 @@ const (
 // wait times based on observed delays using EWMA (Exponentially Weighted Moving Average).
 type ConcurrencyLimiter struct {
 	sem            *semaphore.Weighted
+	limit          int64
+	inFlight       atomic.Int64 // slots held by reservations
+	waiters        atomic.Int64 // reservations blocked in Wait
 	estimatedDelay atomic.Int64 // nanoseconds, EWMA of observed wait times
 }
 
@@ -49,7 +53,8 @@ func NewConcurrencyLimiter(limit int64) repository.RequestLimiter {
 		return nil
 	}
 	l := &ConcurrencyLimiter{
-		sem: semaphore.NewWeighted(limit),
+		sem:   semaphore.NewWeighted(limit),
+		limit: limit,
 	}
 	l.estimatedDelay.Store(int64(defaultConcurrencyDelay))
 	return l
@@ -69,12 +74,29 @@ func (c *ConcurrencyLimiter) Probe() time.Duration {
 // Reserve tries to acquire quota for 1 request without blocking.
 // Returns a reservation that may require waiting.
 func (c *ConcurrencyLimiter) Reserve() (repository.Reservation, error) {
+	acquired := c.sem.TryAcquire(1)
+	if acquired {
+		c.inFlight.Add(1)
+	}
 	return &concurrencyReservation{
 		limiter:  c,
-		acquired: c.sem.TryAcquire(1),
+		acquired: acquired,
 	}, nil
 }
 
+// Inspect returns a snapshot of the limiter state.
+func (c *ConcurrencyLimiter) Inspect() repository.LimiterState {
+	inFlight := c.inFlight.Load()
+	return repository.LimiterState{
+		Type:      v1.LimiterType_LIMITER_TYPE_CONCURRENCY,
+		Unit:      v1.LimiterUnit_LIMITER_UNIT_REQUEST,
+		Limit:     c.limit,
+		Remaining: max(0, c.limit-inFlight),
+		InFlight:  inFlight,
+		Waiters:   c.waiters.Load(),
+	}
+}
+
 // recordWaitTime updates the estimated delay using EWMA based on actual observed wait time.
 func (c *ConcurrencyLimiter) recordWaitTime(d time.Duration) {
 	o := c.estimatedDelay.Load()
@@ -108,9 +130,13 @@ func (r *concurrencyReservation) Wait(ctx context.Context) error {
 	start := time.Now()
 
 	// Acquire the semaphore (blocking)
-	if err := r.limiter.sem.Acquire(ctx, 1); err != nil {
+	r.limiter.waiters.Add(1)
+	err := r.limiter.sem.Acquire(ctx, 1)
+	r.limiter.waiters.Add(-1)
+	if err != nil {
 		return err
 	}
+	r.limiter.inFlight.Add(1)
 
 	// Record actual wait time for future estimates
 	r.limiter.recordWaitTime(time.Since(start))
@@ -122,6 +148,7 @@ func (r *concurrencyReservation) Wait(ctx context.Context) error {
 // Cancel returns the reserved quota without consuming it.
 func (r *concurrencyReservation) Cancel() {
 	if r.acquired && !r.released {
+		r.limiter.inFlight.Add(-1)
 		r.limiter.sem.Release(1)
 		r.released = true
 	}
@@ -133,4 +160,5 @@ func (r *concurrencyReservation) Complete() {
 }
 
 var _ repository.RequestLimiter = (*ConcurrencyLimiter)(nil)
+var _ repository.InspectableLimiter = (*ConcurrencyLimiter)(nil)
 var _ repository.Reservation = (*concurrencyReservation)(nil)
@@ -23,6 +23,7 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
 )
 
@@ -550,3 +551,40 @@ func TestConcurrencyLimiter_InterfaceCompliance(t *testing.T) {
 		})
 	})
 }
+
+func TestConcurrencyLimiter_Inspect(t *testing.T) {
+	Convey("ConcurrencyLimiter inspection", t, func() {
+		l := NewConcurrencyLimiter(1).(*ConcurrencyLimiter)
+
+		r1, err := l.Reserve()
+		So(err, ShouldBeNil)
+		s := l.Inspect()
+		So(s.Type, ShouldEqual, v1.LimiterType_LIMITER_TYPE_CONCURRENCY)
+		So(s.Limit, ShouldEqual, 1)
+		So(s.Remaining, ShouldEqual, 0)
+		So(s.InFlight, ShouldEqual, 1)
+
+		r2, err := l.Reserve()
+		So(err, ShouldBeNil)
+		done := make(chan error)
+		go func() { done <- r2.Wait(context.Background()) }()
+		So(func() bool {
+			for range 100 {
+				if l.Inspect().Waiters == 1 {
+					return true
+				}
+				time.Sleep(time.Millisecond)
+			}
+			return false
+		}(), ShouldBeTrue)
+
+		r1.Complete()
+		So(<-done, ShouldBeNil)
+		s = l.Inspect()
+		So(s.InFlight, ShouldEqual, 1)
+		So(s.Waiters, ShouldEqual, 0)
+
+		r2.Complete()
+		So(l.Inspect().Remaining, ShouldEqual, 1)
+	})
+}
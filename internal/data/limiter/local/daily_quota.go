@@ -15,44 +15,23 @@
 package local
 
 import (
-	"context"
-	"sync"
 	"time"
 
-	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
 )
 
+// DailyRequestLimiter implements limiter for RPD.
+type DailyRequestLimiter = CalendarRequestLimiter
+
+// DailyTokenLimiter implements limiter for TPD.
+type DailyTokenLimiter = CalendarTokenLimiter
+
 // getNextMidnight returns the next midnight in the specified timezone.
 func getNextMidnight(loc *time.Location) time.Time {
 	today := time.Now().In(loc)
 	return time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, loc)
 }
 
-// dailyQuotaState holds shared state for daily quota limiters.
-type dailyQuotaState struct {
-	mu sync.Mutex
-
-	limit     int64          // Maximum units per day
-	used      int64          // Units used in current day
-	resetTime time.Time      // Next reset time (midnight in specified timezone)
-	location  *time.Location // Timezone for daily resets
-}
-
-// flush resets the quota if we've passed midnight in the configured timezone.
-// Must be called with lock held.
-func (s *dailyQuotaState) flush() {
-	if time.Now().After(s.resetTime) {
-		s.used = 0
-		s.resetTime = getNextMidnight(s.location)
-	}
-}
-
-// DailyRequestLimiter implements limiter for RPD.
-type DailyRequestLimiter struct {
-	state *dailyQuotaState
-}
-
 // NewDailyRequestLimiter creates a new limiter for RPD.
 // Resets at UTC midnight. If limit is 0 or negative, returns nil (unlimited).
 func NewDailyRequestLimiter(RPDLimit int64) repository.RequestLimiter {
@@ -67,132 +46,12 @@ func NewDailyRequestLimiterWithTimeZone(RPDLimit int64, loc *time.Location) repo
 	}
 
 	return &DailyRequestLimiter{
-		state: &dailyQuotaState{
-			limit:     RPDLimit,
-			used:      0,
-			resetTime: getNextMidnight(loc),
-			location:  loc,
-		},
+		state: newCalendarQuotaState(RPDLimit, 24*time.Hour, func() time.Time {
+			return getNextMidnight(loc)
+		}),
 	}
 }
 
-// Probe detects the waiting duration to reserve 1 request without blocking.
-func (d *DailyRequestLimiter) Probe() time.Duration {
-	d.state.mu.Lock()
-	defer d.state.mu.Unlock()
-
-	d.state.flush()
-
-	if d.state.used < d.state.limit {
-		return 0
-	}
-
-	// Return wait time until next reset
-	return time.Until(d.state.resetTime)
-}
-
-// Reserve tries to acquire quota for 1 request without blocking.
-func (d *DailyRequestLimiter) Reserve() (repository.Reservation, error) {
-	d.state.mu.Lock()
-	defer d.state.mu.Unlock()
-
-	d.state.flush()
-
-	available := d.state.used < d.state.limit
-	if available {
-		d.state.used++
-	}
-
-	return &dailyRequestReservation{
-		state:    d.state,
-		reserved: 1,
-		acquired: available,
-	}, nil
-}
-
-// dailyRequestReservation implements repository.Reservation for daily request limits.
-type dailyRequestReservation struct {
-	state    *dailyQuotaState
-	reserved int64
-	acquired bool
-	released bool
-}
-
-// Delay returns the time to wait before the reservation can be used.
-func (r *dailyRequestReservation) Delay() time.Duration {
-	if r.acquired {
-		return 0
-	}
-
-	r.state.mu.Lock()
-	defer r.state.mu.Unlock()
-
-	r.state.flush()
-
-	if r.state.used < r.state.limit {
-		return 0
-	}
-
-	return time.Until(r.state.resetTime)
-}
-
-// Wait blocks until the resource is ready or the context is done.
-func (r *dailyRequestReservation) Wait(ctx context.Context) error {
-	if r.acquired || r.released {
-		return nil
-	}
-
-	// Wait until quota becomes available
-	for {
-		r.state.mu.Lock()
-		r.state.flush()
-
-		if r.state.used < r.state.limit {
-			r.state.used++
-			r.acquired = true
-			r.state.mu.Unlock()
-			return nil
-		}
-
-		// Wait until next reset
-		dur := time.Until(r.state.resetTime)
-		r.state.mu.Unlock()
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(dur):
-			// Retry after reset
-			continue
-		}
-	}
-}
-
-// Cancel returns the reserved quota without consuming it.
-func (r *dailyRequestReservation) Cancel() {
-	if r.released {
-		return
-	}
-
-	if r.acquired {
-		r.state.mu.Lock()
-		r.state.used = max(0, r.state.used-r.reserved)
-		r.state.mu.Unlock()
-	}
-
-	r.released = true
-}
-
-// Complete the reservation after actual usage.
-func (r *dailyRequestReservation) Complete() {
-	r.released = true
-}
-
-// DailyTokenLimiter implements limiter for TPD.
-type DailyTokenLimiter struct {
-	state *dailyQuotaState
-}
-
 // NewDailyTokenLimiter creates a new limiter for TPD.
 // Resets at UTC midnight. If limit is 0 or negative, returns nil (unlimited).
 func NewDailyTokenLimiter(TPDLimit int64) repository.TokenLimiter {
@@ -207,153 +66,8 @@ func NewDailyTokenLimiterWithTimeZone(TPDLimit int64, loc *time.Location) reposi
 	}
 
 	return &DailyTokenLimiter{
-		state: &dailyQuotaState{
-			limit:     TPDLimit,
-			used:      0,
-			resetTime: getNextMidnight(loc),
-			location:  loc,
-		},
-	}
-}
-
-// Probe detects the waiting duration to reserve tokens without blocking.
-func (d *DailyTokenLimiter) Probe(tokens int64) time.Duration {
-	// Check if request exceeds limit
-	if tokens > d.state.limit {
-		return repository.InfDuration
-	}
-
-	d.state.mu.Lock()
-	defer d.state.mu.Unlock()
-
-	d.state.flush()
-
-	if d.state.used+tokens <= d.state.limit {
-		return 0
-	}
-
-	// Return wait time until next reset
-	return time.Until(d.state.resetTime)
-}
-
-// Reserve tries to acquire quota for given tokens without blocking.
-func (d *DailyTokenLimiter) Reserve(tokens int64) (repository.TokenReservation, error) {
-	// Check if request exceeds limit
-	if tokens > d.state.limit {
-		return nil, entity.ErrTokenQuotaExhausted
-	}
-
-	d.state.mu.Lock()
-	defer d.state.mu.Unlock()
-
-	d.state.flush()
-
-	available := d.state.used+tokens <= d.state.limit
-	if available {
-		d.state.used += tokens
-	}
-
-	return &dailyTokenReservation{
-		state:    d.state,
-		reserved: tokens,
-		acquired: available,
-	}, nil
-}
-
-// dailyTokenReservation implements repository.TokenReservation for daily token limits.
-type dailyTokenReservation struct {
-	state    *dailyQuotaState
-	reserved int64
-	acquired bool
-	released bool
-}
-
-// Delay returns the time to wait before the reservation can be used.
-func (r *dailyTokenReservation) Delay() time.Duration {
-	if r.acquired {
-		return 0
-	}
-
-	r.state.mu.Lock()
-	defer r.state.mu.Unlock()
-
-	r.state.flush()
-
-	if r.state.used+r.reserved <= r.state.limit {
-		return 0
-	}
-
-	return time.Until(r.state.resetTime)
-}
-
-// Wait blocks until the resource is ready or the context is done.
-func (r *dailyTokenReservation) Wait(ctx context.Context) error {
-	if r.acquired || r.released {
-		return nil
-	}
-
-	// Wait until quota becomes available
-	for {
-		r.state.mu.Lock()
-		r.state.flush()
-
-		if r.state.used+r.reserved <= r.state.limit {
-			r.state.used += r.reserved
-			r.acquired = true
-			r.state.mu.Unlock()
-			return nil
-		}
-
-		// Wait until next reset
-		dur := time.Until(r.state.resetTime)
-		r.state.mu.Unlock()
-
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(dur):
-			// Retry after reset
-			continue
-		}
-	}
-}
-
-// Cancel returns the reserved quota without consuming it.
-func (r *dailyTokenReservation) Cancel() {
-	if r.released {
-		return
-	}
-
-	if r.acquired {
-		r.state.mu.Lock()
-		r.state.used = max(0, r.state.used-r.reserved)
-		r.state.mu.Unlock()
+		state: newCalendarQuotaState(TPDLimit, 24*time.Hour, func() time.Time {
+			return getNextMidnight(loc)
+		}),
 	}
-
-	r.released = true
 }
-
-// Complete the reservation after actual usage.
-func (r *dailyTokenReservation) Complete() {
-	r.released = true
-}
-
-// CompleteWithActual completes the reservation after actual usage with token adjustment.
-func (r *dailyTokenReservation) CompleteWithActual(actualTokens int64) {
-	if r.released {
-		return
-	}
-
-	if r.acquired {
-		r.state.mu.Lock()
-		r.state.used = max(0, r.state.used-r.reserved+actualTokens)
-		r.state.mu.Unlock()
-	}
-
-	r.released = true
-}
-
-var _ repository.RequestLimiter = (*DailyRequestLimiter)(nil)
-var _ repository.Reservation = (*dailyRequestReservation)(nil)
-var _ repository.TokenLimiter = (*DailyTokenLimiter)(nil)
-var _ repository.TokenReservation = (*dailyTokenReservation)(nil)
@@ -223,7 +223,7 @@ func TestDailyTokenReservation_WaitAndAdjust(t *testing.T) {
 			So(r.Delay(), ShouldEqual, 0)
 
 			// Simulate actual usage of 60 tokens
-			tr := r.(*dailyTokenReservation)
+			tr := r.(*calendarTokenReservation)
 			// Before complete, used should be 100
 			So(l.state.used, ShouldEqual, int64(100))
 			tr.CompleteWithActual(60)
@@ -241,7 +241,7 @@ func TestDailyTokenReservation_WaitAndAdjust(t *testing.T) {
 		Convey("CompleteWithActual is idempotent when already released", func() {
 			l := NewDailyTokenLimiterWithTimeZone(50, time.UTC).(*DailyTokenLimiter)
 			r, _ := l.Reserve(30)
-			tr := r.(*dailyTokenReservation)
+			tr := r.(*calendarTokenReservation)
 			tr.CompleteWithActual(20)
 			// Call again should have no effect
 			tr.CompleteWithActual(10)
@@ -262,7 +262,7 @@ func TestDailyQuota_InterfaceCompliance(t *testing.T) {
 			So(l, ShouldImplement, (*repository.RequestLimiter)(nil))
 		})
 
-		Convey("dailyRequestReservation implements Reservation", func() {
+		Convey("calendarRequestReservation implements Reservation", func() {
 			l := NewDailyRequestLimiter(1).(*DailyRequestLimiter)
 			r, _ := l.Reserve()
 			So(r, ShouldImplement, (*repository.Reservation)(nil))
@@ -274,7 +274,7 @@ func TestDailyQuota_InterfaceCompliance(t *testing.T) {
 			So(l, ShouldImplement, (*repository.TokenLimiter)(nil))
 		})
 
-		Convey("dailyTokenReservation implements TokenReservation", func() {
+		Convey("calendarTokenReservation implements TokenReservation", func() {
 			l := NewDailyTokenLimiter(2).(*DailyTokenLimiter)
 			r, _ := l.Reserve(1)
 			So(r, ShouldImplement, (*repository.TokenReservation)(nil))
@@ -0,0 +1,393 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// slidingWindowEntry records units admitted at a point in time.
+type slidingWindowEntry struct {
+	at      time.Time
+	amount  int64
+	expired bool
+}
+
+// slidingWindowState holds shared state for sliding window limiters.
+// Usage is logged per admission, so that units are released exactly one window
+// after they were admitted.
+type slidingWindowState struct {
+	mu sync.Mutex
+
+	limit   int64                 // Maximum units within any window
+	window  time.Duration         // Length of the trailing window
+	used    int64                 // Units admitted within the current window
+	entries []*slidingWindowEntry // Admissions within the current window, oldest first
+	waiters atomic.Int64          // Reservations blocked in Wait
+}
+
+func newSlidingWindowState(limit int64, window time.Duration) *slidingWindowState {
+	return &slidingWindowState{
+		limit:  limit,
+		window: window,
+	}
+}
+
+// flush drops admissions that have left the window.
+// Must be called with lock held.
+func (s *slidingWindowState) flush() {
+	now := time.Now()
+	i := 0
+	for ; i < len(s.entries); i++ {
+		e := s.entries[i]
+		if now.Sub(e.at) < s.window {
+			break
+		}
+		s.used -= e.amount
+		e.expired = true
+	}
+	s.entries = s.entries[i:]
+}
+
+// delay returns the time until cost units fit into the window.
+// Must be called with lock held, after flush.
+func (s *slidingWindowState) delay(cost int64) time.Duration {
+	excess := s.used + cost - s.limit
+	if excess <= 0 {
+		return 0
+	}
+	for _, e := range s.entries {
+		excess -= e.amount
+		if excess <= 0 {
+			return max(0, time.Until(e.at.Add(s.window)))
+		}
+	}
+	return repository.InfDuration
+}
+
+// admit records cost units admitted now.
+// Must be called with lock held.
+func (s *slidingWindowState) admit(cost int64) *slidingWindowEntry {
+	e := &slidingWindowEntry{at: time.Now(), amount: cost}
+	s.entries = append(s.entries, e)
+	s.used += cost
+	return e
+}
+
+// adjust changes the units recorded for an admission. Once the admission has
+// left the window, as a long stream outlives it, the units beyond it are
+// charged to the current window instead.
+func (s *slidingWindowState) adjust(e *slidingWindowEntry, amount int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.expired {
+		if extra := amount - e.amount; extra > 0 {
+			s.flush()
+			e.amount = amount
+			s.admit(extra)
+		}
+		return
+	}
+	s.used += amount - e.amount
+	e.amount = amount
+}
+
+// inspect returns a snapshot of the window.
+func (s *slidingWindowState) inspect(unit v1.LimiterUnit) repository.LimiterState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flush()
+
+	state := repository.LimiterState{
+		Type:      v1.LimiterType_LIMITER_TYPE_SLIDING_WINDOW,
+		Unit:      unit,
+		Limit:     s.limit,
+		Remaining: max(0, s.limit-s.used),
+		Waiters:   s.waiters.Load(),
+		Window:    s.window,
+	}
+	if len(s.entries) > 0 {
+		// Quota is next regained when the oldest admission leaves the window
+		state.NextReset = s.entries[0].at.Add(s.window)
+	}
+	return state
+}
+
+// setRemaining replaces the admissions within the window by a single one
+// that leaves the given units available.
+func (s *slidingWindowState) setRemaining(remaining int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		e.expired = true
+	}
+	s.entries = nil
+	s.used = 0
+	if used := s.limit - remaining; used != 0 {
+		s.admit(used)
+	}
+}
+
+// wait blocks until cost units are admitted or the context is done.
+func (s *slidingWindowState) wait(ctx context.Context, cost int64) (*slidingWindowEntry, error) {
+	s.waiters.Add(1)
+	defer s.waiters.Add(-1)
+
+	for {
+		s.mu.Lock()
+		s.flush()
+
+		d := s.delay(cost)
+		if d == 0 {
+			e := s.admit(cost)
+			s.mu.Unlock()
+			return e, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(d):
+			// Retry once the oldest admissions have left the window
+			continue
+		}
+	}
+}
+
+// SlidingWindowRequestLimiter limits the requests admitted within a trailing window.
+type SlidingWindowRequestLimiter struct {
+	state *slidingWindowState
+}
+
+// NewSlidingWindowRequestLimiter creates a request limiter over a trailing window.
+// If limit or window is 0 or negative, returns nil (unlimited).
+func NewSlidingWindowRequestLimiter(limit int64, window time.Duration) repository.RequestLimiter {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	return &SlidingWindowRequestLimiter{state: newSlidingWindowState(limit, window)}
+}
+
+// Probe detects the waiting duration to reserve 1 request without blocking.
+func (l *SlidingWindowRequestLimiter) Probe() time.Duration {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	l.state.flush()
+	return l.state.delay(1)
+}
+
+// Inspect returns a snapshot of the limiter state.
+func (l *SlidingWindowRequestLimiter) Inspect() repository.LimiterState {
+	return l.state.inspect(v1.LimiterUnit_LIMITER_UNIT_REQUEST)
+}
+
+// SetRemaining makes the limiter admit the given requests within the window.
+func (l *SlidingWindowRequestLimiter) SetRemaining(remaining int64) {
+	l.state.setRemaining(remaining)
+}
+
+// Reserve tries to acquire quota for 1 request without blocking.
+func (l *SlidingWindowRequestLimiter) Reserve() (repository.Reservation, error) {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	l.state.flush()
+
+	r := &slidingWindowRequestReservation{state: l.state}
+	if l.state.delay(1) == 0 {
+		r.entry = l.state.admit(1)
+	}
+	return r, nil
+}
+
+// slidingWindowRequestReservation implements repository.Reservation for sliding window request limits.
+type slidingWindowRequestReservation struct {
+	state    *slidingWindowState
+	entry    *slidingWindowEntry
+	released bool
+}
+
+// Delay returns the time to wait before the reservation can be used.
+func (r *slidingWindowRequestReservation) Delay() time.Duration {
+	if r.entry != nil {
+		return 0
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	r.state.flush()
+	return r.state.delay(1)
+}
+
+// Wait blocks until the resource is ready or the context is done.
+func (r *slidingWindowRequestReservation) Wait(ctx context.Context) error {
+	if r.entry != nil || r.released {
+		return nil
+	}
+	e, err := r.state.wait(ctx, 1)
+	if err != nil {
+		return err
+	}
+	r.entry = e
+	return nil
+}
+
+// Cancel returns the reserved quota without consuming it.
+func (r *slidingWindowRequestReservation) Cancel() {
+	if r.released {
+		return
+	}
+	if r.entry != nil {
+		r.state.adjust(r.entry, 0)
+	}
+	r.released = true
+}
+
+// Complete the reservation after actual usage.
+func (r *slidingWindowRequestReservation) Complete() {
+	r.released = true
+}
+
+// SlidingWindowTokenLimiter limits the tokens admitted within a trailing window.
+type SlidingWindowTokenLimiter struct {
+	state *slidingWindowState
+}
+
+// NewSlidingWindowTokenLimiter creates a token limiter over a trailing window.
+// If limit or window is 0 or negative, returns nil (unlimited).
+func NewSlidingWindowTokenLimiter(limit int64, window time.Duration) repository.TokenLimiter {
+	if limit <= 0 || window <= 0 {
+		return nil
+	}
+	return &SlidingWindowTokenLimiter{state: newSlidingWindowState(limit, window)}
+}
+
+// Probe detects the waiting duration to reserve tokens without blocking.
+func (l *SlidingWindowTokenLimiter) Probe(tokens int64) time.Duration {
+	if tokens > l.state.limit {
+		return repository.InfDuration
+	}
+
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	l.state.flush()
+	return l.state.delay(tokens)
+}
+
+// Inspect returns a snapshot of the limiter state.
+func (l *SlidingWindowTokenLimiter) Inspect() repository.LimiterState {
+	return l.state.inspect(v1.LimiterUnit_LIMITER_UNIT_TOKEN)
+}
+
+// SetRemaining makes the limiter admit the given tokens within the window.
+func (l *SlidingWindowTokenLimiter) SetRemaining(remaining int64) {
+	l.state.setRemaining(remaining)
+}
+
+// Reserve tries to acquire quota for given tokens without blocking.
+func (l *SlidingWindowTokenLimiter) Reserve(tokens int64) (repository.TokenReservation, error) {
+	if tokens > l.state.limit {
+		return nil, entity.ErrTokenQuotaExhausted
+	}
+
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	l.state.flush()
+
+	r := &slidingWindowTokenReservation{state: l.state, reserved: tokens}
+	if l.state.delay(tokens) == 0 {
+		r.entry = l.state.admit(tokens)
+	}
+	return r, nil
+}
+
+// slidingWindowTokenReservation implements repository.TokenReservation for sliding window token limits.
+type slidingWindowTokenReservation struct {
+	state    *slidingWindowState
+	entry    *slidingWindowEntry
+	reserved int64
+	released bool
+}
+
+// Delay returns the time to wait before the reservation can be used.
+func (r *slidingWindowTokenReservation) Delay() time.Duration {
+	if r.entry != nil {
+		return 0
+	}
+
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+
+	r.state.flush()
+	return r.state.delay(r.reserved)
+}
+
+// Wait blocks until the resource is ready or the context is done.
+func (r *slidingWindowTokenReservation) Wait(ctx context.Context) error {
+	if r.entry != nil || r.released {
+		return nil
+	}
+	e, err := r.state.wait(ctx, r.reserved)
+	if err != nil {
+		return err
+	}
+	r.entry = e
+	return nil
+}
+
+// Cancel returns the reserved quota without consuming it.
+func (r *slidingWindowTokenReservation) Cancel() {
+	r.CompleteWithActual(0)
+}
+
+// Complete the reservation after actual usage.
+func (r *slidingWindowTokenReservation) Complete() {
+	r.released = true
+}
+
+// CompleteWithActual completes the reservation after actual usage with token adjustment.
+func (r *slidingWindowTokenReservation) CompleteWithActual(actualTokens int64) {
+	if r.released {
+		return
+	}
+	if r.entry != nil {
+		r.state.adjust(r.entry, actualTokens)
+	}
+	r.released = true
+}
+
+var _ repository.RequestLimiter = (*SlidingWindowRequestLimiter)(nil)
+var _ repository.Reservation = (*slidingWindowRequestReservation)(nil)
+var _ repository.TokenLimiter = (*SlidingWindowTokenLimiter)(nil)
+var _ repository.TokenReservation = (*slidingWindowTokenReservation)(nil)
+var _ repository.InspectableLimiter = (*SlidingWindowRequestLimiter)(nil)
+var _ repository.AdjustableLimiter = (*SlidingWindowRequestLimiter)(nil)
+var _ repository.InspectableLimiter = (*SlidingWindowTokenLimiter)(nil)
+var _ repository.AdjustableLimiter = (*SlidingWindowTokenLimiter)(nil)
@@ -0,0 +1,173 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+func TestNewSlidingWindowLimiters(t *testing.T) {
+	Convey("Sliding window limiter constructors", t, func() {
+		Convey("zero limit or window returns nil", func() {
+			So(NewSlidingWindowRequestLimiter(0, time.Hour), ShouldBeNil)
+			So(NewSlidingWindowRequestLimiter(10, 0), ShouldBeNil)
+			So(NewSlidingWindowTokenLimiter(0, time.Hour), ShouldBeNil)
+			So(NewSlidingWindowTokenLimiter(10, -time.Hour), ShouldBeNil)
+		})
+
+		Convey("positive limit returns limiter", func() {
+			So(NewSlidingWindowRequestLimiter(10, time.Hour), ShouldImplement, (*repository.RequestLimiter)(nil))
+			So(NewSlidingWindowTokenLimiter(10, time.Hour), ShouldImplement, (*repository.TokenLimiter)(nil))
+		})
+	})
+}
+
+func TestSlidingWindowRequestLimiter(t *testing.T) {
+	Convey("SlidingWindowRequestLimiter", t, func() {
+		l := NewSlidingWindowRequestLimiter(2, 50*time.Millisecond).(*SlidingWindowRequestLimiter)
+
+		r1, err := l.Reserve()
+		So(err, ShouldBeNil)
+		So(r1.Delay(), ShouldEqual, 0)
+		r1.Complete()
+
+		time.Sleep(20 * time.Millisecond)
+		r2, err := l.Reserve()
+		So(err, ShouldBeNil)
+		So(r2.Delay(), ShouldEqual, 0)
+
+		Convey("exhausted window waits for the oldest request to expire", func() {
+			d := l.Probe()
+			So(d, ShouldBeGreaterThan, 0)
+			So(d, ShouldBeLessThanOrEqualTo, 30*time.Millisecond)
+
+			r3, err := l.Reserve()
+			So(err, ShouldBeNil)
+			So(r3.Delay(), ShouldBeGreaterThan, 0)
+			So(r3.Wait(context.Background()), ShouldBeNil)
+			So(r3.Delay(), ShouldEqual, 0)
+		})
+
+		Convey("cancel returns the request to the window", func() {
+			So(l.Probe(), ShouldBeGreaterThan, 0)
+			r2.Cancel()
+			So(l.Probe(), ShouldEqual, 0)
+		})
+
+		Convey("wait respects context cancellation", func() {
+			r3, err := l.Reserve()
+			So(err, ShouldBeNil)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			So(r3.Wait(ctx), ShouldEqual, context.Canceled)
+		})
+	})
+}
+
+func TestSlidingWindowTokenLimiter(t *testing.T) {
+	Convey("SlidingWindowTokenLimiter", t, func() {
+		l := NewSlidingWindowTokenLimiter(100, time.Hour).(*SlidingWindowTokenLimiter)
+
+		Convey("requests above the limit are rejected", func() {
+			So(l.Probe(101), ShouldEqual, repository.InfDuration)
+			_, err := l.Reserve(101)
+			So(err, ShouldEqual, entity.ErrTokenQuotaExhausted)
+		})
+
+		Convey("completion adjusts usage to the actual tokens", func() {
+			r, err := l.Reserve(80)
+			So(err, ShouldBeNil)
+			So(l.Probe(30), ShouldBeGreaterThan, 0)
+
+			r.CompleteWithActual(20)
+			So(l.state.used, ShouldEqual, int64(20))
+			So(l.Probe(80), ShouldEqual, 0)
+		})
+
+		Convey("cancel refunds the reservation", func() {
+			r, err := l.Reserve(60)
+			So(err, ShouldBeNil)
+			r.Cancel()
+			So(l.state.used, ShouldEqual, int64(0))
+		})
+
+		Convey("expired admissions charge the excess to the current window", func() {
+			l := NewSlidingWindowTokenLimiter(100, 10*time.Millisecond).(*SlidingWindowTokenLimiter)
+			r, err := l.Reserve(60)
+			So(err, ShouldBeNil)
+			time.Sleep(15 * time.Millisecond)
+			So(l.Probe(100), ShouldEqual, 0)
+
+			r.CompleteWithActual(90)
+			So(l.state.used, ShouldEqual, int64(30))
+		})
+
+		Convey("expired admissions are not refunded", func() {
+			l := NewSlidingWindowTokenLimiter(100, 10*time.Millisecond).(*SlidingWindowTokenLimiter)
+			r, err := l.Reserve(60)
+			So(err, ShouldBeNil)
+			time.Sleep(15 * time.Millisecond)
+			So(l.Probe(100), ShouldEqual, 0)
+
+			r.CompleteWithActual(20)
+			So(l.state.used, ShouldEqual, int64(0))
+		})
+	})
+}
+
+func TestSlidingWindowLimiter_InspectAndSetRemaining(t *testing.T) {
+	Convey("Sliding window limiters inspection", t, func() {
+		l := NewSlidingWindowTokenLimiter(100, time.Hour).(*SlidingWindowTokenLimiter)
+
+		s := l.Inspect()
+		So(s.Type, ShouldEqual, v1.LimiterType_LIMITER_TYPE_SLIDING_WINDOW)
+		So(s.Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_TOKEN)
+		So(s.Remaining, ShouldEqual, 100)
+		So(s.NextReset.IsZero(), ShouldBeTrue)
+
+		r, err := l.Reserve(30)
+		So(err, ShouldBeNil)
+		s = l.Inspect()
+		So(s.Remaining, ShouldEqual, 70)
+		So(s.NextReset, ShouldHappenAfter, time.Now())
+
+		Convey("SetRemaining replaces the admissions", func() {
+			l.SetRemaining(10)
+			So(l.Inspect().Remaining, ShouldEqual, 10)
+
+			// Completing a reservation admitted before the adjustment is a no-op
+			r.CompleteWithActual(5)
+			So(l.Inspect().Remaining, ShouldEqual, 10)
+
+			l.SetRemaining(100)
+			So(l.state.entries, ShouldBeEmpty)
+		})
+
+		Convey("request limiter reports requests", func() {
+			rl := NewSlidingWindowRequestLimiter(5, time.Hour).(*SlidingWindowRequestLimiter)
+			rl.SetRemaining(0)
+			So(rl.Inspect().Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_REQUEST)
+			So(rl.Probe(), ShouldBeGreaterThan, 0)
+		})
+	})
+}
@@ -16,9 +16,12 @@ package local
 
 import (
 	"context"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
 )
@@ -30,6 +33,7 @@ type tokenBucket struct {
 	burst      float64
 	tokens     float64
 	lastUpdate time.Time
+	waiters    atomic.Int64 // reservations blocked in Wait
 }
 
 func newTokenBucket(rate, burst float64) *tokenBucket {
@@ -112,6 +116,54 @@ func (b *tokenBucket) adjust(diff float64) {
 	}
 }
 
+// inspect returns a snapshot of the bucket
+func (b *tokenBucket) inspect() repository.LimiterState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.fill()
+
+	state := repository.LimiterState{
+		Type:      v1.LimiterType_LIMITER_TYPE_TOKEN_BUCKET,
+		Limit:     int64(b.burst),
+		Remaining: int64(math.Max(0, math.Floor(b.tokens))),
+		Waiters:   b.waiters.Load(),
+		Window:    time.Duration(b.burst / b.rate * float64(time.Second)),
+	}
+	if b.tokens < b.burst {
+		// The bucket is full again once the deficit is replenished
+		state.NextReset = b.lastUpdate.Add(time.Duration((b.burst - b.tokens) / b.rate * float64(time.Second)))
+	}
+	return state
+}
+
+// setRemaining overwrites the balance, capped at the burst
+func (b *tokenBucket) setRemaining(remaining float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.fill()
+	b.tokens = min(remaining, b.burst)
+}
+
+// wait blocks until readyAt or the context is done
+func (b *tokenBucket) wait(ctx context.Context, readyAt time.Time) error {
+	delay := time.Until(readyAt)
+	if delay <= 0 {
+		return nil
+	}
+
+	b.waiters.Add(1)
+	defer b.waiters.Add(-1)
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // rpmLimiter implements the repository.RequestLimiter for RPM
 type rpmLimiter struct {
 	bucket *tokenBucket
@@ -132,6 +184,16 @@ func (l *rpmLimiter) Probe() time.Duration {
 	return l.bucket.probe(1)
 }
 
+func (l *rpmLimiter) Inspect() repository.LimiterState {
+	state := l.bucket.inspect()
+	state.Unit = v1.LimiterUnit_LIMITER_UNIT_REQUEST
+	return state
+}
+
+func (l *rpmLimiter) SetRemaining(remaining int64) {
+	l.bucket.setRemaining(float64(remaining))
+}
+
 func (l *rpmLimiter) Reserve() (repository.Reservation, error) {
 	return &requestReservation{
 		limiter: l,
@@ -151,12 +213,7 @@ func (r *requestReservation) Delay() time.Duration {
 }
 
 func (r *requestReservation) Wait(ctx context.Context) error {
-	select {
-	case <-time.After(r.Delay()):
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+	return r.limiter.bucket.wait(ctx, r.readyAt)
 }
 
 func (r *requestReservation) Cancel() {
@@ -192,6 +249,16 @@ func (l *tpmLimiter) Probe(tokens int64) time.Duration {
 	return l.bucket.probe(float64(tokens))
 }
 
+func (l *tpmLimiter) Inspect() repository.LimiterState {
+	state := l.bucket.inspect()
+	state.Unit = v1.LimiterUnit_LIMITER_UNIT_TOKEN
+	return state
+}
+
+func (l *tpmLimiter) SetRemaining(remaining int64) {
+	l.bucket.setRemaining(float64(remaining))
+}
+
 func (l *tpmLimiter) Reserve(tokens int64) (repository.TokenReservation, error) {
 	if tokens > int64(l.bucket.burst) {
 		return nil, entity.ErrTokenQuotaExhausted
@@ -216,12 +283,7 @@ func (r *tokenReservation) Delay() time.Duration {
 }
 
 func (r *tokenReservation) Wait(ctx context.Context) error {
-	select {
-	case <-time.After(r.Delay()):
-		return nil
-	case <-ctx.Done():
-		return ctx.Err()
-	}
+	return r.limiter.bucket.wait(ctx, r.readyAt)
 }
 
 func (r *tokenReservation) Cancel() {
@@ -247,3 +309,8 @@ func (r *tokenReservation) CompleteWithActual(actualTokens int64) {
 	}
 	r.limiter.bucket.adjust(diff)
 }
+
+var _ repository.InspectableLimiter = (*rpmLimiter)(nil)
+var _ repository.AdjustableLimiter = (*rpmLimiter)(nil)
+var _ repository.InspectableLimiter = (*tpmLimiter)(nil)
+var _ repository.AdjustableLimiter = (*tpmLimiter)(nil)
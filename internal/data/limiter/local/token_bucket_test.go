@@ -21,6 +21,7 @@ import (
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/repository"
 )
 
@@ -814,3 +815,32 @@ func TestTokenBucket_InterfaceCompliance(t *testing.T) {
 		})
 	})
 }
+
+func TestTokenBucket_InspectAndSetRemaining(t *testing.T) {
+	Convey("Token bucket limiters inspection", t, func() {
+		l := NewRPMLimiter(60).(*rpmLimiter)
+
+		s := l.Inspect()
+		So(s.Type, ShouldEqual, v1.LimiterType_LIMITER_TYPE_TOKEN_BUCKET)
+		So(s.Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_REQUEST)
+		So(s.Limit, ShouldEqual, 60)
+		So(s.Remaining, ShouldEqual, 60)
+		So(s.NextReset.IsZero(), ShouldBeTrue)
+
+		Convey("SetRemaining drains and refills the bucket", func() {
+			l.SetRemaining(0)
+			So(l.Probe(), ShouldBeGreaterThan, 0)
+			s := l.Inspect()
+			So(s.Remaining, ShouldEqual, 0)
+			So(s.NextReset, ShouldHappenAfter, time.Now())
+
+			l.SetRemaining(1000)
+			So(l.Inspect().Remaining, ShouldEqual, 60)
+		})
+
+		Convey("token limiter reports tokens", func() {
+			tl := NewTPMLimiter(1000).(*tpmLimiter)
+			So(tl.Inspect().Unit, ShouldEqual, v1.LimiterUnit_LIMITER_UNIT_TOKEN)
+		})
+	})
+}
@@ -0,0 +1,242 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+const (
+	fileExt           = ".json"
+	fileSweepInterval = time.Hour
+)
+
+// fileRecord is the on-disk form of a stored response.
+type fileRecord struct {
+	ID         string            `json:"id"`
+	Client     string            `json:"client,omitempty"`
+	Messages   []json.RawMessage `json:"messages"`
+	Output     json.RawMessage   `json:"output,omitempty"`
+	Response   []byte            `json:"response,omitempty"`
+	InputItems []byte            `json:"input_items,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+}
+
+// fileStore keeps each response as a JSON file in a directory. The
+// modification time of a file is set to its expiry, so that expired responses
+// are swept without reading them.
+type fileStore struct {
+	dir  string
+	ttl  time.Duration
+	log  *slog.Logger
+	now  func() time.Time
+	stop chan struct{}
+}
+
+func newFileStore(dir string, ttl time.Duration, logger *slog.Logger) (*fileStore, error) {
+	if dir == "" {
+		return nil, errors.New("response store directory is not configured")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	s := &fileStore{
+		dir:  dir,
+		ttl:  ttl,
+		log:  logger,
+		now:  time.Now,
+		stop: make(chan struct{}),
+	}
+	s.sweep()
+	go s.sweepPeriodically()
+	return s, nil
+}
+
+// path returns the file of the response of the client. Client identities and
+// ids come from requests, so they are hashed into safe file names.
+func (s *fileStore) path(client, id string) string {
+	sum := sha256.Sum256([]byte(client + "\x00" + id))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+fileExt)
+}
+
+func (s *fileStore) Put(_ context.Context, resp *entity.StoredResponse) error {
+	if resp.CreatedAt.IsZero() {
+		resp.CreatedAt = s.now()
+	}
+	resp.ExpiresAt = resp.CreatedAt.Add(s.ttl)
+
+	record := &fileRecord{
+		ID:         resp.Id,
+		Client:     resp.Client,
+		Messages:   make([]json.RawMessage, 0, len(resp.Messages)),
+		Response:   resp.Response,
+		InputItems: resp.InputItems,
+		CreatedAt:  resp.CreatedAt,
+		ExpiresAt:  resp.ExpiresAt,
+	}
+	for _, m := range resp.Messages {
+		data, err := protojson.Marshal(m)
+		if err != nil {
+			return err
+		}
+		record.Messages = append(record.Messages, data)
+	}
+	if resp.Output != nil {
+		data, err := protojson.Marshal(resp.Output)
+		if err != nil {
+			return err
+		}
+		record.Output = data
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that readers never see a partial record
+	tmp, err := os.CreateTemp(s.dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Chtimes(tmp.Name(), resp.ExpiresAt, resp.ExpiresAt); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(resp.Client, resp.Id))
+}
+
+func (s *fileStore) Get(_ context.Context, client, id string) (*entity.StoredResponse, error) {
+	path := s.path(client, id)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, entity.ErrResponseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record fileRecord
+	if err = json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	if record.ID != id || record.Client != client {
+		return nil, entity.ErrResponseNotFound
+	}
+	if !s.now().Before(record.ExpiresAt) {
+		_ = os.Remove(path)
+		return nil, entity.ErrResponseNotFound
+	}
+
+	resp := &entity.StoredResponse{
+		Id:         record.ID,
+		Client:     record.Client,
+		Messages:   make([]*v1.Message, 0, len(record.Messages)),
+		Response:   record.Response,
+		InputItems: record.InputItems,
+		CreatedAt:  record.CreatedAt,
+		ExpiresAt:  record.ExpiresAt,
+	}
+	for _, data := range record.Messages {
+		m := &v1.Message{}
+		if err = protojson.Unmarshal(data, m); err != nil {
+			return nil, err
+		}
+		resp.Messages = append(resp.Messages, m)
+	}
+	if len(record.Output) > 0 {
+		resp.Output = &v1.Message{}
+		if err = protojson.Unmarshal(record.Output, resp.Output); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *fileStore) Delete(_ context.Context, client, id string) error {
+	err := os.Remove(s.path(client, id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return entity.ErrResponseNotFound
+	}
+	return err
+}
+
+// sweep removes the files of expired responses.
+func (s *fileStore) sweep() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		s.log.Warn("failed to sweep response store", "dir", s.dir, "error", err)
+		return
+	}
+
+	now := s.now()
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || info.ModTime().After(now) {
+			continue
+		}
+		if err = os.Remove(filepath.Join(s.dir, e.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			s.log.Warn("failed to remove expired response", "file", e.Name(), "error", err)
+		}
+	}
+}
+
+func (s *fileStore) sweepPeriodically() {
+	ticker := time.NewTicker(fileSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.sweep()
+		}
+	}
+}
+
+func (s *fileStore) close() {
+	close(s.stop)
+}
+
+var _ repository.ResponseStoreRepo = (*fileStore)(nil)
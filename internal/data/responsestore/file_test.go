@@ -0,0 +1,110 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsestore
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func TestFileStore(t *testing.T) {
+	Convey("Test fileStore", t, func() {
+		ctx := context.Background()
+		dir := t.TempDir()
+		s, err := newFileStore(dir, time.Hour, slog.Default())
+		So(err, ShouldBeNil)
+		defer s.close()
+
+		Convey("should round-trip a response", func() {
+			resp := makeStoredResponse("resp/../1")
+			resp.Client = "alice"
+			So(s.Put(ctx, resp), ShouldBeNil)
+
+			got, err := s.Get(ctx, "alice", "resp/../1")
+			So(err, ShouldBeNil)
+			So(got.Id, ShouldEqual, "resp/../1")
+			So(got.Client, ShouldEqual, "alice")
+			So(got.Messages, ShouldHaveLength, 1)
+			So(proto.Equal(got.Messages[0], resp.Messages[0]), ShouldBeTrue)
+			So(proto.Equal(got.Output, resp.Output), ShouldBeTrue)
+			So(string(got.Response), ShouldEqual, string(resp.Response))
+			So(got.ExpiresAt.Equal(resp.ExpiresAt), ShouldBeTrue)
+
+			// Ids are hashed, so they never escape the directory
+			entries, err := os.ReadDir(dir)
+			So(err, ShouldBeNil)
+			So(entries, ShouldHaveLength, 1)
+		})
+
+		Convey("should keep the responses of clients apart", func() {
+			alice := makeStoredResponse("resp_1")
+			alice.Client = "alice"
+			So(s.Put(ctx, alice), ShouldBeNil)
+			bob := makeStoredResponse("resp_1")
+			bob.Client = "bob"
+			So(s.Put(ctx, bob), ShouldBeNil)
+
+			got, err := s.Get(ctx, "alice", "resp_1")
+			So(err, ShouldBeNil)
+			So(got.Client, ShouldEqual, "alice")
+			_, err = s.Get(ctx, "", "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+
+			So(s.Delete(ctx, "bob", "resp_1"), ShouldBeNil)
+			_, err = s.Get(ctx, "alice", "resp_1")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should expire and sweep responses after the TTL", func() {
+			So(s.Put(ctx, makeStoredResponse("resp_1")), ShouldBeNil)
+			s.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+			_, err := s.Get(ctx, "", "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+
+			So(s.Put(ctx, makeStoredResponse("resp_2")), ShouldBeNil)
+			s.now = func() time.Time { return time.Now().Add(3 * time.Hour) }
+			s.sweep()
+			entries, err := os.ReadDir(dir)
+			So(err, ShouldBeNil)
+			So(entries, ShouldBeEmpty)
+		})
+
+		Convey("should delete responses", func() {
+			So(s.Put(ctx, makeStoredResponse("resp_1")), ShouldBeNil)
+			So(s.Delete(ctx, "", "resp_1"), ShouldBeNil)
+			So(errors.Is(s.Delete(ctx, "", "resp_1"), entity.ErrResponseNotFound), ShouldBeTrue)
+		})
+
+		Convey("should keep responses across instances", func() {
+			So(s.Put(ctx, makeStoredResponse("resp_1")), ShouldBeNil)
+
+			reopened, err := newFileStore(dir, time.Hour, slog.Default())
+			So(err, ShouldBeNil)
+			defer reopened.close()
+			_, err = reopened.Get(ctx, "", "resp_1")
+			So(err, ShouldBeNil)
+		})
+	})
+}
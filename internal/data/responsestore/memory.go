@@ -0,0 +1,121 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsestore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+)
+
+// responseKey identifies a response among those of all clients.
+type responseKey struct {
+	client string
+	id     string
+}
+
+// memoryStore keeps responses in process memory.
+type memoryStore struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[responseKey]*list.Element
+	// Responses in the order they were stored. As all share the same TTL, this
+	// is also the order in which they expire.
+	order *list.List
+	now   func() time.Time
+}
+
+func newMemoryStore(maxEntries int, ttl time.Duration) *memoryStore {
+	return &memoryStore{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[responseKey]*list.Element),
+		order:      list.New(),
+		now:        time.Now,
+	}
+}
+
+func (s *memoryStore) Put(_ context.Context, resp *entity.StoredResponse) error {
+	if resp.CreatedAt.IsZero() {
+		resp.CreatedAt = s.now()
+	}
+	resp.ExpiresAt = resp.CreatedAt.Add(s.ttl)
+	stored := cloneStoredResponse(resp)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := responseKey{client: stored.Client, id: stored.Id}
+	if e, ok := s.entries[key]; ok {
+		s.order.Remove(e)
+	}
+	s.entries[key] = s.order.PushBack(stored)
+
+	s.evict()
+	return nil
+}
+
+// evict drops expired responses and the oldest beyond the capacity.
+// Must be called with lock held.
+func (s *memoryStore) evict() {
+	now := s.now()
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		resp := e.Value.(*entity.StoredResponse)
+		if s.order.Len() <= s.maxEntries && now.Before(resp.ExpiresAt) {
+			return
+		}
+		s.order.Remove(e)
+		delete(s.entries, responseKey{client: resp.Client, id: resp.Id})
+	}
+}
+
+func (s *memoryStore) Get(_ context.Context, client, id string) (*entity.StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := responseKey{client: client, id: id}
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, entity.ErrResponseNotFound
+	}
+	resp := e.Value.(*entity.StoredResponse)
+	if !s.now().Before(resp.ExpiresAt) {
+		s.order.Remove(e)
+		delete(s.entries, key)
+		return nil, entity.ErrResponseNotFound
+	}
+	return cloneStoredResponse(resp), nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, client, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := responseKey{client: client, id: id}
+	e, ok := s.entries[key]
+	if !ok {
+		return entity.ErrResponseNotFound
+	}
+	s.order.Remove(e)
+	delete(s.entries, key)
+	return nil
+}
+
+var _ repository.ResponseStoreRepo = (*memoryStore)(nil)
@@ -0,0 +1,114 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func makeStoredResponse(id string) *entity.StoredResponse {
+	return &entity.StoredResponse{
+		Id: id,
+		Messages: []*v1.Message{
+			{Role: v1.Role_ROLE_USER, Contents: []*v1.Content{{Content: v1.NewTextContent("hi")}}},
+		},
+		Output: &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{{
+				Phase:     v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Signature: "sig",
+				Content:   v1.NewTextContent("thinking"),
+			}},
+		},
+		Response:   []byte(`{"id":"` + id + `"}`),
+		InputItems: []byte("[]"),
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	Convey("Test memoryStore", t, func() {
+		ctx := context.Background()
+		clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		s := newMemoryStore(2, time.Hour)
+		s.now = func() time.Time { return clock }
+
+		Convey("should return a copy of a stored response", func() {
+			resp := makeStoredResponse("resp_1")
+			So(s.Put(ctx, resp), ShouldBeNil)
+			So(resp.ExpiresAt, ShouldEqual, clock.Add(time.Hour))
+
+			// Later changes of the caller must not leak into the store
+			resp.Messages[0].Contents[0] = &v1.Content{Content: v1.NewTextContent("changed")}
+
+			got, err := s.Get(ctx, "", "resp_1")
+			So(err, ShouldBeNil)
+			So(got.Messages[0].Contents[0].GetText().GetText(), ShouldEqual, "hi")
+			So(got.Output.Contents[0].Signature, ShouldEqual, "sig")
+			So(string(got.Response), ShouldEqual, `{"id":"resp_1"}`)
+		})
+
+		Convey("should keep the responses of clients apart", func() {
+			alice := makeStoredResponse("resp_1")
+			alice.Client = "alice"
+			So(s.Put(ctx, alice), ShouldBeNil)
+			bob := makeStoredResponse("resp_1")
+			bob.Client = "bob"
+			So(s.Put(ctx, bob), ShouldBeNil)
+
+			got, err := s.Get(ctx, "alice", "resp_1")
+			So(err, ShouldBeNil)
+			So(got.Client, ShouldEqual, "alice")
+			_, err = s.Get(ctx, "", "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+
+			So(s.Delete(ctx, "bob", "resp_1"), ShouldBeNil)
+			_, err = s.Get(ctx, "alice", "resp_1")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should expire responses after the TTL", func() {
+			So(s.Put(ctx, makeStoredResponse("resp_1")), ShouldBeNil)
+			clock = clock.Add(time.Hour)
+			_, err := s.Get(ctx, "", "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+		})
+
+		Convey("should evict the oldest response beyond the capacity", func() {
+			for _, id := range []string{"resp_1", "resp_2", "resp_3"} {
+				So(s.Put(ctx, makeStoredResponse(id)), ShouldBeNil)
+			}
+			_, err := s.Get(ctx, "", "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+			_, err = s.Get(ctx, "", "resp_3")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("should delete responses", func() {
+			So(s.Put(ctx, makeStoredResponse("resp_1")), ShouldBeNil)
+			So(s.Delete(ctx, "", "resp_1"), ShouldBeNil)
+			So(errors.Is(s.Delete(ctx, "", "resp_1"), entity.ErrResponseNotFound), ShouldBeTrue)
+			_, err := s.Get(ctx, "", "resp_1")
+			So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+		})
+	})
+}
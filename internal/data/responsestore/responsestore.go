@@ -0,0 +1,81 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsestore
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/wire"
+	"google.golang.org/protobuf/proto"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+var ProviderSet = wire.NewSet(NewResponseStore)
+
+const (
+	defaultTTL        = 30 * 24 * time.Hour
+	defaultMaxEntries = 10000
+)
+
+// NewResponseStore creates the configured response store. It returns nil if
+// no store is configured, in which case responses are not stored.
+func NewResponseStore(c *conf.Data, logger *slog.Logger) (repository.ResponseStoreRepo, func(), error) {
+	sc := c.GetResponseStore()
+	if sc == nil {
+		return nil, func() {}, nil
+	}
+
+	// A TTL that is not positive would expire every response as it is stored
+	ttl := defaultTTL
+	if d := sc.GetTtl().AsDuration(); d > 0 {
+		ttl = d
+	}
+
+	switch backend := sc.GetBackend().(type) {
+	case *conf.ResponseStore_File_:
+		store, err := newFileStore(backend.File.GetDir(), ttl, logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, store.close, nil
+	default:
+		maxEntries := int(sc.GetMemory().GetMaxEntries())
+		if maxEntries <= 0 {
+			maxEntries = defaultMaxEntries
+		}
+		return newMemoryStore(maxEntries, ttl), func() {}, nil
+	}
+}
+
+// cloneStoredResponse deep-copies a response, so that stored messages are
+// never shared with a request in flight.
+func cloneStoredResponse(resp *entity.StoredResponse) *entity.StoredResponse {
+	clone := *resp
+	clone.Messages = make([]*v1.Message, len(resp.Messages))
+	for i, m := range resp.Messages {
+		clone.Messages[i] = proto.Clone(m).(*v1.Message)
+	}
+	if resp.Output != nil {
+		clone.Output = proto.Clone(resp.Output).(*v1.Message)
+	}
+	clone.Response = append([]byte(nil), resp.Response...)
+	clone.InputItems = append([]byte(nil), resp.InputItems...)
+	return &clone
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package responsestore
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestNewResponseStore(t *testing.T) {
+	Convey("Test NewResponseStore", t, func() {
+		Convey("should not store responses without config", func() {
+			store, cleanup, err := NewResponseStore(&conf.Data{}, slog.Default())
+			So(err, ShouldBeNil)
+			So(store, ShouldBeNil)
+			cleanup()
+		})
+
+		Convey("should apply the configured TTL", func() {
+			store, _, err := NewResponseStore(&conf.Data{ResponseStore: &conf.ResponseStore{
+				Ttl: durationpb.New(time.Hour),
+			}}, slog.Default())
+			So(err, ShouldBeNil)
+			So(store.(*memoryStore).ttl, ShouldEqual, time.Hour)
+		})
+
+		Convey("should fall back to the default TTL when it is zero", func() {
+			store, _, err := NewResponseStore(&conf.Data{ResponseStore: &conf.ResponseStore{
+				Ttl: durationpb.New(0),
+			}}, slog.Default())
+			So(err, ShouldBeNil)
+			So(store.(*memoryStore).ttl, ShouldEqual, defaultTTL)
+		})
+	})
+}
@@ -16,8 +16,10 @@ package anthropic
 
 import (
 	"context"
+	"errors"
 	"iter"
 	"log/slog"
+	"net/http"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -37,8 +39,8 @@ type upstream struct {
 }
 
 func NewAnthropicChatRepoFactory(loggerProvider otellog.LoggerProvider) repository.UpstreamFactory[conf.AnthropicConfig] {
-	return func(config *conf.AnthropicConfig, logger *slog.Logger) (repository.Repo, error) {
-		client := shared.NewRecordingClientFromLoggerProvider(loggerProvider, "neurouter.upstream.anthropic")
+	return func(config *conf.AnthropicConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+		client := shared.NewClient(loggerProvider, "neurouter.upstream.anthropic", transport)
 		return newAnthropicUpstreamWithClient(config, client, logger)
 	}
 }
@@ -74,6 +76,7 @@ func (r *upstream) Chat(ctx context.Context, req *entity.ChatRequest) (resp *ent
 
 	anthropicResp, err := r.client.Messages.New(ctx, anthropicReq)
 	if err != nil {
+		err = convertError(err)
 		return
 	}
 
@@ -88,6 +91,16 @@ func (r *upstream) Chat(ctx context.Context, req *entity.ChatRequest) (resp *ent
 	return
 }
 
+func (r *upstream) CountTokens(ctx context.Context, req *entity.ChatRequest) (int64, error) {
+	params := convertCountTokensRequestToAnthropic(r.convertRequestToAnthropic(req))
+
+	count, err := r.client.Messages.CountTokens(ctx, params)
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return count.InputTokens, nil
+}
+
 type anthropicChatStreamClient struct {
 	req                  *entity.ChatRequest
 	upstream             *ssestream.Stream[anthropic.MessageStreamEventUnion]
@@ -102,7 +115,7 @@ func (c *anthropicChatStreamClient) AsSeq() iter.Seq2[*entity.ChatEvent, error]
 		for {
 			if !c.upstream.Next() {
 				if err := c.upstream.Err(); err != nil {
-					yield(nil, err)
+					yield(nil, convertError(err))
 				}
 				return
 			}
@@ -127,3 +140,29 @@ func (r *upstream) ChatStream(ctx context.Context, req *entity.ChatRequest) iter
 
 	return client.AsSeq()
 }
+
+func (r *upstream) ListModels(ctx context.Context) ([]*entity.ModelSpec, error) {
+	var specs []*entity.ModelSpec
+	pager := r.client.Models.ListAutoPaging(ctx, anthropic.ModelListParams{})
+	for pager.Next() {
+		specs = append(specs, convertModelInfoFromAnthropic(new(pager.Current())))
+	}
+	if err := pager.Err(); err != nil {
+		return nil, convertError(err)
+	}
+	return specs, nil
+}
+
+// convertError marks the errors of a rejected API key.
+func convertError(err error) error {
+	var apiErr *anthropic.Error
+	if errors.As(err, &apiErr) {
+		return shared.ConvertStatusError(apiErr.StatusCode, string(apiErr.Type()), err)
+	}
+	return err
+}
+
+var (
+	_ repository.TokenCountingRepo = (*upstream)(nil)
+	_ repository.ModelListingRepo  = (*upstream)(nil)
+)
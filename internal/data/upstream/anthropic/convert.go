@@ -20,6 +20,7 @@ import (
 	"math"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/packages/param"
 	"github.com/tidwall/gjson"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -47,8 +48,9 @@ func (r *upstream) convertRequestToAnthropic(req *entity.ChatRequest) anthropic.
 	}
 
 	if req.Tools != nil {
+		// Anthropic has no list of allowed tools, so the others are left out
 		var tools []anthropic.ToolUnionParam
-		for _, tool := range req.Tools {
+		for _, tool := range req.ToolChoice.FilterTools(req.Tools) {
 			switch t := tool.Tool.(type) {
 			case *v1.Tool_Function_:
 				at := &anthropic.ToolParam{
@@ -65,6 +67,9 @@ func (r *upstream) convertRequestToAnthropic(req *entity.ChatRequest) anthropic.
 		}
 		params.Tools = tools
 	}
+	if len(params.Tools) > 0 && req.ToolChoice != nil {
+		params.ToolChoice = convertToolChoiceToAnthropic(req.ToolChoice)
+	}
 
 	if req.Metadata != nil {
 		if userID, ok := req.Metadata["user_id"]; ok {
@@ -75,6 +80,61 @@ func (r *upstream) convertRequestToAnthropic(req *entity.ChatRequest) anthropic.
 	return params
 }
 
+// convertToolChoiceToAnthropic converts the tool choice. Anthropic sets the
+// parallel tool use along with the mode, so an unspecified mode restricting
+// parallel calls becomes auto.
+func convertToolChoiceToAnthropic(toolChoice *v1.ToolChoice) anthropic.ToolChoiceUnionParam {
+	var disableParallelToolUse param.Opt[bool]
+	if toolChoice.ParallelToolCalls != nil {
+		disableParallelToolUse = anthropic.Opt(!toolChoice.GetParallelToolCalls())
+	}
+
+	switch toolChoice.Mode {
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+		return anthropic.ToolChoiceUnionParam{OfNone: &anthropic.ToolChoiceNoneParam{}}
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED:
+		return anthropic.ToolChoiceUnionParam{OfAny: &anthropic.ToolChoiceAnyParam{
+			DisableParallelToolUse: disableParallelToolUse,
+		}}
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		return anthropic.ToolChoiceUnionParam{OfTool: &anthropic.ToolChoiceToolParam{
+			Name:                   toolChoice.Name,
+			DisableParallelToolUse: disableParallelToolUse,
+		}}
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO:
+		return anthropic.ToolChoiceUnionParam{OfAuto: &anthropic.ToolChoiceAutoParam{
+			DisableParallelToolUse: disableParallelToolUse,
+		}}
+	}
+	if disableParallelToolUse.Valid() {
+		return anthropic.ToolChoiceUnionParam{OfAuto: &anthropic.ToolChoiceAutoParam{
+			DisableParallelToolUse: disableParallelToolUse,
+		}}
+	}
+	return anthropic.ToolChoiceUnionParam{}
+}
+
+// convertCountTokensRequestToAnthropic narrows a message request to the fields
+// the count_tokens endpoint accepts.
+func convertCountTokensRequestToAnthropic(params anthropic.MessageNewParams) anthropic.MessageCountTokensParams {
+	countParams := anthropic.MessageCountTokensParams{
+		Messages:     params.Messages,
+		Model:        params.Model,
+		OutputConfig: params.OutputConfig,
+		Thinking:     params.Thinking,
+		ToolChoice:   params.ToolChoice,
+	}
+	if len(params.System) > 0 {
+		countParams.System.OfTextBlockArray = params.System
+	}
+	for _, tool := range params.Tools {
+		if tool.OfTool != nil {
+			countParams.Tools = append(countParams.Tools, anthropic.MessageCountTokensToolUnionParam{OfTool: tool.OfTool})
+		}
+	}
+	return countParams
+}
+
 func (r *upstream) convertGenerationConfigToAnthropic(config *v1.GenerationConfig, req *anthropic.MessageNewParams) {
 	if config == nil {
 		return
@@ -145,11 +205,19 @@ func (r *upstream) convertMessageToAnthropic(message *v1.Message) anthropic.Mess
 		switch c := content.GetContent().(type) {
 		case *v1.Content_Text:
 			if content.Phase == v1.ContentPhase_CONTENT_PHASE_REASONING {
+				// Anthropic rejects thinking blocks it cannot verify, so reasoning
+				// produced by other providers is dropped.
+				if content.Signature == "" || !content.VerifiableBy(v1.Provenance_PROVENANCE_ANTHROPIC) {
+					continue
+				}
 				parts = append(parts, anthropic.NewThinkingBlock(content.Signature, c.Text.GetText()))
 			} else {
 				parts = append(parts, anthropic.NewTextBlock(c.Text.GetText()))
 			}
 		case *v1.Content_Opaque:
+			if !content.VerifiableBy(v1.Provenance_PROVENANCE_ANTHROPIC) {
+				continue
+			}
 			parts = append(parts, anthropic.NewRedactedThinkingBlock(c.Opaque))
 		case *v1.Content_Image:
 			switch src := c.Image.Source.(type) {
@@ -289,14 +357,16 @@ func convertMessageFromAnthropic(msg *anthropic.Message) *v1.Message {
 		switch content.Type {
 		case "thinking":
 			message.Contents = append(message.Contents, &v1.Content{
-				Signature: content.Signature,
-				Phase:     v1.ContentPhase_CONTENT_PHASE_REASONING,
-				Content:   v1.NewTextContent(content.Thinking),
+				Signature:  content.Signature,
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+				Content:    v1.NewTextContent(content.Thinking),
 			})
 		case "redacted_thinking":
 			message.Contents = append(message.Contents, &v1.Content{
-				Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-				Content: &v1.Content_Opaque{Opaque: content.Data},
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+				Content:    &v1.Content_Opaque{Opaque: content.Data},
 			})
 		case "text":
 			message.Contents = append(message.Contents, &v1.Content{
@@ -348,7 +418,7 @@ func (c *anthropicChatStreamClient) convertStreamEventFromAnthropic(event *anthr
 			}
 			return events
 		case "thinking":
-			events := []*entity.ChatEvent{c.newChatEvent(v1.NewContentStartTextEvent(index, v1.ContentPhase_CONTENT_PHASE_REASONING))}
+			events := []*entity.ChatEvent{c.newChatEvent(v1.NewContentStartTextEvent(index, v1.ContentPhase_CONTENT_PHASE_REASONING).WithProvenance(v1.Provenance_PROVENANCE_ANTHROPIC))}
 			if event.ContentBlock.Thinking != "" {
 				events = append(events, c.newChatEvent(v1.NewContentDeltaTextEvent(index, event.ContentBlock.Thinking)))
 			}
@@ -362,9 +432,10 @@ func (c *anthropicChatStreamClient) convertStreamEventFromAnthropic(event *anthr
 			}
 			c.pendingSnapshotStops[index] = struct{}{}
 			return []*entity.ChatEvent{c.newChatEvent(v1.NewContentSnapshotEvent(&v1.Content{
-				Index:   &index,
-				Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-				Content: &v1.Content_Opaque{Opaque: event.ContentBlock.Data},
+				Index:      &index,
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+				Content:    &v1.Content_Opaque{Opaque: event.ContentBlock.Data},
 			}))}
 		case "tool_use":
 			return []*entity.ChatEvent{c.newChatEvent(v1.NewContentStartToolUseEvent(index, event.ContentBlock.ID, event.ContentBlock.Name))}
@@ -407,11 +478,13 @@ func (c *anthropicChatStreamClient) convertStreamEventFromAnthropic(event *anthr
 			chatEvent = c.newChatEvent(nil)
 		}
 		if hasUsage {
-			cachedInput := uint32(max(event.Usage.CacheReadInputTokens+event.Usage.CacheCreationInputTokens, 0))
+			cacheRead := uint32(max(event.Usage.CacheReadInputTokens, 0))
+			cacheWrite := uint32(max(event.Usage.CacheCreationInputTokens, 0))
 			chatEvent.Usage = &v1.Usage{
-				InputTokens:       uint32(max(event.Usage.InputTokens, 0)) + cachedInput,
-				OutputTokens:      uint32(max(event.Usage.OutputTokens, 0)),
-				CachedInputTokens: cachedInput,
+				InputTokens:           uint32(max(event.Usage.InputTokens, 0)) + cacheRead + cacheWrite,
+				OutputTokens:          uint32(max(event.Usage.OutputTokens, 0)),
+				CachedInputTokens:     cacheRead,
+				CacheWriteInputTokens: cacheWrite,
 			}
 		}
 		return []*entity.ChatEvent{chatEvent}
@@ -450,12 +523,14 @@ func convertStatisticsFromAnthropic(usage *anthropic.Usage) *v1.Statistics {
 		return nil
 	}
 
-	cachedInput := uint32(max(usage.CacheReadInputTokens+usage.CacheCreationInputTokens, 0))
+	cacheRead := uint32(max(usage.CacheReadInputTokens, 0))
+	cacheWrite := uint32(max(usage.CacheCreationInputTokens, 0))
 	return &v1.Statistics{
 		Usage: &v1.Usage{
-			InputTokens:       uint32(max(usage.InputTokens, 0)) + cachedInput,
-			OutputTokens:      uint32(max(usage.OutputTokens, 0)),
-			CachedInputTokens: cachedInput,
+			InputTokens:           uint32(max(usage.InputTokens, 0)) + cacheRead + cacheWrite,
+			OutputTokens:          uint32(max(usage.OutputTokens, 0)),
+			CachedInputTokens:     cacheRead,
+			CacheWriteInputTokens: cacheWrite,
 		},
 	}
 }
@@ -476,3 +551,27 @@ func convertReasoningEffortToAnthropic(effort v1.ReasoningEffort) anthropic.Outp
 		return ""
 	}
 }
+
+// convertModelInfoFromAnthropic converts a listed model. Capabilities are
+// reported only if the API returned them, as proxies of the API may not.
+func convertModelInfoFromAnthropic(m *anthropic.ModelInfo) *entity.ModelSpec {
+	spec := &entity.ModelSpec{
+		Id:            m.ID,
+		Name:          m.DisplayName,
+		Owner:         "anthropic",
+		ContextLength: uint32(max(m.MaxInputTokens, 0)),
+	}
+	if !m.JSON.Capabilities.Valid() {
+		return spec
+	}
+
+	spec.Modalities = []v1.Modality{v1.Modality_MODALITY_TEXT}
+	if m.Capabilities.ImageInput.Supported {
+		spec.Modalities = append(spec.Modalities, v1.Modality_MODALITY_IMAGE)
+	}
+	spec.Capabilities = []v1.Capability{v1.Capability_CAPABILITY_CHAT, v1.Capability_CAPABILITY_TOOL_USE}
+	if m.Capabilities.StructuredOutputs.Supported {
+		spec.Capabilities = append(spec.Capabilities, v1.Capability_CAPABILITY_STRUCTURED_OUTPUT)
+	}
+	return spec
+}
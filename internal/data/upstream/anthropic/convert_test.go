@@ -391,6 +391,45 @@ func TestConvertMessageToAnthropic(t *testing.T) {
 			})
 		})
 
+		Convey("When converting a message with reasoning from other providers", func() {
+			msg := &v1.Message{
+				Role: v1.Role_ROLE_MODEL,
+				Contents: []*v1.Content{
+					{
+						Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Signature:  "anthropic-signature",
+						Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+						Content:    v1.NewTextContent("own thinking"),
+					},
+					{
+						Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Signature:  "Z29vZ2xlLXNpZ25hdHVyZQ==",
+						Provenance: v1.Provenance_PROVENANCE_GOOGLE,
+						Content:    v1.NewTextContent("foreign thinking"),
+					},
+					{
+						Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Content: v1.NewTextContent("unsigned thinking"),
+					},
+					{
+						Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Provenance: v1.Provenance_PROVENANCE_OPENAI,
+						Content:    &v1.Content_Opaque{Opaque: "encrypted-reasoning"},
+					},
+					{Content: v1.NewTextContent("answer")},
+				},
+			}
+			result := repo.convertMessageToAnthropic(msg)
+
+			Convey("Then only verifiable thinking should be replayed", func() {
+				So(result.Content, ShouldHaveLength, 2)
+				So(result.Content[0].OfThinking, ShouldNotBeNil)
+				So(result.Content[0].OfThinking.Thinking, ShouldEqual, "own thinking")
+				So(result.Content[0].OfThinking.Signature, ShouldEqual, "anthropic-signature")
+				So(result.Content[1].OfText.Text, ShouldEqual, "answer")
+			})
+		})
+
 		Convey("When converting a message with image content", func() {
 			msg := &v1.Message{
 				Role: v1.Role_ROLE_USER,
@@ -706,6 +745,82 @@ func TestConvertRequestToAnthropic(t *testing.T) {
 	})
 }
 
+func TestConvertToolChoiceToAnthropic(t *testing.T) {
+	Convey("Given a chat request with a tool choice", t, func() {
+		repo := &upstream{config: &conf.AnthropicConfig{}, log: slog.Default()}
+		tools := []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_weather"}}}}
+		marshal := func(toolChoice *v1.ToolChoice, tools []*v1.Tool) string {
+			body, err := json.Marshal(repo.convertRequestToAnthropic(&entity.ChatRequest{
+				Model:      "claude-3",
+				Tools:      tools,
+				ToolChoice: toolChoice,
+			}))
+			So(err, ShouldBeNil)
+			return string(body)
+		}
+
+		Convey("When a specific tool is forced without parallel calls", func() {
+			body := marshal(&v1.ToolChoice{
+				Mode:              v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL,
+				Name:              "get_weather",
+				ParallelToolCalls: new(false),
+			}, tools)
+
+			Convey("Then parallel tool use is disabled on the tool choice", func() {
+				So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "tool")
+				So(gjson.Get(body, "tool_choice.name").String(), ShouldEqual, "get_weather")
+				So(gjson.Get(body, "tool_choice.disable_parallel_tool_use").Bool(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a call is required", func() {
+			body := marshal(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED}, tools)
+
+			Convey("Then any tool is required", func() {
+				So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "any")
+				So(gjson.Get(body, "tool_choice.disable_parallel_tool_use").Exists(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When only parallel calls are restricted", func() {
+			body := marshal(&v1.ToolChoice{ParallelToolCalls: new(false)}, tools)
+
+			Convey("Then the choice is auto with parallel tool use disabled", func() {
+				So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "auto")
+				So(gjson.Get(body, "tool_choice.disable_parallel_tool_use").Bool(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When tools are disabled", func() {
+			body := marshal(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE}, tools)
+
+			Convey("Then the choice is none", func() {
+				So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "none")
+			})
+		})
+
+		Convey("When the tools are restricted", func() {
+			body := marshal(&v1.ToolChoice{
+				Mode:         v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO,
+				AllowedNames: []string{"get_time"},
+			}, append(tools, &v1.Tool{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_time"}}}))
+
+			Convey("Then only the allowed tools are sent", func() {
+				So(gjson.Get(body, "tools.#.name").Raw, ShouldEqual, `["get_time"]`)
+				So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "auto")
+			})
+		})
+
+		Convey("When the request has no tools", func() {
+			body := marshal(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO}, nil)
+
+			Convey("Then no tool choice is sent", func() {
+				So(gjson.Get(body, "tool_choice").Exists(), ShouldBeFalse)
+			})
+		})
+	})
+}
+
 func TestConvertMessageFromAnthropic(t *testing.T) {
 	Convey("Given an anthropic message with content blocks", t, func() {
 		anthropicMessage := &anthropic.Message{
@@ -955,3 +1070,64 @@ func TestConvertStreamEventFromAnthropic(t *testing.T) {
 		})
 	})
 }
+
+func TestConvertModelInfoFromAnthropic(t *testing.T) {
+	Convey("Given a model listed by Anthropic", t, func() {
+		Convey("When it reports its capabilities", func() {
+			var m anthropic.ModelInfo
+			So(json.Unmarshal([]byte(`{
+				"id": "claude-sonnet-4-5",
+				"display_name": "Claude Sonnet 4.5",
+				"type": "model",
+				"created_at": "2025-09-29T00:00:00Z",
+				"max_input_tokens": 200000,
+				"capabilities": {
+					"image_input": {"supported": true},
+					"structured_outputs": {"supported": true}
+				}
+			}`), &m), ShouldBeNil)
+
+			spec := convertModelInfoFromAnthropic(&m)
+
+			So(spec.Id, ShouldEqual, "claude-sonnet-4-5")
+			So(spec.Name, ShouldEqual, "Claude Sonnet 4.5")
+			So(spec.Owner, ShouldEqual, "anthropic")
+			So(spec.ContextLength, ShouldEqual, 200000)
+			So(spec.Modalities, ShouldResemble, []v1.Modality{v1.Modality_MODALITY_TEXT, v1.Modality_MODALITY_IMAGE})
+			So(spec.Capabilities, ShouldResemble, []v1.Capability{
+				v1.Capability_CAPABILITY_CHAT,
+				v1.Capability_CAPABILITY_TOOL_USE,
+				v1.Capability_CAPABILITY_STRUCTURED_OUTPUT,
+			})
+		})
+
+		Convey("When it does not report its capabilities", func() {
+			var m anthropic.ModelInfo
+			So(json.Unmarshal([]byte(`{"id": "claude-3-haiku-20240307", "display_name": "Claude Haiku 3", "type": "model"}`), &m), ShouldBeNil)
+
+			spec := convertModelInfoFromAnthropic(&m)
+
+			So(spec.Id, ShouldEqual, "claude-3-haiku-20240307")
+			So(spec.Modalities, ShouldBeEmpty)
+			So(spec.Capabilities, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestConvertStatisticsFromAnthropic(t *testing.T) {
+	Convey("Given Anthropic usage with prompt caching", t, func() {
+		stats := convertStatisticsFromAnthropic(&anthropic.Usage{
+			InputTokens:              50,
+			OutputTokens:             300,
+			CacheReadInputTokens:     800,
+			CacheCreationInputTokens: 150,
+		})
+
+		Convey("Then cache reads and writes should be reported separately", func() {
+			So(stats.GetUsage().GetInputTokens(), ShouldEqual, 1000)
+			So(stats.GetUsage().GetOutputTokens(), ShouldEqual, 300)
+			So(stats.GetUsage().GetCachedInputTokens(), ShouldEqual, 800)
+			So(stats.GetUsage().GetCacheWriteInputTokens(), ShouldEqual, 150)
+		})
+	})
+}
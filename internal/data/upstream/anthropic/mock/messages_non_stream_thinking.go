@@ -73,14 +73,16 @@ var NonStreamThinking = &Fixture{
 			Role: v1.Role_ROLE_MODEL,
 			Contents: []*v1.Content{
 				{
-					Phase:     v1.ContentPhase_CONTENT_PHASE_REASONING,
-					Signature: nonStreamThinkingSignature,
-					Content:   v1.NewTextContent(nonStreamThinkingText),
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Signature:  nonStreamThinkingSignature,
+					Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+					Content:    v1.NewTextContent(nonStreamThinkingText),
 				},
 				{Content: v1.NewTextContent(nonStreamThinkingOutput)},
 				{
-					Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-					Content: &v1.Content_Opaque{Opaque: nonStreamThinkingRedacted},
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+					Content:    &v1.Content_Opaque{Opaque: nonStreamThinkingRedacted},
 				},
 			},
 		},
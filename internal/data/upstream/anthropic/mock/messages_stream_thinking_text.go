@@ -34,7 +34,7 @@ func streamThinkingTextChatEvents() []*v1.ChatEvent {
 	id := eventBuilder("stream_thinking_text")
 	return []*v1.ChatEvent{
 		id.of(v1.NewMessageStartEvent("gen-1782640358-8xpFd0HzF6SZnlURyaC9", "anthropic/claude-4.6-sonnet-20260217")),
-		id.of(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+		id.of(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING).WithProvenance(v1.Provenance_PROVENANCE_ANTHROPIC)),
 		id.of(v1.NewContentDeltaTextEvent(0, "Let me calculate")),
 		id.of(v1.NewContentDeltaTextEvent(0, " the expected latency for each upstream with one retry on failure.\n\nFor a single attempt:\n- Success on")),
 		id.of(v1.NewContentDeltaTextEvent(0, " first try: latency = L\n- Failure on first try, then retry:\n  - Success on retry: latency = L + L")),
@@ -63,9 +63,10 @@ func streamThinkingTextChatEvents() []*v1.ChatEvent {
 		id.of(v1.NewContentDeltaTextEvent(1, "210 ms when accounting for one retry on failure.")),
 		id.of(v1.NewContentStopEvent(1)),
 		id.of(v1.NewContentSnapshotEvent(&v1.Content{
-			Index:   new(uint32(2)),
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: &v1.Content_Opaque{Opaque: streamThinkingTextRedacted},
+			Index:      new(uint32(2)),
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+			Content:    &v1.Content_Opaque{Opaque: streamThinkingTextRedacted},
 		})),
 		id.withUsage(
 			v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_COMPLETED),
@@ -35,7 +35,7 @@ func streamThinkingToolCallChatEvents() []*v1.ChatEvent {
 	id := eventBuilder("stream_thinking_tool_call")
 	return []*v1.ChatEvent{
 		id.of(v1.NewMessageStartEvent("gen-1782639618-FBlJV7iR8SzgzHP8zhu2", "anthropic/claude-4.6-sonnet-20260217")),
-		id.of(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+		id.of(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING).WithProvenance(v1.Provenance_PROVENANCE_ANTHROPIC)),
 		id.of(v1.NewContentDeltaTextEvent(0, "The")),
 		id.of(v1.NewContentDeltaTextEvent(0, " user wants me to choose the best provider based on wait_ms and error_rate metrics:\n\n- OpenAI: wait_ms=1800, error_rate=")),
 		id.of(v1.NewContentDeltaTextEvent(0, "0.12\n- Anthropic: wait_ms=120, error_rate=0.01\n- Gemini: wait_ms=400, error_rate=0.04\n\nAnthropic clearly")),
@@ -58,9 +58,10 @@ func streamThinkingToolCallChatEvents() []*v1.ChatEvent {
 		id.of(v1.NewContentDeltaToolInputTextEvent(2, `"}`)),
 		id.of(v1.NewContentStopEvent(2)),
 		id.of(v1.NewContentSnapshotEvent(&v1.Content{
-			Index:   new(uint32(3)),
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: &v1.Content_Opaque{Opaque: streamThinkingToolCallRedacted},
+			Index:      new(uint32(3)),
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+			Content:    &v1.Content_Opaque{Opaque: streamThinkingToolCallRedacted},
 		})),
 		id.withUsage(
 			v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE),
@@ -173,3 +173,42 @@ func TestChatStream(t *testing.T) {
 		})
 	})
 }
+
+func TestCountTokens(t *testing.T) {
+	Convey("Given an anthropic upstream", t, func() {
+		mockClient := &mockHTTPClient{}
+		repo, err := newAnthropicUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		Convey("When CountTokens runs the tool call fixture", func() {
+			var capturedBody []byte
+			mockClient.DoFunc = func(httpReq *http.Request) (*http.Response, error) {
+				So(httpReq.URL.String(), ShouldEqual, "https://api.anthropic.com/v1/messages/count_tokens")
+
+				body, err := io.ReadAll(httpReq.Body)
+				So(err, ShouldBeNil)
+				capturedBody = body
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"input_tokens": 128}`))),
+				}, nil
+			}
+
+			tokens, err := repo.(*upstream).CountTokens(context.Background(), mock.NonStreamToolCall.ChatRequest)
+			So(err, ShouldBeNil)
+
+			Convey("Then the request keeps the counted fields of the message request", func() {
+				So(tokens, ShouldEqual, 128)
+
+				expected := jsonMap(mock.NonStreamToolCall.Request)
+				actual := jsonMap(capturedBody)
+				So(actual["messages"], ShouldResemble, expected["messages"])
+				So(actual["tools"], ShouldResemble, expected["tools"])
+				So(actual["system"], ShouldResemble, expected["system"])
+				So(actual, ShouldNotContainKey, "max_tokens")
+			})
+		})
+	})
+}
@@ -16,27 +16,23 @@ package google
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"strings"
 
 	"google.golang.org/genai"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/entity"
-	"github.com/neuraxes/neurouter/internal/util"
+	"github.com/neuraxes/neurouter/internal/util/googleconv"
 )
 
-// Gemini requires the function name on every function response, but a ToolResult
-// carries only the id of the call it answers. The name is therefore packed into
-// that id, ahead of the call id Gemini assigns when it issues parallel calls. A
-// function name cannot contain a colon, so the first one separates the two.
-const googleToolUseIDSeparator = ":"
-
 func (r *upstream) convertRequestToGoogle(req *entity.ChatRequest) (messages []*genai.Content, config *genai.GenerateContentConfig) {
 	config = &genai.GenerateContentConfig{
 		SystemInstruction: r.convertSystemInstructionToGoogle(req.Messages),
 		Tools:             convertToolsToGoogle(req.Tools),
 	}
+	if len(config.Tools) > 0 {
+		config.ToolConfig = googleconv.ToolChoiceTo(req.ToolChoice)
+	}
 	convertGenerationConfigToGoogle(req.Config, config)
 
 	for _, msg := range req.Messages {
@@ -72,7 +68,7 @@ func convertGenerationConfigToGoogle(config *v1.GenerationConfig, googleConfig *
 		} else {
 			googleConfig.ThinkingConfig.IncludeThoughts = true
 			if c.Effort > v1.ReasoningEffort_REASONING_EFFORT_NONE {
-				googleConfig.ThinkingConfig.ThinkingLevel = convertReasoningEffortToGoogleThinkingLevel(c.Effort)
+				googleConfig.ThinkingConfig.ThinkingLevel = googleconv.ReasoningEffortTo(c.Effort)
 			}
 			if c.TokenBudget != 0 {
 				googleConfig.ThinkingConfig.ThinkingBudget = new(int32(c.TokenBudget))
@@ -82,6 +78,18 @@ func convertGenerationConfigToGoogle(config *v1.GenerationConfig, googleConfig *
 	if len(config.StopSequences) > 0 {
 		googleConfig.StopSequences = config.StopSequences
 	}
+
+	switch g := config.Grammar.(type) {
+	case *v1.GenerationConfig_PresetGrammar:
+		if g.PresetGrammar == "json_object" {
+			googleConfig.ResponseMIMEType = "application/json"
+		}
+	case *v1.GenerationConfig_Schema:
+		if g.Schema != nil {
+			googleConfig.ResponseMIMEType = "application/json"
+			googleConfig.ResponseJsonSchema = convertSchemaToGoogle(g.Schema.AsMap())
+		}
+	}
 }
 
 func convertToolsToGoogle(tools []*v1.Tool) []*genai.Tool {
@@ -131,7 +139,7 @@ func convertMessageToGoogleContent(msg *v1.Message) *genai.Content {
 	var parts []*genai.Part
 	for _, content := range msg.Contents {
 		if part := convertContentToGooglePart(content); part != nil {
-			if content.Signature != "" {
+			if content.Signature != "" && content.VerifiableBy(v1.Provenance_PROVENANCE_GOOGLE) {
 				sig, err := base64.StdEncoding.DecodeString(content.Signature)
 				if err == nil {
 					part.ThoughtSignature = sig
@@ -157,26 +165,11 @@ func convertMessageToGoogleContent(msg *v1.Message) *genai.Content {
 	}
 }
 
-// toolUseIDFromGoogle builds the handle a client uses to answer a function call.
-func toolUseIDFromGoogle(call *genai.FunctionCall) string {
-	if call.ID == "" {
-		return call.Name
-	}
-	return call.Name + googleToolUseIDSeparator + call.ID
-}
-
-// splitToolUseID unpacks what toolUseIDFromGoogle built. An id from another
-// provider has no separator and is read as a bare function name, which is the
-// best guess available for a conversation replayed across providers.
-func splitToolUseID(toolUseID string) (name, id string) {
-	name, id, _ = strings.Cut(toolUseID, googleToolUseIDSeparator)
-	return
-}
-
 func convertContentToGooglePart(content *v1.Content) *genai.Part {
 	// Thought summaries are not replayed, except when one carries a thought
 	// signature that Gemini needs to restore thinking context across turns.
-	if content.IsReasoning() && content.Signature == "" {
+	// Reasoning signed by other providers cannot be verified and is dropped.
+	if content.IsReasoning() && (content.Signature == "" || !content.VerifiableBy(v1.Provenance_PROVENANCE_GOOGLE)) {
 		return nil
 	}
 
@@ -185,27 +178,12 @@ func convertContentToGooglePart(content *v1.Content) *genai.Part {
 	case *v1.Content_Text:
 		part = genai.NewPartFromText(c.Text.GetText())
 	case *v1.Content_Image:
-		part = convertImageToGooglePart(c.Image)
+		part = googleconv.ImageToPart(c.Image)
 	case *v1.Content_ToolUse:
-		textualInput := c.ToolUse.GetTextualInput()
-
-		var args map[string]any
-		if textualInput != "" {
-			if err := json.Unmarshal([]byte(textualInput), &args); err != nil {
-				args = map[string]any{
-					"args": textualInput,
-				}
-			}
-		}
-
-		_, id := splitToolUseID(c.ToolUse.Id)
-		part = &genai.Part{FunctionCall: &genai.FunctionCall{
-			ID:   id,
-			Name: c.ToolUse.Name,
-			Args: args,
-		}}
+		_, id := googleconv.SplitToolUseID(c.ToolUse.Id)
+		part = &genai.Part{FunctionCall: googleconv.ToolUseTo(id, c.ToolUse.Name, c.ToolUse.GetTextualInput())}
 	case *v1.Content_ToolResult:
-		part = convertToolResultToGooglePart(c.ToolResult)
+		part = &genai.Part{FunctionResponse: googleconv.ToolResultTo(c.ToolResult)}
 	default:
 		return nil
 	}
@@ -216,114 +194,19 @@ func convertContentToGooglePart(content *v1.Content) *genai.Part {
 	return part
 }
 
-func convertImageToGooglePart(image *v1.Image) *genai.Part {
-	mimeType := image.MimeType
-	switch source := image.Source.(type) {
-	case *v1.Image_Url:
-		return genai.NewPartFromURI(source.Url, mimeType)
-	case *v1.Image_Data:
-		if mimeType == "" {
-			mimeType = util.InferImageMimeType(source.Data)
-		}
-		return genai.NewPartFromBytes(source.Data, mimeType)
-	case *v1.Image_Base64:
-		data, err := base64.StdEncoding.DecodeString(source.Base64)
-		if err != nil {
-			return nil
-		}
-		if mimeType == "" {
-			mimeType = util.InferImageMimeType(data)
-		}
-		return genai.NewPartFromBytes(data, mimeType)
-	default:
-		return nil
-	}
-}
-
-func convertToolResultToGooglePart(result *v1.ToolResult) *genai.Part {
-	var textParts strings.Builder
-	var mediaParts []*genai.FunctionResponsePart
-	for _, out := range result.GetOutputs() {
-		switch o := out.Output.(type) {
-		case *v1.ToolResult_Output_Text:
-			textParts.WriteString(o.Text)
-		case *v1.ToolResult_Output_Image:
-			if p := convertImageToGooglePart(o.Image); p != nil && p.InlineData != nil {
-				mediaParts = append(mediaParts, &genai.FunctionResponsePart{
-					InlineData: &genai.FunctionResponseBlob{
-						MIMEType: p.InlineData.MIMEType,
-						Data:     p.InlineData.Data,
-					},
-				})
-			}
-		}
-	}
-
-	var response map[string]any
-	if textualOutput := textParts.String(); textualOutput != "" {
-		if err := json.Unmarshal([]byte(textualOutput), &response); err != nil {
-			response = map[string]any{
-				"result": textualOutput,
-			}
-		}
-	}
-
-	name, id := splitToolUseID(result.Id)
-	return &genai.Part{FunctionResponse: &genai.FunctionResponse{
-		ID:       id,
-		Name:     name,
-		Response: response,
-		Parts:    mediaParts,
-	}}
-}
-
 func convertMessageFromGoogleContent(content *genai.Content) *v1.Message {
 	message := &v1.Message{
 		Role: v1.Role_ROLE_MODEL,
 	}
 
 	for _, part := range content.Parts {
-		var content *v1.Content
-
-		if part.Text != "" {
-			phase := v1.ContentPhase_CONTENT_PHASE_NORMAL
-			if part.Thought {
-				phase = v1.ContentPhase_CONTENT_PHASE_REASONING
-			}
-			content = &v1.Content{
-				Phase:   phase,
-				Content: v1.NewTextContent(part.Text),
-			}
-		} else if part.FunctionCall != nil {
-			args, err := json.Marshal(part.FunctionCall.Args)
-			if err != nil {
-				continue
-			}
-			content = &v1.Content{
-				Content: &v1.Content_ToolUse{
-					ToolUse: &v1.ToolUse{
-						Id:   toolUseIDFromGoogle(part.FunctionCall),
-						Name: part.FunctionCall.Name,
-						Inputs: []*v1.ToolUse_Input{
-							{
-								Input: &v1.ToolUse_Input_Text{
-									Text: string(args),
-								},
-							},
-						},
-					},
-				},
-			}
-		}
-
+		content := googleconv.PartFrom(part)
 		if content == nil {
 			continue
 		}
 
-		if len(part.ThoughtSignature) > 0 {
-			content.Signature = base64.StdEncoding.EncodeToString(part.ThoughtSignature)
-		}
-
+		// Gemini may sign any part, so every content is attributed to it.
+		content.Provenance = v1.Provenance_PROVENANCE_GOOGLE
 		message.Contents = append(message.Contents, content)
 	}
 
@@ -354,15 +237,17 @@ func (c *googleChatStreamClient) convertStreamResponseFromGoogle(resp *genai.Gen
 			}
 
 		case part.FunctionCall != nil:
-			args, err := json.Marshal(part.FunctionCall.Args)
+			toolUse, err := googleconv.ToolUseFrom(part.FunctionCall)
 			if err != nil {
 				continue
 			}
 			c.closeOpenBlock(&events)
 			index := c.nextIndex
 			c.nextIndex++
-			events = append(events, c.newChatEvent(v1.NewContentStartToolUseEvent(index, toolUseIDFromGoogle(part.FunctionCall), part.FunctionCall.Name)))
-			events = append(events, c.newChatEvent(v1.NewContentDeltaToolInputTextEvent(index, string(args))))
+			start := v1.NewContentStartToolUseEvent(index, toolUse.Id, toolUse.Name).
+				WithProvenance(v1.Provenance_PROVENANCE_GOOGLE)
+			events = append(events, c.newChatEvent(start))
+			events = append(events, c.newChatEvent(v1.NewContentDeltaToolInputTextEvent(index, toolUse.GetTextualInput())))
 			if len(part.ThoughtSignature) > 0 {
 				events = append(events, c.newChatEvent(v1.NewContentDeltaSignatureEvent(index, base64.StdEncoding.EncodeToString(part.ThoughtSignature))))
 			}
@@ -376,7 +261,7 @@ func (c *googleChatStreamClient) convertStreamResponseFromGoogle(resp *genai.Gen
 
 	if candidate.FinishReason != "" {
 		c.closeOpenBlock(&events)
-		stop := c.newChatEvent(v1.NewMessageStopEvent(convertStatusFromGoogle(candidate.FinishReason, candidate.Content)))
+		stop := c.newChatEvent(v1.NewMessageStopEvent(googleconv.StatusFrom(candidate.FinishReason, candidate.Content)))
 		stop.Usage = c.lastUsage
 		events = append(events, stop)
 	}
@@ -408,63 +293,34 @@ func (c *googleChatStreamClient) openTextBlock(events *[]*entity.ChatEvent, phas
 	c.hasOpen = true
 	c.openPhase = phase
 	c.openIndex = index
-	*events = append(*events, c.newChatEvent(v1.NewContentStartTextEvent(index, phase)))
+	*events = append(*events, c.newChatEvent(v1.NewContentStartTextEvent(index, phase).WithProvenance(v1.Provenance_PROVENANCE_GOOGLE)))
 	return index
 }
 
-func convertStatusFromGoogle(reason genai.FinishReason, content *genai.Content) v1.ChatStatus {
-	// Google returns Stop for completed turns and tool calls, so inspect content first.
-	if reason == genai.FinishReasonStop && content != nil {
-		for _, part := range content.Parts {
-			if part.FunctionCall != nil {
-				return v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
-			}
-		}
-	}
-
-	switch reason {
-	case genai.FinishReasonStop:
-		return v1.ChatStatus_CHAT_STATUS_COMPLETED
-	case genai.FinishReasonMaxTokens:
-		return v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT
-	case genai.FinishReasonSafety,
-		genai.FinishReasonBlocklist,
-		genai.FinishReasonProhibitedContent,
-		genai.FinishReasonSPII,
-		genai.FinishReasonImageSafety,
-		genai.FinishReasonImageProhibitedContent:
-		return v1.ChatStatus_CHAT_STATUS_REFUSED
-	default:
-		return v1.ChatStatus_CHAT_STATUS_IN_PROGRESS
-	}
-}
-
 func convertStatisticsFromGoogle(usage *genai.GenerateContentResponseUsageMetadata) *v1.Statistics {
-	if usage == nil {
-		return nil
-	}
-
-	return &v1.Statistics{
-		Usage: &v1.Usage{
-			InputTokens:       uint32(usage.PromptTokenCount),
-			OutputTokens:      uint32(usage.CandidatesTokenCount + usage.ThoughtsTokenCount),
-			CachedInputTokens: uint32(usage.CachedContentTokenCount),
-			ReasoningTokens:   uint32(usage.ThoughtsTokenCount),
-		},
+	if u := googleconv.UsageFrom(usage); u != nil {
+		return &v1.Statistics{Usage: u}
 	}
+	return nil
 }
 
-func convertReasoningEffortToGoogleThinkingLevel(effort v1.ReasoningEffort) genai.ThinkingLevel {
-	switch effort {
-	case v1.ReasoningEffort_REASONING_EFFORT_MINIMAL:
-		return genai.ThinkingLevelMinimal
-	case v1.ReasoningEffort_REASONING_EFFORT_LOW:
-		return genai.ThinkingLevelLow
-	case v1.ReasoningEffort_REASONING_EFFORT_MEDIUM:
-		return genai.ThinkingLevelMedium
-	case v1.ReasoningEffort_REASONING_EFFORT_HIGH, v1.ReasoningEffort_REASONING_EFFORT_EXTRA_HIGH, v1.ReasoningEffort_REASONING_EFFORT_MAX:
-		return genai.ThinkingLevelHigh
-	default:
-		return genai.ThinkingLevelUnspecified
+// convertModelFromGoogle converts a listed model, deriving its capabilities
+// from the methods it supports.
+func convertModelFromGoogle(m *genai.Model) *entity.ModelSpec {
+	spec := &entity.ModelSpec{
+		Id:            strings.TrimPrefix(m.Name, "models/"),
+		Name:          m.DisplayName,
+		Owner:         "google",
+		ContextLength: uint32(max(m.InputTokenLimit, 0)),
+	}
+	for _, action := range m.SupportedActions {
+		switch action {
+		case "generateContent":
+			// Every model generating content supports function calling
+			spec.Capabilities = append(spec.Capabilities, v1.Capability_CAPABILITY_CHAT, v1.Capability_CAPABILITY_TOOL_USE)
+		case "embedContent":
+			spec.Capabilities = append(spec.Capabilities, v1.Capability_CAPABILITY_EMBEDDING)
+		}
 	}
+	return spec
 }
@@ -114,6 +114,87 @@ func TestConvertRequestToGoogle(t *testing.T) {
 	})
 }
 
+func TestConvertGenerationConfigToGoogle(t *testing.T) {
+	Convey("Test structured output in convertGenerationConfigToGoogle", t, func() {
+		Convey("should request JSON for the json_object grammar", func() {
+			config := &genai.GenerateContentConfig{}
+			convertGenerationConfigToGoogle(&v1.GenerationConfig{
+				Grammar: &v1.GenerationConfig_PresetGrammar{PresetGrammar: "json_object"},
+			}, config)
+			So(config.ResponseMIMEType, ShouldEqual, "application/json")
+			So(config.ResponseJsonSchema, ShouldBeNil)
+		})
+
+		Convey("should translate a schema grammar into a response JSON schema", func() {
+			config := &genai.GenerateContentConfig{}
+			convertGenerationConfigToGoogle(&v1.GenerationConfig{
+				Grammar: &v1.GenerationConfig_Schema{Schema: util.MustStructFromMap(map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"answer": map[string]any{"const": "yes"}},
+					"required":   []any{"answer"},
+				})},
+			}, config)
+			So(config.ResponseMIMEType, ShouldEqual, "application/json")
+			So(config.ResponseSchema, ShouldBeNil)
+			So(config.ResponseJsonSchema, ShouldResemble, map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"answer": map[string]any{"enum": []any{"yes"}}},
+				"required":   []any{"answer"},
+			})
+		})
+
+		Convey("should keep free text for other grammars", func() {
+			config := &genai.GenerateContentConfig{}
+			convertGenerationConfigToGoogle(&v1.GenerationConfig{
+				Grammar: &v1.GenerationConfig_PresetGrammar{PresetGrammar: "text"},
+			}, config)
+			So(config.ResponseMIMEType, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestConvertToolChoiceToGoogle(t *testing.T) {
+	Convey("Test convertToolChoiceToGoogle", t, func() {
+		r := &upstream{config: &conf.GoogleConfig{}, log: slog.Default()}
+		tools := []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_weather"}}}}
+		convert := func(toolChoice *v1.ToolChoice, tools []*v1.Tool) *genai.ToolConfig {
+			_, config := r.convertRequestToGoogle(&entity.ChatRequest{Tools: tools, ToolChoice: toolChoice})
+			return config.ToolConfig
+		}
+
+		Convey("should force a specific function by allowing only that one", func() {
+			config := convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL, Name: "get_weather"}, tools)
+			So(config.FunctionCallingConfig.Mode, ShouldEqual, genai.FunctionCallingConfigModeAny)
+			So(config.FunctionCallingConfig.AllowedFunctionNames, ShouldResemble, []string{"get_weather"})
+		})
+
+		Convey("should convert the other modes", func() {
+			So(convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED}, tools).FunctionCallingConfig.Mode,
+				ShouldEqual, genai.FunctionCallingConfigModeAny)
+			So(convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO}, tools).FunctionCallingConfig.Mode,
+				ShouldEqual, genai.FunctionCallingConfigModeAuto)
+			So(convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE}, tools).FunctionCallingConfig.Mode,
+				ShouldEqual, genai.FunctionCallingConfigModeNone)
+		})
+
+		Convey("should restrict the allowed functions", func() {
+			names := []string{"get_weather", "get_time"}
+			config := convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED, AllowedNames: names}, tools)
+			So(config.FunctionCallingConfig.Mode, ShouldEqual, genai.FunctionCallingConfigModeAny)
+			So(config.FunctionCallingConfig.AllowedFunctionNames, ShouldResemble, names)
+
+			config = convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO, AllowedNames: names}, tools)
+			So(config.FunctionCallingConfig.Mode, ShouldEqual, genai.FunctionCallingConfigModeValidated)
+			So(config.FunctionCallingConfig.AllowedFunctionNames, ShouldResemble, names)
+		})
+
+		Convey("should leave the default without a mode or tools", func() {
+			So(convert(&v1.ToolChoice{ParallelToolCalls: new(false)}, tools), ShouldBeNil)
+			So(convert(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE}, nil), ShouldBeNil)
+		})
+	})
+}
+
 func TestConvertToolsToGoogle(t *testing.T) {
 	Convey("convertToolsToGoogle should convert tools", t, func() {
 		tools := []*v1.Tool{
@@ -278,6 +359,37 @@ func TestConvertMessageToGoogleContent(t *testing.T) {
 		So(result.Parts[0].Thought, ShouldBeTrue)
 		So(string(result.Parts[0].ThoughtSignature), ShouldEqual, "sig")
 	})
+
+	Convey("convertMessageToGoogleContent should drop signatures of other providers", t, func() {
+		msg := &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{
+				{
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Signature:  "anthropic-signature",
+					Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+					Content:    v1.NewTextContent("thinking..."),
+				},
+				{
+					Signature:  base64.StdEncoding.EncodeToString([]byte("sig")),
+					Provenance: v1.Provenance_PROVENANCE_OPENAI,
+					Content:    v1.NewTextContent("answer"),
+				},
+				{
+					Signature:  base64.StdEncoding.EncodeToString([]byte("sig")),
+					Provenance: v1.Provenance_PROVENANCE_GOOGLE,
+					Content:    v1.NewTextContent("signed answer"),
+				},
+			},
+		}
+		result := convertMessageToGoogleContent(msg)
+		So(result, ShouldNotBeNil)
+		So(result.Parts, ShouldHaveLength, 2)
+		So(result.Parts[0].Text, ShouldEqual, "answer")
+		So(result.Parts[0].ThoughtSignature, ShouldBeEmpty)
+		So(result.Parts[1].Text, ShouldEqual, "signed answer")
+		So(string(result.Parts[1].ThoughtSignature), ShouldEqual, "sig")
+	})
 }
 
 func TestConvertContentToGooglePart(t *testing.T) {
@@ -611,59 +723,6 @@ func TestConvertMessageFromGoogleContent(t *testing.T) {
 	})
 }
 
-func TestConvertStatusFromGoogle(t *testing.T) {
-	Convey("Given various Google finish reasons without function calls", t, func() {
-		So(convertStatusFromGoogle(genai.FinishReasonStop, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
-		So(convertStatusFromGoogle(genai.FinishReasonMaxTokens, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT)
-		So(convertStatusFromGoogle(genai.FinishReasonSafety, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
-		So(convertStatusFromGoogle(genai.FinishReasonBlocklist, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
-		So(convertStatusFromGoogle(genai.FinishReasonProhibitedContent, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
-		So(convertStatusFromGoogle(genai.FinishReasonSPII, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
-		So(convertStatusFromGoogle(genai.FinishReasonRecitation, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
-		So(convertStatusFromGoogle(genai.FinishReasonUnspecified, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
-		So(convertStatusFromGoogle(genai.FinishReason(""), nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
-		So(convertStatusFromGoogle(genai.FinishReason("unknown"), nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
-	})
-
-	Convey("Given STOP finish reason with function call in content", t, func() {
-		content := &genai.Content{
-			Parts: []*genai.Part{
-				{
-					FunctionCall: &genai.FunctionCall{
-						Name: "get_weather",
-						Args: map[string]any{"city": "Shanghai"},
-					},
-				},
-			},
-		}
-		So(convertStatusFromGoogle(genai.FinishReasonStop, content), ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
-	})
-
-	Convey("Given STOP finish reason with text content only", t, func() {
-		content := &genai.Content{
-			Parts: []*genai.Part{
-				{Text: "Hello world"},
-			},
-		}
-		So(convertStatusFromGoogle(genai.FinishReasonStop, content), ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
-	})
-
-	Convey("Given STOP finish reason with mixed content including function call", t, func() {
-		content := &genai.Content{
-			Parts: []*genai.Part{
-				{Text: "Let me check the weather for you."},
-				{
-					FunctionCall: &genai.FunctionCall{
-						Name: "get_weather",
-						Args: map[string]any{"city": "Beijing"},
-					},
-				},
-			},
-		}
-		So(convertStatusFromGoogle(genai.FinishReasonStop, content), ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
-	})
-}
-
 func TestConvertStatisticsFromGoogle(t *testing.T) {
 	Convey("convertStatisticsFromGoogle should convert usage metadata", t, func() {
 		usage := &genai.GenerateContentResponseUsageMetadata{
@@ -705,3 +764,26 @@ func TestConvertStatisticsFromGoogle(t *testing.T) {
 		So(stats.Usage.ReasoningTokens, ShouldEqual, 0)
 	})
 }
+
+func TestConvertModelFromGoogle(t *testing.T) {
+	Convey("Given models listed by Gemini", t, func() {
+		spec := convertModelFromGoogle(&genai.Model{
+			Name:             "models/gemini-2.5-flash",
+			DisplayName:      "Gemini 2.5 Flash",
+			InputTokenLimit:  1048576,
+			SupportedActions: []string{"generateContent", "countTokens", "createCachedContent"},
+		})
+		So(spec.Id, ShouldEqual, "gemini-2.5-flash")
+		So(spec.Name, ShouldEqual, "Gemini 2.5 Flash")
+		So(spec.Owner, ShouldEqual, "google")
+		So(spec.ContextLength, ShouldEqual, 1048576)
+		So(spec.Capabilities, ShouldResemble, []v1.Capability{v1.Capability_CAPABILITY_CHAT, v1.Capability_CAPABILITY_TOOL_USE})
+
+		spec = convertModelFromGoogle(&genai.Model{
+			Name:             "models/gemini-embedding-001",
+			SupportedActions: []string{"embedContent", "countTextTokens"},
+		})
+		So(spec.Id, ShouldEqual, "gemini-embedding-001")
+		So(spec.Capabilities, ShouldResemble, []v1.Capability{v1.Capability_CAPABILITY_EMBEDDING})
+	})
+}
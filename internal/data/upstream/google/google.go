@@ -20,6 +20,7 @@ import (
 	"iter"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	otellog "go.opentelemetry.io/otel/log"
 	"google.golang.org/genai"
@@ -29,6 +30,7 @@ import (
 	"github.com/neuraxes/neurouter/internal/biz/repository"
 	"github.com/neuraxes/neurouter/internal/conf"
 	"github.com/neuraxes/neurouter/internal/data/upstream/shared"
+	"github.com/neuraxes/neurouter/internal/util/googleconv"
 )
 
 type upstream struct {
@@ -38,8 +40,8 @@ type upstream struct {
 }
 
 func NewGoogleFactory(loggerProvider otellog.LoggerProvider) repository.UpstreamFactory[conf.GoogleConfig] {
-	return func(config *conf.GoogleConfig, logger *slog.Logger) (repository.Repo, error) {
-		client := shared.NewRecordingClientFromLoggerProvider(loggerProvider, "neurouter.upstream.google")
+	return func(config *conf.GoogleConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+		client := shared.NewClient(loggerProvider, "neurouter.upstream.google", transport)
 		return newGoogleUpstreamWithClient(config, client, logger)
 	}
 }
@@ -71,6 +73,7 @@ func (r *upstream) Chat(ctx context.Context, req *entity.ChatRequest) (resp *ent
 
 	googleResp, err := r.client.Models.GenerateContent(ctx, req.Model, messages, config)
 	if err != nil {
+		err = convertError(err)
 		return
 	}
 
@@ -82,7 +85,7 @@ func (r *upstream) Chat(ctx context.Context, req *entity.ChatRequest) (resp *ent
 	resp = &entity.ChatResponse{
 		Id:         req.Id,
 		Model:      googleResp.ModelVersion,
-		Status:     convertStatusFromGoogle(googleResp.Candidates[0].FinishReason, googleResp.Candidates[0].Content),
+		Status:     googleconv.StatusFrom(googleResp.Candidates[0].FinishReason, googleResp.Candidates[0].Content),
 		Message:    convertMessageFromGoogleContent(googleResp.Candidates[0].Content),
 		Statistics: convertStatisticsFromGoogle(googleResp.UsageMetadata),
 	}
@@ -91,6 +94,23 @@ func (r *upstream) Chat(ctx context.Context, req *entity.ChatRequest) (resp *ent
 	return
 }
 
+func (r *upstream) CountTokens(ctx context.Context, req *entity.ChatRequest) (int64, error) {
+	messages, config := r.convertRequestToGoogle(req)
+
+	// The Gemini Developer API counts contents only, so the system instruction
+	// is counted as a leading user turn and tool declarations are left out
+	if config.SystemInstruction != nil {
+		system := &genai.Content{Role: genai.RoleUser, Parts: config.SystemInstruction.Parts}
+		messages = append([]*genai.Content{system}, messages...)
+	}
+
+	countResp, err := r.client.Models.CountTokens(ctx, req.Model, messages, nil)
+	if err != nil {
+		return 0, convertError(err)
+	}
+	return int64(countResp.TotalTokens), nil
+}
+
 type googleChatStreamClient struct {
 	req *entity.ChatRequest
 	it  iter.Seq2[*genai.GenerateContentResponse, error]
@@ -107,7 +127,7 @@ func (c *googleChatStreamClient) AsSeq() iter.Seq2[*entity.ChatEvent, error] {
 	return func(yield func(*entity.ChatEvent, error) bool) {
 		for googleResp, err := range c.it {
 			if err != nil {
-				yield(nil, err)
+				yield(nil, convertError(err))
 				return
 			}
 
@@ -137,22 +157,105 @@ func (r *upstream) ChatStream(ctx context.Context, req *entity.ChatRequest) iter
 	return client.AsSeq()
 }
 
+// maxEmbeddingBatchSize is the maximum number of contents of a batch
+// embedding request.
+const maxEmbeddingBatchSize = 100
+
+func (r *upstream) MaxEmbeddingBatchSize() int { return maxEmbeddingBatchSize }
+
 func (r *upstream) Embed(ctx context.Context, req *entity.EmbedRequest) (resp *entity.EmbedResponse, err error) {
-	var parts []*genai.Part
-	for _, content := range req.Contents {
-		if part := convertContentToGooglePart(content); part != nil {
-			parts = append(parts, part)
+	// Each input is embedded as a content of its own
+	contents := make([]*genai.Content, 0, len(req.Inputs))
+	for _, input := range req.Inputs {
+		var parts []*genai.Part
+		for _, content := range input.Contents {
+			if part := convertContentToGooglePart(content); part != nil {
+				parts = append(parts, part)
+			}
 		}
+		contents = append(contents, &genai.Content{Parts: parts})
+	}
+
+	config := &genai.EmbedContentConfig{}
+	if req.Dimensions != nil {
+		config.OutputDimensionality = new(int32(*req.Dimensions))
+	}
+	switch req.TaskType {
+	case v1.EmbedTaskType_EMBED_TASK_TYPE_QUERY:
+		config.TaskType = "RETRIEVAL_QUERY"
+	case v1.EmbedTaskType_EMBED_TASK_TYPE_DOCUMENT:
+		config.TaskType = "RETRIEVAL_DOCUMENT"
 	}
 
-	googleResp, err := r.client.Models.EmbedContent(ctx, req.Model, []*genai.Content{{Parts: parts}}, &genai.EmbedContentConfig{})
+	googleResp, err := r.client.Models.EmbedContent(ctx, req.Model, contents, config)
 	if err != nil {
+		err = convertError(err)
 		return
 	}
 
 	resp = &entity.EmbedResponse{
-		Id:        req.Id,
-		Embedding: googleResp.Embeddings[0].Values,
+		Id:         req.Id,
+		Model:      req.Model,
+		Embeddings: make([]*v1.EmbeddingVector, 0, len(googleResp.Embeddings)),
+	}
+	var inputTokens float32
+	for i, e := range googleResp.Embeddings {
+		resp.Embeddings = append(resp.Embeddings, &v1.EmbeddingVector{
+			Index:  uint32(i),
+			Values: e.Values,
+		})
+		if e.Statistics != nil {
+			inputTokens += e.Statistics.TokenCount
+		}
+	}
+
+	// Only Vertex AI reports token statistics, count with the tokenizer of the model otherwise
+	if inputTokens == 0 {
+		countResp, err := r.client.Models.CountTokens(ctx, req.Model, contents, nil)
+		if err != nil {
+			r.log.Warn("failed to count embedding tokens", "error", err)
+		} else {
+			inputTokens = float32(countResp.TotalTokens)
+		}
+	}
+	if inputTokens > 0 {
+		resp.Usage = &v1.Usage{InputTokens: uint32(inputTokens)}
 	}
 	return
 }
+
+func (r *upstream) ListModels(ctx context.Context) ([]*entity.ModelSpec, error) {
+	var specs []*entity.ModelSpec
+	for m, err := range r.client.Models.All(ctx) {
+		if err != nil {
+			return nil, convertError(err)
+		}
+		specs = append(specs, convertModelFromGoogle(m))
+	}
+	return specs, nil
+}
+
+// convertError marks the errors of a rejected API key.
+func convertError(err error) error {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return shared.ConvertStatusError(apiErr.Code, errorReason(apiErr), err)
+	}
+	return err
+}
+
+// errorReason returns the reason of the ErrorInfo detail of an API error.
+func errorReason(apiErr genai.APIError) string {
+	for _, d := range apiErr.Details {
+		if t, _ := d["@type"].(string); strings.HasSuffix(t, "google.rpc.ErrorInfo") {
+			reason, _ := d["reason"].(string)
+			return reason
+		}
+	}
+	return ""
+}
+
+var (
+	_ repository.TokenCountingRepo = (*upstream)(nil)
+	_ repository.ModelListingRepo  = (*upstream)(nil)
+)
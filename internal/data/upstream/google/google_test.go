@@ -121,6 +121,22 @@ func TestChat(t *testing.T) {
 			})
 		})
 
+		Convey("When the API rejects the API key", func() {
+			mockRoundTripper.RoundTripFunc = func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"error":{"code":400,"message":"API key not valid. Please pass a valid API key.","status":"INVALID_ARGUMENT","details":[{"@type":"type.googleapis.com/google.rpc.ErrorInfo","reason":"API_KEY_INVALID","domain":"googleapis.com","metadata":{"service":"generativelanguage.googleapis.com"}}]}}`)),
+				}, nil
+			}
+
+			_, err := repo.Chat(context.Background(), mockChatRequest)
+
+			Convey("Then it should reject the credential", func() {
+				So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+			})
+		})
+
 		Convey("When the API returns empty candidates", func() {
 			mockRoundTripper.RoundTripFunc = func(req *http.Request) (*http.Response, error) {
 				return &http.Response{
@@ -236,20 +252,22 @@ func TestEmbed(t *testing.T) {
 			mockRT.RoundTripFunc = func(req *http.Request) (*http.Response, error) {
 				So(req.URL.Path, ShouldContainSubstring, "/text-embedding-004")
 
+				body := `{"embeddings": [{"values": [0.1, 0.2, 0.3]}]}`
+				if strings.HasSuffix(req.URL.Path, ":countTokens") {
+					body = `{"totalTokens": 2}`
+				}
 				return &http.Response{
 					StatusCode: http.StatusOK,
 					Header:     http.Header{"Content-Type": []string{"application/json"}},
-					Body: io.NopCloser(strings.NewReader(`{
-						"embeddings": [{"values": [0.1, 0.2, 0.3]}]
-					}`)),
+					Body:       io.NopCloser(strings.NewReader(body)),
 				}, nil
 			}
 
 			embedReq := &entity.EmbedRequest{
 				Id:    "embed-1",
 				Model: "text-embedding-004",
-				Contents: []*v1.Content{
-					{Content: v1.NewTextContent("hello world")},
+				Inputs: []*v1.EmbedInput{
+					{Contents: []*v1.Content{{Content: v1.NewTextContent("hello world")}}},
 				},
 			}
 
@@ -259,10 +277,63 @@ func TestEmbed(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(resp, ShouldNotBeNil)
 				So(resp.Id, ShouldEqual, "embed-1")
-				So(resp.Embedding, ShouldHaveLength, 3)
-				So(resp.Embedding[0], ShouldAlmostEqual, 0.1, 0.001)
-				So(resp.Embedding[1], ShouldAlmostEqual, 0.2, 0.001)
-				So(resp.Embedding[2], ShouldAlmostEqual, 0.3, 0.001)
+				So(resp.Embeddings, ShouldHaveLength, 1)
+				values := resp.Embeddings[0].Values
+				So(values, ShouldHaveLength, 3)
+				So(values[0], ShouldAlmostEqual, 0.1, 0.001)
+				So(values[1], ShouldAlmostEqual, 0.2, 0.001)
+				So(values[2], ShouldAlmostEqual, 0.3, 0.001)
+			})
+
+			Convey("Then the usage should be counted with the tokenizer", func() {
+				So(resp.Usage.GetInputTokens(), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When Embed is called with multiple inputs", func() {
+			var requestBody []byte
+			mockRT.RoundTripFunc = func(req *http.Request) (*http.Response, error) {
+				So(req.URL.Path, ShouldContainSubstring, ":batchEmbedContents")
+				requestBody, _ = io.ReadAll(req.Body)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body: io.NopCloser(strings.NewReader(`{
+						"embeddings": [
+							{"values": [0.1, 0.2], "statistics": {"tokenCount": 1}},
+							{"values": [0.3, 0.4], "statistics": {"tokenCount": 2}}
+						]
+					}`)),
+				}, nil
+			}
+
+			embedReq := &entity.EmbedRequest{
+				Id:    "embed-2",
+				Model: "text-embedding-004",
+				Inputs: []*v1.EmbedInput{
+					{Contents: []*v1.Content{{Content: v1.NewTextContent("first")}}},
+					{Contents: []*v1.Content{{Content: v1.NewTextContent("second")}}},
+				},
+				Dimensions: new(uint32(2)),
+				TaskType:   v1.EmbedTaskType_EMBED_TASK_TYPE_DOCUMENT,
+			}
+
+			resp, err := u.Embed(context.Background(), embedReq)
+
+			Convey("Then each input should be sent as a content of its own", func() {
+				So(err, ShouldBeNil)
+				So(string(requestBody), ShouldContainSubstring, "first")
+				So(string(requestBody), ShouldContainSubstring, "second")
+				So(string(requestBody), ShouldContainSubstring, `"outputDimensionality":2`)
+				So(string(requestBody), ShouldContainSubstring, `"taskType":"RETRIEVAL_DOCUMENT"`)
+				So(resp.Embeddings, ShouldHaveLength, 2)
+				So(resp.Embeddings[1].Index, ShouldEqual, 1)
+				So(resp.Embeddings[1].Values[0], ShouldAlmostEqual, 0.3, 0.001)
+			})
+
+			Convey("Then the usage should sum the token statistics", func() {
+				So(resp.Usage.GetInputTokens(), ShouldEqual, 3)
 			})
 		})
 
@@ -274,8 +345,8 @@ func TestEmbed(t *testing.T) {
 			embedReq := &entity.EmbedRequest{
 				Id:    "embed-1",
 				Model: "text-embedding-004",
-				Contents: []*v1.Content{
-					{Content: v1.NewTextContent("hello world")},
+				Inputs: []*v1.EmbedInput{
+					{Contents: []*v1.Content{{Content: v1.NewTextContent("hello world")}}},
 				},
 			}
 
@@ -288,3 +359,60 @@ func TestEmbed(t *testing.T) {
 		})
 	})
 }
+
+func TestCountTokens(t *testing.T) {
+	Convey("Given a upstream with a mock HTTP round tripper", t, func() {
+		mockRoundTripper := &mockRoundTripper{}
+		repo, err := newGoogleUpstreamWithClient(
+			&conf.GoogleConfig{
+				ApiKey: "test-api-key",
+			},
+			&http.Client{Transport: mockRoundTripper},
+			slog.Default(),
+		)
+		So(err, ShouldBeNil)
+
+		Convey("When CountTokens is called", func() {
+			var reqMap map[string]any
+			mockRoundTripper.RoundTripFunc = func(req *http.Request) (*http.Response, error) {
+				So(req.URL.Path, ShouldContainSubstring, "/gemini-3-flash:countTokens")
+
+				body, err := io.ReadAll(req.Body)
+				So(err, ShouldBeNil)
+				So(json.Unmarshal(body, &reqMap), ShouldBeNil)
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"totalTokens": 42}`)),
+				}, nil
+			}
+
+			tokens, err := repo.(*upstream).CountTokens(context.Background(), mockChatRequest)
+
+			Convey("Then it should count the system instruction as the first content", func() {
+				So(err, ShouldBeNil)
+				So(tokens, ShouldEqual, 42)
+
+				contents := reqMap["contents"].([]any)
+				So(contents, ShouldHaveLength, 4)
+				first := contents[0].(map[string]any)
+				So(first["role"], ShouldEqual, "user")
+				So(first["parts"].([]any)[0].(map[string]any)["text"], ShouldEqual, "You are helpful assistant.")
+				So(reqMap, ShouldNotContainKey, "tools")
+			})
+		})
+
+		Convey("When the API call fails", func() {
+			mockRoundTripper.RoundTripFunc = func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("network error")
+			}
+
+			_, err := repo.(*upstream).CountTokens(context.Background(), mockChatRequest)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -263,14 +263,17 @@ var mockChatResponse = &entity.ChatResponse{
 		Role: v1.Role_ROLE_MODEL,
 		Contents: []*v1.Content{
 			{
-				Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-				Content: v1.NewTextContent("**Yesterday's Shanghai Weather**\n\nOkay, so the user asked about the weather in Shanghai yesterday. I've already handled the initial greeting, so I'm past that. Right now, I have today's date, which is \"2025-11-11\". That's a good start.\n\nMy next step is simple: I need to figure out what date \"yesterday\" actually was. With today being the 11th, that means yesterday was the 10th. Easy enough, that's \"2025-11-10\". \n\nNow that I have the date I need, I can use the 'get_weather' tool with the location \"shanghai\" and the date \"2025-11-10\". That should give me the information the user is looking for.\n"),
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: v1.Provenance_PROVENANCE_GOOGLE,
+				Content:    v1.NewTextContent("**Yesterday's Shanghai Weather**\n\nOkay, so the user asked about the weather in Shanghai yesterday. I've already handled the initial greeting, so I'm past that. Right now, I have today's date, which is \"2025-11-11\". That's a good start.\n\nMy next step is simple: I need to figure out what date \"yesterday\" actually was. With today being the 11th, that means yesterday was the 10th. Easy enough, that's \"2025-11-10\". \n\nNow that I have the date I need, I can use the 'get_weather' tool with the location \"shanghai\" and the date \"2025-11-10\". That should give me the information the user is looking for.\n"),
 			},
 			{
-				Signature: "CtcCAdHtim9//a+avd6Mdp2nfaFDy9UN0XRKL5s7OpASpj4EYl4F3YcytuWj2af37z/RF2Wu4wabZG8dj9X5w5alnFCjBrepYwCZwmjbDWmeDcWfygAo6gtbThCoD7I8k1cZD4SzAMR5tlXSQJdqLJE/D2kT54WsTHLm95UHQ5s68/mYf0n6yKLvj+le92wXvkxFctZ6Gsu/W5ihhj9rlADwWWy4fhhJEvPgHm7z+t9+FmfltfX+/Yumzk2GUXSxxhosRXFX4WXlpW096MEFALnkURWeJ+owj6ppNyqNx6i7Hbz70gH3Y5odjvpGVyk8iaDM6SAWV81q95bcGjqso1LF/AyqnQS26XRFtoRcpdPMfDCrFEOPUcD3MlswapgGmnFK2DdKgpwvc3TPCLna9JdltoEVohVaimT7nS4EkSkrDsHiKsp0Omb7cmOCMCe9WXOHhUsx7qeLaA==",
-				Content:   v1.NewTextContent("Yesterday was 2025-11-10.\n"),
+				Provenance: v1.Provenance_PROVENANCE_GOOGLE,
+				Signature:  "CtcCAdHtim9//a+avd6Mdp2nfaFDy9UN0XRKL5s7OpASpj4EYl4F3YcytuWj2af37z/RF2Wu4wabZG8dj9X5w5alnFCjBrepYwCZwmjbDWmeDcWfygAo6gtbThCoD7I8k1cZD4SzAMR5tlXSQJdqLJE/D2kT54WsTHLm95UHQ5s68/mYf0n6yKLvj+le92wXvkxFctZ6Gsu/W5ihhj9rlADwWWy4fhhJEvPgHm7z+t9+FmfltfX+/Yumzk2GUXSxxhosRXFX4WXlpW096MEFALnkURWeJ+owj6ppNyqNx6i7Hbz70gH3Y5odjvpGVyk8iaDM6SAWV81q95bcGjqso1LF/AyqnQS26XRFtoRcpdPMfDCrFEOPUcD3MlswapgGmnFK2DdKgpwvc3TPCLna9JdltoEVohVaimT7nS4EkSkrDsHiKsp0Omb7cmOCMCe9WXOHhUsx7qeLaA==",
+				Content:    v1.NewTextContent("Yesterday was 2025-11-10.\n"),
 			},
 			{
+				Provenance: v1.Provenance_PROVENANCE_GOOGLE,
 				Content: &v1.Content_ToolUse{
 					ToolUse: &v1.ToolUse{
 						Id:   "get_weather",
@@ -317,15 +320,15 @@ func mockStreamStopEvent(status v1.ChatStatus, usage *v1.Usage) *entity.ChatEven
 
 var mockStreamChatEvents = []*entity.ChatEvent{
 	mockStreamEvent(v1.NewMessageStartEvent("HTESab3lIJWe0-kP6_Wh4Ao", "gemini-3-flash")),
-	mockStreamEvent(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+	mockStreamEvent(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING).WithProvenance(v1.Provenance_PROVENANCE_GOOGLE)),
 	mockStreamEvent(v1.NewContentDeltaTextEvent(0, "**Calculating Yesterday's Date**\n\nI've successfully retrieved today's date, 2025-11-11. My current focus is to determine yesterday's date, which I've now calculated as 2025-11-10. This is a crucial step towards providing the requested weather information, and I'm on track to deliver a complete and accurate response.\n\n\n")),
 	mockStreamEvent(v1.NewContentDeltaTextEvent(0, "**Refining Date Parameters**\n\nI've determined yesterday's date, 2025-11-10, crucial for getting the weather. I'm building on that success. My task now is to integrate this date into the 'get_weather' tool with \"shanghai\" as the location. This will allow me to finally provide the requested information, which I will then report back.\n\n\n")),
 	mockStreamEvent(v1.NewContentStopEvent(0)),
-	mockStreamEvent(v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+	mockStreamEvent(v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL).WithProvenance(v1.Provenance_PROVENANCE_GOOGLE)),
 	mockStreamEvent(v1.NewContentDeltaTextEvent(1, "Yesterday was 2025-11-10.\n")),
 	mockStreamEvent(v1.NewContentDeltaSignatureEvent(1, "CikB0e2KbwvEqAUe/Jbf3zx5lg6fKQe382RFpFzHXfaI7x59tkpEUrWQ0Qp6AdHtim93jg0+fEbEV+4yvK/XAUKtsxzs/NjSKNdVB9bE6QiZZgun3CCrEMtOnvI0c0YPeSh7cD7pbCYrEdJAedfO0qLEkLB0Txf7vP8CCXFdVRfid8HX5vATXDgmJBvsb+oNBJMtbYKmvT89CvcceFYWtWUxQPVE7p0KsAEB0e2Kb7CsrMats3mXW9aOqkSbuS3kM3a6YTOQymYEfIsWmRNpM/1wvZsg8rfBSQ4rNtnKoXsRLhYdmpR4T3h5xV/UpclCduXabEjl4BV4lhln1Rp0CdAzW4j60NUv85NKR9Z0rt1sPZwwJ9B+XAgLnqz3aHWGImJG5ZXMa9FRmTIdV3ko8bAgup1nLYrl7UeOb/+QFSEMxqgZ0a9IPAJN/gB1BSBCRAzvZ/xvP3F3tgppAdHtim9mSHTlqsHDzXB6eIXcG+ciJayNMWdfVwrJ1RLK5aXwNgaeQb7p8QANO3gH9GpY5bIIazL+w20wnKM8xFP5rFD8T/x4LNhe+0sXh4Y7aJewaR8C6DN2ocob8zKi1qxqXyEaJL9I")),
 	mockStreamEvent(v1.NewContentStopEvent(1)),
-	mockStreamEvent(v1.NewContentStartToolUseEvent(2, "get_weather", "get_weather")),
+	mockStreamEvent(v1.NewContentStartToolUseEvent(2, "get_weather", "get_weather").WithProvenance(v1.Provenance_PROVENANCE_GOOGLE)),
 	mockStreamEvent(v1.NewContentDeltaToolInputTextEvent(2, `{"city":"shanghai","date":"2025-11-10"}`)),
 	mockStreamEvent(v1.NewContentStopEvent(2)),
 	mockStreamStopEvent(v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE, &v1.Usage{
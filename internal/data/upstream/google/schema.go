@@ -0,0 +1,209 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// googleSchemaKeywords are the JSON Schema keywords Gemini supports in a
+// response schema. It rejects requests using some of the others.
+var googleSchemaKeywords = map[string]bool{
+	"$id":                  true,
+	"$defs":                true,
+	"$ref":                 true,
+	"$anchor":              true,
+	"type":                 true,
+	"format":               true,
+	"title":                true,
+	"description":          true,
+	"enum":                 true,
+	"items":                true,
+	"prefixItems":          true,
+	"minItems":             true,
+	"maxItems":             true,
+	"minimum":              true,
+	"maximum":              true,
+	"anyOf":                true,
+	"oneOf":                true,
+	"properties":           true,
+	"additionalProperties": true,
+	"required":             true,
+	"propertyOrdering":     true,
+}
+
+// convertSchemaToGoogle translates a JSON Schema into the subset Gemini
+// accepts for structured output. Keywords with an equivalent are rewritten,
+// such as const into a single-valued enum, type lists into anyOf, allOf into
+// a merged schema and definitions into $defs. Validation keywords without one,
+// such as pattern or minLength, are dropped, so the output is looser than the
+// schema but never rejected.
+func convertSchemaToGoogle(schema map[string]any) map[string]any {
+	if schema == nil {
+		return nil
+	}
+	schema = mergeAllOf(schema)
+
+	out := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "definitions", "$defs":
+			if defs, ok := value.(map[string]any); ok {
+				merged, _ := out["$defs"].(map[string]any)
+				if merged == nil {
+					merged = make(map[string]any, len(defs))
+				}
+				for name, def := range defs {
+					merged[name] = convertSubschemaToGoogle(def)
+				}
+				out["$defs"] = merged
+			}
+		case "$ref":
+			if ref, ok := value.(string); ok {
+				out[key] = strings.Replace(ref, "#/definitions/", "#/$defs/", 1)
+			}
+		case "const":
+			if _, ok := schema["enum"]; !ok {
+				out["enum"] = []any{value}
+			}
+		case "exclusiveMinimum":
+			if _, ok := schema["minimum"]; !ok {
+				out["minimum"] = value
+			}
+		case "exclusiveMaximum":
+			if _, ok := schema["maximum"]; !ok {
+				out["maximum"] = value
+			}
+		case "properties":
+			if properties, ok := value.(map[string]any); ok {
+				converted := make(map[string]any, len(properties))
+				for name, property := range properties {
+					converted[name] = convertSubschemaToGoogle(property)
+				}
+				out[key] = converted
+			}
+		case "items":
+			// Draft 4 tuples list the items, which later drafts call prefixItems
+			if items, ok := value.([]any); ok {
+				out["prefixItems"] = convertSubschemasToGoogle(items)
+			} else {
+				out[key] = convertSubschemaToGoogle(value)
+			}
+		case "prefixItems", "anyOf", "oneOf":
+			if subschemas, ok := value.([]any); ok {
+				out[key] = convertSubschemasToGoogle(subschemas)
+			}
+		case "additionalProperties":
+			out[key] = convertSubschemaToGoogle(value)
+		default:
+			if googleSchemaKeywords[key] {
+				out[key] = value
+			}
+		}
+	}
+
+	// A type list is spelled as alternatives of single types
+	if types, ok := out["type"].([]any); ok {
+		delete(out, "type")
+		alternatives := make([]any, 0, len(types))
+		for _, t := range types {
+			alternatives = append(alternatives, map[string]any{"type": t})
+		}
+		if len(alternatives) == 1 {
+			out["type"] = types[0]
+		} else if _, ok := out["anyOf"]; !ok {
+			out["anyOf"] = alternatives
+		}
+	}
+
+	// A reference may only be accompanied by keywords starting with $
+	if _, ok := out["$ref"]; ok {
+		maps.DeleteFunc(out, func(key string, _ any) bool {
+			return !strings.HasPrefix(key, "$")
+		})
+	}
+	return out
+}
+
+// convertSubschemaToGoogle converts a nested schema, which may also be a
+// boolean schema.
+func convertSubschemaToGoogle(value any) any {
+	if schema, ok := value.(map[string]any); ok {
+		return convertSchemaToGoogle(schema)
+	}
+	return value
+}
+
+func convertSubschemasToGoogle(subschemas []any) []any {
+	converted := make([]any, 0, len(subschemas))
+	for _, subschema := range subschemas {
+		converted = append(converted, convertSubschemaToGoogle(subschema))
+	}
+	return converted
+}
+
+// mergeAllOf folds the subschemas of allOf into the schema, uniting their
+// properties and required lists. Other keywords keep their first value.
+func mergeAllOf(schema map[string]any) map[string]any {
+	allOf, ok := schema["allOf"].([]any)
+	if !ok {
+		return schema
+	}
+
+	merged := maps.Clone(schema)
+	delete(merged, "allOf")
+	for _, value := range allOf {
+		subschema, ok := value.(map[string]any)
+		if !ok {
+			continue
+		}
+		for key, v := range mergeAllOf(subschema) {
+			switch key {
+			case "properties":
+				properties, _ := merged[key].(map[string]any)
+				properties = maps.Clone(properties)
+				if properties == nil {
+					properties = make(map[string]any)
+				}
+				if p, ok := v.(map[string]any); ok {
+					for name, property := range p {
+						if _, exists := properties[name]; !exists {
+							properties[name] = property
+						}
+					}
+				}
+				merged[key] = properties
+			case "required":
+				required, _ := merged[key].([]any)
+				required = slices.Clone(required)
+				if r, ok := v.([]any); ok {
+					for _, name := range r {
+						if !slices.Contains(required, name) {
+							required = append(required, name)
+						}
+					}
+				}
+				merged[key] = required
+			default:
+				if _, exists := merged[key]; !exists {
+					merged[key] = v
+				}
+			}
+		}
+	}
+	return merged
+}
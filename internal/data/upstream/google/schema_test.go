@@ -0,0 +1,110 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConvertSchemaToGoogle(t *testing.T) {
+	Convey("Test convertSchemaToGoogle", t, func() {
+		Convey("should keep supported keywords and drop the rest", func() {
+			schema := convertSchemaToGoogle(map[string]any{
+				"$schema":              "http://json-schema.org/draft-07/schema#",
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []any{"name"},
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string", "minLength": 1.0, "pattern": "^[a-z]+$", "description": "Name"},
+					"count": map[string]any{"type": "integer", "exclusiveMinimum": 0.0, "multipleOf": 2.0},
+				},
+			})
+			So(schema, ShouldResemble, map[string]any{
+				"type":                 "object",
+				"additionalProperties": false,
+				"required":             []any{"name"},
+				"properties": map[string]any{
+					"name":  map[string]any{"type": "string", "description": "Name"},
+					"count": map[string]any{"type": "integer", "minimum": 0.0},
+				},
+			})
+		})
+
+		Convey("should rewrite const, type lists and tuples", func() {
+			schema := convertSchemaToGoogle(map[string]any{
+				"type": "array",
+				"items": []any{
+					map[string]any{"const": "point"},
+					map[string]any{"type": []any{"number", "null"}},
+				},
+			})
+			So(schema, ShouldResemble, map[string]any{
+				"type": "array",
+				"prefixItems": []any{
+					map[string]any{"enum": []any{"point"}},
+					map[string]any{"anyOf": []any{
+						map[string]any{"type": "number"},
+						map[string]any{"type": "null"},
+					}},
+				},
+			})
+		})
+
+		Convey("should move definitions to $defs and strip the siblings of references", func() {
+			schema := convertSchemaToGoogle(map[string]any{
+				"definitions": map[string]any{
+					"item": map[string]any{"type": "string", "format": "uuid"},
+				},
+				"properties": map[string]any{
+					"item": map[string]any{"$ref": "#/definitions/item", "description": "An item"},
+				},
+			})
+			So(schema, ShouldResemble, map[string]any{
+				"$defs": map[string]any{
+					"item": map[string]any{"type": "string", "format": "uuid"},
+				},
+				"properties": map[string]any{
+					"item": map[string]any{"$ref": "#/$defs/item"},
+				},
+			})
+		})
+
+		Convey("should merge allOf", func() {
+			schema := convertSchemaToGoogle(map[string]any{
+				"type": "object",
+				"allOf": []any{
+					map[string]any{
+						"properties": map[string]any{"a": map[string]any{"type": "string"}},
+						"required":   []any{"a"},
+					},
+					map[string]any{
+						"properties": map[string]any{"b": map[string]any{"type": "integer"}},
+						"required":   []any{"b"},
+					},
+				},
+			})
+			So(schema, ShouldResemble, map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"a": map[string]any{"type": "string"},
+					"b": map[string]any{"type": "integer"},
+				},
+				"required": []any{"a", "b"},
+			})
+		})
+	})
+}
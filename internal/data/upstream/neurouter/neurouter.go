@@ -16,9 +16,11 @@ package neurouter
 
 import (
 	"context"
+	"errors"
 	"io"
 	"iter"
 	"log/slog"
+	"net/http"
 
 	"github.com/go-kratos/kratos/v3/transport/grpc"
 
@@ -29,14 +31,25 @@ import (
 )
 
 type upstream struct {
-	config          *conf.NeurouterConfig
-	chatClient      v1.ChatClient
-	embeddingClient v1.EmbeddingClient
-	log             *slog.Logger
+	config           *conf.NeurouterConfig
+	chatClient       v1.ChatClient
+	embeddingClient  v1.EmbeddingClient
+	completionClient v1.CompletionClient
+	modelClient      v1.ModelClient
+	log              *slog.Logger
 }
 
+// errTransportUnsupported rejects transport settings, which only apply to HTTP
+// upstreams while Neurouter upstreams are reached over gRPC.
+var errTransportUnsupported = errors.New("transport settings are not supported by neurouter upstreams")
+
 func NewNeurouterFactory() repository.UpstreamFactory[conf.NeurouterConfig] {
-	return newNeurouterUpstream
+	return func(config *conf.NeurouterConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+		if transport != nil {
+			return nil, errTransportUnsupported
+		}
+		return newNeurouterUpstream(config, logger)
+	}
 }
 
 func newNeurouterUpstream(config *conf.NeurouterConfig, logger *slog.Logger) (repository.Repo, error) {
@@ -49,10 +62,12 @@ func newNeurouterUpstream(config *conf.NeurouterConfig, logger *slog.Logger) (re
 	}
 
 	return &upstream{
-		config:          config,
-		chatClient:      v1.NewChatClient(conn),
-		embeddingClient: v1.NewEmbeddingClient(conn),
-		log:             logger,
+		config:           config,
+		chatClient:       v1.NewChatClient(conn),
+		embeddingClient:  v1.NewEmbeddingClient(conn),
+		completionClient: v1.NewCompletionClient(conn),
+		modelClient:      v1.NewModelClient(conn),
+		log:              logger,
 	}, nil
 }
 
@@ -99,5 +114,62 @@ func (r *upstream) ChatStream(ctx context.Context, req *entity.ChatRequest) iter
 }
 
 func (r *upstream) Embed(ctx context.Context, req *entity.EmbedRequest) (*entity.EmbedResponse, error) {
-	return r.embeddingClient.Embed(ctx, req)
+	// Send a single input as contents, which older routers understand
+	if len(req.Inputs) == 1 && len(req.Contents) == 0 {
+		req = &entity.EmbedRequest{
+			Id:         req.Id,
+			Model:      req.Model,
+			Contents:   req.Inputs[0].Contents,
+			Dimensions: req.Dimensions,
+			TaskType:   req.TaskType,
+			Normalize:  req.Normalize,
+		}
+	}
+
+	resp, err := r.embeddingClient.Embed(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Older routers only return the embedding of a single input
+	if len(resp.Embeddings) == 0 && len(resp.Embedding) > 0 {
+		resp.Embeddings = []*v1.EmbeddingVector{{Values: resp.Embedding}}
+	}
+	return resp, nil
+}
+
+func (r *upstream) ListModels(ctx context.Context) ([]*entity.ModelSpec, error) {
+	resp, err := r.modelClient.ListModel(ctx, &v1.ListModelRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Models, nil
+}
+
+func (r *upstream) Complete(ctx context.Context, req *entity.CompleteRequest) (*entity.CompleteResponse, error) {
+	return r.completionClient.Complete(ctx, req)
+}
+
+func (r *upstream) CompleteStream(ctx context.Context, req *entity.CompleteRequest) iter.Seq2[*entity.CompleteResponse, error] {
+	return func(yield func(*entity.CompleteResponse, error) bool) {
+		stream, err := r.completionClient.CompleteStream(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					yield(nil, err)
+				}
+				return
+			}
+
+			if !yield(resp, nil) {
+				return
+			}
+		}
+	}
 }
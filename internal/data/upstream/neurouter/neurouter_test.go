@@ -0,0 +1,43 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neurouter
+
+import (
+	"log/slog"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestNewNeurouterFactory(t *testing.T) {
+	Convey("Test NewNeurouterFactory", t, func() {
+		factory := NewNeurouterFactory()
+		config := &conf.NeurouterConfig{Endpoint: "localhost:9000"}
+
+		Convey("should reject transport settings", func() {
+			_, err := factory(config, http.DefaultTransport, slog.Default())
+			So(err, ShouldEqual, errTransportUnsupported)
+		})
+
+		Convey("should connect without transport settings", func() {
+			repo, err := factory(config, nil, slog.Default())
+			So(err, ShouldBeNil)
+			So(repo, ShouldNotBeNil)
+		})
+	})
+}
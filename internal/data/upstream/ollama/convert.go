@@ -0,0 +1,416 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// Ollama requires the tool name on every tool result, but a ToolResult carries
+// only the id of the call it answers, and Ollama does not identify calls. The
+// name is therefore packed into the id of the calls it issues, ahead of the id
+// of the message and the position of the call, which keep ids unique across
+// the turns of a conversation. Results of calls from other providers are
+// matched by the id of the call found earlier in the conversation.
+const ollamaToolUseIDSeparator = ":"
+
+// toolUseIDFromOllama builds the handle a client uses to answer a tool call.
+func toolUseIDFromOllama(name, messageID string, index int) string {
+	if messageID == "" {
+		return fmt.Sprintf("%s%s%d", name, ollamaToolUseIDSeparator, index)
+	}
+	return fmt.Sprintf("%s%s%s-%d", name, ollamaToolUseIDSeparator, messageID, index)
+}
+
+func (r *upstream) convertOptionsToOllama(config *v1.GenerationConfig) *options {
+	opts := &options{NumCtx: r.config.NumCtx}
+	if config != nil {
+		opts.Temperature = config.Temperature
+		opts.TopP = config.TopP
+		opts.TopK = config.TopK
+		opts.NumPredict = config.MaxTokens
+		opts.FrequencyPenalty = config.FrequencyPenalty
+		opts.PresencePenalty = config.PresencePenalty
+		opts.Stop = config.StopSequences
+	}
+	if opts.Temperature == nil && opts.TopP == nil && opts.TopK == nil && opts.NumPredict == nil &&
+		opts.NumCtx == 0 && opts.FrequencyPenalty == nil && opts.PresencePenalty == nil && len(opts.Stop) == 0 {
+		return nil
+	}
+	return opts
+}
+
+func convertThinkToOllama(config *v1.ReasoningConfig) any {
+	if config == nil {
+		return nil
+	}
+	switch config.Effort {
+	case v1.ReasoningEffort_REASONING_EFFORT_NONE:
+		return false
+	case v1.ReasoningEffort_REASONING_EFFORT_MINIMAL, v1.ReasoningEffort_REASONING_EFFORT_LOW:
+		return "low"
+	case v1.ReasoningEffort_REASONING_EFFORT_MEDIUM:
+		return "medium"
+	case v1.ReasoningEffort_REASONING_EFFORT_HIGH,
+		v1.ReasoningEffort_REASONING_EFFORT_EXTRA_HIGH,
+		v1.ReasoningEffort_REASONING_EFFORT_MAX:
+		return "high"
+	default:
+		if config.TokenBudget > 0 {
+			return true
+		}
+		return nil
+	}
+}
+
+func convertFormatToOllama(config *v1.GenerationConfig) json.RawMessage {
+	switch g := config.GetGrammar().(type) {
+	case *v1.GenerationConfig_PresetGrammar:
+		if g.PresetGrammar == "json_object" {
+			return json.RawMessage(`"json"`)
+		}
+	case *v1.GenerationConfig_Schema:
+		if schema, err := json.Marshal(g.Schema.AsMap()); err == nil {
+			return schema
+		}
+	}
+	return nil
+}
+
+// filterToolsForOllama returns the tools the model may call under the tool
+// choice, as Ollama has no tool choice: none in the none mode, the named tool
+// in the tool mode and the allowed tools otherwise.
+func filterToolsForOllama(toolChoice *v1.ToolChoice, tools []*v1.Tool) []*v1.Tool {
+	switch toolChoice.GetMode() {
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+		return nil
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		return slices.DeleteFunc(slices.Clone(tools), func(t *v1.Tool) bool {
+			return t.GetFunction().GetName() != toolChoice.GetName()
+		})
+	}
+	return toolChoice.FilterTools(tools)
+}
+
+func convertToolsToOllama(tools []*v1.Tool) []tool {
+	var result []tool
+	for _, t := range tools {
+		fn := t.GetFunction()
+		if fn == nil {
+			continue
+		}
+		result = append(result, tool{
+			Type: "function",
+			Function: toolFunction{
+				Name:        fn.Name,
+				Description: fn.Description,
+				Parameters:  fn.GetInputSchema().AsMap(),
+			},
+		})
+	}
+	return result
+}
+
+// convertToolArgumentsToOllama returns the arguments as a JSON object, as
+// Ollama does not accept them as text.
+func convertToolArgumentsToOllama(arguments string) json.RawMessage {
+	if !json.Valid([]byte(arguments)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(arguments)
+}
+
+func (r *upstream) convertRequestToOllama(req *entity.ChatRequest) *chatRequest {
+	ollamaReq := &chatRequest{
+		Model:     req.Model,
+		Tools:     convertToolsToOllama(filterToolsForOllama(req.ToolChoice, req.Tools)),
+		Format:    convertFormatToOllama(req.Config),
+		Think:     convertThinkToOllama(req.Config.GetReasoningConfig()),
+		Options:   r.convertOptionsToOllama(req.Config),
+		KeepAlive: r.config.KeepAlive,
+	}
+
+	// The names of the tools called so far, to name their results
+	toolNames := make(map[string]string)
+
+	for _, msg := range req.Messages {
+		m := message{}
+		switch msg.Role {
+		case v1.Role_ROLE_SYSTEM:
+			m.Role = "system"
+		case v1.Role_ROLE_MODEL:
+			m.Role = "assistant"
+		default:
+			m.Role = "user"
+		}
+
+		var content, thinking strings.Builder
+		for _, c := range msg.Contents {
+			switch cc := c.Content.(type) {
+			case *v1.Content_Text:
+				if c.IsReasoning() {
+					thinking.WriteString(cc.Text.GetText())
+				} else {
+					content.WriteString(cc.Text.GetText())
+				}
+			case *v1.Content_Image:
+				if b64, ok := cc.Image.Source.(*v1.Image_Base64); ok {
+					m.Images = append(m.Images, b64.Base64)
+				} else {
+					r.log.Error("ollama only supports base64 images", "image", cc.Image)
+				}
+			case *v1.Content_ToolUse:
+				toolNames[cc.ToolUse.Id] = cc.ToolUse.Name
+				m.ToolCalls = append(m.ToolCalls, toolCall{
+					Function: toolCallFunction{
+						Name:      cc.ToolUse.Name,
+						Arguments: convertToolArgumentsToOllama(cc.ToolUse.GetTextualInput()),
+					},
+				})
+			case *v1.Content_ToolResult:
+				// Each tool result is a message of its own
+				name, ok := toolNames[cc.ToolResult.Id]
+				if !ok {
+					name, _, _ = strings.Cut(cc.ToolResult.Id, ollamaToolUseIDSeparator)
+				}
+				var output strings.Builder
+				for _, o := range cc.ToolResult.Outputs {
+					output.WriteString(o.GetText())
+				}
+				ollamaReq.Messages = append(ollamaReq.Messages, message{
+					Role:     "tool",
+					Content:  output.String(),
+					ToolName: name,
+				})
+			default:
+				r.log.Error("unsupported content", "content", cc)
+			}
+		}
+		m.Content = content.String()
+		m.Thinking = thinking.String()
+
+		if m.Content == "" && m.Thinking == "" && len(m.Images) == 0 && len(m.ToolCalls) == 0 {
+			continue
+		}
+		ollamaReq.Messages = append(ollamaReq.Messages, m)
+	}
+
+	return ollamaReq
+}
+
+func convertStatusFromOllama(doneReason string, toolUse bool) v1.ChatStatus {
+	switch {
+	case toolUse:
+		return v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
+	case doneReason == "length":
+		return v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT
+	default:
+		return v1.ChatStatus_CHAT_STATUS_COMPLETED
+	}
+}
+
+func convertUsageFromOllama(resp *chatResponse) *v1.Usage {
+	if resp.PromptEvalCount == 0 && resp.EvalCount == 0 {
+		return nil
+	}
+	return &v1.Usage{
+		InputTokens:  uint32(max(resp.PromptEvalCount, 0)),
+		OutputTokens: uint32(max(resp.EvalCount, 0)),
+	}
+}
+
+func convertToolCallFromOllama(call *toolCall, messageID string, index int) *v1.ToolUse {
+	return &v1.ToolUse{
+		Id:   toolUseIDFromOllama(call.Function.Name, messageID, index),
+		Name: call.Function.Name,
+		Inputs: []*v1.ToolUse_Input{
+			{Input: &v1.ToolUse_Input_Text{Text: string(call.Function.Arguments)}},
+		},
+	}
+}
+
+func convertResponseFromOllama(req *entity.ChatRequest, resp *chatResponse) *entity.ChatResponse {
+	msg := &v1.Message{Role: v1.Role_ROLE_MODEL}
+	if resp.Message.Thinking != "" {
+		msg.Contents = append(msg.Contents, &v1.Content{
+			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Content: v1.NewTextContent(resp.Message.Thinking),
+		})
+	}
+	if resp.Message.Content != "" {
+		msg.Contents = append(msg.Contents, &v1.Content{Content: v1.NewTextContent(resp.Message.Content)})
+	}
+	for i := range resp.Message.ToolCalls {
+		msg.Contents = append(msg.Contents, &v1.Content{
+			Content: &v1.Content_ToolUse{ToolUse: convertToolCallFromOllama(&resp.Message.ToolCalls[i], req.GetId(), i)},
+		})
+	}
+
+	chatResp := &entity.ChatResponse{
+		Id:      req.Id,
+		Model:   resp.Model,
+		Message: msg,
+		Status:  convertStatusFromOllama(resp.DoneReason, len(resp.Message.ToolCalls) > 0),
+	}
+	if usage := convertUsageFromOllama(resp); usage != nil {
+		chatResp.Statistics = &v1.Statistics{Usage: usage}
+	}
+	return chatResp
+}
+
+// ollamaChatStreamClient converts the NDJSON chunks of a chat stream into
+// events. Ollama streams text and thinking as deltas, and tool calls whole.
+type ollamaChatStreamClient struct {
+	req       *entity.ChatRequest
+	started   bool
+	done      bool
+	index     uint32
+	open      bool
+	phase     v1.ContentPhase
+	toolCalls int
+}
+
+func (c *ollamaChatStreamClient) newChatEvent(payload v1.ChatEventPayload) *entity.ChatEvent {
+	return v1.NewChatEvent(c.req.GetId(), payload)
+}
+
+// closeBlock stops the open content block, if any.
+func (c *ollamaChatStreamClient) closeBlock() []*entity.ChatEvent {
+	if !c.open {
+		return nil
+	}
+	c.open = false
+	c.index++
+	return []*entity.ChatEvent{c.newChatEvent(v1.NewContentStopEvent(c.index - 1))}
+}
+
+// textDelta appends text to the open block of the phase, opening one if needed.
+func (c *ollamaChatStreamClient) textDelta(phase v1.ContentPhase, text string) (events []*entity.ChatEvent) {
+	if text == "" {
+		return nil
+	}
+	if c.open && c.phase != phase {
+		events = c.closeBlock()
+	}
+	if !c.open {
+		c.open = true
+		c.phase = phase
+		events = append(events, c.newChatEvent(v1.NewContentStartTextEvent(c.index, phase)))
+	}
+	return append(events, c.newChatEvent(v1.NewContentDeltaTextEvent(c.index, text)))
+}
+
+func (c *ollamaChatStreamClient) convertChunkFromOllama(chunk *chatResponse) (events []*entity.ChatEvent) {
+	if !c.started {
+		c.started = true
+		events = append(events, c.newChatEvent(v1.NewMessageStartEvent(c.req.GetId(), chunk.Model)))
+	}
+
+	events = append(events, c.textDelta(v1.ContentPhase_CONTENT_PHASE_REASONING, chunk.Message.Thinking)...)
+	events = append(events, c.textDelta(v1.ContentPhase_CONTENT_PHASE_NORMAL, chunk.Message.Content)...)
+
+	for i := range chunk.Message.ToolCalls {
+		events = append(events, c.closeBlock()...)
+		toolUse := convertToolCallFromOllama(&chunk.Message.ToolCalls[i], c.req.GetId(), c.toolCalls)
+		c.toolCalls++
+		events = append(events,
+			c.newChatEvent(v1.NewContentStartToolUseEvent(c.index, toolUse.Id, toolUse.Name)),
+			c.newChatEvent(v1.NewContentDeltaToolInputTextEvent(c.index, toolUse.GetTextualInput())),
+			c.newChatEvent(v1.NewContentStopEvent(c.index)),
+		)
+		c.index++
+	}
+
+	if chunk.Done {
+		c.done = true
+		events = append(events, c.closeBlock()...)
+		stop := c.newChatEvent(v1.NewMessageStopEvent(convertStatusFromOllama(chunk.DoneReason, c.toolCalls > 0)))
+		stop.Usage = convertUsageFromOllama(chunk)
+		events = append(events, stop)
+	}
+	return
+}
+
+func (r *upstream) convertRequestToOllamaEmbed(req *entity.EmbedRequest) *embedRequest {
+	ollamaReq := &embedRequest{
+		Model:      req.Model,
+		Dimensions: req.GetDimensions(),
+		KeepAlive:  r.config.KeepAlive,
+	}
+	if r.config.NumCtx > 0 {
+		ollamaReq.Options = &options{NumCtx: r.config.NumCtx}
+	}
+
+	for _, input := range req.Inputs {
+		var text strings.Builder
+		for _, c := range input.Contents {
+			if t, ok := c.Content.(*v1.Content_Text); ok {
+				text.WriteString(t.Text.GetText())
+			} else {
+				r.log.Error("unsupported embedding content", "content", c.Content)
+			}
+		}
+		ollamaReq.Input = append(ollamaReq.Input, text.String())
+	}
+	return ollamaReq
+}
+
+func convertEmbedResponseFromOllama(req *entity.EmbedRequest, resp *embedResponse) *entity.EmbedResponse {
+	embedResp := &entity.EmbedResponse{
+		Id:    req.Id,
+		Model: resp.Model,
+	}
+	for i, values := range resp.Embeddings {
+		embedResp.Embeddings = append(embedResp.Embeddings, &v1.EmbeddingVector{
+			Index:  uint32(i),
+			Values: values,
+		})
+	}
+	if resp.PromptEvalCount > 0 {
+		embedResp.Usage = &v1.Usage{InputTokens: uint32(resp.PromptEvalCount)}
+	}
+	return embedResp
+}
+
+// convertShowResponseFromOllama fills the capabilities, modalities and context
+// length of spec from the details of the model.
+func convertShowResponseFromOllama(resp *showResponse, spec *entity.ModelSpec) {
+	spec.Modalities = []v1.Modality{v1.Modality_MODALITY_TEXT}
+	for _, c := range resp.Capabilities {
+		switch c {
+		case "completion":
+			spec.Capabilities = append(spec.Capabilities, v1.Capability_CAPABILITY_CHAT)
+		case "tools":
+			spec.Capabilities = append(spec.Capabilities, v1.Capability_CAPABILITY_TOOL_USE)
+		case "embedding":
+			spec.Capabilities = append(spec.Capabilities, v1.Capability_CAPABILITY_EMBEDDING)
+		case "vision":
+			spec.Modalities = append(spec.Modalities, v1.Modality_MODALITY_IMAGE)
+		}
+	}
+
+	// The context length is keyed by the architecture, e.g. llama.context_length
+	if arch, ok := resp.ModelInfo["general.architecture"].(string); ok {
+		if length, ok := resp.ModelInfo[arch+".context_length"].(float64); ok && length > 0 {
+			spec.ContextLength = uint32(length)
+		}
+	}
+}
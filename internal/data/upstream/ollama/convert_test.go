@@ -0,0 +1,82 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tidwall/gjson"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestConvertToolChoiceToOllama(t *testing.T) {
+	Convey("Given a chat request with a tool choice", t, func() {
+		repo := &upstream{config: &conf.OllamaConfig{}, log: slog.Default()}
+		tools := []*v1.Tool{
+			{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_weather"}}},
+			{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_time"}}},
+		}
+		marshal := func(toolChoice *v1.ToolChoice) string {
+			body, err := json.Marshal(repo.convertRequestToOllama(&entity.ChatRequest{
+				Model:      "llama3",
+				Tools:      tools,
+				ToolChoice: toolChoice,
+			}))
+			So(err, ShouldBeNil)
+			return string(body)
+		}
+
+		Convey("When tools are disabled", func() {
+			body := marshal(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE})
+
+			Convey("Then no tool is sent", func() {
+				So(gjson.Get(body, "tools").Exists(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a specific tool is forced", func() {
+			body := marshal(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL, Name: "get_time"})
+
+			Convey("Then only that tool is sent", func() {
+				So(gjson.Get(body, "tools.#.function.name").Raw, ShouldEqual, `["get_time"]`)
+			})
+		})
+
+		Convey("When the tools are restricted", func() {
+			body := marshal(&v1.ToolChoice{
+				Mode:         v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO,
+				AllowedNames: []string{"get_weather"},
+			})
+
+			Convey("Then only the allowed tools are sent", func() {
+				So(gjson.Get(body, "tools.#.function.name").Raw, ShouldEqual, `["get_weather"]`)
+			})
+		})
+
+		Convey("When any tool may be called", func() {
+			body := marshal(&v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO})
+
+			Convey("Then every tool is sent", func() {
+				So(gjson.Get(body, "tools.#.function.name").Raw, ShouldEqual, `["get_weather","get_time"]`)
+			})
+		})
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed chat_stream_max_tokens_request.json
+var streamMaxTokensRequest []byte
+
+//go:embed chat_stream_max_tokens_response.txt
+var streamMaxTokensResponse []byte
+
+func streamMaxTokensChatEvents() []*v1.ChatEvent {
+	id := eventBuilder("stream_max_tokens")
+	return []*v1.ChatEvent{
+		id.of(v1.NewMessageStartEvent("stream_max_tokens", "llama3.2:3b")),
+		id.of(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+		id.of(v1.NewContentDeltaTextEvent(0, "Once")),
+		id.of(v1.NewContentDeltaTextEvent(0, " upon")),
+		id.of(v1.NewContentDeltaTextEvent(0, " a")),
+		id.of(v1.NewContentStopEvent(0)),
+		id.withUsage(
+			v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT),
+			&v1.Usage{InputTokens: 29, OutputTokens: 3},
+		),
+	}
+}
+
+// StreamMaxTokens covers a streamed text cut off by the token limit.
+var StreamMaxTokens = &Fixture{
+	Name:     "stream_max_tokens",
+	Path:     "/api/chat",
+	Request:  streamMaxTokensRequest,
+	Response: streamMaxTokensResponse,
+	Stream:   true,
+	ChatRequest: &v1.ChatRequest{
+		Id:    "stream_max_tokens",
+		Model: "llama3.2:3b",
+		Config: &v1.GenerationConfig{
+			MaxTokens:     new(int64(3)),
+			StopSequences: []string{"THE END"},
+		},
+		Messages: []*v1.Message{
+			{
+				Role:     v1.Role_ROLE_USER,
+				Contents: []*v1.Content{{Content: v1.NewTextContent("Tell me a story.")}},
+			},
+		},
+	},
+	ChatEvents: streamMaxTokensChatEvents(),
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed chat_stream_thinking_tool_call_request.json
+var streamThinkingToolCallRequest []byte
+
+//go:embed chat_stream_thinking_tool_call_response.txt
+var streamThinkingToolCallResponse []byte
+
+func streamThinkingToolCallChatEvents() []*v1.ChatEvent {
+	id := eventBuilder("stream_thinking_tool_call")
+	return []*v1.ChatEvent{
+		id.of(v1.NewMessageStartEvent("stream_thinking_tool_call", "qwen3:8b")),
+		id.of(v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+		id.of(v1.NewContentDeltaTextEvent(0, "The user wants")),
+		id.of(v1.NewContentDeltaTextEvent(0, " historical weather.")),
+		id.of(v1.NewContentStopEvent(0)),
+		id.of(v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+		id.of(v1.NewContentDeltaTextEvent(1, "Let me")),
+		id.of(v1.NewContentDeltaTextEvent(1, " check.")),
+		id.of(v1.NewContentStopEvent(1)),
+		id.of(v1.NewContentStartToolUseEvent(2, "get_weather:stream_thinking_tool_call-0", "get_weather")),
+		id.of(v1.NewContentDeltaToolInputTextEvent(2, `{"city":"Shanghai","date":"2025-11-10"}`)),
+		id.of(v1.NewContentStopEvent(2)),
+		id.withUsage(
+			v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE),
+			&v1.Usage{InputTokens: 207, OutputTokens: 64},
+		),
+	}
+}
+
+// StreamThinkingToolCall covers streamed thinking and text followed by a
+// tool call, which Ollama streams whole.
+var StreamThinkingToolCall = &Fixture{
+	Name:     "stream_thinking_tool_call",
+	Path:     "/api/chat",
+	Request:  streamThinkingToolCallRequest,
+	Response: streamThinkingToolCallResponse,
+	Stream:   true,
+	ChatRequest: &v1.ChatRequest{
+		Id:    "stream_thinking_tool_call",
+		Model: "qwen3:8b",
+		Config: &v1.GenerationConfig{
+			ReasoningConfig: &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_HIGH},
+		},
+		Messages: []*v1.Message{weatherQuestion()},
+		Tools:    []*v1.Tool{getWeatherTool()},
+	},
+	ChatEvents: streamThinkingToolCallChatEvents(),
+}
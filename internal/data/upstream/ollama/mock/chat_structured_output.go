@@ -0,0 +1,71 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+//go:embed chat_structured_output_request.json
+var structuredOutputRequest []byte
+
+//go:embed chat_structured_output_response.json
+var structuredOutputResponse []byte
+
+// StructuredOutput covers a JSON schema format and an output token limit.
+var StructuredOutput = &Fixture{
+	Name:     "structured_output",
+	Path:     "/api/chat",
+	Request:  structuredOutputRequest,
+	Response: structuredOutputResponse,
+	ChatRequest: &v1.ChatRequest{
+		Id:    "structured_output",
+		Model: "llama3.2:3b",
+		Config: &v1.GenerationConfig{
+			MaxTokens: new(int64(256)),
+			Grammar: &v1.GenerationConfig_Schema{
+				Schema: util.MustStructFromMap(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"city":    map[string]any{"type": "string"},
+						"country": map[string]any{"type": "string"},
+					},
+					"required": []any{"city", "country"},
+				}),
+			},
+		},
+		Messages: []*v1.Message{
+			{
+				Role:     v1.Role_ROLE_USER,
+				Contents: []*v1.Content{{Content: v1.NewTextContent("Describe Shanghai as a JSON object.")}},
+			},
+		},
+	},
+	ChatResponse: &v1.ChatResponse{
+		Id:    "structured_output",
+		Model: "llama3.2:3b",
+		Message: &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{
+				{Content: v1.NewTextContent(`{"city": "Shanghai", "country": "China"}`)},
+			},
+		},
+		Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 33, OutputTokens: 14}},
+		Status:     v1.ChatStatus_CHAT_STATUS_COMPLETED,
+	},
+}
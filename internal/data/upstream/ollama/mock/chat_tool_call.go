@@ -0,0 +1,69 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed chat_tool_call_request.json
+var toolCallRequest []byte
+
+//go:embed chat_tool_call_response.json
+var toolCallResponse []byte
+
+// ToolCall covers a system prompt, disabled thinking and a returned tool call.
+var ToolCall = &Fixture{
+	Name:     "tool_call",
+	Path:     "/api/chat",
+	Request:  toolCallRequest,
+	Response: toolCallResponse,
+	ChatRequest: &v1.ChatRequest{
+		Id:    "tool_call",
+		Model: "qwen3:8b",
+		Config: &v1.GenerationConfig{
+			Temperature:     new(float32(0.2)),
+			ReasoningConfig: &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_NONE},
+		},
+		Messages: []*v1.Message{
+			{
+				Role:     v1.Role_ROLE_SYSTEM,
+				Contents: []*v1.Content{{Content: v1.NewTextContent("You are a weather assistant.")}},
+			},
+			weatherQuestion(),
+		},
+		Tools: []*v1.Tool{getWeatherTool()},
+	},
+	ChatResponse: &v1.ChatResponse{
+		Id:    "tool_call",
+		Model: "qwen3:8b",
+		Message: &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{{
+				Content: &v1.Content_ToolUse{ToolUse: &v1.ToolUse{
+					Id:   "get_weather:tool_call-0",
+					Name: "get_weather",
+					Inputs: []*v1.ToolUse_Input{
+						{Input: &v1.ToolUse_Input_Text{Text: `{"city":"Shanghai","date":"2025-11-10"}`}},
+					},
+				}},
+			}},
+		},
+		Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 212, OutputTokens: 31}},
+		Status:     v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE,
+	},
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed chat_tool_result_request.json
+var toolResultRequest []byte
+
+//go:embed chat_tool_result_response.json
+var toolResultResponse []byte
+
+// ToolResult covers replaying a tool call issued by another provider, whose
+// result is named after the call found earlier in the conversation.
+var ToolResult = &Fixture{
+	Name:     "tool_result",
+	Path:     "/api/chat",
+	Request:  toolResultRequest,
+	Response: toolResultResponse,
+	ChatRequest: &v1.ChatRequest{
+		Id:    "tool_result",
+		Model: "qwen3:8b",
+		Messages: []*v1.Message{
+			weatherQuestion(),
+			{
+				Role: v1.Role_ROLE_MODEL,
+				Contents: []*v1.Content{{
+					Content: &v1.Content_ToolUse{ToolUse: &v1.ToolUse{
+						Id:   "call_6g00pJ6tnrsXQ0o9yILksX7j",
+						Name: "get_weather",
+						Inputs: []*v1.ToolUse_Input{
+							{Input: &v1.ToolUse_Input_Text{Text: `{"city":"Shanghai","date":"2025-11-10"}`}},
+						},
+					}},
+				}},
+			},
+			{
+				Role: v1.Role_ROLE_USER,
+				Contents: []*v1.Content{{
+					Content: &v1.Content_ToolResult{ToolResult: &v1.ToolResult{
+						Id: "call_6g00pJ6tnrsXQ0o9yILksX7j",
+						Outputs: []*v1.ToolResult_Output{
+							{Output: &v1.ToolResult_Output_Text{Text: `{"condition":"sunny","high_c":18}`}},
+						},
+					}},
+				}},
+			},
+		},
+		Tools: []*v1.Tool{getWeatherTool()},
+	},
+	ChatResponse: &v1.ChatResponse{
+		Id:    "tool_result",
+		Model: "qwen3:8b",
+		Message: &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{
+				{Content: v1.NewTextContent("It was sunny in Shanghai on 2025-11-10, with a high of 18°C.")},
+			},
+		},
+		Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 268, OutputTokens: 22}},
+		Status:     v1.ChatStatus_CHAT_STATUS_COMPLETED,
+	},
+}
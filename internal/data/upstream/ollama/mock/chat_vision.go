@@ -0,0 +1,69 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed chat_vision_request.json
+var visionRequest []byte
+
+//go:embed chat_vision_response.json
+var visionResponse []byte
+
+// Vision covers a base64 image input and a thinking effort level.
+var Vision = &Fixture{
+	Name:     "vision",
+	Path:     "/api/chat",
+	Request:  visionRequest,
+	Response: visionResponse,
+	ChatRequest: &v1.ChatRequest{
+		Id:    "vision",
+		Model: "qwen2.5vl:7b",
+		Config: &v1.GenerationConfig{
+			ReasoningConfig: &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_MEDIUM},
+		},
+		Messages: []*v1.Message{
+			{
+				Role: v1.Role_ROLE_USER,
+				Contents: []*v1.Content{
+					{Content: v1.NewTextContent("What color is this pixel?")},
+					{Content: &v1.Content_Image{Image: &v1.Image{
+						MimeType: "image/png",
+						Source: &v1.Image_Base64{
+							Base64: "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8z8DwHwAFBQIAX8jx0gAAAABJRU5ErkJggg==",
+						},
+					}}},
+				},
+			},
+		},
+	},
+	ChatResponse: &v1.ChatResponse{
+		Id:    "vision",
+		Model: "qwen2.5vl:7b",
+		Message: &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{
+				{Phase: v1.ContentPhase_CONTENT_PHASE_REASONING, Content: v1.NewTextContent("The image is a single red pixel.")},
+				{Content: v1.NewTextContent("The pixel is red.")},
+			},
+		},
+		Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 39, OutputTokens: 17}},
+		Status:     v1.ChatStatus_CHAT_STATUS_COMPLETED,
+	},
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed embed_request.json
+var embedRequest []byte
+
+//go:embed embed_response.json
+var embedResponse []byte
+
+// Embed covers a batch of text inputs with reduced dimensions.
+var Embed = &Fixture{
+	Name:     "embed",
+	Path:     "/api/embed",
+	Request:  embedRequest,
+	Response: embedResponse,
+	EmbedRequest: &v1.EmbedRequest{
+		Id:    "embed",
+		Model: "nomic-embed-text",
+		Inputs: []*v1.EmbedInput{
+			{Contents: []*v1.Content{{Content: v1.NewTextContent("Neurouter routes embedding requests.")}}},
+			{Contents: []*v1.Content{{Content: v1.NewTextContent("Ollama runs local models.")}}},
+		},
+		Dimensions: new(uint32(4)),
+	},
+	EmbedResponse: &v1.EmbedResponse{
+		Id:    "embed",
+		Model: "nomic-embed-text",
+		Embeddings: []*v1.EmbeddingVector{
+			{Index: 0, Values: []float32{0.0123, -0.4567, 0.8901, -0.2345}},
+			{Index: 1, Values: []float32{-0.1111, 0.2222, -0.3333, 0.4444}},
+		},
+		Usage: &v1.Usage{InputTokens: 14},
+	},
+}
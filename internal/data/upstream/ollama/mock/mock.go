@@ -0,0 +1,114 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mock holds captured Ollama API request/response pairs together with
+// the neurouter entity values that the ollama upstream conversion must produce
+// for them.
+package mock
+
+import (
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+// Fixture pairs captured Ollama payloads with the neurouter entities that the
+// upstream conversion must produce in both directions.
+type Fixture struct {
+	// Name identifies the fixture in test output.
+	Name string
+	// Path is the API path the request is sent to.
+	Path string
+	// Request is the ground-truth upstream request body.
+	Request []byte
+	// Response is the captured upstream reply: a JSON response for non-stream
+	// fixtures, or NDJSON lines for stream fixtures.
+	Response []byte
+	// Stream reports whether Response is an NDJSON stream that converts into
+	// ChatEvents rather than a single ChatResponse.
+	Stream bool
+	// ChatRequest is the neurouter request that must convert into Request.
+	ChatRequest *v1.ChatRequest
+	// ChatResponse is the expected conversion of Response for non-stream fixtures.
+	ChatResponse *v1.ChatResponse
+	// ChatEvents is the expected conversion of Response for stream fixtures.
+	ChatEvents []*v1.ChatEvent
+	// EmbedRequest is the neurouter request that must convert into Request
+	// for embedding fixtures.
+	EmbedRequest *v1.EmbedRequest
+	// EmbedResponse is the expected conversion of Response for embedding fixtures.
+	EmbedResponse *v1.EmbedResponse
+}
+
+// ChatFixtures is the conversion fixture set for the chat API, aggregated from
+// the per-fixture files in this package.
+var ChatFixtures = []*Fixture{
+	ToolCall,
+	ToolResult,
+	Vision,
+	StructuredOutput,
+	StreamThinkingToolCall,
+	StreamMaxTokens,
+}
+
+// EmbedFixtures is the conversion fixture set for the embed API.
+var EmbedFixtures = []*Fixture{
+	Embed,
+}
+
+// eventBuilder constructs ChatEvents that all carry the same request id.
+type eventBuilder string
+
+func (id eventBuilder) of(payload v1.ChatEventPayload) *v1.ChatEvent {
+	return v1.NewChatEvent(string(id), payload)
+}
+
+func (id eventBuilder) withUsage(payload v1.ChatEventPayload, usage *v1.Usage) *v1.ChatEvent {
+	event := v1.NewChatEvent(string(id), payload)
+	event.Usage = usage
+	return event
+}
+
+// getWeatherTool is the shared tool definition used by the tool fixtures.
+func getWeatherTool() *v1.Tool {
+	return &v1.Tool{
+		Tool: &v1.Tool_Function_{
+			Function: &v1.Tool_Function{
+				Name:        "get_weather",
+				Description: "Look up historical weather for a city on a specific date.",
+				InputSchema: util.MustStructFromMap(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"city": map[string]any{
+							"type":        "string",
+							"description": "City name in English.",
+						},
+						"date": map[string]any{
+							"type":        "string",
+							"description": "Date in YYYY-MM-DD format.",
+						},
+					},
+					"required": []any{"city", "date"},
+				}),
+			},
+		},
+	}
+}
+
+// weatherQuestion is the user message shared by the tool fixtures.
+func weatherQuestion() *v1.Message {
+	return &v1.Message{
+		Role:     v1.Role_ROLE_USER,
+		Contents: []*v1.Content{{Content: v1.NewTextContent("What was the weather in Shanghai on 2025-11-10?")}},
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/data/upstream/ollama/mock"
+)
+
+var mockTestConfig = &conf.OllamaConfig{
+	BaseUrl:   "http://localhost:11434",
+	KeepAlive: "10m",
+	NumCtx:    8192,
+}
+
+// jsonMap unmarshals a JSON document into a map for order-independent comparison.
+func jsonMap(data []byte) map[string]any {
+	var m map[string]any
+	So(json.Unmarshal(data, &m), ShouldBeNil)
+	return m
+}
+
+// mockResponder builds a DoFunc that asserts the outgoing request envelope
+// (method, endpoint and content-type header), records the request body into
+// captured, and replies with the given response content type and body.
+func mockResponder(path, responseContentType string, responseBody []byte, captured *[]byte) func(*http.Request) (*http.Response, error) {
+	return func(httpReq *http.Request) (*http.Response, error) {
+		So(httpReq.Method, ShouldEqual, http.MethodPost)
+		So(httpReq.URL.String(), ShouldEqual, "http://localhost:11434"+path)
+		So(httpReq.Header.Get("Content-Type"), ShouldEqual, "application/json")
+
+		body, err := io.ReadAll(httpReq.Body)
+		So(err, ShouldBeNil)
+		*captured = body
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{responseContentType}},
+			Body:       io.NopCloser(bytes.NewReader(responseBody)),
+		}, nil
+	}
+}
+
+func TestChat(t *testing.T) {
+	Convey("Given the ollama upstream conversion fixtures", t, func() {
+		for _, fixture := range mock.ChatFixtures {
+			if fixture.Stream {
+				continue
+			}
+
+			Convey("When Chat runs the "+fixture.Name+" fixture", func() {
+				mockClient := &mockHTTPClient{}
+				repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+				So(err, ShouldBeNil)
+
+				var capturedBody []byte
+				mockClient.DoFunc = mockResponder(fixture.Path, "application/json", fixture.Response, &capturedBody)
+
+				resp, err := repo.Chat(context.Background(), fixture.ChatRequest)
+				So(err, ShouldBeNil)
+
+				Convey("Then the request body matches the fixture request", func() {
+					So(jsonMap(capturedBody), ShouldResemble, jsonMap(fixture.Request))
+				})
+
+				Convey("Then the response converts to the expected ChatResponse", func() {
+					So(proto.Equal(resp, fixture.ChatResponse), ShouldBeTrue)
+				})
+			})
+		}
+
+		Convey("When the API call fails", func() {
+			mockClient := &mockHTTPClient{
+				DoFunc: func(*http.Request) (*http.Response, error) {
+					return nil, errors.New("network error")
+				},
+			}
+			repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+			So(err, ShouldBeNil)
+
+			_, err = repo.Chat(context.Background(), mock.ToolCall.ChatRequest)
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "network error")
+			})
+		})
+	})
+}
+
+func TestChatStream(t *testing.T) {
+	Convey("Given the ollama upstream conversion fixtures", t, func() {
+		for _, fixture := range mock.ChatFixtures {
+			if !fixture.Stream {
+				continue
+			}
+
+			Convey("When ChatStream runs the "+fixture.Name+" fixture", func() {
+				mockClient := &mockHTTPClient{}
+				repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+				So(err, ShouldBeNil)
+
+				var capturedBody []byte
+				mockClient.DoFunc = mockResponder(fixture.Path, "application/x-ndjson", fixture.Response, &capturedBody)
+
+				seq := repo.ChatStream(context.Background(), fixture.ChatRequest)
+				So(seq, ShouldNotBeNil)
+
+				var events []*entity.ChatEvent
+				for event, err := range seq {
+					So(err, ShouldBeNil)
+					So(event, ShouldNotBeNil)
+					events = append(events, event)
+				}
+
+				Convey("Then the request body matches the fixture request", func() {
+					So(jsonMap(capturedBody), ShouldResemble, jsonMap(fixture.Request))
+				})
+
+				Convey("Then the stream converts to the expected ChatEvents", func() {
+					So(len(events), ShouldEqual, len(fixture.ChatEvents))
+					for i := range events {
+						So(proto.Equal(events[i], fixture.ChatEvents[i]), ShouldBeTrue)
+					}
+				})
+			})
+		}
+
+		Convey("When the stream reports an error", func() {
+			mockClient := &mockHTTPClient{}
+			repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+			So(err, ShouldBeNil)
+
+			var capturedBody []byte
+			mockClient.DoFunc = mockResponder("/api/chat", "application/x-ndjson", []byte(`{"error":"an error was encountered while running the model"}`+"\n"), &capturedBody)
+
+			var lastErr error
+			for _, err := range repo.ChatStream(context.Background(), mock.StreamMaxTokens.ChatRequest) {
+				lastErr = err
+			}
+
+			Convey("Then it should yield the error", func() {
+				So(lastErr, ShouldNotBeNil)
+				So(lastErr.Error(), ShouldContainSubstring, "an error was encountered")
+			})
+		})
+
+		Convey("When the stream ends before it is done", func() {
+			mockClient := &mockHTTPClient{}
+			repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+			So(err, ShouldBeNil)
+
+			var capturedBody []byte
+			mockClient.DoFunc = mockResponder("/api/chat", "application/x-ndjson", []byte(`{"model":"llama3.2:3b","message":{"role":"assistant","content":"Hi"},"done":false}`+"\n"), &capturedBody)
+
+			var events int
+			var lastErr error
+			for event, err := range repo.ChatStream(context.Background(), mock.StreamMaxTokens.ChatRequest) {
+				if event != nil {
+					events++
+				}
+				lastErr = err
+			}
+
+			Convey("Then it should yield an error after the events received", func() {
+				So(events, ShouldBeGreaterThan, 0)
+				So(lastErr, ShouldEqual, io.ErrUnexpectedEOF)
+			})
+		})
+
+		Convey("When the API call fails", func() {
+			mockClient := &mockHTTPClient{
+				DoFunc: func(*http.Request) (*http.Response, error) {
+					return nil, errors.New("network error")
+				},
+			}
+			repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+			So(err, ShouldBeNil)
+
+			seq := repo.ChatStream(context.Background(), mock.StreamMaxTokens.ChatRequest)
+			So(seq, ShouldNotBeNil)
+
+			Convey("Then it should return an error in the iterator", func() {
+				for resp, err := range seq {
+					So(resp, ShouldBeNil)
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldContainSubstring, "network error")
+				}
+			})
+		})
+	})
+}
+
+func TestEmbed(t *testing.T) {
+	Convey("Given the ollama embed fixtures", t, func() {
+		for _, fixture := range mock.EmbedFixtures {
+			Convey("When Embed runs the "+fixture.Name+" fixture", func() {
+				mockClient := &mockHTTPClient{}
+				repo, err := newOllamaUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+				So(err, ShouldBeNil)
+
+				var capturedBody []byte
+				mockClient.DoFunc = mockResponder(fixture.Path, "application/json", fixture.Response, &capturedBody)
+
+				resp, err := repo.Embed(context.Background(), fixture.EmbedRequest)
+				So(err, ShouldBeNil)
+
+				Convey("Then the request body matches the fixture request", func() {
+					So(jsonMap(capturedBody), ShouldResemble, jsonMap(fixture.Request))
+				})
+
+				Convey("Then the response converts to the expected EmbedResponse", func() {
+					So(proto.Equal(resp, fixture.EmbedResponse), ShouldBeTrue)
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,254 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	kerrors "github.com/go-kratos/kratos/v3/errors"
+	otellog "go.opentelemetry.io/otel/log"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/data/upstream/shared"
+)
+
+const defaultBaseURL = "http://localhost:11434"
+
+// HTTPClient sends the requests to the Ollama server.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type upstream struct {
+	config  *conf.OllamaConfig
+	client  HTTPClient
+	baseURL string
+	log     *slog.Logger
+}
+
+func NewOllamaFactory(loggerProvider otellog.LoggerProvider) repository.UpstreamFactory[conf.OllamaConfig] {
+	return func(config *conf.OllamaConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+		client := shared.NewClient(loggerProvider, "neurouter.upstream.ollama", transport)
+		return newOllamaUpstreamWithClient(config, client, logger)
+	}
+}
+
+// newOllamaUpstreamWithClient creates a new Ollama upstream with a custom HTTP client for testing.
+func newOllamaUpstreamWithClient(config *conf.OllamaConfig, client HTTPClient, logger *slog.Logger) (repo *upstream, err error) {
+	baseURL := config.BaseUrl
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	repo = &upstream{
+		config:  config,
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		log:     logger,
+	}
+	return repo, nil
+}
+
+// post sends the request body to the API path, returning the response body
+// of a successful request.
+func (r *upstream) post(ctx context.Context, path string, body any) (io.ReadCloser, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	return r.do(httpReq)
+}
+
+// get requests the API path, returning the response body of a successful
+// request.
+func (r *upstream) get(ctx context.Context, path string) (io.ReadCloser, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.do(httpReq)
+}
+
+// do sends the request with the configured headers.
+func (r *upstream) do(httpReq *http.Request) (io.ReadCloser, error) {
+	for k, v := range r.config.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpResp, err := r.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if httpResp.StatusCode != http.StatusOK {
+		defer httpResp.Body.Close()
+		respBody, _ := io.ReadAll(httpResp.Body)
+		message := string(respBody)
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != "" {
+			message = errResp.Error
+		}
+		return nil, kerrors.New(httpResp.StatusCode, "", message)
+	}
+	return httpResp.Body, nil
+}
+
+func (r *upstream) Chat(ctx context.Context, req *entity.ChatRequest) (*entity.ChatResponse, error) {
+	ollamaReq := r.convertRequestToOllama(req)
+
+	body, err := r.post(ctx, "/api/chat", ollamaReq)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var ollamaResp chatResponse
+	if err = json.NewDecoder(body).Decode(&ollamaResp); err != nil {
+		return nil, err
+	}
+	if ollamaResp.Error != "" {
+		return nil, errors.New(ollamaResp.Error)
+	}
+
+	return convertResponseFromOllama(req, &ollamaResp), nil
+}
+
+func (r *upstream) ChatStream(ctx context.Context, req *entity.ChatRequest) iter.Seq2[*entity.ChatEvent, error] {
+	ollamaReq := r.convertRequestToOllama(req)
+	ollamaReq.Stream = true
+
+	return func(yield func(*entity.ChatEvent, error) bool) {
+		body, err := r.post(ctx, "/api/chat", ollamaReq)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		client := &ollamaChatStreamClient{req: req}
+		reader := bufio.NewReader(body)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(bytes.TrimSpace(line)) > 0 {
+				var chunk chatResponse
+				if err := json.Unmarshal(line, &chunk); err != nil {
+					yield(nil, err)
+					return
+				}
+				if chunk.Error != "" {
+					yield(nil, errors.New(chunk.Error))
+					return
+				}
+				for _, event := range client.convertChunkFromOllama(&chunk) {
+					if !yield(event, nil) {
+						return
+					}
+				}
+			}
+			if err == io.EOF {
+				if !client.done {
+					yield(nil, io.ErrUnexpectedEOF)
+				}
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+func (r *upstream) Embed(ctx context.Context, req *entity.EmbedRequest) (*entity.EmbedResponse, error) {
+	ollamaReq := r.convertRequestToOllamaEmbed(req)
+
+	body, err := r.post(ctx, "/api/embed", ollamaReq)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var ollamaResp embedResponse
+	if err = json.NewDecoder(body).Decode(&ollamaResp); err != nil {
+		return nil, err
+	}
+
+	return convertEmbedResponseFromOllama(req, &ollamaResp), nil
+}
+
+// ListModels lists the local models, with the capabilities and context length
+// reported by /api/show for each.
+func (r *upstream) ListModels(ctx context.Context) ([]*entity.ModelSpec, error) {
+	body, err := r.get(ctx, "/api/tags")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var tags tagsResponse
+	if err = json.NewDecoder(body).Decode(&tags); err != nil {
+		return nil, err
+	}
+
+	specs := make([]*entity.ModelSpec, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		spec := &entity.ModelSpec{Id: m.Name, Owner: "ollama"}
+
+		show, err := r.show(ctx, m.Name)
+		if err != nil {
+			r.log.WarnContext(ctx, "failed to show model", "model", m.Name, "error", err)
+		} else {
+			convertShowResponseFromOllama(show, spec)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// show returns the details of a model.
+func (r *upstream) show(ctx context.Context, model string) (*showResponse, error) {
+	body, err := r.post(ctx, "/api/show", &showRequest{Model: model})
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var resp showResponse
+	if err = json.NewDecoder(body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+var _ repository.ChatRepo = (*upstream)(nil)
+var _ repository.EmbeddingRepo = (*upstream)(nil)
+var _ repository.ModelListingRepo = (*upstream)(nil)
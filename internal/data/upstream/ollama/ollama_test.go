@@ -0,0 +1,182 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	kerrors "github.com/go-kratos/kratos/v3/errors"
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/data/upstream/ollama/mock"
+)
+
+// mockHTTPClient is a mock implementation of HTTPClient for testing.
+type mockHTTPClient struct {
+	DoFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.DoFunc != nil {
+		return m.DoFunc(req)
+	}
+	return nil, errors.New("DoFunc is not set")
+}
+
+func TestNewOllamaUpstream(t *testing.T) {
+	Convey("Given a configuration and logger", t, func() {
+		Convey("When the base URL is empty", func() {
+			repo, err := newOllamaUpstreamWithClient(&conf.OllamaConfig{}, &mockHTTPClient{}, slog.Default())
+
+			Convey("Then it should default to the local Ollama server", func() {
+				So(err, ShouldBeNil)
+				So(repo.baseURL, ShouldEqual, defaultBaseURL)
+			})
+		})
+
+		Convey("When the base URL has a trailing slash", func() {
+			repo, err := newOllamaUpstreamWithClient(&conf.OllamaConfig{BaseUrl: "http://gpu-box:11434/"}, &mockHTTPClient{}, slog.Default())
+
+			Convey("Then it should be trimmed", func() {
+				So(err, ShouldBeNil)
+				So(repo.baseURL, ShouldEqual, "http://gpu-box:11434")
+			})
+		})
+	})
+}
+
+func TestPost(t *testing.T) {
+	Convey("Given an Ollama upstream with custom headers", t, func() {
+		mockClient := &mockHTTPClient{}
+		config := &conf.OllamaConfig{Headers: map[string]string{"Authorization": "Bearer test-key"}}
+		repo, err := newOllamaUpstreamWithClient(config, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		Convey("When the server replies with an error status", func() {
+			mockClient.DoFunc = func(httpReq *http.Request) (*http.Response, error) {
+				So(httpReq.Header.Get("Authorization"), ShouldEqual, "Bearer test-key")
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":"model \"qwen3:8b\" not found, try pulling it first"}`))),
+				}, nil
+			}
+
+			_, err := repo.Chat(context.Background(), mock.ToolCall.ChatRequest)
+
+			Convey("Then it should return the error message with the status code", func() {
+				So(err, ShouldNotBeNil)
+				So(kerrors.Code(err), ShouldEqual, http.StatusNotFound)
+				So(kerrors.FromError(err).Message, ShouldEqual, `model "qwen3:8b" not found, try pulling it first`)
+			})
+		})
+
+		Convey("When the error body is not JSON", func() {
+			mockClient.DoFunc = func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusBadGateway,
+					Body:       io.NopCloser(bytes.NewReader([]byte("bad gateway"))),
+				}, nil
+			}
+
+			_, err := repo.Embed(context.Background(), mock.Embed.EmbedRequest)
+
+			Convey("Then it should return the raw body", func() {
+				So(kerrors.Code(err), ShouldEqual, http.StatusBadGateway)
+				So(kerrors.FromError(err).Message, ShouldEqual, "bad gateway")
+			})
+		})
+	})
+}
+
+func TestListModels(t *testing.T) {
+	Convey("Given an Ollama upstream", t, func() {
+		mockClient := &mockHTTPClient{}
+		repo, err := newOllamaUpstreamWithClient(&conf.OllamaConfig{}, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		reply := func(body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		}
+
+		Convey("When the models are listed", func() {
+			mockClient.DoFunc = func(httpReq *http.Request) (*http.Response, error) {
+				switch httpReq.URL.Path {
+				case "/api/tags":
+					So(httpReq.Method, ShouldEqual, http.MethodGet)
+					return reply(`{"models":[{"name":"qwen3:8b"},{"name":"nomic-embed-text:latest"},{"name":"broken:latest"}]}`)
+				case "/api/show":
+					So(httpReq.Method, ShouldEqual, http.MethodPost)
+					body, _ := io.ReadAll(httpReq.Body)
+					switch string(body) {
+					case `{"model":"qwen3:8b"}`:
+						return reply(`{"capabilities":["completion","tools","vision"],"model_info":{"general.architecture":"qwen3","qwen3.context_length":40960}}`)
+					case `{"model":"nomic-embed-text:latest"}`:
+						return reply(`{"capabilities":["embedding"],"model_info":{"general.architecture":"nomic-bert","nomic-bert.context_length":2048}}`)
+					}
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":"model not found"}`))),
+					}, nil
+				}
+				return nil, errors.New("unexpected path " + httpReq.URL.Path)
+			}
+
+			specs, err := repo.ListModels(context.Background())
+
+			Convey("Then it should return the specs shown by the server", func() {
+				So(err, ShouldBeNil)
+				So(specs, ShouldHaveLength, 3)
+
+				So(specs[0].Id, ShouldEqual, "qwen3:8b")
+				So(specs[0].Owner, ShouldEqual, "ollama")
+				So(specs[0].ContextLength, ShouldEqual, 40960)
+				So(specs[0].Modalities, ShouldResemble, []v1.Modality{v1.Modality_MODALITY_TEXT, v1.Modality_MODALITY_IMAGE})
+				So(specs[0].Capabilities, ShouldResemble, []v1.Capability{v1.Capability_CAPABILITY_CHAT, v1.Capability_CAPABILITY_TOOL_USE})
+
+				So(specs[1].ContextLength, ShouldEqual, 2048)
+				So(specs[1].Capabilities, ShouldResemble, []v1.Capability{v1.Capability_CAPABILITY_EMBEDDING})
+
+				So(specs[2].Id, ShouldEqual, "broken:latest")
+				So(specs[2].Capabilities, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the tags cannot be listed", func() {
+			mockClient.DoFunc = func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Body:       io.NopCloser(bytes.NewReader([]byte("unavailable"))),
+				}, nil
+			}
+
+			_, err := repo.ListModels(context.Background())
+
+			Convey("Then it should return the error", func() {
+				So(kerrors.Code(err), ShouldEqual, http.StatusServiceUnavailable)
+			})
+		})
+	})
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"encoding/json"
+)
+
+// Request and response bodies of the native Ollama API.
+
+type options struct {
+	Temperature      *float32 `json:"temperature,omitempty"`
+	TopP             *float32 `json:"top_p,omitempty"`
+	TopK             *int64   `json:"top_k,omitempty"`
+	NumPredict       *int64   `json:"num_predict,omitempty"`
+	NumCtx           uint32   `json:"num_ctx,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+}
+
+type toolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+type tool struct {
+	Type     string       `json:"type"`
+	Function toolFunction `json:"function"`
+}
+
+type toolCallFunction struct {
+	Index     int             `json:"index,omitempty"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type toolCall struct {
+	Function toolCallFunction `json:"function"`
+}
+
+type message struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	Images    []string   `json:"images,omitempty"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+type chatRequest struct {
+	Model    string    `json:"model"`
+	Messages []message `json:"messages"`
+	Tools    []tool    `json:"tools,omitempty"`
+	// Either "json" or a JSON schema.
+	Format json.RawMessage `json:"format,omitempty"`
+	// Either a boolean or an effort level of "low", "medium" or "high".
+	Think     any      `json:"think,omitempty"`
+	Options   *options `json:"options,omitempty"`
+	Stream    bool     `json:"stream"`
+	KeepAlive string   `json:"keep_alive,omitempty"`
+}
+
+type chatResponse struct {
+	Model           string  `json:"model"`
+	Message         message `json:"message"`
+	Done            bool    `json:"done"`
+	DoneReason      string  `json:"done_reason"`
+	PromptEvalCount int64   `json:"prompt_eval_count"`
+	EvalCount       int64   `json:"eval_count"`
+	Error           string  `json:"error"`
+}
+
+type embedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions uint32   `json:"dimensions,omitempty"`
+	Options    *options `json:"options,omitempty"`
+	KeepAlive  string   `json:"keep_alive,omitempty"`
+}
+
+type embedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int64       `json:"prompt_eval_count"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type tagsModel struct {
+	Name string `json:"name"`
+}
+
+type tagsResponse struct {
+	Models []tagsModel `json:"models"`
+}
+
+type showRequest struct {
+	Model string `json:"model"`
+}
+
+type showResponse struct {
+	Capabilities []string       `json:"capabilities"`
+	ModelInfo    map[string]any `json:"model_info"`
+}
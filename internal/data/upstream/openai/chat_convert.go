@@ -15,6 +15,7 @@
 package openai
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/openai/openai-go/v3"
@@ -59,10 +60,47 @@ func (r *upstream) convertRequestToOpenAIChat(req *entity.ChatRequest) openai.Ch
 		}
 		openAIReq.Tools = tools
 	}
+	// OpenAI rejects tool choices for requests without tools
+	if len(openAIReq.Tools) > 0 && req.ToolChoice != nil {
+		convertToolChoiceToOpenAIChat(req.ToolChoice, &openAIReq)
+	}
 
 	return openAIReq
 }
 
+func convertToolChoiceToOpenAIChat(toolChoice *v1.ToolChoice, req *openai.ChatCompletionNewParams) {
+	switch toolChoice.Mode {
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO:
+		req.ToolChoice.OfAuto = openai.Opt(string(openai.ChatCompletionToolChoiceOptionAutoAuto))
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+		req.ToolChoice.OfAuto = openai.Opt(string(openai.ChatCompletionToolChoiceOptionAutoNone))
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED:
+		req.ToolChoice.OfAuto = openai.Opt(string(openai.ChatCompletionToolChoiceOptionAutoRequired))
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		req.ToolChoice.OfFunctionToolChoice = &openai.ChatCompletionNamedToolChoiceParam{
+			Function: openai.ChatCompletionNamedToolChoiceFunctionParam{Name: toolChoice.Name},
+		}
+	}
+	if names := toolChoice.AllowedToolNames(); len(names) > 0 {
+		mode := openai.ChatCompletionAllowedToolsModeAuto
+		if toolChoice.Mode == v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED {
+			mode = openai.ChatCompletionAllowedToolsModeRequired
+		}
+		tools := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			tools = append(tools, map[string]any{"type": "function", "function": map[string]any{"name": name}})
+		}
+		req.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{
+			OfAllowedTools: &openai.ChatCompletionAllowedToolChoiceParam{
+				AllowedTools: openai.ChatCompletionAllowedToolsParam{Mode: mode, Tools: tools},
+			},
+		}
+	}
+	if toolChoice.ParallelToolCalls != nil {
+		req.ParallelToolCalls = openai.Opt(toolChoice.GetParallelToolCalls())
+	}
+}
+
 func convertGenerationConfigToOpenAIChat(config *v1.GenerationConfig, req *openai.ChatCompletionNewParams) {
 	if config == nil {
 		return
@@ -312,6 +350,12 @@ func (r *upstream) convertResponseFromOpenAIChat(req *entity.ChatRequest, openAI
 		resp.Status = convertStatusFromOpenAIChat(openAIResp.Choices[0].FinishReason)
 		resp.Message = r.convertMessageFromOpenAIChat(&openAIResp.Choices[0].Message)
 		resp.Message.Id = openAIResp.ID
+
+		// Tool calls parsed from the text end a turn the upstream considers complete
+		if resp.Status == v1.ChatStatus_CHAT_STATUS_COMPLETED &&
+			slices.ContainsFunc(resp.Message.Contents, func(c *v1.Content) bool { return c.GetToolUse() != nil }) {
+			resp.Status = v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
+		}
 	}
 
 	return
@@ -323,9 +367,14 @@ func (r *upstream) convertMessageFromOpenAIChat(openAIMessage *openai.ChatComple
 	}
 
 	if openAIMessage.Content != "" {
-		message.Contents = append(message.Contents, &v1.Content{
-			Content: v1.NewTextContent(openAIMessage.Content),
-		})
+		if inline := newInlineOutputParser(r.config.GetInlineOutputParser()); inline != nil {
+			outputs := append(inline.feed(openAIMessage.Content), inline.flush()...)
+			message.Contents = append(message.Contents, convertInlineOutputsToContents(outputs)...)
+		} else {
+			message.Contents = append(message.Contents, &v1.Content{
+				Content: v1.NewTextContent(openAIMessage.Content),
+			})
+		}
 	}
 
 	// Support reasoning
@@ -394,8 +443,12 @@ func (c *openAIChatStreamClient) convertStreamChunkFromOpenAIChat(chunk *openai.
 		}
 
 		if msg.Delta.Content != "" {
-			index := c.openTextBlock(&events, v1.ContentPhase_CONTENT_PHASE_NORMAL)
-			events = append(events, c.newChatEvent(v1.NewContentDeltaTextEvent(index, msg.Delta.Content)))
+			if c.inline != nil {
+				c.appendInlineOutputs(&events, c.inline.feed(msg.Delta.Content))
+			} else {
+				index := c.openTextBlock(&events, v1.ContentPhase_CONTENT_PHASE_NORMAL)
+				events = append(events, c.newChatEvent(v1.NewContentDeltaTextEvent(index, msg.Delta.Content)))
+			}
 		}
 
 		for _, toolCall := range msg.Delta.ToolCalls {
@@ -411,6 +464,7 @@ func (c *openAIChatStreamClient) convertStreamChunkFromOpenAIChat(chunk *openai.
 	}
 
 	if statistics := convertStatisticsFromOpenAIChat(&chunk.Usage); statistics != nil {
+		c.flushInline(&events)
 		c.closeOpenBlock(&events)
 		stop := c.newChatEvent(v1.NewMessageStopEvent(c.status))
 		stop.Usage = statistics.Usage
@@ -428,12 +482,47 @@ func (c *openAIChatStreamClient) finish() []*entity.ChatEvent {
 	}
 
 	var events []*entity.ChatEvent
+	c.flushInline(&events)
 	c.closeOpenBlock(&events)
 	events = append(events, c.newChatEvent(v1.NewMessageStopEvent(c.status)))
 	c.stopEmitted = true
 	return events
 }
 
+// appendInlineOutputs streams the reasoning and tool calls parsed from the
+// text content. Tool calls are sent whole once their closing tag arrives.
+func (c *openAIChatStreamClient) appendInlineOutputs(events *[]*entity.ChatEvent, outputs []inlineOutput) {
+	for _, o := range outputs {
+		if o.toolUse == nil {
+			index := c.openTextBlock(events, o.phase)
+			*events = append(*events, c.newChatEvent(v1.NewContentDeltaTextEvent(index, o.text)))
+			continue
+		}
+
+		c.closeOpenBlock(events)
+		index := c.nextIndex
+		c.nextIndex++
+		*events = append(*events,
+			c.newChatEvent(v1.NewContentStartToolUseEvent(index, o.toolUse.Id, o.toolUse.Name)),
+			c.newChatEvent(v1.NewContentDeltaToolInputTextEvent(index, o.toolUse.GetTextualInput())),
+			c.newChatEvent(v1.NewContentStopEvent(index)),
+		)
+		c.inlineToolUse = true
+	}
+}
+
+// flushInline streams the text content held back by the inline parser, and
+// ends the turn awaiting the tool calls it parsed.
+func (c *openAIChatStreamClient) flushInline(events *[]*entity.ChatEvent) {
+	if c.inline == nil {
+		return
+	}
+	c.appendInlineOutputs(events, c.inline.flush())
+	if c.inlineToolUse && c.status == v1.ChatStatus_CHAT_STATUS_COMPLETED {
+		c.status = v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
+	}
+}
+
 func (c *openAIChatStreamClient) newChatEvent(event v1.ChatEventPayload) *entity.ChatEvent {
 	return v1.NewChatEvent(c.req.GetId(), event)
 }
@@ -15,13 +15,17 @@
 package openai
 
 import (
+	"encoding/json"
 	"log/slog"
+	"slices"
 	"testing"
 
 	"github.com/openai/openai-go/v3"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tidwall/gjson"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/chat"
 	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/conf"
 	"github.com/neuraxes/neurouter/internal/util"
@@ -745,6 +749,26 @@ func TestConvertMessageFromOpenAIChat(t *testing.T) {
 			So(msg.Contents[0].GetToolUse().GetTextualInput(), ShouldEqual, `{"arg1":"value1"}`)
 		})
 
+		Convey("with inline reasoning and tool calls", func() {
+			repo.config = &conf.OpenAIConfig{
+				InlineOutputParser: &conf.InlineOutputParser{ThinkTags: true, ToolCallTags: true},
+			}
+			defer func() { repo.config = &conf.OpenAIConfig{} }()
+			openAIMsg := &openai.ChatCompletionMessage{
+				Content: "<think>\nThe user asks for weather.\n</think>\n\nChecking.\n" +
+					"<tool_call>\n{\"name\": \"get_weather\", \"arguments\": {\"city\": \"Paris\"}}\n</tool_call>",
+			}
+
+			msg := repo.convertMessageFromOpenAIChat(openAIMsg)
+			So(msg.Contents, ShouldHaveLength, 3)
+			So(msg.Contents[0].Phase, ShouldEqual, v1.ContentPhase_CONTENT_PHASE_REASONING)
+			So(msg.Contents[0].GetText().GetText(), ShouldEqual, "The user asks for weather.\n")
+			So(msg.Contents[1].Phase, ShouldEqual, v1.ContentPhase_CONTENT_PHASE_NORMAL)
+			So(msg.Contents[1].GetText().GetText(), ShouldEqual, "Checking.\n")
+			So(msg.Contents[2].GetToolUse().GetName(), ShouldEqual, "get_weather")
+			So(msg.Contents[2].GetToolUse().GetTextualInput(), ShouldEqual, `{"city": "Paris"}`)
+		})
+
 		Convey("with empty content and no tool calls", func() {
 			openAIMsg := &openai.ChatCompletionMessage{
 				Content:   "",
@@ -818,6 +842,41 @@ func TestConvertStreamChunkFromOpenAIChat(t *testing.T) {
 			So(last.GetUsage().GetOutputTokens(), ShouldEqual, 10)
 		})
 
+		Convey("with inline output split across chunks", func() {
+			client := &openAIChatStreamClient{
+				inline: newInlineOutputParser(&conf.InlineOutputParser{ThinkOpened: true, ToolCallTags: true}),
+			}
+			content := "Weather needed.</think>\n\nOK <tool_call>{\"name\": \"get_weather\", \"arguments\": {}}</tool_call>"
+
+			reducer := chat.NewChatEventReducer(slog.Default())
+			for chunk := range slices.Chunk([]byte(content), 4) {
+				for _, event := range client.convertStreamChunkFromOpenAIChat(&openai.ChatCompletionChunk{
+					ID: "chatcmpl-1",
+					Choices: []openai.ChatCompletionChunkChoice{
+						{Delta: openai.ChatCompletionChunkChoiceDelta{Content: string(chunk)}},
+					},
+				}) {
+					reducer.Reduce(event)
+				}
+			}
+			for _, event := range client.convertStreamChunkFromOpenAIChat(&openai.ChatCompletionChunk{
+				ID:      "chatcmpl-1",
+				Choices: []openai.ChatCompletionChunkChoice{{FinishReason: "stop"}},
+				Usage:   openai.CompletionUsage{PromptTokens: 5, CompletionTokens: 10},
+			}) {
+				reducer.Reduce(event)
+			}
+
+			resp := reducer.Resp()
+			So(resp.Status, ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
+			So(resp.Message.Contents, ShouldHaveLength, 3)
+			So(resp.Message.Contents[0].Phase, ShouldEqual, v1.ContentPhase_CONTENT_PHASE_REASONING)
+			So(resp.Message.Contents[0].GetText().GetText(), ShouldEqual, "Weather needed.")
+			So(resp.Message.Contents[1].GetText().GetText(), ShouldEqual, "OK ")
+			So(resp.Message.Contents[2].GetToolUse().GetName(), ShouldEqual, "get_weather")
+			So(resp.Message.Contents[2].GetToolUse().GetTextualInput(), ShouldEqual, "{}")
+		})
+
 		Convey("with function tool call", func() {
 			client := &openAIChatStreamClient{}
 			chunk := &openai.ChatCompletionChunk{
@@ -935,3 +994,69 @@ func TestConvertStatisticsFromOpenAIChat(t *testing.T) {
 		})
 	})
 }
+
+func TestConvertToolChoiceToOpenAIChat(t *testing.T) {
+	repo := &upstream{
+		config: &conf.OpenAIConfig{},
+		log:    slog.Default(),
+	}
+	tools := []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_weather"}}}}
+
+	Convey("Test convertToolChoiceToOpenAIChat", t, func() {
+		marshal := func(req *entity.ChatRequest) string {
+			body, err := json.Marshal(repo.convertRequestToOpenAIChat(req))
+			So(err, ShouldBeNil)
+			return string(body)
+		}
+
+		Convey("should force a specific function without parallel calls", func() {
+			body := marshal(&entity.ChatRequest{
+				Model: "gpt-4",
+				Tools: tools,
+				ToolChoice: &v1.ToolChoice{
+					Mode:              v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL,
+					Name:              "get_weather",
+					ParallelToolCalls: new(false),
+				},
+			})
+			So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "function")
+			So(gjson.Get(body, "tool_choice.function.name").String(), ShouldEqual, "get_weather")
+			So(gjson.Get(body, "parallel_tool_calls").IsBool(), ShouldBeTrue)
+			So(gjson.Get(body, "parallel_tool_calls").Bool(), ShouldBeFalse)
+		})
+
+		Convey("should convert modes", func() {
+			body := marshal(&entity.ChatRequest{
+				Model:      "gpt-4",
+				Tools:      tools,
+				ToolChoice: &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED},
+			})
+			So(gjson.Get(body, "tool_choice").String(), ShouldEqual, "required")
+			So(gjson.Get(body, "parallel_tool_calls").Exists(), ShouldBeFalse)
+		})
+
+		Convey("should restrict the allowed tools", func() {
+			body := marshal(&entity.ChatRequest{
+				Model: "gpt-4",
+				Tools: tools,
+				ToolChoice: &v1.ToolChoice{
+					Mode:         v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED,
+					AllowedNames: []string{"get_weather"},
+				},
+			})
+			So(gjson.Get(body, "tool_choice.type").String(), ShouldEqual, "allowed_tools")
+			So(gjson.Get(body, "tool_choice.allowed_tools.mode").String(), ShouldEqual, "required")
+			So(gjson.Get(body, "tool_choice.allowed_tools.tools").Raw, ShouldEqual,
+				`[{"function":{"name":"get_weather"},"type":"function"}]`)
+		})
+
+		Convey("should omit the tool choice without tools", func() {
+			body := marshal(&entity.ChatRequest{
+				Model:      "gpt-4",
+				ToolChoice: &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE, ParallelToolCalls: new(true)},
+			})
+			So(gjson.Get(body, "tool_choice").Exists(), ShouldBeFalse)
+			So(gjson.Get(body, "parallel_tool_calls").Exists(), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"strings"
+
+	"github.com/openai/openai-go/v3"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// convertContentsToOpenAICompletion concatenates the text of the contents, as
+// completion prompts are plain text.
+func (r *upstream) convertContentsToOpenAICompletion(contents []*v1.Content) string {
+	var sb strings.Builder
+	for _, content := range contents {
+		switch c := content.Content.(type) {
+		case *v1.Content_Text:
+			sb.WriteString(c.Text.GetText())
+		default:
+			r.log.Error("unsupported completion content", "content", c)
+		}
+	}
+	return sb.String()
+}
+
+func (r *upstream) convertRequestToOpenAICompletion(req *entity.CompleteRequest) openai.CompletionNewParams {
+	openAIReq := openai.CompletionNewParams{
+		Model: openai.CompletionNewParamsModel(req.Model),
+		Prompt: openai.CompletionNewParamsPromptUnion{
+			OfString: openai.Opt(r.convertContentsToOpenAICompletion(req.Contents)),
+		},
+	}
+	if len(req.Suffix) > 0 {
+		openAIReq.Suffix = openai.Opt(r.convertContentsToOpenAICompletion(req.Suffix))
+	}
+
+	if config := req.Config; config != nil {
+		if config.MaxTokens != nil {
+			openAIReq.MaxTokens = openai.Opt(*config.MaxTokens)
+		}
+		if config.Temperature != nil {
+			openAIReq.Temperature = openai.Opt(float64(*config.Temperature))
+		}
+		if config.TopP != nil {
+			openAIReq.TopP = openai.Opt(float64(*config.TopP))
+		}
+		if config.FrequencyPenalty != nil {
+			openAIReq.FrequencyPenalty = openai.Opt(float64(*config.FrequencyPenalty))
+		}
+		if config.PresencePenalty != nil {
+			openAIReq.PresencePenalty = openai.Opt(float64(*config.PresencePenalty))
+		}
+		if len(config.StopSequences) > 0 {
+			openAIReq.Stop = openai.CompletionNewParamsStopUnion{OfStringArray: config.StopSequences}
+		}
+	}
+	return openAIReq
+}
+
+func convertResponseFromOpenAICompletion(req *entity.CompleteRequest, openAIResp *openai.Completion) *entity.CompleteResponse {
+	resp := &entity.CompleteResponse{
+		Id:         req.Id,
+		Model:      openAIResp.Model,
+		Statistics: convertStatisticsFromOpenAIChat(&openAIResp.Usage),
+	}
+	if len(openAIResp.Choices) > 0 {
+		choice := openAIResp.Choices[0]
+		if choice.Text != "" {
+			resp.Contents = []*v1.Content{{Content: v1.NewTextContent(choice.Text)}}
+		}
+		resp.Status = convertStatusFromOpenAIChat(string(choice.FinishReason))
+	}
+	return resp
+}
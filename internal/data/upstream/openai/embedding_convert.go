@@ -0,0 +1,84 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"strings"
+
+	"github.com/openai/openai-go/v3"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// maxEmbeddingBatchSize is the maximum number of inputs of an embeddings request.
+const maxEmbeddingBatchSize = 2048
+
+func (r *upstream) MaxEmbeddingBatchSize() int { return maxEmbeddingBatchSize }
+
+func (r *upstream) convertRequestToOpenAIEmbedding(req *entity.EmbedRequest) openai.EmbeddingNewParams {
+	// The contents of an input are joined into a single string
+	inputs := make([]string, 0, len(req.Inputs))
+	for _, input := range req.Inputs {
+		var texts []string
+		for _, content := range input.Contents {
+			switch c := content.Content.(type) {
+			case *v1.Content_Text:
+				texts = append(texts, c.Text.GetText())
+			default:
+				r.log.Error("unsupported embedding content", "content", c)
+			}
+		}
+		inputs = append(inputs, strings.Join(texts, "\n"))
+	}
+
+	openAIReq := openai.EmbeddingNewParams{
+		Model: req.Model,
+		Input: openai.EmbeddingNewParamsInputUnion{
+			OfArrayOfStrings: inputs,
+		},
+		// Vectors are always fetched as floats, as they may be truncated and
+		// normalized; the server encodes them as base64 if the client asked
+		EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+	}
+	if req.Dimensions != nil {
+		openAIReq.Dimensions = openai.Opt(int64(*req.Dimensions))
+	}
+	return openAIReq
+}
+
+func convertResponseFromOpenAIEmbedding(req *entity.EmbedRequest, resp *openai.CreateEmbeddingResponse) *entity.EmbedResponse {
+	embedResp := &entity.EmbedResponse{
+		Id:         req.Id,
+		Model:      resp.Model,
+		Embeddings: make([]*v1.EmbeddingVector, 0, len(resp.Data)),
+	}
+	for _, data := range resp.Data {
+		values := make([]float32, len(data.Embedding))
+		for i, v := range data.Embedding {
+			values[i] = float32(v)
+		}
+		embedResp.Embeddings = append(embedResp.Embeddings, &v1.EmbeddingVector{
+			Index:  uint32(data.Index),
+			Values: values,
+		})
+	}
+	if resp.Usage.PromptTokens > 0 {
+		embedResp.Usage = &v1.Usage{
+			InputTokens: uint32(resp.Usage.PromptTokens),
+		}
+	}
+	return embedResp
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"strings"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/conf"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+const thinkTag = "think"
+
+// inlineOutput is a piece of the text content: text of a phase, or a tool use.
+type inlineOutput struct {
+	phase   v1.ContentPhase
+	text    string
+	toolUse *v1.ToolUse
+}
+
+// inlineOutputParser extracts the reasoning and tool calls that models write
+// inline in the text content, when the engine serving them parses neither.
+// The content may be fed in chunks split anywhere, tags included.
+type inlineOutputParser struct {
+	tags *util.TagParser
+	// Set at the start of a piece, where whitespace around tags is dropped
+	fresh bool
+	call  strings.Builder
+}
+
+// newInlineOutputParser returns nil unless the config enables parsing.
+func newInlineOutputParser(config *conf.InlineOutputParser) *inlineOutputParser {
+	var tags []string
+	if config.GetThinkTags() || config.GetThinkOpened() {
+		tags = append(tags, thinkTag)
+	}
+	if config.GetToolCallTags() {
+		tags = append(tags, util.ToolCallTag)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	p := &inlineOutputParser{tags: util.NewTagParser(tags...), fresh: true}
+	if config.GetThinkOpened() {
+		p.tags.Open(thinkTag)
+	}
+	return p
+}
+
+func (p *inlineOutputParser) feed(text string) []inlineOutput {
+	return p.convert(p.tags.Feed(text))
+}
+
+// flush returns what is held back at the end of the content.
+func (p *inlineOutputParser) flush() []inlineOutput {
+	return p.convert(p.tags.Flush())
+}
+
+func (p *inlineOutputParser) convert(events []util.TagEvent) (outputs []inlineOutput) {
+	for _, e := range events {
+		switch e.Kind {
+		case util.TagEventOpen:
+			p.fresh = true
+			p.call.Reset()
+		case util.TagEventText:
+			if e.Tag == util.ToolCallTag {
+				p.call.WriteString(e.Text)
+				continue
+			}
+			phase := v1.ContentPhase_CONTENT_PHASE_NORMAL
+			if e.Tag == thinkTag {
+				phase = v1.ContentPhase_CONTENT_PHASE_REASONING
+			}
+			outputs = p.appendText(outputs, phase, e.Text)
+		case util.TagEventClose:
+			if e.Tag == util.ToolCallTag {
+				if toolUse, ok := util.ParseHermesToolCall(p.call.String()); ok {
+					outputs = append(outputs, inlineOutput{toolUse: toolUse})
+				} else {
+					// Not a call after all, so the model meant the text as is
+					outputs = p.appendText(outputs, v1.ContentPhase_CONTENT_PHASE_NORMAL,
+						"<"+util.ToolCallTag+">"+p.call.String()+"</"+util.ToolCallTag+">")
+				}
+			}
+			p.fresh = true
+		}
+	}
+	return
+}
+
+func (p *inlineOutputParser) appendText(outputs []inlineOutput, phase v1.ContentPhase, text string) []inlineOutput {
+	if p.fresh {
+		text = strings.TrimLeft(text, " \t\r\n")
+		if text == "" {
+			return outputs
+		}
+		p.fresh = false
+	}
+	return append(outputs, inlineOutput{phase: phase, text: text})
+}
+
+// convertInlineOutputsToContents merges the pieces of a whole content.
+func convertInlineOutputsToContents(outputs []inlineOutput) (contents []*v1.Content) {
+	for _, o := range outputs {
+		if o.toolUse != nil {
+			contents = append(contents, &v1.Content{Content: &v1.Content_ToolUse{ToolUse: o.toolUse}})
+			continue
+		}
+		if n := len(contents); n > 0 && contents[n-1].GetText() != nil && contents[n-1].Phase == o.phase {
+			contents[n-1].GetText().Text += o.text
+			continue
+		}
+		contents = append(contents, &v1.Content{Phase: o.phase, Content: v1.NewTextContent(o.text)})
+	}
+	return
+}
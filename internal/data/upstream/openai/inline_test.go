@@ -0,0 +1,55 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestInlineOutputParser(t *testing.T) {
+	Convey("Test inlineOutputParser", t, func() {
+		Convey("should be disabled by default", func() {
+			So(newInlineOutputParser(nil), ShouldBeNil)
+			So(newInlineOutputParser(&conf.InlineOutputParser{}), ShouldBeNil)
+		})
+
+		Convey("should only parse the enabled tags", func() {
+			p := newInlineOutputParser(&conf.InlineOutputParser{ToolCallTags: true})
+			contents := convertInlineOutputsToContents(append(p.feed("<think>hmm</think>"), p.flush()...))
+			So(contents, ShouldHaveLength, 1)
+			So(contents[0].Phase, ShouldEqual, v1.ContentPhase_CONTENT_PHASE_NORMAL)
+			So(contents[0].GetText().GetText(), ShouldEqual, "<think>hmm</think>")
+		})
+
+		Convey("should keep malformed tool calls as text", func() {
+			p := newInlineOutputParser(&conf.InlineOutputParser{ToolCallTags: true})
+			contents := convertInlineOutputsToContents(append(p.feed("See <tool_call>oops</tool_call>"), p.flush()...))
+			So(contents, ShouldHaveLength, 1)
+			So(contents[0].GetText().GetText(), ShouldEqual, "See <tool_call>oops</tool_call>")
+		})
+
+		Convey("should treat an unclosed think as reasoning", func() {
+			p := newInlineOutputParser(&conf.InlineOutputParser{ThinkTags: true})
+			contents := convertInlineOutputsToContents(append(p.feed("<think>still thinking"), p.flush()...))
+			So(contents, ShouldHaveLength, 1)
+			So(contents[0].Phase, ShouldEqual, v1.ContentPhase_CONTENT_PHASE_REASONING)
+		})
+	})
+}
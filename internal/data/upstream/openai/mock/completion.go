@@ -0,0 +1,97 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed completion_request.json
+var completionRequest []byte
+
+//go:embed completion_response.json
+var completionResponse []byte
+
+//go:embed completion_stream_request.json
+var completionStreamRequest []byte
+
+//go:embed completion_stream_response.txt
+var completionStreamResponse []byte
+
+// Completion covers a fill-in-the-middle completion with a stop sequence.
+var Completion = &Fixture{
+	Name:     "completion",
+	Request:  completionRequest,
+	Response: completionResponse,
+	CompleteRequest: &v1.CompleteRequest{
+		Id:    "completion",
+		Model: "gpt-3.5-turbo-instruct",
+		Config: &v1.GenerationConfig{
+			MaxTokens:     new(int64(32)),
+			Temperature:   new(float32(0)),
+			StopSequences: []string{"\n\n"},
+		},
+		Contents: []*v1.Content{{Content: v1.NewTextContent("def fibonacci(n):")}},
+		Suffix:   []*v1.Content{{Content: v1.NewTextContent("\n\nprint(fibonacci(10))")}},
+	},
+	CompleteResponses: []*v1.CompleteResponse{
+		{
+			Id:    "completion",
+			Model: "gpt-3.5-turbo-instruct",
+			Contents: []*v1.Content{{Content: v1.NewTextContent(
+				"\n    if n < 2:\n        return n\n    return fibonacci(n - 1) + fibonacci(n - 2)",
+			)}},
+			Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 12, OutputTokens: 27}},
+			Status:     v1.ChatStatus_CHAT_STATUS_COMPLETED,
+		},
+	},
+}
+
+// CompletionStream covers a streamed completion cut off by the token limit,
+// with the usage reported in a trailing chunk.
+var CompletionStream = &Fixture{
+	Name:     "completion_stream",
+	Request:  completionStreamRequest,
+	Response: completionStreamResponse,
+	Stream:   true,
+	CompleteRequest: &v1.CompleteRequest{
+		Id:       "completion_stream",
+		Model:    "gpt-3.5-turbo-instruct",
+		Config:   &v1.GenerationConfig{MaxTokens: new(int64(4))},
+		Contents: []*v1.Content{{Content: v1.NewTextContent("Once upon a time")}},
+	},
+	CompleteResponses: []*v1.CompleteResponse{
+		completionStreamChunk(","),
+		completionStreamChunk(" there"),
+		completionStreamChunk(" was"),
+		completionStreamChunk(" a"),
+		{
+			Id:         "completion_stream",
+			Model:      "gpt-3.5-turbo-instruct",
+			Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 4, OutputTokens: 4}},
+			Status:     v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT,
+		},
+	},
+}
+
+func completionStreamChunk(text string) *v1.CompleteResponse {
+	return &v1.CompleteResponse{
+		Id:       "completion_stream",
+		Model:    "gpt-3.5-turbo-instruct",
+		Contents: []*v1.Content{{Content: v1.NewTextContent(text)}},
+	}
+}
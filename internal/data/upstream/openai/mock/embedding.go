@@ -0,0 +1,80 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mock
+
+import (
+	_ "embed"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+//go:embed embedding_request.json
+var embeddingRequest []byte
+
+//go:embed embedding_response.json
+var embeddingResponse []byte
+
+//go:embed embedding_batch_request.json
+var embeddingBatchRequest []byte
+
+//go:embed embedding_batch_response.json
+var embeddingBatchResponse []byte
+
+// Embedding covers a shortened float embedding of a single text input.
+var Embedding = &Fixture{
+	Name:     "embedding",
+	Request:  embeddingRequest,
+	Response: embeddingResponse,
+	EmbedRequest: &v1.EmbedRequest{
+		Id:    "embedding",
+		Model: "text-embedding-3-small",
+		Inputs: []*v1.EmbedInput{
+			{Contents: []*v1.Content{{Content: v1.NewTextContent("Neurouter routes embedding requests.")}}},
+		},
+		Dimensions: new(uint32(4)),
+	},
+	EmbedResponse: &v1.EmbedResponse{
+		Id:    "embedding",
+		Model: "text-embedding-3-small",
+		Embeddings: []*v1.EmbeddingVector{
+			{Index: 0, Values: []float32{0.0123, -0.4567, 0.8901, -0.2345}},
+		},
+		Usage: &v1.Usage{InputTokens: 6},
+	},
+}
+
+// EmbeddingBatch covers multiple inputs embedded in one call.
+var EmbeddingBatch = &Fixture{
+	Name:     "embedding_batch",
+	Request:  embeddingBatchRequest,
+	Response: embeddingBatchResponse,
+	EmbedRequest: &v1.EmbedRequest{
+		Id:    "embedding_batch",
+		Model: "text-embedding-3-small",
+		Inputs: []*v1.EmbedInput{
+			{Contents: []*v1.Content{{Content: v1.NewTextContent("First input.")}}},
+			{Contents: []*v1.Content{{Content: v1.NewTextContent("Second input.")}}},
+		},
+	},
+	EmbedResponse: &v1.EmbedResponse{
+		Id:    "embedding_batch",
+		Model: "text-embedding-3-small",
+		Embeddings: []*v1.EmbeddingVector{
+			{Index: 0, Values: []float32{0.1, 0.2, 0.3}},
+			{Index: 1, Values: []float32{0.4, 0.5, 0.6}},
+		},
+		Usage: &v1.Usage{InputTokens: 7},
+	},
+}
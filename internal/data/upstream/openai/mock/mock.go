@@ -41,6 +41,17 @@ type Fixture struct {
 	ChatResponse *v1.ChatResponse
 	// ChatEvents is the expected conversion of Response for stream fixtures.
 	ChatEvents []*v1.ChatEvent
+	// EmbedRequest is the neurouter request that must convert into Request
+	// for embedding fixtures.
+	EmbedRequest *v1.EmbedRequest
+	// EmbedResponse is the expected conversion of Response for embedding fixtures.
+	EmbedResponse *v1.EmbedResponse
+	// CompleteRequest is the neurouter request that must convert into Request
+	// for completion fixtures.
+	CompleteRequest *v1.CompleteRequest
+	// CompleteResponses is the expected conversion of Response for completion
+	// fixtures: a single response, or every chunk of a stream.
+	CompleteResponses []*v1.CompleteResponse
 }
 
 // ChatCompletionFixtures is the conversion fixture set for the Chat Completions
@@ -69,6 +80,18 @@ var ResponsesFixtures = []*Fixture{
 	ResponsesStreamToolCall,
 }
 
+// EmbeddingFixtures is the conversion fixture set for the Embeddings API.
+var EmbeddingFixtures = []*Fixture{
+	Embedding,
+	EmbeddingBatch,
+}
+
+// CompletionFixtures is the conversion fixture set for the legacy Completions API.
+var CompletionFixtures = []*Fixture{
+	Completion,
+	CompletionStream,
+}
+
 // eventBuilder constructs ChatEvents that all carry the same request id.
 type eventBuilder string
 
@@ -34,7 +34,7 @@ func responsesStreamReasoningChatEvents() []*v1.ChatEvent {
 	id := eventBuilder("responses_stream_reasoning")
 	return []*v1.ChatEvent{
 		id.of(v1.NewMessageStartEvent("gen-1785502372-uGZiYp65emwVHnQiUmkC", "openai/gpt-5-mini")),
-		id.of(v1.NewIdentifiedContentStartTextEvent(responsesStreamReasoningItemID, 0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+		id.of(v1.NewIdentifiedContentStartTextEvent(responsesStreamReasoningItemID, 0, v1.ContentPhase_CONTENT_PHASE_REASONING).WithProvenance(v1.Provenance_PROVENANCE_OPENAI)),
 		id.of(v1.NewContentDeltaTextEvent(0, "**Calculating request distribution**\n\nI")),
 		id.of(v1.NewContentDeltaTextEvent(0, " need")),
 		id.of(v1.NewContentDeltaTextEvent(0, " to")),
@@ -220,9 +220,10 @@ func responsesStreamReasoningChatEvents() []*v1.ChatEvent {
 		id.of(v1.NewContentStopEvent(0)),
 		id.of(v1.NewContentSnapshotEvent(
 			&v1.Content{
-				Id:    responsesStreamReasoningItemID,
-				Index: new(uint32(1)),
-				Phase: v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Id:         responsesStreamReasoningItemID,
+				Index:      new(uint32(1)),
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: v1.Provenance_PROVENANCE_OPENAI,
 				Content: &v1.Content_Opaque{
 					Opaque: "gAAAAABqbJqs-OOLolAwA9rQx5va1Uy8Gwuqz_87R6_WpfJLoT3-MX2hK1ef0Fj88SM4WPLdooiMH80WHiPdHFjLIES2W9XzpWyzVRPvMfhkrxEeuNFuxhw_puMvBA6r5UivrDZNaPu0L5bn42Vm1OTeUlN4yDtTYu9wFuqpBAVWnXtCH_1l6PZE1c5ifaV6i5FlzKvKSqZroQpVF5hR1XbUo7WOuWLVA6V3wVOR1022RM06L81wb_R6GczVTX9ht9zHEcYrf1KRE4z8Qg1A4NybYwa7tJZaeuc3S0ERklQgHdR07GJANuOk6HU1294yOe8MPYq3J2x11YoTHHDVQk7OMKLxZBWATnrHHdW7CongpWrOnGAldqJXDlpbRaLbmAOJlUulLL2V2zOeUL-Om4Ty2ljTbj1w84TXso0Qs2xHq5pROhIMYGhgoyxa8iExHmKfhaBwnLvpuPjltjROzzN2eny9dWXh58f6-Z2VwwghL3zLs0amWb7Js9GHCvf1VTu6ybnoX1kl5cBEq92DGCJ3wCSX4oLzXx3ghusC9e5eXDA4mE_FRBgx5Y9FTF3AyBTrBvfB7wVm_ZdsZcmMsqZGzPsItWgHHX-HC-5e85Qc54UQivi2X89EqKp3RYt61GtQM2ub2Mu6eDvCXLzkUnTI1xhmiD5d5dTFVTznGu5KVBmbDLox0lsV3b1bd9TQMHQmA_ePCOnkE0er_rdA0Fr6QP0THrUJah7UrmTWqaO7o0YkDXJLA1WMXUqBwIVKwsz0pXKS6rqjerUvDjvwywhV8j6ytDWeviHOgGM6tAoMSvjunoX6Jl2AF114Usyj2ScLyGbINCmOZa-dbyUFMltYqI3Fu_EJmwz0-AsG0Za126bPvFcXq2xIWe1eKKRjYMrxvyLMnnKKpTLDI-hMidpgt3qMaxgFeHtybMyNFzgRkXVipJoPp8WRUqhhg4RPAkoq9MR6smH7FSOesREBUILXflERKnMKGtK-WQSxjLJy9jK0WaiHyY6r0dvH0tYV2FoIsDREqNE9HHfDl5hS828GZhQyuMC1uLBhui8AA513-TL6PojDTwgsxq4NFikYaTYukRSD5LiBVvV1PH0aCOnKBR2V6eNS-x-cENypPQEsJ46lADpSIOcEWqyy-PqypxtVNiTMqPqagepOBxuCveIS6Qqi124zcKHEUCHN5M7yG59mC2Gv-vnpdNWCsBjKgUD6qKpJBiUuP5XFXZEFD7_5wUUJIc4Ym3G6bN4TvymaylJfx0QC1fqZC8ufj655cgHuuftlH2KiGYugc2FynN2YSwQulzZTp7tLOdTOJy6n2r5xmGPIupspqmQSDtdMC1ZqwDyk_90fmLVMYH2I219scphzF4wPOrY-GYAyvX0yoDtLpLoRHWiSnsWc7FIScR9xpdJwLC7b_zsrxyhqexLldbyiZstOCssrilJvKkjtdMGxGXwNCkJeTcKAaXO-ebnvsC8hyO01J1t9_Gj0m38n_8GjDfxeWcUefZfeWyg9gkpcIYFalwqgPKNZ6PirJxJyOmeP4T33muNWaudncsnTcQPWwst4hQuAc45MpcCkxsm_ONJfzcVLcAeSQXsGSCCrX5Fvn_gdBVcn3dZ3pOYmptiyDXiT5O09UAMVaTNxJmFJkXGmrf6Mk9z4B5Zmi1Z5X_g_xhyCmAMkbgmU7-tUq_O9wBbQbvOdTietdY30QXUjuVgIzwk_yyYpUuoRk1IsqHzVr9WlowUG48hoUTMZWXWCrrVKcwOluvqrBVZI8et54PMsm2gxrlSwTTPH2sc7HkQpSaI0oaI_IqDp45ZZJCyHJ5fuVrTj_J0RVh1ZCsAQWtm64Jv5GHrkg9Gs_ui8tKDK5O2eSi9Wmrtl0k8RV7jwLcJEGMs_W3Eq4KfdBUQ0v_hnU5VRHgt-175Uv2LB4cRsjope-g4fJZKBv9dsDRkdIs4EXx8-97K-OPtREL8bqyPA2wDsz7-8fwSQTyujEg-9LpdGRmy8ViazdGpDq-YrnHgntaIESN_wYh0nORdcrISysKQjn1Xakji-8qpFVK0W7qT7euMyZm5X9ZrUoP8SxgDn4gNoUjosXg-Jbvd1p4f1T33njCLLsskq0UUk4KKy975oP-9RYh02cE0M-H7Nz6dH8Pg0sEsjeCQ6GQ6Bg40VDa8kLLecUzAuteiVuPWZzoMT2k9PQR8NkhkEqLaxhXt2RQGHmU_q37PRJ1Q5ZMbV7yn1-PfzfwuU5uJdltD2925uYyir3DYYU78ILy-JeBxO4llP-ju383LcGNqIe9w_PSygZJgvG7GAg4tUdkJAJk1RfnpECjO9O7KfjkV80S340gHF9mNpqLmARY4o8eb4iM3H3SChvL2cUZygFi1usOfnJwyseZXdq2QVp71U7mv8SRlD6_gznci5eBaZcHdMrMBtQEWYwgmYMexu9wBR7crNR0rbcqO95407DPtW-uuKDcOW9fzggrP1PTPoGvsPIqEb6o2UoDwy4V-aLb2ArnSZEVyAeoPOd2naETQIo5vZwVdz7i47G1yXP8l4q6eEj1odB4B_C461h54aJ8jV0wOu3J-ImCL41-1r4V5oPWbQd-rKkjmF3W2PgQQHteES-Oq_yHjNrDf4_cLUUgEZN2tpdi15h70bP3F01O2Sk3zukAL5RGL3jsCrKJxml9Rr5Br0dMDAtBPVFWous-UcmjhQ63TW-Cia_0R79M_8fZeibWvOpxOPJELHu_YXYPANY8l1XTCXD9lxrbmk4QRdmqSjad2o00kftJWuZk4rfzii2QZanf29YasjK3acNqjbLcYQxF5GjhH2uD1w-CJEbqvYa9qtDa-bCbOgHAO5p3OfW-H5w1y6tFrjeD0IO508CkJEg5mX4tI1GQLQAKuQFl22n10kFI36b1RBqR2F-dfovvrhT5Chw-7Ii6WkafcMUxe9qiiFuxj6q5demKKvO1EV9UHXd821yZh-v73Fv0lHdyc2guhAgxtuJZie9qRTApo3Vj4Mq5uSi9FfdOTKD6aWGuGDCoaFg2Ul04s74QKqR_N0mwhRsmQnabZEj9OwRwnQSsEr1E3DNlUFyjMx-_WCxzLrDxbhbJy6ocIt-D---E1rGFz9MLEXwK_B8Nun5obz6LrM0kS2EWAgiId3ht-uzALEjK5eYgSBO6jJkzS4brrb02PUpfvqCZdMdO6_h_I0MsuyZ-aUIbWC9EcqeIajBf6akF6eLOe3B9YoV0XYk9Rqd-uPxo7uJm51GPbYFZPq5xDTQcnnVeAexsa5Z_TWR9V7npmQATzruJffN2dnNHKxwHWLmnCa92HRffc4q71Pviaet1L95rEiaxFbyBJ3nmZO6JLTqa4M5QcbfzXcVjd2Fp7ak92iLGKoqMsgZ3f8VciBF76D7BpMsBdnsu6Itf_lBBJ2bmyCC2_CmrcFBk3mEbVnNfe03m_QE9H1II2ryDHuC7BqXYdZk27edOyl",
 				},
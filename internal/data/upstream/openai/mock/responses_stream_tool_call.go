@@ -32,9 +32,10 @@ func responsesStreamToolCallChatEvents() []*v1.ChatEvent {
 		id.of(v1.NewMessageStartEvent("gen-1785502380-2zHE7hADYroJvmN6sDQs", "openai/gpt-5-mini")),
 		id.of(v1.NewContentSnapshotEvent(
 			&v1.Content{
-				Id:    "rs_0b770f1e999f2b3f016a6c9aad7b4c81a1a0377ba2bb1ead6e",
-				Index: new(uint32(0)),
-				Phase: v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Id:         "rs_0b770f1e999f2b3f016a6c9aad7b4c81a1a0377ba2bb1ead6e",
+				Index:      new(uint32(0)),
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: v1.Provenance_PROVENANCE_OPENAI,
 				Content: &v1.Content_Opaque{
 					Opaque: "gAAAAABqbJqvEhbDwfUq9PzYA-SncpQEbM__I61f41hKOaFEkQXLuuGgYjthIg_8vxTzE24P7BVWLP0Y7yggTWS_UjppA9KRcK69OnDGM4JqFRiujpTZ9-0vFyJ-h_XpN1yDENysfFkafaXSKN1Ryc2q2MWa-GD2FnZOZq3LGV3en3QoOhfiNIzcvTiClDG2MOMZa6tI9iNAfzrA78tYtZ3OiNFd8SEd1VqnRR_DxarlOtXD5vKMsSRM508ibD4TapKwCh4R1MdMC0NvDGbxJKvo_WUSXuNlHnm1nVHLDalukA_tDWEWvc9cSX-PEfOUI7R7KdhDtw2pM_Inm0m8xioZrY3bOijY7GhNJZLJit-0KgJfefaXEMr9iP-6PK67QlZYltggl_RGcwaDs62zyox8zriIH88u151qJrjOQwtd2GryQmWNmr6FpMd_5AkcIl2NHxUMNiq6y_66nQPdmoBrPZpYjcBOwtUVwWa9idi7gTtWlmFlt7WExuqR5q_GjmOwcNHv_hivac_lVGjo_aUD5vu1w2hW5NBxpC0H7FPizk_nEbfThqwb8cR6A4zsRXQnZi0OSurM_YubRk-L-su18_3_noF9Ow1E9quVqVDQDKcKDA4zJfJFX319-qiR76KeGJmfHb3fLnlNdp4Y7Yv3MhFhAf5vW5j-7gjvSyy4SxuXuyza7h0_j21Jwq99o9cuqYoZe0uvTtk3MK_M2AGH-5M9g7BT8MOqoPCquphq6Kh5S3dXTALxcF0-BWtfGN1yaNt82U6_qM_vay6WI-q20wpz4KmzhIr1WSDUslrpGp8H7O6ll9PCldANWZMP2qwuDuMJeWcyagi5o0TnBByfKOn2LrNDxnwsqMZDJM_eR21vq6T604SOpOKo_j0iwnX-y1fX1dzA_tBLemUDGy3Cv4ZITdBQKc04_7UjugHpIWUEJOoKiSCEex7ve7oa0UasZa2jCLtC17xH52U9PRlTy9-_ucKyEQ0A0jjK8eQwVLntImSfxQy1AP3G05LtnUY0rMTP5Qxt1QqJym1WbK5-iuuPt0h9vk_AIKWjwN-b04lJ_Wv6nkDk3kII0bA7CyuvITWOUfZPbO95vU0MXU6bUKaZ0puQ2Mi1r2GgOPScrAA1lrY2RztgXwk-Wsl0HKBa8X9pnQSq0DIpRGgFdgZHJD2yjW7xy_UV7fAmUeOPg0THyKQg9QYQCsj1gStoMq-8MSoahpSVUF0XLzYk2HobT3EklJ0Viqx9FcN5UUpXGe_mSI6NXG2Q3NlR0WSY0VkGa3G6EBFMLcXnls6wkAD0ts84ym8wYd5PDTiENGgWsDQDnVdhwCSgAiSJkqOqVhpCDFxJckjRGhgnaJCGYkp2SOlhrPVt4j_11ItfyQ4Re59PFoNMipu6ZyCQpmneH5IVw3INNywqPISZnG6NEnlQ2mMlP2LhyA==",
 				},
@@ -84,8 +84,9 @@ var ResponsesStructuredOutput = &Fixture{
 			Role: v1.Role_ROLE_MODEL,
 			Contents: []*v1.Content{
 				{
-					Id:    "rs_062432a4b1a36741016a6c9ab122d081a197d277b7596be463",
-					Phase: v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Id:         "rs_062432a4b1a36741016a6c9ab122d081a197d277b7596be463",
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Provenance: v1.Provenance_PROVENANCE_OPENAI,
 					Content: &v1.Content_Opaque{
 						Opaque: "gAAAAABqbJqxhA1H89QMf-XNMMf_VGn2BFxpi-QXvWHWHcFnJuR88furZacDDJ9ffC5bZPYq6vrIjM6aDsaSQAhCzKi-eLsSZlnLg-nWxW3YZe5q3p5WOwHlWiDQAKwUFTKcxMGX3NibK4FHgxbWuzxZbt-siV6Tn3IGG87ht5IKCmMwsWz9rf6Cl5G5mAdB_F8DuNPBoLwvmo-vJsjC2-sl7VRser1rUU68tgb213r5MZveLY5__HoijHxeM1lVmAOKeCyZlG2HxY_zWB37_92nvd72L9lUgETNXyH_WGKwi9ti-WpIS7KJpAxa5-G-qXjm3OZ8kZy3d9XNDiGB7DQM6ChFVTjLAEKEgt2z7Sjx4iuxGTlVOy0UNsfGFdAQK9qqTvtEbM0l4HHEAxdNn0VBvqZbLPGMsZoT4sFfQ7ZUXzzVUdtLnUrmpbhyFD6BmSCS7pEgkLet6y8jIQNkVO-xtZEAjqPG1cirQWcEpynUen54FgBQEQOLlNMX5EZEebp9HFvELUnb-X_STWUIys1Rrt5oveAX4WsrFriDsXbD18v6BFXjodaKaAIniDhqbR8EFteQ3qN1daa4Az4D2jLgHPN3mNhtAfVHzNyaLn2foOwhn0Nlv1uc0W6QQnImiolEWebYsCmIxKxjwnF_grbjzTL1WrbXk5leEoFdyDLtKy0nS3hUDFi7OZpFLWPVHd-kPkFxlqiXCJbdNMi7S6XTnaRGaESwI05AsOxKFSbnaX7IF_GWWuEShWOrq-hb367quElBF3U1VyoUGqT9jkf2PURmIuOFDn9aT4l8Q6H8TQE0gJnAEJuI2CNkwCaL1n_rUgYp-J5szMGNJDzn7tfTkokEwwcw6vuts7fGZIs934FNnNwKUDu7w-7odFOAOzT62T9u2NFrHwE2bYI3mitr0-g8stkutmeW5_LOY44gRzefSUxAf7d9GCPZERVLncgdyqIgvAiEAt1DmrscXFgUyRPkMaL3atcAVdhsZWa_M9wEoZdhFZU=",
 					},
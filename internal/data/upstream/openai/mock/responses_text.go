@@ -66,8 +66,9 @@ var ResponsesText = &Fixture{
 			Role: v1.Role_ROLE_MODEL,
 			Contents: []*v1.Content{
 				{
-					Id:    "rs_03802c004f5713db016a6c9a72decc81a09a8090abfd176c62",
-					Phase: v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Id:         "rs_03802c004f5713db016a6c9a72decc81a09a8090abfd176c62",
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Provenance: v1.Provenance_PROVENANCE_OPENAI,
 					Content: &v1.Content_Opaque{
 						Opaque: "gAAAAABqbJpzLJzi_lmKfuNMi51LbjdWpKSSgETp7r5XGu8ytH5JGPykbyT9G_SBFQA--cwvIyCEOICyoh6BFGBI_WwM-_NKcwtgl54UwSlBqsk7R87OLCKyuF9MqxGUVn2Q6A2NdynuJCKy-3-0oVMdelSuj6dJu_JhQokgPTd3t_y2uhEvJJguQmjFFjks0j-lFB-SiiXsW-FG2XHKT0DeTXDAILYAHnx9Fu-S2OFTwGzRxfj-TJZsmuqgn7b5tucAzaQYB7nAJz3uD35SRyRukzrZihahUR6PiLwILG3Ta3MXZSL8PSjwq0whLQgI0aQE-4-s1ZG9X2-uz54BQ-blp9g4lg5-WZlW2FxDC0dFCWAAqQthhw7oEHyfvJM6-cmbDs8zwgnUTKjtyGWABC1D97U0-Wh-dsHzpxAc3yszxHybDgW-jG2pjeUTEnZUAo26u8w0rnEO3qp8wLZY674if1P4NKk3JwJIhZhv78Fbq2bpMbW4afW8FJEnD2KojJesI-hAceIYj28CO5sGuCGN2a-gVjFIEiS3b5ThyMO9zxpI7-_phLVp7aE4cHqECGCULMDy3_dASm7HfkLCMQJMihQ3_yKEzZPfYIohae5JAgeYv2vCcJTkKaJASxM7V7_ejZGaXLNdPDTmIE68Y5o6GBrL6mO1Iesl-NJ8p7MN1vr1Rx2pONLizNCfUK5haYXT5XYyjDtxTqLA6Wk-aUbcJNB4_erODsNcaj5fJwFA1Vf2LlHVj5kx9xMFi0IogS3SZ6Rt72k8tGr1En7t_qve6Qp98zi1XQykVqe96sDYPyL42NgKNFotD-vSBobGeVdFjh2d_iKIoW-pnsRS8snUYitPS7Qzn38y7jJ8yInbKVB5BSJRIT1DCk1QoH1s-QUwEJWh0uQDEHbqwBdY_XeZIZWHHElxdShOYRG5w3y2dURNwm0-H9xowC5_zNXbq-6v3wVu3nbKq1QI1ZyDMQ5vnwRfyVpo7J5ANjSm3zo0oR-qeiHzJjg=",
 					},
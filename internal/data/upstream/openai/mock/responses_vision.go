@@ -70,8 +70,9 @@ var ResponsesVision = &Fixture{
 			Role: v1.Role_ROLE_MODEL,
 			Contents: []*v1.Content{
 				{
-					Id:    "rs_01969eef50f6c980016a6c9ab75de8819fa975bc775b833f4f",
-					Phase: v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Id:         "rs_01969eef50f6c980016a6c9ab75de8819fa975bc775b833f4f",
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Provenance: v1.Provenance_PROVENANCE_OPENAI,
 					Content: &v1.Content_Opaque{
 						Opaque: "gAAAAABqbJq3gNByFtjiMDsRgZymiPXje54G2e6_ufgtt1Mtm-Jqoky1_nFAkU3kVvqd457ZZHaoyJx3aMrz0k9XafMWdLId08khC_EwSg2zM0YBZd-hg1MMtjSCsN__LZpeS4gUnKcLtnc5hDwSOLqwqpCPf5NK1X3TwMVR1gNAcki-0CLvKvlPZhazC5zO9wXMb7GPVo4fowRTW25JZeCllBB86F3omukVqxqNMMg-8Dx8j1IXvwjC51kXCKNAPbqBPj0ogiRpQZUf95wnxC4PEWdgS18o6KsHfxMtbFPGkVlwth0UWnEt9D_Mo0h6NFClgUfmqqYJmENA4sk-epoP7ma6TMQxPHalr0T4ScmZtdfTBt-tkjUG6YOeiTtP5DXXdGhpqNDalhGon20Gs2zP33Ejxlyw4EhyapmavWpX6BS2HhuFvQMxurIr_AL1PHhRSt7o1tWCYOEy7mFgz6LvaZxGr95BgRd-DrQh_QYmery7BeEzrxS4dTwqeTsFSAnJsUGaipElJVYenR35FP9CCjNrfR65o-Uc0RQGgWydarM4QxcX0Kf4NV_kzesJTOhqUQRrtYi_pr9iOMWJeYonbmXAyzkxcLZmvH1_NcBW5sxIafz0x5WZ7oEfJoJXN7fiGAgrp_Dp7NL0L0LInUTwNdSbzjbDomIci17ZNr4OCGLfUQ5o5-k00zSOHtf4egmSNcWyKHySk_gSwr79y9SiptEQbFISyvywyhhY4DnP9NQqCQwjKJiDtN1NEnCRQCWjt0V8-89G8RJqiFXBl7OHsTJXzD5LiPNYTmmSW4sw4HFhtUx9pqirv-zv99KjjYL2VgiLTxkXaEV0yegXmrqzNlFBSUo3UpRer9_9oaLJK8dGgcaBisfWGpIjyqY5DMMMudIvtjybWoxqSv8N6JoP1TVaMhkkhrs-1eL_1-Ytg64UCE8zvGwMQj18hH4-3cASg9rCGBRK4WAPe1Shm5MUOEovXoTGyHS1srfu4BytWE9Wkkfgv_4=",
 					},
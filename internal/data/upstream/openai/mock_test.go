@@ -147,6 +147,48 @@ func TestChat(t *testing.T) {
 			So(err.Error(), ShouldContainSubstring, "network error")
 		})
 	})
+
+	Convey("When the API key is rejected", t, func() {
+		mockClient := &mockHTTPClient{
+			DoFunc: func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":{"message":"Incorrect API key provided","code":"invalid_api_key"}}`))),
+				}, nil
+			},
+		}
+		repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		_, err = repo.Chat(context.Background(), mock.ToolCall.ChatRequest)
+
+		Convey("Then it should report the rejected credential", func() {
+			So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "Incorrect API key provided")
+		})
+	})
+
+	Convey("When access to the model is forbidden", t, func() {
+		mockClient := &mockHTTPClient{
+			DoFunc: func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusForbidden,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":{"message":"Country, region, or territory not supported","code":"unsupported_country_region_territory"}}`))),
+				}, nil
+			},
+		}
+		repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		_, err = repo.Chat(context.Background(), mock.ToolCall.ChatRequest)
+
+		Convey("Then it should keep the credential", func() {
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeFalse)
+		})
+	})
 }
 
 func TestChatStream(t *testing.T) {
@@ -247,3 +289,181 @@ func TestChatStream(t *testing.T) {
 		})
 	})
 }
+
+func TestEmbed(t *testing.T) {
+	Convey("Given the embeddings API conversion fixtures", t, func() {
+		for _, fixture := range mock.EmbeddingFixtures {
+			Convey("When Embed runs the "+fixture.Name+" fixture", func() {
+				mockClient := &mockHTTPClient{}
+				repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+				So(err, ShouldBeNil)
+
+				var capturedBody []byte
+				mockClient.DoFunc = mockResponder("/v1/embeddings", "application/json", fixture.Response, &capturedBody)
+
+				resp, err := repo.Embed(context.Background(), fixture.EmbedRequest)
+				So(err, ShouldBeNil)
+				So(resp, ShouldNotBeNil)
+
+				Convey("Then the request body matches the fixture request", func() {
+					So(jsonMap(capturedBody), ShouldResemble, jsonMap(fixture.Request))
+				})
+
+				Convey("Then the response converts to the expected EmbedResponse", func() {
+					So(proto.Equal(resp, fixture.EmbedResponse), ShouldBeTrue)
+				})
+			})
+		}
+	})
+
+	Convey("When the API call fails", t, func() {
+		mockClient := &mockHTTPClient{
+			DoFunc: func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("network error")
+			},
+		}
+		repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		_, err = repo.Embed(context.Background(), mock.Embedding.EmbedRequest)
+
+		Convey("Then it should return an error", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "network error")
+		})
+	})
+}
+
+func TestComplete(t *testing.T) {
+	Convey("Given the completions API conversion fixtures", t, func() {
+		for _, fixture := range mock.CompletionFixtures {
+			if fixture.Stream {
+				continue
+			}
+			Convey("When Complete runs the "+fixture.Name+" fixture", func() {
+				mockClient := &mockHTTPClient{}
+				repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+				So(err, ShouldBeNil)
+
+				var capturedBody []byte
+				mockClient.DoFunc = mockResponder("/v1/completions", "application/json", fixture.Response, &capturedBody)
+
+				resp, err := repo.Complete(context.Background(), fixture.CompleteRequest)
+				So(err, ShouldBeNil)
+				So(resp, ShouldNotBeNil)
+
+				Convey("Then the request body matches the fixture request", func() {
+					So(jsonMap(capturedBody), ShouldResemble, jsonMap(fixture.Request))
+				})
+
+				Convey("Then the response converts to the expected CompleteResponse", func() {
+					So(proto.Equal(resp, fixture.CompleteResponses[0]), ShouldBeTrue)
+				})
+			})
+		}
+	})
+
+	Convey("When the API call fails", t, func() {
+		mockClient := &mockHTTPClient{
+			DoFunc: func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("network error")
+			},
+		}
+		repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		_, err = repo.Complete(context.Background(), mock.Completion.CompleteRequest)
+
+		Convey("Then it should return an error", func() {
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "network error")
+		})
+	})
+}
+
+func TestCompleteStream(t *testing.T) {
+	Convey("Given the completions API stream fixtures", t, func() {
+		for _, fixture := range mock.CompletionFixtures {
+			if !fixture.Stream {
+				continue
+			}
+			Convey("When CompleteStream runs the "+fixture.Name+" fixture", func() {
+				mockClient := &mockHTTPClient{}
+				repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+				So(err, ShouldBeNil)
+
+				var capturedBody []byte
+				mockClient.DoFunc = mockResponder("/v1/completions", "text/event-stream", fixture.Response, &capturedBody)
+
+				var resps []*entity.CompleteResponse
+				for resp, err := range repo.CompleteStream(context.Background(), fixture.CompleteRequest) {
+					So(err, ShouldBeNil)
+					resps = append(resps, resp)
+				}
+
+				Convey("Then the request body matches the fixture request", func() {
+					So(jsonMap(capturedBody), ShouldResemble, jsonMap(fixture.Request))
+				})
+
+				Convey("Then the stream converts to the expected CompleteResponses", func() {
+					So(len(resps), ShouldEqual, len(fixture.CompleteResponses))
+					for i := range resps {
+						So(proto.Equal(resps[i], fixture.CompleteResponses[i]), ShouldBeTrue)
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestListModels(t *testing.T) {
+	Convey("Given an OpenAI upstream", t, func() {
+		mockClient := &mockHTTPClient{}
+		repo, err := newOpenAIUpstreamWithClient(mockTestConfig, mockClient, slog.Default())
+		So(err, ShouldBeNil)
+
+		Convey("When the models are listed", func() {
+			mockClient.DoFunc = func(httpReq *http.Request) (*http.Response, error) {
+				So(httpReq.Method, ShouldEqual, http.MethodGet)
+				So(httpReq.URL.String(), ShouldEqual, "https://api.openai.com/v1/models")
+				So(httpReq.Header.Get("Authorization"), ShouldEqual, "Bearer test-key")
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body: io.NopCloser(bytes.NewReader([]byte(`{"object":"list","data":[` +
+						`{"id":"gpt-4o","object":"model","created":1715367049,"owned_by":"system"},` +
+						`{"id":"ft:gpt-4o:acme","object":"model","created":1715367050,"owned_by":"acme"}]}`))),
+				}, nil
+			}
+
+			specs, err := repo.ListModels(context.Background())
+
+			Convey("Then it should return the listed models", func() {
+				So(err, ShouldBeNil)
+				So(specs, ShouldHaveLength, 2)
+				So(specs[0].Id, ShouldEqual, "gpt-4o")
+				So(specs[0].Owner, ShouldEqual, "system")
+				So(specs[0].Capabilities, ShouldBeEmpty)
+				So(specs[1].Id, ShouldEqual, "ft:gpt-4o:acme")
+				So(specs[1].Owner, ShouldEqual, "acme")
+			})
+		})
+
+		Convey("When the upstream rejects the API key", func() {
+			mockClient.DoFunc = func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":{"message":"Incorrect API key provided","type":"invalid_request_error"}}`))),
+				}, nil
+			}
+
+			_, err := repo.ListModels(context.Background())
+
+			Convey("Then it should return an unauthorized error", func() {
+				So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+			})
+		})
+	})
+}
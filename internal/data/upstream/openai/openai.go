@@ -16,8 +16,10 @@ package openai
 
 import (
 	"context"
+	"errors"
 	"iter"
 	"log/slog"
+	"net/http"
 
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
@@ -39,8 +41,8 @@ type upstream struct {
 }
 
 func NewOpenAIFactory(loggerProvider otellog.LoggerProvider) repository.UpstreamFactory[conf.OpenAIConfig] {
-	return func(config *conf.OpenAIConfig, logger *slog.Logger) (repository.Repo, error) {
-		client := shared.NewRecordingClientFromLoggerProvider(loggerProvider, "neurouter.upstream.openai")
+	return func(config *conf.OpenAIConfig, transport http.RoundTripper, logger *slog.Logger) (repository.Repo, error) {
+		client := shared.NewClient(loggerProvider, "neurouter.upstream.openai", transport)
 		return newOpenAIUpstreamWithClient(config, client, logger)
 	}
 }
@@ -73,6 +75,7 @@ func (r *upstream) chatWithCompletion(ctx context.Context, req *entity.ChatReque
 
 	openAIResp, err := r.client.Chat.Completions.New(ctx, openAIReq)
 	if err != nil {
+		err = convertError(err)
 		return
 	}
 
@@ -86,7 +89,7 @@ func (r *upstream) chatWithResponses(ctx context.Context, req *entity.ChatReques
 
 	openAIResp, err := r.client.Responses.New(ctx, openAIReq)
 	if err != nil {
-		return nil, err
+		return nil, convertError(err)
 	}
 
 	return r.convertResponseFromOpenAIResponses(req, openAIResp), nil
@@ -112,6 +115,8 @@ type openAIChatStreamClient struct {
 	openIndex      uint32
 	openPhase      v1.ContentPhase
 	openToolIndex  int64
+	inline         *inlineOutputParser
+	inlineToolUse  bool
 }
 
 func (c *openAIChatStreamClient) AsSeq() iter.Seq2[*entity.ChatEvent, error] {
@@ -120,7 +125,7 @@ func (c *openAIChatStreamClient) AsSeq() iter.Seq2[*entity.ChatEvent, error] {
 		for {
 			if !c.upstream.Next() {
 				if err := c.upstream.Err(); err != nil {
-					yield(nil, err)
+					yield(nil, convertError(err))
 					return
 				}
 				break
@@ -149,6 +154,7 @@ func (r *upstream) chatStreamWithCompletion(ctx context.Context, req *entity.Cha
 	client := &openAIChatStreamClient{
 		req:      req,
 		upstream: stream,
+		inline:   newInlineOutputParser(r.config.GetInlineOutputParser()),
 	}
 
 	return client.AsSeq()
@@ -175,7 +181,7 @@ func (c *openAIResponseStreamClient) AsSeq() iter.Seq2[*entity.ChatEvent, error]
 		for {
 			if !c.upstream.Next() {
 				if err := c.upstream.Err(); err != nil {
-					yield(nil, err)
+					yield(nil, convertError(err))
 					return
 				}
 				break
@@ -220,3 +226,90 @@ func (r *upstream) ChatStream(ctx context.Context, req *entity.ChatRequest) iter
 	}
 	return r.chatStreamWithCompletion(ctx, req)
 }
+
+func (r *upstream) Embed(ctx context.Context, req *entity.EmbedRequest) (resp *entity.EmbedResponse, err error) {
+	openAIReq := r.convertRequestToOpenAIEmbedding(req)
+
+	openAIResp, err := r.client.Embeddings.New(ctx, openAIReq)
+	if err != nil {
+		err = convertError(err)
+		return
+	}
+
+	resp = convertResponseFromOpenAIEmbedding(req, openAIResp)
+	return
+}
+
+func (r *upstream) Complete(ctx context.Context, req *entity.CompleteRequest) (*entity.CompleteResponse, error) {
+	openAIReq := r.convertRequestToOpenAICompletion(req)
+
+	openAIResp, err := r.client.Completions.New(ctx, openAIReq)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return convertResponseFromOpenAICompletion(req, openAIResp), nil
+}
+
+func (r *upstream) CompleteStream(ctx context.Context, req *entity.CompleteRequest) iter.Seq2[*entity.CompleteResponse, error] {
+	openAIReq := r.convertRequestToOpenAICompletion(req)
+	openAIReq.StreamOptions.IncludeUsage = openai.Opt(true)
+
+	return func(yield func(*entity.CompleteResponse, error) bool) {
+		stream := r.client.Completions.NewStreaming(ctx, openAIReq)
+		defer stream.Close()
+
+		// The finish reason and the usage arrive in separate chunks, both are
+		// reported in the last response
+		final := &entity.CompleteResponse{Id: req.Id}
+		for stream.Next() {
+			chunk := convertResponseFromOpenAICompletion(req, new(stream.Current()))
+			final.Model = chunk.Model
+			if chunk.Statistics != nil {
+				final.Statistics = chunk.Statistics
+			}
+			if chunk.Status != v1.ChatStatus_CHAT_STATUS_UNSPECIFIED && chunk.Status != v1.ChatStatus_CHAT_STATUS_IN_PROGRESS {
+				final.Status = chunk.Status
+			}
+
+			if len(chunk.Contents) == 0 {
+				continue
+			}
+			chunk.Statistics = nil
+			chunk.Status = v1.ChatStatus_CHAT_STATUS_UNSPECIFIED
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+		if err := stream.Err(); err != nil {
+			yield(nil, convertError(err))
+			return
+		}
+
+		yield(final, nil)
+	}
+}
+
+func (r *upstream) ListModels(ctx context.Context) ([]*entity.ModelSpec, error) {
+	var specs []*entity.ModelSpec
+	pager := r.client.Models.ListAutoPaging(ctx)
+	for pager.Next() {
+		m := pager.Current()
+		specs = append(specs, &entity.ModelSpec{Id: m.ID, Owner: m.OwnedBy})
+	}
+	if err := pager.Err(); err != nil {
+		return nil, convertError(err)
+	}
+	return specs, nil
+}
+
+// convertError marks the errors of a rejected API key.
+func convertError(err error) error {
+	var apiErr *openai.Error
+	if errors.As(err, &apiErr) {
+		return shared.ConvertStatusError(apiErr.StatusCode, apiErr.Code, err)
+	}
+	return err
+}
+
+var _ repository.ModelListingRepo = (*upstream)(nil)
@@ -63,10 +63,43 @@ func (r *upstream) convertRequestToOpenAIResponses(req *entity.ChatRequest) resp
 			r.log.Error("unsupported tool", "tool", t)
 		}
 	}
+	// OpenAI rejects tool choices for requests without tools
+	if len(openAIReq.Tools) > 0 && req.ToolChoice != nil {
+		convertToolChoiceToOpenAIResponses(req.ToolChoice, &openAIReq)
+	}
 
 	return openAIReq
 }
 
+func convertToolChoiceToOpenAIResponses(toolChoice *v1.ToolChoice, req *responses.ResponseNewParams) {
+	switch toolChoice.Mode {
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO:
+		req.ToolChoice.OfToolChoiceMode = openai.Opt(responses.ToolChoiceOptionsAuto)
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+		req.ToolChoice.OfToolChoiceMode = openai.Opt(responses.ToolChoiceOptionsNone)
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED:
+		req.ToolChoice.OfToolChoiceMode = openai.Opt(responses.ToolChoiceOptionsRequired)
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		req.ToolChoice.OfFunctionTool = &responses.ToolChoiceFunctionParam{Name: toolChoice.Name}
+	}
+	if names := toolChoice.AllowedToolNames(); len(names) > 0 {
+		mode := responses.ToolChoiceAllowedModeAuto
+		if toolChoice.Mode == v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED {
+			mode = responses.ToolChoiceAllowedModeRequired
+		}
+		tools := make([]map[string]any, 0, len(names))
+		for _, name := range names {
+			tools = append(tools, map[string]any{"type": "function", "name": name})
+		}
+		req.ToolChoice = responses.ResponseNewParamsToolChoiceUnion{
+			OfAllowedTools: &responses.ToolChoiceAllowedParam{Mode: mode, Tools: tools},
+		}
+	}
+	if toolChoice.ParallelToolCalls != nil {
+		req.ParallelToolCalls = openai.Opt(toolChoice.GetParallelToolCalls())
+	}
+}
+
 func (r *upstream) convertGenerationConfigToOpenAIResponses(config *v1.GenerationConfig, req *responses.ResponseNewParams) {
 	if config.MaxTokens != nil {
 		req.MaxOutputTokens = openai.Opt(config.GetMaxTokens())
@@ -177,6 +210,11 @@ func (r *upstream) convertMessageToOpenAIResponses(message *v1.Message) []respon
 		switch c := content.Content.(type) {
 		case *v1.Content_Text:
 			if content.Phase == v1.ContentPhase_CONTENT_PHASE_REASONING {
+				// Reasoning of other providers cannot be bound to a reasoning
+				// item OpenAI issued, so it is dropped.
+				if !content.VerifiableBy(v1.Provenance_PROVENANCE_OPENAI) {
+					continue
+				}
 				flushMessage()
 				item := reasoningItem(content.GetId())
 				if r.config.ResponsesUseRawReasoning {
@@ -230,6 +268,9 @@ func (r *upstream) convertMessageToOpenAIResponses(message *v1.Message) []respon
 				r.log.Error("unsupported non-reasoning opaque content")
 				continue
 			}
+			if !content.VerifiableBy(v1.Provenance_PROVENANCE_OPENAI) {
+				continue
+			}
 			flushMessage()
 			reasoningItem(content.GetId()).EncryptedContent = openai.Opt(c.Opaque)
 
@@ -308,23 +349,26 @@ func convertReasoningItemFromOpenAIResponses(item responses.ResponseReasoningIte
 	contents := make([]*v1.Content, 0, len(item.Summary)+len(item.Content)+1)
 	for _, summary := range item.Summary {
 		contents = append(contents, &v1.Content{
-			Id:      item.ID,
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: v1.NewTextContent(summary.Text),
+			Id:         item.ID,
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: v1.Provenance_PROVENANCE_OPENAI,
+			Content:    v1.NewTextContent(summary.Text),
 		})
 	}
 	for _, reasoning := range item.Content {
 		contents = append(contents, &v1.Content{
-			Id:      item.ID,
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: v1.NewTextContent(reasoning.Text),
+			Id:         item.ID,
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: v1.Provenance_PROVENANCE_OPENAI,
+			Content:    v1.NewTextContent(reasoning.Text),
 		})
 	}
 	if item.EncryptedContent != "" {
 		contents = append(contents, &v1.Content{
-			Id:      item.ID,
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: &v1.Content_Opaque{Opaque: item.EncryptedContent},
+			Id:         item.ID,
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: v1.Provenance_PROVENANCE_OPENAI,
+			Content:    &v1.Content_Opaque{Opaque: item.EncryptedContent},
 		})
 	}
 	return contents
@@ -444,7 +488,7 @@ func (c *openAIResponseStreamClient) convertStreamEventFromOpenAIResponses(
 				event.ItemID,
 				index,
 				v1.ContentPhase_CONTENT_PHASE_REASONING,
-			)))
+			).WithProvenance(v1.Provenance_PROVENANCE_OPENAI)))
 		}
 
 	case "response.content_part.done":
@@ -459,7 +503,7 @@ func (c *openAIResponseStreamClient) convertStreamEventFromOpenAIResponses(
 				event.ItemID,
 				index,
 				v1.ContentPhase_CONTENT_PHASE_REASONING,
-			)))
+			).WithProvenance(v1.Provenance_PROVENANCE_OPENAI)))
 		}
 
 	case "response.reasoning_summary_text.delta", "response.reasoning_text.delta":
@@ -498,10 +542,11 @@ func (c *openAIResponseStreamClient) convertStreamEventFromOpenAIResponses(
 				index := c.nextIndex
 				c.nextIndex++
 				events = append(events, c.newChatEvent(v1.NewContentSnapshotEvent(&v1.Content{
-					Id:      reasoning.ID,
-					Index:   new(index),
-					Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-					Content: &v1.Content_Opaque{Opaque: reasoning.EncryptedContent},
+					Id:         reasoning.ID,
+					Index:      new(index),
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Provenance: v1.Provenance_PROVENANCE_OPENAI,
+					Content:    &v1.Content_Opaque{Opaque: reasoning.EncryptedContent},
 				})))
 			}
 		}
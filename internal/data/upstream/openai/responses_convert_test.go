@@ -22,6 +22,7 @@ import (
 	"github.com/openai/openai-go/v3/responses"
 	openaishared "github.com/openai/openai-go/v3/shared"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tidwall/gjson"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/entity"
@@ -230,7 +231,36 @@ func TestConvertRequestToOpenAIResponses(t *testing.T) {
 			So(items[0].OfReasoning.Content, ShouldHaveLength, 1)
 			So(items[0].OfReasoning.Content[0].Text, ShouldEqual, "raw")
 		})
+	})
+
+	Convey("Given reasoning produced by other providers", t, func() {
+		repo := &upstream{config: &conf.OpenAIConfig{}, log: slog.Default()}
+		result := repo.convertRequestToOpenAIResponses(&entity.ChatRequest{
+			Model: "gpt-5",
+			Messages: []*v1.Message{{
+				Role: v1.Role_ROLE_MODEL,
+				Contents: []*v1.Content{
+					{
+						Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Signature:  "anthropic-signature",
+						Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+						Content:    v1.NewTextContent("thinking"),
+					},
+					{
+						Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+						Content:    &v1.Content_Opaque{Opaque: "redacted"},
+					},
+					{Content: v1.NewTextContent("answer")},
+				},
+			}},
+		})
 
+		Convey("Then only the message is replayed", func() {
+			items := result.Input.OfInputItemList
+			So(items, ShouldHaveLength, 1)
+			So(items[0].OfMessage, ShouldNotBeNil)
+		})
 	})
 }
 
@@ -546,3 +576,60 @@ func TestConvertStreamEventFromOpenAIResponses(t *testing.T) {
 		})
 	})
 }
+
+func TestConvertToolChoiceToOpenAIResponses(t *testing.T) {
+	Convey("Given a native tool choice", t, func() {
+		repo := &upstream{config: &conf.OpenAIConfig{}, log: slog.Default()}
+		tools := []*v1.Tool{{Tool: &v1.Tool_Function_{Function: &v1.Tool_Function{Name: "get_weather"}}}}
+
+		Convey("When a specific function is forced without parallel calls", func() {
+			result := repo.convertRequestToOpenAIResponses(&entity.ChatRequest{
+				Model: "gpt-5",
+				Tools: tools,
+				ToolChoice: &v1.ToolChoice{
+					Mode:              v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL,
+					Name:              "get_weather",
+					ParallelToolCalls: new(false),
+				},
+			})
+			body, err := json.Marshal(result)
+			So(err, ShouldBeNil)
+
+			Convey("Then the function is named in the flat Responses form", func() {
+				So(gjson.GetBytes(body, "tool_choice.type").String(), ShouldEqual, "function")
+				So(gjson.GetBytes(body, "tool_choice.name").String(), ShouldEqual, "get_weather")
+				So(result.ParallelToolCalls.Valid(), ShouldBeTrue)
+				So(result.ParallelToolCalls.Value, ShouldBeFalse)
+			})
+		})
+
+		Convey("When a mode is set", func() {
+			result := repo.convertRequestToOpenAIResponses(&entity.ChatRequest{
+				Model:      "gpt-5",
+				Tools:      tools,
+				ToolChoice: &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE},
+			})
+
+			Convey("Then it is sent as a tool choice option", func() {
+				So(result.ToolChoice.OfToolChoiceMode.Value, ShouldEqual, responses.ToolChoiceOptionsNone)
+				So(result.ParallelToolCalls.Valid(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When the tools are restricted", func() {
+			result := repo.convertRequestToOpenAIResponses(&entity.ChatRequest{
+				Model:      "gpt-5",
+				Tools:      tools,
+				ToolChoice: &v1.ToolChoice{AllowedNames: []string{"get_weather"}},
+			})
+			body, err := json.Marshal(result)
+			So(err, ShouldBeNil)
+
+			Convey("Then the allowed tools are named in the flat Responses form", func() {
+				So(gjson.GetBytes(body, "tool_choice.type").String(), ShouldEqual, "allowed_tools")
+				So(gjson.GetBytes(body, "tool_choice.mode").String(), ShouldEqual, "auto")
+				So(gjson.GetBytes(body, "tool_choice.tools").Raw, ShouldEqual, `[{"name":"get_weather","type":"function"}]`)
+			})
+		})
+	})
+}
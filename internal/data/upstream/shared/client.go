@@ -0,0 +1,138 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	otellog "go.opentelemetry.io/otel/log"
+
+	"github.com/neuraxes/neurouter/internal/biz/repository"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+// NewTransport creates the round tripper of an upstream from its transport
+// config. Returns nil, for http.DefaultTransport, if config is nil.
+func NewTransport(config *conf.HTTPTransport) (http.RoundTripper, error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if config.ProxyUrl != "" {
+		proxy, err := url.Parse(config.ProxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig
+	}
+
+	if d := config.GetConnectTimeout().AsDuration(); d > 0 {
+		t.DialContext = (&net.Dialer{Timeout: d, KeepAlive: 30 * time.Second}).DialContext
+	}
+	if d := config.GetTlsHandshakeTimeout().AsDuration(); d > 0 {
+		t.TLSHandshakeTimeout = d
+	}
+	if d := config.GetResponseHeaderTimeout().AsDuration(); d > 0 {
+		t.ResponseHeaderTimeout = d
+	}
+	if d := config.GetIdleConnTimeout().AsDuration(); d > 0 {
+		t.IdleConnTimeout = d
+	}
+	if config.MaxIdleConns > 0 {
+		t.MaxIdleConns = int(config.MaxIdleConns)
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = int(config.MaxIdleConnsPerHost)
+	}
+	if config.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = int(config.MaxConnsPerHost)
+	}
+
+	return t, nil
+}
+
+// newTLSConfig returns the TLS settings of the transport config, or nil if it
+// sets none.
+func newTLSConfig(config *conf.HTTPTransport) (*tls.Config, error) {
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if config.CaFile == "" && config.CertFile == "" && !config.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.InsecureSkipVerify}
+
+	if config.CaFile != "" {
+		pem, err := os.ReadFile(config.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ca file %s", config.CaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// NewTransportFactory returns the factory creating the round trippers of
+// upstreams, built once per upstream and shared by its credentials.
+func NewTransportFactory() repository.TransportFactory {
+	return NewTransport
+}
+
+// NewClient creates the HTTP client of an upstream over the given round
+// tripper, recording request and response bodies if a logger provider is
+// given.
+func NewClient(provider otellog.LoggerProvider, scope string, base http.RoundTripper) *http.Client {
+	if provider == nil {
+		if base == nil {
+			return http.DefaultClient
+		}
+		return &http.Client{Transport: base}
+	}
+	return NewRecordingClient(provider.Logger(scope), base)
+}
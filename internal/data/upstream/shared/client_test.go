@@ -0,0 +1,117 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestNewTransport(t *testing.T) {
+	Convey("Test NewTransport", t, func() {
+		Convey("without config should keep the default transport", func() {
+			rt, err := NewTransport(nil)
+			So(err, ShouldBeNil)
+			So(rt, ShouldBeNil)
+
+			So(NewClient(nil, "test", nil), ShouldEqual, http.DefaultClient)
+		})
+
+		Convey("should apply timeouts and pool sizes", func() {
+			rt, err := NewTransport(&conf.HTTPTransport{
+				TlsHandshakeTimeout:   durationpb.New(3 * time.Second),
+				ResponseHeaderTimeout: durationpb.New(30 * time.Second),
+				IdleConnTimeout:       durationpb.New(time.Minute),
+				MaxIdleConns:          50,
+				MaxIdleConnsPerHost:   10,
+				MaxConnsPerHost:       20,
+			})
+			So(err, ShouldBeNil)
+			tr := rt.(*http.Transport)
+			So(tr.TLSHandshakeTimeout, ShouldEqual, 3*time.Second)
+			So(tr.ResponseHeaderTimeout, ShouldEqual, 30*time.Second)
+			So(tr.IdleConnTimeout, ShouldEqual, time.Minute)
+			So(tr.MaxIdleConns, ShouldEqual, 50)
+			So(tr.MaxIdleConnsPerHost, ShouldEqual, 10)
+			So(tr.MaxConnsPerHost, ShouldEqual, 20)
+		})
+
+		Convey("should send requests through the proxy", func() {
+			var proxied string
+			proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxied = r.URL.String()
+			}))
+			defer proxy.Close()
+
+			rt, err := NewTransport(&conf.HTTPTransport{ProxyUrl: proxy.URL})
+			So(err, ShouldBeNil)
+			resp, err := NewClient(nil, "test", rt).Get("http://upstream.invalid/v1/models")
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(proxied, ShouldEqual, "http://upstream.invalid/v1/models")
+		})
+
+		Convey("should trust the CA bundle", func() {
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+			server.Config.ErrorLog = log.New(io.Discard, "", 0)
+			server.StartTLS()
+			defer server.Close()
+
+			caFile := filepath.Join(t.TempDir(), "ca.pem")
+			ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+			So(os.WriteFile(caFile, ca, 0o600), ShouldBeNil)
+
+			rt, err := NewTransport(&conf.HTTPTransport{CaFile: caFile})
+			So(err, ShouldBeNil)
+			resp, err := NewClient(nil, "test", rt).Get(server.URL)
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+
+			rt, err = NewTransport(&conf.HTTPTransport{MaxConnsPerHost: 1})
+			So(err, ShouldBeNil)
+			_, err = NewClient(nil, "test", rt).Get(server.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("should reject invalid settings", func() {
+			_, err := NewTransport(&conf.HTTPTransport{ProxyUrl: "://bad"})
+			So(err, ShouldNotBeNil)
+
+			_, err = NewTransport(&conf.HTTPTransport{CaFile: filepath.Join(t.TempDir(), "missing.pem")})
+			So(err, ShouldNotBeNil)
+
+			_, err = NewTransport(&conf.HTTPTransport{CertFile: "cert.pem", KeyFile: "key.pem"})
+			So(err, ShouldNotBeNil)
+
+			_, err = NewTransport(&conf.HTTPTransport{KeyFile: "key.pem"})
+			So(err, ShouldNotBeNil)
+
+			_, err = NewTransport(&conf.HTTPTransport{CertFile: "cert.pem"})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
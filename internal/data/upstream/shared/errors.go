@@ -0,0 +1,45 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// invalidKeyErrorTypes are the error types or codes with which upstreams
+// report an API key that is invalid rather than lacking a permission.
+var invalidKeyErrorTypes = []string{
+	"invalid_api_key",      // OpenAI
+	"authentication_error", // Anthropic
+	"API_KEY_INVALID",      // Google
+}
+
+// ConvertStatusError reports an error the upstream answered with the given
+// HTTP status and error type as a rejected credential, so the credential can
+// be taken out of rotation. A 401 always rejects the credential, while a 400
+// or 403 does only if its type says the key is invalid: a 403 mostly denies
+// access to a model or region instead, and Google answers an invalid key with
+// a 400. Other errors are returned as is.
+func ConvertStatusError(status int, errType string, err error) error {
+	if status == http.StatusUnauthorized ||
+		(status == http.StatusBadRequest || status == http.StatusForbidden) &&
+			slices.Contains(invalidKeyErrorTypes, errType) {
+		return entity.ErrUpstreamUnauthorized.WithCause(err)
+	}
+	return err
+}
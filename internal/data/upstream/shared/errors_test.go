@@ -0,0 +1,62 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func TestConvertStatusError(t *testing.T) {
+	Convey("Test ConvertStatusError", t, func() {
+		cause := errors.New("upstream error")
+
+		Convey("should reject the credential on 401", func() {
+			err := ConvertStatusError(http.StatusUnauthorized, "", cause)
+			So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+			So(errors.Is(err, cause), ShouldBeTrue)
+		})
+
+		Convey("should reject the credential on 403 with an invalid key", func() {
+			err := ConvertStatusError(http.StatusForbidden, "API_KEY_INVALID", cause)
+			So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+		})
+
+		Convey("should reject the credential on 400 with an invalid key", func() {
+			err := ConvertStatusError(http.StatusBadRequest, "API_KEY_INVALID", cause)
+			So(errors.Is(err, entity.ErrUpstreamUnauthorized), ShouldBeTrue)
+		})
+
+		Convey("should keep the credential on other 400s", func() {
+			err := ConvertStatusError(http.StatusBadRequest, "", cause)
+			So(err, ShouldEqual, cause)
+		})
+
+		Convey("should keep the credential on other 403s", func() {
+			err := ConvertStatusError(http.StatusForbidden, "permission_error", cause)
+			So(err, ShouldEqual, cause)
+		})
+
+		Convey("should keep the credential on other statuses", func() {
+			err := ConvertStatusError(http.StatusTooManyRequests, "invalid_api_key", cause)
+			So(err, ShouldEqual, cause)
+		})
+	})
+}
@@ -16,16 +16,6 @@ type recordingTransport struct {
 	logger otellog.Logger
 }
 
-// NewRecordingClientFromLoggerProvider creates a recording client using a logger provider and scope name.
-// Returns http.DefaultClient if the provider is nil.
-func NewRecordingClientFromLoggerProvider(provider otellog.LoggerProvider, scope string) *http.Client {
-	if provider == nil {
-		return http.DefaultClient
-	}
-
-	return NewRecordingClient(provider.Logger(scope), nil)
-}
-
 // NewRecordingClient creates an http.Client that captures request and response bodies.
 func NewRecordingClient(logger otellog.Logger, base http.RoundTripper) *http.Client {
 	if base == nil {
@@ -20,12 +20,16 @@ import (
 	"github.com/neuraxes/neurouter/internal/data/upstream/anthropic"
 	"github.com/neuraxes/neurouter/internal/data/upstream/google"
 	"github.com/neuraxes/neurouter/internal/data/upstream/neurouter"
+	"github.com/neuraxes/neurouter/internal/data/upstream/ollama"
 	"github.com/neuraxes/neurouter/internal/data/upstream/openai"
+	"github.com/neuraxes/neurouter/internal/data/upstream/shared"
 )
 
 var ProviderSet = wire.NewSet(
 	anthropic.NewAnthropicChatRepoFactory,
 	google.NewGoogleFactory,
 	neurouter.NewNeurouterFactory,
+	ollama.NewOllamaFactory,
 	openai.NewOpenAIFactory,
+	shared.NewTransportFactory,
 )
@@ -23,13 +23,15 @@ import (
 )
 
 type Server struct {
+	modelSvc   v1.ModelServer
 	chatSvc    v1.ChatServer
 	otelLogger otellog.Logger
 }
 
 func NewServer(svc *service.RouterService, loggerProvider otellog.LoggerProvider) (s *Server) {
 	s = &Server{
-		chatSvc: svc,
+		modelSvc: svc,
+		chatSvc:  svc,
 	}
 	if loggerProvider != nil {
 		s.otelLogger = loggerProvider.Logger("neurouter.server.anthropic")
@@ -47,7 +49,27 @@ func (s *Server) RegisterRoutes(srv *http.Server) {
 		"/anthropic/v1/messages",
 	} {
 		r.POST(path, func(ctx http.Context) error {
-			return s.handleMessageCompletion(ctx)
+			return writeError(ctx, s.handleMessageCompletion(ctx))
 		})
 	}
+
+	for _, path := range []string{
+		"/messages/count_tokens",
+		"/v1/messages/count_tokens",
+		"/anthropic/messages/count_tokens",
+		"/anthropic/v1/messages/count_tokens",
+	} {
+		r.POST(path, func(ctx http.Context) error {
+			return writeError(ctx, s.handleCountTokens(ctx))
+		})
+	}
+
+	// The bare /models paths are shared with other APIs and registered by the
+	// HTTP server
+	for _, path := range []string{
+		"/anthropic/models",
+		"/anthropic/v1/models",
+	} {
+		r.GET(path, s.HandleListModels)
+	}
 }
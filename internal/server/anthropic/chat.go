@@ -49,6 +49,7 @@ func (s *Server) handleMessageCompletion(httpCtx http.Context) (err error) {
 		httpCtx.Response().Header().Set("Connection", "keep-alive")
 
 		m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+			ctx = newRateLimitContext(ctx, httpCtx)
 			util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
 			streamServer := &messageStreamServer{
 				ctx:     ctx,
@@ -71,6 +72,7 @@ func (s *Server) handleMessageCompletion(httpCtx http.Context) (err error) {
 	} else {
 		var emitCtx context.Context = httpCtx
 		m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+			ctx = newRateLimitContext(ctx, httpCtx)
 			emitCtx = ctx
 			util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
 			return s.chatSvc.Chat(ctx, req.(*v1.ChatRequest))
@@ -97,3 +99,31 @@ func (s *Server) handleMessageCompletion(httpCtx http.Context) (err error) {
 
 	return
 }
+
+func (s *Server) handleCountTokens(httpCtx http.Context) (err error) {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return
+	}
+
+	// The count_tokens body is a message request without max_tokens
+	anthropicReq := anthropic.MessageNewParams{}
+	err = json.Unmarshal(requestBody, &anthropicReq)
+	if err != nil {
+		return
+	}
+
+	req := convertChatRequestFromAnthropic(&anthropicReq)
+
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.chatSvc.CountTokens(ctx, req.(*v1.ChatRequest))
+	})
+	resp, err := m(httpCtx, req)
+	if err != nil {
+		return
+	}
+
+	return httpCtx.Result(200, &anthropic.MessageTokensCount{
+		InputTokens: int64(resp.(*v1.CountTokensResponse).InputTokens),
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 	"testing"
@@ -33,8 +34,9 @@ import (
 
 type mockChatServer struct {
 	v1.ChatServer
-	chatFunc       func(ctx context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error)
-	chatStreamFunc func(req *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error
+	chatFunc        func(ctx context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error)
+	chatStreamFunc  func(req *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error
+	countTokensFunc func(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error)
 }
 
 func (m *mockChatServer) Chat(ctx context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error) {
@@ -51,6 +53,13 @@ func (m *mockChatServer) ChatStream(req *v1.ChatRequest, stream v1.Chat_ChatStre
 	return nil
 }
 
+func (m *mockChatServer) CountTokens(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error) {
+	if m.countTokensFunc != nil {
+		return m.countTokensFunc(ctx, req)
+	}
+	return nil, nil
+}
+
 type mockResponseWriter struct {
 	http.ResponseWriter
 	ctx *mockHTTPContext
@@ -105,6 +114,16 @@ func (t *mockHTTPContext) Blob(code int, contentType string, data []byte) error
 	return nil
 }
 
+func (t *mockHTTPContext) JSON(code int, v any) error {
+	t.statusCode = code
+	t.headers.Set("Content-Type", "application/json")
+	return json.NewEncoder(&t.respBody).Encode(v)
+}
+
+func (t *mockHTTPContext) Result(code int, v any) error {
+	return t.JSON(code, v)
+}
+
 func TestChat(t *testing.T) {
 	Convey("Given the Anthropic conversion fixtures", t, func() {
 		for _, fixture := range mock.Fixtures {
@@ -139,6 +158,44 @@ func TestChat(t *testing.T) {
 	})
 }
 
+func TestCountTokens(t *testing.T) {
+	Convey("Given a count_tokens request", t, func() {
+		body := `{
+			"model": "claude-sonnet-4-5",
+			"system": "You are helpful.",
+			"messages": [{"role": "user", "content": "Hello"}]
+		}`
+
+		var received *v1.ChatRequest
+		srv := &Server{
+			chatSvc: &mockChatServer{
+				countTokensFunc: func(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error) {
+					received = req
+					return &v1.CountTokensResponse{Model: req.Model, InputTokens: 17}, nil
+				},
+			},
+		}
+
+		req, err := http.NewRequest(http.MethodPost, "/v1/messages/count_tokens", strings.NewReader(body))
+		So(err, ShouldBeNil)
+		ctx := newMockHTTPContext(req)
+
+		err = srv.handleCountTokens(ctx)
+		So(err, ShouldBeNil)
+
+		Convey("Then it should count the converted chat request", func() {
+			So(received.Model, ShouldEqual, "claude-sonnet-4-5")
+			So(received.Messages, ShouldHaveLength, 2)
+			So(received.Messages[0].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+		})
+
+		Convey("Then it should respond with the input tokens", func() {
+			So(ctx.statusCode, ShouldEqual, http.StatusOK)
+			So(ctx.respBody.String(), ShouldEqual, `{"input_tokens":17}`+"\n")
+		})
+	})
+}
+
 type sseEvent struct {
 	event string
 	data  string
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 
 	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/packages/param"
 	"github.com/go-kratos/kratos/v3/log"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
@@ -69,14 +70,40 @@ func convertChatRequestFromAnthropic(req *anthropic.MessageNewParams) *v1.ChatRe
 	}
 
 	return &v1.ChatRequest{
-		Model:    string(req.Model),
-		Config:   convertGenerationConfigFromAnthropic(req),
-		Messages: messages,
-		Tools:    tools,
-		Metadata: metadata,
+		Model:      string(req.Model),
+		Config:     convertGenerationConfigFromAnthropic(req),
+		Messages:   messages,
+		Tools:      tools,
+		ToolChoice: convertToolChoiceFromAnthropic(req.ToolChoice),
+		Metadata:   metadata,
 	}
 }
 
+func convertToolChoiceFromAnthropic(choice anthropic.ToolChoiceUnionParam) *v1.ToolChoice {
+	toolChoice := &v1.ToolChoice{}
+	var disableParallelToolUse param.Opt[bool]
+	switch {
+	case choice.OfAuto != nil:
+		toolChoice.Mode = v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO
+		disableParallelToolUse = choice.OfAuto.DisableParallelToolUse
+	case choice.OfAny != nil:
+		toolChoice.Mode = v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED
+		disableParallelToolUse = choice.OfAny.DisableParallelToolUse
+	case choice.OfTool != nil:
+		toolChoice.Mode = v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL
+		toolChoice.Name = choice.OfTool.Name
+		disableParallelToolUse = choice.OfTool.DisableParallelToolUse
+	case choice.OfNone != nil:
+		toolChoice.Mode = v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE
+	default:
+		return nil
+	}
+	if disableParallelToolUse.Valid() {
+		toolChoice.ParallelToolCalls = new(!disableParallelToolUse.Value)
+	}
+	return toolChoice
+}
+
 func convertGenerationConfigFromAnthropic(req *anthropic.MessageNewParams) *v1.GenerationConfig {
 	config := &v1.GenerationConfig{}
 	if req.MaxTokens != 0 {
@@ -194,15 +221,19 @@ func convertMessageFromAnthropicParam(message *anthropic.MessageParam) *v1.Messa
 				})
 			}
 		case content.OfThinking != nil:
+			provenance, signature := util.DecodeProvenance(v1.Provenance_PROVENANCE_ANTHROPIC, content.OfThinking.Signature)
 			contents = append(contents, &v1.Content{
-				Phase:     v1.ContentPhase_CONTENT_PHASE_REASONING,
-				Signature: content.OfThinking.Signature,
-				Content:   v1.NewTextContent(content.OfThinking.Thinking),
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Signature:  signature,
+				Provenance: provenance,
+				Content:    v1.NewTextContent(content.OfThinking.Thinking),
 			})
 		case content.OfRedactedThinking != nil:
+			provenance, data := util.DecodeProvenance(v1.Provenance_PROVENANCE_ANTHROPIC, content.OfRedactedThinking.Data)
 			contents = append(contents, &v1.Content{
-				Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-				Content: &v1.Content_Opaque{Opaque: content.OfRedactedThinking.Data},
+				Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Provenance: provenance,
+				Content:    &v1.Content_Opaque{Opaque: data},
 			})
 		case content.OfToolUse != nil:
 			var args []byte
@@ -294,7 +325,7 @@ func convertChatResponseToAnthropic(resp *v1.ChatResponse) *anthropic.Message {
 					anthropicResp.Content = append(anthropicResp.Content, anthropic.ContentBlockUnion{
 						Type:      "thinking",
 						Thinking:  c.Text.GetText(),
-						Signature: content.Signature,
+						Signature: util.EncodeProvenance(v1.Provenance_PROVENANCE_ANTHROPIC, content.Provenance, content.Signature),
 					})
 				} else {
 					if c.Text.GetText() != "" {
@@ -307,7 +338,7 @@ func convertChatResponseToAnthropic(resp *v1.ChatResponse) *anthropic.Message {
 			case *v1.Content_Opaque:
 				anthropicResp.Content = append(anthropicResp.Content, anthropic.ContentBlockUnion{
 					Type: "redacted_thinking",
-					Data: c.Opaque,
+					Data: util.EncodeProvenance(v1.Provenance_PROVENANCE_ANTHROPIC, content.Provenance, c.Opaque),
 				})
 			case *v1.Content_ToolUse:
 				f := c.ToolUse
@@ -348,8 +379,9 @@ func convertUsageToAnthropic(usage *v1.Usage) anthropic.Usage {
 		return anthropic.Usage{}
 	}
 	return anthropic.Usage{
-		InputTokens:          max(int64(usage.InputTokens)-int64(usage.CachedInputTokens), 0),
-		OutputTokens:         int64(usage.OutputTokens),
-		CacheReadInputTokens: int64(usage.CachedInputTokens),
+		InputTokens:              max(int64(usage.InputTokens)-int64(usage.CachedInputTokens)-int64(usage.CacheWriteInputTokens), 0),
+		OutputTokens:             int64(usage.OutputTokens),
+		CacheReadInputTokens:     int64(usage.CachedInputTokens),
+		CacheCreationInputTokens: int64(usage.CacheWriteInputTokens),
 	}
 }
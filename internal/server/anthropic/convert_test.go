@@ -15,6 +15,7 @@
 package anthropic
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -334,6 +335,7 @@ func TestConvertMessageFromAnthropicParam(t *testing.T) {
 				So(result.Contents[0].GetPhase(), ShouldEqual, v1.ContentPhase_CONTENT_PHASE_REASONING)
 				So(result.Contents[0].GetText().GetText(), ShouldEqual, "Let me think about this...")
 				So(result.Contents[0].Signature, ShouldEqual, "sig-abc")
+				So(result.Contents[0].Provenance, ShouldEqual, v1.Provenance_PROVENANCE_ANTHROPIC)
 			})
 		})
 
@@ -350,6 +352,26 @@ func TestConvertMessageFromAnthropicParam(t *testing.T) {
 				So(result.Contents, ShouldHaveLength, 1)
 				So(result.Contents[0].GetPhase(), ShouldEqual, v1.ContentPhase_CONTENT_PHASE_REASONING)
 				So(result.Contents[0].GetOpaque(), ShouldEqual, "opaque-encrypted-data")
+				So(result.Contents[0].Provenance, ShouldEqual, v1.Provenance_PROVENANCE_ANTHROPIC)
+			})
+		})
+
+		Convey("When converting thinking content produced by other providers", func() {
+			msg := &anthropic.MessageParam{
+				Role: anthropic.MessageParamRoleAssistant,
+				Content: []anthropic.ContentBlockParamUnion{
+					anthropic.NewThinkingBlock("google:c2lnbmF0dXJl", "Thought summary"),
+					anthropic.NewRedactedThinkingBlock("openai:gAAAAB"),
+				},
+			}
+			result := convertMessageFromAnthropicParam(msg)
+
+			Convey("Then the provenance tags should be decoded", func() {
+				So(result.Contents, ShouldHaveLength, 2)
+				So(result.Contents[0].Signature, ShouldEqual, "c2lnbmF0dXJl")
+				So(result.Contents[0].Provenance, ShouldEqual, v1.Provenance_PROVENANCE_GOOGLE)
+				So(result.Contents[1].GetOpaque(), ShouldEqual, "gAAAAB")
+				So(result.Contents[1].Provenance, ShouldEqual, v1.Provenance_PROVENANCE_OPENAI)
 			})
 		})
 
@@ -731,6 +753,36 @@ func TestConvertChatResponseToAnthropic(t *testing.T) {
 			})
 		})
 
+		Convey("When converting a response with reasoning of other providers", func() {
+			resp := &v1.ChatResponse{
+				Status: v1.ChatStatus_CHAT_STATUS_COMPLETED,
+				Message: &v1.Message{
+					Role: v1.Role_ROLE_MODEL,
+					Contents: []*v1.Content{
+						{
+							Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+							Signature:  "c2lnbmF0dXJl",
+							Provenance: v1.Provenance_PROVENANCE_GOOGLE,
+							Content:    v1.NewTextContent("Thought summary"),
+						},
+						{
+							Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+							Provenance: v1.Provenance_PROVENANCE_OPENAI,
+							Content:    &v1.Content_Opaque{Opaque: "gAAAAB"},
+						},
+					},
+				},
+			}
+
+			result := convertChatResponseToAnthropic(resp)
+
+			Convey("Then signatures and opaque data should be tagged with their provenance", func() {
+				So(result.Content, ShouldHaveLength, 2)
+				So(result.Content[0].Signature, ShouldEqual, "google:c2lnbmF0dXJl")
+				So(result.Content[1].Data, ShouldEqual, "openai:gAAAAB")
+			})
+		})
+
 		Convey("When converting a response with redacted thinking", func() {
 			resp := &v1.ChatResponse{
 				Model:  "claude-3-sonnet",
@@ -909,17 +961,63 @@ func TestConvertChatResponseToAnthropic(t *testing.T) {
 func TestConvertUsageToAnthropic(t *testing.T) {
 	Convey("Given usage to convert", t, func() {
 		usage := &v1.Usage{
-			InputTokens:       100,
-			OutputTokens:      50,
-			CachedInputTokens: 20,
+			InputTokens:           100,
+			OutputTokens:          50,
+			CachedInputTokens:     20,
+			CacheWriteInputTokens: 30,
 		}
 
 		result := convertUsageToAnthropic(usage)
 
 		Convey("Then all usage fields should be mapped", func() {
-			So(result.InputTokens, ShouldEqual, 80)
+			So(result.InputTokens, ShouldEqual, 50)
 			So(result.OutputTokens, ShouldEqual, 50)
 			So(result.CacheReadInputTokens, ShouldEqual, 20)
+			So(result.CacheCreationInputTokens, ShouldEqual, 30)
+		})
+	})
+}
+
+func TestConvertToolChoiceFromAnthropic(t *testing.T) {
+	Convey("Given Anthropic tool choices", t, func() {
+		convert := func(choice string) *v1.ToolChoice {
+			var req anthropic.MessageNewParams
+			So(json.Unmarshal([]byte(`{"model":"claude","max_tokens":1,"messages":[],"tool_choice":`+choice+`}`), &req), ShouldBeNil)
+			return convertChatRequestFromAnthropic(&req).ToolChoice
+		}
+
+		Convey("When a specific tool is forced without parallel tool use", func() {
+			toolChoice := convert(`{"type":"tool","name":"get_weather","disable_parallel_tool_use":true}`)
+
+			Convey("Then the tool is named and parallel calls are disabled", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL)
+				So(toolChoice.Name, ShouldEqual, "get_weather")
+				So(toolChoice.ParallelToolCalls, ShouldNotBeNil)
+				So(toolChoice.GetParallelToolCalls(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When any tool is required", func() {
+			toolChoice := convert(`{"type":"any"}`)
+
+			Convey("Then the mode is required with the default parallel tool use", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED)
+				So(toolChoice.ParallelToolCalls, ShouldBeNil)
+			})
+		})
+
+		Convey("When the choice is auto or none", func() {
+			So(convert(`{"type":"auto"}`).Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO)
+			So(convert(`{"type":"none"}`).Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE)
+		})
+
+		Convey("When no tool choice is set", func() {
+			var req anthropic.MessageNewParams
+			So(json.Unmarshal([]byte(`{"model":"claude","max_tokens":1,"messages":[]}`), &req), ShouldBeNil)
+
+			Convey("Then the upstream default is kept", func() {
+				So(convertChatRequestFromAnthropic(&req).ToolChoice, ShouldBeNil)
+			})
 		})
 	})
 }
@@ -0,0 +1,61 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"net/http"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	khttp "github.com/go-kratos/kratos/v3/transport/http"
+)
+
+type errorBody struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	Type  string    `json:"type"`
+	Error errorBody `json:"error"`
+}
+
+// writeError renders err in the Anthropic error format, so that Anthropic
+// clients recognise rate limits and other failures.
+func writeError(httpCtx khttp.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := errors.FromError(err)
+
+	errorType := "api_error"
+	switch code := int(e.Code); {
+	case code == http.StatusTooManyRequests:
+		errorType = "rate_limit_error"
+	case code == http.StatusUnauthorized:
+		errorType = "authentication_error"
+	case code == http.StatusForbidden:
+		errorType = "permission_error"
+	case code == http.StatusNotFound:
+		errorType = "not_found_error"
+	case code == http.StatusRequestEntityTooLarge:
+		errorType = "request_too_large"
+	case code >= 400 && code < 500:
+		errorType = "invalid_request_error"
+	}
+	return httpCtx.JSON(int(e.Code), &errorResponse{
+		Type:  "error",
+		Error: errorBody{Type: errorType, Message: e.Message},
+	})
+}
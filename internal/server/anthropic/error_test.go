@@ -0,0 +1,51 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func TestWriteError(t *testing.T) {
+	Convey("Test writeError", t, func() {
+		Convey("client quota errors should be rendered as Anthropic rate limits", func() {
+			ctx := newMockHTTPContext(nil)
+			So(writeError(ctx, entity.ErrClientQuotaExceeded), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusTooManyRequests)
+
+			var resp errorResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.Type, ShouldEqual, "error")
+			So(resp.Error.Type, ShouldEqual, "rate_limit_error")
+			So(resp.Error.Message, ShouldEqual, "client quota exceeded")
+		})
+
+		Convey("no upstream errors should be rendered as API errors", func() {
+			ctx := newMockHTTPContext(nil)
+			So(writeError(ctx, entity.ErrNoUpstream), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusInternalServerError)
+
+			var resp errorResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error.Type, ShouldEqual, "api_error")
+		})
+	})
+}
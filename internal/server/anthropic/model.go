@@ -0,0 +1,84 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v3/transport/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+// modelInfo is a model in the Anthropic models API.
+type modelInfo struct {
+	Type           string    `json:"type"`
+	ID             string    `json:"id"`
+	DisplayName    string    `json:"display_name"`
+	CreatedAt      time.Time `json:"created_at"`
+	MaxInputTokens uint32    `json:"max_input_tokens,omitempty"`
+}
+
+// modelList is a page of the Anthropic models API. Neurouter lists every model
+// in a single page.
+type modelList struct {
+	Data    []*modelInfo `json:"data"`
+	HasMore bool         `json:"has_more"`
+	FirstID string       `json:"first_id,omitempty"`
+	LastID  string       `json:"last_id,omitempty"`
+}
+
+func convertModelToAnthropic(model *v1.ModelSpec) *modelInfo {
+	displayName := model.Name
+	if displayName == "" {
+		displayName = model.Id
+	}
+	return &modelInfo{
+		Type:        "model",
+		ID:          model.Id,
+		DisplayName: displayName,
+		// The release date is unknown, which the API represents as the epoch
+		CreatedAt:      time.Unix(0, 0).UTC(),
+		MaxInputTokens: model.ContextLength,
+	}
+}
+
+func (s *Server) handleListModels(httpCtx http.Context) error {
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.modelSvc.ListModel(ctx, &v1.ListModelRequest{})
+	})
+	r, err := m(httpCtx, nil)
+	if err != nil {
+		return err
+	}
+
+	resp := &modelList{Data: []*modelInfo{}}
+	for _, model := range r.(*v1.ListModelResponse).Models {
+		resp.Data = append(resp.Data, convertModelToAnthropic(model))
+	}
+	if len(resp.Data) > 0 {
+		resp.FirstID = resp.Data[0].ID
+		resp.LastID = resp.Data[len(resp.Data)-1].ID
+	}
+
+	return httpCtx.Result(200, resp)
+}
+
+// HandleListModels serves the Anthropic model listing. It is exported for the
+// model paths shared with other APIs, which dispatch by request headers.
+func (s *Server) HandleListModels(httpCtx http.Context) error {
+	return writeError(httpCtx, s.handleListModels(httpCtx))
+}
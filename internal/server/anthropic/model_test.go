@@ -0,0 +1,79 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+type mockModelServer struct {
+	v1.ModelServer
+	models []*v1.ModelSpec
+}
+
+func (m *mockModelServer) ListModel(context.Context, *v1.ListModelRequest) (*v1.ListModelResponse, error) {
+	return &v1.ListModelResponse{Models: m.models}, nil
+}
+
+func TestListModels(t *testing.T) {
+	Convey("Given a model listing request", t, func() {
+		req, err := http.NewRequest(http.MethodGet, "/v1/models", nil)
+		So(err, ShouldBeNil)
+		req.Header.Set("anthropic-version", "2023-06-01")
+		ctx := newMockHTTPContext(req)
+
+		Convey("When models are available", func() {
+			srv := &Server{modelSvc: &mockModelServer{models: []*v1.ModelSpec{
+				{Id: "claude-sonnet", Name: "Claude Sonnet", ContextLength: 200000},
+				{Id: "gpt-4o"},
+			}}}
+			So(srv.HandleListModels(ctx), ShouldBeNil)
+
+			var resp map[string]any
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+
+			Convey("Then it should list them in the Anthropic format", func() {
+				So(resp["has_more"], ShouldBeFalse)
+				So(resp["first_id"], ShouldEqual, "claude-sonnet")
+				So(resp["last_id"], ShouldEqual, "gpt-4o")
+
+				data := resp["data"].([]any)
+				So(data, ShouldHaveLength, 2)
+				first := data[0].(map[string]any)
+				So(first["type"], ShouldEqual, "model")
+				So(first["display_name"], ShouldEqual, "Claude Sonnet")
+				So(first["created_at"], ShouldEqual, "1970-01-01T00:00:00Z")
+				So(first["max_input_tokens"], ShouldEqual, 200000)
+				So(data[1].(map[string]any)["display_name"], ShouldEqual, "gpt-4o")
+			})
+		})
+
+		Convey("When no models are available", func() {
+			srv := &Server{modelSvc: &mockModelServer{}}
+			So(srv.HandleListModels(ctx), ShouldBeNil)
+
+			Convey("Then it should return an empty page", func() {
+				So(ctx.respBody.String(), ShouldEqual, `{"data":[],"has_more":false}`+"\n")
+			})
+		})
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	khttp "github.com/go-kratos/kratos/v3/transport/http"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+// newRateLimitContext makes the rate limit of the request be written as
+// Anthropic rate-limit headers, in place of the server-wide defaults.
+func newRateLimitContext(ctx context.Context, httpCtx khttp.Context) context.Context {
+	return entity.NewRateLimitContext(ctx, func(rl *entity.RateLimit) {
+		setRateLimitHeaders(httpCtx.Response().Header(), rl)
+	})
+}
+
+// setRateLimitHeaders writes rl as anthropic-ratelimit-* headers, with reset
+// times as RFC 3339 timestamps.
+func setRateLimitHeaders(header http.Header, rl *entity.RateLimit) {
+	for unit, w := range map[string]*entity.RateLimitWindow{"requests": rl.Requests, "tokens": rl.Tokens} {
+		if w == nil {
+			continue
+		}
+		reset := w.Reset
+		if reset.IsZero() {
+			reset = time.Now()
+		}
+		header.Set("anthropic-ratelimit-"+unit+"-limit", strconv.FormatInt(w.Limit, 10))
+		header.Set("anthropic-ratelimit-"+unit+"-remaining", strconv.FormatInt(w.Remaining, 10))
+		header.Set("anthropic-ratelimit-"+unit+"-reset", reset.UTC().Format(time.RFC3339))
+	}
+	util.SetRetryAfterHeaders(header, rl.RetryAfter)
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	Convey("Test setRateLimitHeaders", t, func() {
+		reset := time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC)
+		header := http.Header{}
+		setRateLimitHeaders(header, &entity.RateLimit{
+			Tokens:     &entity.RateLimitWindow{Limit: 1000, Remaining: 0, Reset: reset},
+			RetryAfter: 30 * time.Second,
+		})
+		So(header.Get("anthropic-ratelimit-tokens-limit"), ShouldEqual, "1000")
+		So(header.Get("anthropic-ratelimit-tokens-remaining"), ShouldEqual, "0")
+		So(header.Get("anthropic-ratelimit-tokens-reset"), ShouldEqual, "2025-01-01T00:01:00Z")
+		So(header.Get("anthropic-ratelimit-requests-limit"), ShouldBeEmpty)
+		So(header.Get("retry-after"), ShouldEqual, "30")
+	})
+}
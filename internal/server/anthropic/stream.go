@@ -23,14 +23,24 @@ import (
 	"github.com/go-kratos/kratos/v3/transport/http"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
 )
 
 type messageStreamServer struct {
 	v1.Chat_ChatStreamServer
-	ctx         context.Context
-	httpCtx     http.Context
-	buffer      *bytes.Buffer
-	blockPhases map[uint32]v1.ContentPhase
+	ctx     context.Context
+	httpCtx http.Context
+	buffer  *bytes.Buffer
+	blocks  map[uint32]*streamBlock
+}
+
+// streamBlock tracks an open content block.
+type streamBlock struct {
+	phase      v1.ContentPhase
+	provenance v1.Provenance
+	// signed is set once the first signature fragment, which carries the
+	// provenance tag, has been sent.
+	signed bool
 }
 
 func (s *messageStreamServer) Context() context.Context {
@@ -117,10 +127,13 @@ func (s *messageStreamServer) startMessage(start *v1.MessageStart, usage *v1.Usa
 }
 
 func (s *messageStreamServer) startContentBlock(start *v1.ContentStart) {
-	if s.blockPhases == nil {
-		s.blockPhases = map[uint32]v1.ContentPhase{}
+	if s.blocks == nil {
+		s.blocks = map[uint32]*streamBlock{}
+	}
+	s.blocks[start.GetIndex()] = &streamBlock{
+		phase:      start.GetPhase(),
+		provenance: start.GetProvenance(),
 	}
-	s.blockPhases[start.GetIndex()] = start.GetPhase()
 
 	contentBlock := anthropic.ContentBlockStartEventContentBlockUnion{}
 	switch c := start.Content.(type) {
@@ -147,7 +160,7 @@ func (s *messageStreamServer) deltaContentBlock(delta *v1.ContentDelta) {
 	switch d := delta.Delta.(type) {
 	case *v1.ContentDelta_Text:
 		deltaUnion := anthropic.RawContentBlockDeltaUnion{}
-		if s.blockPhases[delta.GetIndex()] == v1.ContentPhase_CONTENT_PHASE_REASONING {
+		if block := s.blocks[delta.GetIndex()]; block != nil && block.phase == v1.ContentPhase_CONTENT_PHASE_REASONING {
 			deltaUnion.Type = "thinking_delta"
 			deltaUnion.Thinking = d.Text
 		} else {
@@ -159,11 +172,16 @@ func (s *messageStreamServer) deltaContentBlock(delta *v1.ContentDelta) {
 			Delta: deltaUnion,
 		})
 	case *v1.ContentDelta_Signature:
+		signature := d.Signature
+		if block := s.blocks[delta.GetIndex()]; block != nil && !block.signed {
+			signature = util.EncodeProvenance(v1.Provenance_PROVENANCE_ANTHROPIC, block.provenance, signature)
+			block.signed = signature != ""
+		}
 		s.sendJSONEvent("content_block_delta", anthropic.ContentBlockDeltaEvent{
 			Index: index,
 			Delta: anthropic.RawContentBlockDeltaUnion{
 				Type:      "signature_delta",
-				Signature: d.Signature,
+				Signature: signature,
 			},
 		})
 	case *v1.ContentDelta_ToolInputText:
@@ -186,7 +204,7 @@ func (s *messageStreamServer) snapshotContentBlock(content *v1.Content) {
 			Index: index,
 			ContentBlock: anthropic.ContentBlockStartEventContentBlockUnion{
 				Type: "redacted_thinking",
-				Data: c.Opaque,
+				Data: util.EncodeProvenance(v1.Provenance_PROVENANCE_ANTHROPIC, content.GetProvenance(), c.Opaque),
 			},
 		})
 	default:
@@ -197,8 +215,8 @@ func (s *messageStreamServer) snapshotContentBlock(content *v1.Content) {
 }
 
 func (s *messageStreamServer) sendContentBlockStopEvent(index int64) {
-	if s.blockPhases != nil {
-		delete(s.blockPhases, uint32(index))
+	if s.blocks != nil {
+		delete(s.blocks, uint32(index))
 	}
 	event := anthropic.ContentBlockStopEvent{
 		Index: index,
@@ -0,0 +1,138 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/go-kratos/kratos/v3/transport/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+func (s *Server) handleGenerateContent(httpCtx http.Context, model string) error {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	var googleReq GenerateContentRequest
+	if err = json.Unmarshal(requestBody, &googleReq); err != nil {
+		return err
+	}
+
+	req := convertChatRequestFromGoogle(model, &googleReq)
+
+	var emitCtx context.Context = httpCtx
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		emitCtx = ctx
+		util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
+		return s.chatSvc.Chat(ctx, req.(*v1.ChatRequest))
+	})
+
+	resp, err := m(httpCtx, req)
+	if err != nil {
+		return err
+	}
+
+	googleResp := convertChatResponseToGoogle(resp.(*v1.ChatResponse), includeThoughts(&googleReq))
+	respBytes, err := json.Marshal(googleResp)
+	if err != nil {
+		return err
+	}
+
+	util.EmitEvent(emitCtx, s.otelLogger, util.EventServerRespSent, respBytes)
+
+	return httpCtx.Blob(200, "application/json", respBytes)
+}
+
+func (s *Server) handleStreamGenerateContent(httpCtx http.Context, model string) error {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	var googleReq GenerateContentRequest
+	if err = json.Unmarshal(requestBody, &googleReq); err != nil {
+		return err
+	}
+
+	req := convertChatRequestFromGoogle(model, &googleReq)
+
+	// Without alt=sse, the chunks are streamed as a JSON array
+	sse := httpCtx.Request().URL.Query().Get("alt") == "sse"
+	if sse {
+		httpCtx.Response().Header().Set("Content-Type", "text/event-stream")
+		httpCtx.Response().Header().Set("Cache-Control", "no-cache")
+		httpCtx.Response().Header().Set("Connection", "keep-alive")
+	} else {
+		httpCtx.Response().Header().Set("Content-Type", "application/json")
+	}
+
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
+		streamServer := &generateContentStreamServer{
+			ctx:             ctx,
+			httpCtx:         httpCtx,
+			sse:             sse,
+			includeThoughts: includeThoughts(&googleReq),
+		}
+		if s.otelLogger != nil {
+			streamServer.buffer = &bytes.Buffer{}
+		}
+		err := s.chatSvc.ChatStream(req.(*v1.ChatRequest), streamServer)
+		if err == nil {
+			err = streamServer.close()
+		} else {
+			err = streamServer.fail(err)
+		}
+		if s.otelLogger != nil {
+			util.EmitEvent(ctx, s.otelLogger, util.EventServerRespSent, streamServer.buffer.Bytes())
+		}
+		return nil, err
+	})
+
+	_, err = m(httpCtx, req)
+	return err
+}
+
+func (s *Server) handleCountTokens(httpCtx http.Context, model string) error {
+	var googleReq CountTokensRequest
+	if err := json.NewDecoder(httpCtx.Request().Body).Decode(&googleReq); err != nil {
+		return err
+	}
+
+	generateReq := googleReq.GenerateContentRequest
+	if generateReq == nil {
+		generateReq = &GenerateContentRequest{Contents: googleReq.Contents}
+	}
+	req := convertChatRequestFromGoogle(model, generateReq)
+
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.chatSvc.CountTokens(ctx, req.(*v1.ChatRequest))
+	})
+	resp, err := m(httpCtx, req)
+	if err != nil {
+		return err
+	}
+
+	return httpCtx.Result(200, &CountTokensResponse{
+		TotalTokens: int32(resp.(*v1.CountTokensResponse).InputTokens),
+	})
+}
@@ -0,0 +1,361 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v3/middleware"
+	kratoshttp "github.com/go-kratos/kratos/v3/transport/http"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genai"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+type mockChatServer struct {
+	v1.ChatServer
+	chatFunc        func(ctx context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error)
+	chatStreamFunc  func(req *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error
+	countTokensFunc func(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error)
+}
+
+func (m *mockChatServer) Chat(ctx context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error) {
+	return m.chatFunc(ctx, req)
+}
+
+func (m *mockChatServer) ChatStream(req *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error {
+	return m.chatStreamFunc(req, stream)
+}
+
+func (m *mockChatServer) CountTokens(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error) {
+	return m.countTokensFunc(ctx, req)
+}
+
+type mockResponseWriter struct {
+	http.ResponseWriter
+	ctx *mockHTTPContext
+}
+
+func (w *mockResponseWriter) Write(data []byte) (int, error) {
+	return w.ctx.respBody.Write(data)
+}
+
+func (w *mockResponseWriter) Header() http.Header {
+	return w.ctx.headers
+}
+
+func (w *mockResponseWriter) WriteHeader(statusCode int) {
+	w.ctx.statusCode = statusCode
+}
+
+func (w *mockResponseWriter) Flush() {}
+
+// mockHTTPContext is a fake http.Context serving a request to a model method.
+type mockHTTPContext struct {
+	kratoshttp.Context
+	req        *http.Request
+	vars       url.Values
+	statusCode int
+	headers    http.Header
+	respBody   bytes.Buffer
+}
+
+func newMockHTTPContext(target, body string) *mockHTTPContext {
+	req, _ := http.NewRequest(http.MethodPost, "/v1beta/models/"+target, strings.NewReader(body))
+	model, _, _ := strings.Cut(target, "?")
+	return &mockHTTPContext{
+		req:     req,
+		vars:    url.Values{"model": []string{model}},
+		headers: make(http.Header),
+	}
+}
+
+func (t *mockHTTPContext) Request() *http.Request {
+	return t.req
+}
+
+func (t *mockHTTPContext) Vars() url.Values {
+	return t.vars
+}
+
+func (t *mockHTTPContext) Response() kratoshttp.ResponseWriter {
+	return &mockResponseWriter{ctx: t}
+}
+
+func (t *mockHTTPContext) Middleware(handler middleware.Handler) middleware.Handler {
+	return handler
+}
+
+func (t *mockHTTPContext) Blob(code int, contentType string, data []byte) error {
+	t.statusCode = code
+	t.headers.Set("Content-Type", contentType)
+	t.respBody.Write(data)
+	return nil
+}
+
+func (t *mockHTTPContext) JSON(code int, v any) error {
+	t.statusCode = code
+	t.headers.Set("Content-Type", "application/json")
+	return json.NewEncoder(&t.respBody).Encode(v)
+}
+
+func (t *mockHTTPContext) Result(code int, v any) error {
+	return t.JSON(code, v)
+}
+
+// streamEvents replays a thinking turn that ends with a signed tool call.
+func streamEvents(stream v1.Chat_ChatStreamServer) error {
+	for _, event := range []*v1.ChatEvent{
+		v1.NewChatEvent("resp-1", v1.NewMessageStartEvent("resp-1", "gemini-3-flash")),
+		v1.NewChatEvent("resp-1", v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+		v1.NewChatEvent("resp-1", v1.NewContentDeltaTextEvent(0, "Thinking.")),
+		v1.NewChatEvent("resp-1", v1.NewContentStopEvent(0)),
+		v1.NewChatEvent("resp-1", v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+		v1.NewChatEvent("resp-1", v1.NewContentDeltaTextEvent(1, "Let me check.")),
+		v1.NewChatEvent("resp-1", v1.NewContentStopEvent(1)),
+		v1.NewChatEvent("resp-1", v1.NewContentStartToolUseEvent(2, "get_weather:abc", "get_weather")),
+		v1.NewChatEvent("resp-1", v1.NewContentDeltaToolInputTextEvent(2, `{"city":`)),
+		v1.NewChatEvent("resp-1", v1.NewContentDeltaToolInputTextEvent(2, `"Shanghai"}`)),
+		v1.NewChatEvent("resp-1", v1.NewContentDeltaSignatureEvent(2, "c2lnbmF0dXJl")),
+		v1.NewChatEvent("resp-1", v1.NewContentStopEvent(2)),
+		{
+			Id:    "resp-1",
+			Event: v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE),
+			Usage: &v1.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+	} {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestGenerateContent(t *testing.T) {
+	Convey("Test generateContent", t, func() {
+		var received *v1.ChatRequest
+		s := &Server{chatSvc: &mockChatServer{
+			chatFunc: func(_ context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error) {
+				received = req
+				return &v1.ChatResponse{
+					Id:    "resp-1",
+					Model: "qwen3:8b",
+					Message: &v1.Message{
+						Role:     v1.Role_ROLE_MODEL,
+						Contents: []*v1.Content{{Content: v1.NewTextContent("Hi!")}},
+					},
+					Status: v1.ChatStatus_CHAT_STATUS_COMPLETED,
+				}, nil
+			},
+		}}
+
+		ctx := newMockHTTPContext("qwen3:8b:generateContent", `{"contents":[{"role":"user","parts":[{"text":"Hello"}]}]}`)
+		So(s.handleModelMethod(ctx), ShouldBeNil)
+
+		Convey("should take the model before the last colon", func() {
+			So(received.Model, ShouldEqual, "qwen3:8b")
+			So(received.Messages[0].Contents[0].GetText().GetText(), ShouldEqual, "Hello")
+		})
+
+		Convey("should respond with a GenerateContentResponse", func() {
+			So(ctx.statusCode, ShouldEqual, http.StatusOK)
+
+			var resp genai.GenerateContentResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.Candidates[0].Content.Parts[0].Text, ShouldEqual, "Hi!")
+			So(resp.Candidates[0].FinishReason, ShouldEqual, genai.FinishReasonStop)
+		})
+	})
+}
+
+func TestStreamGenerateContent(t *testing.T) {
+	Convey("Test streamGenerateContent", t, func() {
+		s := &Server{chatSvc: &mockChatServer{
+			chatStreamFunc: func(_ *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error {
+				return streamEvents(stream)
+			},
+		}}
+		body := `{"contents":[{"role":"user","parts":[{"text":"Weather?"}]}],"generationConfig":{"thinkingConfig":{"includeThoughts":true}}}`
+
+		Convey("with alt=sse, chunks should be sent as events", func() {
+			ctx := newMockHTTPContext("gemini-3-flash:streamGenerateContent?alt=sse", body)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+			So(ctx.headers.Get("Content-Type"), ShouldEqual, "text/event-stream")
+
+			var chunks []*genai.GenerateContentResponse
+			for _, frame := range strings.Split(strings.TrimSpace(ctx.respBody.String()), "\r\n\r\n") {
+				data, ok := strings.CutPrefix(frame, "data: ")
+				So(ok, ShouldBeTrue)
+				var chunk genai.GenerateContentResponse
+				So(json.Unmarshal([]byte(data), &chunk), ShouldBeNil)
+				chunks = append(chunks, &chunk)
+			}
+
+			So(chunks, ShouldHaveLength, 4)
+			for _, chunk := range chunks {
+				So(chunk.ResponseID, ShouldEqual, "resp-1")
+				So(chunk.ModelVersion, ShouldEqual, "gemini-3-flash")
+			}
+			So(chunks[0].Candidates[0].Content.Parts[0].Thought, ShouldBeTrue)
+			So(chunks[1].Candidates[0].Content.Parts[0].Text, ShouldEqual, "Let me check.")
+
+			call := chunks[2].Candidates[0].Content.Parts[0]
+			So(call.FunctionCall.ID, ShouldEqual, "abc")
+			So(call.FunctionCall.Name, ShouldEqual, "get_weather")
+			So(call.FunctionCall.Args, ShouldResemble, map[string]any{"city": "Shanghai"})
+			So(string(call.ThoughtSignature), ShouldEqual, "signature")
+
+			So(chunks[3].Candidates[0].FinishReason, ShouldEqual, genai.FinishReasonStop)
+			So(chunks[3].UsageMetadata.TotalTokenCount, ShouldEqual, 15)
+		})
+
+		Convey("without alt=sse, chunks should be sent as a JSON array", func() {
+			ctx := newMockHTTPContext("gemini-3-flash:streamGenerateContent", body)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+
+			var chunks []*genai.GenerateContentResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &chunks), ShouldBeNil)
+			So(chunks, ShouldHaveLength, 4)
+		})
+
+		Convey("an error after the first chunk should close the JSON array", func() {
+			s.chatSvc.(*mockChatServer).chatStreamFunc = func(_ *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error {
+				if err := stream.Send(v1.NewChatEvent("resp-1", v1.NewContentSnapshotEvent(&v1.Content{Content: v1.NewTextContent("Hi")}))); err != nil {
+					return err
+				}
+				return entity.ErrClientQuotaExceeded
+			}
+			ctx := newMockHTTPContext("gemini-3-flash:streamGenerateContent", body)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+
+			var chunks []json.RawMessage
+			So(json.Unmarshal(ctx.respBody.Bytes(), &chunks), ShouldBeNil)
+			So(chunks, ShouldHaveLength, 2)
+
+			var errResp ErrorResponse
+			So(json.Unmarshal(chunks[1], &errResp), ShouldBeNil)
+			So(errResp.Error.Code, ShouldEqual, 429)
+			So(errResp.Error.Status, ShouldEqual, "RESOURCE_EXHAUSTED")
+		})
+
+		Convey("an error after the first event should be sent as an event", func() {
+			s.chatSvc.(*mockChatServer).chatStreamFunc = func(_ *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error {
+				if err := stream.Send(v1.NewChatEvent("resp-1", v1.NewContentSnapshotEvent(&v1.Content{Content: v1.NewTextContent("Hi")}))); err != nil {
+					return err
+				}
+				return entity.ErrNoUpstream
+			}
+			ctx := newMockHTTPContext("gemini-3-flash:streamGenerateContent?alt=sse", body)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+
+			frames := strings.Split(strings.TrimSpace(ctx.respBody.String()), "\r\n\r\n")
+			So(frames, ShouldHaveLength, 2)
+			So(frames[1], ShouldStartWith, `data: {"error":{"code":500`)
+		})
+
+		Convey("thoughts should be left out unless asked for", func() {
+			ctx := newMockHTTPContext("gemini-3-flash:streamGenerateContent", `{"contents":[]}`)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+
+			var chunks []*genai.GenerateContentResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &chunks), ShouldBeNil)
+			So(chunks, ShouldHaveLength, 3)
+			So(chunks[0].Candidates[0].Content.Parts[0].Thought, ShouldBeFalse)
+		})
+	})
+}
+
+func TestCountTokens(t *testing.T) {
+	Convey("Test countTokens", t, func() {
+		var received *v1.ChatRequest
+		s := &Server{chatSvc: &mockChatServer{
+			countTokensFunc: func(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error) {
+				received = req
+				return &v1.CountTokensResponse{Model: req.Model, InputTokens: 3, Estimated: true}, nil
+			},
+		}}
+
+		Convey("should count the wrapped generateContent request", func() {
+			ctx := newMockHTTPContext("gemini-3-flash:countTokens", `{"generateContentRequest":{"contents":[{"role":"user","parts":[{"text":"0123456789"}]}]}}`)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+			So(received.Model, ShouldEqual, "gemini-3-flash")
+			So(received.Messages, ShouldHaveLength, 1)
+
+			var resp CountTokensResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.TotalTokens, ShouldEqual, 3)
+		})
+
+		Convey("should count bare contents", func() {
+			ctx := newMockHTTPContext("gemini-3-flash:countTokens", `{"contents":[{"role":"user","parts":[{"text":"hi"}]},{"role":"model","parts":[{"text":"hello"}]}]}`)
+			So(s.handleModelMethod(ctx), ShouldBeNil)
+			So(received.Messages, ShouldHaveLength, 2)
+			So(received.Messages[1].Role, ShouldEqual, v1.Role_ROLE_MODEL)
+		})
+	})
+}
+
+func TestHandleModelMethod(t *testing.T) {
+	Convey("Test handleModelMethod", t, func() {
+		s := &Server{}
+
+		Convey("should reject unknown methods", func() {
+			ctx := newMockHTTPContext("gemini-3-flash:predict", "{}")
+			err := s.handleModelMethod(ctx)
+			So(err, ShouldNotBeNil)
+
+			So(writeError(ctx, err), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusNotFound)
+
+			var resp ErrorResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error.Status, ShouldEqual, "NOT_FOUND")
+		})
+	})
+}
+
+func TestWriteError(t *testing.T) {
+	Convey("Test writeError", t, func() {
+		Convey("client quota errors should be rendered as exhausted resources", func() {
+			ctx := newMockHTTPContext("", "")
+			So(writeError(ctx, entity.ErrClientQuotaExceeded), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusTooManyRequests)
+
+			var resp ErrorResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error.Code, ShouldEqual, http.StatusTooManyRequests)
+			So(resp.Error.Status, ShouldEqual, "RESOURCE_EXHAUSTED")
+			So(resp.Error.Message, ShouldEqual, "client quota exceeded")
+		})
+
+		Convey("no upstream errors should be rendered as internal errors", func() {
+			ctx := newMockHTTPContext("", "")
+			So(writeError(ctx, entity.ErrNoUpstream), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusInternalServerError)
+
+			var resp ErrorResponse
+			So(json.Unmarshal(ctx.respBody.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error.Status, ShouldEqual, "INTERNAL")
+		})
+	})
+}
@@ -0,0 +1,316 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-kratos/kratos/v3/log"
+	"google.golang.org/genai"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+	"github.com/neuraxes/neurouter/internal/util/googleconv"
+)
+
+// modelResourcePrefix prefixes model names in the resource paths of the Gemini API.
+const modelResourcePrefix = "models/"
+
+func convertModelFromGoogle(model string) string {
+	return strings.TrimPrefix(model, modelResourcePrefix)
+}
+
+// includeThoughts reports whether the client asked for thought summaries.
+func includeThoughts(req *GenerateContentRequest) bool {
+	return req.GenerationConfig != nil &&
+		req.GenerationConfig.ThinkingConfig != nil &&
+		req.GenerationConfig.ThinkingConfig.IncludeThoughts
+}
+
+func convertChatRequestFromGoogle(model string, req *GenerateContentRequest) *v1.ChatRequest {
+	var messages []*v1.Message
+
+	if req.SystemInstruction != nil {
+		system := convertContentFromGoogle(req.SystemInstruction)
+		if len(system.Contents) > 0 {
+			system.Role = v1.Role_ROLE_SYSTEM
+			messages = append(messages, system)
+		}
+	}
+
+	for _, content := range req.Contents {
+		messages = append(messages, convertContentFromGoogle(content))
+	}
+
+	return &v1.ChatRequest{
+		Model:      convertModelFromGoogle(model),
+		Config:     convertGenerationConfigFromGoogle(req.GenerationConfig),
+		Messages:   messages,
+		Tools:      convertToolsFromGoogle(req.Tools),
+		ToolChoice: googleconv.ToolChoiceFrom(req.ToolConfig),
+	}
+}
+
+func convertGenerationConfigFromGoogle(googleConfig *genai.GenerationConfig) *v1.GenerationConfig {
+	if googleConfig == nil {
+		return nil
+	}
+
+	config := &v1.GenerationConfig{
+		Temperature:      googleConfig.Temperature,
+		TopP:             googleConfig.TopP,
+		FrequencyPenalty: googleConfig.FrequencyPenalty,
+		PresencePenalty:  googleConfig.PresencePenalty,
+		StopSequences:    googleConfig.StopSequences,
+	}
+	if googleConfig.MaxOutputTokens != 0 {
+		config.MaxTokens = new(int64(googleConfig.MaxOutputTokens))
+	}
+	if googleConfig.TopK != nil {
+		config.TopK = new(int64(*googleConfig.TopK))
+	}
+	if c := googleConfig.ThinkingConfig; c != nil {
+		config.ReasoningConfig = &v1.ReasoningConfig{
+			Effort: googleconv.ThinkingLevelFrom(c.ThinkingLevel),
+		}
+		if budget := c.ThinkingBudget; budget != nil {
+			switch {
+			case *budget == 0:
+				config.ReasoningConfig.Effort = v1.ReasoningEffort_REASONING_EFFORT_NONE
+			case *budget > 0:
+				// A negative budget asks for dynamic thinking, the default
+				config.ReasoningConfig.TokenBudget = uint32(*budget)
+			}
+		}
+	}
+
+	switch {
+	case googleConfig.ResponseJsonSchema != nil:
+		schema, err := util.StructFromAny(googleConfig.ResponseJsonSchema)
+		if err != nil {
+			log.Error("failed to convert google response schema", "error", err)
+			break
+		}
+		config.Grammar = &v1.GenerationConfig_Schema{Schema: schema}
+	case googleConfig.ResponseSchema != nil:
+		schema, err := convertSchemaFromGoogle(googleConfig.ResponseSchema)
+		if err != nil {
+			log.Error("failed to convert google response schema", "error", err)
+			break
+		}
+		config.Grammar = &v1.GenerationConfig_Schema{Schema: schema}
+	case googleConfig.ResponseMIMEType == "application/json":
+		config.Grammar = &v1.GenerationConfig_PresetGrammar{PresetGrammar: "json_object"}
+	}
+
+	return config
+}
+
+// convertSchemaFromGoogle converts an OpenAPI schema of the Gemini API into
+// a JSON schema, whose type names are lowercase.
+func convertSchemaFromGoogle(schema *genai.Schema) (*structpb.Struct, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	lowercaseSchemaTypes(fields)
+	return util.StructFromMap(fields)
+}
+
+func lowercaseSchemaTypes(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if s, ok := field.(string); ok && key == "type" {
+				v[key] = strings.ToLower(s)
+				continue
+			}
+			lowercaseSchemaTypes(field)
+		}
+	case []any:
+		for _, item := range v {
+			lowercaseSchemaTypes(item)
+		}
+	}
+}
+
+func convertToolsFromGoogle(googleTools []*genai.Tool) []*v1.Tool {
+	var tools []*v1.Tool
+	for _, tool := range googleTools {
+		if len(tool.FunctionDeclarations) == 0 {
+			log.Error("unsupported google tool", "tool", tool)
+			continue
+		}
+		for _, decl := range tool.FunctionDeclarations {
+			function := &v1.Tool_Function{
+				Name:        decl.Name,
+				Description: decl.Description,
+			}
+
+			var err error
+			switch {
+			case decl.ParametersJsonSchema != nil:
+				function.InputSchema, err = util.StructFromAny(decl.ParametersJsonSchema)
+			case decl.Parameters != nil:
+				function.InputSchema, err = convertSchemaFromGoogle(decl.Parameters)
+			}
+			if err != nil {
+				log.Error("failed to convert google function schema", "error", err)
+				continue
+			}
+
+			tools = append(tools, &v1.Tool{Tool: &v1.Tool_Function_{Function: function}})
+		}
+	}
+	return tools
+}
+
+func convertContentFromGoogle(content *genai.Content) *v1.Message {
+	message := &v1.Message{Role: v1.Role_ROLE_USER}
+	if content.Role == genai.RoleModel {
+		message.Role = v1.Role_ROLE_MODEL
+	}
+
+	for _, part := range content.Parts {
+		if c := googleconv.PartFrom(part); c != nil {
+			message.Contents = append(message.Contents, c)
+		}
+	}
+	return message
+}
+
+func convertChatResponseToGoogle(resp *v1.ChatResponse, includeThoughts bool) *genai.GenerateContentResponse {
+	content := &genai.Content{Role: genai.RoleModel}
+	for _, c := range resp.GetMessage().GetContents() {
+		if part := convertContentToGooglePart(c, includeThoughts); part != nil {
+			content.Parts = append(content.Parts, part)
+		}
+	}
+
+	return &genai.GenerateContentResponse{
+		ResponseID:   resp.Id,
+		ModelVersion: resp.Model,
+		Candidates: []*genai.Candidate{{
+			Content:      content,
+			FinishReason: googleconv.StatusTo(resp.Status),
+		}},
+		UsageMetadata: googleconv.UsageTo(resp.GetStatistics().GetUsage()),
+	}
+}
+
+func convertContentToGooglePart(content *v1.Content, includeThoughts bool) *genai.Part {
+	var part *genai.Part
+	switch c := content.Content.(type) {
+	case *v1.Content_Text:
+		if content.IsReasoning() && !includeThoughts {
+			// Keep only the signature of thoughts the client did not ask for
+			if content.Signature == "" {
+				return nil
+			}
+			part = &genai.Part{}
+		} else {
+			part = &genai.Part{Text: c.Text.GetText(), Thought: content.IsReasoning()}
+		}
+	case *v1.Content_Image:
+		part = googleconv.ImageToPart(c.Image)
+	case *v1.Content_ToolUse:
+		part = &genai.Part{FunctionCall: convertToolUseToGoogle(c.ToolUse.Id, c.ToolUse.Name, c.ToolUse.GetTextualInput())}
+	default:
+		return nil
+	}
+	if part == nil {
+		return nil
+	}
+
+	if content.Signature != "" {
+		part.ThoughtSignature = googleconv.EncodeThoughtSignature(content.Provenance, content.Signature)
+	}
+	return part
+}
+
+// convertToolUseToGoogle converts a tool use into a function call, echoing
+// the id the client or Gemini assigned.
+func convertToolUseToGoogle(id, name, input string) *genai.FunctionCall {
+	return googleconv.ToolUseTo(googleconv.ToolCallID(id, name), name, input)
+}
+
+func convertEmbedRequestFromGoogle(model string, reqs []*EmbedContentRequest) *v1.EmbedRequest {
+	req := &v1.EmbedRequest{
+		Model: convertModelFromGoogle(model),
+	}
+	for _, r := range reqs {
+		input := &v1.EmbedInput{}
+		if r.Content != nil {
+			input.Contents = convertContentFromGoogle(r.Content).Contents
+		}
+		req.Inputs = append(req.Inputs, input)
+	}
+
+	// The Gemini API configures each input on its own, while neurouter
+	// configures the whole batch, so the first input decides
+	if len(reqs) > 0 {
+		if d := reqs[0].OutputDimensionality; d != nil && *d > 0 {
+			req.Dimensions = new(uint32(*d))
+		}
+		req.TaskType = convertTaskTypeFromGoogle(reqs[0].TaskType)
+	}
+	return req
+}
+
+func convertTaskTypeFromGoogle(taskType string) v1.EmbedTaskType {
+	switch taskType {
+	case "RETRIEVAL_QUERY", "QUESTION_ANSWERING", "FACT_VERIFICATION", "CODE_RETRIEVAL_QUERY":
+		return v1.EmbedTaskType_EMBED_TASK_TYPE_QUERY
+	case "RETRIEVAL_DOCUMENT":
+		return v1.EmbedTaskType_EMBED_TASK_TYPE_DOCUMENT
+	default:
+		return v1.EmbedTaskType_EMBED_TASK_TYPE_UNSPECIFIED
+	}
+}
+
+func convertEmbeddingsToGoogle(resp *v1.EmbedResponse) []*ContentEmbedding {
+	embeddings := make([]*ContentEmbedding, len(resp.Embeddings))
+	for i, e := range resp.Embeddings {
+		embeddings[i] = &ContentEmbedding{Values: e.Values}
+	}
+	return embeddings
+}
+
+func convertModelToGoogle(model *v1.ModelSpec) *Model {
+	m := &Model{
+		Name:                       modelResourcePrefix + model.Id,
+		BaseModelID:                model.Id,
+		DisplayName:                model.Name,
+		InputTokenLimit:            model.ContextLength,
+		SupportedGenerationMethods: []string{},
+	}
+	for _, c := range model.Capabilities {
+		switch c {
+		case v1.Capability_CAPABILITY_CHAT:
+			m.SupportedGenerationMethods = append(m.SupportedGenerationMethods,
+				"generateContent", "streamGenerateContent", "countTokens")
+		case v1.Capability_CAPABILITY_EMBEDDING:
+			m.SupportedGenerationMethods = append(m.SupportedGenerationMethods,
+				"embedContent", "batchEmbedContents")
+		}
+	}
+	return m
+}
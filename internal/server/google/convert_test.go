@@ -0,0 +1,294 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genai"
+	"google.golang.org/protobuf/proto"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+	"github.com/neuraxes/neurouter/internal/util/googleconv"
+)
+
+func TestConvertChatRequestFromGoogle(t *testing.T) {
+	Convey("Test convertChatRequestFromGoogle", t, func() {
+		body := `{
+			"systemInstruction": {"parts": [{"text": "You are helpful."}]},
+			"contents": [
+				{"role": "user", "parts": [
+					{"text": "What is in this image?"},
+					{"inlineData": {"mimeType": "image/png", "data": "iVBORw0KGgo="}}
+				]},
+				{"role": "model", "parts": [
+					{"text": "Let me look.", "thought": true, "thoughtSignature": "c2lnbmF0dXJl"},
+					{"functionCall": {"id": "abc", "name": "get_weather", "args": {"city": "Shanghai"}}}
+				]},
+				{"role": "user", "parts": [
+					{"functionResponse": {"id": "abc", "name": "get_weather", "response": {"result": "sunny"}}}
+				]}
+			],
+			"tools": [{"functionDeclarations": [{
+				"name": "get_weather",
+				"description": "Get weather",
+				"parameters": {"type": "OBJECT", "properties": {"city": {"type": "STRING"}}, "required": ["city"]}
+			}]}],
+			"generationConfig": {
+				"maxOutputTokens": 1024,
+				"temperature": 0.5,
+				"topK": 40,
+				"stopSequences": ["END"],
+				"thinkingConfig": {"includeThoughts": true, "thinkingLevel": "HIGH"},
+				"responseMimeType": "application/json"
+			}
+		}`
+		var googleReq GenerateContentRequest
+		So(json.Unmarshal([]byte(body), &googleReq), ShouldBeNil)
+
+		req := convertChatRequestFromGoogle("models/gemini-3-flash", &googleReq)
+
+		Convey("should strip the resource prefix from the model", func() {
+			So(req.Model, ShouldEqual, "gemini-3-flash")
+		})
+
+		Convey("should convert the system instruction and contents", func() {
+			So(req.Messages, ShouldHaveLength, 4)
+			So(req.Messages[0].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+			So(req.Messages[0].Contents[0].GetText().GetText(), ShouldEqual, "You are helpful.")
+
+			image := req.Messages[1].Contents[1].GetImage()
+			So(image.MimeType, ShouldEqual, "image/png")
+			So(image.GetData(), ShouldResemble, []byte("\x89PNG\r\n\x1a\n"))
+
+			model := req.Messages[2]
+			So(model.Role, ShouldEqual, v1.Role_ROLE_MODEL)
+			So(model.Contents[0].IsReasoning(), ShouldBeTrue)
+			So(model.Contents[0].Signature, ShouldEqual, "c2lnbmF0dXJl")
+			So(model.Contents[0].Provenance, ShouldEqual, v1.Provenance_PROVENANCE_GOOGLE)
+			So(model.Contents[1].GetToolUse().Id, ShouldEqual, "get_weather:abc")
+			So(model.Contents[1].GetToolUse().GetTextualInput(), ShouldEqual, `{"city":"Shanghai"}`)
+
+			result := req.Messages[3].Contents[0].GetToolResult()
+			So(req.Messages[3].Role, ShouldEqual, v1.Role_ROLE_USER)
+			So(result.Id, ShouldEqual, "get_weather:abc")
+			So(result.GetTextualOutput(), ShouldEqual, "sunny")
+		})
+
+		Convey("should convert OpenAPI schemas to JSON schemas", func() {
+			So(req.Tools, ShouldHaveLength, 1)
+			function := req.Tools[0].GetFunction()
+			So(function.Name, ShouldEqual, "get_weather")
+			So(proto.Equal(function.InputSchema, util.MustStructFromMap(map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"city": map[string]any{"type": "string"}},
+				"required":   []any{"city"},
+			})), ShouldBeTrue)
+		})
+
+		Convey("should convert the generation config", func() {
+			So(req.Config.GetMaxTokens(), ShouldEqual, 1024)
+			So(req.Config.GetTemperature(), ShouldEqual, 0.5)
+			So(req.Config.GetTopK(), ShouldEqual, 40)
+			So(req.Config.StopSequences, ShouldResemble, []string{"END"})
+			So(req.Config.ReasoningConfig.Effort, ShouldEqual, v1.ReasoningEffort_REASONING_EFFORT_HIGH)
+			So(req.Config.GetPresetGrammar(), ShouldEqual, "json_object")
+		})
+	})
+}
+
+func TestConvertToolConfigFromGoogle(t *testing.T) {
+	Convey("Test convertToolConfigFromGoogle", t, func() {
+		convert := func(body string) *v1.ToolChoice {
+			var req GenerateContentRequest
+			So(json.Unmarshal([]byte(body), &req), ShouldBeNil)
+			return convertChatRequestFromGoogle("models/gemini", &req).ToolChoice
+		}
+
+		Convey("should force a single allowed function", func() {
+			toolChoice := convert(`{"toolConfig":{"functionCallingConfig":{"mode":"ANY","allowedFunctionNames":["get_weather"]}}}`)
+			So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL)
+			So(toolChoice.Name, ShouldEqual, "get_weather")
+		})
+
+		Convey("should require a call among several functions", func() {
+			toolChoice := convert(`{"toolConfig":{"functionCallingConfig":{"mode":"ANY"}}}`)
+			So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED)
+		})
+
+		Convey("should convert the other modes", func() {
+			So(convert(`{"toolConfig":{"functionCallingConfig":{"mode":"AUTO"}}}`).Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO)
+			So(convert(`{"toolConfig":{"functionCallingConfig":{"mode":"NONE"}}}`).Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE)
+			So(convert(`{}`), ShouldBeNil)
+		})
+	})
+}
+
+func TestConvertGenerationConfigFromGoogle(t *testing.T) {
+	Convey("Test convertGenerationConfigFromGoogle", t, func() {
+		Convey("a zero thinking budget should disable reasoning", func() {
+			config := convertGenerationConfigFromGoogle(&genai.GenerationConfig{
+				ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: new(int32(0))},
+			})
+			So(config.ReasoningConfig.Effort, ShouldEqual, v1.ReasoningEffort_REASONING_EFFORT_NONE)
+		})
+
+		Convey("a dynamic thinking budget should be left to the model", func() {
+			config := convertGenerationConfigFromGoogle(&genai.GenerationConfig{
+				ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: new(int32(-1))},
+			})
+			So(config.ReasoningConfig.Effort, ShouldEqual, v1.ReasoningEffort_REASONING_EFFORT_UNSPECIFIED)
+			So(config.ReasoningConfig.TokenBudget, ShouldEqual, 0)
+		})
+
+		Convey("a positive thinking budget should be kept", func() {
+			config := convertGenerationConfigFromGoogle(&genai.GenerationConfig{
+				ThinkingConfig: &genai.ThinkingConfig{ThinkingBudget: new(int32(2048))},
+			})
+			So(config.ReasoningConfig.TokenBudget, ShouldEqual, 2048)
+		})
+
+		Convey("a JSON response schema should become the grammar", func() {
+			config := convertGenerationConfigFromGoogle(&genai.GenerationConfig{
+				ResponseMIMEType:   "application/json",
+				ResponseJsonSchema: map[string]any{"type": "object"},
+			})
+			So(proto.Equal(config.GetSchema(), util.MustStructFromMap(map[string]any{"type": "object"})), ShouldBeTrue)
+		})
+	})
+}
+
+func TestConvertChatResponseToGoogle(t *testing.T) {
+	Convey("Test convertChatResponseToGoogle", t, func() {
+		resp := &v1.ChatResponse{
+			Id:    "resp-1",
+			Model: "gemini-3-flash",
+			Message: &v1.Message{
+				Role: v1.Role_ROLE_MODEL,
+				Contents: []*v1.Content{
+					{
+						Phase:     v1.ContentPhase_CONTENT_PHASE_REASONING,
+						Signature: "c2lnbmF0dXJl",
+						Content:   v1.NewTextContent("Thinking."),
+					},
+					{Content: v1.NewTextContent("Checking the weather.")},
+					{Content: &v1.Content_ToolUse{ToolUse: &v1.ToolUse{
+						Id:     "get_weather:abc",
+						Name:   "get_weather",
+						Inputs: []*v1.ToolUse_Input{{Input: &v1.ToolUse_Input_Text{Text: `{"city":"Shanghai"}`}}},
+					}}},
+				},
+			},
+			Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 10, OutputTokens: 30, ReasoningTokens: 20}},
+			Status:     v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE,
+		}
+
+		Convey("should include thoughts when asked", func() {
+			googleResp := convertChatResponseToGoogle(resp, true)
+			So(googleResp.ResponseID, ShouldEqual, "resp-1")
+			So(googleResp.ModelVersion, ShouldEqual, "gemini-3-flash")
+
+			candidate := googleResp.Candidates[0]
+			So(candidate.FinishReason, ShouldEqual, genai.FinishReasonStop)
+			So(candidate.Content.Parts, ShouldHaveLength, 3)
+			So(candidate.Content.Parts[0].Thought, ShouldBeTrue)
+			So(candidate.Content.Parts[0].Text, ShouldEqual, "Thinking.")
+			So(string(candidate.Content.Parts[0].ThoughtSignature), ShouldEqual, "signature")
+			So(candidate.Content.Parts[2].FunctionCall.ID, ShouldEqual, "abc")
+			So(candidate.Content.Parts[2].FunctionCall.Args, ShouldResemble, map[string]any{"city": "Shanghai"})
+
+			So(googleResp.UsageMetadata.PromptTokenCount, ShouldEqual, 10)
+			So(googleResp.UsageMetadata.CandidatesTokenCount, ShouldEqual, 10)
+			So(googleResp.UsageMetadata.ThoughtsTokenCount, ShouldEqual, 20)
+			So(googleResp.UsageMetadata.TotalTokenCount, ShouldEqual, 40)
+		})
+
+		Convey("should keep only the signature of thoughts otherwise", func() {
+			parts := convertChatResponseToGoogle(resp, false).Candidates[0].Content.Parts
+			So(parts, ShouldHaveLength, 3)
+			So(parts[0].Text, ShouldBeEmpty)
+			So(parts[0].Thought, ShouldBeFalse)
+			So(string(parts[0].ThoughtSignature), ShouldEqual, "signature")
+		})
+	})
+
+	Convey("Test thought signatures of other providers", t, func() {
+		resp := &v1.ChatResponse{
+			Message: &v1.Message{
+				Role: v1.Role_ROLE_MODEL,
+				Contents: []*v1.Content{{
+					Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Signature:  "anthropic-signature",
+					Provenance: v1.Provenance_PROVENANCE_ANTHROPIC,
+					Content:    v1.NewTextContent("Thinking."),
+				}},
+			},
+			Status: v1.ChatStatus_CHAT_STATUS_COMPLETED,
+		}
+
+		Convey("should carry the signature as tagged text", func() {
+			parts := convertChatResponseToGoogle(resp, true).Candidates[0].Content.Parts
+			So(parts, ShouldHaveLength, 1)
+			So(string(parts[0].ThoughtSignature), ShouldEqual, "anthropic:anthropic-signature")
+		})
+
+		Convey("should restore the provenance when the signature is replayed", func() {
+			content := googleconv.PartFrom(&genai.Part{
+				Text:             "Thinking.",
+				Thought:          true,
+				ThoughtSignature: []byte("anthropic:anthropic-signature"),
+			})
+			So(content.Signature, ShouldEqual, "anthropic-signature")
+			So(content.Provenance, ShouldEqual, v1.Provenance_PROVENANCE_ANTHROPIC)
+		})
+	})
+}
+
+func TestConvertEmbedRequestFromGoogle(t *testing.T) {
+	Convey("Test convertEmbedRequestFromGoogle", t, func() {
+		req := convertEmbedRequestFromGoogle("models/gemini-embedding-001", []*EmbedContentRequest{
+			{
+				Content:              &genai.Content{Parts: []*genai.Part{{Text: "hello"}}},
+				TaskType:             "RETRIEVAL_QUERY",
+				OutputDimensionality: new(int32(768)),
+			},
+			{Content: &genai.Content{Parts: []*genai.Part{{Text: "world"}}}},
+		})
+
+		So(req.Model, ShouldEqual, "gemini-embedding-001")
+		So(req.Inputs, ShouldHaveLength, 2)
+		So(req.Inputs[1].Contents[0].GetText().GetText(), ShouldEqual, "world")
+		So(req.GetDimensions(), ShouldEqual, 768)
+		So(req.TaskType, ShouldEqual, v1.EmbedTaskType_EMBED_TASK_TYPE_QUERY)
+	})
+}
+
+func TestConvertModelToGoogle(t *testing.T) {
+	Convey("Test convertModelToGoogle", t, func() {
+		model := convertModelToGoogle(&v1.ModelSpec{
+			Id:            "gemini-3-flash",
+			Name:          "Gemini 3 Flash",
+			ContextLength: 1048576,
+			Capabilities:  []v1.Capability{v1.Capability_CAPABILITY_CHAT, v1.Capability_CAPABILITY_TOOL_USE},
+		})
+		So(model.Name, ShouldEqual, "models/gemini-3-flash")
+		So(model.DisplayName, ShouldEqual, "Gemini 3 Flash")
+		So(model.InputTokenLimit, ShouldEqual, 1048576)
+		So(model.SupportedGenerationMethods, ShouldResemble, []string{"generateContent", "streamGenerateContent", "countTokens"})
+	})
+}
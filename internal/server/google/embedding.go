@@ -0,0 +1,67 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-kratos/kratos/v3/transport/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func (s *Server) embed(httpCtx http.Context, req *v1.EmbedRequest) (*v1.EmbedResponse, error) {
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.embedSvc.Embed(ctx, req.(*v1.EmbedRequest))
+	})
+	resp, err := m(httpCtx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*v1.EmbedResponse), nil
+}
+
+func (s *Server) handleEmbedContent(httpCtx http.Context, model string) error {
+	var googleReq EmbedContentRequest
+	if err := json.NewDecoder(httpCtx.Request().Body).Decode(&googleReq); err != nil {
+		return err
+	}
+
+	resp, err := s.embed(httpCtx, convertEmbedRequestFromGoogle(model, []*EmbedContentRequest{&googleReq}))
+	if err != nil {
+		return err
+	}
+
+	googleResp := &EmbedContentResponse{Embedding: &ContentEmbedding{}}
+	if embeddings := convertEmbeddingsToGoogle(resp); len(embeddings) > 0 {
+		googleResp.Embedding = embeddings[0]
+	}
+	return httpCtx.Result(200, googleResp)
+}
+
+func (s *Server) handleBatchEmbedContents(httpCtx http.Context, model string) error {
+	var googleReq BatchEmbedContentsRequest
+	if err := json.NewDecoder(httpCtx.Request().Body).Decode(&googleReq); err != nil {
+		return err
+	}
+
+	resp, err := s.embed(httpCtx, convertEmbedRequestFromGoogle(model, googleReq.Requests))
+	if err != nil {
+		return err
+	}
+
+	return httpCtx.Result(200, &BatchEmbedContentsResponse{Embeddings: convertEmbeddingsToGoogle(resp)})
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"net/http"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	khttp "github.com/go-kratos/kratos/v3/transport/http"
+)
+
+// writeError renders err in the Gemini API error format, so that Gemini
+// clients recognise rate limits and other failures.
+func writeError(httpCtx khttp.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	resp := convertErrorToGoogle(err)
+	return httpCtx.JSON(int(resp.Error.Code), resp)
+}
+
+func convertErrorToGoogle(err error) *ErrorResponse {
+	e := errors.FromError(err)
+
+	status := "INTERNAL"
+	switch code := int(e.Code); {
+	case code == http.StatusTooManyRequests:
+		status = "RESOURCE_EXHAUSTED"
+	case code == http.StatusUnauthorized:
+		status = "UNAUTHENTICATED"
+	case code == http.StatusForbidden:
+		status = "PERMISSION_DENIED"
+	case code == http.StatusNotFound:
+		status = "NOT_FOUND"
+	case code == http.StatusServiceUnavailable:
+		status = "UNAVAILABLE"
+	case code == http.StatusGatewayTimeout:
+		status = "DEADLINE_EXCEEDED"
+	case code >= 400 && code < 500:
+		status = "INVALID_ARGUMENT"
+	}
+	return &ErrorResponse{
+		Error: ErrorBody{Code: e.Code, Message: e.Message, Status: status},
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"strings"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	"github.com/go-kratos/kratos/v3/transport/http"
+	otellog "go.opentelemetry.io/otel/log"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/service"
+)
+
+type Server struct {
+	modelSvc   v1.ModelServer
+	chatSvc    v1.ChatServer
+	embedSvc   v1.EmbeddingServer
+	otelLogger otellog.Logger
+}
+
+func NewServer(svc *service.RouterService, loggerProvider otellog.LoggerProvider) (s *Server) {
+	s = &Server{
+		modelSvc: svc,
+		chatSvc:  svc,
+		embedSvc: svc,
+	}
+	if loggerProvider != nil {
+		s.otelLogger = loggerProvider.Logger("neurouter.server.google")
+	}
+	return
+}
+
+func (s *Server) RegisterRoutes(srv *http.Server) {
+	r := srv.Route("/")
+
+	r.GET("/v1beta/models", func(ctx http.Context) error {
+		return writeError(ctx, s.handleListModels(ctx))
+	})
+	// Model names may contain slashes, and methods follow the name after a colon
+	r.GET("/v1beta/models/{model:.+}", func(ctx http.Context) error {
+		return writeError(ctx, s.handleGetModel(ctx, ctx.Vars().Get("model")))
+	})
+	r.POST("/v1beta/models/{model:.+}", func(ctx http.Context) error {
+		return writeError(ctx, s.handleModelMethod(ctx))
+	})
+}
+
+// handleModelMethod dispatches a request to a method of a model, addressed as
+// "models/{model}:{method}". Model names may contain colons themselves, so the
+// method follows the last one.
+func (s *Server) handleModelMethod(ctx http.Context) error {
+	target := ctx.Vars().Get("model")
+	i := strings.LastIndex(target, ":")
+	if i < 0 {
+		return errors.NotFound("", "method is missing")
+	}
+	model, method := target[:i], target[i+1:]
+
+	switch method {
+	case "generateContent":
+		return s.handleGenerateContent(ctx, model)
+	case "streamGenerateContent":
+		return s.handleStreamGenerateContent(ctx, model)
+	case "countTokens":
+		return s.handleCountTokens(ctx, model)
+	case "embedContent":
+		return s.handleEmbedContent(ctx, model)
+	case "batchEmbedContents":
+		return s.handleBatchEmbedContents(ctx, model)
+	default:
+		return errors.NotFound("", "method "+method+" is not found")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	"github.com/go-kratos/kratos/v3/transport/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func (s *Server) listModels(httpCtx http.Context) (*v1.ListModelResponse, error) {
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.modelSvc.ListModel(ctx, &v1.ListModelRequest{})
+	})
+	r, err := m(httpCtx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return r.(*v1.ListModelResponse), nil
+}
+
+func (s *Server) handleListModels(httpCtx http.Context) error {
+	resp, err := s.listModels(httpCtx)
+	if err != nil {
+		return err
+	}
+
+	googleResp := &ListModelsResponse{Models: []*Model{}}
+	for _, model := range resp.Models {
+		googleResp.Models = append(googleResp.Models, convertModelToGoogle(model))
+	}
+	return httpCtx.Result(200, googleResp)
+}
+
+func (s *Server) handleGetModel(httpCtx http.Context, name string) error {
+	resp, err := s.listModels(httpCtx)
+	if err != nil {
+		return err
+	}
+
+	id := convertModelFromGoogle(name)
+	for _, model := range resp.Models {
+		if model.Id == id {
+			return httpCtx.Result(200, convertModelToGoogle(model))
+		}
+	}
+	return errors.NotFound("", "model "+name+" is not found")
+}
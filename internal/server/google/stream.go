@@ -0,0 +1,235 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/go-kratos/kratos/v3/transport/http"
+	"google.golang.org/genai"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util/googleconv"
+)
+
+// pendingToolUse buffers a streamed tool use, as Gemini sends each function
+// call whole.
+type pendingToolUse struct {
+	id         string
+	name       string
+	input      strings.Builder
+	signature  string
+	provenance v1.Provenance
+}
+
+// generateContentStreamServer writes chat events as GenerateContentResponse
+// chunks, either as server-sent events or as the elements of a JSON array.
+type generateContentStreamServer struct {
+	v1.Chat_ChatStreamServer
+	ctx             context.Context
+	httpCtx         http.Context
+	buffer          *bytes.Buffer
+	sse             bool
+	includeThoughts bool
+
+	chunks       int
+	responseID   string
+	modelVersion string
+	starts       map[uint32]*v1.ContentStart
+	toolUses     map[uint32]*pendingToolUse
+}
+
+func (s *generateContentStreamServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *generateContentStreamServer) write(data []byte) error {
+	if s.buffer != nil {
+		s.buffer.Write(data)
+	}
+
+	_, err := s.httpCtx.Response().Write(data)
+	if err != nil {
+		return err
+	}
+	s.httpCtx.Response().(http.Flusher).Flush()
+	return nil
+}
+
+func (s *generateContentStreamServer) sendChunk(candidate *genai.Candidate, usage *v1.Usage) error {
+	chunk := &genai.GenerateContentResponse{
+		ResponseID:    s.responseID,
+		ModelVersion:  s.modelVersion,
+		Candidates:    []*genai.Candidate{candidate},
+		UsageMetadata: googleconv.UsageTo(usage),
+	}
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+
+	var frame []byte
+	switch {
+	case s.sse:
+		frame = append([]byte("data: "), data...)
+		frame = append(frame, "\r\n\r\n"...)
+	case s.chunks == 0:
+		frame = append([]byte("["), data...)
+	default:
+		frame = append([]byte(",\r\n"), data...)
+	}
+	s.chunks++
+	return s.write(frame)
+}
+
+func (s *generateContentStreamServer) sendPart(part *genai.Part) error {
+	return s.sendChunk(&genai.Candidate{
+		Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{part}},
+	}, nil)
+}
+
+// fail ends a stream that broke after chunks were sent with an error object,
+// as its status can no longer be set. Errors before any chunk are returned to
+// be rendered as usual.
+func (s *generateContentStreamServer) fail(err error) error {
+	if s.chunks == 0 {
+		return err
+	}
+	data, marshalErr := json.Marshal(convertErrorToGoogle(err))
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	var frame []byte
+	if s.sse {
+		frame = append([]byte("data: "), data...)
+		frame = append(frame, "\r\n\r\n"...)
+	} else {
+		frame = append([]byte(",\r\n"), data...)
+		frame = append(frame, ']')
+	}
+	return s.write(frame)
+}
+
+// close terminates the JSON array of a stream that is not sent as events.
+func (s *generateContentStreamServer) close() error {
+	if s.sse {
+		return nil
+	}
+	if s.chunks == 0 {
+		return s.write([]byte("[]"))
+	}
+	return s.write([]byte("]"))
+}
+
+func (s *generateContentStreamServer) Send(event *v1.ChatEvent) error {
+	switch e := event.Event.(type) {
+	case *v1.ChatEvent_MessageStart:
+		s.responseID = e.MessageStart.GetId()
+		s.modelVersion = e.MessageStart.GetModel()
+
+	case *v1.ChatEvent_ContentStart:
+		return s.startContent(e.ContentStart)
+
+	case *v1.ChatEvent_ContentDelta:
+		return s.deltaContent(e.ContentDelta)
+
+	case *v1.ChatEvent_ContentStop:
+		return s.stopContent(e.ContentStop.GetIndex())
+
+	case *v1.ChatEvent_ContentSnapshot:
+		if part := convertContentToGooglePart(e.ContentSnapshot, s.includeThoughts); part != nil {
+			return s.sendPart(part)
+		}
+
+	case *v1.ChatEvent_MessageStop:
+		return s.sendChunk(&genai.Candidate{
+			Content:      &genai.Content{Role: genai.RoleModel},
+			FinishReason: googleconv.StatusTo(e.MessageStop.GetStatus()),
+		}, event.Usage)
+	}
+
+	return nil
+}
+
+func (s *generateContentStreamServer) startContent(start *v1.ContentStart) error {
+	if s.starts == nil {
+		s.starts = map[uint32]*v1.ContentStart{}
+		s.toolUses = map[uint32]*pendingToolUse{}
+	}
+	s.starts[start.GetIndex()] = start
+
+	if toolUse := start.GetToolUse(); toolUse != nil {
+		s.toolUses[start.GetIndex()] = &pendingToolUse{
+			id:         toolUse.GetId(),
+			name:       toolUse.GetName(),
+			provenance: start.GetProvenance(),
+		}
+	}
+	return nil
+}
+
+func (s *generateContentStreamServer) deltaContent(delta *v1.ContentDelta) error {
+	index := delta.GetIndex()
+	start := s.starts[index]
+	reasoning := start.GetPhase() == v1.ContentPhase_CONTENT_PHASE_REASONING
+
+	if toolUse, ok := s.toolUses[index]; ok {
+		switch d := delta.Delta.(type) {
+		case *v1.ContentDelta_ToolInputText:
+			toolUse.input.WriteString(d.ToolInputText)
+		case *v1.ContentDelta_Signature:
+			toolUse.signature = d.Signature
+		}
+		return nil
+	}
+
+	switch d := delta.Delta.(type) {
+	case *v1.ContentDelta_Text:
+		if reasoning && !s.includeThoughts {
+			return nil
+		}
+		return s.sendPart(&genai.Part{Text: d.Text, Thought: reasoning})
+	case *v1.ContentDelta_Signature:
+		sig := googleconv.EncodeThoughtSignature(start.GetProvenance(), d.Signature)
+		if sig == nil {
+			return nil
+		}
+		return s.sendPart(&genai.Part{
+			Thought:          reasoning && s.includeThoughts,
+			ThoughtSignature: sig,
+		})
+	}
+	return nil
+}
+
+func (s *generateContentStreamServer) stopContent(index uint32) error {
+	delete(s.starts, index)
+
+	toolUse, ok := s.toolUses[index]
+	if !ok {
+		return nil
+	}
+	delete(s.toolUses, index)
+
+	part := &genai.Part{FunctionCall: convertToolUseToGoogle(toolUse.id, toolUse.name, toolUse.input.String())}
+	if toolUse.signature != "" {
+		part.ThoughtSignature = googleconv.EncodeThoughtSignature(toolUse.provenance, toolUse.signature)
+	}
+	return s.sendPart(part)
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import "google.golang.org/genai"
+
+// GenerateContentRequest is the body of generateContent and
+// streamGenerateContent. Contents and parts use the genai types, which
+// serialize in the wire format of the Gemini API.
+type GenerateContentRequest struct {
+	Model             string                  `json:"model,omitempty"`
+	Contents          []*genai.Content        `json:"contents"`
+	SystemInstruction *genai.Content          `json:"systemInstruction,omitempty"`
+	Tools             []*genai.Tool           `json:"tools,omitempty"`
+	ToolConfig        *genai.ToolConfig       `json:"toolConfig,omitempty"`
+	GenerationConfig  *genai.GenerationConfig `json:"generationConfig,omitempty"`
+	SafetySettings    []*genai.SafetySetting  `json:"safetySettings,omitempty"`
+	CachedContent     string                  `json:"cachedContent,omitempty"`
+}
+
+// CountTokensRequest is the body of countTokens. Clients either send the
+// contents alone or the full request they are about to generate with.
+type CountTokensRequest struct {
+	Contents               []*genai.Content        `json:"contents,omitempty"`
+	GenerateContentRequest *GenerateContentRequest `json:"generateContentRequest,omitempty"`
+}
+
+type CountTokensResponse struct {
+	TotalTokens int32 `json:"totalTokens"`
+}
+
+type EmbedContentRequest struct {
+	Model                string         `json:"model,omitempty"`
+	Content              *genai.Content `json:"content"`
+	TaskType             string         `json:"taskType,omitempty"`
+	Title                string         `json:"title,omitempty"`
+	OutputDimensionality *int32         `json:"outputDimensionality,omitempty"`
+}
+
+type ContentEmbedding struct {
+	Values []float32 `json:"values"`
+}
+
+type EmbedContentResponse struct {
+	Embedding *ContentEmbedding `json:"embedding"`
+}
+
+type BatchEmbedContentsRequest struct {
+	Requests []*EmbedContentRequest `json:"requests"`
+}
+
+type BatchEmbedContentsResponse struct {
+	Embeddings []*ContentEmbedding `json:"embeddings"`
+}
+
+type Model struct {
+	Name                       string   `json:"name"`
+	BaseModelID                string   `json:"baseModelId,omitempty"`
+	DisplayName                string   `json:"displayName,omitempty"`
+	InputTokenLimit            uint32   `json:"inputTokenLimit,omitempty"`
+	SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+}
+
+type ListModelsResponse struct {
+	Models []*Model `json:"models"`
+}
+
+type ErrorBody struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
@@ -41,11 +41,12 @@ func NewGRPCServer(
 		tracing.Server(tracing.WithTracerProvider(tracerProvider)),
 		logging.Server(logger),
 	}
-	streamMiddlewares := middlewares
-	j := jwtAuth(c)
-	if j != nil {
-		streamMiddlewares = append(slices.Clone(middlewares), j)
+	var authMiddlewares []middleware.Middleware
+	if j := jwtAuth(c); j != nil {
+		authMiddlewares = append(authMiddlewares, j)
 	}
+	authMiddlewares = append(authMiddlewares, clientIdentity(c.GetClientIdentity()))
+	streamMiddlewares := append(slices.Clone(middlewares), authMiddlewares...)
 
 	var opts = []grpc.ServerOption{
 		grpc.Middleware(middlewares...),
@@ -64,10 +65,14 @@ func NewGRPCServer(
 	v1.RegisterModelServer(srv, svc)
 	v1.RegisterChatServer(srv, svc)
 	v1.RegisterEmbeddingServer(srv, svc)
-
-	if j != nil {
-		srv.Use("/neurouter.v1.*", j)
+	v1.RegisterCompletionServer(srv, svc)
+	if adminEnabled(c, logger) {
+		v1.RegisterAdminServer(srv, svc)
 	}
 
+	srv.Use("/neurouter.v1.*", authMiddlewares...)
+	// Only the longest matching selector applies, so admin routes repeat the auth chain
+	srv.Use("/neurouter.v1.Admin/*", append(slices.Clone(authMiddlewares), adminAuth(c.GetAdmin()))...)
+
 	return srv
 }
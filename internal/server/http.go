@@ -15,9 +15,12 @@
 package server
 
 import (
+	"context"
 	"log/slog"
+	"slices"
 
 	"github.com/go-kratos/kratos/contrib/otel/v3/tracing"
+	"github.com/go-kratos/kratos/v3/middleware"
 	"github.com/go-kratos/kratos/v3/middleware/logging"
 	"github.com/go-kratos/kratos/v3/middleware/recovery"
 	"github.com/go-kratos/kratos/v3/transport/http"
@@ -29,6 +32,7 @@ import (
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/conf"
 	"github.com/neuraxes/neurouter/internal/server/anthropic"
+	"github.com/neuraxes/neurouter/internal/server/google"
 	"github.com/neuraxes/neurouter/internal/server/ollama"
 	"github.com/neuraxes/neurouter/internal/server/openai"
 	"github.com/neuraxes/neurouter/internal/service"
@@ -56,6 +60,34 @@ func newCORSFilter(cors *conf.Server_HTTP_CORS) http.FilterFunc {
 	return handlers.CORS(options...)
 }
 
+// registerSharedModelRoutes serves the model paths that several APIs share,
+// ahead of their own routes. Anthropic clients always send the
+// anthropic-version header and are answered in the Anthropic format, while
+// other clients keep the native (/v1/models) and OpenAI (/models) responses.
+func registerSharedModelRoutes(srv *http.Server, svc *service.RouterService, openaiSrv *openai.Server, anthropicSrv *anthropic.Server) {
+	r := srv.Route("/")
+	r.GET("/models", func(ctx http.Context) error {
+		if ctx.Header().Get("anthropic-version") != "" {
+			return anthropicSrv.HandleListModels(ctx)
+		}
+		return openaiSrv.HandleListModels(ctx)
+	})
+	r.GET("/v1/models", func(ctx http.Context) error {
+		if ctx.Header().Get("anthropic-version") != "" {
+			return anthropicSrv.HandleListModels(ctx)
+		}
+		http.SetOperation(ctx, v1.OperationModelListModel)
+		h := ctx.Middleware(func(ctx context.Context, req any) (any, error) {
+			return svc.ListModel(ctx, req.(*v1.ListModelRequest))
+		})
+		out, err := h(ctx, &v1.ListModelRequest{})
+		if err != nil {
+			return err
+		}
+		return ctx.Result(200, out)
+	})
+}
+
 func NewHTTPServer(
 	c *conf.Server,
 	svc *service.RouterService,
@@ -91,19 +123,32 @@ func NewHTTPServer(
 		opts = append(opts, http.Timeout(c.Http.Timeout.AsDuration()))
 	}
 	srv := http.NewServer(opts...)
+	openaiSrv := openai.NewServer(svc, loggerProvider, logger)
+	anthropicSrv := anthropic.NewServer(svc, loggerProvider)
+	registerSharedModelRoutes(srv, svc, openaiSrv, anthropicSrv)
 	v1.RegisterModelHTTPServer(srv, svc)
 	v1.RegisterChatHTTPServer(srv, svc)
 	v1.RegisterEmbeddingHTTPServer(srv, svc)
-	openai.NewServer(svc, loggerProvider).RegisterRoutes(srv)
+	v1.RegisterCompletionHTTPServer(srv, svc)
+	if adminEnabled(c, logger) {
+		v1.RegisterAdminHTTPServer(srv, svc)
+	}
+	openaiSrv.RegisterRoutes(srv)
 	ollama.NewServer(svc).RegisterRoutes(srv)
-	anthropic.NewServer(svc, loggerProvider).RegisterRoutes(srv)
+	anthropicSrv.RegisterRoutes(srv)
+	google.NewServer(svc, loggerProvider).RegisterRoutes(srv)
 
 	// Register /metrics endpoint directly on mux, bypassing Kratos middleware (including JWT)
 	srv.Handle("/metrics", promhttp.Handler())
 
+	var ms []middleware.Middleware
 	if j := jwtAuth(c); j != nil {
-		srv.Use("/*", j)
+		ms = append(ms, j)
 	}
+	ms = append(ms, clientIdentity(c.GetClientIdentity()), rateLimitHeaders())
+	srv.Use("/*", ms...)
+	// Only the longest matching selector applies, so admin routes repeat the auth chain
+	srv.Use("/neurouter.v1.Admin/*", append(slices.Clone(ms), adminAuth(c.GetAdmin()))...)
 
 	return srv
 }
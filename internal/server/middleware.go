@@ -17,13 +17,18 @@ package server
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 
 	"github.com/go-kratos/kratos/contrib/middleware/jwt/v3"
 	"github.com/go-kratos/kratos/v3/middleware"
 	"github.com/go-kratos/kratos/v3/middleware/logging"
+	"github.com/go-kratos/kratos/v3/transport"
 	jwt5 "github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc"
 
+	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/conf"
 )
 
@@ -38,6 +43,109 @@ func jwtAuth(c *conf.Server) middleware.Middleware {
 	})
 }
 
+// clientIdentity returns a middleware that attaches the identity of the calling
+// client to the context, for per-client quotas. It must run after jwtAuth.
+func clientIdentity(c *conf.ClientIdentity) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if client := identifyClient(ctx, c); client != "" {
+				ctx = entity.NewClientContext(ctx, client)
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// identifyClient extracts the client identity configured by c from ctx.
+func identifyClient(ctx context.Context, c *conf.ClientIdentity) string {
+	switch c.GetSource() {
+	case conf.ClientIdentity_SOURCE_JWT_CLAIM:
+		claims, ok := jwt.FromContext(ctx)
+		if !ok {
+			return ""
+		}
+		mapClaims, ok := claims.(jwt5.MapClaims)
+		if !ok {
+			return ""
+		}
+		if value, ok := mapClaims[c.GetClaim()]; ok && value != nil {
+			return fmt.Sprint(value)
+		}
+		return ""
+	case conf.ClientIdentity_SOURCE_API_KEY:
+		tr, ok := transport.FromServerContext(ctx)
+		if !ok {
+			return ""
+		}
+		header := tr.RequestHeader()
+		for _, key := range []string{"x-api-key", "x-goog-api-key"} {
+			if value := header.Get(key); value != "" {
+				return value
+			}
+		}
+		authorization := header.Get("Authorization")
+		if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+			return authorization[7:]
+		}
+		return ""
+	default:
+		claims, ok := jwt.FromContext(ctx)
+		if !ok {
+			return ""
+		}
+		sub, _ := claims.GetSubject()
+		return sub
+	}
+}
+
+// adminEnabled reports whether the admin API should be registered. As it can
+// lift any limit, it is refused unless callers authenticate with JWT and are
+// restricted to configured subjects.
+func adminEnabled(c *conf.Server, logger *slog.Logger) bool {
+	if !c.GetAdmin().GetEnabled() {
+		return false
+	}
+	if c.GetJwtKey() == "" || len(c.GetAdmin().GetSubjects()) == 0 {
+		logger.Error("admin API is enabled without a JWT key and subjects, not registering it")
+		return false
+	}
+	return true
+}
+
+// adminAuth returns a middleware that restricts the admin API to the configured
+// JWT subjects, denying every caller when none are. It must run after jwtAuth.
+func adminAuth(c *conf.Admin) middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			claims, ok := jwt.FromContext(ctx)
+			if !ok {
+				return nil, entity.ErrForbidden
+			}
+			sub, _ := claims.GetSubject()
+			if !slices.Contains(c.GetSubjects(), sub) {
+				return nil, entity.ErrForbidden
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
+// rateLimitHeaders returns a middleware that writes the rate limit of each
+// request as OpenAI-style rate-limit headers. Compatible servers following
+// other conventions install their own reporter.
+func rateLimitHeaders() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if tr, ok := transport.FromServerContext(ctx); ok {
+				ctx = entity.NewRateLimitContext(ctx, func(rl *entity.RateLimit) {
+					setRateLimitHeaders(tr.ReplyHeader(), rl)
+				})
+			}
+			return handler(ctx, req)
+		}
+	}
+}
+
 // createStreamInterceptor applies middleware to streaming RPCs.
 func createStreamInterceptor(ms ...middleware.Middleware) grpc.StreamServerInterceptor {
 	chain := middleware.Chain(ms...)
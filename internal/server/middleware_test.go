@@ -0,0 +1,88 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/go-kratos/kratos/contrib/middleware/jwt/v3"
+	jwt5 "github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/conf"
+)
+
+func TestAdminEnabled(t *testing.T) {
+	Convey("Test adminEnabled", t, func() {
+		Convey("should not register a disabled admin API", func() {
+			c := &conf.Server{JwtKey: "secret", Admin: &conf.Admin{Subjects: []string{"ops"}}}
+			So(adminEnabled(c, slog.Default()), ShouldBeFalse)
+		})
+
+		Convey("should refuse an admin API open to anyone", func() {
+			c := &conf.Server{Admin: &conf.Admin{Enabled: true}}
+			So(adminEnabled(c, slog.Default()), ShouldBeFalse)
+		})
+
+		Convey("should refuse an admin API without a JWT key", func() {
+			c := &conf.Server{Admin: &conf.Admin{Enabled: true, Subjects: []string{"ops"}}}
+			So(adminEnabled(c, slog.Default()), ShouldBeFalse)
+		})
+
+		Convey("should refuse an admin API open to any authenticated caller", func() {
+			c := &conf.Server{JwtKey: "secret", Admin: &conf.Admin{Enabled: true}}
+			So(adminEnabled(c, slog.Default()), ShouldBeFalse)
+		})
+
+		Convey("should register an admin API restricted to subjects", func() {
+			c := &conf.Server{JwtKey: "secret", Admin: &conf.Admin{Enabled: true, Subjects: []string{"ops"}}}
+			So(adminEnabled(c, slog.Default()), ShouldBeTrue)
+		})
+	})
+}
+
+func TestAdminAuth(t *testing.T) {
+	Convey("Test adminAuth", t, func() {
+		handler := func(context.Context, any) (any, error) { return "ok", nil }
+		withSubject := func(sub string) context.Context {
+			return jwt.NewContext(context.Background(), jwt5.MapClaims{"sub": sub})
+		}
+
+		Convey("should deny every caller without subjects", func() {
+			_, err := adminAuth(&conf.Admin{})(handler)(withSubject("ops"), nil)
+			So(errors.Is(err, entity.ErrForbidden), ShouldBeTrue)
+		})
+
+		Convey("should deny unauthenticated callers", func() {
+			_, err := adminAuth(&conf.Admin{Subjects: []string{"ops"}})(handler)(context.Background(), nil)
+			So(errors.Is(err, entity.ErrForbidden), ShouldBeTrue)
+		})
+
+		Convey("should deny other subjects", func() {
+			_, err := adminAuth(&conf.Admin{Subjects: []string{"ops"}})(handler)(withSubject("client"), nil)
+			So(errors.Is(err, entity.ErrForbidden), ShouldBeTrue)
+		})
+
+		Convey("should allow configured subjects", func() {
+			reply, err := adminAuth(&conf.Admin{Subjects: []string{"ops"}})(handler)(withSubject("ops"), nil)
+			So(err, ShouldBeNil)
+			So(reply, ShouldEqual, "ok")
+		})
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	"github.com/go-kratos/kratos/v3/transport/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func (s *Server) handleChat(httpCtx http.Context) error {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	var ollamaReq ChatReq
+	err = json.Unmarshal(requestBody, &ollamaReq)
+	if err != nil {
+		return err
+	}
+
+	stream := ollamaReq.Stream == nil || *ollamaReq.Stream
+	return s.chat(httpCtx, convertChatReqFromOllama(&ollamaReq), stream, false)
+}
+
+func (s *Server) handleGenerate(httpCtx http.Context) error {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	var ollamaReq GenerateReq
+	err = json.Unmarshal(requestBody, &ollamaReq)
+	if err != nil {
+		return err
+	}
+
+	// An empty prompt only loads the model
+	if ollamaReq.Prompt == "" && len(ollamaReq.Images) == 0 {
+		return httpCtx.Result(200, &GenerateResp{
+			Model:      ollamaReq.Model,
+			CreatedAt:  time.Now().UTC(),
+			Done:       true,
+			DoneReason: "load",
+		})
+	}
+
+	stream := ollamaReq.Stream == nil || *ollamaReq.Stream
+	return s.chat(httpCtx, convertGenerateReqFromOllama(&ollamaReq), stream, true)
+}
+
+// chat serves both /api/chat and /api/generate, whose responses only differ in
+// shape.
+func (s *Server) chat(httpCtx http.Context, req *v1.ChatRequest, stream bool, generate bool) error {
+	start := time.Now()
+
+	if stream {
+		httpCtx.Response().Header().Set("Content-Type", "application/x-ndjson")
+
+		streamServer := &chatStreamServer{
+			httpCtx:  httpCtx,
+			generate: generate,
+			start:    start,
+		}
+		middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+			streamServer.ctx = ctx
+			return nil, s.chatSvc.ChatStream(req.(*v1.ChatRequest), streamServer)
+		})
+		_, err := middleware(httpCtx, req)
+		// Once streaming, errors can only be reported as the last line
+		if err != nil && streamServer.started {
+			return streamServer.writeError(err)
+		}
+		return err
+	}
+
+	middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.chatSvc.Chat(ctx, req.(*v1.ChatRequest))
+	})
+	resp, err := middleware(httpCtx, req)
+	if err != nil {
+		return err
+	}
+
+	chatResp := convertChatRespToOllama(resp.(*v1.ChatResponse))
+	chatResp.CreatedAt = time.Now().UTC()
+	chatResp.TotalDuration = time.Since(start).Nanoseconds()
+	if generate {
+		return httpCtx.Result(200, convertChatRespToGenerate(chatResp))
+	}
+	return httpCtx.Result(200, chatResp)
+}
+
+// chatStreamServer writes chat events as Ollama NDJSON lines. Tool calls are
+// sent whole once their inputs are complete, as Ollama does not stream them.
+type chatStreamServer struct {
+	v1.Chat_ChatStreamServer
+	ctx      context.Context
+	httpCtx  http.Context
+	generate bool
+	start    time.Time
+	started  bool
+
+	model     string
+	usage     *v1.Usage
+	reasoning map[uint32]bool
+	toolCalls map[uint32]*ToolCall
+	toolCount int
+	toolInput map[uint32]string
+}
+
+func (c *chatStreamServer) Context() context.Context {
+	return c.ctx
+}
+
+func (c *chatStreamServer) writeLine(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	c.started = true
+	_, err = c.httpCtx.Response().Write(data)
+	if err != nil {
+		return err
+	}
+	c.httpCtx.Response().(http.Flusher).Flush()
+	return nil
+}
+
+func (c *chatStreamServer) write(resp *ChatResp) error {
+	resp.Model = c.model
+	resp.CreatedAt = time.Now().UTC()
+	resp.Message.Role = "assistant"
+	if c.generate {
+		return c.writeLine(convertChatRespToGenerate(resp))
+	}
+	return c.writeLine(resp)
+}
+
+func (c *chatStreamServer) writeError(err error) error {
+	return c.writeLine(&ErrorResp{Error: errors.FromError(err).Message})
+}
+
+func (c *chatStreamServer) Send(event *v1.ChatEvent) error {
+	if event.Usage != nil {
+		c.usage = event.Usage
+	}
+
+	switch e := event.Event.(type) {
+	case *v1.ChatEvent_MessageStart:
+		c.model = e.MessageStart.GetModel()
+
+	case *v1.ChatEvent_ContentStart:
+		start := e.ContentStart
+		switch ct := start.Content.(type) {
+		case *v1.ContentStart_ToolUse:
+			if c.toolCalls == nil {
+				c.toolCalls = map[uint32]*ToolCall{}
+				c.toolInput = map[uint32]string{}
+			}
+			c.toolCalls[start.GetIndex()] = &ToolCall{
+				Function: ToolCallFunction{
+					Index: c.toolCount,
+					Name:  ct.ToolUse.GetName(),
+				},
+			}
+			c.toolCount++
+		default:
+			if c.reasoning == nil {
+				c.reasoning = map[uint32]bool{}
+			}
+			c.reasoning[start.GetIndex()] = start.GetPhase() == v1.ContentPhase_CONTENT_PHASE_REASONING
+		}
+
+	case *v1.ChatEvent_ContentDelta:
+		delta := e.ContentDelta
+		switch d := delta.Delta.(type) {
+		case *v1.ContentDelta_Text:
+			resp := &ChatResp{}
+			if c.reasoning[delta.GetIndex()] {
+				resp.Message.Thinking = d.Text
+			} else {
+				resp.Message.Content = d.Text
+			}
+			return c.write(resp)
+		case *v1.ContentDelta_ToolInputText:
+			// Inputs of a tool use that was not started are dropped
+			if _, ok := c.toolCalls[delta.GetIndex()]; ok {
+				c.toolInput[delta.GetIndex()] += d.ToolInputText
+			}
+		}
+
+	case *v1.ChatEvent_ContentStop:
+		index := e.ContentStop.GetIndex()
+		if tc, ok := c.toolCalls[index]; ok {
+			tc.Function.Arguments = convertToolArgumentsToOllama(c.toolInput[index])
+			delete(c.toolCalls, index)
+			delete(c.toolInput, index)
+			// /api/generate has no tool calls
+			if !c.generate {
+				return c.write(&ChatResp{Message: Message{ToolCalls: []ToolCall{*tc}}})
+			}
+		}
+
+	case *v1.ChatEvent_MessageStop:
+		return c.write(&ChatResp{
+			Done:       true,
+			DoneReason: convertStatusToOllama(e.MessageStop.GetStatus()),
+			Metrics: Metrics{
+				TotalDuration:   time.Since(c.start).Nanoseconds(),
+				PromptEvalCount: c.usage.GetInputTokens(),
+				EvalCount:       c.usage.GetOutputTokens(),
+			},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-kratos/kratos/v3/middleware"
+	kratoshttp "github.com/go-kratos/kratos/v3/transport/http"
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+type testResponseWriter struct {
+	http.ResponseWriter
+	ctx *testHTTPContext
+}
+
+func (w *testResponseWriter) Header() http.Header {
+	return w.ctx.headers
+}
+
+func (w *testResponseWriter) Write(data []byte) (int, error) {
+	return w.ctx.body.Write(data)
+}
+
+func (w *testResponseWriter) WriteHeader(statusCode int) {
+	w.ctx.statusCode = statusCode
+}
+
+func (w *testResponseWriter) Flush() {}
+
+type testHTTPContext struct {
+	kratoshttp.Context
+	statusCode int
+	headers    http.Header
+	body       bytes.Buffer
+	writer     *testResponseWriter
+}
+
+func newTestHTTPContext() *testHTTPContext {
+	ctx := &testHTTPContext{headers: make(http.Header)}
+	ctx.writer = &testResponseWriter{ctx: ctx}
+	return ctx
+}
+
+func (c *testHTTPContext) Response() kratoshttp.ResponseWriter {
+	return c.writer
+}
+
+func (c *testHTTPContext) Middleware(handler middleware.Handler) middleware.Handler {
+	return handler
+}
+
+func (c *testHTTPContext) JSON(statusCode int, v any) error {
+	c.statusCode = statusCode
+	c.headers.Set("Content-Type", "application/json")
+	return json.NewEncoder(&c.body).Encode(v)
+}
+
+func parseLines(body string) []map[string]any {
+	var lines []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]any
+		if json.Unmarshal(scanner.Bytes(), &line) == nil {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func chatStreamEvents() []*v1.ChatEvent {
+	return []*v1.ChatEvent{
+		v1.NewChatEvent("req", v1.NewMessageStartEvent("msg", "llama3")),
+		v1.NewChatEvent("req", v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_REASONING)),
+		v1.NewChatEvent("req", v1.NewContentDeltaTextEvent(0, "Hmm.")),
+		v1.NewChatEvent("req", v1.NewContentStopEvent(0)),
+		v1.NewChatEvent("req", v1.NewContentStartTextEvent(1, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+		v1.NewChatEvent("req", v1.NewContentDeltaTextEvent(1, "Hi")),
+		v1.NewChatEvent("req", v1.NewContentStopEvent(1)),
+		v1.NewChatEvent("req", v1.NewContentStartToolUseEvent(2, "call", "get_weather")),
+		v1.NewChatEvent("req", v1.NewContentDeltaToolInputTextEvent(2, `{"city":`)),
+		v1.NewChatEvent("req", v1.NewContentDeltaToolInputTextEvent(2, `"Paris"}`)),
+		v1.NewChatEvent("req", v1.NewContentStopEvent(2)),
+		{
+			Id:    "req",
+			Event: v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE),
+			Usage: &v1.Usage{InputTokens: 10, OutputTokens: 5},
+		},
+	}
+}
+
+func TestChatStreamServer(t *testing.T) {
+	Convey("Given a stream with reasoning, text and a tool use", t, func() {
+		httpCtx := newTestHTTPContext()
+
+		Convey("When streaming it as /api/chat", func() {
+			server := &chatStreamServer{ctx: context.Background(), httpCtx: httpCtx}
+			for _, event := range chatStreamEvents() {
+				So(server.Send(event), ShouldBeNil)
+			}
+			lines := parseLines(httpCtx.body.String())
+
+			Convey("Then each delta and the whole tool call should be a line", func() {
+				So(lines, ShouldHaveLength, 4)
+				So(lines[0]["model"], ShouldEqual, "llama3")
+				So(lines[0]["message"].(map[string]any)["thinking"], ShouldEqual, "Hmm.")
+				So(lines[1]["message"].(map[string]any)["content"], ShouldEqual, "Hi")
+
+				toolCalls := lines[2]["message"].(map[string]any)["tool_calls"].([]any)
+				function := toolCalls[0].(map[string]any)["function"].(map[string]any)
+				So(function["name"], ShouldEqual, "get_weather")
+				So(function["arguments"], ShouldResemble, map[string]any{"city": "Paris"})
+
+				So(lines[3]["done"], ShouldBeTrue)
+				So(lines[3]["done_reason"], ShouldEqual, "stop")
+				So(lines[3]["prompt_eval_count"], ShouldEqual, 10)
+				So(lines[3]["eval_count"], ShouldEqual, 5)
+			})
+		})
+
+		Convey("When streaming it as /api/generate", func() {
+			server := &chatStreamServer{ctx: context.Background(), httpCtx: httpCtx, generate: true}
+			for _, event := range chatStreamEvents() {
+				So(server.Send(event), ShouldBeNil)
+			}
+			lines := parseLines(httpCtx.body.String())
+
+			Convey("Then the text should be in response and tool calls dropped", func() {
+				So(lines, ShouldHaveLength, 3)
+				So(lines[0]["thinking"], ShouldEqual, "Hmm.")
+				So(lines[1]["response"], ShouldEqual, "Hi")
+				So(lines[2]["done"], ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestChatStreamServer_OrphanToolInput(t *testing.T) {
+	Convey("Given a tool input delta without a tool use start", t, func() {
+		httpCtx := newTestHTTPContext()
+		server := &chatStreamServer{ctx: context.Background(), httpCtx: httpCtx}
+
+		Convey("Then it should be dropped", func() {
+			So(server.Send(v1.NewChatEvent("req", v1.NewContentDeltaToolInputTextEvent(0, `{}`))), ShouldBeNil)
+			So(server.Send(v1.NewChatEvent("req", v1.NewContentStopEvent(0))), ShouldBeNil)
+			So(httpCtx.body.Len(), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestWriteError(t *testing.T) {
+	Convey("Test writeError", t, func() {
+		Convey("nil error should write nothing", func() {
+			ctx := newTestHTTPContext()
+			So(writeError(ctx, nil), ShouldBeNil)
+			So(ctx.body.Len(), ShouldEqual, 0)
+		})
+
+		Convey("errors should be rendered with their status", func() {
+			ctx := newTestHTTPContext()
+			So(writeError(ctx, entity.ErrClientQuotaExceeded), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusTooManyRequests)
+
+			var resp ErrorResp
+			So(json.Unmarshal(ctx.body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error, ShouldEqual, "client quota exceeded")
+		})
+
+		Convey("unknown errors should be rendered as server errors", func() {
+			ctx := newTestHTTPContext()
+			So(writeError(ctx, errors.New("boom")), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -0,0 +1,288 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+func convertConfigFromOllama(options *Options, format, think json.RawMessage) *v1.GenerationConfig {
+	config := &v1.GenerationConfig{}
+
+	if options != nil {
+		config.Temperature = options.Temperature
+		config.TopP = options.TopP
+		config.TopK = options.TopK
+		config.FrequencyPenalty = options.FrequencyPenalty
+		config.PresencePenalty = options.PresencePenalty
+		config.StopSequences = options.Stop
+		// A negative num_predict generates without limit
+		if options.NumPredict != nil && *options.NumPredict > 0 {
+			config.MaxTokens = options.NumPredict
+		}
+	}
+
+	if len(format) > 0 {
+		var schema map[string]any
+		if bytes.Equal(format, []byte(`"json"`)) {
+			config.Grammar = &v1.GenerationConfig_PresetGrammar{PresetGrammar: "json_object"}
+		} else if err := json.Unmarshal(format, &schema); err == nil && schema != nil {
+			if s, err := util.StructFromMap(schema); err == nil {
+				config.Grammar = &v1.GenerationConfig_Schema{Schema: s}
+			}
+		}
+	}
+
+	config.ReasoningConfig = convertThinkFromOllama(think)
+	return config
+}
+
+func convertThinkFromOllama(think json.RawMessage) *v1.ReasoningConfig {
+	if len(think) == 0 {
+		return nil
+	}
+
+	var enabled bool
+	if err := json.Unmarshal(think, &enabled); err == nil {
+		if enabled {
+			return &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_MEDIUM}
+		}
+		return &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_NONE}
+	}
+
+	var level string
+	if err := json.Unmarshal(think, &level); err != nil {
+		return nil
+	}
+	switch level {
+	case "low":
+		return &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_LOW}
+	case "medium":
+		return &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_MEDIUM}
+	case "high":
+		return &v1.ReasoningConfig{Effort: v1.ReasoningEffort_REASONING_EFFORT_HIGH}
+	default:
+		return nil
+	}
+}
+
+// convertImageFromOllama converts a base64 image, whose MIME type Ollama does
+// not send and is sniffed from the leading bytes.
+func convertImageFromOllama(encoded string) *v1.Image {
+	image := &v1.Image{Source: &v1.Image_Base64{Base64: encoded}}
+	// 684 base64 characters decode to the 512 bytes needed for sniffing
+	if data, err := base64.StdEncoding.DecodeString(encoded[:min(len(encoded), 684)]); err == nil {
+		image.MimeType = http.DetectContentType(data)
+	}
+	return image
+}
+
+func convertToolsFromOllama(tools []Tool) []*v1.Tool {
+	var result []*v1.Tool
+	for _, tool := range tools {
+		if tool.Type != "" && tool.Type != "function" {
+			continue
+		}
+		inputSchema, _ := util.StructFromMap(tool.Function.Parameters)
+		result = append(result, &v1.Tool{
+			Tool: &v1.Tool_Function_{
+				Function: &v1.Tool_Function{
+					Name:        tool.Function.Name,
+					Description: tool.Function.Description,
+					InputSchema: inputSchema,
+				},
+			},
+		})
+	}
+	return result
+}
+
+// convertMessagesFromOllama converts the chat history. Ollama does not identify
+// tool calls, so each call is given an id and each tool result is matched to
+// the earliest pending call of the same tool.
+func convertMessagesFromOllama(messages []Message) []*v1.Message {
+	type pendingCall struct {
+		id   string
+		name string
+	}
+	var pending []pendingCall
+	var callCount int
+
+	var result []*v1.Message
+	for _, m := range messages {
+		msg := &v1.Message{}
+		switch m.Role {
+		case "system":
+			msg.Role = v1.Role_ROLE_SYSTEM
+		case "assistant":
+			msg.Role = v1.Role_ROLE_MODEL
+		case "tool":
+			id := ""
+			for i, call := range pending {
+				if m.ToolName == "" || call.name == m.ToolName {
+					id = call.id
+					pending = append(pending[:i], pending[i+1:]...)
+					break
+				}
+			}
+			result = append(result, &v1.Message{
+				Role: v1.Role_ROLE_USER,
+				Contents: []*v1.Content{{
+					Content: &v1.Content_ToolResult{
+						ToolResult: &v1.ToolResult{
+							Id: id,
+							Outputs: []*v1.ToolResult_Output{
+								{Output: &v1.ToolResult_Output_Text{Text: m.Content}},
+							},
+						},
+					},
+				}},
+			})
+			continue
+		default:
+			msg.Role = v1.Role_ROLE_USER
+		}
+
+		if m.Thinking != "" {
+			msg.Contents = append(msg.Contents, &v1.Content{
+				Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
+				Content: v1.NewTextContent(m.Thinking),
+			})
+		}
+		if m.Content != "" {
+			msg.Contents = append(msg.Contents, &v1.Content{Content: v1.NewTextContent(m.Content)})
+		}
+		for _, image := range m.Images {
+			msg.Contents = append(msg.Contents, &v1.Content{
+				Content: &v1.Content_Image{Image: convertImageFromOllama(image)},
+			})
+		}
+		for _, tc := range m.ToolCalls {
+			id := fmt.Sprintf("call_%d", callCount)
+			callCount++
+			pending = append(pending, pendingCall{id: id, name: tc.Function.Name})
+			msg.Contents = append(msg.Contents, &v1.Content{
+				Content: &v1.Content_ToolUse{
+					ToolUse: &v1.ToolUse{
+						Id:   id,
+						Name: tc.Function.Name,
+						Inputs: []*v1.ToolUse_Input{
+							{Input: &v1.ToolUse_Input_Text{Text: string(tc.Function.Arguments)}},
+						},
+					},
+				},
+			})
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+func convertChatReqFromOllama(req *ChatReq) *v1.ChatRequest {
+	return &v1.ChatRequest{
+		Model:    req.Model,
+		Config:   convertConfigFromOllama(req.Options, req.Format, req.Think),
+		Messages: convertMessagesFromOllama(req.Messages),
+		Tools:    convertToolsFromOllama(req.Tools),
+	}
+}
+
+func convertGenerateReqFromOllama(req *GenerateReq) *v1.ChatRequest {
+	var messages []Message
+	if req.System != "" {
+		messages = append(messages, Message{Role: "system", Content: req.System})
+	}
+	messages = append(messages, Message{Role: "user", Content: req.Prompt, Images: req.Images})
+
+	return &v1.ChatRequest{
+		Model:    req.Model,
+		Config:   convertConfigFromOllama(req.Options, req.Format, req.Think),
+		Messages: convertMessagesFromOllama(messages),
+	}
+}
+
+// convertToolArgumentsToOllama returns the arguments as a JSON object, as
+// Ollama clients do not accept them as text.
+func convertToolArgumentsToOllama(arguments string) json.RawMessage {
+	if !json.Valid([]byte(arguments)) {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(arguments)
+}
+
+func convertStatusToOllama(status v1.ChatStatus) string {
+	switch status {
+	case v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT:
+		return "length"
+	default:
+		return "stop"
+	}
+}
+
+func convertUsageToOllama(usage *v1.Usage) Metrics {
+	return Metrics{
+		PromptEvalCount: usage.GetInputTokens(),
+		EvalCount:       usage.GetOutputTokens(),
+	}
+}
+
+func convertChatRespToOllama(resp *v1.ChatResponse) *ChatResp {
+	message := Message{Role: "assistant"}
+	for _, content := range resp.GetMessage().GetContents() {
+		switch c := content.Content.(type) {
+		case *v1.Content_Text:
+			if content.Phase == v1.ContentPhase_CONTENT_PHASE_REASONING {
+				message.Thinking += c.Text.GetText()
+			} else {
+				message.Content += c.Text.GetText()
+			}
+		case *v1.Content_ToolUse:
+			message.ToolCalls = append(message.ToolCalls, ToolCall{
+				Function: ToolCallFunction{
+					Index:     len(message.ToolCalls),
+					Name:      c.ToolUse.GetName(),
+					Arguments: convertToolArgumentsToOllama(c.ToolUse.GetTextualInput()),
+				},
+			})
+		}
+	}
+
+	return &ChatResp{
+		Model:      resp.Model,
+		Message:    message,
+		Done:       true,
+		DoneReason: convertStatusToOllama(resp.Status),
+		Metrics:    convertUsageToOllama(resp.GetStatistics().GetUsage()),
+	}
+}
+
+func convertChatRespToGenerate(resp *ChatResp) *GenerateResp {
+	return &GenerateResp{
+		Model:      resp.Model,
+		CreatedAt:  resp.CreatedAt,
+		Response:   resp.Message.Content,
+		Thinking:   resp.Message.Thinking,
+		Done:       resp.Done,
+		DoneReason: resp.DoneReason,
+		Metrics:    resp.Metrics,
+	}
+}
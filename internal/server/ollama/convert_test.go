@@ -0,0 +1,179 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func TestConvertConfigFromOllama(t *testing.T) {
+	Convey("Given Ollama options, format and think", t, func() {
+		Convey("When options are set", func() {
+			config := convertConfigFromOllama(&Options{
+				Temperature: new(float32(0.5)),
+				TopK:        new(int64(40)),
+				NumPredict:  new(int64(128)),
+				Stop:        []string{"\n"},
+			}, nil, nil)
+
+			Convey("Then they should map onto the generation config", func() {
+				So(config.GetTemperature(), ShouldEqual, 0.5)
+				So(config.GetTopK(), ShouldEqual, 40)
+				So(config.GetMaxTokens(), ShouldEqual, 128)
+				So(config.StopSequences, ShouldResemble, []string{"\n"})
+				So(config.ReasoningConfig, ShouldBeNil)
+			})
+		})
+
+		Convey("When num_predict is negative", func() {
+			config := convertConfigFromOllama(&Options{NumPredict: new(int64(-1))}, nil, nil)
+
+			Convey("Then the tokens should not be limited", func() {
+				So(config.MaxTokens, ShouldBeNil)
+			})
+		})
+
+		Convey("When the format is json", func() {
+			config := convertConfigFromOllama(nil, json.RawMessage(`"json"`), nil)
+
+			Convey("Then it should request a JSON object", func() {
+				So(config.GetPresetGrammar(), ShouldEqual, "json_object")
+			})
+		})
+
+		Convey("When the format is a schema", func() {
+			config := convertConfigFromOllama(nil, json.RawMessage(`{"type":"object"}`), nil)
+
+			Convey("Then it should request the schema", func() {
+				So(config.GetSchema().AsMap(), ShouldResemble, map[string]any{"type": "object"})
+			})
+		})
+
+		Convey("When think is a boolean or a level", func() {
+			So(convertThinkFromOllama(json.RawMessage("true")).Effort, ShouldEqual, v1.ReasoningEffort_REASONING_EFFORT_MEDIUM)
+			So(convertThinkFromOllama(json.RawMessage("false")).Effort, ShouldEqual, v1.ReasoningEffort_REASONING_EFFORT_NONE)
+			So(convertThinkFromOllama(json.RawMessage(`"high"`)).Effort, ShouldEqual, v1.ReasoningEffort_REASONING_EFFORT_HIGH)
+			So(convertThinkFromOllama(json.RawMessage(`"unknown"`)), ShouldBeNil)
+		})
+	})
+}
+
+func TestConvertMessagesFromOllama(t *testing.T) {
+	Convey("Given an Ollama conversation with tool calls", t, func() {
+		messages := []Message{
+			{Role: "system", Content: "Be brief."},
+			{Role: "user", Content: "Weather?", Images: []string{"iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="}},
+			{Role: "assistant", Thinking: "Look it up.", ToolCalls: []ToolCall{
+				{Function: ToolCallFunction{Name: "get_weather", Arguments: json.RawMessage(`{"city":"Paris"}`)}},
+				{Function: ToolCallFunction{Name: "get_time", Arguments: json.RawMessage(`{}`)}},
+			}},
+			{Role: "tool", ToolName: "get_time", Content: "noon"},
+			{Role: "tool", ToolName: "get_weather", Content: "sunny"},
+		}
+
+		Convey("When converting the messages", func() {
+			result := convertMessagesFromOllama(messages)
+
+			Convey("Then roles and contents should be converted", func() {
+				So(result, ShouldHaveLength, 5)
+				So(result[0].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+				So(result[1].Contents, ShouldHaveLength, 2)
+				So(result[1].Contents[1].GetImage().MimeType, ShouldEqual, "image/png")
+				So(result[2].Role, ShouldEqual, v1.Role_ROLE_MODEL)
+				So(result[2].Contents[0].Phase, ShouldEqual, v1.ContentPhase_CONTENT_PHASE_REASONING)
+			})
+
+			Convey("Then tool results should match the calls by name", func() {
+				weather := result[2].Contents[1].GetToolUse()
+				clock := result[2].Contents[2].GetToolUse()
+				So(weather.GetTextualInput(), ShouldEqual, `{"city":"Paris"}`)
+				So(result[3].Contents[0].GetToolResult().Id, ShouldEqual, clock.Id)
+				So(result[4].Contents[0].GetToolResult().Id, ShouldEqual, weather.Id)
+				So(weather.Id, ShouldNotEqual, clock.Id)
+			})
+		})
+	})
+}
+
+func TestConvertGenerateReqFromOllama(t *testing.T) {
+	Convey("Given an Ollama generate request with a system prompt", t, func() {
+		req := &GenerateReq{Model: "llama3", System: "Be brief.", Prompt: "Hi"}
+
+		Convey("When converting it to a chat request", func() {
+			result := convertGenerateReqFromOllama(req)
+
+			Convey("Then it should be a system and a user message", func() {
+				So(result.Model, ShouldEqual, "llama3")
+				So(result.Messages, ShouldHaveLength, 2)
+				So(result.Messages[0].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+				So(result.Messages[1].Contents[0].GetText().GetText(), ShouldEqual, "Hi")
+			})
+		})
+	})
+}
+
+func TestConvertChatRespToOllama(t *testing.T) {
+	Convey("Given a chat response with reasoning and a tool use", t, func() {
+		resp := &v1.ChatResponse{
+			Model: "llama3",
+			Message: &v1.Message{Contents: []*v1.Content{
+				{Phase: v1.ContentPhase_CONTENT_PHASE_REASONING, Content: v1.NewTextContent("Hmm.")},
+				{Content: v1.NewTextContent("Checking.")},
+				{Content: &v1.Content_ToolUse{ToolUse: &v1.ToolUse{
+					Name:   "get_weather",
+					Inputs: []*v1.ToolUse_Input{{Input: &v1.ToolUse_Input_Text{Text: `{"city":"Paris"}`}}},
+				}}},
+			}},
+			Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 10, OutputTokens: 5}},
+			Status:     v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE,
+		}
+
+		Convey("When converting it to Ollama", func() {
+			result := convertChatRespToOllama(resp)
+
+			Convey("Then the message should carry thinking, content and tool calls", func() {
+				So(result.Message.Thinking, ShouldEqual, "Hmm.")
+				So(result.Message.Content, ShouldEqual, "Checking.")
+				So(result.Message.ToolCalls, ShouldHaveLength, 1)
+				So(string(result.Message.ToolCalls[0].Function.Arguments), ShouldEqual, `{"city":"Paris"}`)
+				So(result.Done, ShouldBeTrue)
+				So(result.DoneReason, ShouldEqual, "stop")
+				So(result.PromptEvalCount, ShouldEqual, 10)
+				So(result.EvalCount, ShouldEqual, 5)
+			})
+		})
+	})
+}
+
+func TestEmbedInput(t *testing.T) {
+	Convey("Given an embed request input", t, func() {
+		var req EmbedReq
+
+		Convey("When it is a single string", func() {
+			So(json.Unmarshal([]byte(`{"input":"hello"}`), &req), ShouldBeNil)
+			So(req.Input, ShouldResemble, EmbedInput{"hello"})
+		})
+
+		Convey("When it is an array of strings", func() {
+			So(json.Unmarshal([]byte(`{"input":["a","b"]}`), &req), ShouldBeNil)
+			So(req.Input, ShouldResemble, EmbedInput{"a", "b"})
+		})
+	})
+}
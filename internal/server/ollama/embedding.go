@@ -0,0 +1,107 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/go-kratos/kratos/v3/transport/http"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func newTextEmbedInput(text string) *v1.EmbedInput {
+	return &v1.EmbedInput{
+		Contents: []*v1.Content{{Content: v1.NewTextContent(text)}},
+	}
+}
+
+func (s *Server) handleEmbed(httpCtx http.Context) error {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	var ollamaReq EmbedReq
+	err = json.Unmarshal(requestBody, &ollamaReq)
+	if err != nil {
+		return err
+	}
+
+	req := &v1.EmbedRequest{
+		Model: ollamaReq.Model,
+		// Ollama embeddings are normalized to length 1
+		Normalize: true,
+	}
+	for _, text := range ollamaReq.Input {
+		req.Inputs = append(req.Inputs, newTextEmbedInput(text))
+	}
+	if ollamaReq.Dimensions > 0 {
+		req.Dimensions = new(ollamaReq.Dimensions)
+	}
+
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.embedSvc.Embed(ctx, req.(*v1.EmbedRequest))
+	})
+	r, err := m(httpCtx, req)
+	if err != nil {
+		return err
+	}
+
+	resp := r.(*v1.EmbedResponse)
+	ollamaResp := &EmbedResp{
+		Model:           resp.Model,
+		Embeddings:      make([][]float32, 0, len(resp.Embeddings)),
+		PromptEvalCount: resp.GetUsage().GetInputTokens(),
+	}
+	for _, e := range resp.Embeddings {
+		ollamaResp.Embeddings = append(ollamaResp.Embeddings, e.Values)
+	}
+	return httpCtx.Result(200, ollamaResp)
+}
+
+func (s *Server) handleEmbeddings(httpCtx http.Context) error {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	var ollamaReq EmbeddingsReq
+	err = json.Unmarshal(requestBody, &ollamaReq)
+	if err != nil {
+		return err
+	}
+
+	req := &v1.EmbedRequest{
+		Model:  ollamaReq.Model,
+		Inputs: []*v1.EmbedInput{newTextEmbedInput(ollamaReq.Prompt)},
+	}
+
+	m := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.embedSvc.Embed(ctx, req.(*v1.EmbedRequest))
+	})
+	r, err := m(httpCtx, req)
+	if err != nil {
+		return err
+	}
+
+	ollamaResp := &EmbeddingsResp{}
+	if embeddings := r.(*v1.EmbedResponse).Embeddings; len(embeddings) > 0 {
+		ollamaResp.Embedding = embeddings[0].Values
+	}
+	return httpCtx.Result(200, ollamaResp)
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ollama
+
+import (
+	"github.com/go-kratos/kratos/v3/errors"
+	khttp "github.com/go-kratos/kratos/v3/transport/http"
+)
+
+// writeError renders err in the Ollama error format, so that Ollama clients
+// surface the failure message.
+func writeError(httpCtx khttp.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := errors.FromError(err)
+	return httpCtx.JSON(int(e.Code), &ErrorResp{Error: e.Message})
+}
@@ -23,11 +23,15 @@ import (
 
 type Server struct {
 	modelSvc v1.ModelServer
+	chatSvc  v1.ChatServer
+	embedSvc v1.EmbeddingServer
 }
 
 func NewServer(svc *service.RouterService) *Server {
 	return &Server{
 		modelSvc: svc,
+		chatSvc:  svc,
+		embedSvc: svc,
 	}
 }
 
@@ -44,4 +48,16 @@ func (s *Server) RegisterRoutes(srv *http.Server) {
 	r.POST("/api/show", func(ctx http.Context) error {
 		return s.handleShowModel(ctx)
 	})
+	r.POST("/api/chat", func(ctx http.Context) error {
+		return writeError(ctx, s.handleChat(ctx))
+	})
+	r.POST("/api/generate", func(ctx http.Context) error {
+		return writeError(ctx, s.handleGenerate(ctx))
+	})
+	r.POST("/api/embed", func(ctx http.Context) error {
+		return writeError(ctx, s.handleEmbed(ctx))
+	})
+	r.POST("/api/embeddings", func(ctx http.Context) error {
+		return writeError(ctx, s.handleEmbeddings(ctx))
+	})
 }
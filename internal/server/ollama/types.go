@@ -14,6 +14,11 @@
 
 package ollama
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Model struct {
 	Name  string `json:"name"`
 	Model string `json:"model"`
@@ -31,3 +36,133 @@ type ModelDetail struct {
 type ShowModelReq struct {
 	Model string `json:"model"`
 }
+
+type Options struct {
+	Temperature      *float32 `json:"temperature,omitempty"`
+	TopP             *float32 `json:"top_p,omitempty"`
+	TopK             *int64   `json:"top_k,omitempty"`
+	NumPredict       *int64   `json:"num_predict,omitempty"`
+	FrequencyPenalty *float32 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float32 `json:"presence_penalty,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+}
+
+type ToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+type Tool struct {
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Index     int             `json:"index"`
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+type Message struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	Thinking  string     `json:"thinking,omitempty"`
+	Images    []string   `json:"images,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+type ChatReq struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Tools    []Tool    `json:"tools,omitempty"`
+	// Either "json" or a JSON schema.
+	Format json.RawMessage `json:"format,omitempty"`
+	// Either a boolean or an effort level of "low", "medium" or "high".
+	Think   json.RawMessage `json:"think,omitempty"`
+	Options *Options        `json:"options,omitempty"`
+	// Ollama streams unless explicitly disabled.
+	Stream *bool `json:"stream,omitempty"`
+}
+
+type Metrics struct {
+	TotalDuration   int64  `json:"total_duration,omitempty"`
+	PromptEvalCount uint32 `json:"prompt_eval_count,omitempty"`
+	EvalCount       uint32 `json:"eval_count,omitempty"`
+}
+
+type ChatResp struct {
+	Model      string    `json:"model"`
+	CreatedAt  time.Time `json:"created_at"`
+	Message    Message   `json:"message"`
+	Done       bool      `json:"done"`
+	DoneReason string    `json:"done_reason,omitempty"`
+	Metrics
+}
+
+type GenerateReq struct {
+	Model  string   `json:"model"`
+	Prompt string   `json:"prompt"`
+	System string   `json:"system,omitempty"`
+	Images []string `json:"images,omitempty"`
+	// Either "json" or a JSON schema.
+	Format json.RawMessage `json:"format,omitempty"`
+	// Either a boolean or an effort level of "low", "medium" or "high".
+	Think   json.RawMessage `json:"think,omitempty"`
+	Options *Options        `json:"options,omitempty"`
+	// Ollama streams unless explicitly disabled.
+	Stream *bool `json:"stream,omitempty"`
+}
+
+type GenerateResp struct {
+	Model      string    `json:"model"`
+	CreatedAt  time.Time `json:"created_at"`
+	Response   string    `json:"response"`
+	Thinking   string    `json:"thinking,omitempty"`
+	Done       bool      `json:"done"`
+	DoneReason string    `json:"done_reason,omitempty"`
+	Metrics
+}
+
+// EmbedInput is either a single string or an array of strings.
+type EmbedInput []string
+
+func (i *EmbedInput) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*i = EmbedInput{text}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(i))
+}
+
+type EmbedReq struct {
+	Model      string     `json:"model"`
+	Input      EmbedInput `json:"input"`
+	Dimensions uint32     `json:"dimensions,omitempty"`
+}
+
+type EmbedResp struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount uint32      `json:"prompt_eval_count,omitempty"`
+}
+
+// EmbeddingsReq is the request of the legacy /api/embeddings endpoint.
+type EmbeddingsReq struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+type EmbeddingsResp struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+type ErrorResp struct {
+	Error string `json:"error"`
+}
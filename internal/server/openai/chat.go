@@ -41,6 +41,10 @@ func (s *Server) handleChatCompletion(httpCtx http.Context) (err error) {
 	}
 
 	req := convertChatRequestFromOpenAIChat(&openAIReq)
+	req.ToolChoice, err = convertToolChoiceFromOpenAI(requestBody)
+	if err != nil {
+		return err
+	}
 
 	if gjson.GetBytes(requestBody, "stream").Bool() {
 		httpCtx.Response().Header().Set("Content-Type", "text/event-stream")
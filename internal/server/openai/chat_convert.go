@@ -15,8 +15,10 @@
 package openai
 
 import (
+	"github.com/go-kratos/kratos/v3/errors"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/shared"
+	"github.com/tidwall/gjson"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/util"
@@ -101,6 +103,65 @@ func convertChatRequestFromOpenAIChat(req *openai.ChatCompletionNewParams) *v1.C
 	}
 }
 
+// convertToolChoiceFromOpenAI reads the tool choice of a raw Chat Completions
+// or Responses request body. It is read straight from the JSON because the SDK
+// resolves every object choice to its first object variant, so forced
+// functions would come out as allowed tools. The function name is nested in
+// Chat Completions and flat in Responses, and so are the allowed tools. Allowed
+// tools without a name, such as built-in ones, cannot be represented and are
+// rejected.
+func convertToolChoiceFromOpenAI(body []byte) (*v1.ToolChoice, error) {
+	toolChoice := &v1.ToolChoice{}
+	switch choice := gjson.GetBytes(body, "tool_choice"); {
+	case choice.Type == gjson.String:
+		toolChoice.Mode = convertToolChoiceModeFromOpenAI(choice.String())
+	case choice.Get("type").String() == "allowed_tools":
+		allowed := choice.Get("allowed_tools")
+		if !allowed.Exists() {
+			allowed = choice
+		}
+		toolChoice.Mode = convertToolChoiceModeFromOpenAI(allowed.Get("mode").String())
+		for _, tool := range allowed.Get("tools").Array() {
+			name := tool.Get(tool.Get("type").String() + ".name")
+			if !name.Exists() {
+				name = tool.Get("name")
+			}
+			if name.String() == "" {
+				return nil, errors.BadRequest("", "allowed tools must be named: "+tool.Raw)
+			}
+			toolChoice.AllowedNames = append(toolChoice.AllowedNames, name.String())
+		}
+	case choice.Get("type").String() == "function":
+		toolChoice.Mode = v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL
+		name := choice.Get("function.name")
+		if !name.Exists() {
+			name = choice.Get("name")
+		}
+		toolChoice.Name = name.String()
+	}
+	if parallelToolCalls := gjson.GetBytes(body, "parallel_tool_calls"); parallelToolCalls.IsBool() {
+		toolChoice.ParallelToolCalls = new(parallelToolCalls.Bool())
+	}
+
+	if toolChoice.Mode == v1.ToolChoiceMode_TOOL_CHOICE_MODE_UNSPECIFIED && toolChoice.ParallelToolCalls == nil {
+		return nil, nil
+	}
+	return toolChoice, nil
+}
+
+func convertToolChoiceModeFromOpenAI(mode string) v1.ToolChoiceMode {
+	switch mode {
+	case "auto":
+		return v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO
+	case "none":
+		return v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE
+	case "required":
+		return v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED
+	default:
+		return v1.ToolChoiceMode_TOOL_CHOICE_MODE_UNSPECIFIED
+	}
+}
+
 func convertChatMessageParamFromOpenAIChat(msg openai.ChatCompletionMessageParamUnion) *v1.Message {
 	if msg.OfDeveloper != nil {
 		return convertDeveloperMessageFromOpenAIChat(msg.OfDeveloper)
@@ -17,6 +17,7 @@ package openai
 import (
 	"testing"
 
+	kerrors "github.com/go-kratos/kratos/v3/errors"
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/shared"
 	. "github.com/smartystreets/goconvey/convey"
@@ -1171,10 +1172,11 @@ func TestConvertEmbeddingReqFromOpenAI(t *testing.T) {
 			}
 			result := convertEmbeddingReqFromOpenAI(req)
 
-			Convey("Then it should create an EmbedRequest with text content", func() {
+			Convey("Then it should create an EmbedRequest with a single input", func() {
 				So(result.Model, ShouldEqual, "text-embedding-3-small")
-				So(result.Contents, ShouldHaveLength, 1)
-				So(result.Contents[0].GetText().GetText(), ShouldEqual, "Hello world")
+				So(result.Inputs, ShouldHaveLength, 1)
+				So(result.Inputs[0].Contents, ShouldHaveLength, 1)
+				So(result.Inputs[0].Contents[0].GetText().GetText(), ShouldEqual, "Hello world")
 			})
 		})
 
@@ -1187,10 +1189,27 @@ func TestConvertEmbeddingReqFromOpenAI(t *testing.T) {
 			}
 			result := convertEmbeddingReqFromOpenAI(req)
 
-			Convey("Then it should use the first string", func() {
+			Convey("Then each string should become an input", func() {
+				So(result.Normalize, ShouldBeTrue)
 				So(result.Model, ShouldEqual, "text-embedding-3-large")
-				So(result.Contents, ShouldHaveLength, 1)
-				So(result.Contents[0].GetText().GetText(), ShouldEqual, "first")
+				So(result.Inputs, ShouldHaveLength, 2)
+				So(result.Inputs[0].Contents[0].GetText().GetText(), ShouldEqual, "first")
+				So(result.Inputs[1].Contents[0].GetText().GetText(), ShouldEqual, "second")
+			})
+		})
+
+		Convey("When dimensions are requested", func() {
+			req := &openai.EmbeddingNewParams{
+				Model: "text-embedding-3-small",
+				Input: openai.EmbeddingNewParamsInputUnion{
+					OfString: openai.Opt("Hello world"),
+				},
+				Dimensions: openai.Opt(int64(768)),
+			}
+			result := convertEmbeddingReqFromOpenAI(req)
+
+			Convey("Then they should be passed through", func() {
+				So(result.GetDimensions(), ShouldEqual, 768)
 			})
 		})
 
@@ -1203,8 +1222,8 @@ func TestConvertEmbeddingReqFromOpenAI(t *testing.T) {
 			}
 			result := convertEmbeddingReqFromOpenAI(req)
 
-			Convey("Then contents should be empty", func() {
-				So(result.Contents, ShouldBeEmpty)
+			Convey("Then inputs should be empty", func() {
+				So(result.Inputs, ShouldBeEmpty)
 			})
 		})
 
@@ -1214,8 +1233,8 @@ func TestConvertEmbeddingReqFromOpenAI(t *testing.T) {
 			}
 			result := convertEmbeddingReqFromOpenAI(req)
 
-			Convey("Then contents should be empty", func() {
-				So(result.Contents, ShouldBeEmpty)
+			Convey("Then inputs should be empty", func() {
+				So(result.Inputs, ShouldBeEmpty)
 			})
 		})
 	})
@@ -1226,35 +1245,149 @@ func TestConvertEmbeddingRespToOpenAI(t *testing.T) {
 
 		Convey("When embedding has values", func() {
 			resp := &v1.EmbedResponse{
-				Model:     "text-embedding-3-small",
-				Embedding: []float32{0.1, 0.2, 0.3, -0.5},
+				Model: "text-embedding-3-small",
+				Embeddings: []*v1.EmbeddingVector{
+					{Values: []float32{0.1, 0.2, 0.3, -0.5}},
+				},
+				Usage: &v1.Usage{InputTokens: 5},
 			}
-			result := convertEmbeddingRespToOpenAI(resp)
+			result := convertEmbeddingRespToOpenAI(resp, openai.EmbeddingNewParamsEncodingFormatFloat)
 
 			Convey("Then the response should have correct fields", func() {
 				So(result.Object, ShouldEqual, "list")
 				So(result.Model, ShouldEqual, "text-embedding-3-small")
 				So(result.Data, ShouldHaveLength, 1)
 				So(result.Data[0].Index, ShouldEqual, 0)
-				So(result.Data[0].Embedding, ShouldHaveLength, 4)
-				So(result.Data[0].Embedding[0], ShouldAlmostEqual, 0.1, 0.001)
-				So(result.Data[0].Embedding[1], ShouldAlmostEqual, 0.2, 0.001)
-				So(result.Data[0].Embedding[2], ShouldAlmostEqual, 0.3, 0.001)
-				So(result.Data[0].Embedding[3], ShouldAlmostEqual, -0.5, 0.001)
+				embedding := result.Data[0].Embedding.([]float64)
+				So(embedding, ShouldHaveLength, 4)
+				So(embedding[0], ShouldAlmostEqual, 0.1, 0.001)
+				So(embedding[1], ShouldAlmostEqual, 0.2, 0.001)
+				So(embedding[2], ShouldAlmostEqual, 0.3, 0.001)
+				So(embedding[3], ShouldAlmostEqual, -0.5, 0.001)
+				So(result.Usage.PromptTokens, ShouldEqual, 5)
+				So(result.Usage.TotalTokens, ShouldEqual, 5)
+			})
+		})
+
+		Convey("When there are multiple embeddings", func() {
+			resp := &v1.EmbedResponse{
+				Model: "text-embedding-3-small",
+				Embeddings: []*v1.EmbeddingVector{
+					{Index: 0, Values: []float32{0.1}},
+					{Index: 1, Values: []float32{0.2}},
+				},
+			}
+			result := convertEmbeddingRespToOpenAI(resp, openai.EmbeddingNewParamsEncodingFormatFloat)
+
+			Convey("Then each embedding should keep its index", func() {
+				So(result.Data, ShouldHaveLength, 2)
+				So(result.Data[1].Index, ShouldEqual, 1)
+				So(result.Data[1].Embedding.([]float64)[0], ShouldAlmostEqual, 0.2, 0.001)
 			})
 		})
 
 		Convey("When embedding is empty", func() {
 			resp := &v1.EmbedResponse{
-				Model:     "text-embedding-3-small",
-				Embedding: []float32{},
+				Model: "text-embedding-3-small",
+				Embeddings: []*v1.EmbeddingVector{
+					{Values: []float32{}},
+				},
 			}
-			result := convertEmbeddingRespToOpenAI(resp)
+			result := convertEmbeddingRespToOpenAI(resp, openai.EmbeddingNewParamsEncodingFormatFloat)
 
 			Convey("Then the embedding should be empty", func() {
 				So(result.Data, ShouldHaveLength, 1)
 				So(result.Data[0].Embedding, ShouldBeEmpty)
 			})
 		})
+
+		Convey("When base64 encoding is requested", func() {
+			resp := &v1.EmbedResponse{
+				Embeddings: []*v1.EmbeddingVector{
+					{Values: []float32{1, -2}},
+				},
+			}
+			result := convertEmbeddingRespToOpenAI(resp, openai.EmbeddingNewParamsEncodingFormatBase64)
+
+			Convey("Then the embedding should be packed as little-endian float32", func() {
+				So(result.Data[0].Embedding, ShouldEqual, "AACAPwAAAMA=")
+			})
+		})
+	})
+}
+
+func TestConvertToolChoiceFromOpenAI(t *testing.T) {
+	Convey("Given Chat Completions tool choices", t, func() {
+		convert := func(body string) *v1.ToolChoice {
+			toolChoice, err := convertToolChoiceFromOpenAI([]byte(body))
+			So(err, ShouldBeNil)
+			return toolChoice
+		}
+
+		Convey("When no tool choice is set", func() {
+			toolChoice := convert(`{"model":"gpt-4o","messages":[]}`)
+
+			Convey("Then the upstream default is kept", func() {
+				So(toolChoice, ShouldBeNil)
+			})
+		})
+
+		Convey("When a mode is set along with parallel tool calls", func() {
+			toolChoice := convert(`{"model":"gpt-4o","messages":[],"tool_choice":"required","parallel_tool_calls":false}`)
+
+			Convey("Then both are converted", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED)
+				So(toolChoice.ParallelToolCalls, ShouldNotBeNil)
+				So(toolChoice.GetParallelToolCalls(), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a specific function is forced", func() {
+			toolChoice := convert(`{"model":"gpt-4o","messages":[],"tool_choice":{"type":"function","function":{"name":"get_weather"}}}`)
+
+			Convey("Then the function is named", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL)
+				So(toolChoice.Name, ShouldEqual, "get_weather")
+				So(toolChoice.ParallelToolCalls, ShouldBeNil)
+			})
+		})
+
+		Convey("When tools are disabled", func() {
+			toolChoice := convert(`{"model":"gpt-4o","messages":[],"tool_choice":"none"}`)
+
+			Convey("Then the mode is none", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE)
+			})
+		})
+
+		Convey("When the tools are restricted", func() {
+			toolChoice := convert(`{"model":"gpt-4o","messages":[],"tool_choice":{"type":"allowed_tools",` +
+				`"allowed_tools":{"mode":"required","tools":[{"type":"function","function":{"name":"get_weather"}},` +
+				`{"type":"custom","custom":{"name":"run"}}]}}}`)
+
+			Convey("Then the allowed tools are named", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED)
+				So(toolChoice.AllowedNames, ShouldResemble, []string{"get_weather", "run"})
+			})
+		})
+
+		Convey("When the tools are restricted in the Responses form", func() {
+			toolChoice := convert(`{"model":"gpt-5","input":"hi","tool_choice":{"type":"allowed_tools",` +
+				`"mode":"auto","tools":[{"type":"function","name":"get_weather"}]}}`)
+
+			Convey("Then the allowed tools are named", func() {
+				So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO)
+				So(toolChoice.AllowedNames, ShouldResemble, []string{"get_weather"})
+			})
+		})
+
+		Convey("When a built-in tool is allowed", func() {
+			_, err := convertToolChoiceFromOpenAI([]byte(`{"model":"gpt-5","input":"hi","tool_choice":` +
+				`{"type":"allowed_tools","mode":"auto","tools":[{"type":"web_search"}]}}`))
+
+			Convey("Then the request is rejected", func() {
+				So(kerrors.IsBadRequest(err), ShouldBeTrue)
+			})
+		})
 	})
 }
@@ -75,13 +75,46 @@ type chatCompletionChunk struct {
 	Usage   *openai.CompletionUsage     `json:"usage,omitempty"`
 }
 
+type completionChoice struct {
+	Text         string `json:"text"`
+	Index        int64  `json:"index"`
+	FinishReason string `json:"finish_reason"`
+}
+
+type completionResponse struct {
+	ID      string                  `json:"id"`
+	Object  string                  `json:"object"`
+	Model   string                  `json:"model"`
+	Choices []completionChoice      `json:"choices"`
+	Usage   *openai.CompletionUsage `json:"usage,omitempty"`
+}
+
 type embeddingResponse struct {
-	Object string             `json:"object"`
-	Model  string             `json:"model"`
-	Data   []openai.Embedding `json:"data"`
+	Object string                              `json:"object"`
+	Model  string                              `json:"model"`
+	Data   []embeddingData                     `json:"data"`
+	Usage  openai.CreateEmbeddingResponseUsage `json:"usage"`
+}
+
+type embeddingData struct {
+	Object string `json:"object"`
+	Index  int64  `json:"index"`
+	// Either a float array or base64 of the little-endian float32 values.
+	Embedding any `json:"embedding"`
 }
 
 type modelsList struct {
 	Object string         `json:"object"`
 	Models []openai.Model `json:"data"`
 }
+
+type errorBody struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"`
+	Param   *string `json:"param"`
+	Code    *string `json:"code"`
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/go-kratos/kratos/v3/transport/http"
+	"github.com/openai/openai-go/v3"
+	"github.com/tidwall/gjson"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+func (s *Server) handleCompletion(httpCtx http.Context) (err error) {
+	requestBody, err := io.ReadAll(httpCtx.Request().Body)
+	if err != nil {
+		return
+	}
+
+	var openAIReq openai.CompletionNewParams
+	err = json.Unmarshal(requestBody, &openAIReq)
+	if err != nil {
+		return err
+	}
+
+	req, err := convertCompletionRequestFromOpenAI(&openAIReq)
+	if err != nil {
+		return err
+	}
+
+	if gjson.GetBytes(requestBody, "stream").Bool() {
+		httpCtx.Response().Header().Set("Content-Type", "text/event-stream")
+		httpCtx.Response().Header().Set("Cache-Control", "no-cache")
+		httpCtx.Response().Header().Set("Connection", "keep-alive")
+
+		middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+			util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
+			streamServer := &completionStreamServer{
+				ctx:          ctx,
+				httpCtx:      httpCtx,
+				includeUsage: gjson.GetBytes(requestBody, "stream_options.include_usage").Bool(),
+			}
+			if s.otelLogger != nil {
+				streamServer.buffer = &bytes.Buffer{}
+			}
+			err := s.completeSvc.CompleteStream(req.(*v1.CompleteRequest), streamServer)
+			if err == nil {
+				err = streamServer.sendDone()
+			}
+			if s.otelLogger != nil {
+				util.EmitEvent(ctx, s.otelLogger, util.EventServerRespSent, streamServer.buffer.Bytes())
+			}
+			return nil, err
+		})
+		_, err = middleware(httpCtx, req)
+		return
+	}
+
+	var eventCtx context.Context = httpCtx
+	middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		eventCtx = ctx
+		util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
+		return s.completeSvc.Complete(ctx, req.(*v1.CompleteRequest))
+	})
+
+	resp, err := middleware(httpCtx, req)
+	if err != nil {
+		return err
+	}
+
+	respBytes, err := json.Marshal(convertCompletionResponseToOpenAI(resp.(*v1.CompleteResponse)))
+	if err != nil {
+		return err
+	}
+
+	util.EmitEvent(eventCtx, s.otelLogger, util.EventServerRespSent, respBytes)
+
+	return httpCtx.Blob(200, "application/json", respBytes)
+}
+
+type completionStreamServer struct {
+	v1.Completion_CompleteStreamServer
+	ctx          context.Context
+	httpCtx      http.Context
+	buffer       *bytes.Buffer
+	includeUsage bool
+}
+
+func (c *completionStreamServer) Context() context.Context {
+	return c.ctx
+}
+
+func (c *completionStreamServer) write(resp *completionResponse) error {
+	respJson, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	data := append([]byte("data: "), respJson...)
+	data = append(data, '\n', '\n')
+
+	if c.buffer != nil {
+		c.buffer.Write(data)
+	}
+
+	_, err = c.httpCtx.Response().Write(data)
+	if err != nil {
+		return err
+	}
+	c.httpCtx.Response().(http.Flusher).Flush()
+	return nil
+}
+
+func (c *completionStreamServer) Send(resp *v1.CompleteResponse) error {
+	chunk := convertCompletionResponseToOpenAI(resp)
+	usage := chunk.Usage
+	chunk.Usage = nil
+
+	// The last response carries the status, chunks before only carry text
+	if len(resp.Contents) > 0 || resp.Status != v1.ChatStatus_CHAT_STATUS_UNSPECIFIED {
+		if err := c.write(chunk); err != nil {
+			return err
+		}
+	}
+
+	// Like the chat completions API, usage is reported in a trailing chunk
+	// without choices when requested
+	if c.includeUsage && usage != nil {
+		chunk.Choices = []completionChoice{}
+		chunk.Usage = usage
+		return c.write(chunk)
+	}
+	return nil
+}
+
+func (c *completionStreamServer) sendDone() error {
+	data := []byte("data: [DONE]\n\n")
+	if c.buffer != nil {
+		c.buffer.Write(data)
+	}
+	_, err := c.httpCtx.Response().Write(data)
+	if err != nil {
+		return err
+	}
+	c.httpCtx.Response().(http.Flusher).Flush()
+	return nil
+}
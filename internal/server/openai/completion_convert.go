@@ -0,0 +1,96 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"strings"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	"github.com/openai/openai-go/v3"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func convertCompletionRequestFromOpenAI(req *openai.CompletionNewParams) (*v1.CompleteRequest, error) {
+	config := &v1.GenerationConfig{}
+
+	if req.MaxTokens.Valid() {
+		config.MaxTokens = new(req.MaxTokens.Value)
+	}
+	if req.Temperature.Valid() {
+		config.Temperature = new(float32(req.Temperature.Value))
+	}
+	if req.TopP.Valid() {
+		config.TopP = new(float32(req.TopP.Value))
+	}
+	if req.FrequencyPenalty.Valid() {
+		config.FrequencyPenalty = new(float32(req.FrequencyPenalty.Value))
+	}
+	if req.PresencePenalty.Valid() {
+		config.PresencePenalty = new(float32(req.PresencePenalty.Value))
+	}
+
+	if len(req.Stop.OfStringArray) > 0 {
+		config.StopSequences = req.Stop.OfStringArray
+	} else if req.Stop.OfString.Valid() {
+		config.StopSequences = []string{req.Stop.OfString.Value}
+	}
+
+	// Batched prompts would need one choice each, and token prompts the
+	// tokenizer of the model, so both are rejected rather than dropped
+	if req.Prompt.OfArrayOfTokens != nil || req.Prompt.OfArrayOfTokenArrays != nil {
+		return nil, errors.BadRequest("", "token prompts are not supported")
+	}
+	if len(req.Prompt.OfArrayOfStrings) > 1 {
+		return nil, errors.BadRequest("", "batched prompts are not supported")
+	}
+	prompt := req.Prompt.OfString.Value
+	if len(req.Prompt.OfArrayOfStrings) == 1 {
+		prompt = req.Prompt.OfArrayOfStrings[0]
+	}
+
+	completeReq := &v1.CompleteRequest{
+		Model:    string(req.Model),
+		Config:   config,
+		Contents: []*v1.Content{{Content: v1.NewTextContent(prompt)}},
+	}
+	if req.Suffix.Valid() && req.Suffix.Value != "" {
+		completeReq.Suffix = []*v1.Content{{Content: v1.NewTextContent(req.Suffix.Value)}}
+	}
+	return completeReq, nil
+}
+
+func convertCompletionResponseToOpenAI(resp *v1.CompleteResponse) *completionResponse {
+	var text strings.Builder
+	for _, content := range resp.Contents {
+		if content.Phase != v1.ContentPhase_CONTENT_PHASE_REASONING {
+			text.WriteString(content.GetText().GetText())
+		}
+	}
+
+	r := &completionResponse{
+		ID:     resp.Id,
+		Object: "text_completion",
+		Model:  resp.Model,
+		Choices: []completionChoice{{
+			Text:         text.String(),
+			FinishReason: convertStatusToOpenAIChat(resp.Status),
+		}},
+	}
+	if resp.Statistics != nil {
+		r.Usage = convertUsageToOpenAIChat(resp.Statistics.Usage)
+	}
+	return r
+}
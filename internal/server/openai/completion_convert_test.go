@@ -0,0 +1,120 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	"github.com/openai/openai-go/v3"
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func TestConvertCompletionRequestFromOpenAI(t *testing.T) {
+	Convey("Given an OpenAI completion request", t, func() {
+		var req openai.CompletionNewParams
+
+		Convey("When it has a prompt, a suffix and sampling parameters", func() {
+			err := json.Unmarshal([]byte(`{
+				"model": "gpt-3.5-turbo-instruct",
+				"prompt": "def add(a, b):",
+				"suffix": "\n\nprint(add(1, 2))",
+				"max_tokens": 16,
+				"temperature": 0.2,
+				"stop": "\n\n"
+			}`), &req)
+			So(err, ShouldBeNil)
+
+			result, err := convertCompletionRequestFromOpenAI(&req)
+			So(err, ShouldBeNil)
+
+			Convey("Then it should convert to a fill-in-the-middle request", func() {
+				So(result.Model, ShouldEqual, "gpt-3.5-turbo-instruct")
+				So(result.Contents, ShouldHaveLength, 1)
+				So(result.Contents[0].GetText().GetText(), ShouldEqual, "def add(a, b):")
+				So(result.Suffix, ShouldHaveLength, 1)
+				So(result.Suffix[0].GetText().GetText(), ShouldEqual, "\n\nprint(add(1, 2))")
+				So(result.Config.GetMaxTokens(), ShouldEqual, 16)
+				So(result.Config.GetTemperature(), ShouldAlmostEqual, 0.2, 1e-6)
+				So(result.Config.StopSequences, ShouldResemble, []string{"\n\n"})
+			})
+		})
+
+		Convey("When the prompt is an array of one string", func() {
+			err := json.Unmarshal([]byte(`{"model": "m", "prompt": ["first"]}`), &req)
+			So(err, ShouldBeNil)
+
+			result, err := convertCompletionRequestFromOpenAI(&req)
+			So(err, ShouldBeNil)
+
+			Convey("Then the prompt should be completed", func() {
+				So(result.Contents[0].GetText().GetText(), ShouldEqual, "first")
+				So(result.Suffix, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the prompt is a batch of strings", func() {
+			err := json.Unmarshal([]byte(`{"model": "m", "prompt": ["first", "second"]}`), &req)
+			So(err, ShouldBeNil)
+
+			_, err = convertCompletionRequestFromOpenAI(&req)
+
+			Convey("Then it should be rejected", func() {
+				So(errors.IsBadRequest(err), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the prompt is made of tokens", func() {
+			for _, prompt := range []string{`[1, 2, 3]`, `[[1, 2], [3]]`} {
+				err := json.Unmarshal([]byte(`{"model": "m", "prompt": `+prompt+`}`), &req)
+				So(err, ShouldBeNil)
+
+				_, err = convertCompletionRequestFromOpenAI(&req)
+				So(errors.IsBadRequest(err), ShouldBeTrue)
+			}
+		})
+	})
+}
+
+func TestConvertCompletionResponseToOpenAI(t *testing.T) {
+	Convey("Given a completion response", t, func() {
+		resp := &v1.CompleteResponse{
+			Id:    "cmpl-1",
+			Model: "gpt-3.5-turbo-instruct",
+			Contents: []*v1.Content{
+				{Phase: v1.ContentPhase_CONTENT_PHASE_REASONING, Content: v1.NewTextContent("thinking")},
+				{Content: v1.NewTextContent("    return a + b")},
+			},
+			Statistics: &v1.Statistics{Usage: &v1.Usage{InputTokens: 5, OutputTokens: 7}},
+			Status:     v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT,
+		}
+
+		Convey("When converting it to the OpenAI format", func() {
+			result := convertCompletionResponseToOpenAI(resp)
+
+			Convey("Then it should be a text completion without reasoning", func() {
+				So(result.ID, ShouldEqual, "cmpl-1")
+				So(result.Object, ShouldEqual, "text_completion")
+				So(result.Choices, ShouldHaveLength, 1)
+				So(result.Choices[0].Text, ShouldEqual, "    return a + b")
+				So(result.Choices[0].FinishReason, ShouldEqual, "length")
+				So(result.Usage.TotalTokens, ShouldEqual, 12)
+			})
+		})
+	})
+}
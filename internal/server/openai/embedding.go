@@ -47,6 +47,6 @@ func (s *Server) handleEmbedding(httpCtx http.Context) error {
 		return err
 	}
 
-	openAIResp := convertEmbeddingRespToOpenAI(resp.(*v1.EmbedResponse))
+	openAIResp := convertEmbeddingRespToOpenAI(resp.(*v1.EmbedResponse), openAIReq.EncodingFormat)
 	return httpCtx.Result(200, openAIResp)
 }
@@ -15,43 +15,79 @@
 package openai
 
 import (
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+
 	"github.com/openai/openai-go/v3"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 )
 
 func convertEmbeddingReqFromOpenAI(req *openai.EmbeddingNewParams) *v1.EmbedRequest {
-	var contents []*v1.Content
+	var inputs []*v1.EmbedInput
 
 	if req.Input.OfString.Valid() {
-		contents = append(contents, &v1.Content{
-			Content: v1.NewTextContent(req.Input.OfString.Value),
-		})
-	} else if len(req.Input.OfArrayOfStrings) > 0 {
-		contents = append(contents, &v1.Content{
-			Content: v1.NewTextContent(req.Input.OfArrayOfStrings[0]),
-		})
+		inputs = append(inputs, newTextEmbedInput(req.Input.OfString.Value))
+	} else {
+		for _, text := range req.Input.OfArrayOfStrings {
+			inputs = append(inputs, newTextEmbedInput(text))
+		}
+	}
+
+	embedReq := &v1.EmbedRequest{
+		Model:  string(req.Model),
+		Inputs: inputs,
+		// OpenAI embeddings are always normalized to length 1
+		Normalize: true,
+	}
+	if req.Dimensions.Valid() {
+		embedReq.Dimensions = new(uint32(req.Dimensions.Value))
 	}
+	return embedReq
+}
 
-	return &v1.EmbedRequest{
-		Model:    string(req.Model),
-		Contents: contents,
+func newTextEmbedInput(text string) *v1.EmbedInput {
+	return &v1.EmbedInput{
+		Contents: []*v1.Content{{Content: v1.NewTextContent(text)}},
 	}
 }
 
-func convertEmbeddingRespToOpenAI(resp *v1.EmbedResponse) *embeddingResponse {
-	embedding := make([]float64, len(resp.Embedding))
-	for i, v := range resp.Embedding {
-		embedding[i] = float64(v)
+func convertEmbeddingRespToOpenAI(resp *v1.EmbedResponse, format openai.EmbeddingNewParamsEncodingFormat) *embeddingResponse {
+	data := make([]embeddingData, 0, len(resp.Embeddings))
+	for _, e := range resp.Embeddings {
+		d := embeddingData{
+			Object: "embedding",
+			Index:  int64(e.Index),
+		}
+		if format == openai.EmbeddingNewParamsEncodingFormatBase64 {
+			d.Embedding = encodeEmbeddingBase64(e.Values)
+		} else {
+			embedding := make([]float64, len(e.Values))
+			for i, v := range e.Values {
+				embedding[i] = float64(v)
+			}
+			d.Embedding = embedding
+		}
+		data = append(data, d)
 	}
+	inputTokens := int64(resp.GetUsage().GetInputTokens())
 	return &embeddingResponse{
 		Object: "list",
 		Model:  resp.Model,
-		Data: []openai.Embedding{
-			{
-				Index:     0,
-				Embedding: embedding,
-			},
+		Data:   data,
+		Usage: openai.CreateEmbeddingResponseUsage{
+			PromptTokens: inputTokens,
+			TotalTokens:  inputTokens,
 		},
 	}
 }
+
+// encodeEmbeddingBase64 packs an embedding as little-endian float32 values.
+func encodeEmbeddingBase64(values []float32) string {
+	buf := make([]byte, 0, 4*len(values))
+	for _, v := range values {
+		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"net/http"
+
+	"github.com/go-kratos/kratos/v3/errors"
+	khttp "github.com/go-kratos/kratos/v3/transport/http"
+)
+
+// writeError renders err in the OpenAI error format, so that OpenAI clients
+// recognise rate limits and other failures.
+func writeError(httpCtx khttp.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := errors.FromError(err)
+
+	body := errorBody{
+		Message: e.Message,
+		Type:    "server_error",
+	}
+	if e.Reason != "" {
+		body.Code = &e.Reason
+	}
+	switch code := int(e.Code); {
+	case code == http.StatusTooManyRequests:
+		body.Type = "requests"
+		body.Code = new("rate_limit_exceeded")
+	case code == http.StatusUnauthorized:
+		body.Type = "authentication_error"
+	case code == http.StatusForbidden:
+		body.Type = "permission_error"
+	case code == http.StatusNotFound:
+		body.Type = "not_found_error"
+	case code >= 400 && code < 500:
+		body.Type = "invalid_request_error"
+	}
+	return httpCtx.JSON(int(e.Code), &errorResponse{Error: body})
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func TestWriteError(t *testing.T) {
+	Convey("Test writeError", t, func() {
+		Convey("nil error should write nothing", func() {
+			ctx := newResponsesTestHTTPContext()
+			So(writeError(ctx, nil), ShouldBeNil)
+			So(ctx.body.Len(), ShouldEqual, 0)
+		})
+
+		Convey("client quota errors should be rendered as OpenAI rate limits", func() {
+			ctx := newResponsesTestHTTPContext()
+			So(writeError(ctx, entity.ErrClientQuotaExceeded), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusTooManyRequests)
+
+			var resp errorResponse
+			So(json.Unmarshal(ctx.body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error.Message, ShouldEqual, "client quota exceeded")
+			So(resp.Error.Type, ShouldEqual, "requests")
+			So(*resp.Error.Code, ShouldEqual, "rate_limit_exceeded")
+		})
+
+		Convey("unknown errors should be rendered as server errors", func() {
+			ctx := newResponsesTestHTTPContext()
+			So(writeError(ctx, errors.New("boom")), ShouldBeNil)
+			So(ctx.statusCode, ShouldEqual, http.StatusInternalServerError)
+
+			var resp errorResponse
+			So(json.Unmarshal(ctx.body.Bytes(), &resp), ShouldBeNil)
+			So(resp.Error.Type, ShouldEqual, "server_error")
+		})
+	})
+}
@@ -44,3 +44,9 @@ func (s *Server) handleListModels(httpCtx http.Context) error {
 
 	return httpCtx.Result(200, openaiResp)
 }
+
+// HandleListModels serves the OpenAI model listing. It is exported for the
+// model paths shared with other APIs, which dispatch by request headers.
+func (s *Server) HandleListModels(httpCtx http.Context) error {
+	return s.handleListModels(httpCtx)
+}
@@ -15,25 +15,47 @@
 package openai
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/go-kratos/kratos/v3/transport/http"
 	otellog "go.opentelemetry.io/otel/log"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/service"
 )
 
+// responseStore keeps the responses of stateful Responses API clients.
+type responseStore interface {
+	// StoresResponses reports whether a response store is configured.
+	StoresResponses() bool
+	// ChatStreamResponse streams a chat whose response is stored, returning
+	// the response the stream reduces to.
+	ChatStreamResponse(*v1.ChatRequest, v1.Chat_ChatStreamServer) (*v1.ChatResponse, error)
+	SaveResponse(context.Context, *entity.StoredResponse)
+	GetResponse(context.Context, string) (*entity.StoredResponse, error)
+	DeleteResponse(context.Context, string) error
+}
+
 type Server struct {
-	modelSvc   v1.ModelServer
-	chatSvc    v1.ChatServer
-	embedSvc   v1.EmbeddingServer
-	otelLogger otellog.Logger
+	modelSvc    v1.ModelServer
+	chatSvc     v1.ChatServer
+	embedSvc    v1.EmbeddingServer
+	completeSvc v1.CompletionServer
+	responseSvc responseStore
+	otelLogger  otellog.Logger
+	log         *slog.Logger
 }
 
-func NewServer(svc *service.RouterService, loggerProvider otellog.LoggerProvider) (s *Server) {
+func NewServer(svc *service.RouterService, loggerProvider otellog.LoggerProvider, logger *slog.Logger) (s *Server) {
 	s = &Server{
-		modelSvc: svc,
-		chatSvc:  svc,
-		embedSvc: svc,
+		modelSvc:    svc,
+		chatSvc:     svc,
+		embedSvc:    svc,
+		completeSvc: svc,
+		responseSvc: svc,
+		log:         logger,
 	}
 	if loggerProvider != nil {
 		s.otelLogger = loggerProvider.Logger("neurouter.server.openai")
@@ -50,7 +72,7 @@ func (s *Server) RegisterRoutes(srv *http.Server) {
 		"/openai/chat/completions",
 		"/openai/v1/chat/completions",
 	} {
-		r.POST(path, func(ctx http.Context) error { return s.handleChatCompletion(ctx) })
+		r.POST(path, func(ctx http.Context) error { return writeError(ctx, s.handleChatCompletion(ctx)) })
 	}
 
 	for _, path := range []string{
@@ -59,7 +81,25 @@ func (s *Server) RegisterRoutes(srv *http.Server) {
 		"/openai/responses",
 		"/openai/v1/responses",
 	} {
-		r.POST(path, func(ctx http.Context) error { return s.handleResponses(ctx) })
+		r.POST(path, func(ctx http.Context) error { return writeError(ctx, s.handleResponses(ctx)) })
+		r.GET(path+"/{id}", func(ctx http.Context) error {
+			return writeError(ctx, s.handleGetResponse(ctx, ctx.Vars().Get("id")))
+		})
+		r.DELETE(path+"/{id}", func(ctx http.Context) error {
+			return writeError(ctx, s.handleDeleteResponse(ctx, ctx.Vars().Get("id")))
+		})
+		r.GET(path+"/{id}/input_items", func(ctx http.Context) error {
+			return writeError(ctx, s.handleListResponseInputItems(ctx, ctx.Vars().Get("id")))
+		})
+	}
+
+	for _, path := range []string{
+		"/completions",
+		"/v1/completions",
+		"/openai/completions",
+		"/openai/v1/completions",
+	} {
+		r.POST(path, func(ctx http.Context) error { return writeError(ctx, s.handleCompletion(ctx)) })
 	}
 
 	for _, path := range []string{
@@ -68,15 +108,15 @@ func (s *Server) RegisterRoutes(srv *http.Server) {
 		"/openai/embeddings",
 		"/openai/v1/embeddings",
 	} {
-		r.POST(path, func(ctx http.Context) error { return s.handleEmbedding(ctx) })
+		r.POST(path, func(ctx http.Context) error { return writeError(ctx, s.handleEmbedding(ctx)) })
 	}
 
+	// The bare /models paths are shared with other APIs and registered by the
+	// HTTP server
 	for _, path := range []string{
-		"/models",
-		"/v1/models",
 		"/openai/models",
 		"/openai/v1/models",
 	} {
-		r.GET(path, func(ctx http.Context) error { return s.handleListModels(ctx) })
+		r.GET(path, s.HandleListModels)
 	}
 }
@@ -19,11 +19,17 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/go-kratos/kratos/v3/errors"
 	"github.com/go-kratos/kratos/v3/transport/http"
+	"github.com/google/uuid"
 	"github.com/tidwall/gjson"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/util"
 )
 
@@ -33,10 +39,13 @@ func (s *Server) handleResponses(httpCtx http.Context) (err error) {
 		return err
 	}
 
-	req, err := convertChatRequestFromOpenAIResponses(requestBody)
+	req, err := convertChatRequestFromOpenAIResponses(requestBody, nil)
 	if err != nil {
 		return err
 	}
+	// Responses are identified by the router rather than the upstream, so
+	// that stored responses never collide.
+	responseID := newResponsesID()
 
 	if gjson.GetBytes(requestBody, "stream").Bool() {
 		httpCtx.Response().Header().Set("Content-Type", "text/event-stream")
@@ -45,17 +54,30 @@ func (s *Server) handleResponses(httpCtx http.Context) (err error) {
 
 		middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
 			util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
+			chatReq, history, err := s.continueResponse(ctx, requestBody, req.(*v1.ChatRequest))
+			if err != nil {
+				return nil, err
+			}
 			streamServer := &responsesStreamServer{
 				ctx:     ctx,
 				httpCtx: httpCtx,
+				id:      responseID,
 			}
 			if s.otelLogger != nil {
 				streamServer.buffer = &bytes.Buffer{}
 			}
-			err := s.chatSvc.ChatStream(req.(*v1.ChatRequest), streamServer)
+			var chatResp *v1.ChatResponse
+			if s.shouldStoreResponse(requestBody) {
+				chatResp, err = s.responseSvc.ChatStreamResponse(chatReq, streamServer)
+			} else {
+				err = s.chatSvc.ChatStream(chatReq, streamServer)
+			}
 			if s.otelLogger != nil {
 				util.EmitEvent(ctx, s.otelLogger, util.EventServerRespSent, streamServer.buffer.Bytes())
 			}
+			if err == nil && chatResp != nil {
+				s.storeResponse(ctx, requestBody, history, chatResp.Message, &streamServer.response)
+			}
 			return nil, err
 		})
 		_, err = middleware(httpCtx, req)
@@ -63,20 +85,192 @@ func (s *Server) handleResponses(httpCtx http.Context) (err error) {
 	}
 
 	var eventCtx context.Context = httpCtx
+	var history []*v1.Message
 	middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
 		eventCtx = ctx
 		util.EmitEvent(ctx, s.otelLogger, util.EventServerReqReceived, requestBody)
-		return s.chatSvc.Chat(ctx, req.(*v1.ChatRequest))
+		chatReq, h, err := s.continueResponse(ctx, requestBody, req.(*v1.ChatRequest))
+		if err != nil {
+			return nil, err
+		}
+		history = h
+		return s.chatSvc.Chat(ctx, chatReq)
 	})
 	resp, err := middleware(httpCtx, req)
 	if err != nil {
 		return err
 	}
 
-	responseBody, err := json.Marshal(convertChatResponseToOpenAIResponses(resp.(*v1.ChatResponse)))
+	chatResp := resp.(*v1.ChatResponse)
+	openAIResp := convertChatResponseToOpenAIResponses(chatResp, responseID)
+	responseBody, err := json.Marshal(openAIResp)
 	if err != nil {
 		return err
 	}
 	util.EmitEvent(eventCtx, s.otelLogger, util.EventServerRespSent, responseBody)
+	if s.shouldStoreResponse(requestBody) {
+		s.storeResponse(eventCtx, requestBody, history, chatResp.Message, openAIResp)
+	}
 	return httpCtx.Blob(200, "application/json", responseBody)
 }
+
+// continueResponse expands the previous_response_id of the request into the
+// conversation it continues, returning the request to route along with that
+// conversation.
+func (s *Server) continueResponse(
+	ctx context.Context,
+	body []byte,
+	req *v1.ChatRequest,
+) (*v1.ChatRequest, []*v1.Message, error) {
+	previousID := gjson.GetBytes(body, "previous_response_id").String()
+	if previousID == "" {
+		return req, nil, nil
+	}
+	if s.responseSvc == nil {
+		return nil, nil, entity.ErrResponseNotFound
+	}
+
+	previous, err := s.responseSvc.GetResponse(ctx, previousID)
+	if err != nil {
+		return nil, nil, err
+	}
+	history := previous.Conversation()
+	req, err = convertChatRequestFromOpenAIResponses(body, history)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, history, nil
+}
+
+// newResponsesID returns a new id for a Responses API response.
+func newResponsesID() string {
+	return "resp_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+// shouldStoreResponse reports whether the response to the request is stored,
+// which the Responses API does unless the client opts out.
+func (s *Server) shouldStoreResponse(body []byte) bool {
+	if s.responseSvc == nil || !s.responseSvc.StoresResponses() {
+		return false
+	}
+	store := gjson.GetBytes(body, "store")
+	return store.Type == gjson.Null || store.Bool()
+}
+
+func (s *Server) storeResponse(
+	ctx context.Context,
+	body []byte,
+	history []*v1.Message,
+	output *v1.Message,
+	resp *responsesResponse,
+) {
+	if resp.ID == "" {
+		return
+	}
+	responseBody, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	inputItems, err := json.Marshal(convertInputItemsToOpenAIResponses(body, resp.ID))
+	if err != nil {
+		return
+	}
+
+	s.responseSvc.SaveResponse(ctx, &entity.StoredResponse{
+		Id:         resp.ID,
+		Messages:   append(slices.Clip(history), convertInputFromOpenAIResponses(body)...),
+		Output:     output,
+		Response:   responseBody,
+		InputItems: inputItems,
+	})
+}
+
+// getStoredResponse looks up a stored response on behalf of the calling
+// client, whose identity is only known inside the middleware chain.
+func (s *Server) getStoredResponse(httpCtx http.Context, id string) (*entity.StoredResponse, error) {
+	if s.responseSvc == nil {
+		return nil, entity.ErrResponseNotFound
+	}
+	middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return s.responseSvc.GetResponse(ctx, req.(string))
+	})
+	resp, err := middleware(httpCtx, id)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*entity.StoredResponse), nil
+}
+
+func (s *Server) handleGetResponse(httpCtx http.Context, id string) error {
+	resp, err := s.getStoredResponse(httpCtx, id)
+	if err != nil {
+		return err
+	}
+	return httpCtx.Blob(200, "application/json", resp.Response)
+}
+
+func (s *Server) handleDeleteResponse(httpCtx http.Context, id string) error {
+	if s.responseSvc == nil {
+		return entity.ErrResponseNotFound
+	}
+	middleware := httpCtx.Middleware(func(ctx context.Context, req any) (any, error) {
+		return nil, s.responseSvc.DeleteResponse(ctx, req.(string))
+	})
+	if _, err := middleware(httpCtx, id); err != nil {
+		return err
+	}
+	return httpCtx.JSON(200, &responsesDeletedResponse{
+		ID:      id,
+		Object:  "response",
+		Deleted: true,
+	})
+}
+
+func (s *Server) handleListResponseInputItems(httpCtx http.Context, id string) error {
+	resp, err := s.getStoredResponse(httpCtx, id)
+	if err != nil {
+		return err
+	}
+
+	var items []json.RawMessage
+	if err = json.Unmarshal(resp.InputItems, &items); err != nil {
+		return err
+	}
+
+	query := httpCtx.Request().URL.Query()
+	switch query.Get("order") {
+	case "", "desc":
+		slices.Reverse(items)
+	case "asc":
+	default:
+		return errors.BadRequest("", "order must be asc or desc")
+	}
+
+	if after := query.Get("after"); after != "" {
+		index := slices.IndexFunc(items, func(item json.RawMessage) bool {
+			return gjson.GetBytes(item, "id").String() == after
+		})
+		if index < 0 {
+			return errors.BadRequest("", "after must be the id of an input item")
+		}
+		items = items[index+1:]
+	}
+
+	limit := 20
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > 100 {
+			return errors.BadRequest("", "limit must be between 1 and 100")
+		}
+	}
+
+	list := &responsesInputItemList{
+		Object:  "list",
+		Data:    items[:min(limit, len(items))],
+		HasMore: len(items) > limit,
+	}
+	if len(list.Data) > 0 {
+		list.FirstID = gjson.GetBytes(list.Data[0], "id").String()
+		list.LastID = gjson.GetBytes(list.Data[len(list.Data)-1], "id").String()
+	}
+	return httpCtx.JSON(200, list)
+}
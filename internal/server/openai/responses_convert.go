@@ -21,12 +21,15 @@ import (
 
 	"github.com/openai/openai-go/v3/responses"
 	"github.com/tidwall/gjson"
+	"google.golang.org/protobuf/proto"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/util"
 )
 
-// convertChatRequestFromOpenAIResponses converts a raw Responses API request body.
+// convertChatRequestFromOpenAIResponses converts a raw Responses API request
+// body. The history is the conversation of the response it continues, which is
+// placed between the instructions and the input.
 //
 // Everything but the input items is decoded with the OpenAI SDK. The input
 // items are read straight from the JSON because the SDK registers
@@ -35,7 +38,7 @@ import (
 // which accepts neither output_text parts nor items that omit "type". Both
 // occur whenever a client replays our own output items, and the SDK drops them
 // without reporting an error.
-func convertChatRequestFromOpenAIResponses(body []byte) (*v1.ChatRequest, error) {
+func convertChatRequestFromOpenAIResponses(body []byte, history []*v1.Message) (*v1.ChatRequest, error) {
 	var req responses.ResponseNewParams
 	if err := json.Unmarshal(body, &req); err != nil {
 		return nil, err
@@ -48,6 +51,11 @@ func convertChatRequestFromOpenAIResponses(body []byte) (*v1.ChatRequest, error)
 		Tools:    convertToolsFromOpenAIResponses(req.Tools),
 		Metadata: map[string]string(req.Metadata),
 	}
+	toolChoice, err := convertToolChoiceFromOpenAI(body)
+	if err != nil {
+		return nil, err
+	}
+	chatReq.ToolChoice = toolChoice
 
 	if req.Instructions.Valid() {
 		appendAdjacentResponsesMessage(&chatReq.Messages, &v1.Message{
@@ -58,9 +66,24 @@ func convertChatRequestFromOpenAIResponses(body []byte) (*v1.ChatRequest, error)
 		})
 	}
 
+	// Stored messages are cloned, as merging adjacent messages modifies them
+	for _, message := range history {
+		appendAdjacentResponsesMessage(&chatReq.Messages, proto.Clone(message).(*v1.Message))
+	}
+	for _, message := range convertInputFromOpenAIResponses(body) {
+		appendAdjacentResponsesMessage(&chatReq.Messages, message)
+	}
+
+	return chatReq, nil
+}
+
+// convertInputFromOpenAIResponses converts the input items of a raw Responses
+// API request body.
+func convertInputFromOpenAIResponses(body []byte) []*v1.Message {
+	var messages []*v1.Message
 	input := gjson.GetBytes(body, "input")
 	if input.Type == gjson.String {
-		appendAdjacentResponsesMessage(&chatReq.Messages, &v1.Message{
+		appendAdjacentResponsesMessage(&messages, &v1.Message{
 			Role: v1.Role_ROLE_USER,
 			Contents: []*v1.Content{{
 				Content: v1.NewTextContent(input.String()),
@@ -68,11 +91,49 @@ func convertChatRequestFromOpenAIResponses(body []byte) (*v1.ChatRequest, error)
 		})
 	} else {
 		for _, item := range input.Array() {
-			appendAdjacentResponsesMessage(&chatReq.Messages, convertInputItemFromOpenAIResponses(item))
+			appendAdjacentResponsesMessage(&messages, convertInputItemFromOpenAIResponses(item))
 		}
 	}
+	return messages
+}
 
-	return chatReq, nil
+// convertInputItemsToOpenAIResponses renders the input of a raw Responses API
+// request body as the items listed by the input_items endpoint. The shorthand
+// forms are expanded and items without an id are given a synthetic one.
+func convertInputItemsToOpenAIResponses(body []byte, responseID string) []map[string]any {
+	input := gjson.GetBytes(body, "input")
+	if input.Type == gjson.String {
+		return []map[string]any{{
+			"id":      syntheticResponsesItemID("in", responseID, 0),
+			"type":    "message",
+			"role":    "user",
+			"status":  "completed",
+			"content": []map[string]any{{"type": "input_text", "text": input.String()}},
+		}}
+	}
+
+	items := make([]map[string]any, 0)
+	for i, raw := range input.Array() {
+		var item map[string]any
+		if err := json.Unmarshal([]byte(raw.Raw), &item); err != nil || item == nil {
+			continue
+		}
+		if _, ok := item["type"]; !ok && raw.Get("role").Exists() {
+			item["type"] = "message"
+		}
+		if id, _ := item["id"].(string); id == "" {
+			item["id"] = syntheticResponsesItemID("in", responseID, i)
+		}
+		if content := raw.Get("content"); item["type"] == "message" && content.Type == gjson.String {
+			partType := "input_text"
+			if raw.Get("role").String() == "assistant" {
+				partType = "output_text"
+			}
+			item["content"] = []map[string]any{{"type": partType, "text": content.String()}}
+		}
+		items = append(items, item)
+	}
+	return items
 }
 
 func convertGenerationConfigFromOpenAIResponses(req *responses.ResponseNewParams) *v1.GenerationConfig {
@@ -220,11 +281,20 @@ func convertReasoningItemFromOpenAIResponses(item gjson.Result) *v1.Message {
 	id := item.Get("id").String()
 	message := &v1.Message{Role: v1.Role_ROLE_MODEL}
 
+	// The encrypted content tells which provider family produced the item, and
+	// items without one are taken as OpenAI's.
+	encrypted := item.Get("encrypted_content")
+	provenance, opaque := util.DecodeProvenance(v1.Provenance_PROVENANCE_OPENAI, encrypted.String())
+	if provenance == v1.Provenance_PROVENANCE_UNSPECIFIED {
+		provenance = v1.Provenance_PROVENANCE_OPENAI
+	}
+
 	appendText := func(text gjson.Result) {
 		message.Contents = append(message.Contents, &v1.Content{
-			Id:      id,
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: v1.NewTextContent(text.Get("text").String()),
+			Id:         id,
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: provenance,
+			Content:    v1.NewTextContent(text.Get("text").String()),
 		})
 	}
 	for _, summary := range item.Get("summary").Array() {
@@ -234,11 +304,12 @@ func convertReasoningItemFromOpenAIResponses(item gjson.Result) *v1.Message {
 		appendText(reasoning)
 	}
 
-	if encrypted := item.Get("encrypted_content"); encrypted.Type == gjson.String {
+	if encrypted.Type == gjson.String {
 		message.Contents = append(message.Contents, &v1.Content{
-			Id:      id,
-			Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-			Content: &v1.Content_Opaque{Opaque: encrypted.String()},
+			Id:         id,
+			Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+			Provenance: provenance,
+			Content:    &v1.Content_Opaque{Opaque: opaque},
 		})
 	}
 	return message
@@ -256,12 +327,7 @@ func appendAdjacentResponsesMessage(messages *[]*v1.Message, message *v1.Message
 	*messages = append(*messages, message)
 }
 
-func convertChatResponseToOpenAIResponses(resp *v1.ChatResponse) *responsesResponse {
-	responseID := resp.GetMessage().GetId()
-	if responseID == "" {
-		responseID = resp.GetId()
-	}
-
+func convertChatResponseToOpenAIResponses(resp *v1.ChatResponse, responseID string) *responsesResponse {
 	openAIResp := &responsesResponse{
 		ID:        responseID,
 		Object:    "response",
@@ -380,7 +446,7 @@ func convertContentsToOpenAIResponses(responseID string, contents []*v1.Content)
 				continue
 			}
 			flushMessage()
-			reasoningItem(content.GetId()).EncryptedContent = c.Opaque
+			reasoningItem(content.GetId()).EncryptedContent = util.EncodeProvenance(v1.Provenance_PROVENANCE_OPENAI, content.Provenance, c.Opaque)
 
 		case *v1.Content_ToolUse:
 			flushMessage()
@@ -30,7 +30,7 @@ func TestConvertChatRequestFromOpenAIResponses(t *testing.T) {
 			"model": "gpt-5",
 			"prompt_cache_key": "session-1",
 			"input": "hello"
-		}`))
+		}`), nil)
 		So(err, ShouldBeNil)
 
 		Convey("Then it becomes the native session ID", func() {
@@ -41,7 +41,7 @@ func TestConvertChatRequestFromOpenAIResponses(t *testing.T) {
 	Convey("Given the Responses API request fixtures", t, func() {
 		for _, fixture := range mock.ResponsesFixtures {
 			Convey("When converting the "+fixture.Name+" request", func() {
-				actual, err := convertChatRequestFromOpenAIResponses(fixture.Request)
+				actual, err := convertChatRequestFromOpenAIResponses(fixture.Request, nil)
 				So(err, ShouldBeNil)
 
 				expected := proto.Clone(fixture.ChatRequest).(*v1.ChatRequest)
@@ -62,7 +62,7 @@ func TestConvertChatRequestFromOpenAIResponsesReplayedOutput(t *testing.T) {
 				{"type":"message","role":"assistant","status":"completed","id":"msg-1","phase":"final_answer","content":[{"type":"output_text","text":"previous answer","annotations":[]}]},
 				{"role":"user","content":[{"type":"input_text","text":"second question"}]}
 			]
-		}`))
+		}`), nil)
 		So(err, ShouldBeNil)
 
 		Convey("Then every turn survives, including the assistant output_text", func() {
@@ -78,14 +78,16 @@ func TestConvertChatRequestFromOpenAIResponsesReplayedOutput(t *testing.T) {
 						Role: v1.Role_ROLE_MODEL,
 						Contents: []*v1.Content{
 							{
-								Id:      "rs-1",
-								Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-								Content: v1.NewTextContent("thought"),
+								Id:         "rs-1",
+								Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+								Provenance: v1.Provenance_PROVENANCE_OPENAI,
+								Content:    v1.NewTextContent("thought"),
 							},
 							{
-								Id:      "rs-1",
-								Phase:   v1.ContentPhase_CONTENT_PHASE_REASONING,
-								Content: &v1.Content_Opaque{Opaque: "encrypted"},
+								Id:         "rs-1",
+								Phase:      v1.ContentPhase_CONTENT_PHASE_REASONING,
+								Provenance: v1.Provenance_PROVENANCE_OPENAI,
+								Content:    &v1.Content_Opaque{Opaque: "encrypted"},
 							},
 							{
 								Phase:   v1.ContentPhase_CONTENT_PHASE_OUTCOME,
@@ -108,7 +110,7 @@ func TestConvertChatRequestFromOpenAIResponsesReplayedOutput(t *testing.T) {
 			"input": [
 				{"type":"function_call_output","call_id":"call-1","output":[{"type":"output_text","text":"tool result"}]}
 			]
-		}`))
+		}`), nil)
 		So(err, ShouldBeNil)
 
 		Convey("Then the parts are flattened into the tool result text", func() {
@@ -125,7 +127,7 @@ func TestConvertChatRequestFromOpenAIResponsesReplayedOutput(t *testing.T) {
 				{"type":"web_search_call","id":"ws-1","status":"completed"},
 				{"type":"message","role":"assistant","content":[{"type":"refusal","refusal":"no"},{"type":"output_text","text":"answer"}]}
 			]
-		}`))
+		}`), nil)
 		So(err, ShouldBeNil)
 
 		Convey("Then they are skipped without discarding the rest of the history", func() {
@@ -136,7 +138,7 @@ func TestConvertChatRequestFromOpenAIResponsesReplayedOutput(t *testing.T) {
 	})
 
 	Convey("Given a string input", t, func() {
-		req, err := convertChatRequestFromOpenAIResponses([]byte(`{"model":"gpt-5","input":"hello"}`))
+		req, err := convertChatRequestFromOpenAIResponses([]byte(`{"model":"gpt-5","input":"hello"}`), nil)
 		So(err, ShouldBeNil)
 
 		Convey("Then it becomes a single user message", func() {
@@ -145,6 +147,32 @@ func TestConvertChatRequestFromOpenAIResponsesReplayedOutput(t *testing.T) {
 			So(req.Messages[0].Contents[0].GetText().GetText(), ShouldEqual, "hello")
 		})
 	})
+
+	Convey("Given a forced function tool choice", t, func() {
+		req, err := convertChatRequestFromOpenAIResponses([]byte(`{
+			"model": "gpt-5",
+			"input": "hello",
+			"tool_choice": {"type":"function","name":"get_weather"},
+			"parallel_tool_calls": false
+		}`), nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then the function and the parallel tool call setting are converted", func() {
+			So(req.ToolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL)
+			So(req.ToolChoice.Name, ShouldEqual, "get_weather")
+			So(req.ToolChoice.GetParallelToolCalls(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a tool choice mode", t, func() {
+		req, err := convertChatRequestFromOpenAIResponses([]byte(`{"model":"gpt-5","input":"hello","tool_choice":"none"}`), nil)
+		So(err, ShouldBeNil)
+
+		Convey("Then the mode is converted without a parallel tool call setting", func() {
+			So(req.ToolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE)
+			So(req.ToolChoice.ParallelToolCalls, ShouldBeNil)
+		})
+	})
 }
 
 func TestConvertChatResponseToOpenAIResponses(t *testing.T) {
@@ -192,7 +220,7 @@ func TestConvertChatResponseToOpenAIResponses(t *testing.T) {
 			}},
 		}
 
-		result := convertChatResponseToOpenAIResponses(resp)
+		result := convertChatResponseToOpenAIResponses(resp, "resp-1")
 
 		Convey("Then the response and output items retain their order and identities", func() {
 			So(result.ID, ShouldEqual, "resp-1")
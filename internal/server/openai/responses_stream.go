@@ -25,6 +25,7 @@ import (
 	"github.com/openai/openai-go/v3/responses"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
 )
 
 type responsesStreamBlockKind uint8
@@ -50,6 +51,8 @@ type responsesStreamServer struct {
 	ctx     context.Context
 	httpCtx http.Context
 	buffer  *bytes.Buffer
+	// id is the id of the response, assigned by the router.
+	id string
 
 	sequenceNumber  int64
 	nextOutputIndex int64
@@ -77,7 +80,7 @@ func (s *responsesStreamServer) Send(event *v1.ChatEvent) error {
 		if err := s.flushPendingItems(); err != nil {
 			return err
 		}
-		return s.handleMessageStart(event.GetId(), e.MessageStart)
+		return s.handleMessageStart(e.MessageStart)
 
 	case *v1.ChatEvent_ContentStart:
 		return s.handleContentStart(e.ContentStart)
@@ -106,13 +109,9 @@ func (s *responsesStreamServer) Send(event *v1.ChatEvent) error {
 	return nil
 }
 
-func (s *responsesStreamServer) handleMessageStart(requestID string, start *v1.MessageStart) error {
-	responseID := start.GetId()
-	if responseID == "" {
-		responseID = requestID
-	}
+func (s *responsesStreamServer) handleMessageStart(start *v1.MessageStart) error {
 	s.response = responsesResponse{
-		ID:        responseID,
+		ID:        s.id,
 		Object:    "response",
 		CreatedAt: time.Now().Unix(),
 		Model:     start.GetModel(),
@@ -422,8 +421,9 @@ func (s *responsesStreamServer) handleContentSnapshot(content *v1.Content) error
 	if content == nil || content.Phase != v1.ContentPhase_CONTENT_PHASE_REASONING || content.GetOpaque() == "" {
 		return s.flushPendingReasoning()
 	}
+	encrypted := util.EncodeProvenance(v1.Provenance_PROVENANCE_OPENAI, content.GetProvenance(), content.GetOpaque())
 	if block := s.pendingReasoningFor(content.GetId()); block != nil {
-		block.item.EncryptedContent = content.GetOpaque()
+		block.item.EncryptedContent = encrypted
 		return s.flushPendingReasoning()
 	}
 	if err := s.flushPendingReasoning(); err != nil {
@@ -439,7 +439,7 @@ func (s *responsesStreamServer) handleContentSnapshot(content *v1.Content) error
 		ID:               itemID,
 		Type:             "reasoning",
 		Status:           "in_progress",
-		EncryptedContent: content.GetOpaque(),
+		EncryptedContent: encrypted,
 	}
 	s.response.Output = append(s.response.Output, item)
 	block := &responsesStreamBlock{
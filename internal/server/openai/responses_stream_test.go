@@ -86,6 +86,12 @@ func (c *responsesTestHTTPContext) Blob(statusCode int, contentType string, data
 	return nil
 }
 
+func (c *responsesTestHTTPContext) JSON(statusCode int, v any) error {
+	c.statusCode = statusCode
+	c.headers.Set("Content-Type", "application/json")
+	return json.NewEncoder(&c.body).Encode(v)
+}
+
 type parsedResponsesSSEEvent struct {
 	typeName string
 	data     map[string]any
@@ -117,6 +123,7 @@ func TestResponsesStreamServer(t *testing.T) {
 		server := &responsesStreamServer{
 			ctx:     context.Background(),
 			httpCtx: httpCtx,
+			id:      "resp-1",
 		}
 
 		for _, event := range []*v1.ChatEvent{
@@ -254,6 +261,7 @@ func TestResponsesStreamServer(t *testing.T) {
 		server := &responsesStreamServer{
 			ctx:     context.Background(),
 			httpCtx: httpCtx,
+			id:      "resp-1",
 		}
 
 		for _, event := range []*v1.ChatEvent{
@@ -314,6 +322,7 @@ func TestResponsesStreamServer(t *testing.T) {
 		server := &responsesStreamServer{
 			ctx:     context.Background(),
 			httpCtx: httpCtx,
+			id:      "resp-1",
 		}
 
 		for _, event := range []*v1.ChatEvent{
@@ -376,6 +385,7 @@ func TestResponsesStreamServer(t *testing.T) {
 		server := &responsesStreamServer{
 			ctx:     context.Background(),
 			httpCtx: httpCtx,
+			id:      "resp-1",
 		}
 
 		for _, event := range []*v1.ChatEvent{
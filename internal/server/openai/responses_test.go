@@ -18,13 +18,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"testing"
 
+	kerrors "github.com/go-kratos/kratos/v3/errors"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/tidwall/gjson"
 	"google.golang.org/protobuf/proto"
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
 	"github.com/neuraxes/neurouter/internal/data/upstream/openai/mock"
 )
 
@@ -63,7 +68,8 @@ func TestHandleResponses(t *testing.T) {
 			So(httpCtx.headers.Get("Content-Type"), ShouldEqual, "application/json")
 			var response responsesResponse
 			So(json.Unmarshal(httpCtx.body.Bytes(), &response), ShouldBeNil)
-			So(response.ID, ShouldEqual, mock.ResponsesText.ChatResponse.Message.Id)
+			So(response.ID, ShouldStartWith, "resp_")
+			So(response.ID, ShouldNotEqual, mock.ResponsesText.ChatResponse.Message.Id)
 			So(response.Object, ShouldEqual, "response")
 			So(response.Status, ShouldEqual, "completed")
 			So(response.Output, ShouldHaveLength, 2)
@@ -104,3 +110,250 @@ func TestHandleResponses(t *testing.T) {
 		})
 	})
 }
+
+type responsesTestStore struct {
+	responses map[string]*entity.StoredResponse
+	chat      v1.ChatServer
+	disabled  bool
+}
+
+// responsesTestRecorder rebuilds the text of a chat stream as it is sent.
+type responsesTestRecorder struct {
+	v1.Chat_ChatStreamServer
+	resp *v1.ChatResponse
+}
+
+func (r *responsesTestRecorder) Send(event *v1.ChatEvent) error {
+	switch e := event.Event.(type) {
+	case *v1.ChatEvent_MessageStart:
+		r.resp.Message = &v1.Message{Id: e.MessageStart.Id, Role: v1.Role_ROLE_MODEL}
+	case *v1.ChatEvent_ContentDelta:
+		r.resp.Message.Contents = append(r.resp.Message.Contents, &v1.Content{
+			Content: v1.NewTextContent(e.ContentDelta.GetText()),
+		})
+	}
+	return r.Chat_ChatStreamServer.Send(event)
+}
+
+func (s *responsesTestStore) ChatStreamResponse(req *v1.ChatRequest, srv v1.Chat_ChatStreamServer) (*v1.ChatResponse, error) {
+	recorder := &responsesTestRecorder{Chat_ChatStreamServer: srv, resp: &v1.ChatResponse{}}
+	if err := s.chat.ChatStream(req, recorder); err != nil {
+		return nil, err
+	}
+	return recorder.resp, nil
+}
+
+func (s *responsesTestStore) StoresResponses() bool {
+	return !s.disabled
+}
+
+func (s *responsesTestStore) SaveResponse(_ context.Context, resp *entity.StoredResponse) {
+	s.responses[resp.Id] = resp
+}
+
+func (s *responsesTestStore) GetResponse(_ context.Context, id string) (*entity.StoredResponse, error) {
+	resp, ok := s.responses[id]
+	if !ok {
+		return nil, entity.ErrResponseNotFound
+	}
+	return resp, nil
+}
+
+func (s *responsesTestStore) DeleteResponse(_ context.Context, id string) error {
+	if _, ok := s.responses[id]; !ok {
+		return entity.ErrResponseNotFound
+	}
+	delete(s.responses, id)
+	return nil
+}
+
+func TestStatefulResponses(t *testing.T) {
+	Convey("Given a response store", t, func() {
+		store := &responsesTestStore{responses: map[string]*entity.StoredResponse{}}
+		previousOutput := &v1.Message{
+			Role: v1.Role_ROLE_MODEL,
+			Contents: []*v1.Content{
+				{
+					Phase:     v1.ContentPhase_CONTENT_PHASE_REASONING,
+					Signature: "sig",
+					Content:   v1.NewTextContent("thinking"),
+				},
+				{Content: v1.NewTextContent("Hi there")},
+			},
+		}
+		store.responses["resp-0"] = &entity.StoredResponse{
+			Id: "resp-0",
+			Messages: []*v1.Message{{
+				Role:     v1.Role_ROLE_USER,
+				Contents: []*v1.Content{{Content: v1.NewTextContent("Hello")}},
+			}},
+			Output:     previousOutput,
+			Response:   []byte(`{"id":"resp-0","object":"response"}`),
+			InputItems: []byte(`[{"id":"in_0"},{"id":"in_1"},{"id":"in_2"}]`),
+		}
+
+		var received *v1.ChatRequest
+		server := &Server{
+			responseSvc: store,
+			chatSvc: &responsesTestChatServer{
+				chatFunc: func(_ context.Context, req *v1.ChatRequest) (*v1.ChatResponse, error) {
+					received = req
+					return &v1.ChatResponse{
+						Model: "gpt-5",
+						Message: &v1.Message{
+							Id:       "resp-1",
+							Role:     v1.Role_ROLE_MODEL,
+							Contents: []*v1.Content{{Content: v1.NewTextContent("Fine")}},
+						},
+						Status: v1.ChatStatus_CHAT_STATUS_COMPLETED,
+					}, nil
+				},
+				chatStreamFunc: func(req *v1.ChatRequest, stream v1.Chat_ChatStreamServer) error {
+					received = req
+					for _, event := range []*v1.ChatEvent{
+						v1.NewChatEvent("request-1", v1.NewMessageStartEvent("resp-1", "gpt-5")),
+						v1.NewChatEvent("request-1", v1.NewContentStartTextEvent(0, v1.ContentPhase_CONTENT_PHASE_NORMAL)),
+						v1.NewChatEvent("request-1", v1.NewContentDeltaTextEvent(0, "Fine")),
+						v1.NewChatEvent("request-1", v1.NewContentStopEvent(0)),
+						v1.NewChatEvent("request-1", v1.NewMessageStopEvent(v1.ChatStatus_CHAT_STATUS_COMPLETED)),
+					} {
+						if err := stream.Send(event); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+		}
+		store.chat = server.chatSvc
+		post := func(body string) *responsesTestHTTPContext {
+			req, err := http.NewRequest(http.MethodPost, "/v1/responses", bytes.NewReader([]byte(body)))
+			So(err, ShouldBeNil)
+			httpCtx := newResponsesTestHTTPContext(req)
+			So(server.handleResponses(httpCtx), ShouldBeNil)
+			return httpCtx
+		}
+		// stored returns the response stored by the last request.
+		stored := func() *entity.StoredResponse {
+			for id, resp := range store.responses {
+				if id != "resp-0" {
+					return resp
+				}
+			}
+			return nil
+		}
+
+		for _, stream := range []bool{false, true} {
+			Convey("When continuing a response with stream="+strconv.FormatBool(stream), func() {
+				httpCtx := post(`{"model":"gpt-5","instructions":"Be brief","previous_response_id":"resp-0",` +
+					`"input":"How are you?","stream":` + strconv.FormatBool(stream) + `}`)
+
+				Convey("Then the conversation is expanded after the instructions", func() {
+					So(received.Messages, ShouldHaveLength, 4)
+					So(received.Messages[0].Role, ShouldEqual, v1.Role_ROLE_SYSTEM)
+					So(received.Messages[1].Contents[0].GetText().GetText(), ShouldEqual, "Hello")
+					So(proto.Equal(received.Messages[2], previousOutput), ShouldBeTrue)
+					So(received.Messages[3].Contents[0].GetText().GetText(), ShouldEqual, "How are you?")
+				})
+
+				Convey("Then the response is stored with its conversation under a new id", func() {
+					stored := stored()
+					So(stored, ShouldNotBeNil)
+					So(stored.Id, ShouldStartWith, "resp_")
+					So(httpCtx.body.String(), ShouldContainSubstring, `"id":"`+stored.Id+`"`)
+					So(stored.Messages, ShouldHaveLength, 3)
+					So(stored.Output.Contents[0].GetText().GetText(), ShouldEqual, "Fine")
+					So(gjson.GetBytes(stored.Response, "id").String(), ShouldEqual, stored.Id)
+					So(gjson.GetBytes(stored.Response, "status").String(), ShouldEqual, "completed")
+					So(string(stored.InputItems), ShouldEqual,
+						`[{"content":[{"text":"How are you?","type":"input_text"}],`+
+							`"id":"in_`+stored.Id+`_0","role":"user","status":"completed","type":"message"}]`)
+				})
+			})
+		}
+
+		Convey("When the client opts out of storage", func() {
+			post(`{"model":"gpt-5","input":"hello","store":false}`)
+
+			Convey("Then the response is not stored", func() {
+				So(store.responses, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When no store is configured", func() {
+			store.disabled = true
+			post(`{"model":"gpt-5","input":"hello","stream":true}`)
+
+			Convey("Then the response is not stored", func() {
+				So(store.responses, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When the previous response does not exist", func() {
+			req, err := http.NewRequest(http.MethodPost, "/v1/responses",
+				bytes.NewReader([]byte(`{"model":"gpt-5","input":"hello","previous_response_id":"resp-x"}`)))
+			So(err, ShouldBeNil)
+			err = server.handleResponses(newResponsesTestHTTPContext(req))
+
+			Convey("Then the request fails as not found", func() {
+				So(errors.Is(err, entity.ErrResponseNotFound), ShouldBeTrue)
+				So(received, ShouldBeNil)
+			})
+		})
+
+		Convey("When retrieving a response", func() {
+			httpCtx := newResponsesTestHTTPContext()
+			So(server.handleGetResponse(httpCtx, "resp-0"), ShouldBeNil)
+
+			Convey("Then the stored rendering is returned", func() {
+				So(httpCtx.statusCode, ShouldEqual, http.StatusOK)
+				So(httpCtx.body.String(), ShouldEqual, `{"id":"resp-0","object":"response"}`)
+			})
+		})
+
+		Convey("When deleting a response", func() {
+			httpCtx := newResponsesTestHTTPContext()
+			So(server.handleDeleteResponse(httpCtx, "resp-0"), ShouldBeNil)
+
+			Convey("Then it is removed", func() {
+				So(httpCtx.body.String(), ShouldEqual, `{"id":"resp-0","object":"response","deleted":true}`+"\n")
+				So(store.responses, ShouldNotContainKey, "resp-0")
+				So(errors.Is(server.handleDeleteResponse(newResponsesTestHTTPContext(), "resp-0"), entity.ErrResponseNotFound), ShouldBeTrue)
+			})
+		})
+
+		Convey("When listing input items", func() {
+			list := func(query string) *responsesInputItemList {
+				req, err := http.NewRequest(http.MethodGet, "/v1/responses/resp-0/input_items?"+query, nil)
+				So(err, ShouldBeNil)
+				httpCtx := newResponsesTestHTTPContext(req)
+				So(server.handleListResponseInputItems(httpCtx, "resp-0"), ShouldBeNil)
+				var list responsesInputItemList
+				So(json.Unmarshal(httpCtx.body.Bytes(), &list), ShouldBeNil)
+				return &list
+			}
+
+			Convey("Then items are listed newest first by default", func() {
+				l := list("")
+				So(l.Data, ShouldHaveLength, 3)
+				So(l.FirstID, ShouldEqual, "in_2")
+				So(l.LastID, ShouldEqual, "in_0")
+				So(l.HasMore, ShouldBeFalse)
+			})
+
+			Convey("Then items are paginated", func() {
+				l := list("order=asc&limit=1&after=in_0")
+				So(l.Data, ShouldHaveLength, 1)
+				So(l.FirstID, ShouldEqual, "in_1")
+				So(l.HasMore, ShouldBeTrue)
+			})
+
+			Convey("Then an unknown cursor is rejected", func() {
+				req, err := http.NewRequest(http.MethodGet, "/v1/responses/resp-0/input_items?after=in_x", nil)
+				So(err, ShouldBeNil)
+				err = server.handleListResponseInputItems(newResponsesTestHTTPContext(req), "resp-0")
+				So(kerrors.IsBadRequest(err), ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+// setRateLimitHeaders writes rl as the OpenAI-style x-ratelimit-* headers,
+// which most clients understand, and retry-after for rejected requests.
+func setRateLimitHeaders(header util.HeaderSetter, rl *entity.RateLimit) {
+	for unit, w := range map[string]*entity.RateLimitWindow{"requests": rl.Requests, "tokens": rl.Tokens} {
+		if w == nil {
+			continue
+		}
+		header.Set("x-ratelimit-limit-"+unit, strconv.FormatInt(w.Limit, 10))
+		header.Set("x-ratelimit-remaining-"+unit, strconv.FormatInt(w.Remaining, 10))
+		var reset time.Duration
+		if !w.Reset.IsZero() {
+			reset = max(0, time.Until(w.Reset)).Round(time.Millisecond)
+		}
+		header.Set("x-ratelimit-reset-"+unit, reset.String())
+	}
+	util.SetRetryAfterHeaders(header, rl.RetryAfter)
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	Convey("setRateLimitHeaders", t, func() {
+		Convey("should write the x-ratelimit headers of each unit", func() {
+			header := http.Header{}
+			setRateLimitHeaders(header, &entity.RateLimit{
+				Requests: &entity.RateLimitWindow{Limit: 60, Remaining: 59},
+				Tokens:   &entity.RateLimitWindow{Limit: 1000, Remaining: 0, Reset: time.Now().Add(time.Minute)},
+			})
+			So(header.Get("x-ratelimit-limit-requests"), ShouldEqual, "60")
+			So(header.Get("x-ratelimit-remaining-requests"), ShouldEqual, "59")
+			So(header.Get("x-ratelimit-reset-requests"), ShouldEqual, "0s")
+			So(header.Get("x-ratelimit-limit-tokens"), ShouldEqual, "1000")
+			So(header.Get("x-ratelimit-remaining-tokens"), ShouldEqual, "0")
+			So(header.Get("x-ratelimit-reset-tokens"), ShouldNotEqual, "0s")
+			So(header.Get("retry-after"), ShouldBeEmpty)
+		})
+
+		Convey("should round retry-after up to whole seconds", func() {
+			header := http.Header{}
+			setRateLimitHeaders(header, &entity.RateLimit{RetryAfter: 1500 * time.Millisecond})
+			So(header.Get("retry-after"), ShouldEqual, "2")
+			So(header.Get("retry-after-ms"), ShouldEqual, "1500")
+			So(header.Get("x-ratelimit-limit-requests"), ShouldBeEmpty)
+		})
+	})
+}
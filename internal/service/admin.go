@@ -0,0 +1,74 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+// ListLimiters returns the live state of every upstream and model limiter.
+func (s *RouterService) ListLimiters(ctx context.Context, _ *v1.ListLimitersRequest) (resp *v1.ListLimitersResponse, err error) {
+	groups, err := s.admin.ListLimiters(ctx)
+	if err != nil {
+		return
+	}
+
+	resp = &v1.ListLimitersResponse{
+		Groups: groups,
+	}
+	return
+}
+
+// ResetLimiter restores the full limit of a limiter or of a whole limiter group.
+func (s *RouterService) ResetLimiter(ctx context.Context, req *v1.ResetLimiterRequest) (resp *v1.ResetLimiterResponse, err error) {
+	group, err := s.admin.ResetLimiter(ctx, req.Upstream, req.Model, req.Credential, req.Index)
+	if err != nil {
+		return
+	}
+
+	resp = &v1.ResetLimiterResponse{
+		Group: group,
+	}
+	return
+}
+
+// AdjustLimiter sets the units a limiter admits from now on.
+func (s *RouterService) AdjustLimiter(ctx context.Context, req *v1.AdjustLimiterRequest) (resp *v1.AdjustLimiterResponse, err error) {
+	group, err := s.admin.AdjustLimiter(ctx, req.Upstream, req.Model, req.Credential, req.Index, req.Remaining)
+	if err != nil {
+		return
+	}
+
+	resp = &v1.AdjustLimiterResponse{
+		Group: group,
+	}
+	return
+}
+
+// EnableCredential puts a credential disabled after the upstream rejected it
+// back into rotation.
+func (s *RouterService) EnableCredential(ctx context.Context, req *v1.EnableCredentialRequest) (resp *v1.EnableCredentialResponse, err error) {
+	group, err := s.admin.EnableCredential(ctx, req.Upstream, req.Credential)
+	if err != nil {
+		return
+	}
+
+	resp = &v1.EnableCredentialResponse{
+		Group: group,
+	}
+	return
+}
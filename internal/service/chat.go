@@ -63,3 +63,7 @@ func (s *RouterService) ChatStream(req *v1.ChatRequest, srv v1.Chat_ChatStreamSe
 	err := s.chat.ChatStream(srv.Context(), acceptChatRequest(req), &wrappedChatStreamServer{srv})
 	return err
 }
+
+func (s *RouterService) CountTokens(ctx context.Context, req *v1.ChatRequest) (*v1.CountTokensResponse, error) {
+	return s.chat.CountTokens(ctx, acceptChatRequest(req))
+}
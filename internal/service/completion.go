@@ -0,0 +1,51 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// acceptCompleteRequest copies the inbound request so the caller's message is
+// never mutated, and assigns the request id that every response echoes back.
+func acceptCompleteRequest(req *v1.CompleteRequest) *v1.CompleteRequest {
+	completeReq := proto.Clone(req).(*v1.CompleteRequest)
+	if completeReq.Id == "" {
+		completeReq.Id = uuid.NewString()
+	}
+	return completeReq
+}
+
+func (s *RouterService) Complete(ctx context.Context, req *v1.CompleteRequest) (*v1.CompleteResponse, error) {
+	return s.completion.Complete(ctx, acceptCompleteRequest(req))
+}
+
+type wrappedCompletionStreamServer struct {
+	srv v1.Completion_CompleteStreamServer
+}
+
+func (w *wrappedCompletionStreamServer) Send(resp *entity.CompleteResponse) error {
+	return w.srv.Send(resp)
+}
+
+func (s *RouterService) CompleteStream(req *v1.CompleteRequest, srv v1.Completion_CompleteStreamServer) error {
+	return s.completion.CompleteStream(srv.Context(), acceptCompleteRequest(req), &wrappedCompletionStreamServer{srv})
+}
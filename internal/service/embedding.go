@@ -24,9 +24,13 @@ import (
 // Embed creates embeddings for the given contents using the specified model.
 func (s *RouterService) Embed(ctx context.Context, req *v1.EmbedRequest) (resp *v1.EmbedResponse, err error) {
 	embedReq := &entity.EmbedRequest{
-		Id:       req.Id,
-		Model:    req.Model,
-		Contents: req.Contents,
+		Id:         req.Id,
+		Model:      req.Model,
+		Contents:   req.Contents,
+		Dimensions: req.Dimensions,
+		Inputs:     req.Inputs,
+		TaskType:   req.TaskType,
+		Normalize:  req.Normalize,
 	}
 
 	r, err := s.embedding.Embed(ctx, embedReq)
@@ -35,8 +39,11 @@ func (s *RouterService) Embed(ctx context.Context, req *v1.EmbedRequest) (resp *
 	}
 
 	resp = &v1.EmbedResponse{
-		Id:        r.Id,
-		Embedding: r.Embedding,
+		Id:         r.Id,
+		Model:      r.Model,
+		Embedding:  r.Embedding,
+		Usage:      r.Usage,
+		Embeddings: r.Embeddings,
 	}
 	return
 }
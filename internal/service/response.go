@@ -0,0 +1,71 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/biz/chat"
+	"github.com/neuraxes/neurouter/internal/biz/entity"
+)
+
+// StoresResponses reports whether a response store is configured.
+func (s *RouterService) StoresResponses() bool {
+	return s.response.Enabled()
+}
+
+// SaveResponse stores a response generated for a stateful API client. The
+// response has already been delivered, so failures are only logged.
+func (s *RouterService) SaveResponse(ctx context.Context, resp *entity.StoredResponse) {
+	if err := s.response.Save(ctx, resp); err != nil {
+		s.log.WarnContext(ctx, "failed to store response", "id", resp.Id, "error", err)
+	}
+}
+
+// GetResponse returns a response previously stored by the calling client.
+func (s *RouterService) GetResponse(ctx context.Context, id string) (*entity.StoredResponse, error) {
+	return s.response.Get(ctx, id)
+}
+
+// DeleteResponse removes a response previously stored by the calling client.
+func (s *RouterService) DeleteResponse(ctx context.Context, id string) error {
+	return s.response.Delete(ctx, id)
+}
+
+// reducingChatStreamServer rebuilds the response of a chat stream as its
+// events are sent.
+type reducingChatStreamServer struct {
+	v1.Chat_ChatStreamServer
+	reducer *chat.ChatEventReducer
+}
+
+func (s *reducingChatStreamServer) Send(event *v1.ChatEvent) error {
+	s.reducer.Reduce(event)
+	return s.Chat_ChatStreamServer.Send(event)
+}
+
+// ChatStreamResponse streams the chat like ChatStream, and returns the
+// response the stream reduces to, so that it can be stored.
+func (s *RouterService) ChatStreamResponse(req *v1.ChatRequest, srv v1.Chat_ChatStreamServer) (*v1.ChatResponse, error) {
+	reducing := &reducingChatStreamServer{
+		Chat_ChatStreamServer: srv,
+		reducer:               chat.NewChatEventReducer(s.log),
+	}
+	if err := s.ChatStream(req, reducing); err != nil {
+		return nil, err
+	}
+	return reducing.reducer.Resp(), nil
+}
@@ -19,30 +19,43 @@ import (
 
 	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
 	"github.com/neuraxes/neurouter/internal/biz/chat"
+	"github.com/neuraxes/neurouter/internal/biz/completion"
 	"github.com/neuraxes/neurouter/internal/biz/embedding"
 	"github.com/neuraxes/neurouter/internal/biz/model"
+	"github.com/neuraxes/neurouter/internal/biz/response"
 )
 
 type RouterService struct {
 	v1.UnimplementedModelServer
 	v1.UnimplementedChatServer
 	v1.UnimplementedEmbeddingServer
-	chat      chat.UseCase
-	model     model.UseCase
-	embedding embedding.UseCase
-	log       *slog.Logger
+	v1.UnimplementedCompletionServer
+	v1.UnimplementedAdminServer
+	chat       chat.UseCase
+	model      model.UseCase
+	embedding  embedding.UseCase
+	completion completion.UseCase
+	admin      model.AdminUseCase
+	response   response.UseCase
+	log        *slog.Logger
 }
 
 func NewRouterService(
 	chat chat.UseCase,
 	model model.UseCase,
 	embedding embedding.UseCase,
+	completion completion.UseCase,
+	admin model.AdminUseCase,
+	response response.UseCase,
 	logger *slog.Logger,
 ) *RouterService {
 	return &RouterService{
-		chat:      chat,
-		model:     model,
-		embedding: embedding,
-		log:       logger,
+		chat:       chat,
+		model:      model,
+		embedding:  embedding,
+		completion: completion,
+		admin:      admin,
+		response:   response,
+		log:        logger,
 	}
 }
@@ -0,0 +1,414 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package googleconv holds the conversions between neurouter and the Gemini
+// API. The google upstream converts requests to the Gemini API and responses
+// from it, while the Gemini-compatible server does the reverse. Both share
+// these conversions, so that a conversation keeps its shape whichever side of
+// neurouter speaks Gemini.
+package googleconv
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"google.golang.org/genai"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+	"github.com/neuraxes/neurouter/internal/util"
+)
+
+// toolResultKey wraps tool results that are not JSON objects, as a
+// function response must be one.
+const toolResultKey = "result"
+
+// PartFrom converts a part of Gemini contents. Parts that carry
+// nothing neurouter represents yield nil.
+func PartFrom(part *genai.Part) *v1.Content {
+	var content *v1.Content
+	switch {
+	case part.FunctionCall != nil:
+		toolUse, err := ToolUseFrom(part.FunctionCall)
+		if err != nil {
+			return nil
+		}
+		content = &v1.Content{Content: &v1.Content_ToolUse{ToolUse: toolUse}}
+	case part.FunctionResponse != nil:
+		content = &v1.Content{
+			Content: &v1.Content_ToolResult{ToolResult: ToolResultFrom(part.FunctionResponse)},
+		}
+	case part.InlineData != nil:
+		if !strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+			return nil
+		}
+		content = &v1.Content{
+			Content: &v1.Content_Image{Image: &v1.Image{
+				MimeType: part.InlineData.MIMEType,
+				Source:   &v1.Image_Data{Data: part.InlineData.Data},
+			}},
+		}
+	case part.FileData != nil:
+		if !strings.HasPrefix(part.FileData.MIMEType, "image/") {
+			return nil
+		}
+		content = &v1.Content{
+			Content: &v1.Content_Image{Image: &v1.Image{
+				MimeType: part.FileData.MIMEType,
+				Source:   &v1.Image_Url{Url: part.FileData.FileURI},
+			}},
+		}
+	case part.Text != "" || len(part.ThoughtSignature) > 0:
+		// A signature may arrive on a part of its own, which is kept so
+		// that it is replayed to Gemini
+		content = &v1.Content{Content: v1.NewTextContent(part.Text)}
+	default:
+		return nil
+	}
+
+	if part.Thought {
+		content.Phase = v1.ContentPhase_CONTENT_PHASE_REASONING
+	}
+	if len(part.ThoughtSignature) > 0 {
+		content.Provenance, content.Signature = DecodeThoughtSignature(part.ThoughtSignature)
+	}
+	return content
+}
+
+// ToolUseFrom converts a function call, packing its name into
+// the tool use id.
+func ToolUseFrom(call *genai.FunctionCall) (*v1.ToolUse, error) {
+	args := "{}"
+	if call.Args != nil {
+		data, err := json.Marshal(call.Args)
+		if err != nil {
+			return nil, err
+		}
+		args = string(data)
+	}
+	return &v1.ToolUse{
+		Id:     PackToolUseID(call.Name, call.ID),
+		Name:   call.Name,
+		Inputs: []*v1.ToolUse_Input{{Input: &v1.ToolUse_Input_Text{Text: args}}},
+	}, nil
+}
+
+// ToolUseTo converts the textual input of a tool use into a
+// function call with the given call id. Inputs that are not JSON objects are
+// wrapped.
+func ToolUseTo(id, name, input string) *genai.FunctionCall {
+	var args map[string]any
+	if input != "" {
+		if err := json.Unmarshal([]byte(input), &args); err != nil {
+			args = map[string]any{
+				"args": input,
+			}
+		}
+	}
+	return &genai.FunctionCall{
+		ID:   id,
+		Name: name,
+		Args: args,
+	}
+}
+
+// ToolResultTo converts a tool result into a function response,
+// unpacking the function name from the id of the call it answers.
+func ToolResultTo(result *v1.ToolResult) *genai.FunctionResponse {
+	var textParts strings.Builder
+	var mediaParts []*genai.FunctionResponsePart
+	for _, out := range result.GetOutputs() {
+		switch o := out.Output.(type) {
+		case *v1.ToolResult_Output_Text:
+			textParts.WriteString(o.Text)
+		case *v1.ToolResult_Output_Image:
+			if p := ImageToPart(o.Image); p != nil && p.InlineData != nil {
+				mediaParts = append(mediaParts, &genai.FunctionResponsePart{
+					InlineData: &genai.FunctionResponseBlob{
+						MIMEType: p.InlineData.MIMEType,
+						Data:     p.InlineData.Data,
+					},
+				})
+			}
+		}
+	}
+
+	var response map[string]any
+	if textualOutput := textParts.String(); textualOutput != "" {
+		if err := json.Unmarshal([]byte(textualOutput), &response); err != nil {
+			response = map[string]any{
+				toolResultKey: textualOutput,
+			}
+		}
+	}
+
+	name, id := SplitToolUseID(result.Id)
+	return &genai.FunctionResponse{
+		ID:       id,
+		Name:     name,
+		Response: response,
+		Parts:    mediaParts,
+	}
+}
+
+// ToolResultFrom reverses ToolResultTo, unwrapping
+// plain text results.
+func ToolResultFrom(resp *genai.FunctionResponse) *v1.ToolResult {
+	result := &v1.ToolResult{
+		Id: PackToolUseID(resp.Name, resp.ID),
+	}
+
+	if text, ok := resp.Response[toolResultKey].(string); ok && len(resp.Response) == 1 {
+		result.Outputs = append(result.Outputs, &v1.ToolResult_Output{
+			Output: &v1.ToolResult_Output_Text{Text: text},
+		})
+	} else if resp.Response != nil {
+		data, err := json.Marshal(resp.Response)
+		if err == nil {
+			result.Outputs = append(result.Outputs, &v1.ToolResult_Output{
+				Output: &v1.ToolResult_Output_Text{Text: string(data)},
+			})
+		}
+	}
+
+	for _, part := range resp.Parts {
+		if part.InlineData == nil || !strings.HasPrefix(part.InlineData.MIMEType, "image/") {
+			continue
+		}
+		result.Outputs = append(result.Outputs, &v1.ToolResult_Output{
+			Output: &v1.ToolResult_Output_Image{Image: &v1.Image{
+				MimeType: part.InlineData.MIMEType,
+				Source:   &v1.Image_Data{Data: part.InlineData.Data},
+			}},
+		})
+	}
+	return result
+}
+
+// ImageToPart converts an image into a part, inferring the MIME
+// type of inline data that has none.
+func ImageToPart(image *v1.Image) *genai.Part {
+	mimeType := image.MimeType
+	switch source := image.Source.(type) {
+	case *v1.Image_Url:
+		return genai.NewPartFromURI(source.Url, mimeType)
+	case *v1.Image_Data:
+		if mimeType == "" {
+			mimeType = util.InferImageMimeType(source.Data)
+		}
+		return genai.NewPartFromBytes(source.Data, mimeType)
+	case *v1.Image_Base64:
+		data, err := base64.StdEncoding.DecodeString(source.Base64)
+		if err != nil {
+			return nil
+		}
+		if mimeType == "" {
+			mimeType = util.InferImageMimeType(data)
+		}
+		return genai.NewPartFromBytes(data, mimeType)
+	default:
+		return nil
+	}
+}
+
+// EncodeThoughtSignature converts a signature into thought signature
+// bytes. Gemini signatures are the base64 of those bytes, while signatures of
+// other provider families are carried as their tagged text.
+func EncodeThoughtSignature(provenance v1.Provenance, signature string) []byte {
+	switch provenance {
+	case v1.Provenance_PROVENANCE_UNSPECIFIED, v1.Provenance_PROVENANCE_GOOGLE:
+		sig, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			return nil
+		}
+		return sig
+	default:
+		return []byte(util.EncodeProvenance(v1.Provenance_PROVENANCE_GOOGLE, provenance, signature))
+	}
+}
+
+// DecodeThoughtSignature reverses EncodeThoughtSignature.
+func DecodeThoughtSignature(sig []byte) (v1.Provenance, string) {
+	if provenance, signature := util.DecodeProvenance(v1.Provenance_PROVENANCE_GOOGLE, string(sig)); provenance != v1.Provenance_PROVENANCE_GOOGLE {
+		return provenance, signature
+	}
+	return v1.Provenance_PROVENANCE_GOOGLE, base64.StdEncoding.EncodeToString(sig)
+}
+
+// ToolChoiceTo converts the tool choice to a function calling
+// mode. A specific function is forced by allowing only that one in ANY mode.
+// Gemini only restricts the allowed functions in ANY and VALIDATED modes, so
+// an automatic choice among allowed functions is VALIDATED. Gemini has no
+// control over parallel calls.
+func ToolChoiceTo(toolChoice *v1.ToolChoice) *genai.ToolConfig {
+	config := &genai.FunctionCallingConfig{}
+	switch toolChoice.GetMode() {
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO:
+		config.Mode = genai.FunctionCallingConfigModeAuto
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE:
+		config.Mode = genai.FunctionCallingConfigModeNone
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED:
+		config.Mode = genai.FunctionCallingConfigModeAny
+	case v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL:
+		config.Mode = genai.FunctionCallingConfigModeAny
+		config.AllowedFunctionNames = []string{toolChoice.Name}
+	}
+	if allowed := toolChoice.AllowedToolNames(); len(allowed) > 0 {
+		if config.Mode != genai.FunctionCallingConfigModeAny {
+			config.Mode = genai.FunctionCallingConfigModeValidated
+		}
+		config.AllowedFunctionNames = allowed
+	}
+	if config.Mode == "" {
+		return nil
+	}
+	return &genai.ToolConfig{FunctionCallingConfig: config}
+}
+
+// ToolChoiceFrom reverses ToolChoiceTo.
+func ToolChoiceFrom(toolConfig *genai.ToolConfig) *v1.ToolChoice {
+	if toolConfig == nil || toolConfig.FunctionCallingConfig == nil {
+		return nil
+	}
+
+	config := toolConfig.FunctionCallingConfig
+	switch config.Mode {
+	case genai.FunctionCallingConfigModeAuto, genai.FunctionCallingConfigModeValidated:
+		return &v1.ToolChoice{
+			Mode:         v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO,
+			AllowedNames: config.AllowedFunctionNames,
+		}
+	case genai.FunctionCallingConfigModeNone:
+		return &v1.ToolChoice{Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_NONE}
+	case genai.FunctionCallingConfigModeAny:
+		if len(config.AllowedFunctionNames) == 1 {
+			return &v1.ToolChoice{
+				Mode: v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL,
+				Name: config.AllowedFunctionNames[0],
+			}
+		}
+		return &v1.ToolChoice{
+			Mode:         v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED,
+			AllowedNames: config.AllowedFunctionNames,
+		}
+	}
+	return nil
+}
+
+// ReasoningEffortTo converts a reasoning effort to a thinking
+// level.
+func ReasoningEffortTo(effort v1.ReasoningEffort) genai.ThinkingLevel {
+	switch effort {
+	case v1.ReasoningEffort_REASONING_EFFORT_MINIMAL:
+		return genai.ThinkingLevelMinimal
+	case v1.ReasoningEffort_REASONING_EFFORT_LOW:
+		return genai.ThinkingLevelLow
+	case v1.ReasoningEffort_REASONING_EFFORT_MEDIUM:
+		return genai.ThinkingLevelMedium
+	case v1.ReasoningEffort_REASONING_EFFORT_HIGH, v1.ReasoningEffort_REASONING_EFFORT_EXTRA_HIGH, v1.ReasoningEffort_REASONING_EFFORT_MAX:
+		return genai.ThinkingLevelHigh
+	default:
+		return genai.ThinkingLevelUnspecified
+	}
+}
+
+// ThinkingLevelFrom reverses ReasoningEffortTo.
+func ThinkingLevelFrom(level genai.ThinkingLevel) v1.ReasoningEffort {
+	switch level {
+	case genai.ThinkingLevelMinimal:
+		return v1.ReasoningEffort_REASONING_EFFORT_MINIMAL
+	case genai.ThinkingLevelLow:
+		return v1.ReasoningEffort_REASONING_EFFORT_LOW
+	case genai.ThinkingLevelMedium:
+		return v1.ReasoningEffort_REASONING_EFFORT_MEDIUM
+	case genai.ThinkingLevelHigh:
+		return v1.ReasoningEffort_REASONING_EFFORT_HIGH
+	default:
+		return v1.ReasoningEffort_REASONING_EFFORT_UNSPECIFIED
+	}
+}
+
+// StatusFrom converts the finish reason of a candidate.
+func StatusFrom(reason genai.FinishReason, content *genai.Content) v1.ChatStatus {
+	// Google returns Stop for completed turns and tool calls, so inspect content first.
+	if reason == genai.FinishReasonStop && content != nil {
+		for _, part := range content.Parts {
+			if part.FunctionCall != nil {
+				return v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE
+			}
+		}
+	}
+
+	switch reason {
+	case genai.FinishReasonStop:
+		return v1.ChatStatus_CHAT_STATUS_COMPLETED
+	case genai.FinishReasonMaxTokens:
+		return v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT
+	case genai.FinishReasonSafety,
+		genai.FinishReasonBlocklist,
+		genai.FinishReasonProhibitedContent,
+		genai.FinishReasonSPII,
+		genai.FinishReasonImageSafety,
+		genai.FinishReasonImageProhibitedContent:
+		return v1.ChatStatus_CHAT_STATUS_REFUSED
+	default:
+		return v1.ChatStatus_CHAT_STATUS_IN_PROGRESS
+	}
+}
+
+// StatusTo reverses StatusFrom. Pending tool use
+// stops like a completed turn.
+func StatusTo(status v1.ChatStatus) genai.FinishReason {
+	switch status {
+	case v1.ChatStatus_CHAT_STATUS_COMPLETED, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE:
+		return genai.FinishReasonStop
+	case v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT:
+		return genai.FinishReasonMaxTokens
+	case v1.ChatStatus_CHAT_STATUS_REFUSED:
+		return genai.FinishReasonSafety
+	case v1.ChatStatus_CHAT_STATUS_FAILED, v1.ChatStatus_CHAT_STATUS_CANCELLED:
+		return genai.FinishReasonOther
+	default:
+		return ""
+	}
+}
+
+// UsageFrom converts usage metadata. Gemini counts thoughts apart
+// from the candidates, while neurouter counts them as output.
+func UsageFrom(usage *genai.GenerateContentResponseUsageMetadata) *v1.Usage {
+	if usage == nil {
+		return nil
+	}
+	return &v1.Usage{
+		InputTokens:       uint32(usage.PromptTokenCount),
+		OutputTokens:      uint32(usage.CandidatesTokenCount + usage.ThoughtsTokenCount),
+		CachedInputTokens: uint32(usage.CachedContentTokenCount),
+		ReasoningTokens:   uint32(usage.ThoughtsTokenCount),
+	}
+}
+
+// UsageTo reverses UsageFrom.
+func UsageTo(usage *v1.Usage) *genai.GenerateContentResponseUsageMetadata {
+	if usage == nil {
+		return nil
+	}
+	thoughts := min(usage.ReasoningTokens, usage.OutputTokens)
+	return &genai.GenerateContentResponseUsageMetadata{
+		PromptTokenCount:        int32(usage.InputTokens),
+		CandidatesTokenCount:    int32(usage.OutputTokens - thoughts),
+		ThoughtsTokenCount:      int32(thoughts),
+		CachedContentTokenCount: int32(usage.CachedInputTokens),
+		TotalTokenCount:         int32(usage.InputTokens + usage.OutputTokens),
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package googleconv
+
+import (
+	"encoding/base64"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/genai"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func TestPartFrom(t *testing.T) {
+	Convey("Test PartFrom", t, func() {
+		Convey("should pack the name into the id of function calls", func() {
+			content := PartFrom(&genai.Part{FunctionCall: &genai.FunctionCall{ID: "call-1", Name: "fn"}})
+			So(content.GetToolUse().GetId(), ShouldEqual, "fn:call-1")
+			So(content.GetToolUse().GetTextualInput(), ShouldEqual, "{}")
+		})
+
+		Convey("should keep a thought signature sent on a part of its own", func() {
+			content := PartFrom(&genai.Part{Thought: true, ThoughtSignature: []byte("sig")})
+			So(content.IsReasoning(), ShouldBeTrue)
+			So(content.Provenance, ShouldEqual, v1.Provenance_PROVENANCE_GOOGLE)
+			So(content.Signature, ShouldEqual, base64.StdEncoding.EncodeToString([]byte("sig")))
+		})
+
+		Convey("should skip parts that are not images or text", func() {
+			So(PartFrom(&genai.Part{InlineData: &genai.Blob{MIMEType: "audio/wav"}}), ShouldBeNil)
+			So(PartFrom(&genai.Part{Thought: true}), ShouldBeNil)
+		})
+	})
+}
+
+func TestToolResultTo(t *testing.T) {
+	Convey("Test ToolResultTo", t, func() {
+		Convey("should round trip plain text results", func() {
+			result := &v1.ToolResult{
+				Id:      "fn:call-1",
+				Outputs: []*v1.ToolResult_Output{{Output: &v1.ToolResult_Output_Text{Text: "sunny"}}},
+			}
+			resp := ToolResultTo(result)
+			So(resp.ID, ShouldEqual, "call-1")
+			So(resp.Name, ShouldEqual, "fn")
+			So(resp.Response, ShouldResemble, map[string]any{"result": "sunny"})
+			So(ToolResultFrom(resp), ShouldResemble, result)
+		})
+
+		Convey("should pass JSON objects through", func() {
+			resp := ToolResultTo(&v1.ToolResult{
+				Id:      "fn",
+				Outputs: []*v1.ToolResult_Output{{Output: &v1.ToolResult_Output_Text{Text: `{"temp":20}`}}},
+			})
+			So(resp.Response, ShouldResemble, map[string]any{"temp": float64(20)})
+			So(ToolResultFrom(resp).Outputs[0].GetText(), ShouldEqual, `{"temp":20}`)
+		})
+	})
+}
+
+func TestUsageTo(t *testing.T) {
+	Convey("Test UsageTo", t, func() {
+		usage := &v1.Usage{InputTokens: 100, OutputTokens: 80, CachedInputTokens: 25, ReasoningTokens: 30}
+		metadata := UsageTo(usage)
+		So(metadata.CandidatesTokenCount, ShouldEqual, 50)
+		So(metadata.ThoughtsTokenCount, ShouldEqual, 30)
+		So(metadata.TotalTokenCount, ShouldEqual, 180)
+		So(UsageFrom(metadata), ShouldResemble, usage)
+		So(UsageTo(nil), ShouldBeNil)
+	})
+}
+
+func TestThinkingLevelFrom(t *testing.T) {
+	Convey("Test ThinkingLevelFrom", t, func() {
+		for _, effort := range []v1.ReasoningEffort{
+			v1.ReasoningEffort_REASONING_EFFORT_MINIMAL,
+			v1.ReasoningEffort_REASONING_EFFORT_LOW,
+			v1.ReasoningEffort_REASONING_EFFORT_MEDIUM,
+			v1.ReasoningEffort_REASONING_EFFORT_HIGH,
+		} {
+			So(ThinkingLevelFrom(ReasoningEffortTo(effort)), ShouldEqual, effort)
+		}
+		So(ReasoningEffortTo(v1.ReasoningEffort_REASONING_EFFORT_MAX), ShouldEqual, genai.ThinkingLevelHigh)
+	})
+}
+
+func TestToolChoiceFrom(t *testing.T) {
+	Convey("Test ToolChoiceFrom", t, func() {
+		convert := func(mode genai.FunctionCallingConfigMode, names ...string) *v1.ToolChoice {
+			return ToolChoiceFrom(&genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+				Mode:                 mode,
+				AllowedFunctionNames: names,
+			}})
+		}
+
+		Convey("should force the only allowed function", func() {
+			toolChoice := convert(genai.FunctionCallingConfigModeAny, "get_weather")
+			So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_TOOL)
+			So(toolChoice.Name, ShouldEqual, "get_weather")
+		})
+
+		Convey("should keep several allowed functions", func() {
+			toolChoice := convert(genai.FunctionCallingConfigModeAny, "get_weather", "get_time")
+			So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_REQUIRED)
+			So(toolChoice.AllowedNames, ShouldResemble, []string{"get_weather", "get_time"})
+
+			toolChoice = convert(genai.FunctionCallingConfigModeValidated, "get_weather", "get_time")
+			So(toolChoice.Mode, ShouldEqual, v1.ToolChoiceMode_TOOL_CHOICE_MODE_AUTO)
+			So(toolChoice.AllowedNames, ShouldResemble, []string{"get_weather", "get_time"})
+			So(ToolChoiceTo(toolChoice).FunctionCallingConfig.Mode, ShouldEqual, genai.FunctionCallingConfigModeValidated)
+		})
+	})
+}
+
+func TestStatusFrom(t *testing.T) {
+	Convey("Given various Google finish reasons without function calls", t, func() {
+		So(StatusFrom(genai.FinishReasonStop, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+		So(StatusFrom(genai.FinishReasonMaxTokens, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT)
+		So(StatusFrom(genai.FinishReasonSafety, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
+		So(StatusFrom(genai.FinishReasonBlocklist, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
+		So(StatusFrom(genai.FinishReasonProhibitedContent, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
+		So(StatusFrom(genai.FinishReasonSPII, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
+		So(StatusFrom(genai.FinishReasonRecitation, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
+		So(StatusFrom(genai.FinishReasonUnspecified, nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
+		So(StatusFrom(genai.FinishReason(""), nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
+		So(StatusFrom(genai.FinishReason("unknown"), nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_IN_PROGRESS)
+	})
+
+	Convey("Given STOP finish reason with function call in content", t, func() {
+		content := &genai.Content{
+			Parts: []*genai.Part{
+				{
+					FunctionCall: &genai.FunctionCall{
+						Name: "get_weather",
+						Args: map[string]any{"city": "Shanghai"},
+					},
+				},
+			},
+		}
+		So(StatusFrom(genai.FinishReasonStop, content), ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
+	})
+
+	Convey("Given STOP finish reason with text content only", t, func() {
+		content := &genai.Content{
+			Parts: []*genai.Part{
+				{Text: "Hello world"},
+			},
+		}
+		So(StatusFrom(genai.FinishReasonStop, content), ShouldEqual, v1.ChatStatus_CHAT_STATUS_COMPLETED)
+	})
+
+	Convey("Given STOP finish reason with mixed content including function call", t, func() {
+		content := &genai.Content{
+			Parts: []*genai.Part{
+				{Text: "Let me check the weather for you."},
+				{
+					FunctionCall: &genai.FunctionCall{
+						Name: "get_weather",
+						Args: map[string]any{"city": "Beijing"},
+					},
+				},
+			},
+		}
+		So(StatusFrom(genai.FinishReasonStop, content), ShouldEqual, v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE)
+	})
+}
+
+func TestStatusTo(t *testing.T) {
+	Convey("Test StatusTo", t, func() {
+		So(StatusTo(v1.ChatStatus_CHAT_STATUS_PENDING_TOOL_USE), ShouldEqual, genai.FinishReasonStop)
+		So(StatusTo(v1.ChatStatus_CHAT_STATUS_REACHED_TOKEN_LIMIT), ShouldEqual, genai.FinishReasonMaxTokens)
+		So(StatusFrom(StatusTo(v1.ChatStatus_CHAT_STATUS_REFUSED), nil), ShouldEqual, v1.ChatStatus_CHAT_STATUS_REFUSED)
+		So(StatusTo(v1.ChatStatus_CHAT_STATUS_IN_PROGRESS), ShouldBeEmpty)
+	})
+}
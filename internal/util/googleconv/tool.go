@@ -0,0 +1,55 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package googleconv
+
+import "strings"
+
+// Gemini requires the function name on every function response, but a ToolResult
+// carries only the id of the call it answers. The name is therefore packed into
+// that id, ahead of the call id Gemini assigns when it issues parallel calls. A
+// function name cannot contain a colon, so the first one separates the two.
+// The google upstream and the Gemini-compatible server pack ids the same way,
+// so that results match their calls whichever upstream serves the conversation.
+const toolUseIDSeparator = ":"
+
+// PackToolUseID builds the tool use id of a Gemini function call or
+// response from its name and id.
+func PackToolUseID(name, id string) string {
+	if id == "" {
+		return name
+	}
+	return name + toolUseIDSeparator + id
+}
+
+// SplitToolUseID unpacks what PackToolUseID built. An id from
+// another provider has no separator and is read as a bare function name, which
+// is the best guess available for a conversation replayed across providers.
+func SplitToolUseID(toolUseID string) (name, id string) {
+	name, id, _ = strings.Cut(toolUseID, toolUseIDSeparator)
+	return
+}
+
+// ToolCallID returns the id a Gemini client should echo in the response
+// to the function call name, dropping the name packed by PackToolUseID.
+// Ids issued by other upstreams are passed through.
+func ToolCallID(toolUseID, name string) string {
+	if toolUseID == name {
+		return ""
+	}
+	if id, ok := strings.CutPrefix(toolUseID, name+toolUseIDSeparator); ok {
+		return id
+	}
+	return toolUseID
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package googleconv
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestToolUseID(t *testing.T) {
+	Convey("Test tool use ids", t, func() {
+		Convey("should pack the id after the name", func() {
+			So(PackToolUseID("get_weather", "abc"), ShouldEqual, "get_weather:abc")
+			So(PackToolUseID("get_weather", ""), ShouldEqual, "get_weather")
+		})
+
+		Convey("should split a packed id", func() {
+			name, id := SplitToolUseID("get_weather:abc")
+			So(name, ShouldEqual, "get_weather")
+			So(id, ShouldEqual, "abc")
+
+			name, id = SplitToolUseID("call_123")
+			So(name, ShouldEqual, "call_123")
+			So(id, ShouldBeEmpty)
+		})
+
+		Convey("should return the id a client echoes", func() {
+			So(ToolCallID("get_weather:abc", "get_weather"), ShouldEqual, "abc")
+			So(ToolCallID("get_weather", "get_weather"), ShouldBeEmpty)
+			So(ToolCallID("call_123", "get_weather"), ShouldEqual, "call_123")
+		})
+
+		Convey("should round-trip a foreign id", func() {
+			id := ToolCallID("call_123", "get_weather")
+			So(PackToolUseID("get_weather", id), ShouldEqual, "get_weather:call_123")
+		})
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"strings"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+// provenancePrefixes tag signatures and opaque contents that a server hands to
+// clients of an API other than the one of the producing provider family.
+var provenancePrefixes = map[v1.Provenance]string{
+	v1.Provenance_PROVENANCE_ANTHROPIC: "anthropic:",
+	v1.Provenance_PROVENANCE_GOOGLE:    "google:",
+	v1.Provenance_PROVENANCE_OPENAI:    "openai:",
+}
+
+// EncodeProvenance tags a signature or an opaque value with the provider family
+// that produced it, so that the provenance survives a round trip through a
+// client of the server family. Values of the server family or of unknown
+// provenance are left untagged.
+func EncodeProvenance(server, provenance v1.Provenance, value string) string {
+	if value == "" || provenance == server {
+		return value
+	}
+	prefix, ok := provenancePrefixes[provenance]
+	if !ok {
+		return value
+	}
+	return prefix + value
+}
+
+// DecodeProvenance reverses EncodeProvenance. Untagged values are attributed to
+// the server family, and empty values carry no provenance.
+func DecodeProvenance(server v1.Provenance, value string) (v1.Provenance, string) {
+	if value == "" {
+		return v1.Provenance_PROVENANCE_UNSPECIFIED, value
+	}
+	for provenance, prefix := range provenancePrefixes {
+		if rest, ok := strings.CutPrefix(value, prefix); ok {
+			return provenance, rest
+		}
+	}
+	return server, value
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+func TestProvenance(t *testing.T) {
+	Convey("Given a server of the Anthropic family", t, func() {
+		server := v1.Provenance_PROVENANCE_ANTHROPIC
+
+		Convey("Values of the server family stay untagged", func() {
+			value := EncodeProvenance(server, v1.Provenance_PROVENANCE_ANTHROPIC, "sig")
+			So(value, ShouldEqual, "sig")
+
+			provenance, decoded := DecodeProvenance(server, value)
+			So(provenance, ShouldEqual, v1.Provenance_PROVENANCE_ANTHROPIC)
+			So(decoded, ShouldEqual, "sig")
+		})
+
+		Convey("Values of other families round trip with their provenance", func() {
+			value := EncodeProvenance(server, v1.Provenance_PROVENANCE_OPENAI, "gAAAA")
+			So(value, ShouldEqual, "openai:gAAAA")
+
+			provenance, decoded := DecodeProvenance(server, value)
+			So(provenance, ShouldEqual, v1.Provenance_PROVENANCE_OPENAI)
+			So(decoded, ShouldEqual, "gAAAA")
+		})
+
+		Convey("Values of unknown provenance stay untagged", func() {
+			So(EncodeProvenance(server, v1.Provenance_PROVENANCE_UNSPECIFIED, "sig"), ShouldEqual, "sig")
+		})
+
+		Convey("Empty values carry no provenance", func() {
+			So(EncodeProvenance(server, v1.Provenance_PROVENANCE_GOOGLE, ""), ShouldBeEmpty)
+
+			provenance, decoded := DecodeProvenance(server, "")
+			So(provenance, ShouldEqual, v1.Provenance_PROVENANCE_UNSPECIFIED)
+			So(decoded, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// HeaderSetter is implemented by net/http.Header and Kratos transport headers.
+type HeaderSetter interface {
+	Set(key, value string)
+}
+
+// SetRetryAfterHeaders writes retry-after in whole seconds, rounded up, and
+// retry-after-ms, if d is positive.
+func SetRetryAfterHeaders(header HeaderSetter, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	header.Set("retry-after", strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10))
+	header.Set("retry-after-ms", strconv.FormatInt(d.Milliseconds(), 10))
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSetRetryAfterHeaders(t *testing.T) {
+	Convey("SetRetryAfterHeaders", t, func() {
+		Convey("should round retry-after up to whole seconds", func() {
+			header := http.Header{}
+			SetRetryAfterHeaders(header, 1500*time.Millisecond)
+			So(header.Get("retry-after"), ShouldEqual, "2")
+			So(header.Get("retry-after-ms"), ShouldEqual, "1500")
+		})
+
+		Convey("should write nothing without a delay", func() {
+			header := http.Header{}
+			SetRetryAfterHeaders(header, 0)
+			So(header, ShouldBeEmpty)
+		})
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "strings"
+
+type TagEventKind int
+
+const (
+	// TagEventText carries text, enclosed by Tag unless it is empty.
+	TagEventText TagEventKind = iota
+	// TagEventOpen reports the opening of Tag.
+	TagEventOpen
+	// TagEventClose reports the closing of Tag.
+	TagEventClose
+)
+
+type TagEvent struct {
+	Kind TagEventKind
+	Tag  string
+	Text string
+}
+
+// TagParser splits text streamed in chunks at XML-like tags such as
+// <think>...</think>, which models emit inline. Tags do not nest: within a
+// tag, only its closing tag is recognized. Text that may be the start of a
+// tag is held back until the following chunks decide it, so tags split
+// across chunks are found all the same.
+type TagParser struct {
+	tags []string
+	open string
+	buf  string
+}
+
+func NewTagParser(tags ...string) *TagParser {
+	return &TagParser{tags: tags}
+}
+
+// Open starts the parser within the tag, for output whose opening tag was
+// already given in the prompt.
+func (p *TagParser) Open(tag string) {
+	p.open = tag
+}
+
+// Tag returns the tag the parser is within, or empty outside of tags.
+func (p *TagParser) Tag() string {
+	return p.open
+}
+
+// Feed parses the next chunk of text.
+func (p *TagParser) Feed(chunk string) (events []TagEvent) {
+	p.buf += chunk
+	for {
+		markers := p.markers()
+		i, marker := indexMarker(p.buf, markers)
+		if i < 0 {
+			n := len(p.buf) - heldBack(p.buf, markers)
+			events = p.appendText(events, p.buf[:n])
+			p.buf = p.buf[n:]
+			return
+		}
+
+		events = p.appendText(events, p.buf[:i])
+		p.buf = p.buf[i+len(marker):]
+		if p.open == "" {
+			p.open = marker[1 : len(marker)-1]
+			events = append(events, TagEvent{Kind: TagEventOpen, Tag: p.open})
+		} else {
+			events = append(events, TagEvent{Kind: TagEventClose, Tag: p.open})
+			p.open = ""
+		}
+	}
+}
+
+// Flush releases the text held back at the end of the output, and closes the
+// tag left open.
+func (p *TagParser) Flush() (events []TagEvent) {
+	events = p.appendText(events, p.buf)
+	p.buf = ""
+	if p.open != "" {
+		events = append(events, TagEvent{Kind: TagEventClose, Tag: p.open})
+		p.open = ""
+	}
+	return
+}
+
+// markers returns the tags recognized in the current state.
+func (p *TagParser) markers() []string {
+	if p.open != "" {
+		return []string{"</" + p.open + ">"}
+	}
+	markers := make([]string, len(p.tags))
+	for i, tag := range p.tags {
+		markers[i] = "<" + tag + ">"
+	}
+	return markers
+}
+
+func (p *TagParser) appendText(events []TagEvent, text string) []TagEvent {
+	if text == "" {
+		return events
+	}
+	return append(events, TagEvent{Kind: TagEventText, Tag: p.open, Text: text})
+}
+
+// indexMarker finds the first of the markers in s.
+func indexMarker(s string, markers []string) (index int, marker string) {
+	index = -1
+	for _, m := range markers {
+		if i := strings.Index(s, m); i >= 0 && (index < 0 || i < index) {
+			index, marker = i, m
+		}
+	}
+	return
+}
+
+// heldBack returns the length of the suffix of s that may be the start of
+// one of the markers. Markers hold a single '<', so only the last one in s
+// can begin such a suffix.
+func heldBack(s string, markers []string) int {
+	start := strings.LastIndexByte(s, '<')
+	if start < 0 {
+		return 0
+	}
+	for _, m := range markers {
+		if strings.HasPrefix(m, s[start:]) {
+			return len(s) - start
+		}
+	}
+	return 0
+}
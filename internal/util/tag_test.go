@@ -0,0 +1,93 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// parseTagChunks feeds the chunks and merges adjacent text of the events.
+func parseTagChunks(p *TagParser, chunks ...string) (events []TagEvent) {
+	for _, chunk := range chunks {
+		events = append(events, p.Feed(chunk)...)
+	}
+	events = append(events, p.Flush()...)
+
+	var merged []TagEvent
+	for _, e := range events {
+		if n := len(merged); n > 0 && e.Kind == TagEventText && merged[n-1].Kind == TagEventText && merged[n-1].Tag == e.Tag {
+			merged[n-1].Text += e.Text
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+func TestTagParser(t *testing.T) {
+	Convey("TagParser", t, func() {
+		expected := []TagEvent{
+			{Kind: TagEventOpen, Tag: "think"},
+			{Kind: TagEventText, Tag: "think", Text: "hmm <b>"},
+			{Kind: TagEventClose, Tag: "think"},
+			{Kind: TagEventText, Text: "a < b "},
+			{Kind: TagEventOpen, Tag: "tool_call"},
+			{Kind: TagEventText, Tag: "tool_call", Text: "{}"},
+			{Kind: TagEventClose, Tag: "tool_call"},
+		}
+
+		Convey("should split text at tags", func() {
+			p := NewTagParser("think", "tool_call")
+			events := parseTagChunks(p, "<think>hmm <b></think>a < b <tool_call>{}</tool_call>")
+			So(events, ShouldResemble, expected)
+		})
+
+		Convey("should find tags split across chunks", func() {
+			p := NewTagParser("think", "tool_call")
+			events := parseTagChunks(p, "<th", "ink>hmm <b></", "think>a <", " b <tool", "_call>{}</tool_call", ">")
+			So(events, ShouldResemble, expected)
+		})
+
+		Convey("should hold back possible tags only", func() {
+			p := NewTagParser("think")
+			So(p.Feed("a <t"), ShouldResemble, []TagEvent{{Kind: TagEventText, Text: "a "}})
+			So(p.Feed("x"), ShouldResemble, []TagEvent{{Kind: TagEventText, Text: "<tx"}})
+		})
+
+		Convey("should start within an opened tag", func() {
+			p := NewTagParser("think")
+			p.Open("think")
+			So(p.Tag(), ShouldEqual, "think")
+			events := parseTagChunks(p, "hmm</think>ok")
+			So(events, ShouldResemble, []TagEvent{
+				{Kind: TagEventText, Tag: "think", Text: "hmm"},
+				{Kind: TagEventClose, Tag: "think"},
+				{Kind: TagEventText, Text: "ok"},
+			})
+		})
+
+		Convey("should close the tag left open on flush", func() {
+			p := NewTagParser("tool_call")
+			events := parseTagChunks(p, "<tool_call>{\"name\"</tool")
+			So(events, ShouldResemble, []TagEvent{
+				{Kind: TagEventOpen, Tag: "tool_call"},
+				{Kind: TagEventText, Tag: "tool_call", Text: "{\"name\"</tool"},
+				{Kind: TagEventClose, Tag: "tool_call"},
+			})
+		})
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+
+	v1 "github.com/neuraxes/neurouter/api/neurouter/v1"
+)
+
+// Tags of the Hermes format, which many open models are trained on to call
+// tools: calls and results are JSON wrapped in tags within the text.
+const (
+	ToolCallTag     = "tool_call"
+	ToolResponseTag = "tool_response"
+)
+
+// HermesToolCall is a tool call as written within <tool_call> tags.
+type HermesToolCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+	// Parameters is accepted in place of arguments, as some models use it.
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ParseHermesToolCall reads a call written by a model within <tool_call>
+// tags, reporting false if it is not one. Models write no call ids, so a
+// unique one is assigned.
+func ParseHermesToolCall(text string) (*v1.ToolUse, bool) {
+	var call HermesToolCall
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text)), &call); err != nil || call.Name == "" {
+		return nil, false
+	}
+
+	arguments := call.Arguments
+	if len(arguments) == 0 {
+		arguments = call.Parameters
+	}
+	// Arguments encoded as a string, the way OpenAI passes them, are unwrapped
+	var encoded string
+	if json.Unmarshal(arguments, &encoded) == nil && json.Valid([]byte(encoded)) {
+		arguments = json.RawMessage(encoded)
+	}
+	if len(arguments) == 0 || string(arguments) == "null" {
+		arguments = json.RawMessage("{}")
+	}
+
+	return &v1.ToolUse{
+		Id:     "call_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Name:   call.Name,
+		Inputs: []*v1.ToolUse_Input{{Input: &v1.ToolUse_Input_Text{Text: string(arguments)}}},
+	}, true
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Neurouter Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseHermesToolCall(t *testing.T) {
+	Convey("ParseHermesToolCall", t, func() {
+		Convey("should parse the name and arguments", func() {
+			toolUse, ok := ParseHermesToolCall("\n{\"name\": \"get_weather\", \"arguments\": {\"city\": \"Paris\"}}\n")
+			So(ok, ShouldBeTrue)
+			So(toolUse.Name, ShouldEqual, "get_weather")
+			So(toolUse.GetTextualInput(), ShouldEqual, `{"city": "Paris"}`)
+			So(toolUse.Id, ShouldStartWith, "call_")
+		})
+
+		Convey("should accept parameters and encoded arguments", func() {
+			toolUse, ok := ParseHermesToolCall(`{"name": "f", "parameters": {"a": 1}}`)
+			So(ok, ShouldBeTrue)
+			So(toolUse.GetTextualInput(), ShouldEqual, `{"a": 1}`)
+
+			toolUse, ok = ParseHermesToolCall(`{"name": "f", "arguments": "{\"a\": 1}"}`)
+			So(ok, ShouldBeTrue)
+			So(toolUse.GetTextualInput(), ShouldEqual, `{"a": 1}`)
+		})
+
+		Convey("should default to empty arguments", func() {
+			toolUse, ok := ParseHermesToolCall(`{"name": "f"}`)
+			So(ok, ShouldBeTrue)
+			So(toolUse.GetTextualInput(), ShouldEqual, "{}")
+		})
+
+		Convey("should reject text that is no call", func() {
+			_, ok := ParseHermesToolCall("not json")
+			So(ok, ShouldBeFalse)
+			_, ok = ParseHermesToolCall(`{"arguments": {}}`)
+			So(ok, ShouldBeFalse)
+		})
+	})
+}